@@ -1,7 +1,16 @@
 // Code generated for package assets by go-bindata DO NOT EDIT. (@generated)
 // sources:
 // templates/cloudformation/iam_user_osdCcsAdmin.json
+// templates/policies/openshift_cloud_credential_operator_cloud_credential_operator_iam_ro_creds_policy.json
+// templates/policies/openshift_cluster_csi_drivers_ebs_cloud_credentials_policy.json
+// templates/policies/openshift_image_registry_installer_cloud_credentials_policy.json
+// templates/policies/openshift_ingress_operator_cloud_credentials_policy.json
+// templates/policies/openshift_machine_api_aws_cloud_credentials_policy.json
 // templates/policies/osd_scp_policy.json
+// templates/policies/sts_installer_permission_policy.json
+// templates/policies/sts_instance_controlplane_permission_policy.json
+// templates/policies/sts_instance_worker_permission_policy.json
+// templates/policies/sts_support_permission_policy.json
 package assets
 
 import (
@@ -55,13 +64,55 @@ func (fi bindataFileInfo) Sys() interface{} {
 }
 
 var _templatesCloudformationIam_user_osdccsadminJson = []byte(`{
+  "Parameters": {
+    "PermissionsBoundary": {
+      "Type": "String",
+      "Default": "",
+      "Description": "ARN of the managed policy that is the permissions boundary of the user, if any"
+    },
+    "Path": {
+      "Type": "String",
+      "Default": "/",
+      "Description": "Path of the user"
+    }
+  },
+  "Conditions": {
+    "HasPermissionsBoundary": {
+      "Fn::Not": [
+        {
+          "Fn::Equals": [
+            {
+              "Ref": "PermissionsBoundary"
+            },
+            ""
+          ]
+        }
+      ]
+    }
+  },
   "Resources": {
     "osdCcsAdmin": {
       "Type": "AWS::IAM::User",
       "Properties": {
         "ManagedPolicyArns": [
-          "arn:aws:iam::aws:policy/AdministratorAccess"
+          {
+            "Fn::Sub": "arn:${AWS::Partition}:iam::aws:policy/AdministratorAccess"
+          }
         ],
+        "Path": {
+          "Ref": "Path"
+        },
+        "PermissionsBoundary": {
+          "Fn::If": [
+            "HasPermissionsBoundary",
+            {
+              "Ref": "PermissionsBoundary"
+            },
+            {
+              "Ref": "AWS::NoValue"
+            }
+          ]
+        },
         "UserName": "osdCcsAdmin"
       }
     }
@@ -84,6 +135,221 @@ func templatesCloudformationIam_user_osdccsadminJson() (*asset, error) {
 	return a, nil
 }
 
+var _templatesPoliciesOpenshift_cloud_credential_operator_cloud_credential_operator_iam_ro_creds_policyJson = []byte(`{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Action": [
+        "iam:GetUser",
+        "iam:GetUserPolicy",
+        "iam:ListAccessKeys"
+      ],
+      "Resource": "*"
+    }
+  ]
+}
+`)
+
+func templatesPoliciesOpenshift_cloud_credential_operator_cloud_credential_operator_iam_ro_creds_policyJsonBytes() ([]byte, error) {
+	return _templatesPoliciesOpenshift_cloud_credential_operator_cloud_credential_operator_iam_ro_creds_policyJson, nil
+}
+
+func templatesPoliciesOpenshift_cloud_credential_operator_cloud_credential_operator_iam_ro_creds_policyJson() (*asset, error) {
+	bytes, err := templatesPoliciesOpenshift_cloud_credential_operator_cloud_credential_operator_iam_ro_creds_policyJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/policies/openshift_cloud_credential_operator_cloud_credential_operator_iam_ro_creds_policy.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesPoliciesOpenshift_cluster_csi_drivers_ebs_cloud_credentials_policyJson = []byte(`{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Action": [
+        "ec2:AttachVolume",
+        "ec2:CreateSnapshot",
+        "ec2:CreateTags",
+        "ec2:CreateVolume",
+        "ec2:DeleteSnapshot",
+        "ec2:DeleteTags",
+        "ec2:DeleteVolume",
+        "ec2:DescribeAvailabilityZones",
+        "ec2:DescribeInstances",
+        "ec2:DescribeSnapshots",
+        "ec2:DescribeTags",
+        "ec2:DescribeVolumes",
+        "ec2:DescribeVolumesModifications",
+        "ec2:DetachVolume",
+        "ec2:ModifyVolume"
+      ],
+      "Resource": "*"
+    }
+  ]
+}
+`)
+
+func templatesPoliciesOpenshift_cluster_csi_drivers_ebs_cloud_credentials_policyJsonBytes() ([]byte, error) {
+	return _templatesPoliciesOpenshift_cluster_csi_drivers_ebs_cloud_credentials_policyJson, nil
+}
+
+func templatesPoliciesOpenshift_cluster_csi_drivers_ebs_cloud_credentials_policyJson() (*asset, error) {
+	bytes, err := templatesPoliciesOpenshift_cluster_csi_drivers_ebs_cloud_credentials_policyJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/policies/openshift_cluster_csi_drivers_ebs_cloud_credentials_policy.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesPoliciesOpenshift_image_registry_installer_cloud_credentials_policyJson = []byte(`{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Action": [
+        "s3:AbortMultipartUpload",
+        "s3:CreateBucket",
+        "s3:DeleteBucket",
+        "s3:DeleteObject",
+        "s3:GetBucketLocation",
+        "s3:GetBucketPublicAccessBlock",
+        "s3:GetBucketTagging",
+        "s3:GetEncryptionConfiguration",
+        "s3:GetLifecycleConfiguration",
+        "s3:GetObject",
+        "s3:ListBucket",
+        "s3:ListBucketMultipartUploads",
+        "s3:ListMultipartUploadParts",
+        "s3:PutBucketPublicAccessBlock",
+        "s3:PutBucketTagging",
+        "s3:PutEncryptionConfiguration",
+        "s3:PutLifecycleConfiguration",
+        "s3:PutObject"
+      ],
+      "Resource": "*"
+    }
+  ]
+}
+`)
+
+func templatesPoliciesOpenshift_image_registry_installer_cloud_credentials_policyJsonBytes() ([]byte, error) {
+	return _templatesPoliciesOpenshift_image_registry_installer_cloud_credentials_policyJson, nil
+}
+
+func templatesPoliciesOpenshift_image_registry_installer_cloud_credentials_policyJson() (*asset, error) {
+	bytes, err := templatesPoliciesOpenshift_image_registry_installer_cloud_credentials_policyJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/policies/openshift_image_registry_installer_cloud_credentials_policy.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesPoliciesOpenshift_ingress_operator_cloud_credentials_policyJson = []byte(`{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Action": [
+        "elasticloadbalancing:DescribeLoadBalancers",
+        "route53:ChangeResourceRecordSets",
+        "route53:ListHostedZones",
+        "tag:GetResources"
+      ],
+      "Resource": "*"
+    }
+  ]
+}
+`)
+
+func templatesPoliciesOpenshift_ingress_operator_cloud_credentials_policyJsonBytes() ([]byte, error) {
+	return _templatesPoliciesOpenshift_ingress_operator_cloud_credentials_policyJson, nil
+}
+
+func templatesPoliciesOpenshift_ingress_operator_cloud_credentials_policyJson() (*asset, error) {
+	bytes, err := templatesPoliciesOpenshift_ingress_operator_cloud_credentials_policyJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/policies/openshift_ingress_operator_cloud_credentials_policy.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesPoliciesOpenshift_machine_api_aws_cloud_credentials_policyJson = []byte(`{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Action": [
+        "ec2:CreateTags",
+        "ec2:DescribeAvailabilityZones",
+        "ec2:DescribeDhcpOptions",
+        "ec2:DescribeImages",
+        "ec2:DescribeInstances",
+        "ec2:DescribeSecurityGroups",
+        "ec2:DescribeSubnets",
+        "ec2:DescribeVpcs",
+        "ec2:RunInstances",
+        "ec2:TerminateInstances",
+        "elasticloadbalancing:DescribeLoadBalancers",
+        "elasticloadbalancing:DescribeTargetGroups",
+        "elasticloadbalancing:RegisterInstancesWithLoadBalancer",
+        "elasticloadbalancing:RegisterTargets",
+        "iam:PassRole",
+        "iam:CreateServiceLinkedRole"
+      ],
+      "Resource": "*"
+    },
+    {
+      "Effect": "Allow",
+      "Action": [
+        "kms:Decrypt",
+        "kms:Encrypt",
+        "kms:GenerateDataKey",
+        "kms:GenerateDataKeyWithoutPlainText",
+        "kms:DescribeKey",
+        "kms:RevokeGrant",
+        "kms:CreateGrant",
+        "kms:ListGrants"
+      ],
+      "Resource": "*",
+      "Condition": {
+        "Bool": {
+          "kms:GrantIsForAWSResource": true
+        }
+      }
+    }
+  ]
+}
+`)
+
+func templatesPoliciesOpenshift_machine_api_aws_cloud_credentials_policyJsonBytes() ([]byte, error) {
+	return _templatesPoliciesOpenshift_machine_api_aws_cloud_credentials_policyJson, nil
+}
+
+func templatesPoliciesOpenshift_machine_api_aws_cloud_credentials_policyJson() (*asset, error) {
+	bytes, err := templatesPoliciesOpenshift_machine_api_aws_cloud_credentials_policyJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/policies/openshift_machine_api_aws_cloud_credentials_policy.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _templatesPoliciesOsd_scp_policyJson = []byte(`{
     "Version": "2012-10-17",
     "Id": "OSD SCP Policy Document",
@@ -278,6 +544,308 @@ func templatesPoliciesOsd_scp_policyJson() (*asset, error) {
 	return a, nil
 }
 
+var _templatesPoliciesSts_installer_permission_policyJson = []byte(`{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Action": [
+        "autoscaling:DescribeAutoScalingGroups",
+        "ec2:AllocateAddress",
+        "ec2:AssociateAddress",
+        "ec2:AssociateDhcpOptions",
+        "ec2:AssociateRouteTable",
+        "ec2:AttachInternetGateway",
+        "ec2:AttachNetworkInterface",
+        "ec2:AuthorizeSecurityGroupEgress",
+        "ec2:AuthorizeSecurityGroupIngress",
+        "ec2:CopyImage",
+        "ec2:CreateDhcpOptions",
+        "ec2:CreateInternetGateway",
+        "ec2:CreateNatGateway",
+        "ec2:CreateNetworkInterface",
+        "ec2:CreateRoute",
+        "ec2:CreateRouteTable",
+        "ec2:CreateSecurityGroup",
+        "ec2:CreateSubnet",
+        "ec2:CreateTags",
+        "ec2:CreateVolume",
+        "ec2:CreateVpc",
+        "ec2:CreateVpcEndpoint",
+        "ec2:DeleteDhcpOptions",
+        "ec2:DeleteInternetGateway",
+        "ec2:DeleteNatGateway",
+        "ec2:DeleteNetworkInterface",
+        "ec2:DeleteRoute",
+        "ec2:DeleteRouteTable",
+        "ec2:DeleteSecurityGroup",
+        "ec2:DeleteSnapshot",
+        "ec2:DeleteSubnet",
+        "ec2:DeleteTags",
+        "ec2:DeleteVolume",
+        "ec2:DeleteVpc",
+        "ec2:DeleteVpcEndpoints",
+        "ec2:DeregisterImage",
+        "ec2:Describe*",
+        "ec2:DetachInternetGateway",
+        "ec2:DisassociateAddress",
+        "ec2:DisassociateRouteTable",
+        "ec2:GetConsoleOutput",
+        "ec2:GetEbsDefaultKmsKeyId",
+        "ec2:ModifyInstanceAttribute",
+        "ec2:ModifyNetworkInterfaceAttribute",
+        "ec2:ModifySubnetAttribute",
+        "ec2:ModifyVpcAttribute",
+        "ec2:ReleaseAddress",
+        "ec2:ReplaceRouteTableAssociation",
+        "ec2:RevokeSecurityGroupEgress",
+        "ec2:RevokeSecurityGroupIngress",
+        "ec2:RunInstances",
+        "ec2:StartInstances",
+        "ec2:StopInstances",
+        "ec2:TerminateInstances",
+        "elasticloadbalancing:AddTags",
+        "elasticloadbalancing:ApplySecurityGroupsToLoadBalancer",
+        "elasticloadbalancing:AttachLoadBalancerToSubnets",
+        "elasticloadbalancing:ConfigureHealthCheck",
+        "elasticloadbalancing:CreateListener",
+        "elasticloadbalancing:CreateLoadBalancer",
+        "elasticloadbalancing:CreateLoadBalancerListeners",
+        "elasticloadbalancing:CreateTargetGroup",
+        "elasticloadbalancing:DeleteLoadBalancer",
+        "elasticloadbalancing:DeleteTargetGroup",
+        "elasticloadbalancing:DeregisterInstancesFromLoadBalancer",
+        "elasticloadbalancing:DeregisterTargets",
+        "elasticloadbalancing:Describe*",
+        "elasticloadbalancing:ModifyLoadBalancerAttributes",
+        "elasticloadbalancing:ModifyTargetGroup",
+        "elasticloadbalancing:ModifyTargetGroupAttributes",
+        "elasticloadbalancing:RegisterInstancesWithLoadBalancer",
+        "elasticloadbalancing:RegisterTargets",
+        "elasticloadbalancing:SetLoadBalancerPoliciesOfListener",
+        "iam:AddRoleToInstanceProfile",
+        "iam:CreateInstanceProfile",
+        "iam:DeleteInstanceProfile",
+        "iam:GetInstanceProfile",
+        "iam:GetRole",
+        "iam:GetRolePolicy",
+        "iam:GetUser",
+        "iam:ListAttachedRolePolicies",
+        "iam:ListInstanceProfiles",
+        "iam:ListInstanceProfilesForRole",
+        "iam:ListRolePolicies",
+        "iam:ListRoles",
+        "iam:ListUserPolicies",
+        "iam:ListUsers",
+        "iam:PassRole",
+        "iam:RemoveRoleFromInstanceProfile",
+        "iam:SimulatePrincipalPolicy",
+        "iam:TagRole",
+        "route53:ChangeResourceRecordSets",
+        "route53:ChangeTagsForResource",
+        "route53:CreateHostedZone",
+        "route53:DeleteHostedZone",
+        "route53:GetChange",
+        "route53:GetHostedZone",
+        "route53:ListHostedZones",
+        "route53:ListHostedZonesByName",
+        "route53:ListResourceRecordSets",
+        "route53:ListTagsForResource",
+        "route53:UpdateHostedZoneComment",
+        "s3:CreateBucket",
+        "s3:DeleteBucket",
+        "s3:DeleteObject",
+        "s3:GetBucketAcl",
+        "s3:GetBucketLocation",
+        "s3:GetBucketPolicy",
+        "s3:GetBucketTagging",
+        "s3:GetEncryptionConfiguration",
+        "s3:GetLifecycleConfiguration",
+        "s3:GetObject",
+        "s3:GetObjectAcl",
+        "s3:GetObjectTagging",
+        "s3:ListBucket",
+        "s3:PutBucketAcl",
+        "s3:PutBucketTagging",
+        "s3:PutEncryptionConfiguration",
+        "s3:PutObject",
+        "s3:PutObjectAcl",
+        "s3:PutObjectTagging",
+        "servicequotas:GetServiceQuota",
+        "servicequotas:ListAWSDefaultServiceQuotas",
+        "sts:AssumeRole",
+        "sts:AssumeRoleWithWebIdentity",
+        "sts:GetCallerIdentity",
+        "tag:GetResources",
+        "tag:UntagResources"
+      ],
+      "Resource": "*"
+    }
+  ]
+}
+`)
+
+func templatesPoliciesSts_installer_permission_policyJsonBytes() ([]byte, error) {
+	return _templatesPoliciesSts_installer_permission_policyJson, nil
+}
+
+func templatesPoliciesSts_installer_permission_policyJson() (*asset, error) {
+	bytes, err := templatesPoliciesSts_installer_permission_policyJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/policies/sts_installer_permission_policy.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesPoliciesSts_instance_controlplane_permission_policyJson = []byte(`{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Action": [
+        "ec2:AttachVolume",
+        "ec2:AuthorizeSecurityGroupIngress",
+        "ec2:CreateSecurityGroup",
+        "ec2:CreateTags",
+        "ec2:CreateVolume",
+        "ec2:DeleteSecurityGroup",
+        "ec2:DeleteVolume",
+        "ec2:Describe*",
+        "ec2:DetachVolume",
+        "ec2:ModifyInstanceAttribute",
+        "ec2:ModifyVolume",
+        "ec2:RevokeSecurityGroupIngress",
+        "elasticloadbalancing:AddTags",
+        "elasticloadbalancing:AttachLoadBalancerToSubnets",
+        "elasticloadbalancing:ApplySecurityGroupsToLoadBalancer",
+        "elasticloadbalancing:CreateListener",
+        "elasticloadbalancing:CreateLoadBalancer",
+        "elasticloadbalancing:CreateLoadBalancerPolicy",
+        "elasticloadbalancing:CreateLoadBalancerListeners",
+        "elasticloadbalancing:CreateTargetGroup",
+        "elasticloadbalancing:ConfigureHealthCheck",
+        "elasticloadbalancing:DeleteListener",
+        "elasticloadbalancing:DeleteLoadBalancer",
+        "elasticloadbalancing:DeleteLoadBalancerListeners",
+        "elasticloadbalancing:DeleteTargetGroup",
+        "elasticloadbalancing:DeregisterInstancesFromLoadBalancer",
+        "elasticloadbalancing:DeregisterTargets",
+        "elasticloadbalancing:Describe*",
+        "elasticloadbalancing:DetachLoadBalancerFromSubnets",
+        "elasticloadbalancing:ModifyListener",
+        "elasticloadbalancing:ModifyLoadBalancerAttributes",
+        "elasticloadbalancing:ModifyTargetGroup",
+        "elasticloadbalancing:ModifyTargetGroupAttributes",
+        "elasticloadbalancing:RegisterInstancesWithLoadBalancer",
+        "elasticloadbalancing:RegisterTargets",
+        "elasticloadbalancing:SetLoadBalancerPoliciesForBackendServer",
+        "elasticloadbalancing:SetLoadBalancerPoliciesOfListener",
+        "kms:DescribeKey"
+      ],
+      "Resource": "*"
+    }
+  ]
+}
+`)
+
+func templatesPoliciesSts_instance_controlplane_permission_policyJsonBytes() ([]byte, error) {
+	return _templatesPoliciesSts_instance_controlplane_permission_policyJson, nil
+}
+
+func templatesPoliciesSts_instance_controlplane_permission_policyJson() (*asset, error) {
+	bytes, err := templatesPoliciesSts_instance_controlplane_permission_policyJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/policies/sts_instance_controlplane_permission_policy.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesPoliciesSts_instance_worker_permission_policyJson = []byte(`{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Action": [
+        "ec2:DescribeInstances",
+        "ec2:DescribeRegions"
+      ],
+      "Resource": "*"
+    }
+  ]
+}
+`)
+
+func templatesPoliciesSts_instance_worker_permission_policyJsonBytes() ([]byte, error) {
+	return _templatesPoliciesSts_instance_worker_permission_policyJson, nil
+}
+
+func templatesPoliciesSts_instance_worker_permission_policyJson() (*asset, error) {
+	bytes, err := templatesPoliciesSts_instance_worker_permission_policyJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/policies/sts_instance_worker_permission_policy.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesPoliciesSts_support_permission_policyJson = []byte(`{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Action": [
+        "cloudtrail:DescribeTrails",
+        "cloudtrail:LookupEvents",
+        "cloudwatch:GetMetricData",
+        "cloudwatch:GetMetricStatistics",
+        "cloudwatch:ListMetrics",
+        "ec2:Describe*",
+        "ec2:GetConsoleOutput",
+        "elasticloadbalancing:Describe*",
+        "iam:GetRole",
+        "iam:ListAttachedRolePolicies",
+        "iam:ListRoles",
+        "route53:GetHostedZone",
+        "route53:ListHostedZones",
+        "route53:ListResourceRecordSets",
+        "s3:GetBucketLocation",
+        "s3:GetBucketPolicy",
+        "s3:GetBucketTagging",
+        "s3:ListBucket",
+        "s3:ListAllMyBuckets",
+        "servicequotas:GetServiceQuota",
+        "sts:DecodeAuthorizationMessage",
+        "tag:GetResources"
+      ],
+      "Resource": "*"
+    }
+  ]
+}
+`)
+
+func templatesPoliciesSts_support_permission_policyJsonBytes() ([]byte, error) {
+	return _templatesPoliciesSts_support_permission_policyJson, nil
+}
+
+func templatesPoliciesSts_support_permission_policyJson() (*asset, error) {
+	bytes, err := templatesPoliciesSts_support_permission_policyJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/policies/sts_support_permission_policy.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -330,8 +898,17 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"templates/cloudformation/iam_user_osdCcsAdmin.json": templatesCloudformationIam_user_osdccsadminJson,
-	"templates/policies/osd_scp_policy.json":             templatesPoliciesOsd_scp_policyJson,
+	"templates/cloudformation/iam_user_osdCcsAdmin.json":                                                        templatesCloudformationIam_user_osdccsadminJson,
+	"templates/policies/openshift_cloud_credential_operator_cloud_credential_operator_iam_ro_creds_policy.json": templatesPoliciesOpenshift_cloud_credential_operator_cloud_credential_operator_iam_ro_creds_policyJson,
+	"templates/policies/openshift_cluster_csi_drivers_ebs_cloud_credentials_policy.json":                        templatesPoliciesOpenshift_cluster_csi_drivers_ebs_cloud_credentials_policyJson,
+	"templates/policies/openshift_image_registry_installer_cloud_credentials_policy.json":                       templatesPoliciesOpenshift_image_registry_installer_cloud_credentials_policyJson,
+	"templates/policies/openshift_ingress_operator_cloud_credentials_policy.json":                               templatesPoliciesOpenshift_ingress_operator_cloud_credentials_policyJson,
+	"templates/policies/openshift_machine_api_aws_cloud_credentials_policy.json":                                templatesPoliciesOpenshift_machine_api_aws_cloud_credentials_policyJson,
+	"templates/policies/osd_scp_policy.json":                                                                    templatesPoliciesOsd_scp_policyJson,
+	"templates/policies/sts_installer_permission_policy.json":                                                   templatesPoliciesSts_installer_permission_policyJson,
+	"templates/policies/sts_instance_controlplane_permission_policy.json":                                       templatesPoliciesSts_instance_controlplane_permission_policyJson,
+	"templates/policies/sts_instance_worker_permission_policy.json":                                             templatesPoliciesSts_instance_worker_permission_policyJson,
+	"templates/policies/sts_support_permission_policy.json":                                                     templatesPoliciesSts_support_permission_policyJson,
 }
 
 // AssetDir returns the file names below a certain
@@ -380,7 +957,16 @@ var _bintree = &bintree{nil, map[string]*bintree{
 			"iam_user_osdCcsAdmin.json": &bintree{templatesCloudformationIam_user_osdccsadminJson, map[string]*bintree{}},
 		}},
 		"policies": &bintree{nil, map[string]*bintree{
+			"openshift_cloud_credential_operator_cloud_credential_operator_iam_ro_creds_policy.json": &bintree{templatesPoliciesOpenshift_cloud_credential_operator_cloud_credential_operator_iam_ro_creds_policyJson, map[string]*bintree{}},
+			"openshift_cluster_csi_drivers_ebs_cloud_credentials_policy.json": &bintree{templatesPoliciesOpenshift_cluster_csi_drivers_ebs_cloud_credentials_policyJson, map[string]*bintree{}},
+			"openshift_image_registry_installer_cloud_credentials_policy.json": &bintree{templatesPoliciesOpenshift_image_registry_installer_cloud_credentials_policyJson, map[string]*bintree{}},
+			"openshift_ingress_operator_cloud_credentials_policy.json": &bintree{templatesPoliciesOpenshift_ingress_operator_cloud_credentials_policyJson, map[string]*bintree{}},
+			"openshift_machine_api_aws_cloud_credentials_policy.json": &bintree{templatesPoliciesOpenshift_machine_api_aws_cloud_credentials_policyJson, map[string]*bintree{}},
 			"osd_scp_policy.json": &bintree{templatesPoliciesOsd_scp_policyJson, map[string]*bintree{}},
+			"sts_installer_permission_policy.json": &bintree{templatesPoliciesSts_installer_permission_policyJson, map[string]*bintree{}},
+			"sts_instance_controlplane_permission_policy.json": &bintree{templatesPoliciesSts_instance_controlplane_permission_policyJson, map[string]*bintree{}},
+			"sts_instance_worker_permission_policy.json": &bintree{templatesPoliciesSts_instance_worker_permission_policyJson, map[string]*bintree{}},
+			"sts_support_permission_policy.json": &bintree{templatesPoliciesSts_support_permission_policyJson, map[string]*bintree{}},
 		}},
 	}},
 }}
@@ -0,0 +1,49 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clear
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/cache"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove the local cache",
+	Long:  "Remove the local cache of regions, versions and machine types.",
+	Example: `  # Remove the local cache
+  rosa cache clear`,
+	Args: cobra.NoArgs,
+	RunE: run,
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	err := cache.Clear()
+	if err != nil {
+		return fmt.Errorf("Failed to remove cache: %w", err)
+	}
+	reporter.Infof("Cache has been removed")
+
+	return nil
+}
@@ -0,0 +1,35 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/cmd/cache/clear"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "cache COMMAND",
+	Short: "Manage the local cache",
+	Long: "Manage the local cache of data that rarely changes, like the lists of regions, versions " +
+		"and machine types. Use '--refresh' with any command to retrieve that data from the API " +
+		"again.",
+}
+
+func init() {
+	Cmd.AddCommand(clear.Cmd)
+}
@@ -0,0 +1,273 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+
+	"github.com/openshift/moactl/pkg/aws"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm/regions"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/table"
+)
+
+var args struct {
+	toRegion       string
+	name           string
+	subnetIDs      []string
+	export         string
+	skipQuotaCheck bool
+}
+
+var Cmd = &cobra.Command{
+	Use:   "cluster [ID|NAME] [-- CREATE FLAGS]",
+	Short: "Create a copy of a cluster in a different region",
+	Long: "Create a new cluster in a different region with the settings and the machine pools of an " +
+		"existing cluster. The settings that refer to resources of the original region, like the " +
+		"subnets, the security groups and the KMS key, are removed, and the AWS quotas of the target " +
+		"region are checked before the cluster is created. Flags given after '--' are passed to " +
+		"'rosa create cluster' and take precedence over the copied settings. Identity providers " +
+		"aren't copied, as their secrets can't be read back.",
+	Example: `  # Create a copy of cluster "mycluster" named "mycluster-dr" in region eu-west-1
+  rosa clone cluster mycluster --to-region=eu-west-1 --name=mycluster-dr
+
+  # Use existing subnets and a KMS key of the target region
+  rosa clone cluster mycluster --to-region=eu-west-1 --name=mycluster-dr \
+    --subnet-ids=subnet-1,subnet-2 -- --kms-key-arn=arn:aws:kms:eu-west-1:123456789012:key/abcd
+
+  # Write the definition of the copy to a file, to review it before creating the cluster with
+  # 'rosa create cluster --file'
+  rosa clone cluster mycluster --to-region=eu-west-1 --name=mycluster-dr --export=mycluster-dr.yaml`,
+	RunE: run,
+}
+
+func init() {
+	clusterprovider.UseKey(Cmd)
+
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringVar(
+		&args.toRegion,
+		"to-region",
+		"",
+		"AWS region where the copy of the cluster will be created.",
+	)
+	_ = Cmd.MarkFlagRequired("to-region")
+	_ = Cmd.RegisterFlagCompletionFunc("to-region", regions.Complete)
+
+	flags.StringVar(
+		&args.name,
+		"name",
+		"",
+		"Name of the copy of the cluster.",
+	)
+	_ = Cmd.MarkFlagRequired("name")
+
+	flags.StringSliceVar(
+		&args.subnetIDs,
+		"subnet-ids",
+		nil,
+		"Subnets of the target region where the copy of the cluster will be installed. By default "+
+			"the cluster is installed in a new VPC.",
+	)
+
+	flags.StringVar(
+		&args.export,
+		"export",
+		"",
+		"Write the definition of the copy of the cluster to the given file instead of creating it.",
+	)
+
+	flags.BoolVar(
+		&args.skipQuotaCheck,
+		"skip-quota-check",
+		false,
+		"Don't check the AWS quotas of the target region.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	// Arguments after '--' are flags for 'rosa create cluster':
+	createArgs := []string{}
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		createArgs = argv[dash:]
+		argv = argv[:dash]
+	}
+
+	if !clusterprovider.IsValidClusterName(args.name) {
+		return rosaerrors.Usagef("Cluster name must consist of no more than 15 lowercase alphanumeric " +
+			"characters or '-', start with a letter, and end with an alphanumeric character.")
+	}
+
+	clusterKey, err := clusterprovider.GetKey(r, argv)
+	if err != nil {
+		return err
+	}
+
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	// Check that the target region is available:
+	reporter.Debugf("Loading regions")
+	regionList, regionAZ, err := regions.GetRegionList(r.OCM(),
+		r.WithAWSRegion(aws.GlobalRegion()).AWSClient(), false)
+	if err != nil {
+		return err
+	}
+	if _, ok := regionAZ[args.toRegion]; !ok {
+		return rosaerrors.Usagef("Region '%s' isn't available, use one of %v", args.toRegion, regionList)
+	}
+	if cluster.MultiAZ() && !regionAZ[args.toRegion] {
+		return rosaerrors.Usagef("Cluster '%s' is multi-AZ, but region '%s' doesn't support multi-AZ clusters",
+			clusterKey, args.toRegion)
+	}
+
+	reporter.Debugf("Exporting definition of cluster '%s'", clusterKey)
+	definition, err := clusterprovider.ExportDefinition(r.OCM(), cluster)
+	if err != nil {
+		return err
+	}
+	definition.Name = args.name
+	for _, removed := range definition.Relocate(args.toRegion, args.subnetIDs) {
+		reporter.Warnf("Not copying %s", removed)
+	}
+
+	data, err := yaml.Marshal(definition)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal definition of cluster '%s': %w", args.name, err)
+	}
+	if args.export != "" {
+		err = ioutil.WriteFile(args.export, data, 0600)
+		if err != nil {
+			return fmt.Errorf("Failed to write file '%s': %w", args.export, err)
+		}
+		reporter.Infof("Definition of cluster '%s' written to '%s', create it with "+
+			"'rosa create cluster --file=%s'", args.name, args.export, args.export)
+		return nil
+	}
+
+	if !args.skipQuotaCheck {
+		err = checkQuotas(r, args.toRegion)
+		if err != nil {
+			return err
+		}
+	}
+
+	file, err := ioutil.TempFile("", "rosa-clone-*.yaml")
+	if err != nil {
+		return fmt.Errorf("Failed to create temporary file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Close()
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to write temporary file '%s': %w", file.Name(), err)
+	}
+
+	reporter.Infof("Creating cluster '%s' in region '%s' with the settings of cluster '%s'", args.name,
+		args.toRegion, clusterKey)
+	return createCluster(r, cmd, file.Name(), createArgs)
+}
+
+// checkQuotas fails with the list of the insufficient AWS quotas if the target region doesn't have
+// the quotas needed to install the cluster.
+func checkQuotas(r *runtime.Runtime, region string) error {
+	reporter := r.Reporter()
+
+	reporter.Infof("Validating AWS quota in region '%s'...", region)
+	checks, err := r.WithAWSRegion(region).AWSClient().CheckQuotas()
+	if err != nil {
+		return fmt.Errorf("Failed to check AWS quotas in region '%s': %w", region, err)
+	}
+	insufficient := aws.InsufficientQuotas(checks)
+	if len(insufficient) == 0 {
+		return nil
+	}
+	writer := table.NewWriter(os.Stdout).Numeric("REQUIRED", "VALUE", "MISSING")
+	fmt.Fprintf(writer, "SERVICE\tQUOTA CODE\tQUOTA NAME\tREQUIRED\tVALUE\tMISSING\n")
+	for _, check := range insufficient {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%d\t%d\t%d\n",
+			check.ServiceCode, check.QuotaCode, check.QuotaName,
+			int(check.Required), int(check.Value), int(check.Missing))
+	}
+	writer.Flush()
+	return rosaerrors.Quotaf("Insufficient AWS quotas in region '%s'", region)
+}
+
+// createCluster runs 'rosa create cluster' with the given definition file, as a separate process so
+// that the file and the flags are parsed like those given by users, and fails with its exit code.
+// The global flags given to this command, except the one that selects the original cluster, are
+// passed along.
+func createCluster(r *runtime.Runtime, cmd *cobra.Command, file string, createArgs []string) error {
+	reporter := r.Reporter()
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("Failed to find executable: %w", err)
+	}
+	argv := []string{"create", "cluster", "--file", file}
+	cmd.InheritedFlags().Visit(func(flag *pflag.Flag) {
+		if flag.Name == clusterprovider.KeyFlag {
+			return
+		}
+		if value, ok := flag.Value.(pflag.SliceValue); ok {
+			for _, item := range value.GetSlice() {
+				argv = append(argv, "--"+flag.Name+"="+item)
+			}
+			return
+		}
+		argv = append(argv, "--"+flag.Name+"="+flag.Value.String())
+	})
+	argv = append(argv, createArgs...)
+	if args.skipQuotaCheck {
+		argv = append(argv, "--skip-quota-check")
+	}
+
+	reporter.Debugf("Running '%s %v'", executable, argv)
+	// #nosec G204
+	child := exec.Command(executable, argv...)
+	child.Stdin = os.Stdin
+	child.Stdout = os.Stdout
+	child.Stderr = os.Stderr
+	err = child.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() > 0 {
+		// The reason has already been reported by the child, so only its exit code is kept:
+		return rosaerrors.Wrap(exitErr.ExitCode(), fmt.Errorf("Failed to create cluster '%s'", args.name))
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to create cluster '%s': %w", args.name, err)
+	}
+	return nil
+}
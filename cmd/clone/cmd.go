@@ -0,0 +1,36 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clone
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/cmd/clone/cluster"
+	"github.com/openshift/moactl/pkg/ocm/roles"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "clone RESOURCE",
+	Short: "Create a copy of a resource",
+	Long:  "Create a copy of a resource, for example in a different region",
+}
+
+func init() {
+	roles.Require(Cmd, roles.Create)
+
+	Cmd.AddCommand(cluster.Cmd)
+}
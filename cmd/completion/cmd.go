@@ -20,12 +20,17 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 )
 
 var Cmd = &cobra.Command{
-	Use:   "completion",
-	Short: "Generates bash completion scripts",
-	Long: `To load completion run
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generates completion scripts",
+	Long: `Generates the completion script for the given shell, bash by default. In bash and fish
+the values of some flags, like '--cluster' and '--region', are also completed.
+
+To load completion in bash run
 
 . <(rosa completion)
 
@@ -33,8 +38,43 @@ To configure your bash shell to load completions for each session add to your ba
 
 # ~/.bashrc or ~/.profile
 . <(rosa completion)
+
+To load completion in zsh run
+
+rosa completion zsh > "${fpath[1]}/_rosa"
+
+To load completion in fish run
+
+rosa completion fish | source
+
+To load completion in PowerShell run
+
+rosa completion powershell | Out-String | Invoke-Expression
 `,
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Root().GenBashCompletion(os.Stdout)
-	},
+	ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+	Args:      cobra.MaximumNArgs(1),
+	RunE:      run,
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	shell := "bash"
+	if len(argv) == 1 {
+		shell = argv[0]
+	}
+
+	var err error
+	switch shell {
+	case "bash":
+		err = cmd.Root().GenBashCompletion(os.Stdout)
+	case "zsh":
+		err = cmd.Root().GenZshCompletion(os.Stdout)
+	case "fish":
+		err = cmd.Root().GenFishCompletion(os.Stdout, true)
+	case "powershell":
+		err = cmd.Root().GenPowerShellCompletion(os.Stdout)
+	default:
+		err = rosaerrors.Usagef("Shell '%s' isn't supported, valid options are 'bash', 'zsh', 'fish' and "+
+			"'powershell'", shell)
+	}
+	return err
 }
@@ -0,0 +1,38 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/cmd/config/get"
+	"github.com/openshift/moactl/cmd/config/set"
+	"github.com/openshift/moactl/cmd/config/unset"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "config COMMAND KEY [VALUE]",
+	Short: "Manage the settings of the configuration file",
+	Long: "Manage the settings kept in the configuration file, '~/.config/rosa/config.yaml', " +
+		"like the default cluster used by the commands when '--cluster' isn't given.",
+}
+
+func init() {
+	Cmd.AddCommand(get.Cmd)
+	Cmd.AddCommand(set.Cmd)
+	Cmd.AddCommand(unset.Cmd)
+}
@@ -0,0 +1,54 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package get
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "get KEY",
+	Short: "Print a setting of the configuration file",
+	Long: "Print a setting of the configuration file. Nothing is printed if the setting isn't set. " +
+		"The only setting is 'cluster', the default cluster of the commands.",
+	Example: `  # Print the default cluster
+  rosa config get cluster`,
+	Args: cobra.ExactArgs(1),
+	RunE: run,
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	key := argv[0]
+	if key != cluster.KeyFlag {
+		return rosaerrors.Usagef("Setting '%s' isn't valid, the only setting is '%s'", key, cluster.KeyFlag)
+	}
+
+	value, err := cluster.GetDefaultKey()
+	if err != nil {
+		return fmt.Errorf("Failed to load default cluster: %w", err)
+	}
+	if value != "" {
+		fmt.Println(value)
+	}
+
+	return nil
+}
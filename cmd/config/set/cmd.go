@@ -0,0 +1,70 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package set
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "set KEY VALUE",
+	Short: "Set a setting of the configuration file",
+	Long: "Set a setting of the configuration file. The only setting is 'cluster', the name or " +
+		"identifier of the cluster used by the commands when '--cluster' isn't given.",
+	Example: `  # Use the cluster named "mycluster" when '--cluster' isn't given
+  rosa config set cluster mycluster`,
+	Args: cobra.ExactArgs(2),
+	RunE: run,
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	key, value := argv[0], argv[1]
+	if key != cluster.KeyFlag {
+		return rosaerrors.Usagef("Setting '%s' isn't valid, the only setting is '%s'", key, cluster.KeyFlag)
+	}
+	if !cluster.IsValidClusterKey(value) {
+		return fmt.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			value,
+		)
+	}
+
+	// Check that the cluster exists before saving it:
+	reporter.Debugf("Loading cluster '%s'", value)
+	_, err := r.OCM().GetCluster(value, r.Creator().ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", value, err)
+	}
+
+	err = cluster.SaveDefaultKey(value)
+	if err != nil {
+		return fmt.Errorf("Failed to save default cluster: %w", err)
+	}
+	reporter.Infof("Commands will use cluster '%s' when '--cluster' isn't given", value)
+
+	return nil
+}
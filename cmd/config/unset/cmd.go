@@ -0,0 +1,56 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unset
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "unset KEY",
+	Short: "Remove a setting from the configuration file",
+	Long: "Remove a setting from the configuration file. The only setting is 'cluster', the default " +
+		"cluster of the commands.",
+	Example: `  # Stop using a default cluster
+  rosa config unset cluster`,
+	Args: cobra.ExactArgs(1),
+	RunE: run,
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	key := argv[0]
+	if key != cluster.KeyFlag {
+		return rosaerrors.Usagef("Setting '%s' isn't valid, the only setting is '%s'", key, cluster.KeyFlag)
+	}
+
+	err := cluster.SaveDefaultKey("")
+	if err != nil {
+		return fmt.Errorf("Failed to remove default cluster: %w", err)
+	}
+	reporter.Infof("Commands will require '--cluster' again")
+
+	return nil
+}
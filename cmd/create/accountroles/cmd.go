@@ -0,0 +1,128 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accountroles
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/iamsettings"
+	"github.com/openshift/moactl/pkg/ci"
+	"github.com/openshift/moactl/pkg/confirm"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm/roles"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var args struct {
+	prefix string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "account-roles",
+	Aliases: []string{"accountroles", "account-role"},
+	Short:   "Create account roles",
+	Long: "Create the IAM roles that are shared by all the clusters of the AWS account that use AWS STS, " +
+		"with the managed policies that contain their permissions. Roles that already exist are " +
+		"updated to the policies of this version of the tool.",
+	Example: `  # Create the account roles with the default prefix
+  rosa create account-roles
+
+  # Create the account roles with a custom prefix
+  rosa create account-roles --prefix=MyOrg
+
+  # Create the account roles with the permissions boundary and path required by the organization
+  rosa create account-roles --permissions-boundary=arn:aws:iam::123456789012:policy/Boundary \
+    --iam-path=/rosa/`,
+	RunE: run,
+}
+
+func init() {
+	roles.Require(Cmd, roles.None)
+
+	flags := Cmd.Flags()
+
+	flags.StringVar(
+		&args.prefix,
+		"prefix",
+		aws.DefaultAccountRolePrefix,
+		"Prefix of the names of the account roles.",
+	)
+
+	iamsettings.AddFlags(flags)
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	err := aws.ValidateAccountRolePrefix(args.prefix)
+	if err != nil {
+		return err
+	}
+	settings := iamsettings.Settings()
+	err = aws.ValidateIAMSettings(settings)
+	if err != nil {
+		return err
+	}
+	awsClient := r.AWSClient()
+
+	reporter.Debugf("Loading account roles with prefix '%s'", args.prefix)
+	existing, err := awsClient.GetAccountRoles(args.prefix)
+	if err != nil {
+		return fmt.Errorf("Failed to get account roles: %w", err)
+	}
+	for _, role := range existing {
+		// IAM doesn't allow changing the path of a role, so check it before changing anything:
+		if settings.Path != "" && role.Path != settings.Path {
+			return rosaerrors.Conflictf("Role '%s' already exists with path '%s' instead of '%s'. The path of a role "+
+				"can't be changed, delete the role to create it again with the new path",
+				role.Name, role.Path, settings.Path)
+		}
+		reporter.Infof("Role '%s' already exists and will be updated", role.Name)
+		if settings.PermissionsBoundary != "" && role.PermissionsBoundary != settings.PermissionsBoundary {
+			reporter.Infof("The permissions boundary of role '%s' will be changed to '%s'",
+				role.Name, settings.PermissionsBoundary)
+		}
+	}
+
+	if !confirm.Confirm("create the account roles with prefix '%s' in AWS account %s",
+		args.prefix, r.Creator().AccountID) {
+		return nil
+	}
+
+	for _, roleType := range aws.AccountRoleTypes {
+		roleName := roleType.RoleName(args.prefix)
+		reporter.Infof("Creating %s role '%s'", roleType.Name, roleName)
+		roleARN, err := awsClient.CreateAccountRole(args.prefix, roleType, settings)
+		if err != nil {
+			return fmt.Errorf("Failed to create %s role '%s': %w", roleType.Name, roleName, err)
+		}
+		ci.RecordResource(&ci.Resource{Kind: "account-role", ID: roleARN, Name: roleName})
+		fmt.Printf("%s\n", roleARN)
+	}
+	command := "rosa create cluster --sts"
+	if args.prefix != aws.DefaultAccountRolePrefix {
+		command = fmt.Sprintf("%s --account-roles-prefix=%s", command, args.prefix)
+	}
+	reporter.Infof("Created account roles with prefix '%s'. To create a cluster that uses them run '%s'",
+		args.prefix, command)
+
+	return nil
+}
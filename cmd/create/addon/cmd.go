@@ -17,23 +17,18 @@ limitations under the License.
 package addon
 
 import (
-	"os"
+	"fmt"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/ci"
 	clusterprovider "github.com/openshift/moactl/pkg/cluster"
 	"github.com/openshift/moactl/pkg/confirm"
-	"github.com/openshift/moactl/pkg/logging"
-	"github.com/openshift/moactl/pkg/ocm"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
-var args struct {
-	clusterKey string
-}
-
 var Cmd = &cobra.Command{
 	Use:     "addon",
 	Aliases: []string{"addons", "add-on", "add-ons"},
@@ -42,106 +37,54 @@ var Cmd = &cobra.Command{
 	Long:    "Install Red Hat managed add-ons on a cluster",
 	Example: `  # Add the CodeReady Workspaces add-on installation to the cluster
   rosa create addon --cluster=mycluster codeready-workspaces`,
-	Run: run,
+	Deprecated: "use 'rosa install addon' instead",
+	RunE:       run,
 }
 
 func init() {
-	flags := Cmd.Flags()
-	confirm.AddFlag(flags)
-
-	flags.StringVarP(
-		&args.clusterKey,
-		"cluster",
-		"c",
-		"",
-		"Name or ID of the cluster to add the IdP to (required).",
-	)
-	Cmd.MarkFlagRequired("cluster")
+	clusterprovider.UseKey(Cmd)
 }
 
-func run(_ *cobra.Command, argv []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
 
 	// Check command line arguments:
 	if len(argv) != 1 {
-		reporter.Errorf("Expected exactly one command line parameters containing the identifier of the add-on.")
-		os.Exit(1)
+		return rosaerrors.Usagef("Expected exactly one command line parameters containing the identifier of the add-on.")
 	}
 
 	addOnID := argv[0]
 	if addOnID == "" {
-		reporter.Errorf("Add-on ID is required.")
-		os.Exit(1)
-	}
-
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
-	clusterKey := args.clusterKey
-	if !ocm.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
-	}
-
-	// Create the AWS client:
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		return rosaerrors.Usagef("Add-on ID is required.")
 	}
 
-	awsCreator, err := awsClient.GetCreator()
+	clusterKey, err := clusterprovider.GetKey(r, nil)
 	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		return err
 	}
 
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
-	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
-
-	// Get the client for the OCM collection of clusters:
-	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
-
 	// Try to find the cluster:
 	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
 	if err != nil {
-		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
 	}
 
 	if cluster.State() != cmv1.ClusterStateReady {
-		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
-		os.Exit(1)
+		return rosaerrors.Conflictf("Cluster '%s' is not yet ready", clusterKey)
 	}
 
-	reporter.Warnf("Once installed, add-ons cannot be uninstalled")
 	if confirm.Confirm("install add-on '%s' on cluster '%s'", addOnID, clusterKey) {
 		reporter.Debugf("Installing add-on '%s' on cluster '%s'", addOnID, clusterKey)
-		err = clusterprovider.InstallAddOn(clustersCollection, clusterKey, awsCreator.ARN, addOnID)
+		err = clusterprovider.InstallAddOn(r.OCM(), clusterKey, r.Creator().ARN, addOnID)
 		if err != nil {
-			reporter.Errorf("Failed to add add-on installation '%s' for cluster '%s': %s", addOnID, clusterKey, err)
-			os.Exit(1)
+			return fmt.Errorf("Failed to add add-on installation '%s' for cluster '%s': %w", addOnID, clusterKey, err)
 		}
+		ci.RecordResource(&ci.Resource{Kind: "addon", Cluster: cluster.ID(), ID: addOnID})
 		reporter.Infof("Add-on '%s' is now installing. To check the status run 'rosa list addons -c %s'",
 			addOnID, clusterKey)
 	}
+
+	return nil
 }
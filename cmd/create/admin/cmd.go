@@ -17,26 +17,21 @@ limitations under the License.
 package admin
 
 import (
-	"crypto/rand"
-	"math/big"
-	"os"
+	"fmt"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/moactl/pkg/aws"
-	"github.com/openshift/moactl/pkg/logging"
+	"github.com/openshift/moactl/pkg/ci"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/ocm"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
-)
-
-const (
-	idpName  = "Cluster-Admin"
-	username = "cluster-admin"
+	"github.com/openshift/moactl/pkg/ocm/htpasswd"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
 var args struct {
-	clusterKey string
+	regeneratePassword bool
 }
 
 var Cmd = &cobra.Command{
@@ -44,184 +39,155 @@ var Cmd = &cobra.Command{
 	Short: "Creates an admin user to login to the cluster",
 	Long:  "Creates a cluster-admin user with an auto-generated password to login to the cluster",
 	Example: `  # Create an admin user to login to the cluster
-  rosa create admin --cluster=mycluster`,
-	Run: run,
+  rosa create admin --cluster=mycluster
+
+  # Replace the password of the admin user with a new generated one
+  rosa create admin --cluster=mycluster --regenerate-password`,
+	RunE: run,
 }
 
 func init() {
 	flags := Cmd.Flags()
 
-	flags.StringVarP(
-		&args.clusterKey,
-		"cluster",
-		"c",
-		"",
-		"Name or ID of the cluster to add the IdP to (required).",
+	clusterprovider.UseKey(Cmd)
+
+	flags.BoolVar(
+		&args.regeneratePassword,
+		"regenerate-password",
+		false,
+		"Replace the password of the existing admin user with a new generated one, for example to "+
+			"rotate it or when it has been lost.",
 	)
-	Cmd.MarkFlagRequired("cluster")
 }
 
-func run(cmd *cobra.Command, _ []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
 
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
-	clusterKey := args.clusterKey
-	if !ocm.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
+	clusterKey, err := clusterprovider.GetKey(r, nil)
+	if err != nil {
+		return err
 	}
 
 	reporter.Warnf("It is recommended to add an identity provider to login to this cluster. " +
 		"See 'rosa create idp --help' for more information.")
 
-	// Create the AWS client:
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Build()
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
 	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
 	}
 
-	awsCreator, err := awsClient.GetCreator()
-	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+	if cluster.State() != cmv1.ClusterStateReady {
+		return rosaerrors.Conflictf("Cluster '%s' is not yet ready", clusterKey)
 	}
 
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
+	reporter.Debugf("Loading '%s' identity provider", ocm.AdminIdentityProviderName)
+	idp, err := r.OCM().GetAdminIdentityProvider(cluster.ID())
 	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get '%s' identity provider for cluster '%s': %w",
+			ocm.AdminIdentityProviderName, clusterKey, err)
+	}
+	if idp != nil && !args.regeneratePassword {
+		return rosaerrors.Conflictf("Cluster '%s' already has an admin. To replace its password run the following "+
+			"command:\n   rosa create admin -c %s --regenerate-password", clusterKey, clusterKey)
+	}
+	if idp == nil && args.regeneratePassword {
+		return rosaerrors.NotFoundf("Cluster '%s' doesn't have an admin. To create it run the following command:\n"+
+			"   rosa create admin -c %s", clusterKey, clusterKey)
 	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
-
-	// Get the client for the OCM collection of clusters:
-	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
 
-	// Try to find the cluster:
-	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
+	password, err := ocm.GenerateAdminPassword()
 	if err != nil {
-		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to generate a random password")
 	}
 
-	if cluster.State() != cmv1.ClusterStateReady {
-		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
-		os.Exit(1)
+	if args.regeneratePassword {
+		err = regeneratePassword(r, cluster, clusterKey, idp, password)
+	} else {
+		err = createAdmin(r, cluster, clusterKey, password)
+	}
+	if err != nil {
+		return err
 	}
 
-	// TODO: Verify that the htpasswd IdP does not already exist
+	reporter.Infof("Please securely store this generated password. " +
+		"If you lose this password you can generate a new one with '--regenerate-password'.")
+	reporter.Infof("Password: %s", password)
 
-	// TODO: Verify that the user does not already exist
+	// The password isn't part of the suggested command, so that it doesn't end up in the history of
+	// the shell. The 'oc' tool asks for it instead:
+	reporter.Infof("To login, run the following command and enter the password when asked:\n"+
+		"   oc login %s --username %s", cluster.API().URL(), ocm.AdminUsername)
 
-	password, err := generateRandomPassword(23)
-	if err != nil {
-		reporter.Errorf("Failed to generate a random password")
-		os.Exit(1)
-	}
+	return nil
+}
+
+// createAdmin adds the admin user to the cluster-admins group and creates the identity provider
+// that it logs in with.
+func createAdmin(r *runtime.Runtime, cluster *cmv1.Cluster, clusterKey string, password string) error {
+	reporter := r.Reporter()
 
 	// Add admin user to the cluster-admins group:
-	reporter.Debugf("Adding '%s' user to cluster '%s'", username, clusterKey)
-	user, err := cmv1.NewUser().ID(username).Build()
+	reporter.Debugf("Adding '%s' user to cluster '%s'", ocm.AdminUsername, clusterKey)
+	err := r.OCM().AddGroupUser(cluster.ID(), ocm.ClusterAdminsGroup, ocm.AdminUsername)
 	if err != nil {
-		reporter.Errorf("Failed to create user '%s' for cluster '%s'", username, clusterKey)
-		os.Exit(1)
-	}
-	userResp, err := clustersCollection.Cluster(cluster.ID()).
-		Groups().Group("cluster-admins").
-		Users().Add().Body(user).
-		Send()
-	if err != nil {
-		reporter.Errorf("Failed to add user '%s' to cluster '%s': %s",
-			username, clusterKey, userResp.Error().Reason())
-		os.Exit(1)
+		return fmt.Errorf("Failed to add user '%s' to cluster '%s': %w", ocm.AdminUsername, clusterKey, err)
 	}
 
 	// Create HTPasswd IDP configuration:
-	reporter.Debugf("Adding '%s' udp to cluster '%s'", idpName, clusterKey)
-	htpasswdIDP := cmv1.NewHTPasswdIdentityProvider().
-		Username(username).
-		Password(password)
-
-	// Create new IDP with HTPasswd provider:
-	idp, err := cmv1.NewIdentityProvider().
-		Type("HTPasswdIdentityProvider"). // FIXME: ocm-api-model has the wrong enum values
-		Name(idpName).
-		MappingMethod(cmv1.IdentityProviderMappingMethod("claim")).
-		Htpasswd(htpasswdIDP).
-		Build()
+	reporter.Debugf("Adding '%s' idp to cluster '%s'", ocm.AdminIdentityProviderName, clusterKey)
+	idp, err := ocm.BuildAdminIdentityProvider(password)
 	if err != nil {
-		reporter.Errorf("Failed to create '%s' identity provider for cluster '%s'", idpName, clusterKey)
-		os.Exit(1)
+		return fmt.Errorf("Failed to create '%s' identity provider for cluster '%s': %w",
+			ocm.AdminIdentityProviderName, clusterKey, err)
 	}
-
-	// Add HTPasswd IDP to cluster:
-	idpResp, err := clustersCollection.Cluster(cluster.ID()).
-		IdentityProviders().
-		Add().
-		Body(idp).
-		Send()
+	err = r.OCM().AddIdentityProvider(cluster.ID(), idp)
 	if err != nil {
-		reporter.Errorf("Failed to add '%s' identity provider to cluster '%s': %s",
-			idpName, clusterKey, idpResp.Error().Reason())
-		os.Exit(1)
+		return fmt.Errorf("Failed to add '%s' identity provider to cluster '%s': %w",
+			ocm.AdminIdentityProviderName, clusterKey, err)
 	}
 
+	ci.RecordResource(&ci.Resource{Kind: "identity-provider", Cluster: cluster.ID(),
+		Name: ocm.AdminIdentityProviderName})
+	ci.RecordResource(&ci.Resource{Kind: "user", Cluster: cluster.ID(), ID: ocm.AdminUsername})
 	reporter.Infof("Admin account has been added to cluster '%s'. "+
 		"It may take up to a minute for the account to become active.", clusterKey)
-	reporter.Infof("Please securely store this generated password. " +
-		"If you lose this password you can delete and recreate the cluster admin user.")
-	reporter.Infof("To login, run the following command:\n"+
-		"   oc login %s \\\n   --username %s \\\n   --password %s", cluster.API().URL(), username, password)
+
+	return nil
 }
 
-func generateRandomPassword(length int) (string, error) {
-	const (
-		lowerLetters = "abcdefghijkmnopqrstuvwxyz"
-		upperLetters = "ABCDEFGHIJKLMNPQRSTUVWXYZ"
-		digits       = "23456789"
-		all          = lowerLetters + upperLetters + digits
-	)
-	var password string
-	for i := 0; i < length; i++ {
-		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(all))))
-		if err != nil {
-			return "", err
-		}
-		newchar := string(all[n.Int64()])
-		if password == "" {
-			password = newchar
-		}
-		if i < length-1 {
-			n, err = rand.Int(rand.Reader, big.NewInt(int64(len(password)+1)))
-			if err != nil {
-				return "", err
-			}
-			j := n.Int64()
-			password = password[0:j] + newchar + password[j:]
+// regeneratePassword replaces the password of the admin user of the given identity provider.
+func regeneratePassword(r *runtime.Runtime, cluster *cmv1.Cluster, clusterKey string,
+	idp *cmv1.IdentityProvider, password string) error {
+	reporter := r.Reporter()
+
+	reporter.Debugf("Loading users of '%s' identity provider", ocm.AdminIdentityProviderName)
+	users, err := r.OCM().GetHTPasswdUsers(cluster.ID(), idp.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get users of '%s' identity provider for cluster '%s': %w",
+			ocm.AdminIdentityProviderName, clusterKey, err)
+	}
+	var user *htpasswd.User
+	for _, item := range users {
+		if item.Username == ocm.AdminUsername {
+			user = item
 		}
 	}
+	if user == nil {
+		return rosaerrors.NotFoundf("Identity provider '%s' of cluster '%s' doesn't have user '%s'",
+			ocm.AdminIdentityProviderName, clusterKey, ocm.AdminUsername)
+	}
 
-	pw := []rune(password)
-	for _, replace := range []int{5, 11, 17} {
-		pw[replace] = '-'
+	reporter.Debugf("Replacing password of user '%s' on cluster '%s'", ocm.AdminUsername, clusterKey)
+	err = r.OCM().UpdateHTPasswdPassword(cluster.ID(), idp.ID(), user.ID, password)
+	if err != nil {
+		return fmt.Errorf("Failed to replace password of user '%s' on cluster '%s': %w",
+			ocm.AdminUsername, clusterKey, err)
 	}
+	reporter.Infof("Password of the admin of cluster '%s' has been replaced. "+
+		"It may take up to a minute for the new password to become active.", clusterKey)
 
-	return string(pw), nil
+	return nil
 }
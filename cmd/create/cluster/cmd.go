@@ -17,14 +17,12 @@ limitations under the License.
 package cluster
 
 import (
-	"errors"
+	"encoding/json"
 	"fmt"
 	"net"
-	"os"
 	"strings"
 	"time"
 
-	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	clusterdescribe "github.com/openshift/moactl/cmd/describe/cluster"
 	installLogs "github.com/openshift/moactl/cmd/logs/install"
 
@@ -33,15 +31,25 @@ import (
 	awssdk "github.com/aws/aws-sdk-go/aws"
 	v "github.com/openshift/moactl/cmd/validations"
 	"github.com/openshift/moactl/pkg/aws"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
 
+	"github.com/openshift/moactl/pkg/arguments"
+	"github.com/openshift/moactl/pkg/ci"
 	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/defaults"
+	"github.com/openshift/moactl/pkg/deprecation"
+	"github.com/openshift/moactl/pkg/dryrun"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/interactive"
-	"github.com/openshift/moactl/pkg/logging"
+	"github.com/openshift/moactl/pkg/network"
 	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/machinepools"
 	"github.com/openshift/moactl/pkg/ocm/machines"
 	"github.com/openshift/moactl/pkg/ocm/regions"
+	"github.com/openshift/moactl/pkg/ocm/roles"
+	"github.com/openshift/moactl/pkg/ocm/validations"
 	"github.com/openshift/moactl/pkg/ocm/versions"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
 var args struct {
@@ -51,11 +59,44 @@ var args struct {
 	// Simulate creating a cluster
 	dryRun bool
 
+	// Print the topology of the cluster instead of creating it
+	preview bool
+
+	// Print the JSON schema of the options instead of creating a cluster
+	schema bool
+
+	// File containing the definition of the cluster
+	file string
+
 	// Disable SCP checks in the installer
 	disableSCPChecks bool
 
+	// Secret where the access keys of the admin user are kept
+	credentialsSecretARN string
+	temporaryCredentials time.Duration
+
+	// Roles assumed by clusters that use AWS STS
+	sts                 bool
+	accountRolesPrefix  string
+	roleARN             string
+	supportRoleARN      string
+	controlPlaneRoleARN string
+	workerRoleARN       string
+	operatorRolesPrefix string
+
+	// Skip individual preflight checks
+	skipQuotaCheck       bool
+	skipPermissionsCheck bool
+	skipNetworkCheck     bool
+	skipELBRoleCheck     bool
+	skipVersionCheck     bool
+
 	// Basic options
 	private            bool
+	privateLink        bool
+	deleteProtection   bool
+	etcdEncryption     bool
+	kmsKeyARN          string
 	multiAZ            bool
 	expirationDuration time.Duration
 	expirationTime     string
@@ -64,9 +105,14 @@ var args struct {
 	version            string
 	channelGroup       string
 
+	// Compliance options
+	fips                      bool
+	disableWorkloadMonitoring bool
+
 	// Scaling options
 	computeMachineType string
 	computeNodes       int
+	workerDiskSize     string
 
 	// Networking options
 	hostPrefix  int
@@ -74,9 +120,24 @@ var args struct {
 	serviceCIDR net.IPNet
 	podCIDR     net.IPNet
 
+	// Dual-stack networking options
+	dualStack     bool
+	machineCIDRv6 net.IPNet
+	serviceCIDRv6 net.IPNet
+	podCIDRv6     net.IPNet
+
 	// The Subnet IDs to use when installing the cluster.
 	// SubnetIDs should come in pairs; two per availability zone, one private and one public.
 	subnetIDs []string
+
+	// Availability zones of the VPC created by the installer
+	availabilityZones []string
+
+	// Security groups attached to the load balancers in addition to the ones of the installer
+	additionalSecurityGroupIDs []string
+
+	// Additional tags for the AWS resources of the cluster
+	tags []string
 }
 
 var Cmd = &cobra.Command{
@@ -87,31 +148,41 @@ var Cmd = &cobra.Command{
   rosa create cluster --cluster-name=mycluster
 
   # Create a cluster in the us-east-2 region
-  rosa create cluster --cluster-name=mycluster --region=us-east-2`,
-	Run:              run,
-	PersistentPreRun: v.Validations,
+  rosa create cluster --cluster-name=mycluster --region=us-east-2
+
+  # Create a cluster whose API is only reachable over AWS PrivateLink in an existing VPC
+  rosa create cluster --cluster-name=mycluster --private-link --subnet-ids=subnet-1,subnet-2
+
+  # Check the zones, subnets, nodes and endpoints of a multi-AZ cluster before creating it
+  rosa create cluster --cluster-name=mycluster --multi-az --preview
+
+  # Create a cluster that uses AWS STS with the account roles created by 'rosa create account-roles'
+  rosa create cluster --cluster-name=mycluster --sts
+
+  # Create a cluster, its machine pools and identity providers from the definition in a file.
+  # Running it again once the cluster exists adds the missing machine pools and identity providers
+  rosa create cluster --file=mycluster.yaml
+
+  # Print the JSON schema of the options, for tools that generate forms or validate inputs
+  rosa create cluster --schema`,
+	RunE:              run,
+	PersistentPreRunE: preRun,
 }
 
 func init() {
+	roles.Require(Cmd, roles.Create)
+
 	flags := Cmd.Flags()
 	flags.SortFlags = false
 
 	// Basic options
-	flags.StringVarP(
-		&args.clusterName,
-		"name",
-		"n",
-		"",
-		"Name of the cluster. This will be used when generating a sub-domain for your cluster on openshiftapps.com.",
-	)
-	flags.MarkDeprecated("name", "use --cluster-name instead")
-	flags.StringVarP(
+	flags.StringVar(
 		&args.clusterName,
 		"cluster-name",
-		"c",
 		"",
 		"Name of the cluster. This will be used when generating a sub-domain for your cluster on openshiftapps.com.",
 	)
+	deprecation.AddFlag(Cmd, "name", "n", "cluster-name", "0.2.0")
 	flags.BoolVar(
 		&args.multiAZ,
 		"multi-az",
@@ -123,7 +194,8 @@ func init() {
 		"region",
 		"r",
 		"",
-		"AWS region where your worker pool will be located. (overrides the AWS_REGION environment variable)",
+		"AWS region where your worker pool will be located. (overrides the AWS_REGION environment variable). "+
+			"If subnets are given with '--subnet-ids' the default is the region of the subnets.",
 	)
 	flags.StringVar(
 		&args.version,
@@ -135,8 +207,11 @@ func init() {
 		&args.channelGroup,
 		"channel-group",
 		versions.DefaultChannelGroup,
-		"Channel group is the name of the group where this image belongs, for example \"stable\" or \"fast\".",
+		"Channel group of the version: 'stable', 'candidate' or 'nightly'. Candidate and nightly "+
+			"versions preview upcoming releases and aren't supported for production clusters.",
 	)
+	// The only error is that the completion is already registered, which is fine:
+	_ = Cmd.RegisterFlagCompletionFunc("channel-group", versions.CompleteChannelGroup)
 	flags.StringVar(
 		&args.expirationTime,
 		"expiration-time",
@@ -163,9 +238,18 @@ func init() {
 	flags.IntVar(
 		&args.computeNodes,
 		"compute-nodes",
-		2,
-		"Number of worker nodes to provision per zone. Single zone clusters need at least 2 nodes, "+
-			"multizone clusters need at least 3 nodes.",
+		defaults.Builtin().SingleAZ.Default,
+		fmt.Sprintf("Number of worker nodes to provision per zone. Single zone clusters need at least %d "+
+			"nodes, multizone clusters need at least %d nodes.",
+			defaults.Builtin().SingleAZ.Min, defaults.Builtin().MultiAZ.Min),
+	)
+	flags.StringVar(
+		&args.workerDiskSize,
+		"worker-disk-size",
+		"",
+		fmt.Sprintf("Size of the root disk of the compute nodes, for example \"300GiB\" or \"1TiB\". It "+
+			"must be between %d GiB and %d GiB. The default is the size chosen by OpenShift.",
+			defaults.Builtin().WorkerDiskSize.Min, defaults.Builtin().WorkerDiskSize.Max),
 	)
 
 	flags.IPNetVar(
@@ -193,12 +277,84 @@ func init() {
 		"Subnet prefix length to assign to each individual node. For example, if host prefix is set "+
 			"to \"23\", then each node is assigned a /23 subnet out of the given CIDR.",
 	)
+	flags.BoolVar(
+		&args.dualStack,
+		"dual-stack",
+		false,
+		"Give the nodes, pods and services of the cluster IPv6 addresses in addition to the IPv4 ones. "+
+			"Only available when it is enabled for the organization.",
+	)
+	flags.IPNetVar(
+		&args.machineCIDRv6,
+		"machine-cidr-v6",
+		net.IPNet{},
+		"Block of IPv6 addresses of the VPC of a dual-stack cluster. It can only be given with "+
+			"'--subnet-ids', as AWS assigns the IPv6 block of the VPCs created by the installer. "+
+			"The IPv6 blocks of the subnets must be inside it.",
+	)
+	flags.IPNetVar(
+		&args.serviceCIDRv6,
+		"service-cidr-v6",
+		net.IPNet{},
+		fmt.Sprintf("Block of IPv6 addresses for the services of a dual-stack cluster, for example "+
+			"\"%s\".", network.DefaultServiceCIDRv6),
+	)
+	flags.IPNetVar(
+		&args.podCIDRv6,
+		"pod-cidr-v6",
+		net.IPNet{},
+		fmt.Sprintf("Block of IPv6 addresses from which the Pod IPv6 addresses of a dual-stack cluster "+
+			"are allocated, for example \"%s\". Each node gets a /%d.", network.DefaultPodCIDRv6,
+			network.HostPrefixV6),
+	)
 	flags.BoolVar(
 		&args.private,
 		"private",
 		false,
 		"Restrict master API endpoint and application routes to direct, private connectivity.",
 	)
+	flags.BoolVar(
+		&args.privateLink,
+		"private-link",
+		false,
+		"Make the API of the cluster only reachable over AWS PrivateLink. PrivateLink clusters are "+
+			"private and are installed into an existing VPC given with '--subnet-ids'.",
+	)
+	flags.BoolVar(
+		&args.deleteProtection,
+		"enable-delete-protection",
+		false,
+		"Protect the cluster against accidental deletion. Protected clusters can only be deleted "+
+			"with 'rosa delete cluster --force'.",
+	)
+	flags.BoolVar(
+		&args.etcdEncryption,
+		"etcd-encryption",
+		false,
+		"Encrypt the etcd database of the cluster, in addition to the encryption of the volumes, so "+
+			"that the secrets and other resources stored in it are encrypted at rest.",
+	)
+	flags.BoolVar(
+		&args.fips,
+		"fips",
+		false,
+		fmt.Sprintf("Install the cluster in FIPS mode, so that it uses FIPS validated cryptographic "+
+			"libraries. It requires OpenShift %s or newer and enables etcd encryption.", ocm.MinFIPSVersion),
+	)
+	flags.BoolVar(
+		&args.disableWorkloadMonitoring,
+		"disable-workload-monitoring",
+		false,
+		"Disable the monitoring stack for user workloads, so that only the platform components of the "+
+			"cluster are monitored.",
+	)
+	flags.StringVar(
+		&args.kmsKeyARN,
+		"kms-key-arn",
+		"",
+		"ARN of the customer managed KMS key used to encrypt the volumes of the cluster nodes, instead "+
+			"of the default key of the account. The key must be in the region of the cluster.",
+	)
 
 	flags.BoolVar(
 		&args.disableSCPChecks,
@@ -207,20 +363,148 @@ func init() {
 		"Indicates if cloud permission checks are disabled when attempting installation of the cluster.",
 	)
 
+	flags.StringVar(
+		&args.credentialsSecretARN,
+		"credentials-secret-arn",
+		"",
+		fmt.Sprintf("ARN of an AWS Secrets Manager secret where the access keys of the '%s' user are "+
+			"kept. The keys in the secret are reused while they are active, instead of replacing the "+
+			"keys each time a cluster is created. When the keys are replaced the secret is updated.",
+			aws.AdminUserName),
+	)
+
+	flags.DurationVar(
+		&args.temporaryCredentials,
+		"temporary-credentials",
+		0,
+		fmt.Sprintf("Give OCM temporary credentials that expire after the given time, like '4h', "+
+			"instead of access keys of the '%s' user. They are limited to the permissions that the "+
+			"installer needs, and they must last until the cluster is installed. "+
+			"Use 'rosa rotate credentials' to replace them later.", aws.AdminUserName),
+	)
+
+	flags.BoolVar(
+		&args.sts,
+		"sts",
+		false,
+		fmt.Sprintf("Use AWS STS: the cluster assumes IAM roles to get short lived credentials, instead of "+
+			"using the access keys of the '%s' user.", aws.AdminUserName),
+	)
+	flags.StringVar(
+		&args.accountRolesPrefix,
+		"account-roles-prefix",
+		aws.DefaultAccountRolePrefix,
+		"Prefix of the names of the account roles used by a cluster that uses AWS STS.",
+	)
+	flags.StringVar(
+		&args.roleARN,
+		"role-arn",
+		"",
+		"ARN of the installer role, instead of the account role with the prefix.",
+	)
+	flags.StringVar(
+		&args.supportRoleARN,
+		"support-role-arn",
+		"",
+		"ARN of the role used by Red Hat support, instead of the account role with the prefix.",
+	)
+	flags.StringVar(
+		&args.controlPlaneRoleARN,
+		"controlplane-iam-role",
+		"",
+		"ARN of the role of the control plane instances, instead of the account role with the prefix.",
+	)
+	flags.StringVar(
+		&args.workerRoleARN,
+		"worker-iam-role",
+		"",
+		"ARN of the role of the worker instances, instead of the account role with the prefix.",
+	)
+	flags.StringVar(
+		&args.operatorRolesPrefix,
+		"operator-roles-prefix",
+		"",
+		"Prefix of the names of the roles of the operators of the cluster. The default is the name of "+
+			"the cluster.",
+	)
+
+	flags.BoolVar(
+		&args.skipQuotaCheck,
+		"skip-quota-check",
+		false,
+		"Skip verifying that the AWS account has enough quota to create the cluster.",
+	)
+	flags.BoolVar(
+		&args.skipPermissionsCheck,
+		"skip-permissions-check",
+		false,
+		fmt.Sprintf("Skip verifying the SCP policies of the '%s' user by simulating its permissions.", aws.AdminUserName),
+	)
+	flags.BoolVar(
+		&args.skipNetworkCheck,
+		"skip-network-check",
+		false,
+		"Skip verifying that the subnets provided exist in the AWS account, cover the availability zones "+
+			"of the cluster with enough free IP addresses and aren't used by other clusters.",
+	)
+	flags.BoolVar(
+		&args.skipELBRoleCheck,
+		"skip-elb-role-check",
+		false,
+		fmt.Sprintf("Skip verifying that the '%s' service linked role exists, and creating it if it doesn't.",
+			aws.ELBServiceLinkedRoleName),
+	)
+	flags.BoolVar(
+		&args.skipVersionCheck,
+		"skip-version-check",
+		false,
+		"Skip verifying that this version of the tool is still supported for creating clusters.",
+	)
+
 	flags.BoolVar(
 		&args.watch,
 		"watch",
 		false,
 		"Watch cluster installation logs.",
 	)
+	installLogs.AddWatchFlags(flags)
 
 	flags.BoolVar(
 		&args.dryRun,
 		"dry-run",
 		false,
-		"Simulate creating the cluster.",
+		"Simulate creating the cluster, printing the requests that would be sent without changing "+
+			"anything.",
 	)
 
+	flags.BoolVar(
+		&args.preview,
+		"preview",
+		false,
+		"Print a summary of the availability zones, subnets, NAT gateways, nodes and endpoints of the "+
+			"cluster, after checking the options, without creating it.",
+	)
+
+	flags.BoolVar(
+		&args.schema,
+		"schema",
+		false,
+		"Print the JSON schema of the options of this command and exit, without creating a cluster.",
+	)
+
+	flags.StringVarP(
+		&args.file,
+		"file",
+		"f",
+		"",
+		"Path to a YAML or JSON file with the definition of the cluster: the settings that correspond to "+
+			"the flags of this command, like 'name', 'region' and 'network', and the 'machine_pools' and "+
+			"'identity_providers' of the cluster. Flags given in the command line take precedence. If the "+
+			"cluster already exists its missing machine pools and identity providers are created, and the "+
+			"replicas and labels of its machine pools are updated.",
+	)
+	arguments.MarkFlagsMutuallyExclusive(flags, "file", "schema")
+
 	flags.StringSliceVar(
 		&args.subnetIDs,
 		"subnet-ids",
@@ -230,36 +514,160 @@ func init() {
 			"Subnets are comma separated, for example: --subnet-ids=subnet-1,subnet-2."+
 			"Leave empty for installer provisioned subnet IDs.",
 	)
+
+	flags.StringSliceVar(
+		&args.availabilityZones,
+		"availability-zones",
+		nil,
+		"Availability zones of the region where the cluster is installed, one for single-AZ clusters "+
+			"and three for multi-AZ clusters, for example: --availability-zones=us-east-1a,us-east-1b,"+
+			"us-east-1c. When installing into existing subnets the zones are those of the subnets. "+
+			"Leave empty to let the installer choose the zones.",
+	)
+
+	flags.StringSliceVar(
+		&args.additionalSecurityGroupIDs,
+		"additional-security-group-ids",
+		nil,
+		"Security groups attached to the load balancers of the API and the default ingress, in addition "+
+			"to the ones created by the installer, for example: --additional-security-group-ids=sg-1,sg-2. "+
+			"They must be in the VPC of the subnets given with '--subnet-ids'.",
+	)
+
+	flags.StringSliceVar(
+		&args.tags,
+		"tags",
+		nil,
+		"Additional tags for the AWS resources of the cluster, as comma separated 'key=value' pairs, for "+
+			"example: --tags=CostCenter=1234,Team=infra. The tags are checked against the tag policies "+
+			"of the AWS organization before creating the cluster.",
+	)
+
+	// Combinations of flags that are checked before making any API call:
+	arguments.MarkFlagsMutuallyExclusive(flags, "expiration-time", "expiration")
+	arguments.MarkFlagsMutuallyExclusive(flags, "dry-run", "watch")
+	arguments.MarkFlagsMutuallyExclusive(flags, "availability-zones", "subnet-ids")
+	arguments.MarkFlagRequires(flags, "watch-interval", "watch")
+	arguments.MarkFlagRequires(flags, "watch-timeout", "watch")
+	arguments.MarkFlagRequires(flags, "skip-network-check", "subnet-ids")
+	arguments.MarkFlagRequires(flags, "machine-cidr-v6", "subnet-ids")
+	arguments.MarkFlagRequires(flags, "additional-security-group-ids", "subnet-ids")
+	arguments.MarkFlagRequires(flags, "private-link", "subnet-ids")
+	for _, flag := range []string{"machine-cidr-v6", "service-cidr-v6", "pod-cidr-v6"} {
+		arguments.MarkFlagRequires(flags, flag, "dual-stack")
+	}
+	arguments.MarkFlagsMutuallyExclusive(flags, "sts", "credentials-secret-arn", "temporary-credentials")
+	for _, flag := range []string{"account-roles-prefix", "role-arn", "support-role-arn", "controlplane-iam-role",
+		"worker-iam-role", "operator-roles-prefix"} {
+		arguments.MarkFlagRequires(flags, flag, "sts")
+	}
+}
+
+func preRun(cmd *cobra.Command, argv []string) error {
+	// The schema describes the options, so they aren't checked when it is printed:
+	if args.schema {
+		return nil
+	}
+
+	// The settings of the definition are set as flags, so that they are validated in the same way:
+	if args.file != "" {
+		err := applyDefinition(cmd)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := arguments.ValidateFlagGroups(cmd.Flags())
+	if err != nil {
+		return rosaerrors.Wrap(rosaerrors.ExitUsage, err)
+	}
+
+	return v.Validations(cmd, argv)
 }
 
-func run(cmd *cobra.Command, _ []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
+const clusterNameMessage = "Cluster name must consist of no more than 15 lowercase alphanumeric " +
+	"characters or '-', start with a letter, and end with an alphanumeric character."
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
 	var err error
 
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
+	if args.schema {
+		schema := arguments.Schema(arguments.OwnFlags(cmd), "rosa create cluster", cmd.Short, "schema", "file", "help")
+		data, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			return fmt.Errorf("Failed to generate schema: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	// This '--dry-run' flag replaces the one of the parent command, and it also needs to prevent
+	// the changes to AWS resources:
+	if args.dryRun {
+		dryrun.Enable()
+	}
+
+	if args.temporaryCredentials != 0 && (args.temporaryCredentials < aws.MinTemporaryCredentialsDuration ||
+		args.temporaryCredentials > aws.MaxTemporaryCredentialsDuration) {
+		return rosaerrors.Usagef("Duration of temporary credentials must be between %s and %s",
+			aws.MinTemporaryCredentialsDuration, aws.MaxTemporaryCredentialsDuration)
+	}
+	if args.credentialsSecretARN != "" {
+		err = aws.ValidateCredentialsSecretARN(args.credentialsSecretARN)
+		if err != nil {
+			return err
+		}
+	}
+	tags, err := aws.ParseTags(args.tags)
 	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		return err
 	}
-	defer func() {
-		err = ocmConnection.Close()
+
+	// Check the version first, so that users don't answer the questions of the interactive mode
+	// for nothing:
+	if args.skipVersionCheck {
+		reporter.Warnf("Skipping check of the supported versions of the tool")
+	} else {
+		err = checkVersionSkew(r)
 		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
+			return err
 		}
-	}()
-	ocmClient := ocmConnection.ClustersMgmt().V1()
+	}
+
+	// The organization can change the defaults and limits of the cluster settings:
+	orgDefaults, err := r.OCM().LoadDefaults()
+	if err != nil {
+		reporter.Debugf("Failed to load cluster defaults of the organization, using built-in values: %v", err)
+		orgDefaults = defaults.Builtin()
+	}
+	addedTags, err := orgDefaults.ApplyRequiredTags(tags)
+	if err != nil {
+		return err
+	}
+	for _, key := range addedTags {
+		reporter.Infof("Adding tag '%s=%s' required by organization '%s'", key, tags[key],
+			orgDefaults.Organization)
+	}
+	if len(tags) > aws.MaxTags {
+		return rosaerrors.Usagef("Too many tags: at most %d tags can be added, including the ones required by "+
+			"organization '%s'", aws.MaxTags, orgDefaults.Organization)
+	}
 
 	if interactive.Enabled() {
 		reporter.Infof("Interactive mode enabled.\n" +
 			"Any optional fields can be left empty and a default will be selected.")
 	}
 
-	// Get cluster name
+	// Get cluster name. It can also be given with the '--cluster' flag, which is what '-c' selects,
+	// but not with the default cluster, as that is an existing cluster:
 	clusterName := args.clusterName
+	if clusterName == "" {
+		clusterName = clusterprovider.Key()
+	} else if clusterprovider.Key() != "" && clusterprovider.Key() != clusterName {
+		return rosaerrors.Usagef("At most one of '--cluster-name' or '--cluster' may be specified")
+	}
 
 	if clusterName == "" && !interactive.Enabled() {
 		interactive.Enable()
@@ -272,21 +680,49 @@ func run(cmd *cobra.Command, _ []string) {
 			Help:     cmd.Flags().Lookup("cluster-name").Usage,
 			Default:  clusterName,
 			Required: true,
+			Validators: []interactive.Validator{
+				func(answer interface{}) error {
+					if name, ok := answer.(string); ok && !clusterprovider.IsValidClusterName(name) {
+						return fmt.Errorf("%s", clusterNameMessage)
+					}
+					return nil
+				},
+			},
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid cluster name: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid cluster name: %w", err)
 		}
 	}
 	if !clusterprovider.IsValidClusterName(clusterName) {
-		reporter.Errorf("Cluster name must consist" +
-			" of no more than 15 lowercase alphanumeric characters or '-', " +
-			"start with a letter, and end with an alphanumeric character.")
-		os.Exit(1)
+		return rosaerrors.Usagef("%s", clusterNameMessage)
+	}
+
+	// A definition is applied to the cluster again when it already exists:
+	if definition != nil {
+		cluster, err := findDefinedCluster(r, clusterName)
+		if err != nil {
+			return err
+		}
+		if cluster != nil {
+			if args.dryRun {
+				reporter.Infof("Cluster '%s' already exists, its machine pools and identity providers "+
+					"would be reconciled with file '%s'", clusterName, args.file)
+				return nil
+			}
+			reporter.Infof("Cluster '%s' already exists, reconciling its machine pools and identity "+
+				"providers with file '%s'", clusterName, args.file)
+			if !reconcileDefinition(r, cluster) {
+				return fmt.Errorf("Failed to reconcile cluster '%s' with file '%s'", clusterName, args.file)
+			}
+			return nil
+		}
 	}
 
 	// Multi-AZ:
 	multiAZ := args.multiAZ
+	if len(args.availabilityZones) > 1 && !cmd.Flags().Changed("multi-az") {
+		multiAZ = true
+	}
 	if interactive.Enabled() {
 		multiAZ, err = interactive.GetBool(interactive.Input{
 			Question: "Multiple availability zones",
@@ -294,61 +730,96 @@ func run(cmd *cobra.Command, _ []string) {
 			Default:  multiAZ,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid multi-AZ value: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid multi-AZ value: %w", err)
 		}
 	}
 
 	// Get AWS region
 	region, err := aws.GetRegion(args.region)
 	if err != nil {
-		reporter.Errorf("Error getting region: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("Error getting region: %w", err)
 	}
 
-	regionList, regionAZ, err := regions.GetRegionList(ocmClient, multiAZ)
+	regionList, regionAZ, err := regions.GetRegionList(r.OCM(),
+		r.WithAWSRegion(aws.GlobalRegion()).AWSClient(), multiAZ)
 	if err != nil {
-		reporter.Errorf(fmt.Sprintf("%s", err))
-		os.Exit(1)
+		return err
+	}
+
+	// Existing subnets can only be used in their own region, so when the region isn't given
+	// explicitly it is taken from the subnets:
+	if len(args.subnetIDs) > 0 && !cmd.Flags().Changed("region") {
+		reporter.Debugf("Finding region of subnets '%s'", strings.Join(args.subnetIDs, "', '"))
+		subnetsRegion, err := aws.GetSubnetsRegion(r.Logger(), regionList, args.subnetIDs)
+		if err != nil {
+			return err
+		}
+		if subnetsRegion != region {
+			reporter.Infof("Using region '%s' of the subnets", subnetsRegion)
+		}
+		region = subnetsRegion
 	}
 	if interactive.Enabled() {
+		// Only offer the regions allowed by the organization:
+		allowedRegions := orgDefaults.FilterRegions(regionList)
+		if orgDefaults.ValidateRegion(region) != nil && len(allowedRegions) > 0 {
+			region = allowedRegions[0]
+		}
 		region, err = interactive.GetOption(interactive.Input{
 			Question: "AWS region",
 			Help:     cmd.Flags().Lookup("region").Usage,
-			Options:  regionList,
+			Options:  allowedRegions,
 			Default:  region,
 			Required: true,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid AWS region: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid AWS region: %w", err)
 		}
 	}
 
 	if region == "" {
-		reporter.Errorf("Expected a valid AWS region")
-		os.Exit(1)
+		return rosaerrors.Usagef("Expected a valid AWS region")
 	} else {
+		err = orgDefaults.ValidateRegion(region)
+		if err != nil {
+			return err
+		}
 		if supportsMultiAZ, found := regionAZ[region]; found {
 			if !supportsMultiAZ && multiAZ {
-				reporter.Errorf("Region '%s' does not support multiple availability zones", region)
-				os.Exit(1)
+				return rosaerrors.Usagef("Region '%s' does not support multiple availability zones", region)
 			}
 		} else {
-			reporter.Errorf("Region '%s' is not supported for this AWS account", region)
-			os.Exit(1)
+			// The regions of other partitions are never available, explain why:
+			if partition, err := r.AWSClient().GetPartition(); err == nil {
+				err = aws.ValidateRegionPartition(region, partition)
+				if err != nil {
+					return err
+				}
+			}
+			return rosaerrors.Usagef("Region '%s' is not supported for this AWS account", region)
 		}
 	}
 
 	// OpenShift version:
 	version := args.version
 	channelGroup := args.channelGroup
-	versionList, err := getVersionList(ocmClient, channelGroup)
+	if !cmd.Flags().Changed("channel-group") && orgDefaults.ChannelGroup != "" {
+		channelGroup = orgDefaults.ChannelGroup
+		reporter.Infof("Using channel group '%s' from the defaults of organization '%s'", channelGroup,
+			orgDefaults.Organization)
+	}
+	err = versions.ValidateChannelGroup(channelGroup)
 	if err != nil {
-		reporter.Errorf(fmt.Sprintf("%s", err))
-		os.Exit(1)
+		return err
+	}
+	versionList, defaultVersion, err := getVersionList(r.OCM(), channelGroup)
+	if err != nil {
+		return err
 	}
 	if interactive.Enabled() {
+		if version == "" {
+			version = defaultVersion
+		}
 		version, err = interactive.GetOption(interactive.Input{
 			Question: "OpenShift version",
 			Help:     cmd.Flags().Lookup("version").Usage,
@@ -356,25 +827,31 @@ func run(cmd *cobra.Command, _ []string) {
 			Default:  version,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid OpenShift version: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid OpenShift version: %w", err)
 		}
 	}
-	version, err = validateVersion(version, versionList)
+	// The raw version, like '4.10.3', is needed to check the features that require newer versions:
+	rawVersion := version
+	if rawVersion == "" {
+		rawVersion = defaultVersion
+	}
+	version, err = validateVersion(version, versionList, channelGroup)
 	if err != nil {
-		reporter.Errorf("Expected a valid OpenShift version: %s", err)
-		os.Exit(1)
+		return rosaerrors.Usagef("Expected a valid OpenShift version: %w", err)
+	}
+	if version != "" {
+		endOfLifeDates, err := r.OCM().GetEndOfLifeDates(channelGroup)
+		if err != nil {
+			reporter.Debugf("Failed to fetch end of life dates: %v", err)
+		}
+		endOfLife := endOfLifeDates[version]
+		if versions.IsNearEndOfLife(endOfLife) {
+			reporter.Warnf("%s", versions.EndOfLifeWarning(strings.TrimPrefix(version, "openshift-v"), endOfLife))
+		}
 	}
 
 	// Subnet IDs
-	awsClient, err := aws.NewClient().
-		Region(region).
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create awsClient: %s", err)
-		os.Exit(1)
-	}
+	awsClient := r.WithAWSRegion(region).AWSClient()
 
 	subnetIDs := args.subnetIDs
 	subnetsProvided := len(subnetIDs) > 0
@@ -389,8 +866,7 @@ func run(cmd *cobra.Command, _ []string) {
 			Default: useExistingVPC,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid value: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid value: %w", err)
 		}
 	}
 
@@ -398,8 +874,7 @@ func run(cmd *cobra.Command, _ []string) {
 	if useExistingVPC || subnetsProvided {
 		subnets, err := awsClient.GetSubnetIDs()
 		if err != nil {
-			reporter.Errorf("Failed to get the list of subnets: %s", err)
-			os.Exit(1)
+			return fmt.Errorf("Failed to get the list of subnets: %w", err)
 		}
 
 		mapSubnetToAZ := make(map[string]string)
@@ -408,7 +883,7 @@ func run(cmd *cobra.Command, _ []string) {
 		defaultOptions := make([]string, len(subnetIDs))
 
 		// Verify subnets provided exist.
-		if subnetsProvided {
+		if subnetsProvided && !args.skipNetworkCheck {
 			for _, subnetArg := range subnetIDs {
 				verifiedSubnet := false
 				for _, subnet := range subnets {
@@ -417,8 +892,7 @@ func run(cmd *cobra.Command, _ []string) {
 					}
 				}
 				if !verifiedSubnet {
-					reporter.Errorf("Could not find the following subnet provided: %s", subnetArg)
-					os.Exit(1)
+					return rosaerrors.NotFoundf("Could not find the following subnet provided: %s", subnetArg)
 				}
 			}
 		}
@@ -446,8 +920,7 @@ func run(cmd *cobra.Command, _ []string) {
 				Default:  defaultOptions,
 			})
 			if err != nil {
-				reporter.Errorf("Expected valid subnet IDs: %s", err)
-				os.Exit(1)
+				return rosaerrors.Usagef("Expected valid subnet IDs: %w", err)
 			}
 			for i, subnet := range subnetIDs {
 				subnetIDs[i] = parseSubnet(subnet)
@@ -461,16 +934,25 @@ func run(cmd *cobra.Command, _ []string) {
 				mapAZCreated[az] = true
 			}
 		}
+	} else {
+		availabilityZones, err = getAvailabilityZones(cmd, r, awsClient, multiAZ)
+		if err != nil {
+			return err
+		}
+	}
+	reporter.Debugf("Using the following availability zones: %v", availabilityZones)
+	if len(subnetIDs) > 0 && !args.skipNetworkCheck {
+		checkVPCClusters(r, awsClient, subnetIDs)
 	}
-	reporter.Debugf("Found the following availability zones for the subnets provided: %v", availabilityZones)
 
 	// Compute node instance type:
 	computeMachineType := args.computeMachineType
-	computeMachineTypeList, err := machines.GetMachineTypeList(ocmClient)
+	computeMachineTypeList, err := r.OCM().GetMachineTypeList()
 	if err != nil {
-		reporter.Errorf(fmt.Sprintf("%s", err))
-		os.Exit(1)
+		return err
 	}
+	computeMachineTypeList = filterAvailableMachineTypes(r, awsClient, computeMachineTypeList,
+		availabilityZones)
 	if interactive.Enabled() {
 		computeMachineType, err = interactive.GetOption(interactive.Input{
 			Question: "Compute nodes instance type",
@@ -479,44 +961,72 @@ func run(cmd *cobra.Command, _ []string) {
 			Default:  computeMachineType,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid machine type: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid machine type: %w", err)
 		}
 	}
 	computeMachineType, err = machines.ValidateMachineType(computeMachineType, computeMachineTypeList)
 	if err != nil {
-		reporter.Errorf("Expected a valid machine type: %s", err)
-		os.Exit(1)
+		return rosaerrors.Usagef("Expected a valid machine type: %w", err)
 	}
 
 	// Compute nodes:
 	computeNodes := args.computeNodes
 	// Compute node requirements for multi-AZ clusters are higher
-	if multiAZ && !cmd.Flags().Changed("compute-nodes") {
-		computeNodes = 3
+	nodeLimits := orgDefaults.ComputeNodes(multiAZ)
+	if !cmd.Flags().Changed("compute-nodes") {
+		computeNodes = nodeLimits.Default
 	}
 	if interactive.Enabled() {
 		computeNodes, err = interactive.GetInt(interactive.Input{
-			Question: "Compute nodes",
-			Help:     cmd.Flags().Lookup("compute-nodes").Usage,
-			Default:  computeNodes,
+			Question:   "Compute nodes",
+			Help:       cmd.Flags().Lookup("compute-nodes").Usage,
+			Default:    computeNodes,
+			Validators: []interactive.Validator{interactive.IntValidator(nodeLimits.Validate)},
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid number of compute nodes: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid number of compute nodes: %w", err)
+		}
+	}
+	err = nodeLimits.Validate(computeNodes)
+	if err != nil {
+		return err
+	}
+
+	// Root disk size of the compute nodes:
+	workerDiskSize := args.workerDiskSize
+	if interactive.Enabled() {
+		workerDiskSize, err = interactive.GetString(interactive.Input{
+			Question: "Worker disk size",
+			Help:     cmd.Flags().Lookup("worker-disk-size").Usage,
+			Default:  workerDiskSize,
+			Validators: []interactive.Validator{
+				machinepools.DiskSizeValidator(orgDefaults.WorkerDiskSize.Validate),
+			},
+		})
+		if err != nil {
+			return rosaerrors.Usagef("Expected a valid worker disk size: %w", err)
+		}
+	}
+	var diskSize int
+	if workerDiskSize != "" {
+		diskSize, err = machinepools.ParseDiskSize(workerDiskSize)
+		if err == nil {
+			err = orgDefaults.WorkerDiskSize.Validate(diskSize)
+		}
+		if err != nil {
+			return err
 		}
 	}
 
 	// Validate all remaining flags:
 	expiration, err := validateExpiration()
 	if err != nil {
-		reporter.Errorf(fmt.Sprintf("%s", err))
-		os.Exit(1)
+		return err
 	}
 	var dMachinecidr *net.IPNet
 	var dPodcidr *net.IPNet
 	var dServicecidr *net.IPNet
-	dMachinecidr, dPodcidr, dServicecidr, dhostPrefix := ocm.GetDefaultClusterFlavors(ocmClient)
+	dMachinecidr, dPodcidr, dServicecidr, dhostPrefix := r.OCM().GetDefaultClusterFlavors()
 
 	// Machine CIDR:
 	machineCIDR := args.machineCIDR
@@ -525,56 +1035,118 @@ func run(cmd *cobra.Command, _ []string) {
 			Question: "Machine CIDR",
 			Help:     cmd.Flags().Lookup("machine-cidr").Usage,
 			Default:  *dMachinecidr,
+			Validators: []interactive.Validator{
+				validations.CIDRValidator("machine"),
+			},
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid CIDR value: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid CIDR value: %w", err)
 		}
 	}
 
 	// Service CIDR:
 	serviceCIDR := args.serviceCIDR
 	if interactive.Enabled() {
+		dServicecidr = freeDefault(dServicecidr, network.FreeServiceCIDR, &machineCIDR)
 		serviceCIDR, err = interactive.GetIPNet(interactive.Input{
 			Question: "Service CIDR",
 			Help:     cmd.Flags().Lookup("service-cidr").Usage,
 			Default:  *dServicecidr,
+			Validators: []interactive.Validator{
+				validations.CIDRValidator("service",
+					validations.Block{Name: "machine", CIDR: &machineCIDR},
+				),
+			},
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid CIDR value: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid CIDR value: %w", err)
 		}
 	}
 	// Pod CIDR:
 	podCIDR := args.podCIDR
 	if interactive.Enabled() {
+		dPodcidr = freeDefault(dPodcidr, network.FreePodCIDR, &machineCIDR, &serviceCIDR)
 		podCIDR, err = interactive.GetIPNet(interactive.Input{
 			Question: "Pod CIDR",
 			Help:     cmd.Flags().Lookup("pod-cidr").Usage,
 			Default:  *dPodcidr,
+			Validators: []interactive.Validator{
+				validations.CIDRValidator("pod",
+					validations.Block{Name: "machine", CIDR: &machineCIDR},
+					validations.Block{Name: "service", CIDR: &serviceCIDR},
+				),
+			},
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid CIDR value: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid CIDR value: %w", err)
 		}
 	}
 
 	// Host prefix:
 	hostPrefix := args.hostPrefix
+	clusterNodes := validations.ClusterNodes(computeNodes, multiAZ)
 	if interactive.Enabled() {
 		hostPrefix, err = interactive.GetInt(interactive.Input{
 			Question: "Host prefix",
 			Help:     cmd.Flags().Lookup("host-prefix").Usage,
 			Default:  dhostPrefix,
+			Validators: []interactive.Validator{
+				interactive.IntValidator(func(value int) error {
+					return validations.ValidateHostPrefix(value, ipNetOr(podCIDR, dPodcidr), clusterNodes)
+				}),
+			},
+		})
+		if err != nil {
+			return rosaerrors.Usagef("Expected a valid host prefix value: %w", err)
+		}
+	}
+
+	// Check the network settings, using the defaults for the ones that weren't given, as the
+	// server will use them:
+	effectiveHostPrefix := hostPrefix
+	if effectiveHostPrefix == 0 {
+		effectiveHostPrefix = dhostPrefix
+	}
+	err = validations.ValidateNetwork(
+		ipNetOr(machineCIDR, dMachinecidr),
+		ipNetOr(serviceCIDR, dServicecidr),
+		ipNetOr(podCIDR, dPodcidr),
+		effectiveHostPrefix,
+		clusterNodes,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Dual-stack networking:
+	dualStack := args.dualStack
+	if dualStack && !orgDefaults.DualStack {
+		return rosaerrors.Authf("Dual-stack networking isn't enabled for organization '%s'", orgDefaults.Organization)
+	}
+	if interactive.Enabled() && orgDefaults.DualStack {
+		dualStack, err = interactive.GetBool(interactive.Input{
+			Question: "Dual-stack networking",
+			Help:     cmd.Flags().Lookup("dual-stack").Usage,
+			Default:  dualStack,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid host prefix value: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid dual-stack value: %w", err)
+		}
+	}
+	var ipv6Layout *network.IPv6Layout
+	if dualStack {
+		ipv6Layout, err = getIPv6Layout(cmd, subnetIDs)
+		if err != nil {
+			return err
 		}
 	}
 
 	// Cluster privacy:
-	private := args.private
+	if args.privateLink && cmd.Flags().Changed("private") && !args.private {
+		return rosaerrors.Usagef("PrivateLink clusters are always private, option '--private-link' can't be used " +
+			"with '--private=false'")
+	}
+	private := args.private || args.privateLink
 	if interactive.Enabled() {
 		private, err = interactive.GetBool(interactive.Input{
 			Question: "Private cluster",
@@ -582,8 +1154,175 @@ func run(cmd *cobra.Command, _ []string) {
 			Default:  private,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid private value: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid private value: %w", err)
+		}
+	}
+	privateLink := args.privateLink
+	if interactive.Enabled() && private && len(subnetIDs) > 0 {
+		privateLink, err = interactive.GetBool(interactive.Input{
+			Question: "PrivateLink cluster",
+			Help:     cmd.Flags().Lookup("private-link").Usage,
+			Default:  privateLink,
+		})
+		if err != nil {
+			return rosaerrors.Usagef("Expected a valid PrivateLink value: %w", err)
+		}
+	}
+	if privateLink && !private {
+		return rosaerrors.Usagef("PrivateLink clusters must be private")
+	}
+
+	// FIPS mode:
+	fips := args.fips
+	if interactive.Enabled() {
+		fips, err = interactive.GetBool(interactive.Input{
+			Question: "FIPS mode",
+			Help:     cmd.Flags().Lookup("fips").Usage,
+			Default:  fips,
+		})
+		if err != nil {
+			return rosaerrors.Usagef("Expected a valid FIPS value: %w", err)
+		}
+	}
+	if fips && rawVersion != "" {
+		err = ocm.ValidateFIPSVersion(rawVersion)
+		if err != nil {
+			return err
+		}
+	}
+	if fips {
+		if cmd.Flags().Changed("etcd-encryption") && !args.etcdEncryption {
+			return rosaerrors.Usagef("Clusters in FIPS mode always use etcd encryption, option '--fips' can't " +
+				"be used with '--etcd-encryption=false'")
+		}
+	}
+
+	// Encryption, always enabled in FIPS mode:
+	etcdEncryption := args.etcdEncryption || fips
+	if interactive.Enabled() && !fips {
+		etcdEncryption, err = interactive.GetBool(interactive.Input{
+			Question: "Etcd encryption",
+			Help:     cmd.Flags().Lookup("etcd-encryption").Usage,
+			Default:  etcdEncryption,
+		})
+		if err != nil {
+			return rosaerrors.Usagef("Expected a valid etcd encryption value: %w", err)
+		}
+	}
+	kmsKeyARN := args.kmsKeyARN
+	if interactive.Enabled() {
+		kmsKeyARN, err = interactive.GetString(interactive.Input{
+			Question: "KMS key ARN",
+			Help:     cmd.Flags().Lookup("kms-key-arn").Usage,
+			Default:  kmsKeyARN,
+		})
+		if err != nil {
+			return rosaerrors.Usagef("Expected a valid KMS key ARN: %w", err)
+		}
+	}
+	if kmsKeyARN != "" {
+		err = aws.ValidateKMSKeyARN(kmsKeyARN, region)
+		if err != nil {
+			return err
+		}
+	}
+
+	// User workload monitoring:
+	disableWorkloadMonitoring := args.disableWorkloadMonitoring
+	if interactive.Enabled() {
+		disableWorkloadMonitoring, err = interactive.GetBool(interactive.Input{
+			Question: "Disable workload monitoring",
+			Help:     cmd.Flags().Lookup("disable-workload-monitoring").Usage,
+			Default:  disableWorkloadMonitoring,
+		})
+		if err != nil {
+			return rosaerrors.Usagef("Expected a valid disable workload monitoring value: %w", err)
+		}
+	}
+
+	// Delete protection:
+	deleteProtection := args.deleteProtection
+	if interactive.Enabled() {
+		deleteProtection, err = interactive.GetBool(interactive.Input{
+			Question: "Enable delete protection",
+			Help:     cmd.Flags().Lookup("enable-delete-protection").Usage,
+			Default:  deleteProtection,
+		})
+		if err != nil {
+			return rosaerrors.Usagef("Expected a valid delete protection value: %w", err)
+		}
+	}
+
+	// Preflight checks:
+	r.CheckAWSIdentity(15 * time.Minute)
+	sts, err := getSTS(r, awsClient, clusterName)
+	if err != nil {
+		return err
+	}
+	if args.skipQuotaCheck {
+		reporter.Warnf("Skipping AWS quota check")
+	} else {
+		reporter.Infof("Validating AWS quota...")
+		_, err = awsClient.ValidateQuota()
+		if err != nil {
+			return rosaerrors.Quotaf("Insufficient AWS quotas: %w", err)
+		}
+	}
+	if args.skipPermissionsCheck {
+		reporter.Warnf("Skipping SCP policies check for user '%s'", aws.AdminUserName)
+	} else if sts == nil {
+		reporter.Infof("Validating SCP policies for '%s'...", aws.AdminUserName)
+		target := aws.AdminUserName
+		isValid, err := awsClient.ValidateSCP(&target)
+		if err != nil {
+			return rosaerrors.Authf("Failed to verify permissions for user '%s': %w", target, err)
+		}
+		if !isValid {
+			return rosaerrors.Authf("User '%s' doesn't have the permissions required by the SCP policies",
+				target)
+		}
+	}
+	if len(subnetIDs) > 0 && !args.skipNetworkCheck {
+		err = checkSubnets(r, awsClient, subnetIDs, multiAZ, private)
+		if err != nil {
+			return err
+		}
+		if privateLink {
+			err = checkPrivateLinkVPC(r, awsClient, subnetIDs)
+			if err != nil {
+				return err
+			}
+		}
+		if ipv6Layout != nil {
+			err = checkIPv6Subnets(r, awsClient, subnetIDs, ipv6Layout.MachineCIDR)
+			if err != nil {
+				return err
+			}
+		}
+		if len(args.additionalSecurityGroupIDs) > 0 {
+			err = checkSecurityGroups(r, awsClient, args.additionalSecurityGroupIDs, subnetIDs, private,
+				map[string]*net.IPNet{"service": &serviceCIDR, "pod": &podCIDR})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if args.skipELBRoleCheck {
+		reporter.Warnf("Skipping check of service linked role '%s'", aws.ELBServiceLinkedRoleName)
+	} else {
+		err = checkELBServiceLinkedRole(r, awsClient)
+		if err != nil {
+			return err
+		}
+	}
+	if len(tags) > 0 {
+		reporter.Infof("Validating tags against the tag policies of the AWS organization...")
+		warnings, err := awsClient.ValidateTags(tags)
+		for _, warning := range warnings {
+			reporter.Warnf("%s", warning)
+		}
+		if err != nil {
+			return err
 		}
 	}
 
@@ -596,45 +1335,89 @@ func run(cmd *cobra.Command, _ []string) {
 		Expiration:         expiration,
 		ComputeMachineType: computeMachineType,
 		ComputeNodes:       computeNodes,
+		WorkerDiskSize:     diskSize,
 		MachineCIDR:        machineCIDR,
 		ServiceCIDR:        serviceCIDR,
 		PodCIDR:            podCIDR,
 		HostPrefix:         hostPrefix,
+		DualStack:          dualStack,
 		Private:            &private,
+		PrivateLink:        privateLink,
+		DeleteProtection:   deleteProtection,
+		EtcdEncryption:     etcdEncryption,
+		FIPS:               fips,
+		KMSKeyARN:          kmsKeyARN,
 		DryRun:             &args.dryRun,
 		DisableSCPChecks:   &args.disableSCPChecks,
 		AvailabilityZones:  availabilityZones,
 		SubnetIds:          subnetIDs,
+
+		CredentialsSecretARN:       args.credentialsSecretARN,
+		TemporaryCredentials:       args.temporaryCredentials,
+		AdditionalSecurityGroupIDs: args.additionalSecurityGroupIDs,
+		DisableWorkloadMonitoring:  disableWorkloadMonitoring,
+		Tags:                       tags,
+		STS:                        sts,
+	}
+	if ipv6Layout != nil {
+		if ipv6Layout.MachineCIDR != nil {
+			clusterConfig.MachineCIDRv6 = *ipv6Layout.MachineCIDR
+		}
+		clusterConfig.ServiceCIDRv6 = *ipv6Layout.ServiceCIDR
+		clusterConfig.PodCIDRv6 = *ipv6Layout.PodCIDR
+	}
+
+	if args.preview {
+		err = printPreview(r, awsClient, clusterConfig)
+		if err != nil {
+			return err
+		}
+		reporter.Infof("Run without the '--preview' flag to create the cluster.")
+		return nil
 	}
 
 	reporter.Infof("Creating cluster '%s'", clusterName)
 	reporter.Infof("To view a list of clusters and their status, run 'rosa list clusters'")
 
-	cluster, err := clusterprovider.CreateCluster(ocmClient.Clusters(), clusterConfig)
+	cluster, err := clusterprovider.CreateCluster(r.OCM(), awsClient, clusterConfig)
 	if err != nil {
 		if args.dryRun {
-			reporter.Errorf("Creating cluster '%s' should fail: %s", clusterName, err)
-		} else {
-			reporter.Errorf("Failed to create cluster: %s", err)
+			return fmt.Errorf("Creating cluster '%s' should fail: %w", clusterName, err)
 		}
-		os.Exit(1)
+		return fmt.Errorf("Failed to create cluster: %w", err)
 	}
 
 	if args.dryRun {
 		reporter.Infof(
 			"Creating cluster '%s' should succeed. Run without the '--dry-run' flag to create the cluster.",
 			clusterName)
-		os.Exit(0)
+		return nil
 	}
 
-	reporter.Infof("Cluster '%s' has been created.", clusterName)
+	reporter.Progressf(rprtr.CodeClusterCreated, rprtr.Fields{"cluster": cluster.ID()},
+		"Cluster '%s' has been created.", clusterName)
+	ci.RecordResource(&ci.Resource{Kind: "cluster", ID: cluster.ID(), Name: clusterName})
+	if definition != nil && !reconcileDefinition(r, cluster) {
+		reporter.Warnf("Run 'rosa create cluster --file=%s' again once the cluster is ready to create "+
+			"the rest of its machine pools and identity providers", args.file)
+	}
+	if sts != nil {
+		reporter.Infof(
+			"The installation starts once the operator roles and the OIDC provider of the cluster exist. "+
+				"To create them run 'rosa create oidc-provider -c %s' and then "+
+				"'rosa create operator-roles -c %s'.",
+			clusterName, clusterName)
+	}
 	reporter.Infof(
 		"Once the cluster is installed you will need to add an Identity Provider " +
 			"before you can login into the cluster. See 'rosa create idp --help' " +
 			"for more information.")
 
 	if args.watch {
-		installLogs.Cmd.Run(cmd, []string{cluster.ID()})
+		err = installLogs.Cmd.RunE(cmd, []string{cluster.ID()})
+		if err != nil {
+			return err
+		}
 	} else {
 		reporter.Infof(
 			"To determine when your cluster is Ready, run 'rosa describe cluster -c %s'.",
@@ -646,11 +1429,11 @@ func run(cmd *cobra.Command, _ []string) {
 		)
 	}
 
-	clusterdescribe.Cmd.Run(cmd, []string{cluster.ID()})
+	return clusterdescribe.Cmd.RunE(cmd, []string{cluster.ID()})
 }
 
 // Validate OpenShift versions
-func validateVersion(version string, versionList []string) (string, error) {
+func validateVersion(version string, versionList []string, channelGroup string) (string, error) {
 	if version != "" {
 		// Check and set the cluster version
 		hasVersion := false
@@ -665,22 +1448,24 @@ func validateVersion(version string, versionList []string) (string, error) {
 			return version, err
 		}
 
-		version = "openshift-v" + version
+		version = versions.CreateVersionID(version, channelGroup)
 	}
 
 	return version, nil
 }
 
-func getVersionList(client *cmv1.Client, channelGroup string) (versionList []string, err error) {
-	versions, err := versions.GetVersions(client, channelGroup)
+func getVersionList(client ocm.Client, channelGroup string) (versionList []string,
+	defaultVersion string, err error) {
+	versionItems, err := client.GetVersions(channelGroup)
 	if err != nil {
 		err = fmt.Errorf("Failed to retrieve versions: %s", err)
 		return
 	}
 
-	for _, v := range versions {
-		versionList = append(versionList, strings.Replace(v.ID(), "openshift-v", "", 1))
+	for _, v := range versionItems {
+		versionList = append(versionList, versions.GetRawID(v))
 	}
+	defaultVersion = versions.GetDefaultVersion(versionItems)
 
 	if len(versionList) == 0 {
 		err = fmt.Errorf("Could not find versions for the provided channel-group: '%s'", channelGroup)
@@ -691,12 +1476,6 @@ func getVersionList(client *cmv1.Client, channelGroup string) (versionList []str
 }
 
 func validateExpiration() (expiration time.Time, err error) {
-	// Validate options
-	if len(args.expirationTime) > 0 && args.expirationDuration != 0 {
-		err = errors.New("At most one of 'expiration-time' or 'expiration' may be specified")
-		return
-	}
-
 	// Parse the expiration options
 	if len(args.expirationTime) > 0 {
 		t, err := parseRFC3339(args.expirationTime)
@@ -723,6 +1502,34 @@ func parseRFC3339(s string) (time.Time, error) {
 	return time.Parse(time.RFC3339, s)
 }
 
+// freeDefault returns the given default block if it doesn't overlap with the blocks already chosen,
+// otherwise it proposes one that doesn't.
+func freeDefault(block *net.IPNet, propose func(...*net.IPNet) (*net.IPNet, error),
+	used ...*net.IPNet) *net.IPNet {
+	overlaps := block == nil
+	for _, cidr := range used {
+		if !overlaps && cidr.IP != nil && network.Overlaps(block, cidr) {
+			overlaps = true
+		}
+	}
+	if !overlaps {
+		return block
+	}
+	proposed, err := propose(used...)
+	if err != nil {
+		return block
+	}
+	return proposed
+}
+
+// ipNetOr returns the given block if it is set, otherwise the given default, which may be nil.
+func ipNetOr(cidr net.IPNet, dflt *net.IPNet) *net.IPNet {
+	if cidr.IP != nil {
+		return &cidr
+	}
+	return dflt
+}
+
 const subnetTemplate = "%s (%s)"
 
 // Creates a subnet options using a predefined template.
@@ -0,0 +1,61 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// checkELBServiceLinkedRole makes sure that the service linked role of Elastic Load Balancing
+// exists, creating it if needed, as otherwise the installation fails when the installer creates
+// the load balancers of the API. In dry run and preview modes the role isn't created.
+func checkELBServiceLinkedRole(r *runtime.Runtime, awsClient aws.Client) error {
+	reporter := r.Reporter()
+
+	reporter.Infof("Validating service linked role '%s'...", aws.ELBServiceLinkedRoleName)
+	exists, err := awsClient.HasELBServiceLinkedRole()
+	if err != nil {
+		return fmt.Errorf("Failed to check service linked role '%s': %w", aws.ELBServiceLinkedRoleName, err)
+	}
+	if exists {
+		return nil
+	}
+
+	if args.dryRun || args.preview {
+		reporter.Warnf(
+			"Service linked role '%s' doesn't exist and will be created. To create it yourself run:\n\n"+
+				"   %s\n",
+			aws.ELBServiceLinkedRoleName, aws.ELBServiceLinkedRoleCommand,
+		)
+		return nil
+	}
+	reporter.Infof("Creating service linked role '%s'", aws.ELBServiceLinkedRoleName)
+	err = awsClient.CreateELBServiceLinkedRole()
+	if err != nil {
+		return fmt.Errorf(
+			"Failed to create service linked role '%s': %v\n"+
+				"Ask an administrator of the AWS account to create it running:\n\n"+
+				"   %s\n",
+			aws.ELBServiceLinkedRoleName, err, aws.ELBServiceLinkedRoleCommand,
+		)
+	}
+
+	return nil
+}
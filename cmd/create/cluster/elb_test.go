@@ -0,0 +1,48 @@
+package cluster
+
+import (
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws/mocks"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var _ = Describe("ELB service linked role", func() {
+	var (
+		ctrl      *gomock.Controller
+		awsClient *mocks.MockClient
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		awsClient = mocks.NewMockClient(ctrl)
+		awsClient.EXPECT().HasELBServiceLinkedRole().Return(false, nil)
+	})
+
+	AfterEach(func() {
+		args.dryRun = false
+		args.preview = false
+		ctrl.Finish()
+	})
+
+	It("Creates the role when it doesn't exist", func() {
+		awsClient.EXPECT().CreateELBServiceLinkedRole().Return(nil)
+		err := checkELBServiceLinkedRole(runtime.New(), awsClient)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Doesn't create the role in preview mode", func() {
+		// The mock fails the test if the role is created:
+		args.preview = true
+		err := checkELBServiceLinkedRole(runtime.New(), awsClient)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Doesn't create the role in dry run mode", func() {
+		args.dryRun = true
+		err := checkELBServiceLinkedRole(runtime.New(), awsClient)
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
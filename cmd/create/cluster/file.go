@@ -0,0 +1,228 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"reflect"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/openshift/moactl/cmd/create/idp"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/machinepools"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// definition is the cluster definition loaded from the file given with '--file', if any.
+var definition *clusterprovider.Definition
+
+// applyDefinition loads the cluster definition from the file and sets the flags that correspond
+// to its settings, so that they are validated like the ones given in the command line. The flags
+// given in the command line take precedence over the file.
+func applyDefinition(cmd *cobra.Command) error {
+	reporter := runtime.FromContext(cmd.Context()).Reporter()
+
+	var err error
+	definition, err = clusterprovider.LoadDefinition(args.file)
+	if err != nil {
+		return err
+	}
+	flags := cmd.Flags()
+	for name, value := range definition.Flags() {
+		if flags.Changed(name) {
+			reporter.Debugf("Using '--%s' from the command line instead of the value in file '%s'",
+				name, args.file)
+			continue
+		}
+		err = flags.Set(name, value)
+		if err != nil {
+			return rosaerrors.Usagef("Invalid value '%s' for '--%s' in file '%s': %w", value, name, args.file, err)
+		}
+	}
+
+	return nil
+}
+
+// findDefinedCluster returns the existing cluster with the given name, or nil if the cluster of
+// the definition hasn't been created yet.
+func findDefinedCluster(r *runtime.Runtime, clusterName string) (*cmv1.Cluster, error) {
+	reporter := r.Reporter()
+
+	reporter.Debugf("Checking if cluster '%s' already exists", clusterName)
+	clusters, err := r.OCM().SearchClusters(r.Creator().ARN,
+		fmt.Sprintf("name = '%s'", clusterName))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to check if cluster '%s' exists: %w", clusterName, err)
+	}
+	if len(clusters) == 0 {
+		return nil, nil
+	}
+	return clusters[0], nil
+}
+
+// reconcileDefinition makes sure that the cluster has the machine pools and the identity providers
+// of the definition. Missing ones are created, and the replicas and labels of existing machine
+// pools are updated. Machine pools and identity providers that aren't in the definition are kept.
+// It returns false if some of them couldn't be reconciled, for example because the cluster isn't
+// ready yet, so that the user can run the command again later.
+func reconcileDefinition(r *runtime.Runtime, cluster *cmv1.Cluster) bool {
+	reporter := r.Reporter()
+	ok := true
+
+	if len(definition.MachinePools) > 0 {
+		reporter.Debugf("Loading machine pools of cluster '%s'", cluster.Name())
+		existing, err := r.OCM().GetMachinePools(cluster.ID())
+		if err != nil {
+			reporter.Warnf("Failed to get machine pools of cluster '%s': %v", cluster.Name(), err)
+			return false
+		}
+		pools := map[string]*cmv1.MachinePool{}
+		for _, pool := range existing {
+			pools[pool.ID()] = pool
+		}
+		for _, pool := range definition.MachinePools {
+			err = reconcileMachinePool(r, cluster, pool, pools[pool.Name])
+			if err != nil {
+				reporter.Warnf("Failed to reconcile machine pool '%s' of cluster '%s': %v", pool.Name,
+					cluster.Name(), err)
+				ok = false
+			}
+		}
+	}
+
+	if len(definition.IdentityProviders) > 0 {
+		reporter.Debugf("Loading identity providers of cluster '%s'", cluster.Name())
+		existing, err := r.OCM().GetIdentityProviders(cluster.ID())
+		if err != nil {
+			reporter.Warnf("Failed to get identity providers of cluster '%s': %v", cluster.Name(), err)
+			return false
+		}
+		names := map[string]bool{}
+		for _, item := range existing {
+			names[item.Name()] = true
+		}
+		for _, config := range definition.IdentityProviders {
+			name := config["name"].(string)
+			// Secrets can't be read back, so existing identity providers are left unchanged:
+			if names[name] {
+				reporter.Debugf("Identity provider '%s' already exists", name)
+				continue
+			}
+			err = addIdentityProvider(r, cluster, config)
+			if err != nil {
+				reporter.Warnf("Failed to add identity provider '%s' to cluster '%s': %v", name,
+					cluster.Name(), err)
+				ok = false
+				continue
+			}
+			reporter.Infof("Identity provider '%s' has been created on cluster '%s'", name, cluster.Name())
+		}
+	}
+
+	return ok
+}
+
+func reconcileMachinePool(r *runtime.Runtime, cluster *cmv1.Cluster, pool *clusterprovider.MachinePoolDefinition,
+	current *cmv1.MachinePool) error {
+	reporter := r.Reporter()
+
+	if pool.Autoscaling() {
+		err := machinepools.ValidateAutoscaling(*pool.MinReplicas, *pool.MaxReplicas, cluster.MultiAZ())
+		if err != nil {
+			return err
+		}
+	}
+
+	if current == nil {
+		builder := cmv1.NewMachinePool().
+			ID(pool.Name).
+			InstanceType(pool.InstanceType).
+			Labels(pool.Labels)
+		if pool.Autoscaling() {
+			builder = builder.Autoscaling(machinepools.NewAutoscaling(*pool.MinReplicas, *pool.MaxReplicas))
+		} else {
+			builder = builder.Replicas(*pool.Replicas)
+		}
+		machinePool, err := builder.Build()
+		if err != nil {
+			return err
+		}
+		err = r.OCM().AddMachinePool(cluster.ID(), machinePool, nil, "", 0)
+		if err != nil {
+			return err
+		}
+		reporter.Infof("Machine pool '%s' has been created on cluster '%s'", pool.Name, cluster.Name())
+		return nil
+	}
+
+	if current.InstanceType() != pool.InstanceType {
+		reporter.Warnf("Machine pool '%s' of cluster '%s' uses instance type '%s' instead of '%s', "+
+			"use 'rosa edit machinepool --instance-type' to replace its nodes", pool.Name, cluster.Name(),
+			current.InstanceType(), pool.InstanceType)
+	}
+
+	// Only the replicas and the labels can be changed:
+	builder := cmv1.NewMachinePool().ID(pool.Name)
+	changed := false
+	if pool.Autoscaling() {
+		autoscaling, ok := current.GetAutoscaling()
+		if !ok || autoscaling.MinReplicas() != *pool.MinReplicas || autoscaling.MaxReplicas() != *pool.MaxReplicas {
+			builder = builder.Autoscaling(machinepools.NewAutoscaling(*pool.MinReplicas, *pool.MaxReplicas))
+			changed = true
+		}
+	} else if _, ok := current.GetAutoscaling(); ok || current.Replicas() != *pool.Replicas {
+		builder = builder.Replicas(*pool.Replicas)
+		changed = true
+	}
+	if pool.Labels != nil && !reflect.DeepEqual(current.Labels(), pool.Labels) {
+		builder = builder.Labels(pool.Labels)
+		changed = true
+	}
+	if !changed {
+		reporter.Debugf("Machine pool '%s' is up to date", pool.Name)
+		return nil
+	}
+	machinePool, err := builder.Build()
+	if err != nil {
+		return err
+	}
+	err = r.OCM().UpdateMachinePool(cluster.ID(), pool.Name, machinePool)
+	if err != nil {
+		return err
+	}
+	reporter.Infof("Machine pool '%s' has been updated on cluster '%s'", pool.Name, cluster.Name())
+	return nil
+}
+
+// addIdentityProvider creates the identity provider with the given configuration, in the format of
+// 'rosa describe idp -o yaml'. The secret is read like in 'rosa create idp --file'.
+func addIdentityProvider(r *runtime.Runtime, cluster *cmv1.Cluster, config map[string]interface{}) error {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	provider, err := ocm.ImportIdentityProvider(data, idp.GetSecret)
+	if err != nil {
+		return err
+	}
+	return r.OCM().AddIdentityProvider(cluster.ID(), provider)
+}
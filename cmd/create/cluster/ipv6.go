@@ -0,0 +1,108 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"net"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/network"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// getIPv6Layout returns the IPv6 blocks of a dual-stack cluster, asking for them in interactive
+// mode. The machine block can only be chosen for clusters installed into an existing VPC.
+func getIPv6Layout(cmd *cobra.Command, subnetIDs []string) (*network.IPv6Layout, error) {
+	var err error
+
+	machineCIDR := args.machineCIDRv6
+	if interactive.Enabled() && len(subnetIDs) > 0 {
+		machineCIDR, err = interactive.GetIPNet(interactive.Input{
+			Question: "IPv6 machine CIDR",
+			Help:     cmd.Flags().Lookup("machine-cidr-v6").Usage,
+			Default:  machineCIDR,
+		})
+		if err != nil {
+			return nil, rosaerrors.Usagef("Expected a valid CIDR value: %w", err)
+		}
+	}
+	serviceCIDR := args.serviceCIDRv6
+	if interactive.Enabled() {
+		serviceCIDR, err = interactive.GetIPNet(interactive.Input{
+			Question: "IPv6 service CIDR",
+			Help:     cmd.Flags().Lookup("service-cidr-v6").Usage,
+			Default:  ipNetOrDefault(serviceCIDR, network.DefaultServiceCIDRv6),
+		})
+		if err != nil {
+			return nil, rosaerrors.Usagef("Expected a valid CIDR value: %w", err)
+		}
+	}
+	podCIDR := args.podCIDRv6
+	if interactive.Enabled() {
+		podCIDR, err = interactive.GetIPNet(interactive.Input{
+			Question: "IPv6 pod CIDR",
+			Help:     cmd.Flags().Lookup("pod-cidr-v6").Usage,
+			Default:  ipNetOrDefault(podCIDR, network.DefaultPodCIDRv6),
+		})
+		if err != nil {
+			return nil, rosaerrors.Usagef("Expected a valid CIDR value: %w", err)
+		}
+	}
+
+	layout, err := network.ProposeIPv6Layout(ipNetOrNil(machineCIDR), ipNetOrNil(serviceCIDR),
+		ipNetOrNil(podCIDR))
+	if err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+// checkIPv6Subnets makes sure that the given subnets have the IPv6 blocks that the nodes of a
+// dual-stack cluster get their addresses from.
+func checkIPv6Subnets(r *runtime.Runtime, awsClient aws.Client, subnetIDs []string, machineCIDR *net.IPNet) error {
+	reporter := r.Reporter()
+
+	reporter.Infof("Validating IPv6 blocks of subnets...")
+	err := awsClient.ValidateIPv6Subnets(subnetIDs, machineCIDR)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func ipNetOrNil(cidr net.IPNet) *net.IPNet {
+	if cidr.IP == nil {
+		return nil
+	}
+	return &cidr
+}
+
+func ipNetOrDefault(cidr net.IPNet, dflt string) net.IPNet {
+	if cidr.IP != nil {
+		return cidr
+	}
+	_, parsed, err := net.ParseCIDR(dflt)
+	if err != nil {
+		return cidr
+	}
+	return *parsed
+}
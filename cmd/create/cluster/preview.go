@@ -0,0 +1,153 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openshift/moactl/pkg/aws"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// printPreview prints a summary of the topology of the cluster that would be created with the
+// given spec: the availability zones, the subnets and NAT gateways of each zone, how the nodes are
+// spread across them, and the privacy of the endpoints. The subnets of an existing VPC are loaded
+// from AWS, the rest is derived from the options.
+func printPreview(r *runtime.Runtime, awsClient aws.Client, spec clusterprovider.Spec) error {
+	plan := &aws.ClusterPlan{
+		MultiAZ:            spec.MultiAZ,
+		ComputeMachineType: spec.ComputeMachineType,
+		ComputeNodes:       spec.ComputeNodes,
+	}
+	nodes := plan.NodesPerZone()
+
+	// Zones are only known when installing into an existing VPC, otherwise OCM chooses them:
+	zones := spec.AvailabilityZones
+	if len(zones) == 0 {
+		zones = make([]string, len(nodes))
+		for i := range zones {
+			zones[i] = fmt.Sprintf("Zone %d (chosen at install time)", i+1)
+		}
+	}
+
+	network := &aws.Network{}
+	if len(spec.SubnetIds) > 0 {
+		var err error
+		network, err = awsClient.GetClusterNetwork("", spec.SubnetIds)
+		if err != nil {
+			return fmt.Errorf("Failed to get AWS network resources of subnets '%s': %w",
+				strings.Join(spec.SubnetIds, "', '"), err)
+		}
+	}
+
+	private := spec.Private != nil && *spec.Private
+	vpc := "Created by the installer"
+	if len(network.VPCs) > 0 {
+		vpcs := make([]string, len(network.VPCs))
+		for i, item := range network.VPCs {
+			vpcs[i] = fmt.Sprintf("%s (%s)", item.ID, item.CIDR)
+		}
+		vpc = "Existing " + strings.Join(vpcs, ", ")
+	}
+	topology := "Single-AZ"
+	if spec.MultiAZ {
+		topology = "Multi-AZ"
+	}
+	fmt.Printf(""+
+		"Cluster:            %s\n"+
+		"Region:             %s\n"+
+		"Topology:           %s\n"+
+		"VPC:                %s\n"+
+		"Machine CIDR:       %s\n"+
+		"API endpoint:       %s\n"+
+		"Default ingress:    %s\n"+
+		"PrivateLink:        %s\n",
+		spec.Name,
+		spec.Region,
+		topology,
+		vpc,
+		spec.MachineCIDR.String(),
+		privacy(private),
+		privacy(private),
+		yesNo(spec.PrivateLink),
+	)
+
+	for i, zone := range zones {
+		fmt.Printf("\n%s\n", zone)
+
+		var subnets []string
+		var gateways []string
+		if len(spec.SubnetIds) == 0 {
+			// The installer creates a public and a private subnet in each zone, with a NAT gateway
+			// in the public subnet for the egress of the nodes:
+			subnets = []string{"public (created)", "private (created)"}
+			gateways = []string{"1 (created)"}
+		} else {
+			for _, subnet := range network.Subnets {
+				if subnet.AvailabilityZone != zone {
+					continue
+				}
+				kind := "private"
+				if subnet.Public {
+					kind = "public"
+				}
+				subnets = append(subnets, fmt.Sprintf("%s %s (%s)", kind, subnet.ID, subnet.CIDR))
+				for _, gateway := range network.NATGateways {
+					if gateway.SubnetID == subnet.ID {
+						gateways = append(gateways, gateway.ID)
+					}
+				}
+			}
+			if len(gateways) == 0 {
+				gateways = []string{"None in the given subnets"}
+			}
+		}
+
+		// Nodes are spread round robin, so zones beyond the planned ones get none:
+		zoneNodes := &aws.ZoneNodes{}
+		if i < len(nodes) {
+			zoneNodes = nodes[i]
+		}
+		fmt.Printf(""+
+			"  Subnets:          %s\n"+
+			"  NAT gateways:     %s\n"+
+			"  Nodes:            %d control plane, %d infra, %d compute (%s)\n",
+			strings.Join(subnets, ", "),
+			strings.Join(gateways, ", "),
+			zoneNodes.ControlPlane, zoneNodes.Infra, zoneNodes.Compute, spec.ComputeMachineType,
+		)
+	}
+	fmt.Println()
+
+	return nil
+}
+
+func privacy(private bool) string {
+	if private {
+		return "Private"
+	}
+	return "Public"
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "Yes"
+	}
+	return "No"
+}
@@ -0,0 +1,59 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/openshift/moactl/pkg/info"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// checkVersionSkew makes sure that the service still supports this version of the tool, as older
+// versions may send cluster descriptions that the service accepts but that result in clusters
+// that don't work. Failures to get the minimum version are only warnings, so that a problem of
+// the service doesn't prevent creating clusters.
+func checkVersionSkew(r *runtime.Runtime) error {
+	reporter := r.Reporter()
+
+	minimum, err := r.OCM().GetMinimumCLIVersion()
+	if err != nil {
+		reporter.Warnf("Failed to get the minimum supported version of the tool: %v", err)
+		return nil
+	}
+	if minimum == "" {
+		reporter.Debugf("The service doesn't report a minimum supported version of the tool")
+		return nil
+	}
+	reporter.Debugf("Minimum supported version of the tool is '%s'", minimum)
+	comparison, err := info.CompareVersions(info.Version, minimum)
+	if err != nil {
+		reporter.Warnf("Failed to check the minimum supported version of the tool: %v", err)
+		return nil
+	}
+	if comparison < 0 {
+		return fmt.Errorf(
+			"Version '%s' of the tool is no longer supported for creating clusters, the minimum "+
+				"version is '%s'. Download the latest version from "+
+				"https://github.com/openshift/moactl/releases, or use '--skip-version-check' to "+
+				"create the cluster anyway",
+			info.Version, minimum,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+
+	"github.com/openshift/moactl/pkg/aws"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// getSTS returns the roles that the cluster will assume, or nil if it doesn't use AWS STS. Roles
+// that aren't given explicitly are the account roles with the selected prefix. The operator roles
+// don't exist yet, they are created once the cluster has an OIDC provider.
+func getSTS(r *runtime.Runtime, awsClient aws.Client, clusterName string) (*clusterprovider.STS, error) {
+	if !args.sts {
+		return nil, nil
+	}
+	reporter := r.Reporter()
+
+	operatorRolesPrefix := args.operatorRolesPrefix
+	if operatorRolesPrefix == "" {
+		operatorRolesPrefix = clusterName
+	}
+	for _, prefix := range []string{args.accountRolesPrefix, operatorRolesPrefix} {
+		err := aws.ValidateAccountRolePrefix(prefix)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	reporter.Debugf("Loading account roles with prefix '%s'", args.accountRolesPrefix)
+	roles, err := awsClient.GetAccountRoles(args.accountRolesPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get account roles: %w", err)
+	}
+	sts := &clusterprovider.STS{}
+	roleARNs := []struct {
+		given    string
+		roleType string
+		arn      *string
+	}{
+		{args.roleARN, aws.InstallerRoleType, &sts.RoleARN},
+		{args.supportRoleARN, aws.SupportRoleType, &sts.SupportRoleARN},
+		{args.controlPlaneRoleARN, aws.ControlPlaneRoleType, &sts.ControlPlaneRoleARN},
+		{args.workerRoleARN, aws.WorkerRoleType, &sts.WorkerRoleARN},
+	}
+	for _, roleARN := range roleARNs {
+		if roleARN.given != "" {
+			*roleARN.arn = roleARN.given
+			continue
+		}
+		role := aws.FindAccountRole(roles, roleARN.roleType)
+		if role == nil {
+			return nil, rosaerrors.NotFoundf("There is no %s role with prefix '%s'. To create the "+
+				"account roles run 'rosa create account-roles --prefix=%s'",
+				roleARN.roleType, args.accountRolesPrefix, args.accountRolesPrefix)
+		}
+		if !role.UpToDate() {
+			reporter.Warnf("The policies of role '%s' don't have the version %s that this version of the "+
+				"tool expects. To update them run 'rosa create account-roles --prefix=%s'",
+				role.Name, aws.AccountRoleVersion, args.accountRolesPrefix)
+		}
+		*roleARN.arn = role.ARN
+	}
+
+	// Red Hat assumes the installer and support roles, so they have to trust it:
+	reporter.Infof("Validating account roles...")
+	trusted := map[string]string{
+		sts.RoleARN:        aws.InstallerPrincipal,
+		sts.SupportRoleARN: aws.SupportPrincipal,
+	}
+	for role, principal := range trusted {
+		err = awsClient.ValidateRoleARN(role, principal)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The operator roles are in the same partition and account, and have the same path, as the
+	// installer role:
+	parsed, err := arn.Parse(sts.RoleARN)
+	if err != nil {
+		return nil, rosaerrors.Usagef("Role ARN '%s' isn't valid: %w", sts.RoleARN, err)
+	}
+	path, err := aws.IAMPath(sts.RoleARN)
+	if err != nil {
+		return nil, rosaerrors.Usagef("Role ARN '%s' isn't valid: %w", sts.RoleARN, err)
+	}
+	for _, operator := range aws.OperatorRoles {
+		sts.OperatorRoles = append(sts.OperatorRoles, &clusterprovider.OperatorIAMRole{
+			Namespace: operator.Namespace,
+			Name:      operator.Name,
+			RoleARN:   operator.RoleARN(parsed.Partition, parsed.AccountID, path, operatorRolesPrefix),
+		})
+	}
+	return sts, nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"net"
+	"strings"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// checkSubnets makes sure that the given subnets can be used for the cluster, so that it fails
+// before it is created instead of during the installation.
+func checkSubnets(r *runtime.Runtime, awsClient aws.Client, subnetIDs []string, multiAZ bool, private bool) error {
+	reporter := r.Reporter()
+
+	reporter.Infof("Validating subnets...")
+	err := awsClient.ValidateSubnets(subnetIDs, multiAZ, private)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// checkPrivateLinkVPC makes sure that the VPC of the given subnets can be used for a PrivateLink
+// cluster.
+func checkPrivateLinkVPC(r *runtime.Runtime, awsClient aws.Client, subnetIDs []string) error {
+	reporter := r.Reporter()
+
+	reporter.Infof("Validating VPC for PrivateLink...")
+	err := awsClient.ValidatePrivateLinkVPC(subnetIDs)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// checkSecurityGroups makes sure that the additional security groups can be attached to the load
+// balancers of the cluster, and that their rules don't conflict with the network of the cluster.
+func checkSecurityGroups(r *runtime.Runtime, awsClient aws.Client, groupIDs []string, subnetIDs []string,
+	private bool, clusterCIDRs map[string]*net.IPNet) error {
+	reporter := r.Reporter()
+
+	reporter.Infof("Validating security groups...")
+	err := awsClient.ValidateSecurityGroups(groupIDs, subnetIDs, private, clusterCIDRs)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// checkVPCClusters warns about other clusters that already use the VPC of the given subnets. They
+// don't prevent the installation, but subnets owned by another cluster are deleted together with
+// that cluster, and the load balancers of the services of each cluster may be created in subnets
+// meant for the other one.
+func checkVPCClusters(r *runtime.Runtime, awsClient aws.Client, subnetIDs []string) {
+	reporter := r.Reporter()
+
+	reporter.Debugf("Checking for other clusters using the VPC of subnets %v", subnetIDs)
+	clusters, err := awsClient.FindVPCClusters(subnetIDs)
+	if err != nil {
+		reporter.Warnf("Failed to check for other clusters using the VPC: %v", err)
+		return
+	}
+
+	for _, cluster := range clusters {
+		if len(cluster.OwnedSubnets) > 0 {
+			reporter.Warnf(
+				"Subnets %s were created by cluster '%s' and will be deleted when that cluster is "+
+					"deleted. Use subnets that aren't owned by another cluster",
+				strings.Join(cluster.OwnedSubnets, ", "), cluster.InfraID,
+			)
+		}
+		if len(cluster.SharedSubnets) > 0 {
+			reporter.Warnf(
+				"Subnets %s are already used by cluster '%s'. Make sure that the subnets have enough "+
+					"free IP addresses for both clusters",
+				strings.Join(cluster.SharedSubnets, ", "), cluster.InfraID,
+			)
+		}
+		if len(cluster.LoadBalancers) > 0 {
+			reporter.Warnf(
+				"Cluster '%s' has load balancers in the same VPC: %s. Tag the public subnets with "+
+					"'kubernetes.io/role/elb' and the private subnets with 'kubernetes.io/role/internal-elb' "+
+					"so that the load balancers of each cluster are created in the right subnets",
+				cluster.InfraID, strings.Join(cluster.LoadBalancers, ", "),
+			)
+		}
+	}
+}
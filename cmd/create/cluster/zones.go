@@ -0,0 +1,74 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// multiAZZones is the number of availability zones of multi-AZ clusters.
+const multiAZZones = 3
+
+// getAvailabilityZones returns the availability zones selected for a cluster whose VPC is created
+// by the installer, asking for them in interactive mode. It fails if the number of zones
+// doesn't match the multi-AZ setting or if they aren't zones of the region of the AWS client. An
+// empty result means that the zones are chosen when the cluster is installed.
+func getAvailabilityZones(cmd *cobra.Command, r *runtime.Runtime, awsClient aws.Client,
+	multiAZ bool) ([]string, error) {
+
+	zones := args.availabilityZones
+	if interactive.Enabled() {
+		regionZones, err := awsClient.GetAvailabilityZones()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get the availability zones of region '%s': %w",
+				awsClient.GetRegion(), err)
+		}
+		zones, err = interactive.GetMultipleOptions(interactive.Input{
+			Question: "Availability zones",
+			Help:     cmd.Flags().Lookup("availability-zones").Usage,
+			Options:  regionZones,
+			Default:  zones,
+		})
+		if err != nil {
+			return nil, rosaerrors.Usagef("Expected valid availability zones: %w", err)
+		}
+	}
+	if len(zones) == 0 {
+		return nil, nil
+	}
+
+	if multiAZ && len(zones) != multiAZZones {
+		return nil, rosaerrors.Usagef("Multi-AZ clusters need exactly %d availability zones, but %d were given",
+			multiAZZones, len(zones))
+	}
+	if !multiAZ && len(zones) != 1 {
+		return nil, rosaerrors.Usagef("Single-AZ clusters need exactly one availability zone, but %d were given, "+
+			"use '--multi-az' to spread the cluster over %d zones", len(zones), multiAZZones)
+	}
+	err := awsClient.ValidateAvailabilityZones(zones)
+	if err != nil {
+		return nil, err
+	}
+	return zones, nil
+}
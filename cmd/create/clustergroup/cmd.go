@@ -0,0 +1,98 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustergroup
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm/roles"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var args struct {
+	search string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "cluster-group NAME",
+	Aliases: []string{"clustergroup"},
+	Short:   "Create a group of clusters",
+	Long: "Create a named group of clusters, selected with an OCM search expression. The group is " +
+		"stored in the configuration file and can be used with '--cluster-group' in any command " +
+		"that accepts '--cluster'.",
+	Example: `  # Create a group with the production clusters in Europe
+  rosa create cluster-group prod-eu --search "name like 'prod-%' and region.id like 'eu-%'"
+
+  # List the users of all the clusters of the group
+  rosa list users --cluster-group prod-eu`,
+	RunE: run,
+}
+
+func init() {
+	roles.Require(Cmd, roles.None)
+
+	flags := Cmd.Flags()
+
+	flags.StringVar(
+		&args.search,
+		"search",
+		"",
+		"OCM search expression that selects the clusters of the group (required).",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	if len(argv) != 1 {
+		return rosaerrors.Usagef("Expected exactly one command line parameter containing the name of the group")
+	}
+	name := argv[0]
+	if !cluster.IsValidGroupName(name) {
+		return rosaerrors.Usagef("Group name '%s' isn't valid: it must contain only lowercase letters, "+
+			"digits and dashes", name)
+	}
+	if args.search == "" {
+		return rosaerrors.Usagef("Option '--search' is mandatory")
+	}
+
+	// Check that the expression is valid before saving it:
+	clusters, err := r.OCM().SearchClusters(r.Creator().ARN, args.search)
+	if err != nil {
+		return fmt.Errorf("Failed to search clusters: %w", err)
+	}
+
+	groups, err := cluster.GetGroups()
+	if err != nil {
+		return fmt.Errorf("Failed to load cluster groups: %w", err)
+	}
+	if _, ok := groups[name]; ok {
+		return rosaerrors.Conflictf("Cluster group '%s' already exists", name)
+	}
+	err = cluster.SaveGroup(name, args.search)
+	if err != nil {
+		return fmt.Errorf("Failed to save cluster group '%s': %w", name, err)
+	}
+	reporter.Infof("Created cluster group '%s', currently matching %d clusters", name, len(clusters))
+
+	return nil
+}
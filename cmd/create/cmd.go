@@ -19,13 +19,23 @@ package create
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/moactl/cmd/create/accountroles"
 	"github.com/openshift/moactl/cmd/create/addon"
 	"github.com/openshift/moactl/cmd/create/admin"
 	"github.com/openshift/moactl/cmd/create/cluster"
+	"github.com/openshift/moactl/cmd/create/clustergroup"
 	"github.com/openshift/moactl/cmd/create/idp"
 	"github.com/openshift/moactl/cmd/create/ingress"
+	"github.com/openshift/moactl/cmd/create/kubeletconfig"
 	"github.com/openshift/moactl/cmd/create/machinepool"
+	"github.com/openshift/moactl/cmd/create/oidcconfig"
+	"github.com/openshift/moactl/cmd/create/oidcprovider"
+	"github.com/openshift/moactl/cmd/create/operatorroles"
+	"github.com/openshift/moactl/cmd/create/servicelog"
+	"github.com/openshift/moactl/cmd/create/tuningconfig"
+	"github.com/openshift/moactl/pkg/dryrun"
 	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/ocm/roles"
 )
 
 var Cmd = &cobra.Command{
@@ -36,13 +46,24 @@ var Cmd = &cobra.Command{
 }
 
 func init() {
+	roles.Require(Cmd, roles.Update)
+
+	Cmd.AddCommand(accountroles.Cmd)
 	Cmd.AddCommand(addon.Cmd)
 	Cmd.AddCommand(admin.Cmd)
 	Cmd.AddCommand(cluster.Cmd)
+	Cmd.AddCommand(clustergroup.Cmd)
 	Cmd.AddCommand(idp.Cmd)
 	Cmd.AddCommand(ingress.Cmd)
+	Cmd.AddCommand(kubeletconfig.Cmd)
 	Cmd.AddCommand(machinepool.Cmd)
+	Cmd.AddCommand(oidcconfig.Cmd)
+	Cmd.AddCommand(oidcprovider.Cmd)
+	Cmd.AddCommand(operatorroles.Cmd)
+	Cmd.AddCommand(servicelog.Cmd)
+	Cmd.AddCommand(tuningconfig.Cmd)
 
 	flags := Cmd.PersistentFlags()
 	interactive.AddFlag(flags)
+	dryrun.AddFlag(flags)
 }
@@ -18,7 +18,6 @@ package idp
 
 import (
 	"fmt"
-	"os"
 	"regexp"
 	"strconv"
 	"strings"
@@ -26,12 +25,14 @@ import (
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/arguments"
+	"github.com/openshift/moactl/pkg/ci"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/interactive"
-	"github.com/openshift/moactl/pkg/logging"
 	"github.com/openshift/moactl/pkg/ocm"
 	"github.com/openshift/moactl/pkg/reporter"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
 type IdentityProvider interface {
@@ -39,10 +40,9 @@ type IdentityProvider interface {
 }
 
 var args struct {
-	clusterKey string
-
 	idpType string
 	idpName string
+	idpFile string
 
 	clientID      string
 	clientSecret  string
@@ -60,6 +60,11 @@ var args struct {
 	// Google
 	googleHostedDomain string
 
+	// htpasswd
+	htpasswdFile     string
+	htpasswdUsername string
+	htpasswdPassword string
+
 	// LDAP
 	ldapURL          string
 	ldapInsecure     bool
@@ -69,6 +74,7 @@ var args struct {
 	ldapUsernames    string
 	ldapDisplayNames string
 	ldapEmails       string
+	ldapTestBind     bool
 
 	// OpenID
 	openidIssuerURL string
@@ -76,9 +82,10 @@ var args struct {
 	openidName      string
 	openidUsername  string
 	openidScopes    string
+	openidSkipCheck bool
 }
 
-var validIdps []string = []string{"github", "gitlab", "google", "ldap", "openid"}
+var validIdps []string = []string{"github", "gitlab", "google", "htpasswd", "ldap", "openid"}
 
 var idRE = regexp.MustCompile(`(?i)^[0-9a-z]+([-_][0-9a-z]+)*$`)
 
@@ -89,23 +96,23 @@ var Cmd = &cobra.Command{
 	Example: `  # Add a GitHub identity provider to a cluster named "mycluster"
   rosa create idp --type=github --cluster=mycluster
 
+  # Add an htpasswd identity provider with the users of a file generated with 'htpasswd -B'
+  rosa create idp --type=htpasswd --from-file=users.htpasswd --cluster=mycluster
+
+  # Add an identity provider exported from another cluster with 'rosa describe idp -o yaml'
+  rosa create idp --file=idp.yaml --cluster=mycluster
+
   # Add an identity provider following interactive prompts
   rosa create idp --cluster=mycluster --interactive`,
-	Run: run,
+	PreRunE: arguments.CheckFlagGroups,
+	RunE:    run,
 }
 
 func init() {
 	flags := Cmd.Flags()
 	flags.SortFlags = false
 
-	flags.StringVarP(
-		&args.clusterKey,
-		"cluster",
-		"c",
-		"",
-		"Name or ID of the cluster to add the IdP to (required).",
-	)
-	Cmd.MarkFlagRequired("cluster")
+	clusterprovider.UseKey(Cmd)
 
 	flags.StringVarP(
 		&args.idpType,
@@ -118,8 +125,18 @@ func init() {
 		&args.idpName,
 		"name",
 		"",
-		"Name for the identity provider.\n",
+		"Name for the identity provider.",
+	)
+	flags.StringVarP(
+		&args.idpFile,
+		"file",
+		"f",
+		"",
+		"Path to a file with the configuration of the identity provider, as generated by "+
+			"'rosa describe idp -o yaml'. Secrets are read from the ROSA_IDP_CLIENT_SECRET, "+
+			"ROSA_IDP_BIND_PASSWORD or ROSA_IDP_PASSWORD environment variables, or prompted.\n",
 	)
+	arguments.MarkFlagsMutuallyExclusive(flags, "file", "type")
 
 	flags.StringVar(
 		&args.mappingMethod,
@@ -139,6 +156,7 @@ func init() {
 		"",
 		"Client Secret from the registered application.",
 	)
+	arguments.AddSecretFileFlag(flags, "client-secret")
 	flags.StringVar(
 		&args.caPath,
 		"ca",
@@ -183,6 +201,30 @@ func init() {
 		"Google: Restrict users to a Google Apps domain.\n",
 	)
 
+	// htpasswd
+	flags.StringVar(
+		&args.htpasswdFile,
+		"from-file",
+		"",
+		"htpasswd: Path to a file with the users to import, generated with 'htpasswd -B'.",
+	)
+	flags.StringVar(
+		&args.htpasswdUsername,
+		"username",
+		"",
+		"htpasswd: Username of the only user, if no file is given.",
+	)
+	flags.StringVar(
+		&args.htpasswdPassword,
+		"password",
+		"",
+		"htpasswd: Password of the only user, if no file is given.\n",
+	)
+	arguments.AddSecretFileFlag(flags, "password")
+	arguments.MarkFlagsMutuallyExclusive(flags, "from-file", "username")
+	arguments.MarkFlagsMutuallyExclusive(flags, "from-file", "password")
+	arguments.MarkFlagsMutuallyExclusive(flags, "from-file", "password-file")
+
 	// LDAP
 	flags.StringVar(
 		&args.ldapURL,
@@ -208,6 +250,7 @@ func init() {
 		"",
 		"LDAP: Password to bind with during the search phase.",
 	)
+	arguments.AddSecretFileFlag(flags, "bind-password")
 	flags.StringVar(
 		&args.ldapIDs,
 		"id-attributes",
@@ -230,7 +273,14 @@ func init() {
 		&args.ldapEmails,
 		"email-attributes",
 		"",
-		"LDAP: The list of attributes whose values should be used as the email address.\n",
+		"LDAP: The list of attributes whose values should be used as the email address.",
+	)
+	flags.BoolVar(
+		&args.ldapTestBind,
+		"test-bind",
+		false,
+		"LDAP: Before creating the identity provider, check from this machine that the bind DN and "+
+			"password can be used to search the base DN of the URL.\n",
 	)
 
 	// OpenID
@@ -263,70 +313,44 @@ func init() {
 		&args.openidScopes,
 		"extra-scopes",
 		"",
-		"OpenID: List of scopes to request, in addition to the 'openid' scope, during the authorization token request.\n",
+		"OpenID: List of scopes to request, in addition to the 'openid' scope, during the authorization token request.",
+	)
+	flags.BoolVar(
+		&args.openidSkipCheck,
+		"skip-issuer-check",
+		false,
+		"OpenID: Skip fetching and validating the discovery document of the issuer, for example when "+
+			"the issuer isn't reachable from this machine.\n",
 	)
 }
 
-func run(cmd *cobra.Command, _ []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
-
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
-	clusterKey := args.clusterKey
-	if !ocm.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
-	}
-
-	// Create the AWS client:
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
-	}
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
 
-	awsCreator, err := awsClient.GetCreator()
+	err := arguments.ResolveSecrets(cmd.Flags())
 	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		return err
 	}
 
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
+	clusterKey, err := clusterprovider.GetKey(r, nil)
 	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		return err
 	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
-
-	// Get the client for the OCM collection of clusters:
-	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
 
 	// Try to find the cluster:
 	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
 	if err != nil {
-		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
 	}
 
 	if cluster.State() != cmv1.ClusterStateReady {
-		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
-		os.Exit(1)
+		return rosaerrors.Conflictf("Cluster '%s' is not yet ready", clusterKey)
+	}
+
+	if args.idpFile != "" {
+		return createIdpFromFile(r, cluster, args.idpFile)
 	}
 
 	if interactive.Enabled() {
@@ -347,13 +371,11 @@ func run(cmd *cobra.Command, _ []string) {
 			Default:  idpType,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid IdP type: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid IdP type: %w", err)
 		}
 	}
 	if idpType == "" {
-		reporter.Errorf("Expected a valid IDP type. Options are: %s", strings.Join(validIdps, ","))
-		os.Exit(1)
+		return rosaerrors.Usagef("Expected a valid IDP type. Options are: %s", strings.Join(validIdps, ","))
 	}
 
 	if idpType != "" {
@@ -364,8 +386,7 @@ func run(cmd *cobra.Command, _ []string) {
 			}
 		}
 		if !isValidIdp {
-			reporter.Errorf("Expected a valid IDP type. Options are %s", validIdps)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid IDP type. Options are %s", validIdps)
 		}
 	}
 
@@ -373,13 +394,15 @@ func run(cmd *cobra.Command, _ []string) {
 
 	// Auto-generate a name if none provided
 	if !cmd.Flags().Changed("name") {
-		idps := getIdps(reporter, clustersCollection, cluster)
+		idps, err := getIdps(reporter, r.OCM(), cluster)
+		if err != nil {
+			return err
+		}
 		idpName = GenerateIdpName(idpType, idps)
 	} else {
 		isValidIdpName := idRE.MatchString(idpName)
 		if !isValidIdpName {
-			reporter.Errorf("Invalid identifier '%s' for 'name'", idpName)
-			os.Exit(1)
+			return rosaerrors.Usagef("Invalid identifier '%s' for 'name'", idpName)
 		}
 	}
 	if interactive.Enabled() {
@@ -390,9 +413,20 @@ func run(cmd *cobra.Command, _ []string) {
 			Required: true,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid name for the identity provider: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid name for the identity provider: %w", err)
+		}
+	}
+
+	// The users of htpasswd identity providers can't be created with the SDK, so they are
+	// created separately:
+	if idpType == "htpasswd" {
+		reporter.Infof("Configuring IDP for cluster '%s'", clusterKey)
+		err = createHtpasswdIdp(cmd, r, cluster, idpName)
+		if err != nil {
+			return fmt.Errorf("Failed to add IDP to cluster '%s': %w", clusterKey, err)
 		}
+		printCreated(r, cluster, idpName)
+		return nil
 	}
 
 	var idpBuilder cmv1.IdentityProviderBuilder
@@ -409,30 +443,29 @@ func run(cmd *cobra.Command, _ []string) {
 		idpBuilder, err = buildOpenidIdp(cmd, cluster, idpName)
 	}
 	if err != nil {
-		reporter.Errorf("Failed to create IDP for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to create IDP for cluster '%s': %w", clusterKey, err)
 	}
 
 	reporter.Infof("Configuring IDP for cluster '%s'", clusterKey)
 
 	idp, err := idpBuilder.Build()
 	if err != nil {
-		reporter.Errorf("Failed to create IDP for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to create IDP for cluster '%s': %w", clusterKey, err)
 	}
 
-	res, err := clustersCollection.Cluster(cluster.ID()).
-		IdentityProviders().
-		Add().
-		Body(idp).
-		Send()
+	err = r.OCM().AddIdentityProvider(cluster.ID(), idp)
 	if err != nil {
-		reporter.Debugf(err.Error())
-		reporter.Errorf("Failed to add IDP to cluster '%s': %s", clusterKey, res.Error().Reason())
-		os.Exit(1)
+		return fmt.Errorf("Failed to add IDP to cluster '%s': %w", clusterKey, err)
 	}
 
-	reporter.Infof(
+	printCreated(r, cluster, idpName)
+
+	return nil
+}
+
+func printCreated(r *runtime.Runtime, cluster *cmv1.Cluster, idpName string) {
+	ci.RecordResource(&ci.Resource{Kind: "identity-provider", Cluster: cluster.ID(), Name: idpName})
+	r.Reporter().Infof(
 		"Identity Provider '%s' has been created.\n"+
 			"   It will take up to 1 minute for this configuration to be enabled.\n"+
 			"   To add cluster administrators, see 'rosa create user --help'.\n"+
@@ -470,26 +503,28 @@ func getMappingMethod(cmd *cobra.Command, mappingMethod string) (string, error)
 		mappingMethod, err = interactive.GetOption(interactive.Input{
 			Question: "Mapping method",
 			Help:     usage,
-			Options:  []string{"add", "claim", "generate", "lookup"},
+			Options:  ocm.IdentityProviderMappingMethods,
 			Default:  mappingMethod,
 			Required: true,
 		})
+		if err != nil {
+			return mappingMethod, err
+		}
 	}
-	return mappingMethod, err
+	return mappingMethod, ocm.ValidateMappingMethod(mappingMethod)
 }
 
-func getIdps(reporter *reporter.Object, clusters *cmv1.ClustersClient, cluster *cmv1.Cluster) []IdentityProvider {
+func getIdps(reporter *reporter.Object, client ocm.Client, cluster *cmv1.Cluster) ([]IdentityProvider, error) {
 	// Load any existing IDPs for this cluster
 	reporter.Debugf("Loading identity providers for cluster '%s'", cluster.ID())
 
-	ocmIdps, err := ocm.GetIdentityProviders(clusters, cluster.ID())
+	ocmIdps, err := client.GetIdentityProviders(cluster.ID())
 	if err != nil {
-		reporter.Errorf("Failed to get identity providers for cluster '%s': %v", cluster.ID(), err)
-		os.Exit(1)
+		return nil, fmt.Errorf("Failed to get identity providers for cluster '%s': %w", cluster.ID(), err)
 	}
 	idps := []IdentityProvider{}
 	for _, idp := range ocmIdps {
 		idps = append(idps, idp)
 	}
-	return idps
+	return idps, nil
 }
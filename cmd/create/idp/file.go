@@ -0,0 +1,95 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package idp
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// Environment variables that contain the secrets of the identity providers created from a file:
+var secretEnvVars = map[string]string{
+	"client_secret": "ROSA_IDP_CLIENT_SECRET",
+	"bind_password": "ROSA_IDP_BIND_PASSWORD",
+	"password":      "ROSA_IDP_PASSWORD",
+}
+
+func createIdpFromFile(r *runtime.Runtime, cluster *cmv1.Cluster, path string) error {
+	reporter := r.Reporter()
+
+	// #nosec G304
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Failed to read file '%s': %w", path, err)
+	}
+	idp, err := ocm.ImportIdentityProvider(data, GetSecret)
+	if err != nil {
+		return fmt.Errorf("Failed to load identity provider from file '%s': %w", path, err)
+	}
+
+	// The name in the file can be overridden, for example to avoid conflicts:
+	idpName := idp.Name()
+	if args.idpName != "" {
+		if !idRE.MatchString(args.idpName) {
+			return rosaerrors.Usagef("Invalid identifier '%s' for 'name'", args.idpName)
+		}
+		idpName = args.idpName
+		idp, err = cmv1.NewIdentityProvider().Copy(idp).Name(idpName).Build()
+		if err != nil {
+			return fmt.Errorf("Failed to create IDP for cluster '%s': %w", cluster.Name(), err)
+		}
+	}
+	if idpName == "" {
+		return rosaerrors.Usagef("File '%s' doesn't contain the name of the identity provider", path)
+	}
+
+	reporter.Infof("Configuring IDP for cluster '%s'", cluster.Name())
+	err = r.OCM().AddIdentityProvider(cluster.ID(), idp)
+	if err != nil {
+		return fmt.Errorf("Failed to add IDP to cluster '%s': %w", cluster.Name(), err)
+	}
+
+	printCreated(r, cluster, idpName)
+
+	return nil
+}
+
+// GetSecret reads the secret of an identity provider imported from a file from the environment,
+// or asks for it.
+func GetSecret(provider string, field string) (string, error) {
+	envVar := secretEnvVars[field]
+	if value := os.Getenv(envVar); value != "" {
+		return value, nil
+	}
+	if !interactive.IsTerminal() {
+		return "", fmt.Errorf("The '%s' of the %s identity provider is required: set the %s "+
+			"environment variable", field, provider, envVar)
+	}
+	return interactive.GetPassword(interactive.Input{
+		Question: fmt.Sprintf("Value of '%s'", field),
+		Help:     fmt.Sprintf("The %s identity provider requires a '%s'.", provider, field),
+		Required: true,
+	})
+}
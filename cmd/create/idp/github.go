@@ -20,13 +20,18 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"net/url"
+	"os"
 	"strings"
+	"time"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
 func buildGithubIdp(cmd *cobra.Command,
@@ -92,6 +97,17 @@ func buildGithubIdp(cmd *cobra.Command,
 		return idpBuilder, errors.New("GitHub IdP requires either organizations or teams")
 	}
 
+	githubHostname := args.githubHostname
+	reporter := runtime.FromContext(cmd.Context()).Reporter()
+	err = validateGithubRestrictions(reporter, githubHostname, organizations, teams)
+	if err != nil {
+		return idpBuilder, err
+	}
+
+	consoleURL := cluster.Console().URL()
+	oauthURL := strings.Replace(consoleURL, "console-openshift-console", "oauth-openshift", 1)
+	callbackURL := oauthURL + "/oauth2callback/" + idpName
+
 	clientID := args.clientID
 	clientSecret := args.clientSecret
 	if clientID == "" || clientSecret == "" {
@@ -112,12 +128,10 @@ func buildGithubIdp(cmd *cobra.Command,
 		}
 
 		// Populate fields in the GitHub registration form
-		consoleURL := cluster.Console().URL()
-		oauthURL := strings.Replace(consoleURL, "console-openshift-console", "oauth-openshift", 1)
 		urlParams := url.Values{}
 		urlParams.Add("oauth_application[name]", cluster.Name())
 		urlParams.Add("oauth_application[url]", consoleURL)
-		urlParams.Add("oauth_application[callback_url]", oauthURL+"/oauth2callback/"+idpName)
+		urlParams.Add("oauth_application[callback_url]", callbackURL)
 
 		registerURL.RawQuery = urlParams.Encode()
 
@@ -160,7 +174,6 @@ func buildGithubIdp(cmd *cobra.Command,
 		ClientID(clientID).
 		ClientSecret(clientSecret)
 
-	githubHostname := args.githubHostname
 	if interactive.Enabled() {
 		githubHostname, err = interactive.GetString(interactive.Input{
 			Question: "GitHub Enterprise Hostname",
@@ -205,6 +218,13 @@ func buildGithubIdp(cmd *cobra.Command,
 		}
 	}
 
+	if githubCallbackMismatch(githubHostname, clientID, callbackURL) {
+		reporter.Warnf("The callback URL of the GitHub application doesn't match the cluster. "+
+			"Change it to:\n   %s", callbackURL)
+	} else {
+		reporter.Infof("The callback URL of the GitHub application must be '%s'", callbackURL)
+	}
+
 	mappingMethod, err := getMappingMethod(cmd, args.mappingMethod)
 	if err != nil {
 		return idpBuilder, fmt.Errorf("Expected a valid mapping method: %s", err)
@@ -226,3 +246,110 @@ func buildGithubIdp(cmd *cobra.Command,
 
 	return
 }
+
+// githubURL returns the URL of GitHub, or of the given GitHub Enterprise host.
+func githubURL(hostname string) string {
+	if hostname == "" {
+		return "https://github.com"
+	}
+	return "https://" + strings.TrimPrefix(strings.TrimPrefix(hostname, "https://"), "http://")
+}
+
+// githubAPIURL returns the URL of the API of GitHub, or of the given GitHub Enterprise host.
+func githubAPIURL(hostname string) string {
+	if hostname == "" {
+		return "https://api.github.com"
+	}
+	return githubURL(hostname) + "/api/v3"
+}
+
+// validateGithubRestrictions checks that the given organizations, or the organizations of the
+// given teams, exist. Teams can only be checked by members of the organization, so they are only
+// checked when the GITHUB_TOKEN environment variable contains a token. Problems reaching GitHub
+// are reported as warnings, as they don't mean that the restrictions are wrong.
+func validateGithubRestrictions(reporter *reporter.Object, hostname string, organizations string,
+	teams string) error {
+	client := &http.Client{Timeout: 10 * time.Second}
+	apiURL := githubAPIURL(hostname)
+	token := os.Getenv("GITHUB_TOKEN")
+
+	check := func(path string, description string) error {
+		request, err := http.NewRequest(http.MethodGet, apiURL+path, nil)
+		if err != nil {
+			return err
+		}
+		request.Header.Set("Accept", "application/vnd.github.v3+json")
+		if token != "" {
+			request.Header.Set("Authorization", "token "+token)
+		}
+		response, err := client.Do(request)
+		if err != nil {
+			reporter.Warnf("Failed to check that %s exists: %v", description, err)
+			return nil
+		}
+		defer response.Body.Close()
+		switch response.StatusCode {
+		case http.StatusOK:
+			return nil
+		case http.StatusNotFound:
+			return fmt.Errorf("GitHub %s doesn't exist", description)
+		default:
+			reporter.Warnf("Failed to check that %s exists: unexpected status code %d",
+				description, response.StatusCode)
+			return nil
+		}
+	}
+
+	if organizations != "" {
+		for _, org := range strings.Split(organizations, ",") {
+			err := check("/orgs/"+url.PathEscape(org), fmt.Sprintf("organization '%s'", org))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if teams != "" {
+		for _, team := range strings.Split(teams, ",") {
+			parts := strings.Split(team, "/")
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				return fmt.Errorf("Team '%s' isn't valid: the format is <org>/<team>", team)
+			}
+			org, slug := parts[0], parts[1]
+			err := check("/orgs/"+url.PathEscape(org), fmt.Sprintf("organization '%s'", org))
+			if err != nil {
+				return err
+			}
+			if token == "" {
+				reporter.Debugf("Skipping check of team '%s' as GITHUB_TOKEN isn't set", team)
+				continue
+			}
+			err = check(fmt.Sprintf("/orgs/%s/teams/%s", url.PathEscape(org), url.PathEscape(slug)),
+				fmt.Sprintf("team '%s'", team))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// githubCallbackMismatch checks if GitHub rejects the given callback URL for the application.
+// GitHub redirects to the registered callback with a 'redirect_uri_mismatch' error when they
+// don't match. Any other outcome, including failures, is treated as a match.
+func githubCallbackMismatch(hostname string, clientID string, callbackURL string) bool {
+	query := url.Values{}
+	query.Add("client_id", clientID)
+	query.Add("redirect_uri", callbackURL)
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	response, err := client.Get(githubURL(hostname) + "/login/oauth/authorize?" + query.Encode())
+	if err != nil {
+		return false
+	}
+	defer response.Body.Close()
+	return strings.Contains(response.Header.Get("Location"), "error=redirect_uri_mismatch")
+}
@@ -20,13 +20,13 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
-	"net/url"
 	"strings"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/ocm"
 )
 
 func buildGitlabIdp(cmd *cobra.Command,
@@ -47,18 +47,10 @@ func buildGitlabIdp(cmd *cobra.Command,
 			return idpBuilder, fmt.Errorf("Expected a valid GitLab provider URL: %s", err)
 		}
 	}
-	parsedIssuerURL, err := url.ParseRequestURI(gitlabURL)
+	// Check the URL before it is used in the instructions:
+	err = ocm.ValidateHTTPSURL("GitLab provider", gitlabURL)
 	if err != nil {
-		return idpBuilder, fmt.Errorf("Expected a valid GitLab provider URL: %s", err)
-	}
-	if parsedIssuerURL.Scheme != "https" {
-		return idpBuilder, errors.New("Expected GitLab provider URL to use an https:// scheme")
-	}
-	if parsedIssuerURL.RawQuery != "" {
-		return idpBuilder, errors.New("GitLab provider URL must not have query parameters")
-	}
-	if parsedIssuerURL.Fragment != "" {
-		return idpBuilder, errors.New("GitLab provider URL must not have a fragment")
+		return idpBuilder, err
 	}
 
 	if clientID == "" || clientSecret == "" {
@@ -132,23 +124,16 @@ func buildGitlabIdp(cmd *cobra.Command,
 		return idpBuilder, fmt.Errorf("Expected a valid mapping method: %s", err)
 	}
 
-	// Create GitLab IDP
-	gitlabIDP := cmv1.NewGitlabIdentityProvider().
-		ClientID(clientID).
-		ClientSecret(clientSecret).
-		URL(gitlabURL)
-
-	// Set the CA file, if any
-	if ca != "" {
-		gitlabIDP = gitlabIDP.CA(ca)
+	builder, err := ocm.BuildGitLabIdentityProvider(&ocm.GitLabIdentityProviderSpec{
+		Name:          idpName,
+		MappingMethod: mappingMethod,
+		ClientID:      clientID,
+		ClientSecret:  clientSecret,
+		URL:           gitlabURL,
+		CA:            ca,
+	})
+	if err != nil {
+		return idpBuilder, err
 	}
-
-	// Create new IDP with GitLab provider
-	idpBuilder.
-		Type("GitlabIdentityProvider"). // FIXME: ocm-api-model has the wrong enum values
-		Name(idpName).
-		MappingMethod(cmv1.IdentityProviderMappingMethod(mappingMethod)).
-		Gitlab(gitlabIDP)
-
-	return
+	return *builder, nil
 }
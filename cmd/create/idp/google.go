@@ -19,13 +19,13 @@ package idp
 import (
 	"errors"
 	"fmt"
-	"net/url"
 	"strings"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/ocm"
 )
 
 func buildGoogleIdp(cmd *cobra.Command,
@@ -79,11 +79,6 @@ func buildGoogleIdp(cmd *cobra.Command,
 		return idpBuilder, fmt.Errorf("Expected a valid mapping method: %s", err)
 	}
 
-	// Create Google IDP
-	googleIDP := cmv1.NewGoogleIdentityProvider().
-		ClientID(clientID).
-		ClientSecret(clientSecret)
-
 	hostedDomain := args.googleHostedDomain
 	if interactive.Enabled() || mappingMethod != "lookup" {
 		hostedDomain, err = interactive.GetString(interactive.Input{
@@ -97,21 +92,15 @@ func buildGoogleIdp(cmd *cobra.Command,
 		}
 	}
 
-	if hostedDomain != "" {
-		_, err = url.ParseRequestURI(hostedDomain)
-		if err != nil {
-			return idpBuilder, fmt.Errorf("Expected a valid Hosted Domain: %v", err)
-		}
-		// Set the hosted domain, if any
-		googleIDP = googleIDP.HostedDomain(hostedDomain)
+	builder, err := ocm.BuildGoogleIdentityProvider(&ocm.GoogleIdentityProviderSpec{
+		Name:          idpName,
+		MappingMethod: mappingMethod,
+		ClientID:      clientID,
+		ClientSecret:  clientSecret,
+		HostedDomain:  hostedDomain,
+	})
+	if err != nil {
+		return idpBuilder, err
 	}
-
-	// Create new IDP with Google provider
-	idpBuilder.
-		Type("GoogleIdentityProvider"). // FIXME: ocm-api-model has the wrong enum values
-		Name(idpName).
-		MappingMethod(cmv1.IdentityProviderMappingMethod(mappingMethod)).
-		Google(googleIDP)
-
-	return
+	return *builder, nil
 }
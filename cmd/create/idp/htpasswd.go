@@ -0,0 +1,92 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package idp
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/htpasswd"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+func createHtpasswdIdp(cmd *cobra.Command,
+	r *runtime.Runtime,
+	cluster *cmv1.Cluster,
+	idpName string) (err error) {
+	var users []*htpasswd.User
+
+	if args.htpasswdFile != "" {
+		// #nosec G304
+		data, err := ioutil.ReadFile(args.htpasswdFile)
+		if err != nil {
+			return fmt.Errorf("Failed to read file '%s': %v", args.htpasswdFile, err)
+		}
+		users, err = htpasswd.ParseFile(data)
+		if err != nil {
+			return fmt.Errorf("Failed to parse file '%s': %v", args.htpasswdFile, err)
+		}
+	} else {
+		username := args.htpasswdUsername
+		if interactive.Enabled() || username == "" {
+			username, err = interactive.GetString(interactive.Input{
+				Question: "Username",
+				Help:     cmd.Flags().Lookup("username").Usage,
+				Default:  username,
+				Required: true,
+			})
+			if err != nil {
+				return errors.New("Expected a valid username")
+			}
+		}
+		password := args.htpasswdPassword
+		if password == "" {
+			password, err = interactive.GetPassword(interactive.Input{
+				Question: "Password",
+				Help:     cmd.Flags().Lookup("password").Usage,
+				Required: true,
+			})
+			if err != nil {
+				return errors.New("Expected a valid password")
+			}
+		}
+		users = []*htpasswd.User{{
+			Username: username,
+			Password: password,
+		}}
+	}
+
+	for _, user := range users {
+		if !ocm.IsValidUsername(user.Username) {
+			return fmt.Errorf("Username '%s' isn't valid", user.Username)
+		}
+	}
+
+	mappingMethod, err := getMappingMethod(cmd, args.mappingMethod)
+	if err != nil {
+		return fmt.Errorf("Expected a valid mapping method: %s", err)
+	}
+
+	r.Reporter().Debugf("Adding %d users to identity provider '%s'", len(users), idpName)
+	return r.OCM().CreateHTPasswdIdentityProvider(cluster.ID(), idpName, mappingMethod, users)
+}
@@ -21,12 +21,14 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/url"
-	"strings"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/ldap"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
 func buildLdapIdp(cmd *cobra.Command,
@@ -82,9 +84,6 @@ func buildLdapIdp(cmd *cobra.Command,
 			return idpBuilder, fmt.Errorf("Expected a valid insecure value: %s", err)
 		}
 	}
-	if needsSecure && ldapInsecure {
-		return idpBuilder, fmt.Errorf("Cannot use insecure connection on ldaps URLs")
-	}
 
 	caPath := args.caPath
 	if interactive.Enabled() && !ldapInsecure {
@@ -100,9 +99,6 @@ func buildLdapIdp(cmd *cobra.Command,
 	// Get certificate contents
 	ca := ""
 	if caPath != "" {
-		if ldapInsecure {
-			return idpBuilder, fmt.Errorf("Cannot use certificate bundle with an insecure connection")
-		}
 		cert, err := ioutil.ReadFile(caPath)
 		if err != nil {
 			return idpBuilder, fmt.Errorf("Expected a valid certificate bundle: %s", err)
@@ -139,6 +135,20 @@ func buildLdapIdp(cmd *cobra.Command,
 		}
 	}
 
+	if args.ldapTestBind {
+		runtime.FromContext(cmd.Context()).Reporter().Infof("Testing connection to '%s'", ldapURL)
+		err = ldap.Test(ldap.Config{
+			URL:          ldapURL,
+			Insecure:     ldapInsecure,
+			CA:           ca,
+			BindDN:       ldapBindDN,
+			BindPassword: ldapBindPassword,
+		})
+		if err != nil {
+			return idpBuilder, err
+		}
+	}
+
 	if interactive.Enabled() {
 		err = interactive.PrintHelp(interactive.Help{
 			Message: "The following options map LDAP attributes to identities. Enter multiple values separated by commas.",
@@ -192,44 +202,21 @@ func buildLdapIdp(cmd *cobra.Command,
 		}
 	}
 
-	// Create LDAP attributes
-	ldapAttributes := cmv1.NewLDAPAttributes().
-		ID(strings.Split(ldapIDs, ",")...)
-
-	if ldapUsernames != "" {
-		ldapAttributes = ldapAttributes.PreferredUsername(strings.Split(ldapUsernames, ",")...)
-	}
-	if ldapDisplayNames != "" {
-		ldapAttributes = ldapAttributes.Name(strings.Split(ldapDisplayNames, ",")...)
-	}
-	if ldapEmails != "" {
-		ldapAttributes = ldapAttributes.Email(strings.Split(ldapEmails, ",")...)
-	}
-
-	// Create LDAP IDP
-	ldapIDP := cmv1.NewLDAPIdentityProvider().
-		URL(ldapURL).
-		Insecure(ldapInsecure).
-		Attributes(ldapAttributes)
-
-	if ldapBindDN != "" {
-		ldapIDP = ldapIDP.BindDN(ldapBindDN)
-		if ldapBindPassword != "" {
-			ldapIDP = ldapIDP.BindPassword(ldapBindPassword)
-		}
-	}
-
-	// Set the CA file, if any
-	if ca != "" {
-		ldapIDP = ldapIDP.CA(ca)
+	builder, err := ocm.BuildLDAPIdentityProvider(&ocm.LDAPIdentityProviderSpec{
+		Name:               idpName,
+		MappingMethod:      mappingMethod,
+		URL:                ldapURL,
+		Insecure:           ldapInsecure,
+		CA:                 ca,
+		BindDN:             ldapBindDN,
+		BindPassword:       ldapBindPassword,
+		IDAttributes:       ocm.SplitList(ldapIDs),
+		UsernameAttributes: ocm.SplitList(ldapUsernames),
+		NameAttributes:     ocm.SplitList(ldapDisplayNames),
+		EmailAttributes:    ocm.SplitList(ldapEmails),
+	})
+	if err != nil {
+		return idpBuilder, err
 	}
-
-	// Create new IDP with LDAP provider
-	idpBuilder.
-		Type("LDAPIdentityProvider"). // FIXME: ocm-api-model has the wrong enum values
-		Name(idpName).
-		MappingMethod(cmv1.IdentityProviderMappingMethod(mappingMethod)).
-		LDAP(ldapIDP)
-
-	return
+	return *builder, nil
 }
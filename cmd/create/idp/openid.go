@@ -20,13 +20,15 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
-	"net/url"
 	"strings"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/oidc"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
 func buildOpenidIdp(cmd *cobra.Command,
@@ -95,18 +97,10 @@ func buildOpenidIdp(cmd *cobra.Command,
 			return idpBuilder, fmt.Errorf("Expected a valid OpenID Issuer URL: %s", err)
 		}
 	}
-	parsedIssuerURL, err := url.ParseRequestURI(issuerURL)
+	// Check the URL before it is used to check the issuer:
+	err = ocm.ValidateHTTPSURL("OpenID issuer", issuerURL)
 	if err != nil {
-		return idpBuilder, fmt.Errorf("Expected a valid OpenID issuer URL: %v", err)
-	}
-	if parsedIssuerURL.Scheme != "https" {
-		return idpBuilder, errors.New("Expected OpenID issuer URL to use an https:// scheme")
-	}
-	if parsedIssuerURL.RawQuery != "" {
-		return idpBuilder, errors.New("OpenID issuer URL must not have query parameters")
-	}
-	if parsedIssuerURL.Fragment != "" {
-		return idpBuilder, errors.New("OpenID issuer URL must not have a fragment")
+		return idpBuilder, err
 	}
 
 	caPath := args.caPath
@@ -173,18 +167,6 @@ func buildOpenidIdp(cmd *cobra.Command,
 		return idpBuilder, errors.New("At least one claim is required: [email-claims name-claims username-claims]")
 	}
 
-	// Build OpenID Claims
-	openIDClaims := cmv1.NewOpenIDClaims()
-	if email != "" {
-		openIDClaims = openIDClaims.Email(strings.Split(email, ",")...)
-	}
-	if name != "" {
-		openIDClaims = openIDClaims.Name(strings.Split(name, ",")...)
-	}
-	if username != "" {
-		openIDClaims = openIDClaims.PreferredUsername(strings.Split(username, ",")...)
-	}
-
 	// Build extra OpenID scopes
 	scopes := args.openidScopes
 	if interactive.Enabled() {
@@ -198,28 +180,38 @@ func buildOpenidIdp(cmd *cobra.Command,
 		}
 	}
 
-	// Create OpenID IDP
-	openIDIDP := cmv1.NewOpenIDIdentityProvider().
-		ClientID(clientID).
-		ClientSecret(clientSecret).
-		Issuer(issuerURL).
-		Claims(openIDClaims)
-
-	if scopes != "" {
-		openIDIDP = openIDIDP.ExtraScopes(strings.Split(scopes, ",")...)
+	// Check the issuer now, as typos in the URL would otherwise only be noticed when users fail
+	// to log in:
+	if !args.openidSkipCheck {
+		claims := []string{}
+		for _, list := range []string{email, name, username} {
+			claims = append(claims, ocm.SplitList(list)...)
+		}
+		warnings, err := oidc.ValidateIssuer(issuerURL, ca, ocm.SplitList(scopes), claims)
+		if err != nil {
+			return idpBuilder, fmt.Errorf("%v. Use '--skip-issuer-check' to create the identity "+
+				"provider anyway", err)
+		}
+		reporter := runtime.FromContext(cmd.Context()).Reporter()
+		for _, warning := range warnings {
+			reporter.Warnf("%s", warning)
+		}
 	}
 
-	// Set the CA file, if any
-	if ca != "" {
-		openIDIDP = openIDIDP.CA(ca)
+	builder, err := ocm.BuildOpenIDIdentityProvider(&ocm.OpenIDIdentityProviderSpec{
+		Name:           idpName,
+		MappingMethod:  mappingMethod,
+		ClientID:       clientID,
+		ClientSecret:   clientSecret,
+		IssuerURL:      issuerURL,
+		CA:             ca,
+		EmailClaims:    ocm.SplitList(email),
+		NameClaims:     ocm.SplitList(name),
+		UsernameClaims: ocm.SplitList(username),
+		ExtraScopes:    ocm.SplitList(scopes),
+	})
+	if err != nil {
+		return idpBuilder, err
 	}
-
-	// Create new IDP with OpenID provider
-	idpBuilder.
-		Type("OpenIDIdentityProvider"). // FIXME: ocm-api-model has the wrong enum values
-		Name(idpName).
-		MappingMethod(cmv1.IdentityProviderMappingMethod(mappingMethod)).
-		OpenID(openIDIDP)
-
-	return
+	return *builder, nil
 }
@@ -18,21 +18,18 @@ package ingress
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/moactl/pkg/aws"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/interactive"
-	"github.com/openshift/moactl/pkg/logging"
-	"github.com/openshift/moactl/pkg/ocm"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
 var args struct {
-	clusterKey string
 	private    bool
 	labelMatch string
 }
@@ -50,20 +47,13 @@ var Cmd = &cobra.Command{
 
   # Add an ingress with route selector label match
   rosa create ingress -c mycluster --label-match="foo=bar,bar=baz"`,
-	Run: run,
+	RunE: run,
 }
 
 func init() {
 	flags := Cmd.Flags()
 
-	flags.StringVarP(
-		&args.clusterKey,
-		"cluster",
-		"c",
-		"",
-		"Name or ID of the cluster to add the ingress to (required).",
-	)
-	Cmd.MarkFlagRequired("cluster")
+	clusterprovider.UseKey(Cmd)
 
 	flags.BoolVar(
 		&args.private,
@@ -81,25 +71,17 @@ func init() {
 	)
 }
 
-func run(cmd *cobra.Command, _ []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
-
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
-	clusterKey := args.clusterKey
-	if !ocm.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	clusterKey, err := clusterprovider.GetKey(r, nil)
+	if err != nil {
+		return err
 	}
 
 	labelMatch := args.labelMatch
 	routeSelectors := make(map[string]string)
-	var err error
 	if interactive.Enabled() {
 		labelMatch, err = interactive.GetString(interactive.Input{
 			Question: "Label match for ingress",
@@ -107,62 +89,25 @@ func run(cmd *cobra.Command, _ []string) {
 			Default:  labelMatch,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid comma-separated list of attributes: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid comma-separated list of attributes: %w", err)
 		}
 	}
 	if labelMatch != "" {
 		routeSelectors, err = getRouteSelector(labelMatch)
 		if err != nil {
-			reporter.Errorf("%s", err)
-			os.Exit(1)
+			return err
 		}
 	}
 
-	// Create the AWS client:
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
-	}
-
-	awsCreator, err := awsClient.GetCreator()
-	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
-	}
-
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
-	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
-
-	// Get the client for the OCM collection of clusters:
-	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
-
 	// Try to find the cluster:
 	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
 	if err != nil {
-		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
 	}
 
 	if cluster.State() != cmv1.ClusterStateReady {
-		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
-		os.Exit(1)
+		return rosaerrors.Conflictf("Cluster '%s' is not yet ready", clusterKey)
 	}
 
 	ingressBuilder := cmv1.NewIngress()
@@ -180,8 +125,7 @@ func run(cmd *cobra.Command, _ []string) {
 			Default:  args.private,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid private value: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid private value: %w", err)
 		}
 		if private {
 			ingressBuilder = ingressBuilder.Listening(cmv1.ListeningMethodInternal)
@@ -195,20 +139,15 @@ func run(cmd *cobra.Command, _ []string) {
 	}
 	ingress, err := ingressBuilder.Build()
 	if err != nil {
-		reporter.Errorf("Failed to create ingress for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to create ingress for cluster '%s': %w", clusterKey, err)
 	}
 
-	res, err := clustersCollection.Cluster(cluster.ID()).
-		Ingresses().
-		Add().
-		Body(ingress).
-		Send()
+	err = r.OCM().AddIngress(cluster.ID(), ingress)
 	if err != nil {
-		reporter.Debugf(err.Error())
-		reporter.Errorf("Failed to add ingress to cluster '%s': %s", clusterKey, res.Error().Reason())
-		os.Exit(1)
+		return fmt.Errorf("Failed to add ingress to cluster '%s': %w", clusterKey, err)
 	}
+
+	return nil
 }
 
 func getRouteSelector(labelMatches string) (map[string]string, error) {
@@ -0,0 +1,150 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeletconfig
+
+import (
+	"fmt"
+	"regexp"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/ci"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/ocm/kubeletconfigs"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// Regular expression used to make sure that the name given by the user is safe:
+var nameRE = regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
+
+var args struct {
+	name         string
+	podPidsLimit int
+}
+
+var Cmd = &cobra.Command{
+	Use:     "kubeletconfig",
+	Aliases: []string{"kubelet-config"},
+	Short:   "Create kubelet configuration",
+	Long: "Create a kubelet configuration for a cluster. The configuration is applied to the nodes " +
+		"of the machine pools that it is attached to with 'rosa edit machinepool --kubelet-configs'.",
+	Example: `  # Create a kubelet configuration that allows 8192 processes per pod
+  rosa create kubeletconfig --cluster=mycluster --name=high-pids --pod-pids-limit=8192
+
+  # Use it in machine pool 'mp1'
+  rosa edit machinepool --cluster=mycluster --kubelet-configs=high-pids mp1`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	clusterprovider.UseKey(Cmd)
+
+	flags.StringVar(
+		&args.name,
+		"name",
+		"",
+		"Name of the kubelet configuration (required).",
+	)
+
+	flags.IntVar(
+		&args.podPidsLimit,
+		"pod-pids-limit",
+		0,
+		"Maximum number of processes that can run in each pod (required).",
+	)
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	clusterKey, err := clusterprovider.GetKey(r, nil)
+	if err != nil {
+		return err
+	}
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	if cluster.State() != cmv1.ClusterStateReady {
+		return rosaerrors.Conflictf("Cluster '%s' is not yet ready", clusterKey)
+	}
+
+	name := args.name
+	if interactive.Enabled() || name == "" {
+		name, err = interactive.GetString(interactive.Input{
+			Question: "Kubelet configuration name",
+			Help:     cmd.Flags().Lookup("name").Usage,
+			Default:  name,
+			Required: true,
+		})
+		if err != nil {
+			return rosaerrors.Usagef("Expected a valid name: %w", err)
+		}
+	}
+	if !nameRE.MatchString(name) {
+		return rosaerrors.Usagef("Expected a valid name for the kubelet configuration")
+	}
+
+	podPidsLimit, err := getPodPidsLimit(cmd, args.podPidsLimit)
+	if err != nil {
+		return rosaerrors.Usagef("Expected a valid pod PIDs limit: %w", err)
+	}
+	err = kubeletconfigs.ValidatePodPidsLimit(podPidsLimit)
+	if err != nil {
+		return err
+	}
+
+	reporter.Debugf("Creating kubelet configuration '%s' on cluster '%s'", name, clusterKey)
+	config, err := r.OCM().CreateKubeletConfig(cluster.ID(), &kubeletconfigs.KubeletConfig{
+		Name:         name,
+		PodPidsLimit: podPidsLimit,
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to create kubelet configuration '%s' on cluster '%s': %w",
+			name, clusterKey, err)
+	}
+	ci.RecordResource(&ci.Resource{Kind: "kubelet-config", Cluster: cluster.ID(), ID: config.ID, Name: name})
+	reporter.Infof("Kubelet configuration '%s' has been created on cluster '%s'. Attach it to machine "+
+		"pools with 'rosa edit machinepool --kubelet-configs=%s'", name, clusterKey, name)
+
+	return nil
+}
+
+func getPodPidsLimit(cmd *cobra.Command, limit int) (int, error) {
+	if interactive.Enabled() || !cmd.Flags().Changed("pod-pids-limit") {
+		if limit == 0 {
+			limit = kubeletconfigs.MinPodPidsLimit
+		}
+		return interactive.GetInt(interactive.Input{
+			Question: "Pod PIDs limit",
+			Help:     cmd.Flags().Lookup("pod-pids-limit").Usage,
+			Default:  limit,
+			Required: true,
+		})
+	}
+	return limit, nil
+}
@@ -18,33 +18,45 @@ package machinepool
 
 import (
 	"fmt"
-	"os"
 	"regexp"
-	"strings"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/moactl/pkg/arguments"
 	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/ci"
 	c "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/defaults"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/interactive"
-	"github.com/openshift/moactl/pkg/logging"
-	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/machinepools"
 	"github.com/openshift/moactl/pkg/ocm/machines"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
 // Regular expression to used to make sure that the identifier given by the
 // user is safe and that it there is no risk of SQL injection:
 var machinePoolKeyRE = regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
 
+const machinePoolNameMessage = "it must contain only lowercase letters, digits and dashes, start with a " +
+	"letter and end with a letter or digit"
+
 var args struct {
-	clusterKey   string
-	name         string
-	instanceType string
-	replicas     int
-	labels       string
-	taints       string
+	name              string
+	instanceType      string
+	replicas          int
+	enableAutoscaling bool
+	minReplicas       int
+	maxReplicas       int
+	labels            string
+	taints            string
+	useSpotInstances  bool
+	spotMaxPrice      string
+	deleteProtection  bool
+	subnet            string
+	availabilityZones []string
+	workerDiskSize    string
 }
 
 var Cmd = &cobra.Command{
@@ -58,22 +70,31 @@ var Cmd = &cobra.Command{
   # Add a machine pool mp-1 with 3 replicas of m5.xlarge to a cluster
   rosa create machinepool --cluster=mycluster --name=mp-1 --replicas=3 --instance-type=m5.xlarge
 
+  # Add a machine pool that scales between 2 and 6 replicas to a cluster
+  rosa create machinepool --cluster=mycluster --name=mp-1 --enable-autoscaling --min-replicas=2 --max-replicas=6
+
   # Add a machine pool with labels to a cluster
-  rosa create machinepool -c mycluster --name=mp-1 --replicas=2 --instance-type=r5.2xlarge --labels =foo=bar,bar=baz"`,
-	Run: run,
+  rosa create machinepool -c mycluster --name=mp-1 --replicas=2 --instance-type=r5.2xlarge --labels =foo=bar,bar=baz"
+
+  # Add a machine pool whose nodes have a root disk of 500 GiB for image-heavy workloads
+  rosa create machinepool --cluster=mycluster --name=mp-1 --replicas=2 --worker-disk-size=500GiB
+
+  # Add a machine pool that runs on spot instances costing at most $0.50 per hour
+  rosa create machinepool --cluster=mycluster --name=mp-1 --replicas=3 --use-spot-instances --spot-max-price=0.5
+
+  # Add a machine pool whose nodes run only in zone us-east-1a of a multi-AZ cluster
+  rosa create machinepool --cluster=mycluster --name=mp-1 --replicas=2 --availability-zones=us-east-1a
+
+  # Add a machine pool whose nodes run in the Local Zone of subnet subnet-1
+  rosa create machinepool --cluster=mycluster --name=mp-1 --replicas=2 --instance-type=r5.2xlarge --subnet=subnet-1`,
+	PreRunE: arguments.CheckFlagGroups,
+	RunE:    run,
 }
 
 func init() {
 	flags := Cmd.Flags()
 
-	flags.StringVarP(
-		&args.clusterKey,
-		"cluster",
-		"c",
-		"",
-		"Name or ID of the cluster to add the machine pool to (required).",
-	)
-	Cmd.MarkFlagRequired("cluster")
+	c.UseKey(Cmd)
 
 	flags.StringVar(
 		&args.name,
@@ -86,7 +107,29 @@ func init() {
 		&args.replicas,
 		"replicas",
 		0,
-		"Count of machines for this machine pool (required).",
+		"Count of machines for this machine pool (required unless autoscaling is enabled).",
+	)
+
+	flags.BoolVar(
+		&args.enableAutoscaling,
+		"enable-autoscaling",
+		false,
+		"Enable autoscaling for the machine pool, so that the number of machines is kept between "+
+			"'--min-replicas' and '--max-replicas'.",
+	)
+
+	flags.IntVar(
+		&args.minReplicas,
+		"min-replicas",
+		0,
+		"Minimum number of machines for the machine pool when autoscaling is enabled.",
+	)
+
+	flags.IntVar(
+		&args.maxReplicas,
+		"max-replicas",
+		0,
+		"Maximum number of machines for the machine pool when autoscaling is enabled.",
 	)
 
 	flags.StringVar(
@@ -108,72 +151,87 @@ func init() {
 		&args.taints,
 		"taints",
 		"",
-		"Taints for machine pool. Format should be a comma-separated list of 'key=value:ScheduleType'. "+
+		"Taints for machine pool. Format should be a comma-separated list of 'key=value:Effect', where the "+
+			"effect is 'NoSchedule', 'PreferNoSchedule' or 'NoExecute'. "+
 			"This list will overwrite any modifications made to Node taints on an ongoing basis.",
 	)
-}
 
-func run(cmd *cobra.Command, _ []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
-
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
-	clusterKey := args.clusterKey
-	if !c.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
-	}
-
-	// Create the AWS client:
-	var err error
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
-	}
+	flags.StringVar(
+		&args.workerDiskSize,
+		"worker-disk-size",
+		"",
+		fmt.Sprintf("Size of the root disk of the nodes of the machine pool, for example \"300GiB\" or "+
+			"\"1TiB\". It must be between %d GiB and %d GiB. The default is the size chosen by OpenShift.",
+			defaults.Builtin().WorkerDiskSize.Min, defaults.Builtin().WorkerDiskSize.Max),
+	)
 
-	awsCreator, err := awsClient.GetCreator()
-	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
-	}
+	flags.BoolVar(
+		&args.useSpotInstances,
+		"use-spot-instances",
+		false,
+		"Use spot instances for the machine pool. Spot instances are cheaper, but AWS can reclaim "+
+			"them at any time, so use them only for workloads that tolerate interruptions.",
+	)
+
+	flags.StringVar(
+		&args.spotMaxPrice,
+		"spot-max-price",
+		machinepools.OnDemandMaxPrice,
+		"Maximum price per hour, in US dollars, of the spot instances, or 'on-demand' to pay at "+
+			"most the price of the on-demand instances.",
+	)
+
+	flags.BoolVar(
+		&args.deleteProtection,
+		"enable-delete-protection",
+		false,
+		"Protect the machine pool against accidental deletion. Protected machine pools can only be "+
+			"deleted with 'rosa delete machinepool --force'.",
+	)
+
+	flags.StringVar(
+		&args.subnet,
+		"subnet",
+		"",
+		"Subnet in an AWS Local Zone or Outpost, in the VPC of the cluster, where the nodes of the machine "+
+			"pool run instead of in the availability zones of the cluster. The nodes get the "+
+			"'node-role.kubernetes.io/edge' label, and a taint with the same key unless taints are given.",
+	)
+
+	flags.StringSliceVar(
+		&args.availabilityZones,
+		"availability-zones",
+		nil,
+		"Availability zones of the cluster where the nodes of the machine pool run, either a single "+
+			"zone or all the zones of the cluster. A single zone in a multi-AZ cluster creates a "+
+			"single-AZ machine pool. The default is all the zones of the cluster.",
+	)
 
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
+	arguments.MarkFlagsMutuallyExclusive(flags, "replicas", "enable-autoscaling")
+	arguments.MarkFlagsMutuallyExclusive(flags, "availability-zones", "subnet")
+	arguments.MarkFlagRequires(flags, "spot-max-price", "use-spot-instances")
+	arguments.MarkFlagRequires(flags, "min-replicas", "enable-autoscaling")
+	arguments.MarkFlagRequires(flags, "max-replicas", "enable-autoscaling")
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	clusterKey, err := c.GetKey(r, nil)
 	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		return err
 	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
-
-	// Get the client for the OCM collection of clusters:
-	ocmClient := ocmConnection.ClustersMgmt().V1()
 
 	// Try to find the cluster:
 	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := ocm.GetCluster(ocmClient.Clusters(), clusterKey, awsCreator.ARN)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
 	if err != nil {
-		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
 	}
 
 	if cluster.State() != cmv1.ClusterStateReady {
-		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
-		os.Exit(1)
+		return rosaerrors.Conflictf("Cluster '%s' is not yet ready", clusterKey)
 	}
 
 	// Machine pool name:
@@ -187,20 +245,104 @@ func run(cmd *cobra.Command, _ []string) {
 			Question: "Machine pool name",
 			Default:  name,
 			Required: true,
+			Validators: []interactive.Validator{
+				interactive.RegExpValidator(machinePoolKeyRE, machinePoolNameMessage),
+			},
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid name for the machine pool: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid name for the machine pool: %w", err)
 		}
 	}
 	if !machinePoolKeyRE.MatchString(name) {
-		reporter.Errorf("Expected a valid name for the machine pool")
-		os.Exit(1)
+		return rosaerrors.Usagef("Expected a valid name for the machine pool: %s", machinePoolNameMessage)
+	}
+
+	// Subnet in a Local Zone or Outpost:
+	subnetID := args.subnet
+	if interactive.Enabled() {
+		subnetID, err = interactive.GetString(interactive.Input{
+			Question: "Local Zone or Outpost subnet",
+			Help:     cmd.Flags().Lookup("subnet").Usage,
+			Default:  subnetID,
+		})
+		if err != nil {
+			return rosaerrors.Usagef("Expected a valid subnet: %w", err)
+		}
+	}
+	// Availability zones, only multi-AZ clusters have a choice:
+	clusterZones := cluster.Nodes().AvailabilityZones()
+	zones := args.availabilityZones
+	if interactive.Enabled() && cluster.MultiAZ() && subnetID == "" {
+		zones, err = interactive.GetMultipleOptions(interactive.Input{
+			Question: "Availability zones",
+			Help:     cmd.Flags().Lookup("availability-zones").Usage,
+			Options:  clusterZones,
+			Default:  zones,
+		})
+		if err != nil {
+			return rosaerrors.Usagef("Expected valid availability zones: %w", err)
+		}
+	}
+	if len(zones) > 0 {
+		err = r.WithAWSRegion(cluster.Region().ID()).AWSClient().ValidateAvailabilityZones(zones)
+		if err != nil {
+			return err
+		}
+		err = machinepools.ValidateAvailabilityZones(zones, clusterZones)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The nodes of machine pools in a single subnet or zone aren't spread over the zones of the
+	// cluster:
+	multiAZ := cluster.MultiAZ() && subnetID == "" && len(zones) != 1
+
+	// Autoscaling replaces the fixed number of replicas with a range:
+	autoscaling := args.enableAutoscaling
+	if interactive.Enabled() {
+		autoscaling, err = interactive.GetBool(interactive.Input{
+			Question: "Enable autoscaling",
+			Help:     cmd.Flags().Lookup("enable-autoscaling").Usage,
+			Default:  autoscaling,
+		})
+		if err != nil {
+			return rosaerrors.Usagef("Expected a valid value for enable autoscaling: %w", err)
+		}
 	}
 
 	// Number of replicas:
 	replicas := args.replicas
-	if interactive.Enabled() {
+	minReplicas := args.minReplicas
+	maxReplicas := args.maxReplicas
+	if autoscaling {
+		if interactive.Enabled() {
+			minReplicas, err = interactive.GetInt(interactive.Input{
+				Question: "Min replicas",
+				Help:     cmd.Flags().Lookup("min-replicas").Usage,
+				Default:  minReplicas,
+				Required: true,
+			})
+			if err != nil {
+				return rosaerrors.Usagef("Expected a valid minimum number of replicas: %w", err)
+			}
+			maxReplicas, err = interactive.GetInt(interactive.Input{
+				Question: "Max replicas",
+				Help:     cmd.Flags().Lookup("max-replicas").Usage,
+				Default:  maxReplicas,
+				Required: true,
+			})
+			if err != nil {
+				return rosaerrors.Usagef("Expected a valid maximum number of replicas: %w", err)
+			}
+		}
+		err = machinepools.ValidateAutoscaling(minReplicas, maxReplicas, multiAZ)
+		if err != nil {
+			return err
+		}
+		// The quotas have to be enough for the largest size of the machine pool:
+		replicas = maxReplicas
+	} else if interactive.Enabled() {
 		replicas, err = interactive.GetInt(interactive.Input{
 			Question: "Replicas",
 			Help:     cmd.Flags().Lookup("replicas").Usage,
@@ -208,20 +350,27 @@ func run(cmd *cobra.Command, _ []string) {
 			Required: true,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid number of replicas: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid number of replicas: %w", err)
 		}
 	}
 
 	// Machine pool instance type:
 	instanceType := args.instanceType
-	instanceTypeList, err := machines.GetMachineTypeList(ocmClient)
+	instanceTypeList, err := r.OCM().GetMachineTypeList()
 	if err != nil {
-		reporter.Errorf(fmt.Sprintf("%s", err))
-		os.Exit(1)
+		return err
+	}
+	// The instance types of machine pools in Local Zones or Outposts are checked with the subnet:
+	if subnetID == "" {
+		poolZones := clusterZones
+		if len(zones) > 0 {
+			poolZones = zones
+		}
+		instanceTypeList = filterAvailableMachineTypes(r, r.WithAWSRegion(cluster.Region().ID()).AWSClient(),
+			instanceTypeList, poolZones)
 	}
 	if interactive.Enabled() {
-		if instanceType == "" {
+		if instanceType == "" && len(instanceTypeList) > 0 {
 			instanceType = instanceTypeList[0]
 		}
 		instanceType, err = interactive.GetOption(interactive.Input{
@@ -232,94 +381,210 @@ func run(cmd *cobra.Command, _ []string) {
 			Required: true,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid machine type: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid machine type: %w", err)
 		}
 	}
 	if instanceType == "" {
-		reporter.Errorf("Expected a valid machine type")
-		os.Exit(1)
+		return rosaerrors.Usagef("Expected a valid machine type")
 	}
 	instanceType, err = machines.ValidateMachineType(instanceType, instanceTypeList)
 	if err != nil {
-		reporter.Errorf("Expected a valid machine type: %s", err)
-		os.Exit(1)
+		return rosaerrors.Usagef("Expected a valid machine type: %w", err)
 	}
 
-	labels := args.labels
-	labelMap := make(map[string]string)
+	// Root disk size:
+	workerDiskSize := args.workerDiskSize
+	diskLimits := defaults.Builtin().WorkerDiskSize
+	if workerDiskSize != "" || interactive.Enabled() {
+		orgDefaults, err := r.OCM().LoadDefaults()
+		if err != nil {
+			reporter.Debugf("Failed to load disk size limits of the organization, using built-in values: %v", err)
+		} else {
+			diskLimits = orgDefaults.WorkerDiskSize
+		}
+	}
 	if interactive.Enabled() {
-		labels, err = interactive.GetString(interactive.Input{
-			Question: "Labels",
-			Help:     cmd.Flags().Lookup("labels").Usage,
-			Default:  labels,
+		workerDiskSize, err = interactive.GetString(interactive.Input{
+			Question:   "Worker disk size",
+			Help:       cmd.Flags().Lookup("worker-disk-size").Usage,
+			Default:    workerDiskSize,
+			Validators: []interactive.Validator{machinepools.DiskSizeValidator(diskLimits.Validate)},
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid comma-separated list of attributes: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid worker disk size: %w", err)
+		}
+	}
+	var diskSize int
+	if workerDiskSize != "" {
+		diskSize, err = machinepools.ParseDiskSize(workerDiskSize)
+		if err == nil {
+			err = diskLimits.Validate(diskSize)
+		}
+		if err != nil {
+			return err
 		}
 	}
-	if labels != "" {
-		for _, label := range strings.Split(labels, ",") {
-			if !strings.Contains(label, "=") {
-				reporter.Errorf("Expected key=value format for labels")
-				os.Exit(1)
+
+	var edgeSubnet *aws.EdgeSubnet
+	if subnetID != "" {
+		edgeSubnet, err = checkEdgeSubnet(r, cluster, subnetID, instanceType)
+		if err != nil {
+			return err
+		}
+	}
+
+	// GPU instances have their own vCPU quotas, which are usually much lower than the quota of the
+	// standard instances, so check them before anything is created:
+	var gpuInfo *aws.GPUInfo
+	if aws.IsGPUInstanceType(instanceType) {
+		gpuInfo, err = checkGPUs(r, cluster, instanceType, replicas)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Spot instances:
+	useSpotInstances := args.useSpotInstances
+	if interactive.Enabled() {
+		useSpotInstances, err = interactive.GetBool(interactive.Input{
+			Question: "Use spot instances",
+			Help:     cmd.Flags().Lookup("use-spot-instances").Usage,
+			Default:  useSpotInstances,
+		})
+		if err != nil {
+			return rosaerrors.Usagef("Expected a valid value for use spot instances: %w", err)
+		}
+	}
+	var spot *machinepools.SpotMarketOptions
+	if useSpotInstances {
+		spotMaxPrice := args.spotMaxPrice
+		if interactive.Enabled() {
+			spotMaxPrice, err = interactive.GetString(interactive.Input{
+				Question: "Spot instance max price",
+				Help:     cmd.Flags().Lookup("spot-max-price").Usage,
+				Default:  spotMaxPrice,
+				Required: true,
+			})
+			if err != nil {
+				return rosaerrors.Usagef("Expected a valid spot instance max price: %w", err)
 			}
-			tokens := strings.Split(label, "=")
-			labelMap[strings.TrimSpace(tokens[0])] = strings.TrimSpace(tokens[1])
+		}
+		spot, err = machinepools.ParseSpotMaxPrice(spotMaxPrice)
+		if err != nil {
+			return err
 		}
 	}
 
+	labels := args.labels
+	if interactive.Enabled() {
+		labels, err = interactive.GetString(interactive.Input{
+			Question:   "Labels",
+			Help:       cmd.Flags().Lookup("labels").Usage,
+			Default:    labels,
+			Validators: []interactive.Validator{labelsValidator},
+		})
+		if err != nil {
+			return rosaerrors.Usagef("Expected a valid comma-separated list of attributes: %w", err)
+		}
+	}
+	labelMap, err := machinepools.ParseLabels(labels)
+	if err != nil {
+		return err
+	}
+
 	taints := args.taints
-	taintBuilders := []*cmv1.TaintBuilder{}
 	if interactive.Enabled() {
 		taints, err = interactive.GetString(interactive.Input{
-			Question: "Taints",
-			Help:     cmd.Flags().Lookup("taints").Usage,
-			Default:  labels,
+			Question:   "Taints",
+			Help:       cmd.Flags().Lookup("taints").Usage,
+			Default:    taints,
+			Validators: []interactive.Validator{taintsValidator},
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid comma-separated list of attributes: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid comma-separated list of attributes: %w", err)
 		}
 	}
-	if taints != "" {
-		for _, taint := range strings.Split(taints, ",") {
-			if !strings.Contains(taint, "=") || !strings.Contains(taint, ":") {
-				reporter.Errorf("Expected key=value:scheduleType format for taints")
-				os.Exit(1)
-			}
-			tokens := strings.FieldsFunc(taint, Split)
-			taintBuilders = append(taintBuilders, cmv1.NewTaint().Key(tokens[0]).Value(tokens[1]).Effect(tokens[2]))
+	taintBuilders, err := machinepools.ParseTaints(taints)
+	if err != nil {
+		return err
+	}
+
+	if edgeSubnet != nil {
+		if _, ok := labelMap[edgeNodeRole]; !ok {
+			labelMap[edgeNodeRole] = ""
+		}
+		if len(taintBuilders) == 0 {
+			taintBuilders = append(taintBuilders, edgeNodeTaint())
+		}
+	}
+
+	deleteProtection := args.deleteProtection
+	if interactive.Enabled() {
+		deleteProtection, err = interactive.GetBool(interactive.Input{
+			Question: "Enable delete protection",
+			Help:     cmd.Flags().Lookup("enable-delete-protection").Usage,
+			Default:  deleteProtection,
+		})
+		if err != nil {
+			return rosaerrors.Usagef("Expected a valid delete protection value: %w", err)
 		}
 	}
 
-	machinePool, err := cmv1.NewMachinePool().
+	machinePoolBuilder := cmv1.NewMachinePool().
 		ID(name).
-		Replicas(replicas).
 		InstanceType(instanceType).
 		Labels(labelMap).
-		Taints(taintBuilders...).
-		Build()
+		Taints(taintBuilders...)
+	if len(zones) > 0 {
+		machinePoolBuilder = machinePoolBuilder.AvailabilityZones(zones...)
+	}
+	if autoscaling {
+		machinePoolBuilder = machinePoolBuilder.Autoscaling(machinepools.NewAutoscaling(minReplicas, maxReplicas))
+	} else {
+		machinePoolBuilder = machinePoolBuilder.Replicas(replicas)
+	}
+	machinePool, err := machinePoolBuilder.Build()
 	if err != nil {
-		reporter.Errorf("Failed to create machine pool for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to create machine pool for cluster '%s': %w", clusterKey, err)
 	}
 
-	_, err = ocmClient.Clusters().
-		Cluster(cluster.ID()).
-		MachinePools().
-		Add().
-		Body(machinePool).
-		Send()
+	err = r.OCM().AddMachinePool(cluster.ID(), machinePool, spot, subnetID, diskSize)
 	if err != nil {
-		reporter.Errorf("Failed to add machine pool to cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to add machine pool to cluster '%s': %w", clusterKey, err)
 	}
 
 	reporter.Infof("Machine pool '%s' created successfully on cluster '%s'", name, clusterKey)
+	if deleteProtection {
+		err = r.OCM().SetMachinePoolDeleteProtection(cluster, name, true)
+		if err != nil {
+			return fmt.Errorf("Failed to enable delete protection of machine pool '%s': %w", name, err)
+		}
+	}
+	if spot != nil {
+		reporter.Infof("The nodes of machine pool '%s' run on spot instances (%s)", name, spot)
+	}
+	if edgeSubnet != nil {
+		reporter.Infof("The nodes of machine pool '%s' run in %s and have the '%s' label", name,
+			edgeLocationDescription(edgeSubnet), edgeNodeRole)
+	}
+	ci.RecordResource(&ci.Resource{Kind: "machine-pool", Cluster: cluster.ID(), ID: name})
+	if gpuInfo != nil {
+		printGPUHints(r, name, gpuInfo, replicas, len(taintBuilders) > 0)
+	}
+
+	return nil
+}
+
+// labelsValidator checks the labels answered in interactive mode.
+func labelsValidator(answer interface{}) error {
+	str, _ := answer.(string)
+	_, err := machinepools.ParseLabels(str)
+	return err
 }
 
-func Split(r rune) bool {
-	return r == '=' || r == ':'
+// taintsValidator checks the taints answered in interactive mode.
+func taintsValidator(answer interface{}) error {
+	str, _ := answer.(string)
+	_, err := machinepools.ParseTaints(str)
+	return err
 }
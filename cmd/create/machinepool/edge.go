@@ -0,0 +1,88 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinepool
+
+import (
+	"fmt"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/aws"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// Label and taint of the nodes in Local Zones and Outposts, the same that OpenShift uses for edge
+// nodes, so that only the workloads that tolerate the higher latency to the control plane run there:
+const (
+	edgeNodeRole        = "node-role.kubernetes.io/edge"
+	edgeNodeTaintEffect = "NoSchedule"
+)
+
+// checkEdgeSubnet checks that the given subnet is in a Local Zone or an Outpost, in the VPC of the
+// cluster, and that the instance type is offered there.
+func checkEdgeSubnet(r *runtime.Runtime, cluster *cmv1.Cluster, subnetID string,
+	instanceType string) (*aws.EdgeSubnet, error) {
+	reporter := r.Reporter()
+	awsClient := r.WithAWSRegion(cluster.Region().ID()).AWSClient()
+
+	reporter.Debugf("Loading subnet '%s'", subnetID)
+	subnet, err := awsClient.GetEdgeSubnet(subnetID)
+	if err != nil {
+		return nil, err
+	}
+
+	infraID, err := r.OCM().GetInfraID(cluster.ID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get infrastructure identifier of cluster '%s': %w", cluster.Name(), err)
+	}
+	network, err := awsClient.GetClusterNetwork(infraID, cluster.AWS().SubnetIDs())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get network of cluster '%s': %w", cluster.Name(), err)
+	}
+	inVPC := false
+	for _, vpc := range network.VPCs {
+		if vpc.ID == subnet.VPCID {
+			inVPC = true
+		}
+	}
+	if !inVPC {
+		return nil, rosaerrors.Usagef("Subnet '%s' is in VPC '%s', but it must be in the VPC of cluster '%s'",
+			subnetID, subnet.VPCID, cluster.Name())
+	}
+
+	err = awsClient.ValidateEdgeInstanceType(subnet, instanceType)
+	if err != nil {
+		return nil, err
+	}
+	return subnet, nil
+}
+
+// edgeNodeTaint returns the taint that keeps the workloads that don't tolerate it away from the
+// nodes in Local Zones and Outposts.
+func edgeNodeTaint() *cmv1.TaintBuilder {
+	return cmv1.NewTaint().Key(edgeNodeRole).Effect(edgeNodeTaintEffect)
+}
+
+// edgeLocationDescription returns the description of the location of the subnet used in messages,
+// for example "Local Zone 'us-west-2-lax-1a'".
+func edgeLocationDescription(subnet *aws.EdgeSubnet) string {
+	if subnet.Location == aws.SubnetLocationOutpost {
+		return "Outpost '" + subnet.OutpostARN + "'"
+	}
+	return "Local Zone '" + subnet.Zone + "'"
+}
@@ -0,0 +1,79 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinepool
+
+import (
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/aws"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// checkGPUs checks that the AWS account of the cluster has enough quota to run the given number of
+// GPU instances, and returns the description of the GPUs of the instance type. Problems getting
+// the information from AWS are only reported as warnings, as they don't prevent creating the
+// machine pool.
+func checkGPUs(r *runtime.Runtime, cluster *cmv1.Cluster, instanceType string, replicas int) (*aws.GPUInfo, error) {
+	reporter := r.Reporter()
+	awsClient := r.WithAWSRegion(cluster.Region().ID()).AWSClient()
+
+	reporter.Debugf("Loading GPUs of instance type '%s'", instanceType)
+	info, err := awsClient.GetGPUInfo(instanceType)
+	if err != nil {
+		reporter.Warnf("Failed to get GPUs of instance type '%s': %v", instanceType, err)
+		return nil, nil
+	}
+	if replicas == 0 {
+		return info, nil
+	}
+
+	reporter.Debugf("Checking vCPU quota of instance type '%s'", instanceType)
+	err = awsClient.ValidateGPUQuota(info, replicas)
+	if err != nil {
+		if _, ok := err.(*aws.GPUQuotaError); ok {
+			return nil, rosaerrors.Quotaf("Not enough quota to run %d '%s' instances: %w", replicas, instanceType, err)
+		}
+		reporter.Warnf("Failed to check vCPU quota of instance type '%s': %v", instanceType, err)
+	}
+	return info, nil
+}
+
+// printGPUHints tells the user how many GPUs the nodes of the machine pool have, and what needs to
+// be installed in the cluster to use them.
+func printGPUHints(r *runtime.Runtime, name string, info *aws.GPUInfo, replicas int, tainted bool) {
+	reporter := r.Reporter()
+	if info.GPUs == 0 {
+		return
+	}
+	reporter.Infof("Each node of machine pool '%s' has %d %s %s GPUs, %d in total",
+		name, info.GPUs, info.Manufacturer, info.Name, info.GPUs*replicas)
+	switch info.Manufacturer {
+	case "NVIDIA":
+		reporter.Infof("To use the GPUs install the 'Node Feature Discovery' and 'NVIDIA GPU Operator' " +
+			"operators from OperatorHub")
+	case "AMD":
+		reporter.Infof("To use the GPUs install the 'Node Feature Discovery' and 'AMD GPU Operator' " +
+			"operators from OperatorHub")
+	default:
+		reporter.Infof("To use the GPUs install the device plugin of the manufacturer")
+	}
+	if !tainted {
+		reporter.Infof("GPU machine pools are usually created with a taint, like " +
+			"'--taints=nvidia.com/gpu=present:NoSchedule', to keep other workloads off the GPU nodes")
+	}
+}
@@ -0,0 +1,38 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinepool
+
+import (
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/ocm/machines"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// filterAvailableMachineTypes returns the machine types of the given list that EC2 offers in all
+// the given availability zones, or in any zone of the region if no zones are given. If the
+// offerings can't be retrieved the list is returned unchanged, as the service checks them anyway.
+func filterAvailableMachineTypes(r *runtime.Runtime, awsClient aws.Client, machineTypeList []string,
+	zones []string) []string {
+	reporter := r.Reporter()
+	reporter.Debugf("Fetching instance types offered in region '%s'", awsClient.GetRegion())
+	offerings, err := awsClient.GetInstanceTypeZones()
+	if err != nil {
+		reporter.Warnf("Can't check which instance types are available: %v", err)
+		return machineTypeList
+	}
+	return machines.FilterAvailableList(machineTypeList, offerings, zones)
+}
@@ -0,0 +1,271 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oidcconfig
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"regexp"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/arguments"
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/ci"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm/oidcconfigs"
+	"github.com/openshift/moactl/pkg/ocm/roles"
+	"github.com/openshift/moactl/pkg/oidc"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var args struct {
+	managed          bool
+	installerRoleARN string
+	region           string
+	prefix           string
+	bucketName       string
+	reuseBucket      bool
+}
+
+var Cmd = &cobra.Command{
+	Use:     "oidc-config",
+	Aliases: []string{"oidcconfig"},
+	Short:   "Create OIDC configuration",
+	Long: "Create an OIDC configuration that can be reused by multiple clusters. Managed " +
+		"configurations are hosted by Red Hat, unmanaged configurations are hosted in an S3 " +
+		"bucket of your AWS account.",
+	Example: `  # Create a managed OIDC configuration
+  rosa create oidc-config
+
+  # Create an unmanaged OIDC configuration hosted in your AWS account
+  rosa create oidc-config --managed=false \
+    --installer-role-arn=arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role
+
+  # Create an unmanaged OIDC configuration in an existing bucket
+  rosa create oidc-config --managed=false --bucket-name=mybucket --reuse-bucket \
+    --installer-role-arn=arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role`,
+	PreRunE: arguments.CheckFlagGroups,
+	RunE:    run,
+}
+
+func init() {
+	roles.Require(Cmd, roles.None)
+
+	flags := Cmd.Flags()
+
+	flags.BoolVar(
+		&args.managed,
+		"managed",
+		true,
+		"Indicates whether the OIDC configuration is hosted by Red Hat or in your AWS account.",
+	)
+	flags.StringVar(
+		&args.installerRoleARN,
+		"installer-role-arn",
+		"",
+		"ARN of the installer role that will be used to read the private key of an unmanaged "+
+			"OIDC configuration.",
+	)
+	flags.StringVarP(
+		&args.region,
+		"region",
+		"r",
+		"",
+		"AWS region where the resources of an unmanaged OIDC configuration will be created "+
+			"(overrides the AWS_REGION environment variable).",
+	)
+	flags.StringVar(
+		&args.prefix,
+		"prefix",
+		"rosa",
+		"Prefix of the names of the S3 bucket and secret created for an unmanaged OIDC configuration.",
+	)
+	flags.StringVar(
+		&args.bucketName,
+		"bucket-name",
+		"",
+		"Name of the S3 bucket for an unmanaged OIDC configuration. If not specified a unique name "+
+			"starting with the prefix is generated.",
+	)
+	flags.BoolVar(
+		&args.reuseBucket,
+		"reuse-bucket",
+		false,
+		"Use the bucket given with '--bucket-name' if it already exists in your account, instead of "+
+			"failing. The policy of the bucket will be replaced to allow reading the OIDC documents.",
+	)
+	arguments.MarkFlagRequires(flags, "reuse-bucket", "bucket-name")
+}
+
+// The prefix is part of the bucket name, so it has to follow the S3 naming rules:
+var prefixRE = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,31}$`)
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	if args.managed {
+		for _, flag := range []string{"installer-role-arn", "region", "prefix", "bucket-name", "reuse-bucket"} {
+			if cmd.Flags().Changed(flag) {
+				return rosaerrors.Usagef("Option '--%s' can only be used with unmanaged OIDC configurations", flag)
+			}
+		}
+
+		reporter.Debugf("Creating managed OIDC configuration")
+		config, err := r.OCM().CreateOIDCConfig(&oidcconfigs.OIDCConfig{
+			Managed: true,
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to create OIDC configuration: %w", err)
+		}
+		printConfig(r, config)
+		return nil
+	}
+
+	if args.installerRoleARN == "" {
+		return rosaerrors.Usagef("Option '--installer-role-arn' is mandatory for unmanaged OIDC configurations")
+	}
+	if !prefixRE.MatchString(args.prefix) {
+		return fmt.Errorf(
+			"Prefix '%s' isn't valid: it must contain only lowercase letters, digits and dashes, "+
+				"and be at most 32 characters long",
+			args.prefix,
+		)
+	}
+
+	// Get AWS region
+	region, err := aws.GetRegion(args.region)
+	if err != nil {
+		return fmt.Errorf("Error getting region: %w", err)
+	}
+	awsClient := r.WithAWSRegion(region).AWSClient()
+
+	reporter.Debugf("Validating installer role '%s'", args.installerRoleARN)
+	err = awsClient.ValidateRoleARN(args.installerRoleARN, aws.InstallerPrincipal)
+	if err != nil {
+		return err
+	}
+
+	bucketName := args.bucketName
+	if bucketName == "" {
+		suffix, err := randomSuffix()
+		if err != nil {
+			return fmt.Errorf("Failed to generate bucket name: %w", err)
+		}
+		bucketName = fmt.Sprintf("%s-oidc-%s", args.prefix, suffix)
+	}
+	err = aws.ValidateBucketName(bucketName)
+	if err != nil {
+		return err
+	}
+
+	// Check the bucket before creating anything, so that nothing is left behind if it can't be
+	// used:
+	reporter.Debugf("Validating S3 bucket '%s'", bucketName)
+	bucketExists, err := awsClient.ValidateOIDCBucket(bucketName)
+	if err != nil {
+		return err
+	}
+	if bucketExists && !args.reuseBucket {
+		return rosaerrors.Conflictf(
+			"Bucket '%s' already exists. Use '--reuse-bucket' to use it for the OIDC configuration",
+			bucketName,
+		)
+	}
+	issuerURL := aws.OIDCIssuerURL(bucketName, region)
+
+	reporter.Infof("Generating signing keys")
+	keys, err := oidc.GenerateKeys()
+	if err != nil {
+		return fmt.Errorf("Failed to generate signing keys: %w", err)
+	}
+	discovery, err := oidc.DiscoveryDocument(issuerURL, aws.OIDCKeysPath)
+	if err != nil {
+		return fmt.Errorf("Failed to generate discovery document: %w", err)
+	}
+
+	if bucketExists {
+		reporter.Infof("Reusing S3 bucket '%s'", bucketName)
+		err = awsClient.PutOIDCBucketPolicy(bucketName)
+		if err != nil {
+			return fmt.Errorf("Failed to set policy of S3 bucket '%s': %w", bucketName, err)
+		}
+	} else {
+		reporter.Infof("Creating S3 bucket '%s'", bucketName)
+		err = awsClient.CreateOIDCBucket(bucketName)
+		if err != nil {
+			return fmt.Errorf("Failed to create S3 bucket '%s': %w", bucketName, err)
+		}
+	}
+	err = awsClient.UploadOIDCDocuments(bucketName, discovery, keys.JWKS)
+	if err != nil {
+		return fmt.Errorf("Failed to upload documents to S3 bucket '%s': %w", bucketName, err)
+	}
+
+	secretName := fmt.Sprintf("%s-private-key", bucketName)
+	reporter.Infof("Storing private key in secret '%s'", secretName)
+	secretARN, err := awsClient.CreateOIDCPrivateKeySecret(secretName, keys.PrivateKey)
+	if err != nil {
+		return fmt.Errorf("Failed to create secret '%s': %w", secretName, err)
+	}
+
+	reporter.Debugf("Creating unmanaged OIDC configuration")
+	config, err := r.OCM().CreateOIDCConfig(&oidcconfigs.OIDCConfig{
+		Managed:          false,
+		IssuerURL:        issuerURL,
+		SecretARN:        secretARN,
+		InstallerRoleARN: args.installerRoleARN,
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to create OIDC configuration: %w", err)
+	}
+	printConfig(r, config)
+
+	return nil
+}
+
+func printConfig(r *runtime.Runtime, config *oidcconfigs.OIDCConfig) {
+	r.Reporter().Infof("Created OIDC configuration '%s'", config.ID)
+	ci.RecordResource(&ci.Resource{Kind: "oidc-config", ID: config.ID})
+	fmt.Printf(""+
+		"ID:         %s\n"+
+		"Managed:    %t\n"+
+		"Issuer URL: %s\n",
+		config.ID,
+		config.Managed,
+		config.IssuerURL,
+	)
+	if config.SecretARN != "" {
+		fmt.Printf("Secret ARN: %s\n", config.SecretARN)
+	}
+}
+
+// randomSuffix returns a short random string that makes the bucket name globally unique.
+func randomSuffix() (string, error) {
+	const chars = "abcdefghijklmnopqrstuvwxyz0123456789"
+	suffix := make([]byte, 8)
+	for i := range suffix {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+		if err != nil {
+			return "", err
+		}
+		suffix[i] = chars[n.Int64()]
+	}
+	return string(suffix), nil
+}
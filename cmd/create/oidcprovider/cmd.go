@@ -0,0 +1,107 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oidcprovider
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/ci"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/confirm"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm/roles"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var Cmd = &cobra.Command{
+	Use:     "oidc-provider [ID|NAME]",
+	Aliases: []string{"oidcprovider"},
+	Short:   "Create OIDC provider for a cluster",
+	Long: "Create the IAM OIDC provider that allows the operators of a cluster that uses AWS STS to " +
+		"assume their roles with the tokens of their service accounts.",
+	Example: `  # Create the OIDC provider of a cluster named "mycluster"
+  rosa create oidc-provider --cluster=mycluster`,
+	RunE: run,
+}
+
+func init() {
+	roles.Require(Cmd, roles.None)
+
+	clusterprovider.UseKey(Cmd)
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	clusterKey, err := clusterprovider.GetKey(r, argv)
+	if err != nil {
+		return err
+	}
+
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+	sts, err := clusterprovider.GetSTS(r.OCM(), cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get STS details of cluster '%s': %w", clusterKey, err)
+	}
+	if sts == nil {
+		return rosaerrors.Conflictf("Cluster '%s' doesn't use AWS STS", clusterKey)
+	}
+	if sts.OIDCEndpointURL == "" {
+		return rosaerrors.Conflictf("Cluster '%s' doesn't have an OIDC endpoint yet, try again in a few minutes", clusterKey)
+	}
+
+	awsClient := r.AWSClient()
+	reporter.Debugf("Finding OIDC provider of issuer '%s'", sts.OIDCEndpointURL)
+	providerARN, err := awsClient.GetOIDCProvider(sts.OIDCEndpointURL)
+	if err != nil {
+		return fmt.Errorf("Failed to find OIDC provider of cluster '%s': %w", clusterKey, err)
+	}
+	if providerARN != "" {
+		reporter.Infof("Cluster '%s' already has OIDC provider '%s'", clusterKey, providerARN)
+		return nil
+	}
+
+	reporter.Debugf("Getting thumbprint of issuer '%s'", sts.OIDCEndpointURL)
+	thumbprint, err := aws.OIDCThumbprint(sts.OIDCEndpointURL)
+	if err != nil {
+		return err
+	}
+
+	if !confirm.Confirm("create the OIDC provider of cluster '%s' for issuer '%s'",
+		clusterKey, sts.OIDCEndpointURL) {
+		return nil
+	}
+
+	reporter.Infof("Creating OIDC provider for issuer '%s'", sts.OIDCEndpointURL)
+	providerARN, err = awsClient.CreateOIDCProvider(sts.OIDCEndpointURL, thumbprint)
+	if err != nil {
+		return fmt.Errorf("Failed to create OIDC provider of cluster '%s': %w", clusterKey, err)
+	}
+	ci.RecordResource(&ci.Resource{Kind: "oidc-provider", Cluster: cluster.ID(), ID: providerARN})
+	reporter.Infof("Created OIDC provider '%s'. To create the operator roles of the cluster run "+
+		"'rosa create operator-roles -c %s'", providerARN, clusterKey)
+
+	return nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operatorroles
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/iamsettings"
+	"github.com/openshift/moactl/pkg/ci"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/confirm"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm/roles"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var Cmd = &cobra.Command{
+	Use:     "operator-roles [ID|NAME]",
+	Aliases: []string{"operatorroles", "operator-role"},
+	Short:   "Create operator roles for a cluster",
+	Long: "Create the IAM roles that the operators of a cluster that uses AWS STS assume. The roles " +
+		"have the names given when the cluster was created, and they trust the OIDC provider of the " +
+		"cluster, that has to be created first with 'rosa create oidc-provider'. The roles have the " +
+		"path of the ARNs given when the cluster was created.",
+	Example: `  # Create the operator roles of a cluster named "mycluster"
+  rosa create operator-roles --cluster=mycluster`,
+	RunE: run,
+}
+
+func init() {
+	roles.Require(Cmd, roles.None)
+
+	clusterprovider.UseKey(Cmd)
+
+	iamsettings.AddPermissionsBoundaryFlag(Cmd.Flags())
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	settings := iamsettings.Settings()
+	err := aws.ValidateIAMSettings(settings)
+	if err != nil {
+		return err
+	}
+
+	clusterKey, err := clusterprovider.GetKey(r, argv)
+	if err != nil {
+		return err
+	}
+
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+	sts, err := clusterprovider.GetSTS(r.OCM(), cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get STS details of cluster '%s': %w", clusterKey, err)
+	}
+	if sts == nil {
+		return rosaerrors.Conflictf("Cluster '%s' doesn't use AWS STS", clusterKey)
+	}
+	if sts.OIDCEndpointURL == "" {
+		return rosaerrors.Conflictf("Cluster '%s' doesn't have an OIDC endpoint yet, try again in a few minutes", clusterKey)
+	}
+
+	awsClient := r.AWSClient()
+	reporter.Debugf("Finding OIDC provider of issuer '%s'", sts.OIDCEndpointURL)
+	providerARN, err := awsClient.GetOIDCProvider(sts.OIDCEndpointURL)
+	if err != nil {
+		return fmt.Errorf("Failed to find OIDC provider of cluster '%s': %w", clusterKey, err)
+	}
+	if providerARN == "" {
+		return rosaerrors.NotFoundf("Cluster '%s' doesn't have an OIDC provider. To create it run "+
+			"'rosa create oidc-provider -c %s'", clusterKey, clusterKey)
+	}
+
+	if !confirm.Confirm("create the operator roles of cluster '%s'", clusterKey) {
+		return nil
+	}
+
+	for _, role := range sts.OperatorRoles {
+		operator := aws.FindOperatorRole(role.Namespace, role.Name)
+		if operator == nil {
+			reporter.Warnf("Skipping role '%s' of unknown operator secret '%s/%s'",
+				role.RoleARN, role.Namespace, role.Name)
+			continue
+		}
+		parsed, err := arn.Parse(role.RoleARN)
+		if err != nil {
+			return rosaerrors.Usagef("Role ARN '%s' of cluster '%s' isn't valid: %w", role.RoleARN, clusterKey, err)
+		}
+		roleName := parsed.Resource[strings.LastIndex(parsed.Resource, "/")+1:]
+		settings.Path, err = aws.IAMPath(role.RoleARN)
+		if err != nil {
+			return rosaerrors.Usagef("Role ARN '%s' of cluster '%s' isn't valid: %w", role.RoleARN, clusterKey, err)
+		}
+		reporter.Infof("Creating role '%s' for operator '%s'", roleName, role.Namespace)
+		roleARN, err := awsClient.CreateOperatorRole(*operator, roleName, cluster.ID(), providerARN,
+			sts.OIDCEndpointURL, settings)
+		if err != nil {
+			return fmt.Errorf("Failed to create role '%s': %w", roleName, err)
+		}
+		ci.RecordResource(&ci.Resource{Kind: "operator-role", Cluster: cluster.ID(), ID: roleARN, Name: roleName})
+		fmt.Printf("%s\n", roleARN)
+	}
+	reporter.Infof("Created operator roles of cluster '%s'", clusterKey)
+
+	return nil
+}
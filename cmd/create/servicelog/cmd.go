@@ -0,0 +1,148 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicelog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+	"github.com/spf13/cobra"
+
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/confirm"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// defaultServiceName is the name of the service recorded in the entries.
+const defaultServiceName = "SREManualAction"
+
+var args struct {
+	summary      string
+	description  string
+	severity     string
+	serviceName  string
+	internalOnly bool
+}
+
+var Cmd = &cobra.Command{
+	Use:     "service-log",
+	Aliases: []string{"servicelog"},
+	Short:   "Add an entry to the service log of a cluster",
+	Long: "Add an entry to the service log of a cluster, for example to notify its owners of a " +
+		"maintenance. Only privileged accounts, like those of SRE, are allowed to add entries.",
+	Example: `  # Notify the owners of a cluster named "mycluster" of a maintenance
+  rosa create service-log --cluster=mycluster --severity=warning \
+    --summary="Scheduled maintenance" \
+    --description="The ingress controller will be restarted on Saturday at 10:00 UTC."`,
+	Args: cobra.NoArgs,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	clusterprovider.UseKey(Cmd)
+
+	flags.StringVar(
+		&args.summary,
+		"summary",
+		"",
+		"Short summary of the entry.",
+	)
+	flags.StringVar(
+		&args.description,
+		"description",
+		"",
+		"Complete description of the entry.",
+	)
+	flags.StringVar(
+		&args.severity,
+		"severity",
+		"Info",
+		fmt.Sprintf("Severity of the entry: '%s'.", strings.Join(ocm.ServiceLogSeverities, "', '")),
+	)
+	flags.StringVar(
+		&args.serviceName,
+		"service-name",
+		defaultServiceName,
+		"Name of the service that the entry comes from.",
+	)
+	flags.BoolVar(
+		&args.internalOnly,
+		"internal-only",
+		false,
+		"Make the entry visible only to Red Hat, not to the owners of the cluster.",
+	)
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	if args.summary == "" {
+		return rosaerrors.Usagef("Option '--summary' is mandatory")
+	}
+	severity, err := ocm.ValidateServiceLogSeverity(args.severity)
+	if err != nil {
+		return err
+	}
+
+	clusterKey, err := clusterprovider.GetKey(r, nil)
+	if err != nil {
+		return err
+	}
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+	if cluster.ExternalID() == "" {
+		return rosaerrors.NotFoundf("Cluster '%s' doesn't have a service log yet", clusterKey)
+	}
+
+	entry, err := slv1.NewLogEntry().
+		ClusterUUID(cluster.ExternalID()).
+		Summary(args.summary).
+		Description(args.description).
+		Severity(slv1.Severity(severity)).
+		ServiceName(args.serviceName).
+		InternalOnly(args.internalOnly).
+		Timestamp(time.Now()).
+		Build()
+	if err != nil {
+		return fmt.Errorf("Failed to build service log entry: %w", err)
+	}
+
+	if !confirm.Confirm("add entry '%s' to the service log of cluster %s", args.summary, clusterKey) {
+		return nil
+	}
+
+	reporter.Debugf("Adding entry to the service log of cluster '%s'", clusterKey)
+	_, err = r.OCM().CreateServiceLog(entry)
+	if err != nil {
+		return fmt.Errorf("Failed to add entry to the service log of cluster '%s': %w", clusterKey, err)
+	}
+	reporter.Infof("Entry has been added to the service log of cluster '%s'", clusterKey)
+
+	return nil
+}
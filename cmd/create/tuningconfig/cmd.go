@@ -0,0 +1,149 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tuningconfig
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/ci"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/ocm/tuningconfigs"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// Regular expression used to make sure that the name given by the user is safe:
+var nameRE = regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
+
+var args struct {
+	name     string
+	specPath string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "tuning-config",
+	Aliases: []string{"tuningconfig", "tuning-configs", "tuningconfigs"},
+	Short:   "Create tuning configuration",
+	Long: "Create a tuning configuration for a cluster. The specification is the 'spec' of a Node " +
+		"Tuning Operator 'Tuned' object, and is applied to the nodes of the machine pools that the " +
+		"configuration is attached to with 'rosa edit machinepool --tuning-configs'.",
+	Example: `  # Create a tuning configuration from the specification in file 'tuned.yaml'
+  rosa create tuning-config --cluster=mycluster --name=sysctl --spec-path=tuned.yaml
+
+  # Use it in machine pool 'mp1'
+  rosa edit machinepool --cluster=mycluster --tuning-configs=sysctl mp1`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	clusterprovider.UseKey(Cmd)
+
+	flags.StringVar(
+		&args.name,
+		"name",
+		"",
+		"Name of the tuning configuration (required).",
+	)
+
+	flags.StringVar(
+		&args.specPath,
+		"spec-path",
+		"",
+		"Path of a JSON or YAML file containing the specification of the tuning configuration (required).",
+	)
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	clusterKey, err := clusterprovider.GetKey(r, nil)
+	if err != nil {
+		return err
+	}
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	if cluster.State() != cmv1.ClusterStateReady {
+		return rosaerrors.Conflictf("Cluster '%s' is not yet ready", clusterKey)
+	}
+
+	name := args.name
+	if interactive.Enabled() || name == "" {
+		name, err = interactive.GetString(interactive.Input{
+			Question: "Tuning configuration name",
+			Help:     cmd.Flags().Lookup("name").Usage,
+			Default:  name,
+			Required: true,
+		})
+		if err != nil {
+			return rosaerrors.Usagef("Expected a valid name: %w", err)
+		}
+	}
+	if !nameRE.MatchString(name) {
+		return rosaerrors.Usagef("Expected a valid name for the tuning configuration")
+	}
+
+	specPath := args.specPath
+	if interactive.Enabled() || specPath == "" {
+		specPath, err = interactive.GetString(interactive.Input{
+			Question: "Specification file path",
+			Help:     cmd.Flags().Lookup("spec-path").Usage,
+			Default:  specPath,
+			Required: true,
+		})
+		if err != nil {
+			return rosaerrors.Usagef("Expected a valid file path: %w", err)
+		}
+	}
+	data, err := ioutil.ReadFile(specPath)
+	if err != nil {
+		return fmt.Errorf("Failed to read file '%s': %w", specPath, err)
+	}
+	spec, err := tuningconfigs.ParseSpec(data)
+	if err != nil {
+		return err
+	}
+
+	reporter.Debugf("Creating tuning configuration '%s' on cluster '%s'", name, clusterKey)
+	config, err := r.OCM().CreateTuningConfig(cluster.ID(), &tuningconfigs.TuningConfig{
+		Name: name,
+		Spec: spec,
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to create tuning configuration '%s' on cluster '%s': %w",
+			name, clusterKey, err)
+	}
+	ci.RecordResource(&ci.Resource{Kind: "tuning-config", Cluster: cluster.ID(), ID: config.ID, Name: name})
+	reporter.Infof("Tuning configuration '%s' has been created on cluster '%s'. Attach it to machine "+
+		"pools with 'rosa edit machinepool --tuning-configs=%s'", name, clusterKey, name)
+
+	return nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accountroles
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/output"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/table"
+)
+
+var args struct {
+	prefix string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "account-roles",
+	Aliases: []string{"accountroles", "account-role"},
+	Short:   "Show the account roles",
+	Long: "Show the IAM roles that are shared by all the clusters of the AWS account, with the managed " +
+		"policies attached to them, and check that their policies have the version that this version " +
+		"of the tool expects.",
+	Example: `  # Show the account roles with the default prefix
+  rosa describe account-roles
+
+  # Show the account roles with a custom prefix
+  rosa describe account-roles --prefix=MyOrg`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVar(
+		&args.prefix,
+		"prefix",
+		aws.DefaultAccountRolePrefix,
+		"Prefix of the names of the account roles.",
+	)
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	err := output.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = aws.ValidateAccountRolePrefix(args.prefix)
+	if err != nil {
+		return err
+	}
+
+	reporter.Debugf("Loading account roles with prefix '%s'", args.prefix)
+	roles, err := r.AWSClient().GetAccountRoles(args.prefix)
+	if err != nil {
+		return fmt.Errorf("Failed to get account roles: %w", err)
+	}
+	if output.Structured() {
+		err = output.PrintValue(roles)
+		if err != nil {
+			return fmt.Errorf("Failed to print account roles: %w", err)
+		}
+		return nil
+	}
+	if len(roles) == 0 {
+		reporter.Infof("There are no account roles with prefix '%s'", args.prefix)
+		return nil
+	}
+
+	writer := table.NewWriter(os.Stdout)
+	fmt.Fprintf(writer, "TYPE\tROLE NAME\tCREATED\tVERSION\tSTATUS\n")
+	outdated := 0
+	for _, role := range roles {
+		version := role.Version
+		if version == "" {
+			version = "unknown"
+		}
+		status := "up to date"
+		if !role.UpToDate() {
+			status = fmt.Sprintf("expected version %s", aws.AccountRoleVersion)
+			outdated++
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n",
+			role.Type, role.Name, role.Created.Format("Jan _2 2006 15:04:05 MST"), version, status)
+	}
+	writer.Flush()
+
+	fmt.Println()
+	fmt.Fprintf(writer, "ROLE NAME\tPOLICY\tPOLICY VERSION\tARN\n")
+	for _, role := range roles {
+		if len(role.Policies) == 0 {
+			fmt.Fprintf(writer, "%s\t-\t-\t-\n", role.Name)
+		}
+		for _, policy := range role.Policies {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", role.Name, policy.Name, policy.DefaultVersion, policy.ARN)
+		}
+	}
+	writer.Flush()
+
+	missing := len(aws.AccountRoleTypes) - len(roles)
+	if missing > 0 || outdated > 0 {
+		fmt.Println()
+	}
+	if missing > 0 {
+		reporter.Warnf("%d of the %d account roles with prefix '%s' don't exist",
+			missing, len(aws.AccountRoleTypes), args.prefix)
+	}
+	if outdated > 0 {
+		reporter.Warnf("The policies of %d account roles don't have the version %s that this version "+
+			"of the tool expects", outdated, aws.AccountRoleVersion)
+	}
+
+	return nil
+}
@@ -17,16 +17,18 @@ limitations under the License.
 package addon
 
 import (
+	"encoding/json"
 	"fmt"
-	"os"
+	"io"
 	"regexp"
 	"strings"
 
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/moactl/pkg/logging"
-	"github.com/openshift/moactl/pkg/ocm"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/output"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
 var Cmd = &cobra.Command{
@@ -34,54 +36,50 @@ var Cmd = &cobra.Command{
 	Aliases: []string{"add-on"},
 	Hidden:  true,
 	Short:   "Show details of an add-on",
-	Long:    "Show details of an add-on",
+	Long: "Show details of an add-on, including the parameters that can be given when it is " +
+		"installed and the requirements that the cluster must meet.",
 	Example: `  # Describe an add-on named "codeready-workspaces"
   rosa describe addon codeready-workspaces`,
-	Run: run,
+	RunE: run,
 }
 
-func run(_ *cobra.Command, argv []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	err := output.Validate()
+	if err != nil {
+		return err
+	}
 
 	// Check command line arguments:
 	if len(argv) != 1 {
-		reporter.Errorf(
+		return rosaerrors.Usagef(
 			"Expected exactly one command line argument or flag containing the identifier of the add-on",
 		)
-		os.Exit(1)
 	}
 	addOnID := argv[0]
 
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
-	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
-
-	// Get the client for the OCM collection of add-ons:
-	addOnsCollection := ocmConnection.ClustersMgmt().V1().Addons()
-
 	// Try to find the add-on:
 	reporter.Debugf("Loading add-on '%s'", addOnID)
-	addOn, err := ocm.GetAddOn(addOnsCollection, addOnID)
+	addOn, err := r.OCM().GetAddOn(addOnID)
 	if err != nil {
-		reporter.Errorf("Failed to get add-on '%s': %s\n"+
+		return fmt.Errorf("Failed to get add-on '%s': %w\n"+
 			"Try running 'rosa list addons' to see all available add-ons.",
 			addOnID, err)
-		os.Exit(1)
 	}
 
 	// Print add-on description:
+	if output.Structured() {
+		err = output.Print(func(writer io.Writer) error {
+			return cmv1.MarshalAddOn(addOn, writer)
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to print add-on '%s': %w", addOnID, err)
+		}
+		return nil
+	}
+
 	fmt.Printf(""+
 		"ID:               %s\n"+
 		"Name:             %s\n"+
@@ -99,6 +97,60 @@ func run(_ *cobra.Command, argv []string) {
 		addOn.InstallMode(),
 	)
 	fmt.Println()
+
+	// The parameters and requirements are printed so that the add-on can be installed without
+	// prompts, knowing in advance which values are accepted:
+	reporter.Debugf("Loading parameters and requirements of add-on '%s'", addOnID)
+	schema, err := r.OCM().GetAddOnSchema(addOn.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get parameters of add-on '%s': %w", addOnID, err)
+	}
+	if len(schema.Parameters) > 0 {
+		fmt.Printf("Parameters:\n")
+		for _, parameter := range schema.Parameters {
+			fmt.Printf(""+
+				"  ID:             %s\n"+
+				"  Name:           %s\n"+
+				"  Description:    %s\n"+
+				"  Type:           %s\n"+
+				"  Required:       %t\n"+
+				"  Editable:       %t\n",
+				parameter.ID,
+				parameter.Name,
+				parameter.Description,
+				parameter.ValueType,
+				parameter.Required,
+				parameter.Editable,
+			)
+			if parameter.DefaultValue != "" {
+				fmt.Printf("  Default:        %s\n", parameter.DefaultValue)
+			}
+			if parameter.Validation != "" {
+				fmt.Printf("  Validation:     %s\n", parameter.Validation)
+			}
+			if len(parameter.Options) > 0 {
+				options := make([]string, len(parameter.Options))
+				for i, option := range parameter.Options {
+					options[i] = option.Value
+				}
+				fmt.Printf("  Options:        %s\n", strings.Join(options, ", "))
+			}
+			fmt.Println()
+		}
+	}
+	if len(schema.Requirements) > 0 {
+		fmt.Printf("Requirements:\n")
+		for _, requirement := range schema.Requirements {
+			data, err := json.Marshal(requirement.Data)
+			if err != nil {
+				return fmt.Errorf("Failed to print requirement '%s': %w", requirement.ID, err)
+			}
+			fmt.Printf("  %s (%s): %s\n", requirement.ID, requirement.Resource, data)
+		}
+		fmt.Println()
+	}
+
+	return nil
 }
 
 func wrapText(text string) string {
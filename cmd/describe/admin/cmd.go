@@ -17,130 +17,95 @@ limitations under the License.
 package admin
 
 import (
-	"os"
+	"fmt"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/moactl/pkg/aws"
-	"github.com/openshift/moactl/pkg/logging"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/ocm"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/output"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
-const (
-	idpName = "Cluster-Admin"
-	// username = "cluster-admin"
-)
-
-var args struct {
-	clusterKey string
-}
-
 var Cmd = &cobra.Command{
 	Use:   "admin [ID|NAME]",
 	Short: "Show details of the cluster-admin user",
 	Long:  "Show details of the cluster-admin user and a command to login to the cluster",
 	Example: `  # Describe cluster-admin user of a cluster named mycluster
   rosa describe admin -c mycluster`,
-	Run: run,
+	RunE: run,
 }
 
 func init() {
-	flags := Cmd.Flags()
-
-	flags.StringVarP(
-		&args.clusterKey,
-		"cluster",
-		"c",
-		"",
-		"Name or ID of the cluster that cluster-admin belongs to.",
-	)
-	Cmd.MarkFlagRequired("cluster")
+	clusterprovider.UseKey(Cmd)
 }
 
-func run(cmd *cobra.Command, _ []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
-
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
-	clusterKey := args.clusterKey
-	if !ocm.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
-	}
+// admin is the structured representation of the cluster-admin user of a cluster.
+type admin struct {
+	Exists           bool   `json:"exists"`
+	IdentityProvider string `json:"identity_provider,omitempty"`
+	Username         string `json:"username,omitempty"`
+	APIURL           string `json:"api_url"`
+}
 
-	// Create the AWS client:
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
-	}
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
 
-	awsCreator, err := awsClient.GetCreator()
+	err := output.Validate()
 	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		return err
 	}
 
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
+	clusterKey, err := clusterprovider.GetKey(r, nil)
 	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		return err
 	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
-
-	// Get the client for the OCM collection of clusters:
-	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
 
 	// Try to find the cluster:
 	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
 	if err != nil {
-		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
 	}
 
 	if cluster.State() != cmv1.ClusterStateReady {
-		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
-		os.Exit(1)
+		return rosaerrors.Conflictf("Cluster '%s' is not yet ready", clusterKey)
 	}
 
 	// Try to find the htpasswd identity provider:
-	reporter.Debugf("Loading '%s' identity provider", idpName)
-	idps, err := ocm.GetIdentityProviders(clustersCollection, cluster.ID())
+	reporter.Debugf("Loading '%s' identity provider", ocm.AdminIdentityProviderName)
+	idp, err := r.OCM().GetAdminIdentityProvider(cluster.ID())
 	if err != nil {
-		reporter.Errorf("Failed to get '%s' identity provider for cluster '%s': %v", idpName, clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get '%s' identity provider for cluster '%s': %w",
+			ocm.AdminIdentityProviderName, clusterKey, err)
 	}
-
-	var idp *cmv1.IdentityProvider
-	for _, item := range idps {
-		if ocm.IdentityProviderType(item) == "htpasswd" {
-			idp = item
+	if output.Structured() {
+		value := &admin{
+			Exists: idp != nil && idp.Htpasswd() != nil,
+			APIURL: cluster.API().URL(),
+		}
+		if value.Exists {
+			value.IdentityProvider = idp.Name()
+			value.Username = idp.Htpasswd().Username()
 		}
+		err = output.PrintValue(value)
+		if err != nil {
+			return fmt.Errorf("Failed to print admin of cluster '%s': %w", clusterKey, err)
+		}
+		return nil
 	}
+
 	if idp == nil || idp.Htpasswd() == nil {
 		reporter.Warnf("There is no admin on cluster '%s'. To create it run the following command:\n"+
 			"   rosa create admin -c %s", clusterKey, clusterKey)
-		os.Exit(0)
+		return nil
 	}
 
 	reporter.Infof("There is an admin on cluster '%s'. To login, run the following command:\n"+
 		"   oc login %s --username %s", clusterKey, cluster.API().URL(), idp.Htpasswd().Username())
+
+	return nil
 }
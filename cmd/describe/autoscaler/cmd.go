@@ -0,0 +1,121 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/output"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "autoscaler",
+	Short: "Show details of the cluster autoscaler",
+	Long: "Show the options of the cluster autoscaler, which adds and removes nodes of the " +
+		"machine pools that have autoscaling enabled.",
+	Example: `  # Describe the autoscaler of a cluster named "mycluster"
+  rosa describe autoscaler --cluster=mycluster`,
+	RunE: run,
+}
+
+func init() {
+	clusterprovider.UseKey(Cmd)
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	err := output.Validate()
+	if err != nil {
+		return err
+	}
+
+	clusterKey, err := clusterprovider.GetKey(r, nil)
+	if err != nil {
+		return err
+	}
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	reporter.Debugf("Loading autoscaler of cluster '%s'", clusterKey)
+	autoscaler, err := r.OCM().GetAutoscaler(cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get autoscaler of cluster '%s': %w", clusterKey, err)
+	}
+
+	if output.Structured() {
+		err = output.PrintValue(autoscaler)
+		if err != nil {
+			return fmt.Errorf("Failed to print autoscaler of cluster '%s': %w", clusterKey, err)
+		}
+		return nil
+	}
+
+	if autoscaler == nil {
+		reporter.Infof("Cluster '%s' uses the default autoscaler options. To change them run "+
+			"'rosa edit autoscaler -c %s'", clusterKey, clusterKey)
+		return nil
+	}
+
+	threshold := ""
+	if autoscaler.ScaleDown != nil {
+		threshold = autoscaler.ScaleDown.UtilizationThreshold
+	}
+	maxNodesTotal := ""
+	cores := ""
+	memory := ""
+	if limits := autoscaler.ResourceLimits; limits != nil {
+		if limits.MaxNodesTotal > 0 {
+			maxNodesTotal = fmt.Sprintf("%d", limits.MaxNodesTotal)
+		}
+		if limits.Cores != nil {
+			cores = fmt.Sprintf("%d-%d", limits.Cores.Min, limits.Cores.Max)
+		}
+		if limits.Memory != nil {
+			memory = fmt.Sprintf("%d-%d GiB", limits.Memory.Min, limits.Memory.Max)
+		}
+	}
+
+	fmt.Printf(""+
+		"Cluster:                           %s\n"+
+		"Scale down utilization threshold:  %s\n"+
+		"Max node provision time:           %s\n"+
+		"Balance similar node groups:       %t\n"+
+		"Max nodes total:                   %s\n"+
+		"Cores:                             %s\n"+
+		"Memory:                            %s\n",
+		clusterKey,
+		threshold,
+		autoscaler.MaxNodeProvisionTime,
+		autoscaler.BalanceSimilarNodeGroups,
+		maxNodesTotal,
+		cores,
+		memory,
+	)
+
+	return nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/output"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// describeAt prints what the given cluster looked like at the given time.
+func describeAt(r *runtime.Runtime, cluster *cmv1.Cluster, at time.Time) error {
+	reporter := r.Reporter()
+
+	reporter.Debugf("Loading service log of cluster '%s'", cluster.Name())
+	entries, err := r.OCM().GetServiceLogs(cluster.ExternalID())
+	if err != nil {
+		return fmt.Errorf("Failed to get service log of cluster '%s': %w", cluster.Name(), err)
+	}
+	reporter.Debugf("Loading upgrade policies of cluster '%s'", cluster.Name())
+	policies, err := r.OCM().GetUpgradePolicies(cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get upgrade policies of cluster '%s': %w", cluster.Name(), err)
+	}
+
+	snapshot, err := clusterprovider.Reconstruct(cluster, entries, policies, at)
+	if err != nil {
+		return fmt.Errorf("Failed to describe cluster '%s' at %s: %w",
+			cluster.Name(), at.Format(time.RFC3339), err)
+	}
+
+	if output.Structured() {
+		err = output.PrintValue(snapshot)
+		if err != nil {
+			return fmt.Errorf("Failed to print cluster '%s': %w", cluster.Name(), err)
+		}
+		return nil
+	}
+
+	version := snapshot.Version
+	if version == "" {
+		version = "Unknown"
+	}
+	compute := fmt.Sprintf("%d", snapshot.Compute)
+	if contains(snapshot.Unknown, "compute nodes") {
+		compute = "Unknown"
+	}
+	fmt.Printf(""+
+		"Name:                       %s\n"+
+		"ID:                         %s\n"+
+		"Time:                       %s\n"+
+		"State:                      %s\n"+
+		"Version:                    %s\n"+
+		"Nodes:                      Master: %d, Infra: %d, Compute: %s\n",
+		cluster.Name(),
+		cluster.ID(),
+		snapshot.Time.Format("Jan _2 2006 15:04:05 MST"),
+		snapshot.State,
+		version,
+		snapshot.Master, snapshot.Infra, compute,
+	)
+	if len(snapshot.Events) > 0 {
+		fmt.Printf("History:\n")
+		for _, event := range snapshot.Events {
+			fmt.Printf("  %s  %s\n", event.Time.Format("Jan _2 2006 15:04:05 MST"), event.Summary)
+		}
+	}
+	fmt.Println()
+
+	if len(snapshot.Unknown) > 0 {
+		reporter.Warnf("The history of the cluster isn't enough to know its %s at that time",
+			strings.Join(snapshot.Unknown, " and "))
+	}
+	reporter.Infof("Fields without recorded changes since that time show their current value")
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -18,18 +18,28 @@ package cluster
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/spf13/cobra"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+
+	"github.com/openshift/moactl/pkg/arguments"
 	"github.com/openshift/moactl/pkg/aws"
 	clusterprovider "github.com/openshift/moactl/pkg/cluster"
-	"github.com/openshift/moactl/pkg/logging"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/iac"
 	"github.com/openshift/moactl/pkg/ocm"
 	"github.com/openshift/moactl/pkg/ocm/properties"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/ocm/upgrades"
+	"github.com/openshift/moactl/pkg/output"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/table"
 )
 
 const (
@@ -40,107 +50,142 @@ const (
 )
 
 var args struct {
-	clusterKey string
+	endpoints bool
+	at        string
+	export    string
 }
 
 var Cmd = &cobra.Command{
 	Use:   "cluster [ID|NAME]",
 	Short: "Show details of a cluster",
-	Long:  "Show details of a cluster",
+	Long: "Show details of a cluster: its state and the reason of install failures, its URLs, " +
+		"network, machine pools, identity providers, upgrade policy and recent events. With " +
+		"'--output json' the complete document of the cluster returned by the API is printed.",
 	Example: `  # Describe a cluster named "mycluster"
   rosa describe cluster mycluster
 
   # Describe a cluster using the --cluster flag
-  rosa describe cluster --cluster=mycluster`,
-	Run: run,
+  rosa describe cluster --cluster=mycluster
+
+  # Export a cluster with its account identifiers, ARNs and domains masked, for bug reports
+  rosa describe cluster mycluster -o redacted-yaml
+
+  # Show the endpoints and DNS records of a cluster named "mycluster"
+  rosa describe cluster mycluster --endpoints
+
+  # Show the version, nodes and state that a cluster named "mycluster" had at a point in time
+  rosa describe cluster mycluster --at=2021-03-01T12:00:00Z
+
+  # Export a cluster named "mycluster" with its machine pools and identity providers as Terraform
+  rosa describe cluster mycluster --export terraform > mycluster.tf`,
+	PreRunE: arguments.CheckFlagGroups,
+	RunE:    run,
 }
 
 func init() {
 	flags := Cmd.Flags()
 
-	flags.StringVarP(
-		&args.clusterKey,
-		"cluster",
-		"c",
+	clusterprovider.UseKey(Cmd)
+
+	flags.BoolVar(
+		&args.endpoints,
+		"endpoints",
+		false,
+		"Show the endpoints of the cluster and the Route53 records created for it, "+
+			"for example to configure firewalls or external DNS.",
+	)
+
+	flags.StringVar(
+		&args.at,
+		"at",
 		"",
-		"Name or ID of the cluster to describe.",
+		"Show the version, nodes and state that the cluster had at the given time, in RFC3339 "+
+			"format, reconstructed from its upgrade history and service log.",
 	)
+
+	flags.StringVar(
+		&args.export,
+		"export",
+		"",
+		fmt.Sprintf("Print the configuration of the cluster, its machine pools and its identity "+
+			"providers as infrastructure as code, to manage the cluster with other tools. "+
+			"Secrets aren't exported. The resources have the settings of the files of "+
+			"'rosa create cluster --file', so applying them requires a Terraform provider or "+
+			"CloudFormation resource types that implement them. Valid formats are '%s'.",
+			strings.Join(iac.Formats, "', '")),
+	)
+	arguments.MarkFlagsMutuallyExclusive(flags, "endpoints", "at", "export")
 }
 
-func run(_ *cobra.Command, argv []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
-
-	// Check command line arguments:
-	clusterKey := args.clusterKey
-	if clusterKey == "" {
-		if len(argv) != 1 {
-			reporter.Errorf(
-				"Expected exactly one command line argument or flag containing the name " +
-					"or identifier of the cluster",
-			)
-			os.Exit(1)
-		}
-		clusterKey = argv[0]
-	}
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
 
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
-	if !clusterprovider.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
-	}
-
-	// Create the AWS client:
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Build()
+	err := output.Validate(output.RedactedYAML)
 	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		return rosaerrors.Wrap(rosaerrors.ExitUsage, err)
 	}
 
-	awsCreator, err := awsClient.GetCreator()
-	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+	if args.export != "" && cmd.Flags().Changed("output") {
+		return rosaerrors.Usagef("Option '--export' can't be used with '--output'")
 	}
-
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
-
-	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+	if args.export != "" && !validExportFormat(args.export) {
+		return rosaerrors.Usagef("Export format '%s' isn't valid, it must be '%s'", args.export,
+			strings.Join(iac.Formats, "' or '"))
 	}
-	defer func() {
-		err = ocmConnection.Close()
+
+	var at time.Time
+	if args.at != "" {
+		at, err = parseRFC3339(args.at)
 		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
+			return rosaerrors.Usagef("Failed to parse time '%s': %v", args.at, err)
 		}
-	}()
+	}
 
-	// Get the client for the OCM collection of clusters:
-	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
+	clusterKey, err := clusterprovider.GetKey(r, argv)
+	if err != nil {
+		return err
+	}
 
 	// Try to find the cluster:
 	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := clusterprovider.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
 	if err != nil {
-		reporter.Errorf(fmt.Sprintf("Failed to get cluster '%s': %v", clusterKey, err))
-		os.Exit(1)
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	if args.endpoints {
+		return describeEndpoints(r, cluster)
+	}
+	if args.at != "" {
+		return describeAt(r, cluster, at)
+	}
+	if args.export != "" {
+		return export(r, cluster)
+	}
+
+	// The structured formats contain the complete document of the cluster, as returned by the
+	// API, including the fields that the SDK doesn't know. The redacted output is meant for bug
+	// reports:
+	if output.Structured() {
+		var document []byte
+		document, err = r.OCM().GetClusterDocument(cluster.ID())
+		if err != nil {
+			return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+		}
+		err = output.Print(func(writer io.Writer) error {
+			_, err := writer.Write(document)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to print cluster '%s': %w", clusterKey, err)
+		}
+		return nil
 	}
 
 	creatorARN, err := arn.Parse(cluster.Properties()[properties.CreatorARN])
 	if err != nil {
-		reporter.Errorf("Failed to parse creator ARN for cluster '%s'", clusterKey)
-		os.Exit(1)
+		return fmt.Errorf("Failed to parse creator ARN for cluster '%s'", clusterKey)
 	}
 	phase := ""
 
@@ -161,11 +206,20 @@ func run(_ *cobra.Command, argv []string) {
 		}
 	}
 
+	switch cluster.State() {
+	case ocm.ClusterStatePoweringDown:
+		phase = "(Stopping nodes for hibernation)"
+	case ocm.ClusterStateHibernating:
+		phase = "(Run 'rosa resume cluster' to start it again)"
+	case ocm.ClusterStateResuming:
+		phase = "(Starting nodes)"
+	}
+
 	clusterName := cluster.DisplayName()
 	if clusterName == "" {
 		clusterName = cluster.Name()
 	}
-	detailsPage := getDetailsLink(ocmConnection.URL())
+	detailsPage := getDetailsLink(r.OCM().URL())
 	// Print short cluster description:
 	str := fmt.Sprintf(""+
 		"Name:                       %s\n"+
@@ -177,7 +231,9 @@ func run(_ *cobra.Command, argv []string) {
 		"Console URL:                %s\n"+
 		"Nodes:                      Master: %d, Infra: %d, Compute: %d\n"+
 		"Region:                     %s\n"+
+		"Multi-AZ:                   %t\n"+
 		"State:                      %s %s\n"+
+		"OpenShift Version:          %s\n"+
 		"Channel Group:              %s\n"+
 		"Created:                    %s\n",
 		clusterName,
@@ -189,7 +245,9 @@ func run(_ *cobra.Command, argv []string) {
 		cluster.Console().URL(),
 		cluster.Nodes().Master(), cluster.Nodes().Infra(), cluster.Nodes().Compute(),
 		cluster.Region().ID(),
+		cluster.MultiAZ(),
 		cluster.State(), phase,
+		cluster.OpenshiftVersion(),
 		cluster.Version().ChannelGroup(),
 		cluster.CreationTimestamp().Format("Jan _2 2006 15:04:05 MST"),
 	)
@@ -199,7 +257,105 @@ func run(_ *cobra.Command, argv []string) {
 			"Details Page:               %s%s\n", str,
 			detailsPage, cluster.ID())
 	}
-	if cluster.Status().State() == cmv1.ClusterStateError {
+	if ocm.IsDeleteProtected(cluster) {
+		str = fmt.Sprintf("%s"+
+			"Delete Protection:          Enabled\n", str)
+	}
+	if cluster.EtcdEncryption() {
+		str = fmt.Sprintf("%s"+
+			"Etcd Encryption:            Enabled\n", str)
+	}
+	str += formatNetwork(cluster)
+
+	// The following fields come from sub-resources that are loaded separately. They are optional,
+	// so when they fail to load the rest of the description is still printed, followed by a
+	// warning for each missing field:
+	warnings := []string{}
+	reporter.Debugf("Loading PrivateLink setting of cluster '%s'", clusterKey)
+	privateLink, err := r.OCM().GetPrivateLink(cluster.ID())
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("Failed to get PrivateLink setting: %v", err))
+	} else if privateLink {
+		str = fmt.Sprintf("%s"+
+			"PrivateLink:                Yes\n", str)
+	}
+	reporter.Debugf("Loading KMS key of cluster '%s'", clusterKey)
+	kmsKeyARN, err := r.OCM().GetKMSKeyARN(cluster.ID())
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("Failed to get KMS key: %v", err))
+	} else if kmsKeyARN != "" {
+		str = fmt.Sprintf("%s"+
+			"KMS Key ARN:                %s\n", str, kmsKeyARN)
+	}
+	reporter.Debugf("Loading compliance settings of cluster '%s'", clusterKey)
+	compliance, err := r.OCM().GetCompliance(cluster.ID())
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("Failed to get compliance settings: %v", err))
+	} else {
+		if compliance.FIPS {
+			str = fmt.Sprintf("%s"+
+				"FIPS Mode:                  Enabled\n", str)
+		}
+		if compliance.DisableWorkloadMonitoring {
+			str = fmt.Sprintf("%s"+
+				"Workload Monitoring:        Disabled\n", str)
+		}
+	}
+	reporter.Debugf("Loading machine pools of cluster '%s'", clusterKey)
+	machinePools, err := r.OCM().GetMachinePools(cluster.ID())
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("Failed to get machine pools: %v", err))
+	} else {
+		str = fmt.Sprintf("%s"+
+			"Machine Pools:              %s\n", str, formatMachinePools(cluster, machinePools))
+	}
+	reporter.Debugf("Loading identity providers of cluster '%s'", clusterKey)
+	idps, err := r.OCM().GetIdentityProviders(cluster.ID())
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("Failed to get identity providers: %v", err))
+	} else {
+		str = fmt.Sprintf("%s"+
+			"Identity Providers:         %s\n", str, formatIdentityProviders(idps))
+	}
+	reporter.Debugf("Loading upgrade policies of cluster '%s'", clusterKey)
+	upgradePolicies, upgradesErr := r.OCM().GetUpgradePolicies(cluster.ID())
+	if upgradesErr != nil {
+		warnings = append(warnings, fmt.Sprintf("Failed to get upgrade policies: %v", upgradesErr))
+	} else {
+		str = fmt.Sprintf("%s"+
+			"Upgrade Policy:             %s\n", str,
+			formatUpgradePolicy(upgrades.FindAutomaticUpgrade(upgradePolicies)))
+	}
+	if cluster.State() == cmv1.ClusterStateReady {
+		upgrade := "None"
+		if upgradesErr != nil {
+			upgrade = "Unavailable"
+		} else if scheduledUpgrade := upgrades.FindScheduledUpgrade(upgradePolicies); scheduledUpgrade != nil {
+			// The state only adds detail to the description, so it is omitted if it can't be loaded:
+			state, err := r.OCM().GetUpgradeState(cluster.ID(), scheduledUpgrade.ID())
+			if err != nil {
+				reporter.Debugf("Failed to get state of scheduled upgrade: %v", err)
+			}
+			upgrade = upgrades.FormatScheduledUpgrade(scheduledUpgrade, state)
+		}
+		str = fmt.Sprintf("%s"+
+			"Scheduled Upgrade:          %s\n"+
+			"CPU Usage:                  %s\n"+
+			"Memory Usage:               %s\n",
+			str,
+			upgrade,
+			formatMetric(cluster.Metrics().CPU(), ""),
+			formatMetric(cluster.Metrics().Memory(), "GiB"),
+		)
+	}
+	reporter.Debugf("Loading status of cluster '%s'", clusterKey)
+	status, err := r.OCM().GetClusterStatus(cluster.ID())
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("Failed to get status: %v", err))
+	} else {
+		str += formatStatus(status)
+	}
+	if cluster.State() == cmv1.ClusterStateError || cluster.Status().State() == cmv1.ClusterStateError {
 		str = fmt.Sprintf("%s"+
 			"Provisioning Error Code:    %s\n"+
 			"Provisioning Error Message: %s\n",
@@ -208,9 +364,165 @@ func run(_ *cobra.Command, argv []string) {
 			cluster.Status().ProvisionErrorMessage(),
 		)
 	}
+	// The service log only exists once the cluster has been assigned an external identifier:
+	var events []*slv1.LogEntry
+	if cluster.ExternalID() != "" {
+		reporter.Debugf("Loading service log of cluster '%s'", clusterKey)
+		events, err = r.OCM().GetServiceLogs(cluster.ExternalID())
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Failed to get recent events: %v", err))
+		}
+	}
+
 	// Print short cluster description:
 	fmt.Print(str)
 	fmt.Println()
+	printRecentEvents(events)
+	for _, warning := range warnings {
+		reporter.Warnf("%s", warning)
+	}
+
+	// Help the user to find out why the install failed, or why it is taking longer than expected:
+	if cluster.State() == cmv1.ClusterStateError || cluster.State() == cmv1.ClusterStateInstalling {
+		remediation := clusterprovider.TriageProvisionError(cluster)
+		if remediation != nil {
+			fmt.Printf(""+
+				"Cause:                      %s\n"+
+				"Remediation:                %s\n"+
+				"Next Step:                  %s\n",
+				remediation.Cause,
+				remediation.Fix,
+				remediation.Command,
+			)
+		}
+	}
+	return nil
+}
+
+// formatMetric returns the used and total values of the given metric, or 'Unavailable' if it hasn't
+// been reported yet. Values in bytes are converted to the given unit.
+func formatMetric(metric *cmv1.ClusterMetric, unit string) string {
+	if metric.Total().Value() == 0 {
+		return "Unavailable"
+	}
+	used := metric.Used().Value()
+	total := metric.Total().Value()
+	if metric.Total().Unit() == "B" && unit == "GiB" {
+		used /= 1 << 30
+		total /= 1 << 30
+	}
+	if unit == "" {
+		unit = metric.Total().Unit()
+	}
+	value := fmt.Sprintf("%.1f of %.1f", used, total)
+	if unit != "" {
+		value = fmt.Sprintf("%s %s", value, unit)
+	}
+	return fmt.Sprintf("%s (%.0f%%)", value, 100*used/total)
+}
+
+// endpoints is the structured representation of the endpoints of a cluster.
+type endpoints struct {
+	APIURL      string                            `json:"api_url"`
+	ConsoleURL  string                            `json:"console_url"`
+	OAuthURL    string                            `json:"oauth_url"`
+	Records     []aws.DNSRecord                   `json:"records"`
+	PrivateLink []*aws.PrivateLinkEndpointService `json:"private_link,omitempty"`
+}
+
+func describeEndpoints(r *runtime.Runtime, cluster *cmv1.Cluster) error {
+	reporter := r.Reporter()
+
+	domain := fmt.Sprintf("%s.%s", cluster.Name(), cluster.DNS().BaseDomain())
+	oauthURL := ""
+	if cluster.Console().URL() != "" {
+		oauthURL = fmt.Sprintf("https://oauth-openshift.apps.%s", domain)
+	}
+
+	// The records are created by the installer in the AWS account of the user:
+	reporter.Debugf("Loading DNS records for domain '%s'", domain)
+	records, err := r.AWSClient().GetClusterDNSRecords(domain)
+	if err != nil {
+		return fmt.Errorf("Failed to get DNS records for cluster '%s': %w", cluster.Name(), err)
+	}
+
+	// The API of PrivateLink clusters is exposed by endpoint services created in the AWS account of
+	// the user when the cluster is installed:
+	reporter.Debugf("Loading PrivateLink setting of cluster '%s'", cluster.Name())
+	privateLink, err := r.OCM().GetPrivateLink(cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get PrivateLink setting of cluster '%s': %w", cluster.Name(), err)
+	}
+	var services []*aws.PrivateLinkEndpointService
+	if privateLink {
+		infraID, err := r.OCM().GetInfraID(cluster.ID())
+		if err != nil {
+			return fmt.Errorf("Failed to get infrastructure identifier of cluster '%s': %w", cluster.Name(), err)
+		}
+		if infraID != "" {
+			reporter.Debugf("Loading PrivateLink endpoint services of infrastructure '%s'", infraID)
+			services, err = r.WithAWSRegion(cluster.Region().ID()).AWSClient().GetPrivateLinkEndpointServices(infraID)
+			if err != nil {
+				return fmt.Errorf("Failed to get PrivateLink endpoint services of cluster '%s': %w", cluster.Name(), err)
+			}
+		}
+	}
+
+	if output.Structured() {
+		err = output.PrintValue(&endpoints{
+			APIURL:      cluster.API().URL(),
+			ConsoleURL:  cluster.Console().URL(),
+			OAuthURL:    oauthURL,
+			Records:     records,
+			PrivateLink: services,
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to print endpoints of cluster '%s': %w", cluster.Name(), err)
+		}
+		return nil
+	}
+
+	fmt.Printf(""+
+		"API URL:                    %s\n"+
+		"Console URL:                %s\n"+
+		"OAuth URL:                  %s\n",
+		cluster.API().URL(),
+		cluster.Console().URL(),
+		oauthURL,
+	)
+	fmt.Println()
+	if privateLink {
+		if len(services) == 0 {
+			reporter.Infof("There are no PrivateLink endpoint services for cluster '%s' yet", cluster.Name())
+		} else {
+			writer := table.NewWriter(os.Stdout)
+			fmt.Fprintf(writer, "ENDPOINT SERVICE\tNAME\tSTATE\tLOAD BALANCERS\n")
+			for _, service := range services {
+				fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n",
+					service.ID, service.Name, service.State, strings.Join(service.LoadBalancers, ", "))
+			}
+			writer.Flush()
+			fmt.Println()
+		}
+	}
+	if len(records) == 0 {
+		reporter.Infof("There are no DNS records for cluster '%s' yet", cluster.Name())
+		return nil
+	}
+
+	// Create the writer that will be used to print the tabulated results:
+	writer := table.NewWriter(os.Stdout)
+	fmt.Fprintf(writer, "NAME\tTYPE\tZONE\tVALUE\n")
+	for _, record := range records {
+		zone := "public"
+		if record.Private {
+			zone = "private"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n",
+			record.Name, record.Type, zone, strings.Join(record.Values, ", "))
+	}
+	writer.Flush()
+	return nil
 }
 
 func getDetailsLink(environment string) string {
@@ -223,3 +535,11 @@ func getDetailsLink(environment string) string {
 		return ""
 	}
 }
+
+// parseRFC3339 parses an RFC3339 date in either RFC3339Nano or RFC3339 format.
+func parseRFC3339(s string) (time.Time, error) {
+	if t, timeErr := time.Parse(time.RFC3339Nano, s); timeErr == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,132 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/machinepools"
+	"github.com/openshift/moactl/pkg/table"
+)
+
+// recentEvents is the number of entries of the service log shown in the description.
+const recentEvents = 5
+
+// formatNetwork returns the lines of the description that contain the network settings.
+func formatNetwork(cluster *cmv1.Cluster) string {
+	network := cluster.Network()
+	hostPrefix := ""
+	if network.HostPrefix() != 0 {
+		hostPrefix = fmt.Sprintf("/%d", network.HostPrefix())
+	}
+	return fmt.Sprintf(""+
+		"Machine CIDR:               %s\n"+
+		"Service CIDR:               %s\n"+
+		"Pod CIDR:                   %s\n"+
+		"Host Prefix:                %s\n",
+		network.MachineCIDR(),
+		network.ServiceCIDR(),
+		network.PodCIDR(),
+		hostPrefix,
+	)
+}
+
+// formatMachinePools returns the summary of the machine pools of the cluster, starting with the
+// default one, which is part of the cluster, like 'default (3 x m5.xlarge), gpu (2-4 x g4dn.xlarge)'.
+func formatMachinePools(cluster *cmv1.Cluster, machinePools []*cmv1.MachinePool) string {
+	items := []string{
+		fmt.Sprintf("default (%d x %s)", cluster.Nodes().Compute(),
+			cluster.Nodes().ComputeMachineType().ID()),
+	}
+	for _, machinePool := range machinePools {
+		items = append(items, fmt.Sprintf("%s (%s x %s)", machinePool.ID(),
+			machinepools.FormatReplicas(machinePool), machinePool.InstanceType()))
+	}
+	return strings.Join(items, ", ")
+}
+
+// formatIdentityProviders returns the names and types of the given identity providers, like
+// 'github-1 (GitHub)', or 'None' if there are none.
+func formatIdentityProviders(idps []*cmv1.IdentityProvider) string {
+	if len(idps) == 0 {
+		return "None"
+	}
+	items := make([]string, len(idps))
+	for i, idp := range idps {
+		items[i] = fmt.Sprintf("%s (%s)", idp.Name(), ocm.IdentityProviderType(idp))
+	}
+	return strings.Join(items, ", ")
+}
+
+// formatUpgradePolicy returns whether the cluster is upgraded automatically, with the schedule of the
+// upgrades, or manually.
+func formatUpgradePolicy(policy *cmv1.UpgradePolicy) string {
+	if policy == nil {
+		return "Manual"
+	}
+	result := fmt.Sprintf("Automatic, schedule '%s'", policy.Schedule())
+	if !policy.NextRun().IsZero() {
+		result = fmt.Sprintf("%s, next on %s", result, policy.NextRun().Format("2006-01-02 15:04 MST"))
+	}
+	return result
+}
+
+// formatStatus returns the lines of the description that contain the conditions reported in the
+// status of the cluster.
+func formatStatus(status *cmv1.ClusterStatus) string {
+	dnsReady := "No"
+	if status.DNSReady() {
+		dnsReady = "Yes"
+	}
+	result := fmt.Sprintf(""+
+		"DNS Ready:                  %s\n", dnsReady)
+	if status.Description() != "" {
+		result = fmt.Sprintf("%s"+
+			"Status:                     %s\n", result, status.Description())
+	}
+	return result
+}
+
+// printRecentEvents prints the newest entries of the given service log, which is sorted oldest
+// first.
+func printRecentEvents(entries []*slv1.LogEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	if len(entries) > recentEvents {
+		entries = entries[len(entries)-recentEvents:]
+	}
+	fmt.Println("Recent Events:")
+	writer := table.NewWriter(os.Stdout)
+	fmt.Fprintf(writer, "TIME\tSEVERITY\tSUMMARY\n")
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		fmt.Fprintf(writer, "%s\t%s\t%s\n",
+			entry.Timestamp().Format("2006-01-02 15:04 MST"),
+			entry.Severity(),
+			entry.Summary(),
+		)
+	}
+	writer.Flush()
+	fmt.Println()
+}
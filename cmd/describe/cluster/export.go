@@ -0,0 +1,59 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"os"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/iac"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// export prints the definition of the given cluster, with its machine pools and identity
+// providers, in the format given with the '--export' flag.
+func export(r *runtime.Runtime, cluster *cmv1.Cluster) error {
+	reporter := r.Reporter()
+
+	reporter.Debugf("Exporting definition of cluster '%s'", cluster.Name())
+	definition, err := clusterprovider.ExportDefinition(r.OCM(), cluster)
+	if err != nil {
+		return err
+	}
+	err = definition.ExportIdentityProviders(r.OCM(), cluster)
+	if err != nil {
+		return err
+	}
+	data, err := iac.Render(args.export, definition)
+	if err != nil {
+		return fmt.Errorf("Failed to export cluster '%s': %w", cluster.Name(), err)
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+func validExportFormat(format string) bool {
+	for _, valid := range iac.Formats {
+		if format == valid {
+			return true
+		}
+	}
+	return false
+}
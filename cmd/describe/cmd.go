@@ -19,9 +19,18 @@ package describe
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/moactl/cmd/describe/accountroles"
 	"github.com/openshift/moactl/cmd/describe/addon"
 	"github.com/openshift/moactl/cmd/describe/admin"
+	"github.com/openshift/moactl/cmd/describe/autoscaler"
 	"github.com/openshift/moactl/cmd/describe/cluster"
+	"github.com/openshift/moactl/cmd/describe/idp"
+	"github.com/openshift/moactl/cmd/describe/infrastructure"
+	"github.com/openshift/moactl/cmd/describe/machinepool"
+	"github.com/openshift/moactl/cmd/describe/network"
+	"github.com/openshift/moactl/cmd/describe/quota"
+
+	"github.com/openshift/moactl/pkg/output"
 )
 
 var Cmd = &cobra.Command{
@@ -31,7 +40,16 @@ var Cmd = &cobra.Command{
 }
 
 func init() {
+	output.AddFlag(Cmd.PersistentFlags())
+
+	Cmd.AddCommand(accountroles.Cmd)
 	Cmd.AddCommand(addon.Cmd)
 	Cmd.AddCommand(admin.Cmd)
+	Cmd.AddCommand(autoscaler.Cmd)
 	Cmd.AddCommand(cluster.Cmd)
+	Cmd.AddCommand(idp.Cmd)
+	Cmd.AddCommand(infrastructure.Cmd)
+	Cmd.AddCommand(machinepool.Cmd)
+	Cmd.AddCommand(network.Cmd)
+	Cmd.AddCommand(quota.Cmd)
 }
@@ -0,0 +1,130 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package idp
+
+import (
+	"fmt"
+	"io"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/output"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "idp NAME",
+	Short: "Show details of an identity provider",
+	Long: "Show details of an identity provider. The JSON output contains the identity provider as " +
+		"returned by the API. The YAML output doesn't contain secrets, and can be used with " +
+		"'rosa create idp --file' to create the same identity provider in other clusters.",
+	Example: `  # Describe the identity provider named "github-1" of a cluster named "mycluster"
+  rosa describe idp github-1 --cluster=mycluster
+
+  # Copy the identity provider to a cluster named "othercluster"
+  rosa describe idp github-1 --cluster=mycluster -o yaml > idp.yaml
+  rosa create idp --file=idp.yaml --cluster=othercluster`,
+	RunE: run,
+}
+
+func init() {
+	clusterprovider.UseKey(Cmd)
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	// Check command line arguments:
+	if len(argv) != 1 {
+		return fmt.Errorf(
+			"Expected exactly one command line parameter containing the name " +
+				"of the identity provider",
+		)
+	}
+	idpName := argv[0]
+
+	err := output.Validate()
+	if err != nil {
+		return err
+	}
+
+	clusterKey, err := clusterprovider.GetKey(r, nil)
+	if err != nil {
+		return err
+	}
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	// Try to find the identity provider:
+	reporter.Debugf("Loading identity provider '%s'", idpName)
+	idps, err := r.OCM().GetIdentityProviders(cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get identity providers for cluster '%s': %w", clusterKey, err)
+	}
+	var idp *cmv1.IdentityProvider
+	for _, item := range idps {
+		if item.Name() == idpName {
+			idp = item
+		}
+	}
+	if idp == nil {
+		return rosaerrors.NotFoundf("Failed to get identity provider '%s' for cluster '%s'", idpName, clusterKey)
+	}
+
+	// The YAML output is the file that 'rosa create idp --file' accepts, instead of the OCM object,
+	// so that it can be used to copy identity providers between clusters:
+	switch output.Format() {
+	case output.JSON:
+		err = output.Print(func(writer io.Writer) error {
+			return cmv1.MarshalIdentityProvider(idp, writer)
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to print identity provider '%s': %w", idpName, err)
+		}
+		return nil
+	case output.YAML:
+		config, err := ocm.ExportIdentityProvider(idp)
+		if err != nil {
+			return fmt.Errorf("Failed to export identity provider '%s': %w", idpName, err)
+		}
+		fmt.Print(string(config))
+		return nil
+	}
+
+	fmt.Printf(""+
+		"ID:             %s\n"+
+		"Name:           %s\n"+
+		"Type:           %s\n"+
+		"Mapping method: %s\n",
+		idp.ID(),
+		idp.Name(),
+		ocm.IdentityProviderType(idp),
+		idp.MappingMethod(),
+	)
+
+	return nil
+}
@@ -0,0 +1,176 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package infrastructure
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/output"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/table"
+)
+
+var Cmd = &cobra.Command{
+	Use:     "infrastructure",
+	Aliases: []string{"infra"},
+	Short:   "Show the AWS infrastructure of a cluster",
+	Long: "Show the VPCs, subnets, NAT gateways, load balancers and security groups created for a " +
+		"cluster in the AWS account, as found by the tags that the installer adds to them.",
+	Example: `  # Show the AWS infrastructure of a cluster named "mycluster"
+  rosa describe infrastructure --cluster=mycluster`,
+	RunE: run,
+}
+
+func init() {
+	clusterprovider.UseKey(Cmd)
+}
+
+// infrastructure is the structured representation of the AWS resources of a cluster.
+type infrastructure struct {
+	ID     string `json:"id"`
+	Region string `json:"region"`
+	*aws.Infrastructure
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	err := output.Validate()
+	if err != nil {
+		return err
+	}
+
+	clusterKey, err := clusterprovider.GetKey(r, nil)
+	if err != nil {
+		return err
+	}
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	infraID, err := r.OCM().GetInfraID(cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get infrastructure identifier of cluster '%s': %w", clusterKey, err)
+	}
+	if infraID == "" && !output.Structured() {
+		reporter.Infof("The installation of cluster '%s' hasn't started yet, so there is no infrastructure",
+			clusterKey)
+		return nil
+	}
+
+	// The resources are created by the installer in the AWS account of the user:
+	region := cluster.Region().ID()
+	reporter.Debugf("Loading AWS resources of infrastructure '%s' in region '%s'", infraID, region)
+	infra, err := r.WithAWSRegion(region).AWSClient().GetClusterInfrastructure(infraID)
+	if err != nil {
+		return fmt.Errorf("Failed to get AWS infrastructure of cluster '%s': %w", clusterKey, err)
+	}
+
+	if output.Structured() {
+		err = output.PrintValue(&infrastructure{
+			ID:             infraID,
+			Region:         region,
+			Infrastructure: infra,
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to print AWS infrastructure of cluster '%s': %w", clusterKey, err)
+		}
+		return nil
+	}
+
+	fmt.Printf(""+
+		"Infrastructure ID:  %s\n"+
+		"Region:             %s\n",
+		infraID,
+		region,
+	)
+
+	// Create the writer that will be used to print the tabulated results:
+	writer := table.NewWriter(os.Stdout).Numeric("RULES")
+
+	fmt.Println()
+	fmt.Fprintf(writer, "VPC\tCIDR\tCREATED BY INSTALLER\n")
+	for _, vpc := range infra.VPCs {
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", vpc.ID, vpc.CIDR, yesNo(vpc.Owned))
+	}
+	writer.Flush()
+
+	fmt.Println()
+	fmt.Fprintf(writer, "SUBNET\tNAME\tAVAILABILITY ZONE\tCIDR\tPUBLIC\n")
+	for _, subnet := range infra.Subnets {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n",
+			subnet.ID, subnet.Name, subnet.AvailabilityZone, subnet.CIDR, yesNo(subnet.Public))
+	}
+	writer.Flush()
+
+	fmt.Println()
+	fmt.Fprintf(writer, "NAT GATEWAY\tSUBNET\tSTATE\tPUBLIC IPS\n")
+	for _, gateway := range infra.NATGateways {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n",
+			gateway.ID, gateway.SubnetID, gateway.State, strings.Join(gateway.PublicIPs, ", "))
+	}
+	writer.Flush()
+
+	fmt.Println()
+	fmt.Fprintf(writer, "LOAD BALANCER\tTYPE\tSCHEME\tDNS NAME\n")
+	for _, balancer := range infra.LoadBalancers {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", balancer.Name, balancer.Type, balancer.Scheme, balancer.DNS)
+	}
+	writer.Flush()
+
+	fmt.Println()
+	fmt.Fprintf(writer, "SECURITY GROUP\tNAME\tRULES\n")
+	for _, group := range infra.SecurityGroups {
+		fmt.Fprintf(writer, "%s\t%s\t%d\n", group.ID, group.Name, group.Rules)
+	}
+	writer.Flush()
+
+	// NAT gateways and load balancers are billed by the hour even when they aren't used, so they
+	// are usually the most relevant for the cost:
+	fmt.Println()
+	reporter.Infof("Cluster '%s' has %d NAT gateways and %d load balancers, which are billed by the hour, "+
+		"and %d Elastic IP addresses", clusterKey, len(infra.NATGateways), len(infra.LoadBalancers),
+		countPublicIPs(infra))
+
+	return nil
+}
+
+func countPublicIPs(infra *aws.Infrastructure) int {
+	count := 0
+	for _, gateway := range infra.NATGateways {
+		count += len(gateway.PublicIPs)
+	}
+	return count
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "Yes"
+	}
+	return "No"
+}
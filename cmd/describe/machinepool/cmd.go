@@ -0,0 +1,166 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinepool
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/machinepools"
+	"github.com/openshift/moactl/pkg/output"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var args struct {
+	cost bool
+}
+
+var Cmd = &cobra.Command{
+	Use:     "machinepool ID",
+	Aliases: []string{"machine-pool"},
+	Short:   "Show details of a machine pool",
+	Long: "Show details of a machine pool of a cluster, including whether its nodes run on spot " +
+		"instances. The structured output contains the machine pool as returned by the API.",
+	Example: `  # Describe the machine pool "mp-1" of a cluster named "mycluster"
+  rosa describe machinepool mp-1 --cluster=mycluster
+
+  # Show the projected monthly cost of the machine pool "mp-1" and of the whole cluster
+  rosa describe machinepool mp-1 --cluster=mycluster --cost
+
+  # Show the projected monthly cost of all the machine pools of a cluster
+  rosa describe machinepool --cluster=mycluster --cost`,
+	RunE: run,
+}
+
+func init() {
+	clusterprovider.UseKey(Cmd)
+
+	flags := Cmd.Flags()
+	flags.BoolVar(
+		&args.cost,
+		"cost",
+		false,
+		"Show the projected monthly cost of the nodes of the machine pool, or of all the machine "+
+			"pools if no machine pool is given, and the total of the cluster. Autoscaled machine "+
+			"pools are estimated with their minimum and maximum number of nodes.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	// Check command line arguments:
+	if len(argv) != 1 && !(args.cost && len(argv) == 0) {
+		return fmt.Errorf(
+			"Expected exactly one command line parameter containing the identifier " +
+				"of the machine pool",
+		)
+	}
+	machinePoolID := ""
+	if len(argv) == 1 {
+		machinePoolID = argv[0]
+	}
+
+	err := output.Validate()
+	if err != nil {
+		return err
+	}
+
+	clusterKey, err := clusterprovider.GetKey(r, nil)
+	if err != nil {
+		return err
+	}
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	if args.cost {
+		return describeCost(r, cluster, machinePoolID)
+	}
+
+	// The default machine pool is part of the cluster, so it can't be loaded separately:
+	if machinePoolID == defaultMachinePoolID {
+		return rosaerrors.Usagef("The default machine pool is part of cluster '%s', use "+
+			"'rosa describe cluster' to see its details", clusterKey)
+	}
+
+	reporter.Debugf("Loading machine pool '%s'", machinePoolID)
+	machinePool, spot, raw, err := r.OCM().GetMachinePool(cluster.ID(), machinePoolID)
+	if err != nil {
+		return fmt.Errorf("Failed to get machine pool '%s' of cluster '%s': %w", machinePoolID, clusterKey, err)
+	}
+
+	if output.Structured() {
+		err = output.Print(func(writer io.Writer) error {
+			_, err := writer.Write(raw)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to print machine pool '%s': %w", machinePoolID, err)
+		}
+		return nil
+	}
+
+	fmt.Printf(""+
+		"ID:                 %s\n"+
+		"Cluster:            %s\n"+
+		"Autoscaling:        %s\n"+
+		"Replicas:           %s\n"+
+		"Instance type:      %s\n"+
+		"Labels:             %s\n"+
+		"Taints:             %s\n"+
+		"Availability zones: %s\n"+
+		"Spot instances:     %s\n"+
+		"Delete protection:  %s\n",
+		machinePool.ID(),
+		cluster.ID(),
+		yesNo(autoscaling(machinePool)),
+		machinepools.FormatReplicas(machinePool),
+		machinePool.InstanceType(),
+		machinepools.FormatLabels(machinePool.Labels()),
+		machinepools.FormatTaints(machinePool.Taints()),
+		strings.Join(machinePool.AvailabilityZones(), ", "),
+		machinepools.FormatSpot(spot),
+		yesNo(ocm.IsMachinePoolDeleteProtected(cluster, machinePool.ID())),
+	)
+
+	return nil
+}
+
+func autoscaling(machinePool *cmv1.MachinePool) bool {
+	_, ok := machinePool.GetAutoscaling()
+	return ok
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "Yes"
+	}
+	return "No"
+}
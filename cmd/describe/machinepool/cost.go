@@ -0,0 +1,176 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinepool
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/aws"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/output"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/table"
+)
+
+// Identifier of the machine pool of the compute nodes that are part of the cluster:
+const defaultMachinePoolID = "default"
+
+// cost is the structured representation of the projected monthly cost of the machine pools.
+type cost struct {
+	Region     string          `json:"region"`
+	Pools      []*aws.PoolCost `json:"machine_pools"`
+	MinMonthly float64         `json:"min_monthly_usd"`
+	MaxMonthly float64         `json:"max_monthly_usd"`
+	Spot       []string        `json:"spot_machine_pools,omitempty"`
+}
+
+// describeCost prints the projected monthly cost of the nodes of the given machine pool, or of all
+// the machine pools when the identifier is empty, together with the total of the cluster. Pools
+// that are autoscaled are estimated with their minimum and maximum number of nodes.
+func describeCost(r *runtime.Runtime, cluster *cmv1.Cluster, machinePoolID string) error {
+	reporter := r.Reporter()
+	awsClient := r.WithAWSRegion(cluster.Region().ID()).AWSClient()
+
+	reporter.Debugf("Loading machine pools of cluster '%s'", cluster.ID())
+	machinePools, err := r.OCM().GetMachinePools(cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get machine pools of cluster '%s': %w", cluster.Name(), err)
+	}
+	spot, err := r.OCM().GetSpotMarketOptions(cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get spot instances of cluster '%s': %w", cluster.Name(), err)
+	}
+
+	// The compute nodes of the cluster are the default machine pool:
+	type pool struct {
+		id           string
+		instanceType string
+		minNodes     int
+		maxNodes     int
+	}
+	nodes := cluster.Nodes()
+	pools := []*pool{{
+		id:           defaultMachinePoolID,
+		instanceType: nodes.ComputeMachineType().ID(),
+		minNodes:     nodes.Compute(),
+		maxNodes:     nodes.Compute(),
+	}}
+	if autoscaling, ok := nodes.GetAutoscaleCompute(); ok {
+		pools[0].minNodes = autoscaling.MinReplicas()
+		pools[0].maxNodes = autoscaling.MaxReplicas()
+	}
+	found := machinePoolID == "" || machinePoolID == defaultMachinePoolID
+	for _, machinePool := range machinePools {
+		item := &pool{
+			id:           machinePool.ID(),
+			instanceType: machinePool.InstanceType(),
+			minNodes:     machinePool.Replicas(),
+			maxNodes:     machinePool.Replicas(),
+		}
+		if autoscaling, ok := machinePool.GetAutoscaling(); ok {
+			item.minNodes = autoscaling.MinReplicas()
+			item.maxNodes = autoscaling.MaxReplicas()
+		}
+		pools = append(pools, item)
+		if item.id == machinePoolID {
+			found = true
+		}
+	}
+	if !found {
+		return rosaerrors.NotFoundf("Machine pool '%s' doesn't exist in cluster '%s'", machinePoolID, cluster.Name())
+	}
+
+	instanceTypes := make([]string, len(pools))
+	for i, item := range pools {
+		instanceTypes[i] = item.instanceType
+	}
+	reporter.Debugf("Loading prices of region '%s'", cluster.Region().ID())
+	instancePrices, err := awsClient.GetInstancePrices(instanceTypes)
+	if err != nil {
+		return err
+	}
+	volumePrice, err := awsClient.GetVolumePrice(aws.DefaultRootVolumeType)
+	if err != nil {
+		return err
+	}
+
+	result := &cost{
+		Region: cluster.Region().ID(),
+	}
+	all := []*aws.PoolCost{}
+	for _, item := range pools {
+		poolCost := aws.EstimatePoolCost(item.id, item.instanceType, item.minNodes, item.maxNodes,
+			aws.DefaultRootVolumeSize, instancePrices[item.instanceType], volumePrice)
+		all = append(all, poolCost)
+		if machinePoolID == "" || item.id == machinePoolID {
+			result.Pools = append(result.Pools, poolCost)
+		}
+		if spot[item.id] != nil {
+			result.Spot = append(result.Spot, item.id)
+		}
+	}
+	result.MinMonthly, result.MaxMonthly = aws.TotalCost(all)
+
+	if output.Structured() {
+		err = output.PrintValue(result)
+		if err != nil {
+			return fmt.Errorf("Failed to print cost of cluster '%s': %w", cluster.Name(), err)
+		}
+		return nil
+	}
+
+	writer := table.NewWriter(os.Stdout)
+	fmt.Fprintf(writer, "MACHINE POOL\t\tINSTANCE TYPE\t\tNODES\t\tHOURLY PER NODE\t\tDISK PER NODE\t\tMONTHLY\n")
+	for _, item := range result.Pools {
+		fmt.Fprintf(writer, "%s\t\t%s\t\t%s\t\t%s\t\t%s\t\t%s\n",
+			item.Name,
+			item.InstanceType,
+			formatRange(fmt.Sprintf("%d", item.MinNodes), fmt.Sprintf("%d", item.MaxNodes)),
+			formatDollars(item.InstanceHourly),
+			fmt.Sprintf("%d GiB, %s/month", item.DiskSize, formatDollars(item.DiskMonthly)),
+			formatRange(formatDollars(item.MinMonthly), formatDollars(item.MaxMonthly)),
+		)
+	}
+	writer.Flush()
+	fmt.Printf("\nCluster total: %s per month\n",
+		formatRange(formatDollars(result.MinMonthly), formatDollars(result.MaxMonthly)))
+	reporter.Infof("Estimated with the on-demand prices of region '%s' and %d hours per month. The "+
+		"control plane and infrastructure nodes, data transfer and service fees aren't included",
+		result.Region, aws.HoursPerMonth)
+	if len(result.Spot) > 0 {
+		reporter.Infof("Machine pools with spot instances are estimated at the on-demand price, "+
+			"which is the most they can cost: %s", strings.Join(result.Spot, ", "))
+	}
+
+	return nil
+}
+
+func formatDollars(value float64) string {
+	return fmt.Sprintf("$%.2f", value)
+}
+
+// formatRange returns the given values separated by a dash, or only one of them if they are equal.
+func formatRange(min string, max string) string {
+	if min == max {
+		return min
+	}
+	return min + " - " + max
+}
@@ -0,0 +1,213 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/output"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/table"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "network",
+	Short: "Show the network configuration of a cluster",
+	Long: "Show the blocks of IP addresses, the subnets and availability zones, the internet and NAT " +
+		"gateways and the privacy of the endpoints of a cluster in one view, to review its network.",
+	Example: `  # Show the network configuration of a cluster named "mycluster"
+  rosa describe network --cluster=mycluster`,
+	RunE: run,
+}
+
+func init() {
+	clusterprovider.UseKey(Cmd)
+}
+
+// network is the structured representation of the network configuration of a cluster.
+type network struct {
+	ID                string   `json:"id"`
+	Region            string   `json:"region"`
+	MultiAZ           bool     `json:"multi_az"`
+	AvailabilityZones []string `json:"availability_zones"`
+	MachineCIDR       string   `json:"machine_cidr"`
+	ServiceCIDR       string   `json:"service_cidr"`
+	PodCIDR           string   `json:"pod_cidr"`
+	HostPrefix        int      `json:"host_prefix"`
+	Private           bool     `json:"private"`
+	PrivateLink       bool     `json:"private_link"`
+	ExistingVPC       bool     `json:"existing_vpc"`
+	*aws.Network
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	err := output.Validate()
+	if err != nil {
+		return err
+	}
+
+	clusterKey, err := clusterprovider.GetKey(r, nil)
+	if err != nil {
+		return err
+	}
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	privateLink, err := r.OCM().GetPrivateLink(cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get PrivateLink setting of cluster '%s': %w", clusterKey, err)
+	}
+	infraID, err := r.OCM().GetInfraID(cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get infrastructure identifier of cluster '%s': %w", clusterKey, err)
+	}
+
+	// The subnets of an existing VPC are known before the installation starts, the ones created by
+	// the installer are found by their tags afterwards:
+	subnetIDs := cluster.AWS().SubnetIDs()
+	region := cluster.Region().ID()
+	resources := &aws.Network{}
+	if len(subnetIDs) > 0 || infraID != "" {
+		reporter.Debugf("Loading AWS network resources of cluster '%s' in region '%s'", clusterKey, region)
+		resources, err = r.WithAWSRegion(region).AWSClient().GetClusterNetwork(infraID, subnetIDs)
+		if err != nil {
+			return fmt.Errorf("Failed to get AWS network resources of cluster '%s': %w", clusterKey, err)
+		}
+	}
+
+	result := &network{
+		ID:                cluster.ID(),
+		Region:            region,
+		MultiAZ:           cluster.MultiAZ(),
+		AvailabilityZones: cluster.Nodes().AvailabilityZones(),
+		MachineCIDR:       cluster.Network().MachineCIDR(),
+		ServiceCIDR:       cluster.Network().ServiceCIDR(),
+		PodCIDR:           cluster.Network().PodCIDR(),
+		HostPrefix:        cluster.Network().HostPrefix(),
+		Private:           cluster.API().Listening() == cmv1.ListeningMethodInternal,
+		PrivateLink:       privateLink,
+		ExistingVPC:       len(subnetIDs) > 0,
+		Network:           resources,
+	}
+
+	if output.Structured() {
+		err = output.PrintValue(result)
+		if err != nil {
+			return fmt.Errorf("Failed to print network of cluster '%s': %w", clusterKey, err)
+		}
+		return nil
+	}
+
+	vpcs := make([]string, len(result.VPCs))
+	for i, vpc := range result.VPCs {
+		vpcs[i] = fmt.Sprintf("%s (%s)", vpc.ID, vpc.CIDR)
+	}
+	fmt.Printf(""+
+		"ID:                 %s\n"+
+		"Region:             %s\n"+
+		"Availability zones: %s\n"+
+		"Machine CIDR:       %s\n"+
+		"Service CIDR:       %s\n"+
+		"Pod CIDR:           %s\n"+
+		"Host prefix:        /%d\n"+
+		"API endpoint:       %s\n"+
+		"PrivateLink:        %s\n"+
+		"Existing VPC:       %s\n"+
+		"VPC:                %s\n"+
+		"Internet gateways:  %s\n"+
+		"NAT gateways:       %d\n",
+		result.ID,
+		result.Region,
+		valueOrNone(strings.Join(result.AvailabilityZones, ", ")),
+		result.MachineCIDR,
+		result.ServiceCIDR,
+		result.PodCIDR,
+		result.HostPrefix,
+		privacy(result.Private),
+		yesNo(result.PrivateLink),
+		yesNo(result.ExistingVPC),
+		valueOrNone(strings.Join(vpcs, ", ")),
+		valueOrNone(strings.Join(result.InternetGateways, ", ")),
+		len(result.NATGateways),
+	)
+
+	if len(result.Subnets) == 0 {
+		fmt.Println()
+		reporter.Infof("The installation of cluster '%s' hasn't started yet, so there are no subnets",
+			clusterKey)
+		return nil
+	}
+
+	// Create the writer that will be used to print the tabulated results:
+	writer := table.NewWriter(os.Stdout)
+
+	fmt.Println()
+	fmt.Fprintf(writer, "SUBNET\tNAME\tAVAILABILITY ZONE\tCIDR\tPUBLIC\n")
+	for _, subnet := range result.Subnets {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n",
+			subnet.ID, subnet.Name, subnet.AvailabilityZone, subnet.CIDR, yesNo(subnet.Public))
+	}
+	writer.Flush()
+
+	if len(result.NATGateways) > 0 {
+		fmt.Println()
+		fmt.Fprintf(writer, "NAT GATEWAY\tSUBNET\tSTATE\tPUBLIC IPS\n")
+		for _, gateway := range result.NATGateways {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n",
+				gateway.ID, gateway.SubnetID, gateway.State, strings.Join(gateway.PublicIPs, ", "))
+		}
+		writer.Flush()
+	}
+
+	return nil
+}
+
+func privacy(private bool) string {
+	if private {
+		return "Private"
+	}
+	return "Public"
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "None"
+	}
+	return value
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "Yes"
+	}
+	return "No"
+}
@@ -0,0 +1,245 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/defaults"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/output"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/table"
+)
+
+// Instance type of the compute nodes when the description of the cluster doesn't have one, the
+// same that the service uses by default:
+const defaultComputeMachineType = "m5.xlarge"
+
+var args struct {
+	plan   string
+	region string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "quota",
+	Short: "Show the quota that a cluster will use",
+	Long: "Show the OCM quota and the AWS quota that a cluster that hasn't been created yet will use, " +
+		"compared to what is still available. The cluster is described as 'cluster:' followed by a " +
+		"comma separated list of options: 'single-az' or 'multi-az', 'public' or 'private', and the " +
+		"instance type and number of the compute nodes, like 'm5.xlarge x6'.",
+	Example: `  # Show the quota used by a multi-AZ cluster with six m5.xlarge compute nodes
+  rosa describe quota --for "cluster: multi-az, m5.xlarge x6"
+
+  # Show the quota used by a default cluster in region us-west-2
+  rosa describe quota --for "cluster:" --region=us-west-2`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVar(
+		&args.plan,
+		"for",
+		"",
+		"Description of the planned cluster, for example 'cluster: multi-az, m5.xlarge x6' (required).",
+	)
+	flags.StringVarP(
+		&args.region,
+		"region",
+		"r",
+		"",
+		"AWS region where the cluster will be created (overrides the AWS_REGION environment variable).",
+	)
+}
+
+// quota is the structured representation of the quota that a planned cluster needs.
+type quota struct {
+	Region string            `json:"region"`
+	OCM    []*ocm.QuotaCost  `json:"ocm"`
+	AWS    []*aws.QuotaUsage `json:"aws"`
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	err := output.Validate()
+	if err != nil {
+		return err
+	}
+
+	if args.plan == "" {
+		return rosaerrors.Usagef("Option '--for' is mandatory")
+	}
+	plan, err := parsePlan(args.plan)
+	if err != nil {
+		return err
+	}
+	if plan.ComputeNodes == 0 {
+		nodeDefaults, err := r.OCM().LoadDefaults()
+		if err != nil {
+			reporter.Debugf("Failed to load compute node defaults of the organization, using built-in values: %v", err)
+			nodeDefaults = defaults.Builtin()
+		}
+		plan.ComputeNodes = nodeDefaults.ComputeNodes(plan.MultiAZ).Default
+	}
+	reporter.Debugf("Calculating quota for %s cluster with %d '%s' compute nodes",
+		availability(plan), plan.ComputeNodes, plan.ComputeMachineType)
+
+	region, err := aws.GetRegion(args.region)
+	if err != nil {
+		return fmt.Errorf("Error getting region: %w", err)
+	}
+	awsClient := r.WithAWSRegion(region).AWSClient()
+
+	costs, err := r.OCM().GetClusterQuotaCost(plan.MultiAZ, plan.ComputeMachineType,
+		plan.ComputeNodes)
+	if err != nil {
+		return fmt.Errorf("Failed to get OCM quota: %w", err)
+	}
+	usages, err := awsClient.GetClusterQuotaUsage(plan)
+	if err != nil {
+		return fmt.Errorf("Failed to get AWS quota in region '%s': %w", region, err)
+	}
+
+	if output.Structured() {
+		err = output.PrintValue(&quota{
+			Region: region,
+			OCM:    costs,
+			AWS:    usages,
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to print quota: %w", err)
+		}
+		return nil
+	}
+
+	insufficient := 0
+	writer := table.NewWriter(os.Stdout).Numeric("REQUIRED", "AVAILABLE", "USED", "LIMIT")
+	fmt.Fprintf(writer, "OCM RESOURCE\tNAME\tREQUIRED\tAVAILABLE\tSTATUS\n")
+	for _, cost := range costs {
+		name := cost.ResourceName
+		if name == "" {
+			name = "-"
+		}
+		if !cost.Sufficient() {
+			insufficient++
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%d\t%d\t%s\n",
+			cost.ResourceType, name, cost.Required, cost.Available(), status(cost.Sufficient()))
+	}
+	writer.Flush()
+
+	fmt.Println()
+	fmt.Fprintf(writer, "AWS QUOTA\tCODE\tREQUIRED\tUSED\tLIMIT\tSTATUS\n")
+	for _, usage := range usages {
+		if !usage.Sufficient() {
+			insufficient++
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%d\t%d\t%d\t%s\n",
+			usage.QuotaName, usage.QuotaCode, usage.Required, usage.Used, usage.Limit,
+			status(usage.Sufficient()))
+	}
+	writer.Flush()
+
+	fmt.Println()
+	if insufficient > 0 {
+		reporter.Warnf("There isn't enough quota for %d of the resources that the cluster needs. "+
+			"Request a quota increase before creating the cluster", insufficient)
+		return nil
+	}
+	reporter.Infof("There is enough quota to create the cluster in region '%s'", region)
+
+	return nil
+}
+
+var nodesRE = regexp.MustCompile(`^([a-z0-9]+\.[a-z0-9]+)(\s+x\s*([0-9]+))?$`)
+
+// parsePlan parses the description of a planned cluster, for example 'cluster: multi-az, m5.xlarge
+// x6'. Options that aren't given keep their defaults, and the number of compute nodes is zero if
+// it isn't given.
+func parsePlan(text string) (*aws.ClusterPlan, error) {
+	kind := text
+	options := ""
+	if colon := strings.Index(text, ":"); colon >= 0 {
+		kind = text[:colon]
+		options = text[colon+1:]
+	}
+	if strings.TrimSpace(kind) != "cluster" {
+		return nil, fmt.Errorf("Description '%s' isn't valid: it must start with 'cluster:'", text)
+	}
+
+	plan := &aws.ClusterPlan{
+		ComputeMachineType: defaultComputeMachineType,
+	}
+	for _, option := range strings.Split(options, ",") {
+		option = strings.ToLower(strings.TrimSpace(option))
+		switch option {
+		case "":
+			continue
+		case "single-az":
+			plan.MultiAZ = false
+		case "multi-az":
+			plan.MultiAZ = true
+		case "public":
+			plan.Private = false
+		case "private":
+			plan.Private = true
+		default:
+			matches := nodesRE.FindStringSubmatch(option)
+			if matches == nil {
+				return nil, fmt.Errorf("Option '%s' of description '%s' isn't valid: it must be 'single-az', "+
+					"'multi-az', 'public', 'private' or an instance type with the number of nodes, like "+
+					"'m5.xlarge x6'", option, text)
+			}
+			plan.ComputeMachineType = matches[1]
+			if matches[3] != "" {
+				nodes, err := strconv.Atoi(matches[3])
+				if err != nil || nodes < 1 {
+					return nil, fmt.Errorf("Number of nodes of option '%s' isn't valid: it must be a "+
+						"positive number", option)
+				}
+				plan.ComputeNodes = nodes
+			}
+		}
+	}
+	return plan, nil
+}
+
+func availability(plan *aws.ClusterPlan) string {
+	if plan.MultiAZ {
+		return "multi-AZ"
+	}
+	return "single-AZ"
+}
+
+func status(sufficient bool) string {
+	if sufficient {
+		return "ok"
+	}
+	return "insufficient"
+}
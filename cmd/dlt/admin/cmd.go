@@ -17,158 +17,81 @@ limitations under the License.
 package admin
 
 import (
-	"os"
+	"fmt"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/moactl/pkg/aws"
-	"github.com/openshift/moactl/pkg/confirm"
-	"github.com/openshift/moactl/pkg/logging"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/ocm"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
-const (
-	idpName  = "Cluster-Admin"
-	username = "cluster-admin"
-)
-
-var args struct {
-	clusterKey string
-}
-
 var Cmd = &cobra.Command{
 	Use:   "admin",
 	Short: "Deletes the admin user",
 	Long:  "Deletes the cluster-admin user used to login to the cluster",
 	Example: `  # Delete the admin user
   rosa delete admin --cluster=mycluster`,
-	Run: run,
+	RunE: run,
 }
 
 func init() {
-	flags := Cmd.Flags()
-
-	flags.StringVarP(
-		&args.clusterKey,
-		"cluster",
-		"c",
-		"",
-		"Name or ID of the cluster to add the IdP to (required).",
-	)
-	Cmd.MarkFlagRequired("cluster")
+	clusterprovider.UseKey(Cmd)
 }
 
-func run(cmd *cobra.Command, _ []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
-
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
-	clusterKey := args.clusterKey
-	if !ocm.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
-	}
-
-	// Create the AWS client:
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
-	}
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
 
-	awsCreator, err := awsClient.GetCreator()
+	clusterKey, err := clusterprovider.GetKey(r, nil)
 	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		return err
 	}
 
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
-	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
-
-	// Get the client for the OCM collection of clusters:
-	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
-
 	// Try to find the cluster:
 	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
 	if err != nil {
-		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
 	}
 
 	if cluster.State() != cmv1.ClusterStateReady {
-		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
-		os.Exit(1)
+		return rosaerrors.Conflictf("Cluster '%s' is not yet ready", clusterKey)
 	}
 
 	// Try to find the htpasswd identity provider:
-	reporter.Debugf("Loading '%s' identity provider", idpName)
-	idps, err := ocm.GetIdentityProviders(clustersCollection, cluster.ID())
+	reporter.Debugf("Loading '%s' identity provider", ocm.AdminIdentityProviderName)
+	idp, err := r.OCM().GetAdminIdentityProvider(cluster.ID())
 	if err != nil {
-		reporter.Errorf("Failed to get '%s' identity provider for cluster '%s': %v", idpName, clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get '%s' identity provider for cluster '%s': %w",
+			ocm.AdminIdentityProviderName, clusterKey, err)
+	}
+	if idp == nil {
+		return rosaerrors.NotFoundf("Cluster '%s' doesn't have an admin", clusterKey)
 	}
 
-	var idp *cmv1.IdentityProvider
-	for _, item := range idps {
-		if ocm.IdentityProviderType(item) == "htpasswd" {
-			idp = item
-		}
+	if !clusterprovider.ConfirmDestructive(r, cluster, "delete %s user on cluster %s", ocm.AdminUsername,
+		clusterKey) {
+		return nil
 	}
-	if idp == nil {
-		reporter.Errorf("Failed to get '%s' identity provider for cluster '%s'", idpName, clusterKey)
-		os.Exit(1)
+
+	// Delete htpasswd IdP:
+	reporter.Debugf("Deleting '%s' identity provider on cluster '%s'", ocm.AdminIdentityProviderName, clusterKey)
+	err = r.OCM().DeleteIdentityProvider(cluster.ID(), idp.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to delete '%s' identity provider on cluster '%s': %w",
+			ocm.AdminIdentityProviderName, clusterKey, err)
 	}
 
-	if confirm.Confirm("delete %s user on cluster %s", username, clusterKey) {
-		// Delete htpasswd IdP:
-		reporter.Debugf("Deleting '%s' identity provider on cluster '%s'", idpName, clusterKey)
-		idpResp, err := clustersCollection.
-			Cluster(cluster.ID()).
-			IdentityProviders().
-			IdentityProvider(idp.ID()).
-			Delete().
-			Send()
-		if err != nil {
-			reporter.Errorf("Failed to delete '%s' identity provider on cluster '%s': %s",
-				idpName, clusterKey, idpResp.Error().Reason())
-			os.Exit(1)
-		}
-
-		// Delete admin user from the cluster-admins group:
-		reporter.Debugf("Deleting '%s' user from cluster-admins group on cluster '%s'", username, clusterKey)
-		userResp, err := clustersCollection.Cluster(cluster.ID()).
-			Groups().
-			Group("cluster-admins").
-			Users().
-			User(username).
-			Delete().
-			Send()
-		if err != nil {
-			reporter.Errorf("Failed to delete '%s' user from cluster '%s': %s",
-				username, clusterKey, userResp.Error().Reason())
-			os.Exit(1)
-		}
+	// Delete admin user from the cluster-admins group:
+	reporter.Debugf("Deleting '%s' user from cluster-admins group on cluster '%s'", ocm.AdminUsername, clusterKey)
+	err = r.OCM().DeleteGroupUser(cluster.ID(), ocm.ClusterAdminsGroup, ocm.AdminUsername)
+	if err != nil {
+		return fmt.Errorf("Failed to delete '%s' user from cluster '%s': %w", ocm.AdminUsername, clusterKey, err)
 	}
+	reporter.Infof("Admin user '%s' has been deleted from cluster '%s'", ocm.AdminUsername, clusterKey)
+
+	return nil
 }
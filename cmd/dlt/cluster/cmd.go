@@ -17,24 +17,25 @@ limitations under the License.
 package cluster
 
 import (
-	"os"
+	"fmt"
 
 	"github.com/spf13/cobra"
 
 	uninstallLogs "github.com/openshift/moactl/cmd/logs/uninstall"
 
-	"github.com/openshift/moactl/pkg/aws"
 	clusterprovider "github.com/openshift/moactl/pkg/cluster"
-	"github.com/openshift/moactl/pkg/confirm"
-	"github.com/openshift/moactl/pkg/logging"
+	"github.com/openshift/moactl/pkg/dryrun"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/ocm"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/ocm/roles"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
 var args struct {
 	// Watch logs during cluster uninstallation
 	watch      bool
-	clusterKey string
+	force      bool
+	bestEffort bool
 }
 
 var Cmd = &cobra.Command{
@@ -45,20 +46,25 @@ var Cmd = &cobra.Command{
   rosa delete cluster mycluster
 
   # Delete a cluster using the --cluster flag
-  rosa delete cluster --cluster=mycluster`,
-	Run: run,
+  rosa delete cluster --cluster=mycluster
+
+  # Delete a cluster named "mycluster" even if it is protected against deletion
+  rosa delete cluster mycluster --force
+
+  # Delete a cluster named "mycluster" and the AWS resources that it leaves behind
+  rosa delete cluster mycluster --best-effort
+
+  # Delete all the clusters in region us-east-1 whose name starts with "test-"
+  rosa delete cluster --all --filter "region.id = 'us-east-1' and name like 'test-%'"`,
+	RunE: run,
 }
 
 func init() {
+	roles.Require(Cmd, roles.Delete)
+
 	flags := Cmd.Flags()
 
-	flags.StringVarP(
-		&args.clusterKey,
-		"cluster",
-		"c",
-		"",
-		"Name or ID of the cluster to delete.",
-	)
+	clusterprovider.UseBatch(Cmd)
 
 	flags.BoolVar(
 		&args.watch,
@@ -66,81 +72,81 @@ func init() {
 		false,
 		"Watch cluster uninstallation logs.",
 	)
-}
 
-func run(cmd *cobra.Command, argv []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
-
-	// Check command line arguments:
-	clusterKey := args.clusterKey
-	if clusterKey == "" {
-		if len(argv) != 1 {
-			reporter.Errorf(
-				"Expected exactly one command line argument or flag containing the name " +
-					"or identifier of the cluster",
-			)
-			os.Exit(1)
-		}
-		clusterKey = argv[0]
-	}
+	flags.BoolVar(
+		&args.force,
+		"force",
+		false,
+		"Delete the cluster even if it is protected against deletion.",
+	)
 
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
-	if !clusterprovider.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
-	}
+	flags.BoolVar(
+		&args.bestEffort,
+		"best-effort",
+		false,
+		"Wait for the cluster to be uninstalled, and then delete the load balancers, S3 buckets and "+
+			"IAM roles of the cluster that were left behind in the AWS account.",
+	)
+}
 
-	// Create the AWS client:
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
-	}
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
 
-	awsCreator, err := awsClient.GetCreator()
+	clusterKey, err := clusterprovider.GetKey(r, argv)
 	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		return err
 	}
 
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
 	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
 	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
+	if ocm.IsDeleteProtected(cluster) {
+		if !args.force {
+			return rosaerrors.Conflictf("Cluster '%s' is protected against deletion, use '--force' to "+
+				"delete it anyway or 'rosa edit cluster --enable-delete-protection=false' to remove the "+
+				"protection", clusterKey)
 		}
-	}()
+		reporter.Warnf("Cluster '%s' is protected against deletion, deleting it because '--force' "+
+			"was used", clusterKey)
+	}
 
-	// Get the client for the OCM collection of clusters:
-	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
+	if !clusterprovider.ConfirmDestructive(r, cluster, "delete cluster %s", clusterKey) {
+		return nil
+	}
 
-	if !confirm.Confirm("delete cluster %s", clusterKey) {
-		os.Exit(0)
+	// The infrastructure identifier is needed to find the resources left behind, and it can't be
+	// read once the cluster is gone:
+	infraID := ""
+	if args.bestEffort {
+		infraID, err = r.OCM().GetInfraID(cluster.ID())
+		if err != nil {
+			return fmt.Errorf("Failed to get infrastructure identifier of cluster '%s': %w",
+				clusterKey, err)
+		}
+		if infraID == "" {
+			reporter.Warnf("Cluster '%s' hasn't created AWS resources, there will be nothing to clean up",
+				clusterKey)
+		}
 	}
 
 	reporter.Debugf("Deleting cluster '%s'", clusterKey)
-	cluster, err := clusterprovider.DeleteCluster(clustersCollection, clusterKey, awsCreator.ARN)
+	cluster, err = clusterprovider.DeleteCluster(r.OCM(), clusterKey, r.Creator().ARN)
 	if err != nil {
-		reporter.Errorf("Failed to delete cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to delete cluster '%s': %w", clusterKey, err)
 	}
 
 	if args.watch {
-		uninstallLogs.Cmd.Run(cmd, []string{cluster.ID()})
+		err = uninstallLogs.Cmd.RunE(cmd, []string{cluster.ID()})
+		if err != nil {
+			return err
+		}
+	}
+
+	if infraID != "" && !dryrun.Enabled() {
+		return deleteLeftovers(r, cluster, infraID, args.watch)
 	}
+	return nil
 }
@@ -0,0 +1,89 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"os"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/confirm"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/table"
+)
+
+// deleteLeftovers waits until the given cluster is gone, if it wasn't being watched already, and
+// then deletes the AWS resources with the given infrastructure identifier that were left behind.
+// Failures to find or delete the resources are reported as warnings, as the cluster itself has
+// been deleted.
+func deleteLeftovers(r *runtime.Runtime, cluster *cmv1.Cluster, infraID string, watched bool) error {
+	reporter := r.Reporter()
+
+	if !watched {
+		progress := reporter.StartProgress("Waiting for cluster '%s' to be uninstalled...", cluster.Name())
+		err := r.OCM().WaitForUninstall(cluster.ID(), ocm.DefaultWatchInterval,
+			ocm.DefaultWatchTimeout)
+		progress.Stop()
+		if err != nil {
+			return fmt.Errorf("Failed to wait for cluster '%s' to be uninstalled, the AWS resources "+
+				"that it leaves behind won't be deleted: %w", cluster.Name(), err)
+		}
+	}
+
+	awsClient := r.WithAWSRegion(cluster.Region().ID()).AWSClient()
+	reporter.Infof("Looking for AWS resources left behind by cluster '%s'...", cluster.Name())
+	leftovers, err := awsClient.FindLeftovers(infraID, cluster.ID())
+	if err != nil {
+		reporter.Warnf("Failed to find AWS resources left behind by cluster '%s': %v", cluster.Name(), err)
+		return nil
+	}
+	if len(leftovers) == 0 {
+		reporter.Infof("No AWS resources were left behind by cluster '%s'", cluster.Name())
+		return nil
+	}
+
+	writer := table.NewWriter(os.Stdout)
+	fmt.Fprintf(writer, "TYPE\tNAME\n")
+	for _, leftover := range leftovers {
+		fmt.Fprintf(writer, "%s\t%s\n", leftover.Type, leftover.Name)
+	}
+	writer.Flush()
+	if !confirm.Confirm("delete the %d AWS resources left behind by cluster %s", len(leftovers),
+		cluster.Name()) {
+		return nil
+	}
+
+	failed := 0
+	for _, leftover := range leftovers {
+		reporter.Debugf("Deleting %s '%s'", leftover.Type, leftover.Name)
+		err = awsClient.DeleteLeftover(leftover)
+		if err != nil {
+			reporter.Warnf("Failed to delete %s '%s': %v", leftover.Type, leftover.Name, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		reporter.Warnf("%d of the %d AWS resources left behind by cluster '%s' couldn't be deleted",
+			failed, len(leftovers), cluster.Name())
+		return nil
+	}
+	reporter.Infof("Deleted the %d AWS resources left behind by cluster '%s'", len(leftovers),
+		cluster.Name())
+	return nil
+}
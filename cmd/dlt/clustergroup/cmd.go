@@ -0,0 +1,68 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustergroup
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/confirm"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm/roles"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var Cmd = &cobra.Command{
+	Use:     "cluster-group NAME",
+	Aliases: []string{"clustergroup"},
+	Short:   "Delete a group of clusters",
+	Long:    "Delete a group of clusters from the configuration file. The clusters aren't changed.",
+	Example: `  # Delete the cluster group named "prod-eu"
+  rosa delete cluster-group prod-eu`,
+	RunE: run,
+}
+
+func init() {
+	roles.Require(Cmd, roles.None)
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	if len(argv) != 1 {
+		return rosaerrors.Usagef("Expected exactly one command line parameter containing the name of the group")
+	}
+	name := argv[0]
+
+	_, err := cluster.GetGroupSearch(name)
+	if err != nil {
+		return err
+	}
+	if !confirm.Confirm("delete cluster group %s", name) {
+		return nil
+	}
+	err = cluster.SaveGroup(name, "")
+	if err != nil {
+		return fmt.Errorf("Failed to delete cluster group '%s': %w", name, err)
+	}
+	reporter.Infof("Deleted cluster group '%s'", name)
+
+	return nil
+}
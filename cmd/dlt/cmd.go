@@ -21,11 +21,13 @@ import (
 
 	"github.com/openshift/moactl/cmd/dlt/admin"
 	"github.com/openshift/moactl/cmd/dlt/cluster"
+	"github.com/openshift/moactl/cmd/dlt/clustergroup"
 	"github.com/openshift/moactl/cmd/dlt/idp"
 	"github.com/openshift/moactl/cmd/dlt/ingress"
 	"github.com/openshift/moactl/cmd/dlt/machinepool"
 	"github.com/openshift/moactl/cmd/dlt/upgrade"
-	"github.com/openshift/moactl/pkg/confirm"
+	"github.com/openshift/moactl/pkg/dryrun"
+	"github.com/openshift/moactl/pkg/ocm/roles"
 )
 
 var Cmd = &cobra.Command{
@@ -36,11 +38,14 @@ var Cmd = &cobra.Command{
 }
 
 func init() {
+	roles.Require(Cmd, roles.Update)
+
 	flags := Cmd.PersistentFlags()
-	confirm.AddFlag(flags)
+	dryrun.AddFlag(flags)
 
 	Cmd.AddCommand(admin.Cmd)
 	Cmd.AddCommand(cluster.Cmd)
+	Cmd.AddCommand(clustergroup.Cmd)
 	Cmd.AddCommand(idp.Cmd)
 	Cmd.AddCommand(ingress.Cmd)
 	Cmd.AddCommand(machinepool.Cmd)
@@ -17,27 +17,21 @@ limitations under the License.
 package ingress
 
 import (
-	"os"
+	"fmt"
 	"regexp"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/moactl/pkg/aws"
-	"github.com/openshift/moactl/pkg/confirm"
-	"github.com/openshift/moactl/pkg/logging"
-	"github.com/openshift/moactl/pkg/ocm"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
 // Regular expression to used to make sure that the identifier given by the
 // user is safe and that it there is no risk of SQL injection:
 var ingressKeyRE = regexp.MustCompile(`^[a-z0-9]{3,5}$`)
 
-var args struct {
-	clusterKey string
-}
-
 var Cmd = &cobra.Command{
 	Use:     "ingress",
 	Aliases: []string{"ingresses", "route", "routes"},
@@ -48,102 +42,49 @@ var Cmd = &cobra.Command{
 
   # Delete secondary ingress using the sub-domain name
   rosa delete ingress --cluster=mycluster apps2`,
-	Run: run,
+	RunE: run,
 }
 
 func init() {
-	flags := Cmd.Flags()
-
-	flags.StringVarP(
-		&args.clusterKey,
-		"cluster",
-		"c",
-		"",
-		"Name or ID of the cluster to delete the ingress from (required).",
-	)
-	Cmd.MarkFlagRequired("cluster")
+	clusterprovider.UseKey(Cmd)
 }
 
-func run(_ *cobra.Command, argv []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
 
 	// Check command line arguments:
 	if len(argv) != 1 {
-		reporter.Errorf(
+		return rosaerrors.Usagef(
 			"Expected exactly one command line parameter containing the id of the ingress",
 		)
-		os.Exit(1)
 	}
 
 	ingressID := argv[0]
 	if !ingressKeyRE.MatchString(ingressID) {
-		reporter.Errorf(
+		return rosaerrors.Usagef(
 			"Ingress  identifier '%s' isn't valid: it must contain only four letters or digits",
 			ingressID,
 		)
-		os.Exit(1)
-	}
-
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
-	clusterKey := args.clusterKey
-	if !ocm.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
-	}
-
-	// Create the AWS client:
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
 	}
 
-	awsCreator, err := awsClient.GetCreator()
+	clusterKey, err := clusterprovider.GetKey(r, nil)
 	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		return err
 	}
 
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
-	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
-
-	// Get the client for the OCM collection of clusters:
-	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
-
 	// Try to find the cluster:
 	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
 	if err != nil {
-		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
 	}
 
 	// Try to find the ingress:
 	reporter.Debugf("Loading ingresses for cluster '%s'", clusterKey)
-	ingresses, err := ocm.GetIngresses(clustersCollection, cluster.ID())
+	ingresses, err := r.OCM().GetIngresses(cluster.ID())
 	if err != nil {
-		reporter.Errorf("Failed to get ingresses for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get ingresses for cluster '%s': %w", clusterKey, err)
 	}
 
 	var ingress *cmv1.Ingress
@@ -159,23 +100,17 @@ func run(_ *cobra.Command, argv []string) {
 		}
 	}
 	if ingress == nil {
-		reporter.Errorf("Failed to get ingress '%s' for cluster '%s'", ingressID, clusterKey)
-		os.Exit(1)
+		return rosaerrors.NotFoundf("Failed to get ingress '%s' for cluster '%s'", ingressID, clusterKey)
 	}
 
-	if confirm.Confirm("delete ingress %s on cluster %s", ingressID, clusterKey) {
+	if clusterprovider.ConfirmDestructive(r, cluster, "delete ingress %s on cluster %s", ingressID, clusterKey) {
 		reporter.Debugf("Deleting ingress '%s' on cluster '%s'", ingress.ID(), clusterKey)
-		res, err := clustersCollection.
-			Cluster(cluster.ID()).
-			Ingresses().
-			Ingress(ingress.ID()).
-			Delete().
-			Send()
+		err = r.OCM().DeleteIngress(cluster.ID(), ingress.ID())
 		if err != nil {
-			reporter.Debugf(err.Error())
-			reporter.Errorf("Failed to delete ingress '%s' on cluster '%s': %s",
-				ingress.ID(), clusterKey, res.Error().Reason())
-			os.Exit(1)
+			return fmt.Errorf("Failed to delete ingress '%s' on cluster '%s': %w",
+				ingress.ID(), clusterKey, err)
 		}
 	}
+
+	return nil
 }
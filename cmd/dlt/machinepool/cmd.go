@@ -17,17 +17,16 @@ limitations under the License.
 package machinepool
 
 import (
-	"os"
+	"fmt"
 	"regexp"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/moactl/pkg/aws"
-	"github.com/openshift/moactl/pkg/confirm"
-	"github.com/openshift/moactl/pkg/logging"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/ocm"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
 // Regular expression to used to make sure that the identifier given by the
@@ -35,7 +34,7 @@ import (
 var machinePoolKeyRE = regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
 
 var args struct {
-	clusterKey string
+	force bool
 }
 
 var Cmd = &cobra.Command{
@@ -44,105 +43,63 @@ var Cmd = &cobra.Command{
 	Short:   "Delete machine pool",
 	Long:    "Delete the additional machine pool from a cluster.",
 	Example: `  # Delete machine pool with ID mp-1 from a cluster named 'mycluster'
-  rosa delete machinepool --cluster=mycluster mp-1`,
-	Run: run,
+  rosa delete machinepool --cluster=mycluster mp-1
+
+  # Delete machine pool mp-1 even if it is protected against deletion
+  rosa delete machinepool --cluster=mycluster mp-1 --force`,
+	RunE: run,
 }
 
 func init() {
 	flags := Cmd.Flags()
 
-	flags.StringVarP(
-		&args.clusterKey,
-		"cluster",
-		"c",
-		"",
-		"Name or ID of the cluster to delete the machine pool from (required).",
+	clusterprovider.UseKey(Cmd)
+
+	flags.BoolVar(
+		&args.force,
+		"force",
+		false,
+		"Delete the machine pool even if it is protected against deletion.",
 	)
-	Cmd.MarkFlagRequired("cluster")
 }
 
-func run(_ *cobra.Command, argv []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
 
 	// Check command line arguments:
 	if len(argv) != 1 {
-		reporter.Errorf(
+		return rosaerrors.Usagef(
 			"Expected exactly one command line parameter containing the id of the machine pool",
 		)
-		os.Exit(1)
 	}
 
 	machinePoolID := argv[0]
 	if !machinePoolKeyRE.MatchString(machinePoolID) {
-		reporter.Errorf("Expected a valid identifier for the machine pool")
-		os.Exit(1)
-	}
-
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
-	clusterKey := args.clusterKey
-	if !ocm.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
-	}
-
-	if machinePoolID == "default" {
-		reporter.Errorf("Machine pool '%s' cannot be deleted from cluster '%s'", machinePoolID, clusterKey)
-		os.Exit(1)
-	}
-
-	// Create the AWS client:
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		return rosaerrors.Usagef("Expected a valid identifier for the machine pool")
 	}
 
-	awsCreator, err := awsClient.GetCreator()
+	clusterKey, err := clusterprovider.GetKey(r, nil)
 	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		return err
 	}
 
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+	if machinePoolID == "default" {
+		return rosaerrors.Usagef("Machine pool '%s' cannot be deleted from cluster '%s'", machinePoolID, clusterKey)
 	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
-
-	// Get the client for the OCM collection of clusters:
-	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
 
 	// Try to find the cluster:
 	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
 	if err != nil {
-		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
 	}
 
 	// Try to find the machine pool:
 	reporter.Debugf("Loading machine pools for cluster '%s'", clusterKey)
-	machinePools, err := ocm.GetMachinePools(clustersCollection, cluster.ID())
+	machinePools, err := r.OCM().GetMachinePools(cluster.ID())
 	if err != nil {
-		reporter.Errorf("Failed to get machine pools for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get machine pools for cluster '%s': %w", clusterKey, err)
 	}
 
 	var machinePool *cmv1.MachinePool
@@ -152,23 +109,35 @@ func run(_ *cobra.Command, argv []string) {
 		}
 	}
 	if machinePool == nil {
-		reporter.Errorf("Failed to get machine pool '%s' for cluster '%s'", machinePoolID, clusterKey)
-		os.Exit(1)
+		return rosaerrors.NotFoundf("Failed to get machine pool '%s' for cluster '%s'", machinePoolID, clusterKey)
 	}
 
-	if confirm.Confirm("delete machine pool '%s' on cluster '%s'", machinePoolID, clusterKey) {
+	if ocm.IsMachinePoolDeleteProtected(cluster, machinePoolID) {
+		if !args.force {
+			return rosaerrors.Conflictf("Machine pool '%s' on cluster '%s' is protected against deletion, use "+
+				"'--force' to delete it anyway or 'rosa edit machinepool --enable-delete-protection=false' "+
+				"to remove the protection", machinePoolID, clusterKey)
+		}
+		reporter.Warnf("Machine pool '%s' on cluster '%s' is protected against deletion, deleting it "+
+			"because '--force' was used", machinePoolID, clusterKey)
+	}
+
+	if clusterprovider.ConfirmDestructive(r, cluster, "delete machine pool '%s' on cluster '%s'", machinePoolID, clusterKey) {
 		reporter.Debugf("Deleting machine pool '%s' on cluster '%s'", machinePool.ID(), clusterKey)
-		res, err := clustersCollection.
-			Cluster(cluster.ID()).
-			MachinePools().
-			MachinePool(machinePool.ID()).
-			Delete().
-			Send()
+		err = r.OCM().DeleteMachinePool(cluster.ID(), machinePool.ID())
 		if err != nil {
-			reporter.Debugf(err.Error())
-			reporter.Errorf("Failed to delete machine pool '%s' on cluster '%s': %s",
-				machinePool.ID(), clusterKey, res.Error().Reason())
-			os.Exit(1)
+			return fmt.Errorf("Failed to delete machine pool '%s' on cluster '%s': %w",
+				machinePool.ID(), clusterKey, err)
+		}
+
+		// Remove the protection, so that it doesn't apply to a new machine pool with the same
+		// identifier:
+		err = r.OCM().SetMachinePoolDeleteProtection(cluster, machinePoolID, false)
+		if err != nil {
+			reporter.Warnf("Failed to remove delete protection of machine pool '%s' on cluster '%s': %v",
+				machinePoolID, clusterKey, err)
 		}
 	}
+
+	return nil
 }
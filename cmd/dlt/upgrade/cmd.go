@@ -17,132 +17,86 @@ limitations under the License.
 package upgrade
 
 import (
-	"os"
+	"fmt"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/moactl/pkg/aws"
 	c "github.com/openshift/moactl/pkg/cluster"
 	"github.com/openshift/moactl/pkg/confirm"
-	"github.com/openshift/moactl/pkg/logging"
-	"github.com/openshift/moactl/pkg/ocm"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/ocm/upgrades"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
-var args struct {
-	clusterKey string
-}
-
 var Cmd = &cobra.Command{
 	Use:     "upgrade",
 	Aliases: []string{"upgrades"},
 	Short:   "Cancel cluster upgrade",
 	Long:    "Cancel scheduled cluster upgrade",
-	Run:     run,
+	RunE:    run,
 }
 
 func init() {
 	flags := Cmd.Flags()
 	flags.SortFlags = false
 
-	flags.StringVarP(
-		&args.clusterKey,
-		"cluster",
-		"c",
-		"",
-		"Name or ID of the cluster to cancel the upgrade for (required)",
-	)
-	Cmd.MarkFlagRequired("cluster")
+	c.UseKey(Cmd)
 }
 
-func run(cmd *cobra.Command, _ []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
-
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
-	clusterKey := args.clusterKey
-	if !c.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
-	}
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
 
-	// Create the AWS client:
-	var err error
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Build()
+	clusterKey, err := c.GetKey(r, nil)
 	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		return err
 	}
 
-	awsCreator, err := awsClient.GetCreator()
-	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
-	}
-
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
-	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
-
-	// Get the client for the OCM collection of clusters:
-	ocmClient := ocmConnection.ClustersMgmt().V1()
-
 	// Try to find the cluster:
 	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := ocm.GetCluster(ocmClient.Clusters(), clusterKey, awsCreator.ARN)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
 	if err != nil {
-		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
 	}
 
 	if cluster.State() != cmv1.ClusterStateReady {
-		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
-		os.Exit(1)
+		return rosaerrors.Conflictf("Cluster '%s' is not yet ready", clusterKey)
 	}
 
-	scheduledUpgrade, err := upgrades.GetScheduledUpgrade(ocmClient, cluster.ID())
+	scheduledUpgrade, err := r.OCM().GetScheduledUpgrade(cluster.ID())
 	if err != nil {
-		reporter.Errorf("Failed to get scheduled upgrades for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get scheduled upgrades for cluster '%s': %w", clusterKey, err)
 	}
 	if scheduledUpgrade == nil {
 		reporter.Warnf("There are no scheduled upgrades on cluster '%s'", clusterKey)
-		os.Exit(0)
+		return nil
+	}
+
+	state, err := r.OCM().GetUpgradeState(cluster.ID(), scheduledUpgrade.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get state of scheduled upgrade on cluster '%s': %w", clusterKey, err)
+	}
+	if state.Value() == upgrades.UpgradeStateStarted {
+		return rosaerrors.Conflictf("The upgrade of cluster '%s' to version %s has already started and can't be canceled",
+			clusterKey, scheduledUpgrade.Version())
 	}
 
-	if confirm.Confirm("cancel scheduled upgrade on cluster %s", clusterKey) {
+	if confirm.Confirm("cancel scheduled upgrade to version %s on cluster %s",
+		upgrades.FormatScheduledUpgrade(scheduledUpgrade, state), clusterKey) {
 		reporter.Debugf("Deleting scheduled upgrade for cluster '%s'", clusterKey)
-		canceled, err := upgrades.CancelUpgrade(ocmClient, cluster.ID())
+		canceled, err := r.OCM().CancelUpgrade(cluster.ID())
 		if err != nil {
-			reporter.Errorf("Failed to cancel scheduled upgrade on cluster '%s': %v", clusterKey, err)
-			os.Exit(1)
+			return fmt.Errorf("Failed to cancel scheduled upgrade on cluster '%s': %w", clusterKey, err)
 		}
 
 		if !canceled {
 			reporter.Warnf("There were no scheduled upgrades on cluster '%s'", clusterKey)
-			os.Exit(0)
+			return nil
 		}
 
 		reporter.Infof("Successfully canceled scheduled upgrade on cluster '%s'", clusterKey)
 	}
+
+	return nil
 }
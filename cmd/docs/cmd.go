@@ -18,6 +18,7 @@ package docs
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/cobra/doc"
@@ -58,6 +59,11 @@ func init() {
 func run(cmd *cobra.Command, _ []string) (err error) {
 	cmd.Root().DisableAutoGenTag = true
 
+	err = os.MkdirAll(args.dir, 0755)
+	if err != nil {
+		return err
+	}
+
 	switch args.format {
 	case "markdown":
 		err = doc.GenMarkdownTree(cmd.Root(), args.dir)
@@ -70,6 +76,9 @@ func run(cmd *cobra.Command, _ []string) (err error) {
 		err = doc.GenManTree(cmd.Root(), header, args.dir)
 	case "restructured":
 		err = doc.GenReSTTree(cmd.Root(), args.dir)
+	default:
+		err = fmt.Errorf("Format '%s' isn't valid, valid options are 'markdown', 'man' and 'restructured'",
+			args.format)
 	}
 
 	if err != nil {
@@ -19,7 +19,9 @@ package download
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/moactl/cmd/download/installer"
 	"github.com/openshift/moactl/cmd/download/oc"
+	"github.com/openshift/moactl/cmd/download/rosa"
 )
 
 var Cmd = &cobra.Command{
@@ -29,5 +31,7 @@ var Cmd = &cobra.Command{
 }
 
 func init() {
+	Cmd.AddCommand(installer.Cmd)
 	Cmd.AddCommand(oc.Cmd)
+	Cmd.AddCommand(rosa.Cmd)
 }
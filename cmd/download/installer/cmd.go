@@ -0,0 +1,62 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installer
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/cmd/download/oc"
+	"github.com/openshift/moactl/pkg/download"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var args struct {
+	version string
+	dir     string
+	keyring string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "openshift-install",
+	Aliases: []string{"installer"},
+	Short:   "Download OpenShift installer",
+	Long: "Downloads the OpenShift installer from the official mirror, verifies its checksum and " +
+		"installs it in the given directory. By default the newest version is downloaded, use " +
+		"'--cluster' to download the version that matches a cluster.",
+	Example: `  # Download the installer matching the version of cluster "mycluster"
+  rosa download openshift-install --cluster=mycluster`,
+	RunE: run,
+}
+
+func init() {
+	oc.AddFlags(Cmd, &args.version, &args.dir, &args.keyring)
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	paths, err := oc.Install(r, download.Installer, args.version, args.dir, args.keyring)
+	if err != nil {
+		return err
+	}
+	reporter.Infof("Successfully installed %s", strings.Join(paths, ", "))
+
+	return nil
+}
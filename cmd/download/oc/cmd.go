@@ -18,127 +18,124 @@ package oc
 
 import (
 	"fmt"
-	"io"
-	"net/http"
-	"os"
-	"runtime"
 	"strings"
 
-	"github.com/dustin/go-humanize"
 	"github.com/spf13/cobra"
 
-	rprtr "github.com/openshift/moactl/pkg/reporter"
-
 	"github.com/openshift/moactl/cmd/verify/oc"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/download"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
+var args struct {
+	version string
+	dir     string
+	keyring string
+}
+
 var Cmd = &cobra.Command{
 	Use:     "openshift-client",
 	Aliases: []string{"oc", "openshift"},
 	Short:   "Download OpenShift client tools",
-	Long:    "Downloads to latest compatible version of the OpenShift client tools.",
-	Example: `  # Download oc client tools
-  rosa download oc`,
-	Run: run,
+	Long: "Downloads the OpenShift client tools, 'oc' and 'kubectl', from the official mirror, " +
+		"verifies their checksum and installs them in the given directory. By default the newest " +
+		"version is downloaded, use '--cluster' to download the version that matches a cluster.",
+	Example: `  # Download oc client tools to the current directory
+  rosa download oc
+
+  # Download the client tools matching the version of cluster "mycluster" to $HOME/bin
+  rosa download oc --cluster=mycluster --output-dir=$HOME/bin
+
+  # Download a specific version, verifying the signature with the given keyring
+  rosa download oc --version=4.10.3 --keyring=release-keys.gpg`,
+	RunE: run,
 }
 
-func run(cmd *cobra.Command, argv []string) {
-	reporter := rprtr.CreateReporterOrExit()
-
-	// Verify whether `oc` is installed
-	oc.Cmd.Run(cmd, argv)
-
-	platform := getPlatform()
-	extension := getExtension()
-
-	filename := fmt.Sprintf("openshift-client-%s.%s", platform, extension)
-	downloadURL := fmt.Sprintf("https://mirror.openshift.com/pub/openshift-v4/clients/ocp/latest/%s", filename)
-
-	reporter.Infof("Downloading %s", downloadURL)
-
-	err := download(downloadURL, filename)
-	if err != nil {
-		reporter.Errorf("%s", err)
-		os.Exit(1)
-	}
-
-	reporter.Infof("Successfully downloaded %s", filename)
+func init() {
+	AddFlags(Cmd, &args.version, &args.dir, &args.keyring)
 }
 
-// Get the platform name used on the oc tarball filename
-func getPlatform() string {
-	if runtime.GOOS == "darwin" {
-		return "mac"
-	}
-	return runtime.GOOS
+// AddFlags adds the flags that select the version of the client tools, the directory where they
+// are installed and the keyring used to verify them. They are shared with the commands that
+// download other client tools.
+func AddFlags(cmd *cobra.Command, version *string, dir *string, keyring *string) {
+	flags := cmd.Flags()
+	flags.StringVar(
+		version,
+		"version",
+		"",
+		"Version of OpenShift of the tools, like '4.10.3'. The default is the version of the "+
+			"cluster given with '--cluster', or the newest version if no cluster is given.",
+	)
+	flags.StringVar(
+		dir,
+		"output-dir",
+		".",
+		"Directory where the binaries are installed.",
+	)
+	flags.StringVar(
+		keyring,
+		"keyring",
+		"",
+		"File containing the keys used to verify the signature of the checksums. The default is '"+
+			download.DefaultKeyring+"' if it exists, otherwise the signature isn't verified.",
+	)
 }
 
-// Get the extension used for the compressed oc file
-func getExtension() string {
-	if runtime.GOOS == "windows" {
-		return "zip"
-	}
-	return "tar.gz"
-}
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
 
-// download will download a url to a local file. It's efficient because it will
-// write as it downloads and not load the whole file into memory. We pass an io.TeeReader
-// into Copy() to report progress on the download.
-func download(url string, filename string) error {
-	// Create the file, but give it a tmp file extension, this means we won't overwrite a
-	// file until it's downloaded, but we'll remove the tmp extension once downloaded.
-	out, err := os.Create(filename + ".tmp")
+	// Verify whether `oc` is installed
+	err := oc.Cmd.RunE(cmd, argv)
 	if err != nil {
 		return err
 	}
 
-	// Get the data
-	// nolint:gosec
-	resp, err := http.Get(url)
+	paths, err := Install(r, download.OpenShiftClient, args.version, args.dir, args.keyring)
 	if err != nil {
-		out.Close()
-		return err
-	}
-	defer resp.Body.Close()
-
-	// Create our progress reporter and pass it to be used alongside our writer
-	counter := &WriteCounter{}
-	if _, err = io.Copy(out, io.TeeReader(resp.Body, counter)); err != nil {
-		out.Close()
 		return err
 	}
+	reporter.Infof("Successfully installed %s", strings.Join(paths, ", "))
 
-	// The progress use the same line so print a new line once it's finished downloading
-	fmt.Print("\n")
-
-	// Close the file without defer so it can happen before Rename()
-	out.Close()
-
-	if err = os.Rename(filename+".tmp", filename); err != nil {
-		return err
-	}
 	return nil
 }
 
-// WriteCounter counts the number of bytes written to it. It implements to the io.Writer interface
-// and we can pass this into io.TeeReader() which will report progress on each write cycle.
-type WriteCounter struct {
-	Total uint64
-}
-
-func (wc *WriteCounter) Write(p []byte) (int, error) {
-	n := len(p)
-	wc.Total += uint64(n)
-	wc.PrintProgress()
-	return n, nil
-}
+// Install downloads the given tool and installs it in the given directory, exiting on failure.
+// When no version is given, the version of the cluster selected with '--cluster' is used, if any.
+func Install(r *runtime.Runtime, tool *download.Tool, version string, dir string,
+	keyring string) ([]string, error) {
+	reporter := r.Reporter()
 
-func (wc WriteCounter) PrintProgress() {
-	// Clear the line by using a character return to go back to the start and remove
-	// the remaining characters by filling it with spaces
-	fmt.Printf("\r%s", strings.Repeat(" ", 35))
+	if version != "" && clusterprovider.Key() != "" {
+		return nil, rosaerrors.Usagef("At most one of '--version' or '--%s' may be specified", clusterprovider.KeyFlag)
+	}
+	if clusterKey := clusterprovider.Key(); clusterKey != "" {
+		if !clusterprovider.IsValidClusterKey(clusterKey) {
+			return nil, rosaerrors.Usagef("Cluster name, identifier or external identifier '%s' isn't valid",
+				clusterKey)
+		}
+		reporter.Debugf("Loading cluster '%s'", clusterKey)
+		cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+		}
+		version = cluster.OpenshiftVersion()
+		if version == "" {
+			return nil, rosaerrors.Conflictf("Cluster '%s' doesn't have a version yet", clusterKey)
+		}
+		reporter.Infof("Using version '%s' of cluster '%s'", version, clusterKey)
+	}
 
-	// Return again and print current status of download
-	// We use the humanize package to print the bytes in a meaningful way (e.g. 10 MB)
-	fmt.Printf("\rDownloading... %s complete", humanize.Bytes(wc.Total))
+	paths, err := tool.Install(reporter, download.Options{
+		Version: version,
+		Dir:     dir,
+		Keyring: keyring,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to install %s: %w", tool.Name, err)
+	}
+	return paths, nil
 }
@@ -0,0 +1,186 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rosa
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/download"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/info"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var args struct {
+	channel string
+	check   bool
+}
+
+var Cmd = &cobra.Command{
+	Use:   "rosa",
+	Short: "Update the rosa tool",
+	Long: "Replaces the rosa binary with the newest release of the update channel. The channel is " +
+		"taken from the 'update' section of the configuration file, and is 'stable' by default.",
+	Example: `  # Update rosa to the newest stable release, same as 'rosa upgrade rosa'
+  rosa download rosa
+
+  # Update rosa only to the patch releases of version 1.2
+  rosa download rosa --channel=1.2
+
+  # Check if there is a newer release without updating
+  rosa download rosa --check`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVar(
+		&args.channel,
+		"channel",
+		"",
+		"Update channel: 'stable' for the newest release, 'latest' to include pre-releases, or a "+
+			"minor version like '1.2' for the patch releases of that version. Overrides the channel "+
+			"of the configuration file.",
+	)
+	flags.BoolVar(
+		&args.check,
+		"check",
+		false,
+		"Only check if there is a newer release in the update channel.",
+	)
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	channel := args.channel
+	if channel == "" {
+		var err error
+		channel, err = info.ConfiguredChannel()
+		if err != nil {
+			return fmt.Errorf("Failed to get the update channel: %w", err)
+		}
+	}
+	err := info.ValidateChannel(channel)
+	if err != nil {
+		return err
+	}
+
+	releases, err := info.GetChannelReleases(channel)
+	if err != nil {
+		return fmt.Errorf("Failed to get the published releases: %w", err)
+	}
+	newer, err := info.ReleasesSince(releases, info.Version)
+	if err != nil {
+		return err
+	}
+	if len(newer) == 0 {
+		reporter.Infof("Version '%s' is the newest release of channel '%s'", info.Version, channel)
+		return nil
+	}
+	release := newer[0]
+	if args.check {
+		reporter.Infof("Release '%s' of channel '%s' is newer than version '%s'",
+			release.Version, channel, info.Version)
+		return nil
+	}
+
+	assetName := fmt.Sprintf("rosa-%s-%s", goruntime.GOOS, goruntime.GOARCH)
+	if goruntime.GOOS == "windows" {
+		assetName += ".exe"
+	}
+	downloadURL, ok := release.Assets[assetName]
+	if !ok {
+		return rosaerrors.NotFoundf("Release '%s' doesn't contain a binary for %s/%s",
+			release.Version, goruntime.GOOS, goruntime.GOARCH)
+	}
+
+	executable, err := os.Executable()
+	if err == nil {
+		executable, err = filepath.EvalSymlinks(executable)
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to find the rosa binary: %w", err)
+	}
+
+	// Releases that publish the checksums of the binaries are verified, older ones can't be:
+	checksum := ""
+	if checksumURL, ok := release.Assets[assetName+".sha256"]; ok {
+		data, err := download.Get(checksumURL)
+		if err != nil {
+			return fmt.Errorf("Failed to get checksum of release '%s': %w", release.Version, err)
+		}
+		checksum = download.ParseChecksums(data)[assetName]
+		if checksum == "" {
+			return fmt.Errorf("Checksum of release '%s' doesn't contain '%s'", release.Version, assetName)
+		}
+	} else {
+		reporter.Warnf("Release '%s' doesn't publish checksums, the binary can't be verified",
+			release.Version)
+	}
+
+	reporter.Infof("Downloading release '%s' from %s", release.Version, downloadURL)
+	err = replace(reporter, executable, downloadURL, checksum)
+	if err != nil {
+		return fmt.Errorf("Failed to update '%s': %w", executable, err)
+	}
+	reporter.Infof("Updated rosa from version '%s' to version '%s'", info.Version, release.Version)
+
+	return nil
+}
+
+// replace downloads the binary from the given URL next to the executable, so that it's in the same
+// file system, verifies its checksum, if given, and then renames it over the executable. The
+// executable is moved away first, as some systems don't allow replacing a file that is running.
+func replace(reporter *rprtr.Object, executable string, url string, checksum string) error {
+	newPath := executable + ".new"
+	oldPath := executable + ".old"
+
+	err := download.File(reporter, url, newPath, 0755)
+	if err == nil && checksum != "" {
+		err = download.VerifyChecksum(newPath, checksum)
+	}
+	if err != nil {
+		os.Remove(newPath)
+		return err
+	}
+	err = os.Rename(executable, oldPath)
+	if err != nil {
+		os.Remove(newPath)
+		return err
+	}
+	err = os.Rename(newPath, executable)
+	if err != nil {
+		// Put back the original executable:
+		os.Rename(oldPath, executable)
+		os.Remove(newPath)
+		return err
+	}
+
+	// This fails on systems where the running binary can't be removed, and then the old binary is
+	// left behind, which is harmless:
+	os.Remove(oldPath)
+	return nil
+}
@@ -0,0 +1,385 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"fmt"
+	"strconv"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/ocm/autoscalers"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var args struct {
+	balanceSimilarNodeGroups      bool
+	maxNodeProvisionTime          string
+	scaleDownUtilizationThreshold float64
+	maxNodesTotal                 int
+	minCores                      int
+	maxCores                      int
+	minMemory                     int
+	maxMemory                     int
+}
+
+// autoscalerFlags are the names of the flags that change the options of the autoscaler.
+var autoscalerFlags = []string{
+	"balance-similar-node-groups",
+	"max-node-provision-time",
+	"scale-down-utilization-threshold",
+	"max-nodes-total",
+	"min-cores",
+	"max-cores",
+	"min-memory",
+	"max-memory",
+}
+
+var Cmd = &cobra.Command{
+	Use:   "autoscaler",
+	Short: "Edit the cluster autoscaler",
+	Long: "Edit the options of the cluster autoscaler, which adds and removes nodes of the " +
+		"machine pools that have autoscaling enabled. Options that aren't given keep their " +
+		"current values.",
+	Example: `  # Remove nodes whose pods request less than 40% of their resources
+  rosa edit autoscaler --cluster=mycluster --scale-down-utilization-threshold=0.4
+
+  # Limit the cluster to 20 nodes, 200 cores and 800 GiB of memory
+  rosa edit autoscaler --cluster=mycluster --max-nodes-total=20 --max-cores=200 --max-memory=800
+
+  # Edit the autoscaler interactively
+  rosa edit autoscaler --cluster=mycluster --interactive`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	clusterprovider.UseKey(Cmd)
+
+	flags.Float64Var(
+		&args.scaleDownUtilizationThreshold,
+		"scale-down-utilization-threshold",
+		0,
+		"Fraction of the resources of a node, between 0 and 1, requested by its pods below which "+
+			"the node can be removed.",
+	)
+	flags.StringVar(
+		&args.maxNodeProvisionTime,
+		"max-node-provision-time",
+		"",
+		"Maximum time that the autoscaler waits for a node to be provisioned, like '15m'.",
+	)
+	flags.BoolVar(
+		&args.balanceSimilarNodeGroups,
+		"balance-similar-node-groups",
+		false,
+		"Keep the same number of nodes in the machine pools that have the same instance type "+
+			"and labels.",
+	)
+	flags.IntVar(
+		&args.maxNodesTotal,
+		"max-nodes-total",
+		0,
+		"Maximum number of nodes of the cluster.",
+	)
+	flags.IntVar(
+		&args.minCores,
+		"min-cores",
+		0,
+		"Minimum number of cores of the cluster.",
+	)
+	flags.IntVar(
+		&args.maxCores,
+		"max-cores",
+		0,
+		"Maximum number of cores of the cluster.",
+	)
+	flags.IntVar(
+		&args.minMemory,
+		"min-memory",
+		0,
+		"Minimum memory of the cluster, in GiB.",
+	)
+	flags.IntVar(
+		&args.maxMemory,
+		"max-memory",
+		0,
+		"Maximum memory of the cluster, in GiB.",
+	)
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	clusterKey, err := clusterprovider.GetKey(r, nil)
+	if err != nil {
+		return err
+	}
+
+	// Without options the autoscaler is edited interactively:
+	changedFlags := false
+	for _, flag := range autoscalerFlags {
+		if cmd.Flags().Changed(flag) {
+			changedFlags = true
+		}
+	}
+	if !changedFlags && !interactive.Enabled() {
+		if !interactive.IsTerminal() {
+			return rosaerrors.Usagef("Expected at least one of the autoscaler options, see " +
+				"'rosa edit autoscaler --help'")
+		}
+		interactive.Enable()
+	}
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	if cluster.State() != cmv1.ClusterStateReady {
+		return rosaerrors.Conflictf("Cluster '%s' is not yet ready", clusterKey)
+	}
+
+	reporter.Debugf("Loading autoscaler of cluster '%s'", clusterKey)
+	current, err := r.OCM().GetAutoscaler(cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get autoscaler of cluster '%s': %w", clusterKey, err)
+	}
+
+	if interactive.Enabled() {
+		reporter.Infof("Interactive mode enabled.\n" +
+			"Any optional fields can be left empty and will not be updated.")
+		err = prompt(cmd, current)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Only the options that were given are changed:
+	builder := autoscalers.NewBuilder().Copy(current)
+	changed := cmd.Flags().Changed
+	if changed("scale-down-utilization-threshold") {
+		builder.ScaleDownUtilizationThreshold(args.scaleDownUtilizationThreshold)
+	}
+	if changed("max-node-provision-time") {
+		builder.MaxNodeProvisionTime(args.maxNodeProvisionTime)
+	}
+	if changed("balance-similar-node-groups") {
+		builder.BalanceSimilarNodeGroups(args.balanceSimilarNodeGroups)
+	}
+	if changed("max-nodes-total") {
+		builder.MaxNodesTotal(args.maxNodesTotal)
+	}
+	if changed("min-cores") || changed("max-cores") {
+		minCores, maxCores := currentRange(current, cores)
+		if changed("min-cores") {
+			minCores = args.minCores
+		}
+		if changed("max-cores") {
+			maxCores = args.maxCores
+		}
+		builder.Cores(minCores, maxCores)
+	}
+	if changed("min-memory") || changed("max-memory") {
+		minMemory, maxMemory := currentRange(current, memory)
+		if changed("min-memory") {
+			minMemory = args.minMemory
+		}
+		if changed("max-memory") {
+			maxMemory = args.maxMemory
+		}
+		builder.Memory(minMemory, maxMemory)
+	}
+	autoscaler, err := builder.Build()
+	if err != nil {
+		return err
+	}
+
+	reporter.Debugf("Updating autoscaler of cluster '%s'", clusterKey)
+	if current == nil {
+		err = r.OCM().CreateAutoscaler(cluster.ID(), autoscaler)
+	} else {
+		err = r.OCM().UpdateAutoscaler(cluster.ID(), autoscaler)
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to update autoscaler of cluster '%s': %w", clusterKey, err)
+	}
+	reporter.Infof("Updated autoscaler of cluster '%s'", clusterKey)
+
+	return nil
+}
+
+const (
+	cores  = "cores"
+	memory = "memory"
+)
+
+// currentRange returns the current minimum and maximum of the given resource, or zeros if there
+// are none.
+func currentRange(current *autoscalers.Autoscaler, resource string) (int, int) {
+	if current == nil || current.ResourceLimits == nil {
+		return 0, 0
+	}
+	value := current.ResourceLimits.Cores
+	if resource == memory {
+		value = current.ResourceLimits.Memory
+	}
+	if value == nil {
+		return 0, 0
+	}
+	return value.Min, value.Max
+}
+
+// prompt asks for the options of the autoscaler, using the current values as defaults, and sets
+// the flags of the ones that are answered.
+func prompt(cmd *cobra.Command, current *autoscalers.Autoscaler) error {
+	flags := cmd.Flags()
+
+	threshold := ""
+	provisionTime := ""
+	balance := false
+	maxNodesTotal := 0
+	if current != nil {
+		if current.ScaleDown != nil {
+			threshold = current.ScaleDown.UtilizationThreshold
+		}
+		provisionTime = current.MaxNodeProvisionTime
+		balance = current.BalanceSimilarNodeGroups
+		if current.ResourceLimits != nil {
+			maxNodesTotal = current.ResourceLimits.MaxNodesTotal
+		}
+	}
+	if flags.Changed("scale-down-utilization-threshold") {
+		threshold = strconv.FormatFloat(args.scaleDownUtilizationThreshold, 'f', -1, 64)
+	}
+	threshold, err := interactive.GetString(interactive.Input{
+		Question: "Scale down utilization threshold",
+		Help:     flags.Lookup("scale-down-utilization-threshold").Usage,
+		Default:  threshold,
+		Validators: []interactive.Validator{
+			func(answer interface{}) error {
+				str, ok := answer.(string)
+				if !ok || str == "" {
+					return nil
+				}
+				value, err := strconv.ParseFloat(str, 64)
+				if err != nil {
+					return fmt.Errorf("'%s' isn't a number", str)
+				}
+				return autoscalers.ValidateUtilizationThreshold(value)
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("Expected a valid scale down utilization threshold: %v", err)
+	}
+	if threshold != "" {
+		err = flags.Set("scale-down-utilization-threshold", threshold)
+		if err != nil {
+			return err
+		}
+	}
+
+	if flags.Changed("max-node-provision-time") {
+		provisionTime = args.maxNodeProvisionTime
+	}
+	provisionTime, err = interactive.GetString(interactive.Input{
+		Question: "Maximum node provision time",
+		Help:     flags.Lookup("max-node-provision-time").Usage,
+		Default:  provisionTime,
+		Validators: []interactive.Validator{
+			func(answer interface{}) error {
+				str, ok := answer.(string)
+				if !ok || str == "" {
+					return nil
+				}
+				return autoscalers.ValidateMaxNodeProvisionTime(str)
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("Expected a valid maximum node provision time: %v", err)
+	}
+	if provisionTime != "" {
+		err = flags.Set("max-node-provision-time", provisionTime)
+		if err != nil {
+			return err
+		}
+	}
+
+	if flags.Changed("balance-similar-node-groups") {
+		balance = args.balanceSimilarNodeGroups
+	}
+	balance, err = interactive.GetBool(interactive.Input{
+		Question: "Balance similar node groups",
+		Help:     flags.Lookup("balance-similar-node-groups").Usage,
+		Default:  balance,
+	})
+	if err != nil {
+		return fmt.Errorf("Expected a valid value for balance similar node groups: %v", err)
+	}
+	err = flags.Set("balance-similar-node-groups", strconv.FormatBool(balance))
+	if err != nil {
+		return err
+	}
+
+	if flags.Changed("max-nodes-total") {
+		maxNodesTotal = args.maxNodesTotal
+	}
+	minCores, maxCores := currentRange(current, cores)
+	minMemory, maxMemory := currentRange(current, memory)
+	for _, item := range []struct {
+		flag     string
+		question string
+		current  int
+	}{
+		{"max-nodes-total", "Maximum number of nodes", maxNodesTotal},
+		{"min-cores", "Minimum cores", minCores},
+		{"max-cores", "Maximum cores", maxCores},
+		{"min-memory", "Minimum memory (GiB)", minMemory},
+		{"max-memory", "Maximum memory (GiB)", maxMemory},
+	} {
+		dflt := item.current
+		if flags.Changed(item.flag) {
+			dflt, _ = flags.GetInt(item.flag)
+		}
+		value, err := interactive.GetInt(interactive.Input{
+			Question: item.question,
+			Help:     flags.Lookup(item.flag).Usage,
+			Default:  dflt,
+		})
+		if err != nil {
+			return fmt.Errorf("Expected a valid value for '%s': %v", item.flag, err)
+		}
+		if value != item.current || flags.Changed(item.flag) {
+			err = flags.Set(item.flag, strconv.Itoa(value))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
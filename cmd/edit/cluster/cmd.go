@@ -19,23 +19,19 @@ package cluster
 import (
 	"errors"
 	"fmt"
-	"os"
 	"time"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/moactl/pkg/aws"
 	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/interactive"
-	"github.com/openshift/moactl/pkg/logging"
 	"github.com/openshift/moactl/pkg/ocm"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
 var args struct {
-	clusterKey string
-
 	// Basic options
 	expirationTime     string
 	expirationDuration time.Duration
@@ -45,6 +41,9 @@ var args struct {
 
 	// Access control options
 	clusterAdmins bool
+
+	// Protection options
+	deleteProtection bool
 }
 
 var Cmd = &cobra.Command{
@@ -57,22 +56,19 @@ var Cmd = &cobra.Command{
   # Enable the cluster-admins group using the --cluster flag
   rosa edit cluster --cluster=mycluster --enable-cluster-admins
 
+  # Protect a cluster named "mycluster" against accidental deletion
+  rosa edit cluster mycluster --enable-delete-protection
+
   # Edit all options interactively
   rosa edit cluster -c mycluster --interactive`,
-	Run: run,
+	RunE: run,
 }
 
 func init() {
 	flags := Cmd.Flags()
 	flags.SortFlags = false
 
-	flags.StringVarP(
-		&args.clusterKey,
-		"cluster",
-		"c",
-		"",
-		"Name or ID of the cluster to edit.",
-	)
+	clusterprovider.UseKey(Cmd)
 
 	// Basic options
 	flags.StringVar(
@@ -106,39 +102,30 @@ func init() {
 		false,
 		"Enable the cluster-admins role for your cluster.",
 	)
+
+	// Protection options
+	flags.BoolVar(
+		&args.deleteProtection,
+		"enable-delete-protection",
+		false,
+		"Protect the cluster against accidental deletion. Protected clusters can only be deleted "+
+			"with 'rosa delete cluster --force'.",
+	)
 }
 
-func run(cmd *cobra.Command, argv []string) {
-	reporter := rprtr.CreateReporterOrExit()
-
-	// Check command line arguments:
-	clusterKey := args.clusterKey
-	if clusterKey == "" {
-		if len(argv) != 1 {
-			reporter.Errorf(
-				"Expected exactly one command line argument or flag containing the name " +
-					"or identifier of the cluster",
-			)
-			os.Exit(1)
-		}
-		clusterKey = argv[0]
-	}
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
 
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
-	if !clusterprovider.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
+	clusterKey, err := clusterprovider.GetKey(r, argv)
+	if err != nil {
+		return err
 	}
 
 	isInteractive := interactive.Enabled()
 	if !isInteractive {
 		changedFlags := false
-		for _, flag := range []string{"private", "enable-cluster-admins"} {
+		for _, flag := range []string{"private", "enable-cluster-admins", "enable-delete-protection"} {
 			if cmd.Flags().Changed(flag) {
 				changedFlags = true
 			}
@@ -148,51 +135,16 @@ func run(cmd *cobra.Command, argv []string) {
 		}
 	}
 
-	logger := logging.CreateLoggerOrExit(reporter)
-
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
-	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
-	ocmClient := ocmConnection.ClustersMgmt().V1()
-
-	// Create the AWS client:
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
-	}
-
-	awsCreator, err := awsClient.GetCreator()
-	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
-	}
-
 	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := clusterprovider.GetCluster(ocmClient.Clusters(), clusterKey, awsCreator.ARN)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
 	if err != nil {
-		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
 	}
 
 	// Validate flags:
 	expiration, err := validateExpiration()
 	if err != nil {
-		reporter.Errorf(fmt.Sprintf("%s", err))
-		os.Exit(1)
+		return err
 	}
 
 	if interactive.Enabled() {
@@ -216,8 +168,7 @@ func run(cmd *cobra.Command, argv []string) {
 			Default:  privateValue,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid private value: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid private value: %w", err)
 		}
 		private = &privateValue
 	}
@@ -238,12 +189,32 @@ func run(cmd *cobra.Command, argv []string) {
 			Default:  clusterAdminsValue,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid cluster-admins value: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid cluster-admins value: %w", err)
 		}
 		clusterAdmins = &clusterAdminsValue
 	}
 
+	var deleteProtection *bool
+	var deleteProtectionValue bool
+	if cmd.Flags().Changed("enable-delete-protection") {
+		deleteProtectionValue = args.deleteProtection
+		deleteProtection = &deleteProtectionValue
+	} else if isInteractive {
+		deleteProtectionValue = ocm.IsDeleteProtected(cluster)
+	}
+
+	if isInteractive {
+		deleteProtectionValue, err = interactive.GetBool(interactive.Input{
+			Question: "Enable delete protection",
+			Help:     cmd.Flags().Lookup("enable-delete-protection").Usage,
+			Default:  deleteProtectionValue,
+		})
+		if err != nil {
+			return rosaerrors.Usagef("Expected a valid delete protection value: %w", err)
+		}
+		deleteProtection = &deleteProtectionValue
+	}
+
 	clusterConfig := clusterprovider.Spec{
 		Expiration:    expiration,
 		Private:       private,
@@ -251,11 +222,19 @@ func run(cmd *cobra.Command, argv []string) {
 	}
 
 	reporter.Debugf("Updating cluster '%s'", clusterKey)
-	err = clusterprovider.UpdateCluster(ocmClient.Clusters(), clusterKey, awsCreator.ARN, clusterConfig)
+	err = clusterprovider.UpdateCluster(r.OCM(), clusterKey, r.Creator().ARN, clusterConfig)
 	if err != nil {
-		reporter.Errorf("Failed to update cluster: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to update cluster: %w", err)
 	}
+
+	if deleteProtection != nil {
+		err = r.OCM().SetDeleteProtection(cluster, *deleteProtection)
+		if err != nil {
+			return fmt.Errorf("Failed to update delete protection of cluster: %w", err)
+		}
+	}
+
+	return nil
 }
 
 func validateExpiration() (expiration time.Time, err error) {
@@ -19,10 +19,16 @@ package edit
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/moactl/cmd/edit/autoscaler"
 	"github.com/openshift/moactl/cmd/edit/cluster"
+	"github.com/openshift/moactl/cmd/edit/idp"
 	"github.com/openshift/moactl/cmd/edit/ingress"
+	"github.com/openshift/moactl/cmd/edit/kubeletconfig"
 	"github.com/openshift/moactl/cmd/edit/machinepool"
+	"github.com/openshift/moactl/cmd/edit/tuningconfig"
+	"github.com/openshift/moactl/pkg/dryrun"
 	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/ocm/roles"
 )
 
 var Cmd = &cobra.Command{
@@ -33,10 +39,17 @@ var Cmd = &cobra.Command{
 }
 
 func init() {
+	roles.Require(Cmd, roles.Update)
+
 	flags := Cmd.PersistentFlags()
 	interactive.AddFlag(flags)
+	dryrun.AddFlag(flags)
 
+	Cmd.AddCommand(autoscaler.Cmd)
 	Cmd.AddCommand(cluster.Cmd)
+	Cmd.AddCommand(idp.Cmd)
 	Cmd.AddCommand(ingress.Cmd)
+	Cmd.AddCommand(kubeletconfig.Cmd)
 	Cmd.AddCommand(machinepool.Cmd)
+	Cmd.AddCommand(tuningconfig.Cmd)
 }
@@ -0,0 +1,217 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package idp
+
+import (
+	"fmt"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/arguments"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/htpasswd"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var args struct {
+	addUsers       []string
+	removeUsers    []string
+	changePassword []string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "idp NAME",
+	Short: "Edit the users of an htpasswd IDP",
+	Long:  "Add, remove or change the password of users of an htpasswd identity provider.",
+	Example: `  # Add a user to the htpasswd identity provider named "htpasswd-1" of a cluster named "mycluster"
+  rosa edit idp htpasswd-1 --cluster=mycluster --add-user=bob:mypassword
+
+  # Remove a user and change the password of another one
+  rosa edit idp htpasswd-1 --cluster=mycluster --remove-user=bob --change-password=alice:newpassword`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	clusterprovider.UseKey(Cmd)
+
+	flags.StringArrayVar(
+		&args.addUsers,
+		"add-user",
+		nil,
+		"User to add, in the format 'username:password'. Can be repeated. Use 'username:-' to read "+
+			"the password from the standard input.",
+	)
+	flags.StringSliceVar(
+		&args.removeUsers,
+		"remove-user",
+		nil,
+		"Comma-separated list of usernames to remove.",
+	)
+	flags.StringArrayVar(
+		&args.changePassword,
+		"change-password",
+		nil,
+		"New password of a user, in the format 'username:password'. Can be repeated. Use 'username:-' "+
+			"to read the password from the standard input.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	// Check command line arguments:
+	if len(argv) != 1 {
+		return fmt.Errorf(
+			"Expected exactly one command line parameter containing the name " +
+				"of the identity provider",
+		)
+	}
+	idpName := argv[0]
+
+	if len(args.addUsers) == 0 && len(args.removeUsers) == 0 && len(args.changePassword) == 0 {
+		return rosaerrors.Usagef("At least one of '--add-user', '--remove-user' or '--change-password' is required")
+	}
+	addUsers, err := parseUsers(args.addUsers)
+	if err != nil {
+		return rosaerrors.Usagef("Invalid value for '--add-user': %w", err)
+	}
+	changePassword, err := parseUsers(args.changePassword)
+	if err != nil {
+		return rosaerrors.Usagef("Invalid value for '--change-password': %w", err)
+	}
+	for _, user := range addUsers {
+		if !ocm.IsValidUsername(user.Username) {
+			return rosaerrors.Usagef("Username '%s' isn't valid", user.Username)
+		}
+	}
+
+	clusterKey, err := clusterprovider.GetKey(r, nil)
+	if err != nil {
+		return err
+	}
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	// Try to find the identity provider:
+	reporter.Debugf("Loading identity provider '%s'", idpName)
+	idps, err := r.OCM().GetIdentityProviders(cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get identity providers for cluster '%s': %w", clusterKey, err)
+	}
+	var idp *cmv1.IdentityProvider
+	for _, item := range idps {
+		if item.Name() == idpName {
+			idp = item
+		}
+	}
+	if idp == nil {
+		return rosaerrors.NotFoundf("Failed to get identity provider '%s' for cluster '%s'", idpName, clusterKey)
+	}
+	if ocm.IdentityProviderType(idp) != "htpasswd" {
+		return rosaerrors.Usagef("Identity provider '%s' isn't an htpasswd identity provider", idpName)
+	}
+
+	reporter.Debugf("Loading users of identity provider '%s'", idpName)
+	users, err := r.OCM().GetHTPasswdUsers(cluster.ID(), idp.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get users of identity provider '%s': %w", idpName, err)
+	}
+	userIDs := make(map[string]string)
+	for _, user := range users {
+		userIDs[user.Username] = user.ID
+	}
+
+	// Check all the users before changing anything:
+	for _, user := range addUsers {
+		if _, ok := userIDs[user.Username]; ok {
+			return rosaerrors.Conflictf("User '%s' already exists in identity provider '%s'", user.Username, idpName)
+		}
+	}
+	for _, username := range args.removeUsers {
+		if _, ok := userIDs[username]; !ok {
+			return rosaerrors.NotFoundf("User '%s' doesn't exist in identity provider '%s'", username, idpName)
+		}
+	}
+	for _, user := range changePassword {
+		if _, ok := userIDs[user.Username]; !ok {
+			return rosaerrors.NotFoundf("User '%s' doesn't exist in identity provider '%s'", user.Username, idpName)
+		}
+	}
+	if len(args.removeUsers) >= len(users)+len(addUsers) {
+		return rosaerrors.Conflictf("Identity provider '%s' must keep at least one user. "+
+			"To remove it run 'rosa delete idp %s'", idpName, idpName)
+	}
+
+	for _, user := range addUsers {
+		reporter.Debugf("Adding user '%s' to identity provider '%s'", user.Username, idpName)
+		err = r.OCM().AddHTPasswdUser(cluster.ID(), idp.ID(), user)
+		if err != nil {
+			return fmt.Errorf("Failed to add user '%s': %w", user.Username, err)
+		}
+		reporter.Infof("Added user '%s'", user.Username)
+	}
+	for _, user := range changePassword {
+		reporter.Debugf("Changing password of user '%s' of identity provider '%s'", user.Username, idpName)
+		err = r.OCM().UpdateHTPasswdPassword(cluster.ID(), idp.ID(), userIDs[user.Username], user.Password)
+		if err != nil {
+			return fmt.Errorf("Failed to change password of user '%s': %w", user.Username, err)
+		}
+		reporter.Infof("Changed password of user '%s'", user.Username)
+	}
+	for _, username := range args.removeUsers {
+		reporter.Debugf("Removing user '%s' from identity provider '%s'", username, idpName)
+		err = r.OCM().DeleteHTPasswdUser(cluster.ID(), idp.ID(), userIDs[username])
+		if err != nil {
+			return fmt.Errorf("Failed to remove user '%s': %w", username, err)
+		}
+		reporter.Infof("Removed user '%s'", username)
+	}
+
+	return nil
+}
+
+// parseUsers parses a list of values in the format 'username:password'.
+func parseUsers(values []string) ([]*htpasswd.User, error) {
+	users := []*htpasswd.User{}
+	for _, value := range values {
+		parts := strings.SplitN(value, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("expected 'username:password' but got '%s'", parts[0])
+		}
+		password, err := arguments.ReadSecret(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read password of user '%s': %v", parts[0], err)
+		}
+		users = append(users, &htpasswd.User{
+			Username: parts[0],
+			Password: password,
+		})
+	}
+	return users, nil
+}
@@ -18,19 +18,16 @@ package ingress
 
 import (
 	"fmt"
-	"os"
 	"regexp"
 	"strings"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/moactl/pkg/aws"
 	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/interactive"
-	"github.com/openshift/moactl/pkg/logging"
-	"github.com/openshift/moactl/pkg/ocm"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
 // Regular expression to used to make sure that the identifier given by the
@@ -38,7 +35,6 @@ import (
 var ingressKeyRE = regexp.MustCompile(`^[a-z0-9]{3,5}$`)
 
 var args struct {
-	clusterKey string
 	private    bool
 	labelMatch string
 }
@@ -56,20 +52,13 @@ var Cmd = &cobra.Command{
 
   # Update the default ingress using the sub-domain identifier
   rosa edit ingress --private=false --cluster=mycluster apps`,
-	Run: run,
+	RunE: run,
 }
 
 func init() {
 	flags := Cmd.Flags()
 
-	flags.StringVarP(
-		&args.clusterKey,
-		"cluster",
-		"c",
-		"",
-		"Name or ID of the cluster to add the ingress to (required).",
-	)
-	Cmd.MarkFlagRequired("cluster")
+	clusterprovider.UseKey(Cmd)
 
 	flags.BoolVar(
 		&args.private,
@@ -87,42 +76,32 @@ func init() {
 	)
 }
 
-func run(cmd *cobra.Command, argv []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
 
 	// Check command line arguments:
 	if len(argv) != 1 {
-		reporter.Errorf(
+		return rosaerrors.Usagef(
 			"Expected exactly one command line parameter containing the id of the ingress",
 		)
-		os.Exit(1)
 	}
 
 	ingressID := argv[0]
 	if !ingressKeyRE.MatchString(ingressID) {
-		reporter.Errorf(
+		return rosaerrors.Usagef(
 			"Ingress  identifier '%s' isn't valid: it must contain only letters or digits",
 			ingressID,
 		)
-		os.Exit(1)
 	}
 
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
-	clusterKey := args.clusterKey
-	if !ocm.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
+	clusterKey, err := clusterprovider.GetKey(r, nil)
+	if err != nil {
+		return err
 	}
 
 	labelMatch := args.labelMatch
 	routeSelectors := make(map[string]string)
-	var err error
 	if interactive.Enabled() {
 		labelMatch, err = interactive.GetString(interactive.Input{
 			Question: "Label match for ingress",
@@ -130,15 +109,13 @@ func run(cmd *cobra.Command, argv []string) {
 			Default:  labelMatch,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid comma-separated list of attributes: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid comma-separated list of attributes: %w", err)
 		}
 	}
 	if labelMatch != "" {
 		routeSelectors, err = getRouteSelector(labelMatch)
 		if err != nil {
-			reporter.Errorf("%s", err)
-			os.Exit(1)
+			return err
 		}
 	}
 
@@ -152,51 +129,16 @@ func run(cmd *cobra.Command, argv []string) {
 			Default:  args.private,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid private value: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid private value: %w", err)
 		}
 		private = &privArg
 	}
 
-	// Create the AWS client:
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
-	}
-
-	awsCreator, err := awsClient.GetCreator()
-	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
-	}
-
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
-	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
-
-	// Get the client for the OCM collection of clusters:
-	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
-
 	// Try to find the cluster:
 	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
 	if err != nil {
-		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
 	}
 
 	// Edit API endpoint instead of ingresses
@@ -205,21 +147,19 @@ func run(cmd *cobra.Command, argv []string) {
 			Private: private,
 		}
 
-		err = clusterprovider.UpdateCluster(clustersCollection, clusterKey, awsCreator.ARN, clusterConfig)
+		err = clusterprovider.UpdateCluster(r.OCM(), clusterKey, r.Creator().ARN, clusterConfig)
 		if err != nil {
-			reporter.Errorf("Failed to update cluster API on cluster '%s': %v", clusterKey, err)
-			os.Exit(1)
+			return fmt.Errorf("Failed to update cluster API on cluster '%s': %w", clusterKey, err)
 		}
 
-		os.Exit(0)
+		return nil
 	}
 
 	// Try to find the ingress:
 	reporter.Debugf("Loading ingresses for cluster '%s'", clusterKey)
-	ingresses, err := ocm.GetIngresses(clustersCollection, cluster.ID())
+	ingresses, err := r.OCM().GetIngresses(cluster.ID())
 	if err != nil {
-		reporter.Errorf("Failed to get ingresses for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get ingresses for cluster '%s': %w", clusterKey, err)
 	}
 
 	var ingress *cmv1.Ingress
@@ -235,8 +175,7 @@ func run(cmd *cobra.Command, argv []string) {
 		}
 	}
 	if ingress == nil {
-		reporter.Errorf("Failed to get ingress '%s' for cluster '%s'", ingressID, clusterKey)
-		os.Exit(1)
+		return rosaerrors.NotFoundf("Failed to get ingress '%s' for cluster '%s'", ingressID, clusterKey)
 	}
 
 	ingressBuilder := cmv1.NewIngress().ID(ingress.ID())
@@ -257,24 +196,17 @@ func run(cmd *cobra.Command, argv []string) {
 
 	ingress, err = ingressBuilder.Build()
 	if err != nil {
-		reporter.Errorf("Failed to create ingress for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to create ingress for cluster '%s': %w", clusterKey, err)
 	}
 
 	reporter.Debugf("Updating ingress '%s' on cluster '%s'", ingress.ID(), clusterKey)
-	res, err := clustersCollection.
-		Cluster(cluster.ID()).
-		Ingresses().
-		Ingress(ingress.ID()).
-		Update().
-		Body(ingress).
-		Send()
+	err = r.OCM().UpdateIngress(cluster.ID(), ingress.ID(), ingress)
 	if err != nil {
-		reporter.Debugf(err.Error())
-		reporter.Errorf("Failed to update ingress '%s' on cluster '%s': %s",
-			ingress.ID(), clusterKey, res.Error().Reason())
-		os.Exit(1)
+		return fmt.Errorf("Failed to update ingress '%s' on cluster '%s': %w",
+			ingress.ID(), clusterKey, err)
 	}
+
+	return nil
 }
 
 func getRouteSelector(labelMatches string) (map[string]string, error) {
@@ -0,0 +1,123 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeletconfig
+
+import (
+	"fmt"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/ocm/kubeletconfigs"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var args struct {
+	podPidsLimit int
+}
+
+var Cmd = &cobra.Command{
+	Use:     "kubeletconfig NAME",
+	Aliases: []string{"kubelet-config"},
+	Short:   "Edit kubelet configuration",
+	Long: "Edit a kubelet configuration of a cluster. The nodes of the machine pools that use it " +
+		"are updated with the new configuration.",
+	Example: `  # Allow 16384 processes per pod in kubelet configuration 'high-pids'
+  rosa edit kubeletconfig --cluster=mycluster --pod-pids-limit=16384 high-pids`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	clusterprovider.UseKey(Cmd)
+
+	flags.IntVar(
+		&args.podPidsLimit,
+		"pod-pids-limit",
+		0,
+		"Maximum number of processes that can run in each pod.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	// Check command line arguments:
+	if len(argv) != 1 {
+		return rosaerrors.Usagef(
+			"Expected exactly one command line parameter containing the name of the kubelet configuration",
+		)
+	}
+	name := argv[0]
+
+	clusterKey, err := clusterprovider.GetKey(r, nil)
+	if err != nil {
+		return err
+	}
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	if cluster.State() != cmv1.ClusterStateReady {
+		return rosaerrors.Conflictf("Cluster '%s' is not yet ready", clusterKey)
+	}
+
+	reporter.Debugf("Loading kubelet configuration '%s'", name)
+	config, err := r.OCM().GetKubeletConfig(cluster.ID(), name)
+	if err != nil {
+		return fmt.Errorf("Failed to get kubelet configurations for cluster '%s': %w", clusterKey, err)
+	}
+	if config == nil {
+		return rosaerrors.NotFoundf("Failed to get kubelet configuration '%s' for cluster '%s'", name, clusterKey)
+	}
+
+	podPidsLimit := args.podPidsLimit
+	if interactive.Enabled() || !cmd.Flags().Changed("pod-pids-limit") {
+		podPidsLimit, err = interactive.GetInt(interactive.Input{
+			Question: "Pod PIDs limit",
+			Help:     cmd.Flags().Lookup("pod-pids-limit").Usage,
+			Default:  config.PodPidsLimit,
+			Required: true,
+		})
+		if err != nil {
+			return rosaerrors.Usagef("Expected a valid pod PIDs limit: %w", err)
+		}
+	}
+	err = kubeletconfigs.ValidatePodPidsLimit(podPidsLimit)
+	if err != nil {
+		return err
+	}
+
+	reporter.Debugf("Updating kubelet configuration '%s' on cluster '%s'", name, clusterKey)
+	err = r.OCM().UpdateKubeletConfig(cluster.ID(), config.ID, podPidsLimit)
+	if err != nil {
+		return fmt.Errorf("Failed to update kubelet configuration '%s' on cluster '%s': %w",
+			name, clusterKey, err)
+	}
+	reporter.Infof("Kubelet configuration '%s' on cluster '%s' has been updated", name, clusterKey)
+
+	return nil
+}
@@ -17,18 +17,20 @@ limitations under the License.
 package machinepool
 
 import (
-	"os"
+	"fmt"
 	"regexp"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/arguments"
 	c "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/defaults"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/interactive"
-	"github.com/openshift/moactl/pkg/logging"
-	"github.com/openshift/moactl/pkg/ocm"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/ocm/machinepools"
+	"github.com/openshift/moactl/pkg/ocm/machines"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
 // Regular expression to used to make sure that the identifier given by the
@@ -36,8 +38,18 @@ import (
 var machinePoolKeyRE = regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
 
 var args struct {
-	clusterKey string
-	replicas   int
+	replicas          int
+	enableAutoscaling bool
+	minReplicas       int
+	maxReplicas       int
+	instanceType      string
+	maxSurge          string
+	maxUnavailable    string
+	kubeletConfigs    []string
+	tuningConfigs     []string
+	deleteProtection  bool
+	labels            string
+	taints            string
 }
 
 var Cmd = &cobra.Command{
@@ -46,135 +58,216 @@ var Cmd = &cobra.Command{
 	Short:   "Edit machine pool",
 	Long:    "Edit the additional machine pool from a cluster.",
 	Example: `  # Set 4 replicas on machine pool 'mp1' on cluster 'mycluster'
-  rosa edit machinepool --replicas=4 --cluster=mycluster mp1`,
-	Run: run,
+  rosa edit machinepool --replicas=4 --cluster=mycluster mp1
+
+  # Enable autoscaling between 3 and 6 replicas on machine pool 'mp1' on cluster 'mycluster'
+  rosa edit machinepool --enable-autoscaling --min-replicas=3 --max-replicas=6 --cluster=mycluster mp1
+
+  # Replace the nodes of machine pool 'mp1' with 'm6i.2xlarge' instances, two at a time
+  rosa edit machinepool --instance-type=m6i.2xlarge --max-surge=2 --cluster=mycluster mp1
+
+  # Attach the tuning configuration 'sysctl' to machine pool 'mp1'
+  rosa edit machinepool --tuning-configs=sysctl --cluster=mycluster mp1
+
+  # Detach all the kubelet configurations from machine pool 'mp1'
+  rosa edit machinepool --kubelet-configs="" --cluster=mycluster mp1
+
+  # Replace the labels and taints of the nodes of machine pool 'mp1'
+  rosa edit machinepool --labels=team=db --taints=dedicated=db:NoSchedule --cluster=mycluster mp1
+
+  # Remove all the taints of machine pool 'mp1'
+  rosa edit machinepool --taints="" --cluster=mycluster mp1
+
+  # Protect machine pool 'mp1' against accidental deletion
+  rosa edit machinepool --enable-delete-protection --cluster=mycluster mp1`,
+	PreRunE: arguments.CheckFlagGroups,
+	RunE:    run,
 }
 
 func init() {
 	flags := Cmd.Flags()
 
-	flags.StringVarP(
-		&args.clusterKey,
-		"cluster",
-		"c",
-		"",
-		"Name or ID of the cluster to add the machine pool to (required).",
-	)
-	Cmd.MarkFlagRequired("cluster")
+	c.UseKey(Cmd)
 
 	flags.IntVar(
 		&args.replicas,
 		"replicas",
 		0,
-		"Count of machines for this machine pool (required).",
+		"Count of machines for this machine pool (required unless autoscaling is enabled).",
+	)
+
+	flags.BoolVar(
+		&args.enableAutoscaling,
+		"enable-autoscaling",
+		false,
+		"Enable autoscaling for the machine pool, so that the number of machines is kept between "+
+			"'--min-replicas' and '--max-replicas'. Use '--enable-autoscaling=false' together with "+
+			"'--replicas' to go back to a fixed number of machines.",
+	)
+
+	flags.IntVar(
+		&args.minReplicas,
+		"min-replicas",
+		0,
+		"Minimum number of machines for the machine pool when autoscaling is enabled.",
+	)
+
+	flags.IntVar(
+		&args.maxReplicas,
+		"max-replicas",
+		0,
+		"Maximum number of machines for the machine pool when autoscaling is enabled.",
+	)
+
+	flags.StringVar(
+		&args.instanceType,
+		"instance-type",
+		"",
+		"Instance type that the nodes of the machine pool will be replaced with. Only supported for "+
+			"machine pools that can change their instance type in place.",
+	)
+
+	flags.StringVar(
+		&args.maxSurge,
+		"max-surge",
+		machinepools.DefaultMaxSurge,
+		"Number or percentage of nodes that can be created above the number of replicas while the "+
+			"instance type is changed.",
+	)
+
+	flags.StringVar(
+		&args.maxUnavailable,
+		"max-unavailable",
+		machinepools.DefaultMaxUnavailable,
+		"Number or percentage of nodes that can be unavailable while the instance type is changed.",
+	)
+
+	flags.StringSliceVar(
+		&args.kubeletConfigs,
+		"kubelet-configs",
+		nil,
+		"Comma-separated list of the names of the kubelet configurations used by the machine pool. "+
+			"Replaces the current list, an empty list detaches all of them.",
+	)
+
+	flags.StringSliceVar(
+		&args.tuningConfigs,
+		"tuning-configs",
+		nil,
+		"Comma-separated list of the names of the tuning configurations used by the machine pool. "+
+			"Replaces the current list, an empty list detaches all of them.",
+	)
+
+	flags.BoolVar(
+		&args.deleteProtection,
+		"enable-delete-protection",
+		false,
+		"Protect the machine pool against accidental deletion. Protected machine pools can only be "+
+			"deleted with 'rosa delete machinepool --force'. Use '--enable-delete-protection=false' "+
+			"to remove the protection.",
+	)
+
+	flags.StringVar(
+		&args.labels,
+		"labels",
+		"",
+		"Labels for the nodes of the machine pool, as a comma-separated list of 'key=value'. Replaces "+
+			"the current labels, an empty list removes all of them.",
+	)
+
+	flags.StringVar(
+		&args.taints,
+		"taints",
+		"",
+		"Taints for the nodes of the machine pool, as a comma-separated list of 'key=value:Effect', "+
+			"where the effect is 'NoSchedule', 'PreferNoSchedule' or 'NoExecute'. Replaces the current "+
+			"taints, an empty list removes all of them.",
 	)
+	arguments.MarkFlagsMutuallyExclusive(flags, "replicas", "min-replicas")
+	arguments.MarkFlagsMutuallyExclusive(flags, "replicas", "max-replicas")
+	arguments.MarkFlagRequires(flags, "max-surge", "instance-type")
+	arguments.MarkFlagRequires(flags, "max-unavailable", "instance-type")
 }
 
-func run(cmd *cobra.Command, argv []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
 
 	// Check command line arguments:
 	if len(argv) != 1 {
-		reporter.Errorf(
+		return rosaerrors.Usagef(
 			"Expected exactly one command line parameter containing the id of the machine pool",
 		)
-		os.Exit(1)
 	}
 
 	machinePoolID := argv[0]
 	if !machinePoolKeyRE.MatchString(machinePoolID) {
-		reporter.Errorf("Expected a valid identifier for the machine pool")
-		os.Exit(1)
-	}
-
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
-	clusterKey := args.clusterKey
-	if !c.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
-	}
-
-	// Create the AWS client:
-	var err error
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		return rosaerrors.Usagef("Expected a valid identifier for the machine pool")
 	}
 
-	awsCreator, err := awsClient.GetCreator()
+	clusterKey, err := c.GetKey(r, nil)
 	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		return err
 	}
 
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
-	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
-
-	// Get the client for the OCM collection of clusters:
-	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
-
 	// Try to find the cluster:
 	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
 	if err != nil {
-		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
 	}
 
 	var replicas int
 
 	// Editing the default machine pool is a different process
 	if machinePoolID == "default" {
+		if cmd.Flags().Changed("instance-type") {
+			return rosaerrors.Usagef("The instance type of the default machine pool can't be changed")
+		}
+		if cmd.Flags().Changed("kubelet-configs") || cmd.Flags().Changed("tuning-configs") {
+			return rosaerrors.Usagef("Kubelet and tuning configurations can't be attached to the default machine pool")
+		}
+		if autoscalingFlagsChanged(cmd) {
+			return rosaerrors.Usagef("Autoscaling of the default machine pool can't be changed")
+		}
+		if cmd.Flags().Changed("labels") || cmd.Flags().Changed("taints") {
+			return rosaerrors.Usagef("Labels and taints of the default machine pool can't be changed")
+		}
+		if cmd.Flags().Changed("enable-delete-protection") {
+			return rosaerrors.Usagef("The default machine pool can't be deleted, use 'rosa edit cluster " +
+				"--enable-delete-protection' to protect the cluster instead")
+		}
 		replicas, err = getReplicas(cmd)
 		if err != nil {
-			reporter.Errorf("Expected a valid number of replicas: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid number of replicas: %w", err)
+		}
+		nodeDefaults, err := r.OCM().LoadDefaults()
+		if err != nil {
+			reporter.Debugf("Failed to load compute node defaults of the organization, using built-in values: %v", err)
+			nodeDefaults = defaults.Builtin()
 		}
-		if replicas < 2 {
-			reporter.Errorf("Default machine pool requires at least 2 compute nodes")
-			os.Exit(1)
+		minNodes := nodeDefaults.ComputeNodes(cluster.MultiAZ()).Min
+		if replicas < minNodes {
+			return rosaerrors.Usagef("Default machine pool requires at least %d compute nodes", minNodes)
 		}
 
 		clusterConfig := c.Spec{ComputeNodes: replicas}
 
 		reporter.Debugf("Updating machine pool '%s' on cluster '%s'", machinePoolID, clusterKey)
-		err = c.UpdateCluster(clustersCollection, clusterKey, awsCreator.ARN, clusterConfig)
+		err = c.UpdateCluster(r.OCM(), clusterKey, r.Creator().ARN, clusterConfig)
 		if err != nil {
-			reporter.Errorf("Failed to update machine pool '%s' on cluster '%s': %s",
+			return fmt.Errorf("Failed to update machine pool '%s' on cluster '%s': %w",
 				machinePoolID, clusterKey, err)
-			os.Exit(1)
 		}
 
-		os.Exit(0)
+		return nil
 	}
 
 	// Try to find the machine pool:
 	reporter.Debugf("Loading machine pools for cluster '%s'", clusterKey)
-	machinePools, err := ocm.GetMachinePools(clustersCollection, cluster.ID())
+	machinePools, err := r.OCM().GetMachinePools(cluster.ID())
 	if err != nil {
-		reporter.Errorf("Failed to get machine pools for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get machine pools for cluster '%s': %w", clusterKey, err)
 	}
 
 	var machinePool *cmv1.MachinePool
@@ -184,39 +277,289 @@ func run(cmd *cobra.Command, argv []string) {
 		}
 	}
 	if machinePool == nil {
-		reporter.Errorf("Failed to get machine pool '%s' for cluster '%s'", machinePoolID, clusterKey)
-		os.Exit(1)
+		return rosaerrors.NotFoundf("Failed to get machine pool '%s' for cluster '%s'", machinePoolID, clusterKey)
 	}
 
-	replicas, err = getReplicas(cmd)
-	if err != nil {
-		reporter.Errorf("Expected a valid number of replicas: %s", err)
-		os.Exit(1)
+	// When only the instance type is changed the number of replicas is kept:
+	instanceType := args.instanceType
+	if instanceType != "" {
+		instanceTypeList, err := r.OCM().GetMachineTypeList()
+		if err != nil {
+			return err
+		}
+		instanceType, err = machines.ValidateMachineType(instanceType, instanceTypeList)
+		if err != nil {
+			return err
+		}
+		if instanceType == machinePool.InstanceType() {
+			return rosaerrors.Conflictf("Machine pool '%s' already uses instance type '%s'", machinePoolID, instanceType)
+		}
+	}
+
+	// Nil lists leave the attached configurations unchanged:
+	var kubeletConfigs, tuningConfigs []string
+	if cmd.Flags().Changed("kubelet-configs") {
+		kubeletConfigs, err = validateKubeletConfigs(r, cluster, args.kubeletConfigs)
+		if err != nil {
+			return err
+		}
+	}
+	if cmd.Flags().Changed("tuning-configs") {
+		tuningConfigs, err = validateTuningConfigs(r, cluster, args.tuningConfigs)
+		if err != nil {
+			return err
+		}
+	}
+	updateConfigs := kubeletConfigs != nil || tuningConfigs != nil
+	updateProtection := cmd.Flags().Changed("enable-delete-protection")
+
+	// Nil labels and taints are left unchanged:
+	var labels map[string]string
+	var taints []*cmv1.TaintBuilder
+	if cmd.Flags().Changed("labels") {
+		labels, err = machinepools.ParseLabels(args.labels)
+		if err != nil {
+			return err
+		}
+	}
+	if cmd.Flags().Changed("taints") {
+		taints, err = machinepools.ParseTaints(args.taints)
+		if err != nil {
+			return err
+		}
+	}
+	updateNodes := labels != nil || taints != nil
+
+	// The machine pool keeps autoscaling, or a fixed number of replicas, unless explicitly changed:
+	autoscaling := machinePool.Autoscaling() != nil
+	if cmd.Flags().Changed("enable-autoscaling") {
+		autoscaling = args.enableAutoscaling
+	}
+	if autoscaling && cmd.Flags().Changed("replicas") {
+		return rosaerrors.Usagef("Option '--replicas' can't be used when autoscaling is enabled, use " +
+			"'--min-replicas' and '--max-replicas' instead")
+	}
+	if !autoscaling && (cmd.Flags().Changed("min-replicas") || cmd.Flags().Changed("max-replicas")) {
+		return rosaerrors.Usagef("Options '--min-replicas' and '--max-replicas' can only be used when " +
+			"autoscaling is enabled, use '--enable-autoscaling' to enable it")
+	}
+
+	updateReplicas := (instanceType == "" && !updateConfigs && !updateProtection && !updateNodes &&
+		!autoscalingFlagsChanged(cmd)) ||
+		interactive.Enabled() || cmd.Flags().Changed("replicas") || autoscalingFlagsChanged(cmd)
+
+	replicas = machinePool.Replicas()
+	var minReplicas, maxReplicas int
+	if updateReplicas {
+		if autoscaling {
+			minReplicas, maxReplicas, err = getAutoscaling(cmd, machinePool.Autoscaling())
+			if err != nil {
+				return rosaerrors.Usagef("Expected a valid number of replicas: %w", err)
+			}
+			multiAZ := cluster.MultiAZ() && len(machinePool.AvailabilityZones()) != 1
+			err = machinepools.ValidateAutoscaling(minReplicas, maxReplicas, multiAZ)
+			if err != nil {
+				return err
+			}
+		} else {
+			replicas, err = getReplicas(cmd)
+			if err != nil {
+				return rosaerrors.Usagef("Expected a valid number of replicas: %w", err)
+			}
+		}
+	}
+	// The rollout of autoscaled machine pools is planned for their largest size:
+	if autoscaling {
+		if updateReplicas {
+			replicas = maxReplicas
+		} else {
+			replicas = machinePool.Autoscaling().MaxReplicas()
+		}
+	}
+
+	// Show the rollout plan before anything is changed, so that the user can cancel if it would
+	// disrupt the workloads too much:
+	if instanceType != "" {
+		plan, err := machinepools.PlanRollout(replicas, args.maxSurge, args.maxUnavailable)
+		if err != nil {
+			return err
+		}
+		reporter.Infof("Changing the instance type of machine pool '%s' from '%s' to '%s' will "+
+			"replace its %d nodes:", machinePoolID, machinePool.InstanceType(), instanceType, replicas)
+		printPlan(plan)
+		if !c.ConfirmDestructive(r, cluster, "change the instance type of machine pool '%s' on cluster '%s'",
+			machinePoolID, clusterKey) {
+			return nil
+		}
+	}
+
+	if updateReplicas || updateNodes {
+		machinePoolBuilder := cmv1.NewMachinePool().
+			ID(machinePool.ID())
+		if updateReplicas && autoscaling {
+			machinePoolBuilder = machinePoolBuilder.Autoscaling(machinepools.NewAutoscaling(minReplicas, maxReplicas))
+		} else if updateReplicas {
+			machinePoolBuilder = machinePoolBuilder.Replicas(replicas)
+		}
+		if labels != nil {
+			machinePoolBuilder = machinePoolBuilder.Labels(labels)
+		}
+		if taints != nil {
+			machinePoolBuilder = machinePoolBuilder.Taints(taints...)
+		}
+		machinePool, err = machinePoolBuilder.Build()
+		if err != nil {
+			return fmt.Errorf("Failed to create machine pool for cluster '%s': %w", clusterKey, err)
+		}
+
+		reporter.Debugf("Updating machine pool '%s' on cluster '%s'", machinePool.ID(), clusterKey)
+		err = r.OCM().UpdateMachinePool(cluster.ID(), machinePool.ID(), machinePool)
+		if err != nil {
+			return fmt.Errorf("Failed to update machine pool '%s' on cluster '%s': %w",
+				machinePool.ID(), clusterKey, err)
+		}
+	}
+
+	if updateConfigs {
+		reporter.Debugf("Updating configurations of machine pool '%s' on cluster '%s'", machinePoolID, clusterKey)
+		err = r.OCM().UpdateNodeConfigs(cluster.ID(), machinePoolID, kubeletConfigs, tuningConfigs)
+		if err != nil {
+			return fmt.Errorf("Failed to update configurations of machine pool '%s' on cluster '%s': %w",
+				machinePoolID, clusterKey, err)
+		}
+		reporter.Infof("Configurations of machine pool '%s' on cluster '%s' have been updated",
+			machinePoolID, clusterKey)
+	}
+
+	if instanceType != "" {
+		reporter.Debugf("Changing instance type of machine pool '%s' on cluster '%s' to '%s'",
+			machinePoolID, clusterKey, instanceType)
+		err = r.OCM().UpdateInstanceType(cluster.ID(), machinePoolID,
+			instanceType, args.maxSurge, args.maxUnavailable)
+		if err != nil {
+			return fmt.Errorf("Failed to change instance type of machine pool '%s' on cluster '%s': %w",
+				machinePoolID, clusterKey, err)
+		}
+		reporter.Infof("Instance type of machine pool '%s' on cluster '%s' is being changed to '%s'",
+			machinePoolID, clusterKey, instanceType)
+	}
+
+	if updateProtection {
+		reporter.Debugf("Updating delete protection of machine pool '%s' on cluster '%s'", machinePoolID, clusterKey)
+		err = r.OCM().SetMachinePoolDeleteProtection(cluster, machinePoolID, args.deleteProtection)
+		if err != nil {
+			return fmt.Errorf("Failed to update delete protection of machine pool '%s' on cluster '%s': %w",
+				machinePoolID, clusterKey, err)
+		}
 	}
 
-	machinePool, err = cmv1.NewMachinePool().
-		ID(machinePool.ID()).
-		Replicas(replicas).
-		Build()
+	return nil
+}
+
+// validateKubeletConfigs checks that the kubelet configurations with the given names exist, and
+// returns the names as a non-nil list.
+func validateKubeletConfigs(r *runtime.Runtime, cluster *cmv1.Cluster, names []string) ([]string, error) {
+	configs, err := r.OCM().GetKubeletConfigs(cluster.ID())
 	if err != nil {
-		reporter.Errorf("Failed to create machine pool for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
-	}
-
-	reporter.Debugf("Updating machine pool '%s' on cluster '%s'", machinePool.ID(), clusterKey)
-	res, err := clustersCollection.
-		Cluster(cluster.ID()).
-		MachinePools().
-		MachinePool(machinePool.ID()).
-		Update().
-		Body(machinePool).
-		Send()
+		return nil, fmt.Errorf("Failed to get kubelet configurations for cluster '%s': %w", cluster.Name(), err)
+	}
+	existing := map[string]bool{}
+	for _, config := range configs {
+		existing[config.Name] = true
+	}
+	for _, name := range names {
+		if !existing[name] {
+			return nil, rosaerrors.NotFoundf("Kubelet configuration '%s' doesn't exist in cluster '%s'. Create it "+
+				"with 'rosa create kubeletconfig'", name, cluster.Name())
+		}
+	}
+	return append([]string{}, names...), nil
+}
+
+// validateTuningConfigs checks that the tuning configurations with the given names exist, and
+// returns the names as a non-nil list.
+func validateTuningConfigs(r *runtime.Runtime, cluster *cmv1.Cluster, names []string) ([]string, error) {
+	configs, err := r.OCM().GetTuningConfigs(cluster.ID())
 	if err != nil {
-		reporter.Debugf(err.Error())
-		reporter.Errorf("Failed to update machine pool '%s' on cluster '%s': %s",
-			machinePool.ID(), clusterKey, res.Error().Reason())
-		os.Exit(1)
+		return nil, fmt.Errorf("Failed to get tuning configurations for cluster '%s': %w", cluster.Name(), err)
+	}
+	existing := map[string]bool{}
+	for _, config := range configs {
+		existing[config.Name] = true
+	}
+	for _, name := range names {
+		if !existing[name] {
+			return nil, rosaerrors.NotFoundf("Tuning configuration '%s' doesn't exist in cluster '%s'. Create it "+
+				"with 'rosa create tuning-config'", name, cluster.Name())
+		}
+	}
+	return append([]string{}, names...), nil
+}
+
+func printPlan(plan *machinepools.RolloutPlan) {
+	fmt.Printf(""+
+		"  Max surge:          %s\n"+
+		"  Max unavailable:    %s\n"+
+		"  Batches:            %d\n"+
+		"  Nodes at peak:      %d\n"+
+		"  Minimum available:  %d\n",
+		nodes(plan.MaxSurge),
+		nodes(plan.MaxUnavailable),
+		plan.Batches,
+		plan.MaxNodes,
+		plan.MinAvailable,
+	)
+}
+
+func nodes(count int) string {
+	if count == 1 {
+		return "1 node"
+	}
+	return fmt.Sprintf("%d nodes", count)
+}
+
+// autoscalingFlagsChanged returns true if any of the autoscaling options was used.
+func autoscalingFlagsChanged(cmd *cobra.Command) bool {
+	return cmd.Flags().Changed("enable-autoscaling") || cmd.Flags().Changed("min-replicas") ||
+		cmd.Flags().Changed("max-replicas")
+}
+
+// getAutoscaling returns the range of replicas given with the options, using the current settings
+// of the machine pool for the options that weren't used. The values that are still missing are
+// asked for.
+func getAutoscaling(cmd *cobra.Command, current *cmv1.MachinePoolAutoscaling) (int, int, error) {
+	minReplicas := current.MinReplicas()
+	if cmd.Flags().Changed("min-replicas") {
+		minReplicas = args.minReplicas
+	}
+	maxReplicas := current.MaxReplicas()
+	if cmd.Flags().Changed("max-replicas") {
+		maxReplicas = args.maxReplicas
+	}
+	var err error
+	if interactive.Enabled() || (current == nil && !cmd.Flags().Changed("min-replicas")) {
+		minReplicas, err = interactive.GetInt(interactive.Input{
+			Question: "Min replicas",
+			Help:     cmd.Flags().Lookup("min-replicas").Usage,
+			Default:  minReplicas,
+			Required: true,
+		})
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	if interactive.Enabled() || (current == nil && !cmd.Flags().Changed("max-replicas")) {
+		maxReplicas, err = interactive.GetInt(interactive.Input{
+			Question: "Max replicas",
+			Help:     cmd.Flags().Lookup("max-replicas").Usage,
+			Default:  maxReplicas,
+			Required: true,
+		})
+		if err != nil {
+			return 0, 0, err
+		}
 	}
+	return minReplicas, maxReplicas, nil
 }
 
 func getReplicas(cmd *cobra.Command) (int, error) {
@@ -0,0 +1,129 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tuningconfig
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/ocm/tuningconfigs"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var args struct {
+	specPath string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "tuning-config NAME",
+	Aliases: []string{"tuningconfig", "tuning-configs", "tuningconfigs"},
+	Short:   "Edit tuning configuration",
+	Long: "Replace the specification of a tuning configuration of a cluster. The nodes of the " +
+		"machine pools that use it are updated with the new specification.",
+	Example: `  # Replace the specification of tuning configuration 'sysctl'
+  rosa edit tuning-config --cluster=mycluster --spec-path=tuned.yaml sysctl`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	clusterprovider.UseKey(Cmd)
+
+	flags.StringVar(
+		&args.specPath,
+		"spec-path",
+		"",
+		"Path of a JSON or YAML file containing the new specification of the tuning configuration "+
+			"(required).",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	// Check command line arguments:
+	if len(argv) != 1 {
+		return rosaerrors.Usagef(
+			"Expected exactly one command line parameter containing the name of the tuning configuration",
+		)
+	}
+	name := argv[0]
+
+	clusterKey, err := clusterprovider.GetKey(r, nil)
+	if err != nil {
+		return err
+	}
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	if cluster.State() != cmv1.ClusterStateReady {
+		return rosaerrors.Conflictf("Cluster '%s' is not yet ready", clusterKey)
+	}
+
+	reporter.Debugf("Loading tuning configuration '%s'", name)
+	config, err := r.OCM().GetTuningConfig(cluster.ID(), name)
+	if err != nil {
+		return fmt.Errorf("Failed to get tuning configurations for cluster '%s': %w", clusterKey, err)
+	}
+	if config == nil {
+		return rosaerrors.NotFoundf("Failed to get tuning configuration '%s' for cluster '%s'", name, clusterKey)
+	}
+
+	specPath := args.specPath
+	if interactive.Enabled() || specPath == "" {
+		specPath, err = interactive.GetString(interactive.Input{
+			Question: "Specification file path",
+			Help:     cmd.Flags().Lookup("spec-path").Usage,
+			Default:  specPath,
+			Required: true,
+		})
+		if err != nil {
+			return rosaerrors.Usagef("Expected a valid file path: %w", err)
+		}
+	}
+	data, err := ioutil.ReadFile(specPath)
+	if err != nil {
+		return fmt.Errorf("Failed to read file '%s': %w", specPath, err)
+	}
+	spec, err := tuningconfigs.ParseSpec(data)
+	if err != nil {
+		return err
+	}
+
+	reporter.Debugf("Updating tuning configuration '%s' on cluster '%s'", name, clusterKey)
+	err = r.OCM().UpdateTuningConfig(cluster.ID(), config.ID, spec)
+	if err != nil {
+		return fmt.Errorf("Failed to update tuning configuration '%s' on cluster '%s': %w",
+			name, clusterKey, err)
+	}
+	reporter.Infof("Tuning configuration '%s' on cluster '%s' has been updated", name, clusterKey)
+
+	return nil
+}
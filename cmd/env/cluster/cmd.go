@@ -0,0 +1,202 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/arguments"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var args struct {
+	shell      string
+	kubeconfig string
+	login      bool
+	username   string
+	password   string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "cluster [ID|NAME]",
+	Short: "Generate the environment to access a cluster",
+	Long: "Generate the shell commands that set the environment variables to access a cluster: " +
+		"the path of a kubeconfig file for the cluster and the URLs of its API and console. " +
+		"Optionally login to the cluster with 'oc', saving the credentials in that kubeconfig file.",
+	Example: `  # Set the environment to access a cluster named "mycluster"
+  eval $(rosa env cluster mycluster)
+
+  # Login as the cluster admin and set the environment in the fish shell
+  rosa env cluster mycluster --login --shell fish --password-file ~/mycluster-admin | source`,
+	PreRunE: arguments.CheckFlagGroups,
+	RunE:    run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	clusterprovider.UseKey(Cmd)
+
+	flags.StringVar(
+		&args.shell,
+		"shell",
+		"sh",
+		"Syntax of the generated commands. The options are 'sh' (also valid for bash and zsh) and 'fish'.",
+	)
+	flags.StringVar(
+		&args.kubeconfig,
+		"kubeconfig",
+		"",
+		"Path of the kubeconfig file of the cluster. Defaults to '~/.kube/rosa/NAME/config'.",
+	)
+	flags.BoolVar(
+		&args.login,
+		"login",
+		false,
+		"Login to the cluster with 'oc', saving the credentials in the kubeconfig file of the cluster.",
+	)
+	flags.StringVar(
+		&args.username,
+		"username",
+		"cluster-admin",
+		"User used to login to the cluster.",
+	)
+	flags.StringVar(
+		&args.password,
+		"password",
+		"",
+		"Password of the user used to login to the cluster. If not specified it is requested interactively.",
+	)
+	arguments.AddSecretFileFlag(flags, "password")
+	arguments.MarkFlagRequires(flags, "username", "login")
+	arguments.MarkFlagRequires(flags, "password", "login")
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	err := arguments.ResolveSecrets(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	var format string
+	switch args.shell {
+	case "sh":
+		format = "export %s=%s\n"
+	case "fish":
+		format = "set -gx %s %s\n"
+	default:
+		return rosaerrors.Usagef("Expected a valid shell, the options are 'sh' and 'fish'")
+	}
+
+	clusterKey, err := clusterprovider.GetKey(r, argv)
+	if err != nil {
+		return err
+	}
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	if cluster.State() != cmv1.ClusterStateReady {
+		return rosaerrors.Conflictf("Cluster '%s' is not yet ready", clusterKey)
+	}
+
+	kubeconfig := args.kubeconfig
+	if kubeconfig == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("Failed to find home directory: %w", err)
+		}
+		kubeconfig = filepath.Join(home, ".kube", "rosa", cluster.Name(), "config")
+	}
+	kubeconfig, err = filepath.Abs(kubeconfig)
+	if err != nil {
+		return fmt.Errorf("Failed to get absolute path of '%s': %w", kubeconfig, err)
+	}
+
+	if args.login {
+		err = login(cluster, kubeconfig)
+		if err != nil {
+			return fmt.Errorf("Failed to login to cluster '%s': %w", clusterKey, err)
+		}
+	}
+
+	// The standard output is meant to be evaluated by the shell, so only the commands are written
+	// there:
+	fmt.Printf(format, "KUBECONFIG", quote(kubeconfig))
+	fmt.Printf(format, "ROSA_CLUSTER_ID", quote(cluster.ID()))
+	fmt.Printf(format, "ROSA_CLUSTER_NAME", quote(cluster.Name()))
+	fmt.Printf(format, "ROSA_API_URL", quote(cluster.API().URL()))
+	fmt.Printf(format, "ROSA_CONSOLE_URL", quote(cluster.Console().URL()))
+
+	return nil
+}
+
+// quote returns the given value in single quotes, so that the shell doesn't expand it. This syntax
+// works both in POSIX shells and in fish.
+func quote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// login runs 'oc login' for the given cluster, saving the credentials in the given kubeconfig file.
+// The output of 'oc' goes to the standard error, so that it doesn't mix with the generated commands.
+func login(cluster *cmv1.Cluster, kubeconfig string) error {
+	password := args.password
+	if password == "" {
+		if !interactive.IsTerminal() {
+			return fmt.Errorf("Option '--password' is mandatory when not running in a terminal")
+		}
+		var err error
+		password, err = interactive.GetPassword(interactive.Input{
+			Question: fmt.Sprintf("Password of user '%s'", args.username),
+			Required: true,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err := os.MkdirAll(filepath.Dir(kubeconfig), 0700)
+	if err != nil {
+		return err
+	}
+	login := exec.Command(
+		"oc", "login", cluster.API().URL(),
+		"--username", args.username,
+		"--password", password,
+		"--kubeconfig", kubeconfig,
+	)
+	login.Stdout = os.Stderr
+	login.Stderr = os.Stderr
+	return login.Run()
+}
@@ -0,0 +1,40 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleet
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/cmd/fleet/upgrade"
+	"github.com/openshift/moactl/pkg/fleet"
+	"github.com/openshift/moactl/pkg/ocm/roles"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "fleet COMMAND [flags]",
+	Short: "Run an operation on a set of clusters",
+	Long:  "Run an operation on all the clusters that match a search expression",
+}
+
+func init() {
+	roles.Require(Cmd, roles.Update)
+
+	flags := Cmd.PersistentFlags()
+	fleet.AddFlags(flags)
+
+	Cmd.AddCommand(upgrade.Cmd)
+}
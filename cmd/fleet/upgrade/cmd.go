@@ -0,0 +1,114 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"fmt"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/fleet"
+	"github.com/openshift/moactl/pkg/ocm/versions"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var args struct {
+	version string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrade a set of clusters",
+	Long: "Schedule an upgrade to the given version on all the clusters that match the search " +
+		"expression. Clusters that aren't ready, that already have a scheduled upgrade or that " +
+		"can't be upgraded to the version are skipped.",
+	Example: `  # Upgrade all the clusters in region us-east-1 to version 4.5.20
+  rosa fleet upgrade --search "region.id = 'us-east-1'" --version 4.5.20
+
+  # Upgrade two clusters at a time, saving the progress so that the command can be resumed
+  rosa fleet upgrade --search "name like 'prod-%'" --version 4.5.20 \
+    --concurrency 2 --state-file upgrade-state.json
+
+  # Write the results as metrics for the textfile collector of the node exporter
+  rosa fleet upgrade --search "region.id = 'us-east-1'" --version 4.5.20 \
+    --metrics-dir /var/lib/node_exporter/textfile_collector`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVar(
+		&args.version,
+		"version",
+		"",
+		"Version of OpenShift that the clusters will be upgraded to (required).",
+	)
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	if args.version == "" {
+		return rosaerrors.Usagef("Option '--version' is mandatory")
+	}
+
+	// Upgrades start within the next 10 minutes, as with 'rosa upgrade cluster':
+	nextRun := time.Now().UTC().Add(10 * time.Minute)
+
+	return fleet.Execute(r, cmd.Name(), func(cluster *cmv1.Cluster) (fleet.Status, string) {
+		if cluster.State() != cmv1.ClusterStateReady {
+			return fleet.StatusSkipped, fmt.Sprintf("Cluster is %s", cluster.State())
+		}
+
+		scheduledUpgrade, err := r.OCM().GetScheduledUpgrade(cluster.ID())
+		if err != nil {
+			return fleet.StatusFailed, fmt.Sprintf("Failed to get scheduled upgrades: %v", err)
+		}
+		if scheduledUpgrade != nil {
+			return fleet.StatusSkipped, fmt.Sprintf("Upgrade to version %s already scheduled",
+				scheduledUpgrade.Version())
+		}
+
+		availableUpgrades, err := r.OCM().GetAvailableUpgrades(versions.GetVersionID(cluster))
+		if err != nil {
+			return fleet.StatusFailed, fmt.Sprintf("Failed to find available upgrades: %v", err)
+		}
+		available := false
+		for _, version := range availableUpgrades {
+			if version == args.version {
+				available = true
+				break
+			}
+		}
+		if !available {
+			return fleet.StatusSkipped, fmt.Sprintf("Version %s isn't an available upgrade from %s",
+				args.version, cluster.OpenshiftVersion())
+		}
+
+		reporter.Debugf("Scheduling upgrade of cluster '%s' to version %s", cluster.Name(), args.version)
+		err = r.OCM().ScheduleUpgrade(cluster.ID(), args.version, nextRun)
+		if err != nil {
+			return fleet.StatusFailed, fmt.Sprintf("Failed to schedule upgrade: %v", err)
+		}
+		return fleet.StatusSucceeded, fmt.Sprintf("Upgrade scheduled for %s", nextRun.Format("2006-01-02 15:04 MST"))
+	})
+}
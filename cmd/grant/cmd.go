@@ -21,6 +21,7 @@ import (
 
 	"github.com/openshift/moactl/cmd/grant/user"
 	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/ocm/roles"
 )
 
 var Cmd = &cobra.Command{
@@ -30,6 +31,8 @@ var Cmd = &cobra.Command{
 }
 
 func init() {
+	roles.Require(Cmd, roles.Update)
+
 	flags := Cmd.PersistentFlags()
 	interactive.AddFlag(flags)
 
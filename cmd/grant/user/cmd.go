@@ -18,20 +18,20 @@ package user
 
 import (
 	"fmt"
-	"os"
+	"time"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/moactl/pkg/aws"
-	"github.com/openshift/moactl/pkg/logging"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/ocm"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
 var args struct {
-	clusterKey string
-	username   string
+	username string
+	duration time.Duration
 }
 
 var Cmd = &cobra.Command{
@@ -43,24 +43,17 @@ var Cmd = &cobra.Command{
   rosa grant user cluster-admin --user=myusername --cluster=mycluster
 
   # Grant dedicated-admins role to a user
-  rosa grant user dedicated-admin --user=myusername --cluster=mycluster`,
-	Run: run,
-}
+  rosa grant user dedicated-admin --user=myusername --cluster=mycluster
 
-var validRoles = []string{"cluster-admins", "dedicated-admins"}
-var validRolesAliases = []string{"cluster-admin", "dedicated-admin"}
+  # Grant dedicated-admins role to a user for 8 hours
+  rosa grant user dedicated-admin --user=myusername --cluster=mycluster --duration=8h`,
+	RunE: run,
+}
 
 func init() {
 	flags := Cmd.Flags()
 
-	flags.StringVarP(
-		&args.clusterKey,
-		"cluster",
-		"c",
-		"",
-		"Name or ID of the cluster to add the IdP to (required).",
-	)
-	Cmd.MarkFlagRequired("cluster")
+	clusterprovider.UseKey(Cmd)
 
 	flags.StringVarP(
 		&args.username,
@@ -70,116 +63,89 @@ func init() {
 		"Username to grant the role to (required).",
 	)
 	Cmd.MarkFlagRequired("user")
+
+	flags.DurationVar(
+		&args.duration,
+		"duration",
+		0,
+		"Time after which the role will be revoked by 'rosa prune access', for example '8h'. "+
+			"If not specified the role is granted permanently.",
+	)
 }
 
-func run(_ *cobra.Command, argv []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
-
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
-	clusterKey := args.clusterKey
-	if !ocm.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	clusterKey, err := clusterprovider.GetKey(r, nil)
+	if err != nil {
+		return err
 	}
 
 	username := args.username
 	if !ocm.IsValidUsername(username) {
-		reporter.Errorf(
+		return rosaerrors.Usagef(
 			"username '%s' isn't valid: it must contain only letters, digits, dashes and underscores",
 			username,
 		)
-		os.Exit(1)
 	}
 
 	if len(argv) != 1 {
-		reporter.Errorf(
+		return fmt.Errorf(
 			"Expected exactly one command line argument or flag containing the name " +
 				"of the group or role to grant the user.",
 		)
-		os.Exit(1)
-	}
-	role := argv[0]
-	// Allow role aliases
-	for _, validAlias := range validRolesAliases {
-		if role == validAlias {
-			role = fmt.Sprintf("%ss", role)
-		}
-	}
-	isRoleValid := false
-	// Determine if role is valid
-	for _, validRole := range validRoles {
-		if role == validRole {
-			isRoleValid = true
-		}
-	}
-	if !isRoleValid {
-		reporter.Errorf("Expected at least one of %s", validRoles)
-	}
-
-	// Create the AWS client:
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
 	}
-
-	awsCreator, err := awsClient.GetCreator()
+	role, err := ocm.GroupForRole(argv[0])
 	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		return err
 	}
 
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+	if args.duration < 0 {
+		return rosaerrors.Usagef("Duration '%s' isn't valid: it must be positive", args.duration)
 	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
-
-	// Get the client for the OCM collection of clusters:
-	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
 
 	// Try to find the cluster:
 	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
 	if err != nil {
-		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
 	}
 
 	if cluster.State() != cmv1.ClusterStateReady {
-		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
-		os.Exit(1)
+		return rosaerrors.Conflictf("Cluster '%s' is not yet ready", clusterKey)
 	}
 
-	user, err := cmv1.NewUser().ID(username).Build()
-	if err != nil {
-		reporter.Errorf("Failed to create user '%s' for cluster '%s'", username, clusterKey)
-		os.Exit(1)
+	if role == ocm.ClusterAdminsGroup && !cluster.ClusterAdminEnabled() {
+		return rosaerrors.NotFoundf("Role '%s' isn't enabled in cluster '%s'", role, clusterKey)
 	}
 
 	reporter.Debugf("Adding user '%s' to group '%s' in cluster '%s'", username, role, clusterKey)
-	res, err := clustersCollection.Cluster(cluster.ID()).Groups().Group(role).Users().Add().Body(user).Send()
+	err = r.OCM().AddGroupUser(cluster.ID(), role, username)
 	if err != nil {
-		reporter.Debugf(err.Error())
-		reporter.Errorf("Failed to grant '%s' to user '%s' to cluster '%s': %s",
-			role, username, clusterKey, res.Error().Reason())
-		os.Exit(1)
+		return fmt.Errorf("Failed to grant '%s' to user '%s' in cluster '%s': %w",
+			role, username, clusterKey, err)
 	}
+
+	if args.duration > 0 {
+		expiration := time.Now().Add(args.duration)
+		reporter.Debugf("Recording expiration of role '%s' for user '%s' in cluster '%s'", role, username, clusterKey)
+		err = r.OCM().SetAccessExpiration(cluster, role, username, expiration)
+		if err != nil {
+			return fmt.Errorf("Failed to record expiration of role '%s' for user '%s': %w", role, username, err)
+		}
+		reporter.Infof("Granted role '%s' to user '%s' on cluster '%s' until %s. "+
+			"Run 'rosa prune access' to revoke it once it expires",
+			role, username, clusterKey, expiration.Format(time.RFC3339))
+		return nil
+	}
+
+	// The role is now permanent, so forget any previous expiration:
+	err = r.OCM().RemoveAccessExpiration(cluster, role, username)
+	if err != nil {
+		return fmt.Errorf("Failed to remove expiration of role '%s' for user '%s': %w", role, username, err)
+	}
+	reporter.Infof("Granted role '%s' to user '%s' on cluster '%s'", role, username, clusterKey)
+
+	return nil
 }
@@ -0,0 +1,104 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/dryrun"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var args struct {
+	watch bool
+}
+
+var Cmd = &cobra.Command{
+	Use:   "cluster [ID|NAME]",
+	Short: "Hibernate a cluster",
+	Long: "Hibernate a cluster, stopping its nodes so that only its storage is billed until it is " +
+		"resumed with 'rosa resume cluster'. Only ready clusters can be hibernated, and their " +
+		"workloads aren't available while they are hibernating.",
+	Example: `  # Hibernate a cluster named "mycluster"
+  rosa hibernate cluster mycluster
+
+  # Hibernate a cluster and wait until it is hibernating
+  rosa hibernate cluster --cluster=mycluster --watch
+
+  # Hibernate all the clusters whose name starts with "dev-", two at a time
+  rosa hibernate cluster --cluster="dev-*" --max-parallel=2`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	clusterprovider.UseBatch(Cmd)
+
+	flags.BoolVar(
+		&args.watch,
+		"watch",
+		false,
+		"Wait until the cluster is hibernating, reporting the changes of its state.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	clusterKey, err := clusterprovider.GetKey(r, argv)
+	if err != nil {
+		return err
+	}
+
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	err = ocm.ValidateHibernate(cluster.State())
+	if err != nil {
+		return rosaerrors.Conflictf("Cluster '%s' can't be hibernated: %v", clusterKey, err)
+	}
+
+	if !clusterprovider.ConfirmDestructive(r, cluster, "hibernate cluster %s", clusterKey) {
+		return nil
+	}
+
+	reporter.Debugf("Hibernating cluster '%s'", clusterKey)
+	err = r.OCM().HibernateCluster(cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to hibernate cluster '%s': %w", clusterKey, err)
+	}
+	if dryrun.Enabled() {
+		return nil
+	}
+	reporter.Infof("Cluster '%s' is powering down. To resume it run 'rosa resume cluster -c %s'",
+		clusterKey, clusterKey)
+
+	if args.watch {
+		return clusterprovider.WatchState(r, cluster, ocm.ClusterStateHibernating)
+	}
+	return nil
+}
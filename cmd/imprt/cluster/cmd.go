@@ -0,0 +1,183 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/confirm"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var args struct {
+	setDefault bool
+}
+
+var Cmd = &cobra.Command{
+	Use:   "cluster ID",
+	Short: "Import an OpenShift Dedicated cluster",
+	Long: "Import an OpenShift Dedicated cluster that uses the customer's cloud subscription (CCS) " +
+		"in the current AWS account, so that it can be managed with this tool. The cluster and the " +
+		"AWS account are checked first, and the configurations that aren't supported are reported.",
+	Example: `  # Import the cluster with identifier 1a2b3c4d5e6f7g8h9i0j and make it the default cluster
+  rosa import cluster 1a2b3c4d5e6f7g8h9i0j
+
+  # Import a cluster without changing the default cluster
+  rosa import cluster 1a2b3c4d5e6f7g8h9i0j --set-default=false`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.BoolVar(
+		&args.setDefault,
+		"set-default",
+		true,
+		"Make the imported cluster the default cluster, see 'rosa config set cluster'.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	if len(argv) != 1 {
+		return rosaerrors.Usagef("Expected exactly one command line parameter containing the identifier of the cluster")
+	}
+	clusterID := argv[0]
+	if !ocm.IsValidClusterKey(clusterID) {
+		return rosaerrors.Usagef("Cluster identifier '%s' isn't valid: it must contain only letters, digits, "+
+			"dashes and underscores", clusterID)
+	}
+
+	creator := r.Creator()
+
+	reporter.Debugf("Loading cluster '%s'", clusterID)
+	cluster, err := r.OCM().GetClusterByID(clusterID)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterID, err)
+	}
+
+	if ocm.IsImported(cluster, creator.ARN) {
+		reporter.Infof("Cluster '%s' is already managed by this tool", cluster.Name())
+		saveDefault(r, cluster.Name())
+		return nil
+	}
+
+	problems := ocm.CheckImport(cluster, creator.AccountID, creator.ARN)
+	if !hasBlockingProblems(problems) {
+		awsProblems, err := checkAWS(r, cluster.ID(), cluster.Region().ID(), creator.AccountID)
+		if err != nil {
+			return err
+		}
+		problems = append(problems, awsProblems...)
+	}
+	for _, problem := range problems {
+		if problem.Blocking {
+			reporter.Errorf("%s", problem.Message)
+		} else {
+			reporter.Warnf("%s", problem.Message)
+		}
+	}
+	if hasBlockingProblems(problems) {
+		return rosaerrors.Conflictf("Cluster '%s' can't be imported", cluster.Name())
+	}
+
+	if !confirm.Confirm("import cluster %s", cluster.Name()) {
+		return nil
+	}
+
+	reporter.Debugf("Importing cluster '%s'", cluster.ID())
+	err = r.OCM().ImportCluster(cluster, creator.ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to import cluster '%s': %w", cluster.Name(), err)
+	}
+	reporter.Infof("Cluster '%s' has been imported. To see its details run 'rosa describe cluster -c %s'",
+		cluster.Name(), cluster.Name())
+	saveDefault(r, cluster.Name())
+
+	return nil
+}
+
+// checkAWS checks that the resources of the cluster can be found with the current AWS credentials,
+// using the tags that the installer adds to them, and that the account has been initialized.
+func checkAWS(r *runtime.Runtime, clusterID string, region string, accountID string) ([]*ocm.ImportProblem, error) {
+	reporter := r.Reporter()
+	awsClient := r.WithAWSRegion(region).AWSClient()
+	problems := []*ocm.ImportProblem{}
+
+	infraID, err := r.OCM().GetInfraID(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get infrastructure identifier of cluster '%s': %w", clusterID, err)
+	}
+	if infraID == "" {
+		problems = append(problems, &ocm.ImportProblem{
+			Message: "Cluster has no infrastructure identifier, it may not have been installed",
+		})
+	} else {
+		reporter.Debugf("Loading AWS resources of cluster '%s'", clusterID)
+		infra, err := awsClient.GetClusterInfrastructure(infraID)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get AWS resources of cluster '%s': %w", clusterID, err)
+		}
+		if len(infra.VPCs) == 0 && len(infra.Subnets) == 0 {
+			problems = append(problems, &ocm.ImportProblem{
+				Message: fmt.Sprintf("No resources tagged for the cluster were found in region '%s' "+
+					"of AWS account '%s', check that the AWS credentials are for the account of the "+
+					"cluster", region, accountID),
+				Blocking: true,
+			})
+		}
+	}
+
+	ready, _, err := awsClient.CheckStackReadyOrNotExisting(aws.OsdCcsAdminStackName)
+	if err != nil || !ready {
+		problems = append(problems, &ocm.ImportProblem{
+			Message: "AWS account hasn't been initialized with this tool, some commands require " +
+				"running 'rosa init' first",
+		})
+	}
+	return problems, nil
+}
+
+func hasBlockingProblems(problems []*ocm.ImportProblem) bool {
+	for _, problem := range problems {
+		if problem.Blocking {
+			return true
+		}
+	}
+	return false
+}
+
+// saveDefault makes the cluster the default cluster, unless the user disabled it.
+func saveDefault(r *runtime.Runtime, clusterName string) {
+	if !args.setDefault {
+		return
+	}
+	err := clusterprovider.SaveDefaultKey(clusterName)
+	if err != nil {
+		r.Reporter().Warnf("Failed to make '%s' the default cluster: %v", clusterName, err)
+		return
+	}
+	r.Reporter().Infof("Cluster '%s' is now the default cluster", clusterName)
+}
@@ -0,0 +1,36 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imprt
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/cmd/imprt/cluster"
+	"github.com/openshift/moactl/pkg/ocm/roles"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "import RESOURCE",
+	Short: "Import a resource created with other tools",
+	Long:  "Import a resource created with other tools, so that it can be managed with this tool.",
+}
+
+func init() {
+	roles.Require(Cmd, roles.Update)
+
+	Cmd.AddCommand(cluster.Cmd)
+}
@@ -17,10 +17,12 @@ limitations under the License.
 package initialize
 
 import (
-	"os"
+	"fmt"
+	"strings"
+	"time"
 
-	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/openshift/moactl/cmd/login"
 	"github.com/openshift/moactl/cmd/verify/oc"
@@ -28,18 +30,29 @@ import (
 	"github.com/openshift/moactl/cmd/verify/quota"
 
 	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/iamsettings"
 	clusterprovider "github.com/openshift/moactl/pkg/cluster"
-	"github.com/openshift/moactl/pkg/logging"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/ocm"
 	"github.com/openshift/moactl/pkg/ocm/config"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
+// stackWatchInterval is the time between checks of the events of the stack of the admin user while
+// it is being created.
+const stackWatchInterval = 5 * time.Second
+
 var args struct {
 	region      string
 	deleteStack bool
+	repair      bool
+	tags        []string
 }
 
+// loginFlags are the names of the flags of `init` that come from `login`.
+var loginFlags []string
+
 var Cmd = &cobra.Command{
 	Use:   "init",
 	Short: "Applies templates to support Red Hat OpenShift Service on AWS",
@@ -49,8 +62,17 @@ var Cmd = &cobra.Command{
   rosa init
 
   # Configure a new AWS account using pre-existing OCM credentials
-  rosa init --token=$OFFLINE_ACCESS_TOKEN`,
-	Run: run,
+  rosa init --token=$OFFLINE_ACCESS_TOKEN
+
+  # Repair the stack of the admin user after it was changed outside of CloudFormation
+  rosa init --repair
+
+  # Tag the resources created in the AWS account, as required by some organizations
+  rosa init --tags=CostCenter=1234,Team=infra
+
+  # Create the admin user with the permissions boundary and path required by the organization
+  rosa init --permissions-boundary=arn:aws:iam::123456789012:policy/Boundary --iam-path=/rosa/`,
+	RunE: run,
 }
 
 func init() {
@@ -72,137 +94,225 @@ func init() {
 		"Deletes stack template applied to your AWS account during the 'init' command.\n",
 	)
 
+	flags.BoolVar(
+		&args.repair,
+		"repair",
+		false,
+		"Detects changes made outside of CloudFormation to the stack template applied to your AWS "+
+			"account and repairs them, recreating the stack if needed.",
+	)
+	flags.BoolVar(
+		&args.repair,
+		"update",
+		false,
+		"Alias of '--repair'.",
+	)
+	flags.MarkHidden("update")
+
+	flags.StringSliceVar(
+		&args.tags,
+		"tags",
+		nil,
+		"Tags for the stack template applied to your AWS account and for the IAM resources that it "+
+			"creates, as comma separated 'key=value' pairs, for example: --tags=CostCenter=1234,Team=infra.",
+	)
+
+	iamsettings.AddFlags(flags)
+
 	// Force-load all flags from `login` into `init`
+	login.Cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		loginFlags = append(loginFlags, flag.Name)
+	})
 	flags.AddFlagSet(login.Cmd.Flags())
 }
 
-func run(cmd *cobra.Command, argv []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
 
 	// Create the AWS client:
-	client, err := aws.NewClient().
-		Logger(logger).
-		Region(aws.DefaultRegion).
-		Build()
+	client := r.WithAWSRegion(aws.GlobalRegion()).AWSClient()
+
+	if args.deleteStack && args.repair {
+		return rosaerrors.Usagef("Options '--delete-stack' and '--repair' are mutually exclusive")
+	}
+	tags, err := aws.ParseTags(args.tags)
+	if err != nil {
+		return err
+	}
+	settings := iamsettings.Settings()
+	err = aws.ValidateIAMSettings(settings)
 	if err != nil {
-		reporter.Errorf("Error creating AWS client: %v", err)
-		os.Exit(1)
+		return err
 	}
 
 	// If necessary, call `login` as part of `init`. We do this before
 	// other validations to get the prompt out of the way before performing
-	// longer checks.
-	if cmd.Flags().NFlag() == 0 || (args.deleteStack && cmd.Flags().NFlag() == 1) {
+	// longer checks. Only the flags of `login` force the login, the flags
+	// of `init` itself don't.
+	if !loginFlagsChanged(cmd) {
 		// Verify if user is already logged in:
 		isLoggedIn := false
 		cfg, err := config.Load()
 		if err != nil {
-			reporter.Errorf("Failed to load config file: %v", err)
-			os.Exit(1)
+			return fmt.Errorf("Failed to load config file: %w", err)
 		}
 		if cfg != nil {
 			// Check that credentials in the config file are valid
 			isLoggedIn, err = cfg.Armed()
 			if err != nil {
-				reporter.Errorf("Failed to determine if user is logged in: %v", err)
-				os.Exit(1)
+				return fmt.Errorf("Failed to determine if user is logged in: %w", err)
 			}
 		}
 
 		if isLoggedIn {
 			username, err := cfg.GetData("username")
 			if err != nil {
-				reporter.Errorf("Failed to get username: %v", err)
-				os.Exit(1)
+				return fmt.Errorf("Failed to get username: %w", err)
 			}
 
 			reporter.Infof("Logged in as '%s' on '%s'", username, cfg.URL)
 		} else {
-			login.Cmd.Run(cmd, argv)
+			err = login.Cmd.RunE(cmd, argv)
+			if err != nil {
+				return err
+			}
 		}
 	} else {
 		// Always force login if user sets login flags
-		login.Cmd.Run(cmd, argv)
+		err = login.Cmd.RunE(cmd, argv)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Validate AWS credentials for current user
 	reporter.Infof("Validating AWS credentials...")
 	ok, err := client.ValidateCredentials()
 	if err != nil {
-		reporter.Errorf("Error validating AWS credentials: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("Error validating AWS credentials: %w", err)
 	}
 	if !ok {
-		reporter.Errorf("AWS credentials are invalid")
-		os.Exit(1)
+		return rosaerrors.Authf("AWS credentials are invalid")
 	}
 	reporter.Infof("AWS credentials are valid!")
 
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().Logger(logger).Build()
-	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
-	}
-	defer ocmConnection.Close()
-	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
+	// Creating the stack of the admin user takes a few minutes:
+	r.CheckAWSIdentity(15 * time.Minute)
 
 	// Delete CloudFormation stack and exit
 	if args.deleteStack {
 		reporter.Infof("Deleting cluster administrator user '%s'...", aws.AdminUserName)
 
 		// Get creator ARN to determine existing clusters:
-		awsCreator, err := client.GetCreator()
-		if err != nil {
-			reporter.Errorf("Failed to get AWS creator: %v", err)
-			os.Exit(1)
-		}
+		awsCreator := r.Creator()
 
 		// Check whether the account has clusters:
-		hasClusters, err := ocm.HasClusters(clustersCollection, awsCreator.ARN)
+		hasClusters, err := r.OCM().HasClusters(awsCreator.ARN)
 		if err != nil {
-			reporter.Errorf("Failed to check for clusters: %v", err)
-			os.Exit(1)
+			return fmt.Errorf("Failed to check for clusters: %w", err)
 		}
 
 		if hasClusters {
-			reporter.Errorf(
+			return fmt.Errorf(
 				"Failed to delete '%s': User still has clusters.",
 				aws.AdminUserName)
-			os.Exit(1)
 		}
 
 		// Delete the CloudFormation stack
 		err = client.DeleteOsdCcsAdminUser(aws.OsdCcsAdminStackName)
 		if err != nil {
-			reporter.Errorf("Failed to delete user '%s': %v", aws.AdminUserName, err)
-			os.Exit(1)
+			return fmt.Errorf("Failed to delete user '%s': %w", aws.AdminUserName, err)
 		}
 
 		reporter.Infof("Admin user '%s' deleted successfully!", aws.AdminUserName)
-		os.Exit(0)
+		return nil
+	}
+
+	// Repair the CloudFormation stack and exit
+	if args.repair {
+		reporter.Infof("Checking stack '%s' for changes made outside of CloudFormation...",
+			aws.OsdCcsAdminStackName)
+		drift, err := client.RepairOsdCcsAdminUser(aws.OsdCcsAdminStackName, tags, settings)
+		if err != nil {
+			return fmt.Errorf("Failed to repair stack '%s': %w", aws.OsdCcsAdminStackName, err)
+		}
+		if !drift.Drifted() {
+			reporter.Infof("Stack '%s' is in sync and up to date", aws.OsdCcsAdminStackName)
+			return nil
+		}
+		for _, resource := range drift.Resources {
+			reporter.Infof("Recreated %s resource '%s' (%s)",
+				strings.ToLower(resource.Status), resource.PhysicalID, resource.Type)
+		}
+		reporter.Infof("Stack '%s' repaired successfully!", aws.OsdCcsAdminStackName)
+		return nil
 	}
 
 	// Validate AWS SCP/IAM Permissions
 	// Call `verify permissions` as part of init
-	permissions.Cmd.Run(cmd, argv)
+	err = permissions.Cmd.RunE(cmd, argv)
+	if err != nil {
+		return err
+	}
 
 	// Validate AWS quota
 	// Call `verify quota` as part of init
-	quota.Cmd.Run(cmd, argv)
+	err = quota.Cmd.RunE(cmd, argv)
+	if err != nil {
+		return err
+	}
 
 	// Ensure that there is an AWS user to create all the resources needed by the cluster:
-	reporter.Infof("Ensuring cluster administrator user '%s'...", aws.AdminUserName)
-	created, err := client.EnsureOsdCcsAdminUser(aws.OsdCcsAdminStackName, aws.AdminUserName)
+	progress := reporter.StartProgress("Ensuring cluster administrator user '%s'...", aws.AdminUserName)
+	stopWatch := watchStack(client, aws.OsdCcsAdminStackName, progress)
+	created, err := client.EnsureOsdCcsAdminUser(aws.OsdCcsAdminStackName, aws.AdminUserName, tags,
+		settings)
+	stopWatch()
+	progress.Stop()
 	if err != nil {
-		reporter.Errorf("Failed to create user '%s': %v", aws.AdminUserName, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to create user '%s': %w", aws.AdminUserName, err)
 	}
 	if created {
 		reporter.Infof("Admin user '%s' created successfully!", aws.AdminUserName)
 	} else {
 		reporter.Infof("Admin user '%s' already exists!", aws.AdminUserName)
+
+		// Changes made to the stack resources outside of CloudFormation make cluster creation fail
+		// later in confusing ways, so point the user to the repair:
+		drift, err := client.DetectStackDrift(aws.OsdCcsAdminStackName)
+		if err != nil {
+			reporter.Debugf("Failed to detect drift of stack '%s': %v", aws.OsdCcsAdminStackName, err)
+		} else if drift.Drifted() {
+			for _, resource := range drift.Resources {
+				reporter.Warnf("Resource '%s' (%s) of stack '%s' was %s outside of CloudFormation",
+					resource.PhysicalID, resource.Type, aws.OsdCcsAdminStackName,
+					strings.ToLower(resource.Status))
+			}
+			return rosaerrors.Conflictf("Stack '%s' doesn't match its template, run 'rosa init --repair' to fix it",
+				aws.OsdCcsAdminStackName)
+		}
+	}
+
+	// Ensure that Elastic Load Balancing can create the load balancers of the clusters:
+	reporter.Infof("Ensuring service linked role '%s'...", aws.ELBServiceLinkedRoleName)
+	exists, err := client.HasELBServiceLinkedRole()
+	if err != nil {
+		return fmt.Errorf("Failed to check service linked role '%s': %w", aws.ELBServiceLinkedRoleName, err)
+	}
+	if exists {
+		reporter.Infof("Service linked role '%s' already exists!", aws.ELBServiceLinkedRoleName)
+	} else {
+		err = client.CreateELBServiceLinkedRole()
+		if err != nil {
+			return fmt.Errorf(
+				"Failed to create service linked role '%s': %v\n"+
+					"Ask an administrator of the AWS account to create it running:\n\n"+
+					"   %s\n",
+				aws.ELBServiceLinkedRoleName, err, aws.ELBServiceLinkedRoleCommand,
+			)
+		}
+		reporter.Infof("Service linked role '%s' created successfully!", aws.ELBServiceLinkedRoleName)
 	}
 
 	// Check if osdCcsAdmin has right permissions
@@ -210,14 +320,13 @@ func run(cmd *cobra.Command, argv []string) {
 	target := aws.AdminUserName
 	isValid, err := client.ValidateSCP(&target)
 	if !isValid {
-		reporter.Errorf("Failed to verify permissions for user '%s': %v", target, err)
-		os.Exit(1)
+		return rosaerrors.Authf("Failed to verify permissions for user '%s': %w", target, err)
 	}
 	reporter.Infof("AWS SCP policies ok")
 
 	// Check whether the user can create a basic cluster
 	reporter.Infof("Validating cluster creation...")
-	err = simulateCluster(clustersCollection, args.region)
+	err = simulateCluster(r.OCM(), client, args.region)
 	if err != nil {
 		reporter.Warnf("Cluster creation failed. "+
 			"If you create a cluster, it should fail with the following error:\n%s", err)
@@ -225,13 +334,22 @@ func run(cmd *cobra.Command, argv []string) {
 		reporter.Infof("Cluster creation valid")
 	}
 
-	oc.Cmd.Run(cmd, argv)
+	return oc.Cmd.RunE(cmd, argv)
 }
 
-func simulateCluster(client *cmv1.ClustersClient, region string) error {
+func loginFlagsChanged(cmd *cobra.Command) bool {
+	for _, name := range loginFlags {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func simulateCluster(ocmClient ocm.Client, awsClient aws.Client, region string) error {
 	dryRun := true
 	if region == "" {
-		region = aws.DefaultRegion
+		region = aws.GlobalRegion()
 	}
 	spec := clusterprovider.Spec{
 		Name:   "rosa-init",
@@ -239,10 +357,38 @@ func simulateCluster(client *cmv1.ClustersClient, region string) error {
 		DryRun: &dryRun,
 	}
 
-	_, err := clusterprovider.CreateCluster(client, spec)
+	_, err := clusterprovider.CreateCluster(ocmClient, awsClient, spec)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// watchStack shows the latest event of the given stack as the phase of the progress, until the
+// returned function is called. The stack may not exist yet, so failures to get the events are
+// ignored.
+func watchStack(client aws.Client, stackName string, progress *rprtr.Progress) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(stackWatchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				event, err := client.GetStackProgress(stackName)
+				if err == nil && event != "" {
+					progress.Phasef("Stack '%s': %s", stackName, event)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
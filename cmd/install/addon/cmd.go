@@ -0,0 +1,190 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"fmt"
+	"strconv"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/ci"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/confirm"
+	"github.com/openshift/moactl/pkg/dryrun"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var args struct {
+	params []string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "addon ID",
+	Aliases: []string{"addons", "add-on", "add-ons"},
+	Short:   "Install an add-on on a cluster",
+	Long: "Install a Red Hat managed add-on on a cluster. The parameters of the add-on are given " +
+		"with the '--param' option, and the required ones that aren't given are prompted for in " +
+		"interactive terminals. Run 'rosa describe addon' to see the parameters of an add-on.",
+	Example: `  # Install the CodeReady Workspaces add-on on a cluster named "mycluster"
+  rosa install addon --cluster=mycluster codeready-workspaces
+
+  # Install an add-on giving the values of its parameters
+  rosa install addon --cluster=mycluster my-addon --param notification-email=me@example.com`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	clusterprovider.UseKey(Cmd)
+
+	flags.StringArrayVar(
+		&args.params,
+		"param",
+		nil,
+		"Value of a parameter of the add-on, in 'key=value' format. Can be given multiple times.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	// Check command line arguments:
+	if len(argv) != 1 || argv[0] == "" {
+		return rosaerrors.Usagef("Expected exactly one command line parameter containing the identifier " +
+			"of the add-on")
+	}
+	addOnID := argv[0]
+
+	params, err := ocm.ParseAddOnParameters(args.params)
+	if err != nil {
+		return err
+	}
+
+	clusterKey, err := clusterprovider.GetKey(r, nil)
+	if err != nil {
+		return err
+	}
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	if cluster.State() != cmv1.ClusterStateReady {
+		return rosaerrors.Conflictf("Cluster '%s' is not yet ready", clusterKey)
+	}
+
+	reporter.Debugf("Checking if add-on '%s' is installed on cluster '%s'", addOnID, clusterKey)
+	addOnInstallation, err := r.OCM().GetAddOnInstallation(cluster.ID(), addOnID)
+	if err != nil {
+		return fmt.Errorf("Failed to get add-on '%s' for cluster '%s': %w", addOnID, clusterKey, err)
+	}
+	if addOnInstallation != nil {
+		return rosaerrors.Conflictf("Add-on '%s' is already installed on cluster '%s'", addOnID, clusterKey)
+	}
+
+	reporter.Debugf("Loading parameters of add-on '%s'", addOnID)
+	schema, err := r.OCM().GetAddOnSchema(addOnID)
+	if err != nil {
+		return fmt.Errorf("Failed to get add-on '%s': %w", addOnID, err)
+	}
+
+	err = schema.ValidateParameters(params)
+	if err != nil {
+		return err
+	}
+
+	// Required parameters that weren't given are prompted for, and outside of terminals their
+	// default values are used, if they have them:
+	for _, parameter := range schema.MissingParameters(params) {
+		switch {
+		case interactive.IsTerminal():
+			params[parameter.ID], err = promptParameter(parameter)
+			if err != nil {
+				return rosaerrors.Usagef("Expected a valid value for parameter '%s': %w", parameter.ID, err)
+			}
+		case parameter.DefaultValue != "":
+			reporter.Debugf("Using default value '%s' for parameter '%s'",
+				parameter.DefaultValue, parameter.ID)
+			params[parameter.ID] = parameter.DefaultValue
+		default:
+			return rosaerrors.Usagef("Parameter '%s' of add-on '%s' is required, use '--param %s=VALUE'",
+				parameter.ID, addOnID, parameter.ID)
+		}
+	}
+
+	if !confirm.Confirm("install add-on '%s' on cluster '%s'", addOnID, clusterKey) {
+		return nil
+	}
+
+	reporter.Debugf("Installing add-on '%s' on cluster '%s'", addOnID, clusterKey)
+	err = r.OCM().InstallAddOn(cluster.ID(), addOnID, params)
+	if err != nil {
+		return fmt.Errorf("Failed to install add-on '%s' on cluster '%s': %w", addOnID, clusterKey, err)
+	}
+	if dryrun.Enabled() {
+		return nil
+	}
+	ci.RecordResource(&ci.Resource{Kind: "addon", Cluster: cluster.ID(), ID: addOnID})
+	reporter.Infof("Add-on '%s' is now installing. To check the status run 'rosa list addons -c %s'",
+		addOnID, clusterKey)
+
+	return nil
+}
+
+// promptParameter asks the user for the value of the given parameter, offering its options if it
+// has them.
+func promptParameter(parameter ocm.AddOnParameter) (string, error) {
+	input := interactive.Input{
+		Question: parameter.Name,
+		Help:     parameter.Description,
+		Default:  parameter.DefaultValue,
+		Required: true,
+	}
+	if len(parameter.Options) > 0 {
+		for _, option := range parameter.Options {
+			input.Options = append(input.Options, option.Value)
+		}
+		return interactive.GetOption(input)
+	}
+	if parameter.ValueType == "boolean" {
+		// A false answer would be rejected as empty if the prompt were required:
+		input.Required = false
+		input.Default, _ = strconv.ParseBool(parameter.DefaultValue)
+		value, err := interactive.GetBool(input)
+		return strconv.FormatBool(value), err
+	}
+	input.Validators = []interactive.Validator{
+		func(answer interface{}) error {
+			str, ok := answer.(string)
+			if !ok || str == "" {
+				return nil
+			}
+			return parameter.Validate(str)
+		},
+	}
+	return interactive.GetString(input)
+}
@@ -0,0 +1,195 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package access
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/output"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/table"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "access",
+	Short: "List cluster access",
+	Long: "List the identity providers, the administrative users and their groups, and the " +
+		"status of the cluster-admin user of a cluster, to audit who can access it.",
+	Example: `  # List the access to a cluster named "mycluster"
+  rosa list access --cluster=mycluster`,
+	RunE: run,
+}
+
+func init() {
+	clusterprovider.UseKey(Cmd)
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	err := output.Validate()
+	if err != nil {
+		return err
+	}
+
+	clusterKey, err := clusterprovider.GetKey(r, nil)
+	if err != nil {
+		return err
+	}
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	if cluster.State() != cmv1.ClusterStateReady {
+		return rosaerrors.Conflictf("Cluster '%s' is not yet ready", clusterKey)
+	}
+
+	reporter.Debugf("Loading identity providers for cluster '%s'", clusterKey)
+	idps, err := r.OCM().GetIdentityProviders(cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get identity providers for cluster '%s': %w", clusterKey, err)
+	}
+
+	// The cluster-admin user is not listed as a regular user, as it is reported separately:
+	var adminIDP *cmv1.IdentityProvider
+	for _, idp := range idps {
+		if ocm.IdentityProviderType(idp) == "htpasswd" && idp.Name() == ocm.AdminIdentityProviderName {
+			adminIDP = idp
+		}
+	}
+
+	groups := make(map[string][]string)
+	for _, group := range ocm.GetClusterGroups(cluster) {
+		reporter.Debugf("Loading %s for cluster '%s'", group, clusterKey)
+		users, err := r.OCM().GetUsers(cluster.ID(), group)
+		if err != nil {
+			return fmt.Errorf("Failed to get %s for cluster '%s': %w", group, clusterKey, err)
+		}
+		for _, user := range users {
+			if user.ID() == ocm.AdminUsername && adminIDP != nil {
+				continue
+			}
+			membership := group
+			// Temporary grants show when they will be revoked:
+			if expiration := ocm.GetAccessExpiration(cluster, group, user.ID()); expiration != nil {
+				membership = fmt.Sprintf("%s (until %s)", group, expiration.Time.Format(time.RFC3339))
+			}
+			groups[user.ID()] = append(groups[user.ID()], membership)
+		}
+	}
+
+	userIDs := make([]string, 0, len(groups))
+	for id := range groups {
+		userIDs = append(userIDs, id)
+	}
+	sort.Strings(userIDs)
+
+	if output.Structured() {
+		return printStructured(r, cluster, idps, adminIDP, userIDs, groups)
+	}
+
+	// Create the writer that will be used to print the tabulated results:
+	writer := table.NewWriter(os.Stdout)
+
+	fmt.Fprintf(writer, "IDENTITY PROVIDER\tTYPE\n")
+	if len(idps) == 0 {
+		fmt.Fprintf(writer, "None\t\n")
+	}
+	for _, idp := range idps {
+		fmt.Fprintf(writer, "%s\t%s\n", idp.Name(), ocm.IdentityProviderType(idp))
+	}
+	fmt.Fprintf(writer, "\t\n")
+
+	fmt.Fprintf(writer, "USER\tGROUPS\n")
+	if len(userIDs) == 0 {
+		fmt.Fprintf(writer, "None\t\n")
+	}
+	for _, id := range userIDs {
+		fmt.Fprintf(writer, "%s\t%s\n", id, strings.Join(groups[id], ", "))
+	}
+	writer.Flush()
+
+	fmt.Printf("\nCluster admin: %s\n", adminStatus(cluster, adminIDP))
+
+	return nil
+}
+
+// access is the structured representation of the access to a cluster. The identity providers are
+// the OCM objects, the rest is computed from the groups of the cluster.
+type access struct {
+	IdentityProviders json.RawMessage `json:"identity_providers"`
+	Users             []accessUser    `json:"users"`
+	ClusterAdmin      string          `json:"cluster_admin"`
+}
+
+type accessUser struct {
+	ID     string   `json:"id"`
+	Groups []string `json:"groups"`
+}
+
+func printStructured(r *runtime.Runtime, cluster *cmv1.Cluster, idps []*cmv1.IdentityProvider,
+	adminIDP *cmv1.IdentityProvider, userIDs []string, groups map[string][]string) error {
+	buffer := &bytes.Buffer{}
+	err := cmv1.MarshalIdentityProviderList(idps, buffer)
+	if err != nil {
+		return fmt.Errorf("Failed to print access: %w", err)
+	}
+	value := &access{
+		IdentityProviders: buffer.Bytes(),
+		Users:             []accessUser{},
+		ClusterAdmin:      adminStatus(cluster, adminIDP),
+	}
+	for _, id := range userIDs {
+		value.Users = append(value.Users, accessUser{ID: id, Groups: groups[id]})
+	}
+	err = output.PrintValue(value)
+	if err != nil {
+		return fmt.Errorf("Failed to print access: %w", err)
+	}
+
+	return nil
+}
+
+// adminStatus describes the status of the cluster-admin user that 'rosa create admin' creates
+// for break-glass access to the cluster.
+func adminStatus(cluster *cmv1.Cluster, adminIDP *cmv1.IdentityProvider) string {
+	if adminIDP != nil && adminIDP.Htpasswd() != nil {
+		return fmt.Sprintf("Enabled (user '%s' of identity provider '%s')",
+			adminIDP.Htpasswd().Username(), adminIDP.Name())
+	}
+	if !cluster.ClusterAdminEnabled() {
+		return "Disabled"
+	}
+	return "Not created"
+}
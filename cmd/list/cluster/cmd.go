@@ -17,21 +17,35 @@ limitations under the License.
 package cluster
 
 import (
+	"encoding/csv"
 	"fmt"
+	"io"
 	"os"
-	"text/tabwriter"
+	"strings"
+	"time"
 
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/moactl/pkg/aws"
-	clusterprovider "github.com/openshift/moactl/pkg/cluster"
-	"github.com/openshift/moactl/pkg/logging"
+	"github.com/openshift/moactl/pkg/deprecation"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/ocm"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/ocm/versions"
+	"github.com/openshift/moactl/pkg/output"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/table"
+	"github.com/openshift/moactl/pkg/watch"
 )
 
 var args struct {
-	count int
+	count    int
+	all      bool
+	page     int
+	pageSize int
+	filter   string
+	region   string
+	version  string
+	owner    string
 }
 
 var Cmd = &cobra.Command{
@@ -40,8 +54,32 @@ var Cmd = &cobra.Command{
 	Short:   "List clusters",
 	Long:    "List clusters.",
 	Example: `  # List all clusters
-  rosa list clusters`,
-	Run: run,
+  rosa list clusters
+
+  # List all clusters including their exact creation time
+  rosa list clusters --output=wide
+
+  # Export all clusters to a file that can be opened with a spreadsheet
+  rosa list clusters --output=csv > clusters.csv
+
+  # List all clusters as JSON, to process them with other tools
+  rosa list clusters --output=json
+
+  # List the clusters with version 4.5 in region us-east-1
+  rosa list clusters --region=us-east-1 --version=4.5
+
+  # List all the ready clusters in region us-east-1, using the OCM search syntax
+  rosa list clusters --all --filter "region.id = 'us-east-1' and state = 'ready'"
+
+  # List the second page of 50 clusters
+  rosa list clusters --page=2 --size=50
+
+  # Keep the list of clusters up to date as their states change
+  rosa list clusters --watch
+
+  # Print an event for each change of the clusters, to process them with other tools
+  rosa list clusters --watch --output=json`,
+	RunE: run,
 }
 
 func init() {
@@ -55,72 +93,322 @@ func init() {
 		100,
 		"Number of clusters to display.",
 	)
+	flags.BoolVar(
+		&args.all,
+		"all",
+		false,
+		"Display all the clusters, retrieving as many pages as needed, instead of only the number "+
+			"given with '--count'.",
+	)
+	flags.IntVar(
+		&args.page,
+		"page",
+		0,
+		"Display only this page of clusters, of the size given with '--size'.",
+	)
+	flags.IntVar(
+		&args.pageSize,
+		"size",
+		100,
+		"Number of clusters to retrieve from the API in each request, and size of the page selected "+
+			"with '--page'. Each page is printed as soon as it is received.",
+	)
+	deprecation.AddFlag(Cmd, "page-size", "", "size", "0.2.0")
+	// Filters
+	flags.StringVar(
+		&args.filter,
+		"filter",
+		"",
+		"List only the clusters that match this OCM search expression, for example "+
+			"\"region.id = 'us-east-1' and state = 'ready'\". It is combined with the other filters.",
+	)
+	flags.StringVar(
+		&args.region,
+		"region",
+		"",
+		"List only the clusters in this AWS region.",
+	)
+	flags.StringVar(
+		&args.version,
+		"version",
+		"",
+		"List only the clusters with this OpenShift version. A partial version, like '4.5', matches all "+
+			"the versions that start with it.",
+	)
+	flags.StringVar(
+		&args.owner,
+		"owner",
+		"",
+		"List only the clusters created by this Red Hat account username.",
+	)
+	watch.AddFlags(flags)
 }
 
-func run(_ *cobra.Command, argv []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
 
 	// Check command line arguments:
 	if len(argv) != 0 {
-		reporter.Errorf("Expected exactly zero command line parameters")
-		os.Exit(1)
+		return rosaerrors.Usagef("Expected exactly zero command line parameters")
 	}
 
-	// Create the AWS client:
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Build()
+	err := output.Validate("wide", "csv")
 	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		return rosaerrors.Wrap(rosaerrors.ExitUsage, err)
+	}
+	wide := output.Format() == "wide"
+	csvOutput := output.Format() == "csv"
+	structured := output.Structured()
+
+	if args.count < 1 {
+		return rosaerrors.Usagef("Expected a positive number of clusters to display")
+	}
+	if args.pageSize < 1 {
+		return rosaerrors.Usagef("Expected a positive page size")
+	}
+	if cmd.Flags().Changed("page") {
+		if args.page < 1 {
+			return rosaerrors.Usagef("Expected a positive page number")
+		}
+		if args.all || cmd.Flags().Changed("count") {
+			return rosaerrors.Usagef("Option '--page' can't be used with '--all' or '--count'")
+		}
 	}
 
-	awsCreator, err := awsClient.GetCreator()
+	err = watch.Validate()
 	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		return rosaerrors.Wrap(rosaerrors.ExitUsage, err)
+	}
+	if watch.Enabled() && args.page > 0 {
+		return rosaerrors.Usagef("Option '--watch' can't be used with '--page'")
+	}
+	if watch.Enabled() && (csvOutput || output.Format() == output.YAML ||
+		output.Format() == output.RedactedYAML) {
+		return rosaerrors.Usagef("Option '--watch' can only be used with the table and the 'json' formats")
 	}
 
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
+	search, err := buildSearch()
 	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		return rosaerrors.Wrap(rosaerrors.ExitUsage, err)
+	}
+	reporter.Debugf("Using search expression '%s'", search)
+
+	if watch.Enabled() {
+		return watchClusters(r, search, wide)
 	}
-	defer func() {
-		err = ocmConnection.Close()
+
+	// The end of life dates of the versions are used to warn the user about clusters that need to
+	// be upgraded soon. They are only retrieved when the first cluster is printed, and this is
+	// informative only, so failures aren't fatal:
+	var endOfLifeDates map[string]time.Time
+	getEndOfLife := func(cluster *cmv1.Cluster) time.Time {
+		if endOfLifeDates == nil {
+			dates, err := r.OCM().GetEndOfLifeDates("")
+			if err != nil {
+				reporter.Debugf("Failed to fetch end of life dates: %v", err)
+				dates = map[string]time.Time{}
+			}
+			endOfLifeDates = dates
+		}
+		return endOfLifeDates[versions.GetVersionID(cluster)]
+	}
+	endOfLifeWarnings := []string{}
+
+	// Create the writer that will be used to print the tabulated results. Each page of results is
+	// flushed as soon as it arrives, so that large collections don't need to be kept in memory. The
+	// columns are as wide as the longest name, state and age from the start, so that they don't
+	// change from one page to the next:
+	writer := table.NewWriter(os.Stdout).
+		MinWidth("NAME", 15).
+		MinWidth("STATE", len(ocm.ClusterStatePoweringDown)).
+		MinWidth("AGE", len("364d"))
+	printed := 0
+	now := time.Now()
+
+	// The CSV output always contains the header, even if there are no clusters, and the exact
+	// creation time instead of the age, as that is what spreadsheets can work with:
+	csvWriter := csv.NewWriter(os.Stdout)
+	if csvOutput {
+		err = csvWriter.Write([]string{"ID", "NAME", "STATE", "CREATED"})
 		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
+			return fmt.Errorf("Failed to write CSV output: %w", err)
 		}
-	}()
+	}
+
+	// The structured formats need the complete list, so the clusters are collected instead of
+	// printed as they arrive:
+	collected := []*cmv1.Cluster{}
 
 	// Retrieve the list of clusters:
-	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
-	clusters, err := clusterprovider.GetClusters(clustersCollection, awsCreator.ARN, args.count)
+	printPage := func(clusters []*cmv1.Cluster) bool {
+		for _, cluster := range clusters {
+			if !args.all && args.page == 0 && printed == args.count {
+				return false
+			}
+			if structured {
+				collected = append(collected, cluster)
+				printed++
+				continue
+			}
+			if csvOutput {
+				_ = csvWriter.Write([]string{
+					cluster.ID(),
+					cluster.Name(),
+					string(cluster.State()),
+					cluster.CreationTimestamp().UTC().Format(time.RFC3339),
+				})
+				printed++
+				continue
+			}
+			if printed == 0 {
+				if wide {
+					fmt.Fprintf(writer, "ID\tNAME\tSTATE\tAGE\tCREATED\n")
+				} else {
+					fmt.Fprintf(writer, "ID\tNAME\tSTATE\tAGE\n")
+				}
+			}
+			age := formatAge(now.Sub(cluster.CreationTimestamp()))
+			if wide {
+				fmt.Fprintf(
+					writer,
+					"%s\t%s\t%s\t%s\t%s\n",
+					cluster.ID(),
+					cluster.Name(),
+					cluster.State(),
+					age,
+					cluster.CreationTimestamp().Format("Jan _2 2006 15:04:05 MST"),
+				)
+			} else {
+				fmt.Fprintf(
+					writer,
+					"%s\t%s\t%s\t%s\n",
+					cluster.ID(),
+					cluster.Name(),
+					cluster.State(),
+					age,
+				)
+			}
+			endOfLife := getEndOfLife(cluster)
+			if versions.IsNearEndOfLife(endOfLife) {
+				endOfLifeWarnings = append(endOfLifeWarnings, fmt.Sprintf("Cluster '%s': %s",
+					cluster.Name(), versions.EndOfLifeWarning(cluster.OpenshiftVersion(), endOfLife)))
+			}
+			printed++
+		}
+		writer.FlushPart()
+		csvWriter.Flush()
+		return csvWriter.Error() == nil && (args.all || printed < args.count)
+	}
+	total := 0
+	if args.page > 0 {
+		var clusters []*cmv1.Cluster
+		clusters, total, err = r.OCM().GetClustersPage(r.Creator().ARN, search,
+			args.page, args.pageSize)
+		if err == nil {
+			printPage(clusters)
+		}
+	} else {
+		err = r.OCM().StreamClusters(r.Creator().ARN, search, args.pageSize,
+			printPage)
+	}
 	if err != nil {
-		reporter.Errorf("Failed to get clusters: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get clusters: %w", err)
+	}
+	if csvWriter.Error() != nil {
+		return fmt.Errorf("Failed to write CSV output: %v", csvWriter.Error())
 	}
 
-	if len(clusters) == 0 {
-		reporter.Infof("No clusters available")
-		os.Exit(0)
+	if structured {
+		err = output.Print(func(writer io.Writer) error {
+			return cmv1.MarshalClusterList(collected, writer)
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to print clusters: %w", err)
+		}
+		return nil
 	}
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(writer, "ID\tNAME\tSTATE\n")
-	for _, cluster := range clusters {
-		fmt.Fprintf(
-			writer,
-			"%s\t%s\t%s\n",
-			cluster.ID(),
-			cluster.Name(),
-			cluster.State(),
-		)
+	// Messages would end up mixed with the CSV data, so they are only printed for the tables:
+	if csvOutput {
+		return nil
+	}
+	if printed == 0 {
+		if search != "" {
+			reporter.Infof("No clusters match the filters")
+		} else {
+			reporter.Infof("No clusters available")
+		}
+	}
+	for _, warning := range endOfLifeWarnings {
+		reporter.Warnf("%s", warning)
+	}
+	pages := (total + args.pageSize - 1) / args.pageSize
+	switch {
+	case args.page > 0 && args.page < pages:
+		reporter.Infof("Displayed page %d of %d, use '--page=%d' to display the next one",
+			args.page, pages, args.page+1)
+	case args.page == 0 && !args.all && printed == args.count:
+		reporter.Infof("Displayed the first %d clusters, there may be more. Use '--all' to display all "+
+			"of them, or '--page' to select a page", printed)
+	}
+	return nil
+}
+
+// buildSearch translates the filter flags into an OCM search expression. The result is empty when
+// no filter is used.
+func buildSearch() (string, error) {
+	filters := []struct {
+		flag  string
+		value string
+	}{
+		{"region", args.region},
+		{"version", args.version},
+		{"owner", args.owner},
+	}
+	for _, filter := range filters {
+		if strings.ContainsAny(filter.value, "'%\\") {
+			return "", fmt.Errorf("Value '%s' of option '--%s' isn't valid: it must not contain quotes, "+
+				"percent signs or backslashes", filter.value, filter.flag)
+		}
+	}
+
+	terms := []string{}
+	if args.region != "" {
+		terms = append(terms, fmt.Sprintf("region.id = '%s'", args.region))
+	}
+	if args.version != "" {
+		// Versions may be given with the same 'v' prefix that users see in other places:
+		version := strings.TrimPrefix(args.version, "v")
+		terms = append(terms, fmt.Sprintf("(openshift_version = '%s' or openshift_version like '%s.%%')",
+			version, version))
+	}
+	if args.owner != "" {
+		terms = append(terms, fmt.Sprintf("creator.username = '%s'", args.owner))
+	}
+	if args.filter != "" {
+		terms = append(terms, fmt.Sprintf("(%s)", args.filter))
+	}
+	return strings.Join(terms, " and "), nil
+}
+
+// formatAge returns a short, human readable representation of the given duration, following the
+// same conventions that 'kubectl' uses for the AGE column, for example '45s', '5h' or '3d'.
+func formatAge(d time.Duration) string {
+	if d < 0 {
+		return "0s"
+	}
+	if seconds := int(d.Seconds()); seconds < 60 {
+		return fmt.Sprintf("%ds", seconds)
+	}
+	if minutes := int(d.Minutes()); minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	if hours := int(d.Hours()); hours < 24 {
+		return fmt.Sprintf("%dh", hours)
+	}
+	if days := int(d.Hours() / 24); days < 365 {
+		return fmt.Sprintf("%dd", days)
 	}
-	writer.Flush()
+	return fmt.Sprintf("%dy", int(d.Hours()/24/365))
 }
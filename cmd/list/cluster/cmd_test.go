@@ -0,0 +1,148 @@
+package cluster_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/cmd/list/cluster"
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mocks"
+	ocmmocks "github.com/openshift/moactl/pkg/ocm/mocks"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/simulate"
+)
+
+var _ = Describe("List clusters", func() {
+	var (
+		ctrl      *gomock.Controller
+		awsClient *mocks.MockClient
+		ctx       context.Context
+	)
+
+	BeforeEach(func() {
+		// The OCM API is simulated, and the AWS client is a mock:
+		simulate.Enable()
+		ctrl = gomock.NewController(GinkgoT())
+		awsClient = mocks.NewMockClient(ctrl)
+		awsClient.EXPECT().GetCreator().Return(&aws.Creator{
+			ARN:       simulate.UserARN,
+			AccountID: simulate.AccountID,
+		}, nil)
+		ctx = runtime.NewContext(context.Background(), runtime.New().WithAWSClient(awsClient))
+		Expect(cluster.Cmd.Flags().Set("filter", "")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		simulate.Disable()
+		ctrl.Finish()
+	})
+
+	It("Lists the clusters of the AWS user", func() {
+		output := run(ctx)
+
+		Expect(output).To(ContainSubstring(simulate.ClusterID))
+		Expect(output).To(ContainSubstring(simulate.ClusterName))
+		Expect(output).To(ContainSubstring("ready"))
+	})
+
+	It("Lists only the clusters that match the filter", func() {
+		output := run(ctx, "--filter", "name = 'other'")
+
+		Expect(output).ToNot(ContainSubstring(simulate.ClusterID))
+	})
+})
+
+var _ = Describe("List clusters with a mock of the OCM client", func() {
+	var (
+		ctrl      *gomock.Controller
+		ocmClient *ocmmocks.MockClient
+		ctx       context.Context
+	)
+
+	// stream makes the mock return the given clusters as a single page:
+	stream := func(clusters ...*cmv1.Cluster) {
+		ocmClient.EXPECT().StreamClusters("arn:aws:iam::123456789012:user/tester", "", 100, gomock.Any()).
+			DoAndReturn(func(_ string, _ string, _ int, fn func(page []*cmv1.Cluster) bool) error {
+				fn(clusters)
+				return nil
+			})
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		awsClient := mocks.NewMockClient(ctrl)
+		awsClient.EXPECT().GetCreator().Return(&aws.Creator{
+			ARN:       "arn:aws:iam::123456789012:user/tester",
+			AccountID: "123456789012",
+		}, nil)
+		ocmClient = ocmmocks.NewMockClient(ctrl)
+		ctx = runtime.NewContext(context.Background(),
+			runtime.New().WithAWSClient(awsClient).WithOCMClient(ocmClient))
+		Expect(cluster.Cmd.Flags().Set("filter", "")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("Prints the clusters returned by OCM", func() {
+		mycluster, err := cmv1.NewCluster().
+			ID("1a2b3c").
+			Name("mycluster").
+			State(cmv1.ClusterStateInstalling).
+			CreationTimestamp(time.Now()).
+			Version(cmv1.NewVersion().ID("openshift-v4.5.1")).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		stream(mycluster)
+		ocmClient.EXPECT().GetEndOfLifeDates("").Return(map[string]time.Time{}, nil)
+
+		output := run(ctx)
+
+		Expect(output).To(ContainSubstring("1a2b3c"))
+		Expect(output).To(ContainSubstring("mycluster"))
+		Expect(output).To(ContainSubstring("installing"))
+	})
+
+	It("Doesn't retrieve the end of life dates when there are no clusters", func() {
+		// The mock fails the test if the dates are retrieved:
+		stream()
+
+		run(ctx)
+	})
+})
+
+// run executes the command with the given context and arguments, and returns what it writes to the
+// standard output. The output is read while the command runs, so that it doesn't block when the
+// pipe is full.
+func run(ctx context.Context, argv ...string) string {
+	reader, writer, err := os.Pipe()
+	Expect(err).ToNot(HaveOccurred())
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() {
+		os.Stdout = stdout
+	}()
+	var data []byte
+	var readErr error
+	done := make(chan struct{})
+	go func() {
+		data, readErr = ioutil.ReadAll(reader)
+		close(done)
+	}()
+
+	cluster.Cmd.SetArgs(argv)
+	err = cluster.Cmd.ExecuteContext(ctx)
+	writer.Close()
+	<-done
+	Expect(err).ToNot(HaveOccurred())
+	Expect(readErr).ToNot(HaveOccurred())
+	return string(data)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/output"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/table"
+	"github.com/openshift/moactl/pkg/watch"
+)
+
+// watchClusters lists the clusters that match the given search again each time that the state of
+// one of them changes. In a terminal the table is refreshed in place, otherwise it is printed again
+// after each change. With the JSON format each change is printed as an event on its own line.
+func watchClusters(r *runtime.Runtime, search string, wide bool) error {
+	reporter := r.Reporter()
+	creatorARN := r.Creator().ARN
+	terminal := interactive.IsTerminal()
+
+	clusters := map[string]*cmv1.Cluster{}
+	load := func() ([]*watch.Item, error) {
+		items := []*watch.Item{}
+		loaded := map[string]*cmv1.Cluster{}
+		err := r.OCM().StreamClusters(creatorARN, search, args.pageSize,
+			func(page []*cmv1.Cluster) bool {
+				for _, cluster := range page {
+					if !args.all && len(items) == args.count {
+						return false
+					}
+					var buffer bytes.Buffer
+					err := cmv1.MarshalCluster(cluster, &buffer)
+					if err != nil {
+						return false
+					}
+					loaded[cluster.ID()] = cluster
+					items = append(items, &watch.Item{
+						Key:    cluster.ID(),
+						State:  fmt.Sprintf("%s %s %s", cluster.Name(), cluster.State(), cluster.OpenshiftVersion()),
+						Object: json.RawMessage(bytes.TrimSpace(buffer.Bytes())),
+					})
+				}
+				return args.all || len(items) < args.count
+			})
+		clusters = loaded
+		return items, err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	err := watch.Run(load, func(items []*watch.Item, events []*watch.Event) error {
+		if output.Structured() {
+			for _, event := range events {
+				err := encoder.Encode(event)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		if terminal {
+			watch.ClearScreen(os.Stdout)
+		} else {
+			fmt.Println()
+		}
+		writer := table.NewWriter(os.Stdout)
+		if wide {
+			fmt.Fprintf(writer, "ID\tNAME\tSTATE\tAGE\tCREATED\n")
+		} else {
+			fmt.Fprintf(writer, "ID\tNAME\tSTATE\tAGE\n")
+		}
+		now := time.Now()
+		for _, item := range items {
+			cluster := clusters[item.Key]
+			age := formatAge(now.Sub(cluster.CreationTimestamp()))
+			if wide {
+				fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", cluster.ID(), cluster.Name(), cluster.State(), age,
+					cluster.CreationTimestamp().Format("Jan _2 2006 15:04:05 MST"))
+			} else {
+				fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", cluster.ID(), cluster.Name(), cluster.State(), age)
+			}
+		}
+		writer.Flush()
+		if len(items) == 0 {
+			reporter.Infof("No clusters available, waiting for changes")
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to watch clusters: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustergroup
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/output"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/table"
+)
+
+var Cmd = &cobra.Command{
+	Use:     "cluster-groups",
+	Aliases: []string{"cluster-group", "clustergroups", "clustergroup"},
+	Short:   "List groups of clusters",
+	Long:    "List the groups of clusters defined in the configuration file.",
+	Example: `  # List all cluster groups
+  rosa list cluster-groups`,
+	RunE: run,
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	err := output.Validate()
+	if err != nil {
+		return err
+	}
+
+	groups, err := cluster.GetGroups()
+	if err != nil {
+		return fmt.Errorf("Failed to load cluster groups: %w", err)
+	}
+	if output.Structured() {
+		err = output.PrintValue(groups)
+		if err != nil {
+			return fmt.Errorf("Failed to print cluster groups: %w", err)
+		}
+		return nil
+	}
+	if len(groups) == 0 {
+		reporter.Warnf("There are no cluster groups. To create one run 'rosa create cluster-group'")
+		return nil
+	}
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Create the writer that will be used to print the tabulated results:
+	writer := table.NewWriter(os.Stdout)
+	fmt.Fprintf(writer, "NAME\tSEARCH\n")
+	for _, name := range names {
+		fmt.Fprintf(writer, "%s\t%s\n", name, groups[name])
+	}
+	writer.Flush()
+
+	return nil
+}
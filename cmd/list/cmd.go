@@ -19,15 +19,21 @@ package list
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/moactl/cmd/list/access"
 	"github.com/openshift/moactl/cmd/list/addon"
 	"github.com/openshift/moactl/cmd/list/cluster"
+	"github.com/openshift/moactl/cmd/list/clustergroup"
 	"github.com/openshift/moactl/cmd/list/idp"
 	"github.com/openshift/moactl/cmd/list/ingress"
+	"github.com/openshift/moactl/cmd/list/instancetype"
 	"github.com/openshift/moactl/cmd/list/machinepool"
 	"github.com/openshift/moactl/cmd/list/region"
+	"github.com/openshift/moactl/cmd/list/servicelog"
 	"github.com/openshift/moactl/cmd/list/upgrade"
 	"github.com/openshift/moactl/cmd/list/user"
 	"github.com/openshift/moactl/cmd/list/version"
+
+	"github.com/openshift/moactl/pkg/output"
 )
 
 var Cmd = &cobra.Command{
@@ -37,12 +43,18 @@ var Cmd = &cobra.Command{
 }
 
 func init() {
+	output.AddFlag(Cmd.PersistentFlags())
+
+	Cmd.AddCommand(access.Cmd)
 	Cmd.AddCommand(addon.Cmd)
 	Cmd.AddCommand(cluster.Cmd)
+	Cmd.AddCommand(clustergroup.Cmd)
 	Cmd.AddCommand(idp.Cmd)
 	Cmd.AddCommand(ingress.Cmd)
+	Cmd.AddCommand(instancetype.Cmd)
 	Cmd.AddCommand(machinepool.Cmd)
 	Cmd.AddCommand(region.Cmd)
+	Cmd.AddCommand(servicelog.Cmd)
 	Cmd.AddCommand(upgrade.Cmd)
 	Cmd.AddCommand(user.Cmd)
 	Cmd.AddCommand(version.Cmd)
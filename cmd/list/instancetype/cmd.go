@@ -0,0 +1,168 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancetype
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm/machines"
+	"github.com/openshift/moactl/pkg/output"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/table"
+)
+
+var args struct {
+	region            string
+	availabilityZones []string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "instance-types",
+	Aliases: []string{"instance-type", "machine-types", "machine-type"},
+	Short:   "List available instance types",
+	Long: "List the instance types supported for the nodes of clusters and machine pools that EC2 " +
+		"offers in the selected region and availability zones.",
+	Example: `  # List the instance types available in region us-east-1
+  rosa list instance-types --region=us-east-1
+
+  # List the instance types available in two availability zones
+  rosa list instance-types --region=us-east-1 --availability-zones=us-east-1a,us-east-1b`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.StringVarP(
+		&args.region,
+		"region",
+		"r",
+		"",
+		"AWS region where the instance types are used (overrides the AWS_REGION environment variable).",
+	)
+	flags.StringSliceVar(
+		&args.availabilityZones,
+		"availability-zones",
+		nil,
+		"List only the instance types offered in all these availability zones of the region.",
+	)
+}
+
+// instanceType is the structured representation of an available instance type.
+type instanceType struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Category  string   `json:"category"`
+	VCPUs     float64  `json:"vcpus"`
+	MemoryGiB float64  `json:"memory_gib"`
+	Zones     []string `json:"availability_zones"`
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	err := output.Validate()
+	if err != nil {
+		return err
+	}
+
+	region, err := aws.GetRegion(args.region)
+	if err != nil {
+		return fmt.Errorf("Error getting region: %w", err)
+	}
+	awsClient := r.WithAWSRegion(region).AWSClient()
+
+	reporter.Debugf("Fetching instance types")
+	machineTypes, err := r.OCM().GetMachineTypes()
+	if err != nil {
+		return fmt.Errorf("Failed to fetch instance types: %w", err)
+	}
+
+	reporter.Debugf("Fetching instance types offered in region '%s'", region)
+	offerings, err := awsClient.GetInstanceTypeZones()
+	if err != nil {
+		return err
+	}
+	for _, zone := range args.availabilityZones {
+		if !strings.HasPrefix(zone, region) {
+			return rosaerrors.Usagef("Availability zone '%s' isn't in region '%s'", zone, region)
+		}
+	}
+
+	available := []*instanceType{}
+	for _, machineType := range machineTypes {
+		if !machines.IsAvailable(machineType.ID(), offerings, args.availabilityZones) {
+			continue
+		}
+		available = append(available, &instanceType{
+			ID:        machineType.ID(),
+			Name:      machineType.Name(),
+			Category:  string(machineType.Category()),
+			VCPUs:     machineType.CPU().Value(),
+			MemoryGiB: memoryGiB(machineType.Memory()),
+			Zones:     offerings[machineType.ID()],
+		})
+	}
+
+	if output.Structured() {
+		err = output.PrintValue(available)
+		if err != nil {
+			return fmt.Errorf("Failed to print instance types: %w", err)
+		}
+		return nil
+	}
+
+	if len(available) == 0 {
+		return rosaerrors.NotFoundf("There are no instance types available in region '%s'", region)
+	}
+
+	// Create the writer that will be used to print the tabulated results:
+	writer := table.NewWriter(os.Stdout).Numeric("VCPU")
+	fmt.Fprintf(writer, "ID\t\tCATEGORY\t\tVCPU\t\tMEMORY\t\tAVAILABILITY ZONES\n")
+	for _, item := range available {
+		fmt.Fprintf(writer,
+			"%s\t\t%s\t\t%g\t\t%g GiB\t\t%s\n",
+			item.ID,
+			item.Category,
+			item.VCPUs,
+			item.MemoryGiB,
+			strings.Join(item.Zones, ", "),
+		)
+	}
+	writer.Flush()
+
+	return nil
+}
+
+// memoryGiB returns the given amount of memory in GiB. OCM usually returns it in bytes.
+func memoryGiB(memory *cmv1.Value) float64 {
+	switch strings.ToLower(memory.Unit()) {
+	case "gib":
+		return memory.Value()
+	case "mib":
+		return memory.Value() / 1024
+	default:
+		return memory.Value() / (1 << 30)
+	}
+}
@@ -17,169 +17,161 @@ limitations under the License.
 package machinepool
 
 import (
+	"encoding/csv"
 	"fmt"
+	"io"
 	"os"
 	"strings"
-	"text/tabwriter"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/moactl/pkg/aws"
-	"github.com/openshift/moactl/pkg/logging"
-	"github.com/openshift/moactl/pkg/ocm"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm/machinepools"
+	"github.com/openshift/moactl/pkg/output"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/table"
 )
 
-var args struct {
-	clusterKey string
-}
-
 var Cmd = &cobra.Command{
 	Use:     "machinepools",
 	Aliases: []string{"machinepool", "machine-pools", "machine-pool"},
 	Short:   "List cluster machine pools",
 	Long:    "List machine pools configured on a cluster.",
 	Example: `  # List all machine pools on a cluster named "mycluster"
-  rosa list machinepools --cluster=mycluster`,
-	Run: run,
+  rosa list machinepools --cluster=mycluster
+
+  # Export the machine pools of a cluster to a file that can be opened with a spreadsheet
+  rosa list machinepools --cluster=mycluster --output=csv > machinepools.csv`,
+	RunE: run,
 }
 
 func init() {
-	flags := Cmd.Flags()
-
-	flags.StringVarP(
-		&args.clusterKey,
-		"cluster",
-		"c",
-		"",
-		"Name or ID of the cluster to list the machine pools of (required).",
-	)
-	Cmd.MarkFlagRequired("cluster")
+	clusterprovider.UseKey(Cmd)
 }
 
-func run(_ *cobra.Command, _ []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
-
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
-	clusterKey := args.clusterKey
-	if !ocm.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
-	}
-
-	// Create the AWS client:
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
-	}
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
 
-	awsCreator, err := awsClient.GetCreator()
+	err := output.Validate("csv")
 	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		return err
 	}
 
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
+	clusterKey, err := clusterprovider.GetKey(r, nil)
 	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		return err
 	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
-
-	// Get the client for the OCM collection of clusters:
-	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
 
 	// Try to find the cluster:
 	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
 	if err != nil {
-		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
 	}
 
 	if cluster.State() != cmv1.ClusterStateReady {
-		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
-		os.Exit(1)
+		return rosaerrors.Conflictf("Cluster '%s' is not yet ready", clusterKey)
 	}
 
 	// Load any existing machine pools for this cluster
 	reporter.Debugf("Loading machine pools for cluster '%s'", clusterKey)
-	machinePools, err := ocm.GetMachinePools(clustersCollection, cluster.ID())
+	machinePools, err := r.OCM().GetMachinePools(cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get machine pools for cluster '%s': %w", clusterKey, err)
+	}
+
+	// The SDK doesn't return the spot market options, so they are loaded separately:
+	spot, err := r.OCM().GetSpotMarketOptions(cluster.ID())
 	if err != nil {
-		reporter.Errorf("Failed to get machine pools for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get spot instances of machine pools for cluster '%s': %w", clusterKey, err)
+	}
+
+	// The structured formats contain only the machine pools returned by OCM, as the default one is
+	// part of the cluster:
+	if output.Structured() {
+		err = output.Print(func(writer io.Writer) error {
+			return cmv1.MarshalMachinePoolList(machinePools, writer)
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to print machine pools: %w", err)
+		}
+		return nil
+	}
+	if output.Format() == "csv" {
+		return printCSV(r, cluster, machinePools, spot)
 	}
 
 	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	writer := table.NewWriter(os.Stdout).Numeric("REPLICAS")
 
-	fmt.Fprintf(writer, "ID\tREPLICAS\tINSTANCE TYPE\tLABELS\t\tTAINTS\t\tAVAILABILITY ZONES\n")
-	fmt.Fprintf(writer, "%s\t%d\t%s\t%s\t\t%s\t\t%s\n",
+	fmt.Fprintf(writer, "ID\tREPLICAS\tINSTANCE TYPE\tLABELS\t\tTAINTS\t\tAVAILABILITY ZONES\tSPOT INSTANCES\n")
+	fmt.Fprintf(writer, "%s\t%d\t%s\t%s\t\t%s\t\t%s\t%s\n",
 		"default",
 		cluster.Nodes().Compute(),
 		cluster.Nodes().ComputeMachineType().ID(),
-		printLabels(cluster.Nodes().ComputeLabels()),
+		machinepools.FormatLabels(cluster.Nodes().ComputeLabels()),
 		"",
 		printAZ(cluster.Nodes().AvailabilityZones()),
+		machinepools.FormatSpot(nil),
 	)
 	for _, machinePool := range machinePools {
-		fmt.Fprintf(writer, "%s\t%d\t%s\t%s\t\t%s\t\t%s\n",
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t\t%s\t\t%s\t%s\n",
 			machinePool.ID(),
-			machinePool.Replicas(),
+			machinepools.FormatReplicas(machinePool),
 			machinePool.InstanceType(),
-			printLabels(machinePool.Labels()),
-			printTaints(machinePool.Taints()),
+			machinepools.FormatLabels(machinePool.Labels()),
+			machinepools.FormatTaints(machinePool.Taints()),
 			printAZ(machinePool.AvailabilityZones()),
+			machinepools.FormatSpot(spot[machinePool.ID()]),
 		)
 	}
 	writer.Flush()
-}
 
-func printAZ(az []string) string {
-	if len(az) == 0 {
-		return ""
-	}
-	return strings.Join(az, ", ")
+	return nil
 }
 
-func printLabels(labels map[string]string) string {
-	if len(labels) == 0 {
-		return ""
+// printCSV writes the machine pools, including the default one, in CSV format to the standard
+// output.
+func printCSV(r *runtime.Runtime, cluster *cmv1.Cluster, machinePools []*cmv1.MachinePool,
+	spot map[string]*machinepools.SpotMarketOptions) error {
+	writer := csv.NewWriter(os.Stdout)
+	_ = writer.Write([]string{
+		"ID", "REPLICAS", "INSTANCE TYPE", "LABELS", "TAINTS", "AVAILABILITY ZONES", "SPOT INSTANCES",
+	})
+	_ = writer.Write([]string{
+		"default",
+		fmt.Sprintf("%d", cluster.Nodes().Compute()),
+		cluster.Nodes().ComputeMachineType().ID(),
+		machinepools.FormatLabels(cluster.Nodes().ComputeLabels()),
+		"",
+		printAZ(cluster.Nodes().AvailabilityZones()),
+		machinepools.FormatSpot(nil),
+	})
+	for _, machinePool := range machinePools {
+		_ = writer.Write([]string{
+			machinePool.ID(),
+			machinepools.FormatReplicas(machinePool),
+			machinePool.InstanceType(),
+			machinepools.FormatLabels(machinePool.Labels()),
+			machinepools.FormatTaints(machinePool.Taints()),
+			printAZ(machinePool.AvailabilityZones()),
+			machinepools.FormatSpot(spot[machinePool.ID()]),
+		})
 	}
-	output := []string{}
-	for k, v := range labels {
-		output = append(output, fmt.Sprintf("%s=%s", k, v))
+	writer.Flush()
+	if writer.Error() != nil {
+		return fmt.Errorf("Failed to write CSV output: %v", writer.Error())
 	}
 
-	return strings.Join(output, ", ")
+	return nil
 }
 
-func printTaints(taints []*cmv1.Taint) string {
-	if len(taints) == 0 {
+func printAZ(az []string) string {
+	if len(az) == 0 {
 		return ""
 	}
-	output := []string{}
-	for _, taint := range taints {
-		output = append(output, fmt.Sprintf("%s=%s:%s", taint.Key(), taint.Value(), taint.Effect()))
-	}
-
-	return strings.Join(output, ", ")
+	return strings.Join(az, ", ")
 }
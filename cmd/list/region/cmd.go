@@ -18,15 +18,19 @@ package region
 
 import (
 	"fmt"
+	"io"
 	"os"
-	"text/tabwriter"
+	"strings"
 
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/moactl/pkg/logging"
-	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/aws"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/ocm/regions"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/output"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/table"
 )
 
 var args struct {
@@ -39,8 +43,14 @@ var Cmd = &cobra.Command{
 	Short:   "List available regions",
 	Long:    "List regions that are available for the current AWS account.",
 	Example: `  # List all available regions
-  rosa list regions`,
-	Run: run,
+  rosa list regions
+
+  # List all available regions including their availability zones and Local Zones
+  rosa list regions --output=wide
+
+  # List all available regions as JSON
+  rosa list regions --output=json`,
+	RunE: run,
 }
 
 func init() {
@@ -53,45 +63,29 @@ func init() {
 	)
 }
 
-func run(cmd *cobra.Command, _ []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
 
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
+	err := output.Validate("wide")
 	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		return err
 	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
-
-	// Get the client for the OCM collection of clusters:
-	ocmClient := ocmConnection.ClustersMgmt().V1()
+	wide := output.Format() == "wide"
 
 	// Try to find the cluster:
 	reporter.Debugf("Fetching regions")
-	regions, err := regions.GetRegions(ocmClient)
+	regions, err := regions.GetRegions(r.OCM(), r.WithAWSRegion(aws.GlobalRegion()).AWSClient())
 	if err != nil {
-		reporter.Errorf("Failed to fetch regions: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to fetch regions: %w", err)
 	}
 
 	if len(regions) == 0 {
-		reporter.Warnf("There are no regions available for this AWS account")
-		os.Exit(1)
+		return rosaerrors.NotFoundf("There are no regions available for this AWS account")
 	}
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(writer, "ID\t\tNAME\t\tMULTI-AZ SUPPORT\n")
-
+	// Select the regions to display:
+	selected := []*cmv1.CloudRegion{}
 	for _, region := range regions {
 		if !region.Enabled() {
 			continue
@@ -101,12 +95,67 @@ func run(cmd *cobra.Command, _ []string) {
 				continue
 			}
 		}
-		fmt.Fprintf(writer,
-			"%s\t\t%s\t\t%t\n",
-			region.ID(),
-			region.DisplayName(),
-			region.SupportsMultiAZ(),
-		)
+		selected = append(selected, region)
+	}
+
+	if output.Structured() {
+		err = output.Print(func(writer io.Writer) error {
+			return cmv1.MarshalCloudRegionList(selected, writer)
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to print regions: %w", err)
+		}
+		return nil
+	}
+
+	// Fetch the availability zones and Local Zones of all the regions at once for the wide output:
+	var zones map[string][]string
+	var localZones map[string][]string
+	if wide {
+		regionIDs := make([]string, len(selected))
+		for i, region := range selected {
+			regionIDs[i] = region.ID()
+		}
+		reporter.Debugf("Fetching availability zones for %d regions", len(regionIDs))
+		zones, err = aws.GetAvailabilityZonesByRegion(r.Logger(), regionIDs)
+		if err != nil {
+			return fmt.Errorf("Failed to fetch availability zones: %w", err)
+		}
+		reporter.Debugf("Fetching Local Zones for %d regions", len(regionIDs))
+		localZones, err = aws.GetLocalZonesByRegion(r.Logger(), regionIDs)
+		if err != nil {
+			return fmt.Errorf("Failed to fetch Local Zones: %w", err)
+		}
+	}
+
+	// Create the writer that will be used to print the tabulated results:
+	writer := table.NewWriter(os.Stdout)
+	if wide {
+		fmt.Fprintf(writer, "ID\t\tNAME\t\tMULTI-AZ SUPPORT\t\tAVAILABILITY ZONES\t\tLOCAL ZONES\n")
+	} else {
+		fmt.Fprintf(writer, "ID\t\tNAME\t\tMULTI-AZ SUPPORT\n")
+	}
+
+	for _, region := range selected {
+		if wide {
+			fmt.Fprintf(writer,
+				"%s\t\t%s\t\t%t\t\t%s\t\t%s\n",
+				region.ID(),
+				region.DisplayName(),
+				region.SupportsMultiAZ(),
+				strings.Join(zones[region.ID()], ", "),
+				strings.Join(localZones[region.ID()], ", "),
+			)
+		} else {
+			fmt.Fprintf(writer,
+				"%s\t\t%s\t\t%t\n",
+				region.ID(),
+				region.DisplayName(),
+				region.SupportsMultiAZ(),
+			)
+		}
 	}
 	writer.Flush()
+
+	return nil
 }
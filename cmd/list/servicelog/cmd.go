@@ -0,0 +1,167 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicelog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+	"github.com/spf13/cobra"
+
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/output"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/table"
+)
+
+var args struct {
+	severities []string
+	since      time.Duration
+	details    bool
+}
+
+var Cmd = &cobra.Command{
+	Use:     "service-logs",
+	Aliases: []string{"service-log", "servicelogs", "servicelog"},
+	Short:   "List service log entries of a cluster",
+	Long: "List the entries of the service log of a cluster, like maintenance notices and the " +
+		"actions taken by SRE, oldest first.",
+	Example: `  # List the service log of a cluster named "mycluster"
+  rosa list service-logs --cluster=mycluster
+
+  # List the warnings and errors of the last week
+  rosa list service-logs --cluster=mycluster --severity=warning,error --since=168h`,
+	Args: cobra.NoArgs,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	clusterprovider.UseKey(Cmd)
+
+	flags.StringSliceVar(
+		&args.severities,
+		"severity",
+		nil,
+		fmt.Sprintf("List only the entries with the given severities, separated by commas: '%s'.",
+			strings.Join(ocm.ServiceLogSeverities, "', '")),
+	)
+	flags.DurationVar(
+		&args.since,
+		"since",
+		0,
+		"List only the entries newer than the given duration, like '24h'. The default is to list "+
+			"all the entries.",
+	)
+	flags.BoolVar(
+		&args.details,
+		"details",
+		false,
+		"Show the description of each entry in addition to its summary.",
+	)
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	err := output.Validate()
+	if err != nil {
+		return err
+	}
+
+	filter := ocm.ServiceLogFilter{}
+	for _, severity := range args.severities {
+		severity, err = ocm.ValidateServiceLogSeverity(severity)
+		if err != nil {
+			return err
+		}
+		filter.Severities = append(filter.Severities, severity)
+	}
+	if args.since < 0 {
+		return rosaerrors.Usagef("Duration '%s' of option '--since' isn't valid, it must be positive", args.since)
+	}
+	if args.since > 0 {
+		filter.Since = time.Now().Add(-args.since)
+	}
+
+	clusterKey, err := clusterprovider.GetKey(r, nil)
+	if err != nil {
+		return err
+	}
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+	if cluster.ExternalID() == "" {
+		return rosaerrors.NotFoundf("Cluster '%s' doesn't have a service log yet", clusterKey)
+	}
+
+	reporter.Debugf("Loading service log of cluster '%s'", clusterKey)
+	entries, err := r.OCM().ListServiceLogs(cluster.ExternalID(), filter)
+	if err != nil {
+		return fmt.Errorf("Failed to get service log of cluster '%s': %w", clusterKey, err)
+	}
+
+	if output.Structured() {
+		err = output.Print(func(writer io.Writer) error {
+			return slv1.MarshalLogEntryList(entries, writer)
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to print service log: %w", err)
+		}
+		return nil
+	}
+
+	if len(entries) == 0 {
+		reporter.Infof("There are no service log entries for cluster '%s'", clusterKey)
+		return nil
+	}
+
+	// Create the writer that will be used to print the tabulated results:
+	writer := table.NewWriter(os.Stdout)
+	if args.details {
+		fmt.Fprintf(writer, "TIME\tSEVERITY\tSERVICE\tSUMMARY\tDESCRIPTION\n")
+	} else {
+		fmt.Fprintf(writer, "TIME\tSEVERITY\tSERVICE\tSUMMARY\n")
+	}
+	for _, entry := range entries {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s",
+			entry.Timestamp().Format("2006-01-02 15:04:05 MST"),
+			entry.Severity(),
+			entry.ServiceName(),
+			entry.Summary(),
+		)
+		if args.details {
+			fmt.Fprintf(writer, "\t%s", entry.Description())
+		}
+		fmt.Fprintf(writer, "\n")
+	}
+	writer.Flush()
+
+	return nil
+}
@@ -20,140 +20,175 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"text/tabwriter"
+	"time"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/moactl/pkg/aws"
-	"github.com/openshift/moactl/pkg/logging"
-	"github.com/openshift/moactl/pkg/ocm"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/ocm/upgrades"
 	"github.com/openshift/moactl/pkg/ocm/versions"
+	"github.com/openshift/moactl/pkg/output"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/table"
+	"github.com/openshift/moactl/pkg/watch"
 )
 
-var args struct {
-	clusterKey string
-}
-
 var Cmd = &cobra.Command{
 	Use:     "upgrades",
 	Aliases: []string{"upgrade"},
 	Short:   "List available cluster upgrades",
-	Long:    "List available and scheduled cluster version upgrades",
-	Run:     run,
+	Long: "List available and scheduled cluster version upgrades. Scheduled upgrades whose time has " +
+		"passed without starting, for example because they are blocked by an upgrade gate, are " +
+		"flagged as missed.",
+	Example: `  # List the upgrades of a cluster named "mycluster"
+  rosa list upgrades --cluster=mycluster
+
+  # Watch the scheduled upgrade of a cluster, and get a warning if its window is missed
+  rosa list upgrades --cluster=mycluster --watch`,
+	RunE: run,
 }
 
 func init() {
-	flags := Cmd.Flags()
-
-	flags.StringVarP(
-		&args.clusterKey,
-		"cluster",
-		"c",
-		"",
-		"Name or ID of the cluster to list the upgrades of (required).",
-	)
-	Cmd.MarkFlagRequired("cluster")
+	clusterprovider.UseKey(Cmd)
+	watch.AddFlags(Cmd.Flags())
 }
 
-func run(_ *cobra.Command, _ []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
-
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
-	clusterKey := args.clusterKey
-	if !ocm.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
-	}
-
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
-	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
-	ocmClient := ocmConnection.ClustersMgmt().V1()
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
 
-	// Create the AWS client:
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Build()
+	err := output.Validate()
+	if err == nil {
+		err = watch.Validate()
+	}
 	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		return err
+	}
+	if watch.Enabled() && (output.Format() == output.YAML || output.Format() == output.RedactedYAML) {
+		return rosaerrors.Usagef("Option '--watch' can only be used with the table and the 'json' formats")
 	}
 
-	awsCreator, err := awsClient.GetCreator()
+	clusterKey, err := clusterprovider.GetKey(r, nil)
 	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		return err
 	}
 
 	// Try to find the cluster:
 	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := ocm.GetCluster(ocmClient.Clusters(), clusterKey, awsCreator.ARN)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
 	if err != nil {
-		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
 	}
 
 	if cluster.State() != cmv1.ClusterStateReady {
-		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
-		os.Exit(1)
+		return rosaerrors.Conflictf("Cluster '%s' is not yet ready", clusterKey)
+	}
+
+	// With the JSON format only the changes of the scheduled upgrade are printed, as events:
+	if watch.Enabled() && output.Structured() {
+		return watchScheduledUpgrade(r, cluster)
 	}
 
 	// Load available upgrades for this cluster
 	reporter.Debugf("Loading available upgrades for cluster '%s'", clusterKey)
-	availableUpgrades, err := versions.GetAvailableUpgrades(ocmClient, versions.GetVersionID(cluster))
+	availableUpgrades, err := r.OCM().GetAvailableUpgrades(versions.GetVersionID(cluster))
 	if err != nil {
-		reporter.Errorf("Failed to get available upgrades for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get available upgrades for cluster '%s': %w", clusterKey, err)
 	}
 
-	if len(availableUpgrades) == 0 {
+	if len(availableUpgrades) == 0 && !output.Structured() {
 		reporter.Infof("There are no available upgrades for cluster '%s'", clusterKey)
-		os.Exit(0)
+		if watch.Enabled() {
+			return watchScheduledUpgrade(r, cluster)
+		}
+		return nil
 	}
 
 	latestRev := latestInCurrentMinor(versions.GetVersionID(cluster), availableUpgrades)
 
 	reporter.Debugf("Loading scheduled upgrades for cluster '%s'", clusterKey)
-	scheduledUpgrade, err := upgrades.GetScheduledUpgrade(ocmClient, cluster.ID())
-	if err != nil {
-		reporter.Errorf("Failed to get scheduled upgrades for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+	// The scheduled upgrade is only used to annotate the list, so it isn't fatal if it can't be
+	// loaded:
+	scheduledUpgrade, err := r.OCM().GetScheduledUpgrade(cluster.ID())
+	if err != nil && !output.Structured() {
+		reporter.Warnf("Failed to get scheduled upgrades for cluster '%s': %v", clusterKey, err)
+	}
+	var scheduledState *cmv1.UpgradePolicyState
+	missed := false
+	if scheduledUpgrade != nil {
+		scheduledState, err = r.OCM().GetUpgradeState(cluster.ID(), scheduledUpgrade.ID())
+		if err != nil {
+			reporter.Debugf("Failed to get state of scheduled upgrade: %v", err)
+		}
+		missed = upgrades.IsMissed(scheduledUpgrade, scheduledState, time.Now())
+	}
+
+	if output.Structured() {
+		return printStructured(r, availableUpgrades, latestRev, scheduledUpgrade, missed)
 	}
 
 	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	writer := table.NewWriter(os.Stdout)
 	fmt.Fprintf(writer, "VERSION\tNOTES\n")
 	for i, availableUpgrade := range availableUpgrades {
 		notes := ""
 		if notes == "" && (i == 0 || availableUpgrade == latestRev) {
 			notes = "recommended"
 		}
-		if availableUpgrade == scheduledUpgrade.Version() {
+		if scheduledUpgrade != nil && availableUpgrade == scheduledUpgrade.Version() {
 			notes = fmt.Sprintf("scheduled for %s", scheduledUpgrade.NextRun().Format("2006-01-02 15:04 MST"))
+			if missed {
+				notes = fmt.Sprintf("missed window of %s", scheduledUpgrade.NextRun().Format("2006-01-02 15:04 MST"))
+			}
 		}
 		fmt.Fprintf(writer, "%s\t%s\n", availableUpgrade, notes)
 	}
 	writer.Flush()
+	if missed {
+		reporter.WarnEventf(rprtr.CodeUpgradeMissed,
+			rprtr.Fields{"cluster": cluster.ID(), "upgrade": scheduledUpgrade.ID()},
+			"%s", upgrades.MissedWarning(clusterKey, scheduledUpgrade, scheduledState))
+	}
+
+	if watch.Enabled() {
+		return watchScheduledUpgrade(r, cluster)
+	}
+
+	return nil
+}
+
+// availableUpgrade is the structured representation of a version that a cluster can be upgraded to.
+type availableUpgrade struct {
+	Version      string     `json:"version"`
+	Recommended  bool       `json:"recommended"`
+	ScheduledFor *time.Time `json:"scheduled_for,omitempty"`
+	Missed       bool       `json:"missed,omitempty"`
+}
+
+func printStructured(r *runtime.Runtime, availableUpgrades []string, latestRev string,
+	scheduledUpgrade *cmv1.UpgradePolicy, missed bool) error {
+	values := []availableUpgrade{}
+	for i, version := range availableUpgrades {
+		value := availableUpgrade{
+			Version:     version,
+			Recommended: i == 0 || version == latestRev,
+		}
+		if scheduledUpgrade != nil && version == scheduledUpgrade.Version() {
+			nextRun := scheduledUpgrade.NextRun()
+			value.ScheduledFor = &nextRun
+			value.Missed = missed
+		}
+		values = append(values, value)
+	}
+	err := output.PrintValue(values)
+	if err != nil {
+		return fmt.Errorf("Failed to print available upgrades: %w", err)
+	}
+
+	return nil
 }
 
 func latestInCurrentMinor(current string, versions []string) string {
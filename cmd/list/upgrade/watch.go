@@ -0,0 +1,121 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/ocm/upgrades"
+	"github.com/openshift/moactl/pkg/output"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/watch"
+)
+
+// scheduledUpgrade is the representation of the scheduled upgrade used in the events.
+type scheduledUpgrade struct {
+	ID           string    `json:"id"`
+	Version      string    `json:"version"`
+	ScheduledFor time.Time `json:"scheduled_for"`
+	State        string    `json:"state,omitempty"`
+	Description  string    `json:"description,omitempty"`
+	Missed       bool      `json:"missed"`
+}
+
+// watchScheduledUpgrade reports the changes of the scheduled upgrade of the given cluster until
+// the user presses Ctrl-C. When the upgrade doesn't start at its scheduled time a warning is
+// printed, once. With the JSON format each change is printed as an event on its own line.
+func watchScheduledUpgrade(r *runtime.Runtime, cluster *cmv1.Cluster) error {
+	reporter := r.Reporter()
+
+	// Identifiers of the upgrade policies whose missed window has already been reported:
+	warned := map[string]bool{}
+
+	load := func() ([]*watch.Item, error) {
+		upgradePolicy, err := r.OCM().GetScheduledUpgrade(cluster.ID())
+		if err != nil || upgradePolicy == nil {
+			return nil, err
+		}
+		// The state only adds detail, so the upgrade is still reported if it can't be loaded:
+		state, err := r.OCM().GetUpgradeState(cluster.ID(), upgradePolicy.ID())
+		if err != nil {
+			reporter.Debugf("Failed to get state of scheduled upgrade: %v", err)
+		}
+		missed := upgrades.IsMissed(upgradePolicy, state, time.Now())
+		if missed && !warned[upgradePolicy.ID()] {
+			reporter.WarnEventf(rprtr.CodeUpgradeMissed,
+				rprtr.Fields{"cluster": cluster.ID(), "upgrade": upgradePolicy.ID()},
+				"%s", upgrades.MissedWarning(cluster.Name(), upgradePolicy, state))
+			warned[upgradePolicy.ID()] = true
+		}
+		object := &scheduledUpgrade{
+			ID:           upgradePolicy.ID(),
+			Version:      upgradePolicy.Version(),
+			ScheduledFor: upgradePolicy.NextRun(),
+			State:        state.Value(),
+			Description:  state.Description(),
+			Missed:       missed,
+		}
+		return []*watch.Item{{
+			Key:    object.ID,
+			State:  fmt.Sprintf("%s %s %s %t", object.Version, object.ScheduledFor, object.State, object.Missed),
+			Object: object,
+		}}, nil
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	err := watch.Run(load, func(items []*watch.Item, events []*watch.Event) error {
+		if output.Structured() {
+			for _, event := range events {
+				err := encoder.Encode(event)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		if len(items) == 0 && len(events) == 0 {
+			reporter.Infof("There is no scheduled upgrade for cluster '%s', waiting for changes",
+				cluster.Name())
+		}
+		for _, event := range events {
+			object := event.Object.(*scheduledUpgrade)
+			if event.Type == watch.Deleted {
+				reporter.Infof("The upgrade of cluster '%s' to version %s is no longer scheduled",
+					cluster.Name(), object.Version)
+				continue
+			}
+			state := object.State
+			if state == "" {
+				state = "unknown"
+			}
+			reporter.Infof("The upgrade of cluster '%s' to version %s scheduled for %s is %s",
+				cluster.Name(), object.Version, object.ScheduledFor.Format("2006-01-02 15:04 MST"), state)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to watch scheduled upgrade of cluster '%s': %w", cluster.Name(), err)
+	}
+
+	return nil
+}
@@ -20,153 +20,92 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"text/tabwriter"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/moactl/pkg/aws"
-	"github.com/openshift/moactl/pkg/logging"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/ocm"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/output"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/table"
 )
 
-var args struct {
-	clusterKey string
-}
-
 var Cmd = &cobra.Command{
 	Use:     "users",
 	Aliases: []string{"user"},
 	Short:   "List cluster users",
-	Long:    "List administrative cluster users.",
+	Long: "List the users of the cluster and the groups they are members of, like cluster-admins " +
+		"and dedicated-admins.",
 	Example: `  # List all users on a cluster named "mycluster"
   rosa list users --cluster=mycluster`,
-	Run: run,
+	RunE: run,
 }
 
 func init() {
-	flags := Cmd.Flags()
-
-	flags.StringVarP(
-		&args.clusterKey,
-		"cluster",
-		"c",
-		"",
-		"Name or ID of the cluster to list the users of (required).",
-	)
-	Cmd.MarkFlagRequired("cluster")
+	clusterprovider.UseKey(Cmd)
 }
 
-func run(_ *cobra.Command, _ []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
-
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
-	clusterKey := args.clusterKey
-	if !ocm.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
-	}
-
-	// Create the AWS client:
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
-	}
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
 
-	awsCreator, err := awsClient.GetCreator()
+	err := output.Validate()
 	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		return err
 	}
 
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
+	clusterKey, err := clusterprovider.GetKey(r, nil)
 	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		return err
 	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
-
-	// Get the client for the OCM collection of clusters:
-	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
 
 	// Try to find the cluster:
 	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
 	if err != nil {
-		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
 	}
 
 	if cluster.State() != cmv1.ClusterStateReady {
-		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
-		os.Exit(1)
+		return rosaerrors.Conflictf("Cluster '%s' is not yet ready", clusterKey)
 	}
 
-	var clusterAdmins []*cmv1.User
-	if cluster.ClusterAdminEnabled() {
-		reporter.Debugf("Loading users for cluster '%s'", clusterKey)
-		// Load cluster-admins for this cluster
-		clusterAdmins, err = ocm.GetUsers(clustersCollection, cluster.ID(), "cluster-admins")
-		if err != nil {
-			reporter.Errorf("Failed to get cluster-admins for cluster '%s': %v", clusterKey, err)
-			os.Exit(1)
-		}
-		// Remove cluster-admin user
-		for i, user := range clusterAdmins {
-			if user.ID() == "cluster-admin" {
-				clusterAdmins = append(clusterAdmins[:i], clusterAdmins[i+1:]...)
-			}
-		}
-	}
-
-	// Load dedicated-admins for this cluster
-	dedicatedAdmins, err := ocm.GetUsers(clustersCollection, cluster.ID(), "dedicated-admins")
+	reporter.Debugf("Loading users for cluster '%s'", clusterKey)
+	usernames, groups, err := r.OCM().GetGroupMembers(cluster)
 	if err != nil {
-		reporter.Errorf("Failed to get dedicated-admins for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get users for cluster '%s': %w", clusterKey, err)
 	}
 
-	if len(clusterAdmins) == 0 && len(dedicatedAdmins) == 0 {
-		reporter.Warnf("There are no users configured for cluster '%s'", clusterKey)
-		os.Exit(1)
+	// The cluster-admin user created with 'rosa create admin' isn't a regular user:
+	for i, username := range usernames {
+		if username == ocm.AdminUsername {
+			usernames = append(usernames[:i], usernames[i+1:]...)
+			delete(groups, username)
+			break
+		}
 	}
 
-	groups := make(map[string][]string)
-	for _, user := range clusterAdmins {
-		groups[user.ID()] = []string{"cluster-admins"}
+	if len(usernames) == 0 {
+		return rosaerrors.NotFoundf("There are no users configured for cluster '%s'", clusterKey)
 	}
-	for _, user := range dedicatedAdmins {
-		if _, ok := groups[user.ID()]; ok {
-			groups[user.ID()] = []string{"cluster-admins", "dedicated-admins"}
-		} else {
-			groups[user.ID()] = []string{"dedicated-admins"}
+
+	if output.Structured() {
+		err = output.PrintValue(groups)
+		if err != nil {
+			return fmt.Errorf("Failed to print users: %w", err)
 		}
+		return nil
 	}
 
 	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	writer := table.NewWriter(os.Stdout)
 	fmt.Fprintf(writer, "ID\t\tGROUPS\n")
-
-	for u, r := range groups {
-		fmt.Fprintf(writer, "%s\t\t%s\n", u, strings.Join(r, ", "))
-		writer.Flush()
+	for _, username := range usernames {
+		fmt.Fprintf(writer, "%s\t\t%s\n", username, strings.Join(groups[username], ", "))
 	}
+	writer.Flush()
+
+	return nil
 }
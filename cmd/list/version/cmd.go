@@ -18,19 +18,23 @@ package version
 
 import (
 	"fmt"
+	"io"
 	"os"
-	"text/tabwriter"
+	"time"
 
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/moactl/pkg/logging"
-	"github.com/openshift/moactl/pkg/ocm"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/ocm/versions"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/output"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/table"
 )
 
 var args struct {
 	channelGroup string
+	eol          bool
 }
 
 var Cmd = &cobra.Command{
@@ -39,8 +43,14 @@ var Cmd = &cobra.Command{
 	Short:   "List available versions",
 	Long:    "List versions of OpenShift that are available for creating clusters.",
 	Example: `  # List all OpenShift versions
-  rosa list versions`,
-	Run: run,
+  rosa list versions
+
+  # List all OpenShift versions including their end of life dates
+  rosa list versions --eol
+
+  # List the candidate versions, to preview upcoming releases
+  rosa list versions --channel-group=candidate`,
+	RunE: run,
 }
 
 func init() {
@@ -49,58 +59,111 @@ func init() {
 		&args.channelGroup,
 		"channel-group",
 		versions.DefaultChannelGroup,
-		"List only versions from the specified channel group",
+		"List only versions from the specified channel group: 'stable', 'candidate' or 'nightly'.",
+	)
+	// The only error is that the completion is already registered, which is fine:
+	_ = Cmd.RegisterFlagCompletionFunc("channel-group", versions.CompleteChannelGroup)
+	flags.BoolVar(
+		&args.eol,
+		"eol",
+		false,
+		"Show the end of life date of each version",
 	)
 }
 
-func run(cmd *cobra.Command, _ []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
 
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
+	err := output.Validate()
 	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		return err
 	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
 
-	// Get the client for the OCM collection of clusters:
-	ocmClient := ocmConnection.ClustersMgmt().V1()
+	err = versions.ValidateChannelGroup(args.channelGroup)
+	if err != nil {
+		return err
+	}
 
 	// Try to find the cluster:
 	reporter.Debugf("Fetching versions")
-	versions, err := versions.GetVersions(ocmClient, args.channelGroup)
+	versionList, err := r.OCM().GetVersions(args.channelGroup)
 	if err != nil {
-		reporter.Errorf("Failed to fetch versions: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to fetch versions: %w", err)
+	}
+
+	if len(versionList) == 0 {
+		return rosaerrors.NotFoundf("There are no OpenShift versions available")
 	}
 
-	if len(versions) == 0 {
-		reporter.Warnf("There are no OpenShift versions available")
-		os.Exit(1)
+	if output.Structured() {
+		enabled := []*cmv1.Version{}
+		for _, version := range versionList {
+			if version.Enabled() {
+				enabled = append(enabled, version)
+			}
+		}
+		err = output.Print(func(writer io.Writer) error {
+			return cmv1.MarshalVersionList(enabled, writer)
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to print versions: %w", err)
+		}
+		return nil
+	}
+
+	// Fetch the lifecycle dates only when they are going to be displayed:
+	var endOfLifeDates map[string]time.Time
+	if args.eol {
+		reporter.Debugf("Fetching end of life dates")
+		endOfLifeDates, err = r.OCM().GetEndOfLifeDates(args.channelGroup)
+		if err != nil {
+			return fmt.Errorf("Failed to fetch end of life dates: %w", err)
+		}
 	}
 
 	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(writer, "ID\t\tDEFAULT\n")
+	writer := table.NewWriter(os.Stdout)
+	if args.eol {
+		fmt.Fprintf(writer, "ID\t\tDEFAULT\t\tEND OF LIFE\n")
+	} else {
+		fmt.Fprintf(writer, "ID\t\tDEFAULT\n")
+	}
 
-	for _, version := range versions {
+	for _, version := range versionList {
 		if !version.Enabled() {
 			continue
 		}
-		fmt.Fprintf(writer,
-			"%s\t\t%t\n",
-			version.ID(),
-			version.Default(),
-		)
+		if args.eol {
+			fmt.Fprintf(writer,
+				"%s\t\t%t\t\t%s\n",
+				version.ID(),
+				version.Default(),
+				formatEndOfLife(endOfLifeDates[version.ID()]),
+			)
+		} else {
+			fmt.Fprintf(writer,
+				"%s\t\t%t\n",
+				version.ID(),
+				version.Default(),
+			)
+		}
 	}
 	writer.Flush()
+
+	return nil
+}
+
+func formatEndOfLife(endOfLife time.Time) string {
+	if endOfLife.IsZero() {
+		return ""
+	}
+	date := endOfLife.Format("2006-01-02")
+	switch {
+	case versions.HasReachedEndOfLife(endOfLife):
+		date += " (expired)"
+	case versions.IsNearEndOfLife(endOfLife):
+		date += " (soon)"
+	}
+	return date
 }
@@ -24,43 +24,53 @@ import (
 	sdk "github.com/openshift-online/ocm-sdk-go"
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/moactl/pkg/arguments"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/interactive"
-	"github.com/openshift/moactl/pkg/logging"
 	"github.com/openshift/moactl/pkg/ocm"
 	"github.com/openshift/moactl/pkg/ocm/config"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
 // #nosec G101
 const uiTokenPage = "https://cloud.redhat.com/openshift/token/rosa"
 
 var args struct {
-	tokenURL     string
-	clientID     string
-	clientSecret string
-	scopes       []string
-	env          string
-	token        string
-	insecure     bool
+	tokenURL      string
+	clientID      string
+	clientSecret  string
+	scopes        []string
+	token         string
+	insecure      bool
+	useDeviceCode bool
 }
 
 var Cmd = &cobra.Command{
 	Use:   "login",
 	Short: "Log in to your Red Hat account",
 	Long: fmt.Sprintf("Log in to your Red Hat account, saving the credentials to the configuration file.\n"+
+		"The tokens are saved to the keychain of the operating system when it is available.\n"+
 		"The supported mechanism is by using a token, which can be obtained at: %s\n\n"+
 		"The application looks for the token in the following order, stopping when it finds it:\n"+
 		"\t1. Command-line flags\n"+
 		"\t2. Environment variable (ROSA_TOKEN)\n"+
 		"\t3. Environment variable (OCM_TOKEN)\n"+
 		"\t4. Configuration file\n"+
-		"\t5. Command-line prompt\n", uiTokenPage),
+		"\t5. Command-line prompt\n\n"+
+		"Alternatively, use '--use-device-code' to approve the login in a browser.\n", uiTokenPage),
 	Example: `  # Login to the OpenShift staging API with an existing token
   rosa login --env staging --token=$OFFLINE_ACCESS_TOKEN
 
+  # Login reading the token from a file, so that it doesn't appear in the process list
+  rosa login --token-file=token.txt
+
   # Switch environments with an already logged-in account
-  rosa login --env production`,
-	Run: run,
+  rosa login --env production
+
+  # Login approving a code in the browser, instead of copying a token
+  rosa login --use-device-code`,
+	PreRunE: arguments.CheckFlagGroups,
+	RunE:    run,
 }
 
 func init() {
@@ -96,13 +106,6 @@ func init() {
 		"OpenID scope. If this option is used it will replace completely the default "+
 			"scopes. Can be repeated multiple times to specify multiple scopes.",
 	)
-	flags.StringVar(
-		&args.env,
-		"env",
-		sdk.DefaultURL,
-		"Environment of the API gateway. The value can be the complete URL or an alias. "+
-			"The valid aliases are 'production', 'staging' and 'integration'.",
-	)
 	flags.StringVarP(
 		&args.token,
 		"token",
@@ -110,6 +113,7 @@ func init() {
 		"",
 		"Access or refresh token.",
 	)
+	arguments.AddSecretFileFlag(flags, "token")
 	flags.BoolVar(
 		&args.insecure,
 		"insecure",
@@ -117,30 +121,40 @@ func init() {
 		"Enables insecure communication with the server. This disables verification of TLS "+
 			"certificates and host names.",
 	)
+	flags.BoolVar(
+		&args.useDeviceCode,
+		"use-device-code",
+		false,
+		"Log in opening a page in a browser, possibly in another device, and approving the code "+
+			"that is displayed, instead of using a token.",
+	)
 }
 
-func run(cmd *cobra.Command, argv []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+	logger := r.Logger()
 
-	// Check mandatory options:
-	if args.env == "" {
-		reporter.Errorf("Option '--env' is mandatory")
-		os.Exit(1)
+	err := arguments.ResolveSecrets(cmd.Flags())
+	if err != nil {
+		return err
 	}
 
 	// Load the configuration file:
 	cfg, err := config.Load()
 	if err != nil {
-		reporter.Errorf("Failed to load config file: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to load config file: %w", err)
 	}
 	if cfg == nil {
 		cfg = new(config.Config)
 	}
 
+	if args.useDeviceCode && args.token != "" {
+		return rosaerrors.Usagef("Options '--use-device-code' and '--token' are mutually exclusive")
+	}
+
 	token := args.token
-	haveReqs := token != ""
+	haveReqs := token != "" || args.useDeviceCode
 
 	// Verify environment variables:
 	if !haveReqs {
@@ -155,8 +169,7 @@ func run(cmd *cobra.Command, argv []string) {
 	if !haveReqs {
 		armed, err := cfg.Armed()
 		if err != nil {
-			reporter.Errorf("Failed to verify configuration: %v", err)
-			os.Exit(1)
+			return fmt.Errorf("Failed to verify configuration: %w", err)
 		}
 		haveReqs = armed
 	}
@@ -169,15 +182,13 @@ func run(cmd *cobra.Command, argv []string) {
 			Required: true,
 		})
 		if err != nil {
-			reporter.Errorf("Failed to parse token: %v", err)
-			os.Exit(1)
+			return fmt.Errorf("Failed to parse token: %w", err)
 		}
 		haveReqs = token != ""
 	}
 
 	if !haveReqs {
-		reporter.Errorf("Failed to login to OCM. See 'rosa login --help' for information.")
-		os.Exit(1)
+		return rosaerrors.Authf("Failed to login to OCM. See 'rosa login --help' for information.")
 	}
 
 	// Apply the default OpenID details if not explicitly provided by the user:
@@ -186,15 +197,18 @@ func run(cmd *cobra.Command, argv []string) {
 		tokenURL = args.tokenURL
 	}
 	clientID := sdk.DefaultClientID
+	if args.useDeviceCode {
+		clientID = ocm.DeviceClientID
+	}
 	if args.clientID != "" {
 		clientID = args.clientID
 	}
 
-	// If the value of the `--env` is any of the aliases then replace it with the corresponding
-	// real URL:
-	gatewayURL, ok := config.URLAliases[args.env]
-	if !ok {
-		gatewayURL = args.env
+	// Use the environment selected with '--env', 'OCM_URL' or the profile, or production if none
+	// is selected:
+	gatewayURL := config.EnvURL()
+	if gatewayURL == "" {
+		gatewayURL = sdk.DefaultURL
 	}
 
 	// Update the configuration with the values given in the command line:
@@ -205,20 +219,35 @@ func run(cmd *cobra.Command, argv []string) {
 	cfg.URL = gatewayURL
 	cfg.Insecure = args.insecure
 
-	if token != "" {
+	if args.useDeviceCode {
+		authorization, err := ocm.RequestDeviceAuthorization(tokenURL, clientID, args.scopes,
+			args.insecure)
+		if err != nil {
+			return fmt.Errorf("Failed to request device code: %w", err)
+		}
+		verificationURI := authorization.VerificationURIComplete
+		if verificationURI == "" {
+			verificationURI = authorization.VerificationURI
+		}
+		fmt.Printf("To login to your Red Hat account, open %s and approve the code %s\n",
+			verificationURI, authorization.UserCode)
+		reporter.Infof("Waiting for the code to be approved...")
+		cfg.AccessToken, cfg.RefreshToken, err = authorization.WaitForTokens()
+		if err != nil {
+			return fmt.Errorf("Failed to login with device code: %w", err)
+		}
+	} else if token != "" {
 		// If a token has been provided parse it:
 		parser := new(jwt.Parser)
 		jwtToken, _, err := parser.ParseUnverified(token, jwt.MapClaims{})
 		if err != nil {
-			reporter.Errorf("Failed to parse token '%s': %v", token, err)
-			os.Exit(1)
+			return fmt.Errorf("Failed to parse token: %w", err)
 		}
 
 		// Put the token in the place of the configuration that corresponds to its type:
 		typ, err := tokenType(jwtToken)
 		if err != nil {
-			reporter.Errorf("Failed to extract type from 'typ' claim of token '%s': %v", token, err)
-			os.Exit(1)
+			return fmt.Errorf("Failed to extract type from 'typ' claim of token: %w", err)
 		}
 		switch typ {
 		case "Bearer":
@@ -228,11 +257,9 @@ func run(cmd *cobra.Command, argv []string) {
 			cfg.AccessToken = ""
 			cfg.RefreshToken = token
 		case "":
-			reporter.Errorf("Don't know how to handle empty type in token '%s'", token)
-			os.Exit(1)
+			return rosaerrors.Usagef("Don't know how to handle empty type in token")
 		default:
-			reporter.Errorf("Don't know how to handle token type '%s' in token '%s'", typ, token)
-			os.Exit(1)
+			return rosaerrors.Usagef("Don't know how to handle token type '%s'", typ)
 		}
 	}
 
@@ -242,8 +269,7 @@ func run(cmd *cobra.Command, argv []string) {
 		Logger(logger).
 		Build()
 	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to create OCM connection: %w", err)
 	}
 	defer func() {
 		err = connection.Close()
@@ -253,8 +279,18 @@ func run(cmd *cobra.Command, argv []string) {
 	}()
 	accessToken, refreshToken, err := connection.Tokens()
 	if err != nil {
-		reporter.Errorf("Failed to get token: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get token: %w", err)
+	}
+
+	// Check that the API of the environment accepts the token, as it may have been issued for a
+	// different one:
+	response, err := connection.AccountsMgmt().V1().CurrentAccount().Get().Send()
+	if err != nil {
+		reason := response.Error().Reason()
+		if reason == "" {
+			reason = err.Error()
+		}
+		return rosaerrors.Authf("Token isn't valid for '%s': %s", gatewayURL, reason)
 	}
 
 	// Save the configuration:
@@ -262,17 +298,20 @@ func run(cmd *cobra.Command, argv []string) {
 	cfg.RefreshToken = refreshToken
 	err = config.Save(cfg)
 	if err != nil {
-		reporter.Errorf("Failed to save config file: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to save config file: %w", err)
 	}
 
 	username, err := cfg.GetData("username")
 	if err != nil {
-		reporter.Errorf("Failed to get username: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get username: %w", err)
 	}
 
 	reporter.Infof("Logged in as '%s' on '%s'", username, cfg.URL)
+	if cfg.Keyring {
+		reporter.Debugf("Tokens have been saved to the keychain")
+	}
+
+	return nil
 }
 
 // tokenType extracts the value of the `typ` claim. It returns the value as a string, or the empty
@@ -27,12 +27,13 @@ import (
 var Cmd = &cobra.Command{
 	Use:   "logout",
 	Short: "Log out",
-	Long:  "Log out, removing the configuration file.",
+	Long:  "Log out, removing the credentials from the configuration file and the keychain.",
 	RunE:  run,
 }
 
 func run(cmd *cobra.Command, argv []string) error {
-	// Remove the configuration file:
+	// Remove the configuration file. The preferences, like the default cluster, are kept in the
+	// configuration file of rosa, so they aren't lost:
 	err := config.Remove()
 	if err != nil {
 		return fmt.Errorf("Failed to remove config file: %v", err)
@@ -18,26 +18,31 @@ package install
 
 import (
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
-	"github.com/briandowns/spinner"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	errors "github.com/zgalor/weberr"
 
-	"github.com/openshift/moactl/pkg/aws"
 	clusterprovider "github.com/openshift/moactl/pkg/cluster"
-	"github.com/openshift/moactl/pkg/logging"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/ocm"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
 var args struct {
-	clusterKey string
-	tail       int
-	watch      bool
+	tail  int
+	watch bool
+}
+
+// watchOptions are shared by all the commands that watch the installation with this command, like
+// 'rosa create cluster --watch'.
+var watchOptions struct {
+	interval time.Duration
+	timeout  time.Duration
 }
 
 var Cmd = &cobra.Command{
@@ -49,19 +54,13 @@ var Cmd = &cobra.Command{
 
   # Show install logs for a cluster using the --cluster flag
   rosa logs install --cluster=mycluster`,
-	Run: run,
+	RunE: run,
 }
 
 func init() {
 	flags := Cmd.Flags()
 
-	flags.StringVarP(
-		&args.clusterKey,
-		"cluster",
-		"c",
-		"",
-		"Name or ID of the cluster to get logs for.",
-	)
+	clusterprovider.UseKey(Cmd)
 
 	flags.IntVar(
 		&args.tail,
@@ -77,84 +76,57 @@ func init() {
 		false,
 		"After getting the logs, watch for changes.",
 	)
+	AddWatchFlags(flags)
 }
 
-func run(cmd *cobra.Command, argv []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
+// AddWatchFlags adds the flags that control how the installation is watched to the given set of
+// command line flags.
+func AddWatchFlags(flags *pflag.FlagSet) {
+	flags.DurationVar(
+		&watchOptions.interval,
+		"watch-interval",
+		ocm.DefaultWatchInterval,
+		"Time between checks of the state and the logs of the cluster while watching the installation.",
+	)
+	flags.DurationVar(
+		&watchOptions.timeout,
+		"watch-timeout",
+		ocm.DefaultWatchTimeout,
+		"Maximum time to watch the installation. The installation continues after it.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
 
 	// Determine whether the user wants to watch logs streaming.
 	// We check the flag value this way to allow other commands to watch logs
 	watch := cmd.Flags().Lookup("watch").Value.String() == "true"
 
-	// Check command line arguments:
-	clusterKey := args.clusterKey
-	if clusterKey == "" {
-		if len(argv) != 1 {
-			reporter.Errorf(
-				"Expected exactly one command line argument or flag containing the name " +
-					"or identifier of the cluster",
-			)
-			os.Exit(1)
-		}
-		clusterKey = argv[0]
-	}
-
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
-	if !clusterprovider.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
+	if watch && (watchOptions.interval <= 0 || watchOptions.timeout <= 0) {
+		return rosaerrors.Usagef("Options '--watch-interval' and '--watch-timeout' must be positive durations")
 	}
 
-	// Create the AWS client:
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
-	}
-
-	awsCreator, err := awsClient.GetCreator()
-	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+	if args.tail <= 0 {
+		return rosaerrors.Usagef("Option '--tail' must be a positive number of lines")
 	}
 
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
+	clusterKey, err := clusterprovider.GetKey(r, argv)
 	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		return err
 	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
-
-	// Get the client for the OCM collection of clusters:
-	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
 
 	// Try to find the cluster:
 	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := clusterprovider.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
 	if err != nil {
-		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
 	}
 
 	if cluster.State() == cmv1.ClusterStateReady {
 		reporter.Infof("Cluster '%s' has been successfully installed", clusterKey)
-		os.Exit(0)
+		return nil
 	}
 
 	pendingMessage := fmt.Sprintf(
@@ -163,74 +135,91 @@ func run(cmd *cobra.Command, argv []string) {
 	)
 	if cluster.State() == cmv1.ClusterStatePending && !watch {
 		if cluster.CreationTimestamp().Add(5 * time.Minute).Before(time.Now()) {
-			reporter.Errorf(
+			return rosaerrors.Conflictf(
 				"Cluster '%s' has been in %s state for too long. Please contact support",
 				clusterKey, cluster.State(),
 			)
-			os.Exit(1)
 		}
 		reporter.Warnf(pendingMessage)
-		os.Exit(0)
+		return nil
 	}
 
 	// Get logs from Hive
-	logs, err := ocm.GetInstallLogs(clustersCollection, cluster.ID(), args.tail)
+	logs, err := r.OCM().GetInstallLogs(cluster.ID(), args.tail)
 	if err != nil {
 		if errors.GetType(err) == errors.NotFound {
 			reporter.Infof(pendingMessage)
 		} else {
-			reporter.Errorf("Failed to get logs for cluster '%s': %v", clusterKey, err)
-			os.Exit(1)
+			return fmt.Errorf("Failed to get logs for cluster '%s': %w", clusterKey, err)
 		}
 	}
 	printLog(logs, nil)
 
 	if watch {
 		if cluster.State() == cmv1.ClusterStateReady {
-			reporter.Infof("Cluster '%s' is successfully installed", clusterKey)
-			os.Exit(0)
+			reporter.Progressf(rprtr.CodeClusterReady, rprtr.Fields{"cluster": cluster.ID()},
+				"Cluster '%s' is successfully installed", clusterKey)
+			return nil
 		}
 
-		spin := spinner.New(spinner.CharSets[9], 100*time.Millisecond)
-		spin.Start()
+		// Warn about the AWS limits that make installations fail while they can still be raised:
+		limits := newLimitWatcher(reporter, r.WithAWSRegion(cluster.Region().ID()).AWSClient())
+		limits.checkLogs(logs.Content())
+
+		progress := reporter.StartProgress("Cluster '%s' is %s", clusterKey, cluster.State())
 
 		// Poll for changing logs:
-		response, err := ocm.PollInstallLogs(clustersCollection, cluster.ID(), func(logResponse *cmv1.LogGetResponse) bool {
-			state, _ := ocm.GetClusterState(clustersCollection, cluster.ID())
-			if state == cmv1.ClusterStateError {
-				reporter.Errorf("There was an error installing cluster '%s'", clusterKey)
-				os.Exit(1)
-			}
-			if state == cmv1.ClusterStateReady {
-				reporter.Infof("Cluster '%s' is now ready", clusterKey)
-				return true
+		state, err := r.OCM().WatchInstall(cluster.ID(), watchOptions.interval,
+			watchOptions.timeout, func(state cmv1.ClusterState, logs *cmv1.Log) {
+				progress.Phasef("Cluster '%s' is %s", clusterKey, state)
+				lines := printLog(logs, progress)
+				limits.checkLogs(lines)
+				limits.checkUsage()
+			})
+		progress.Stop()
+		switch {
+		case err == ocm.ErrWatchInterrupted:
+			reporter.Progressf(rprtr.CodeWatchStopped, rprtr.Fields{"cluster": cluster.ID()},
+				"Stopped watching cluster '%s', the installation continues. To watch it again "+
+					"run 'rosa logs install -c %s --watch'", clusterKey, clusterKey)
+		case err == ocm.ErrWatchTimeout:
+			return &rprtr.EventError{
+				Code:   rprtr.CodeWatchTimeout,
+				Fields: rprtr.Fields{"cluster": cluster.ID(), "state": state},
+				Err: rosaerrors.Transientf("Cluster '%s' is still in state '%s' after %s", clusterKey, state,
+					watchOptions.timeout),
 			}
-			printLog(logResponse.Body(), spin)
-			return false
-		})
-		if err != nil {
-			if errors.GetType(err) != errors.NotFound {
-				reporter.Errorf(fmt.Sprintf("Failed to watch logs for cluster '%s': %v", clusterKey, err))
-				os.Exit(1)
+		case err != nil:
+			return fmt.Errorf("Failed to watch logs for cluster '%s': %w", clusterKey, err)
+		case state == cmv1.ClusterStateError:
+			return &rprtr.EventError{
+				Code:   rprtr.CodeClusterInstallFail,
+				Fields: rprtr.Fields{"cluster": cluster.ID()},
+				Err:    fmt.Errorf("There was an error installing cluster '%s'", clusterKey),
 			}
+		default:
+			reporter.Progressf(rprtr.CodeClusterReady, rprtr.Fields{"cluster": cluster.ID()},
+				"Cluster '%s' is now ready after %s", clusterKey, progress.Elapsed())
 		}
-		printLog(response, spin)
 	}
+
+	return nil
 }
 
 var lastLine string
 
-// Print next log lines
-func printLog(logs *cmv1.Log, spin *spinner.Spinner) {
+// Print next log lines, above the progress if it is given, and return them
+func printLog(logs *cmv1.Log, progress *rprtr.Progress) string {
 	lines := findNextLines(logs)
-	if lines != "" {
+	if lines == "" {
+		return lines
+	}
+	if progress != nil {
+		progress.Printf("%s\n", lines)
+	} else {
 		fmt.Printf("%s\n", lines)
-		if spin != nil {
-			spin.Stop()
-		}
-	} else if spin != nil {
-		spin.Restart()
 	}
+	return lines
 }
 
 // Remove duplicate lines from the log poll response
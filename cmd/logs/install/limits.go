@@ -0,0 +1,83 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package install
+
+import (
+	"time"
+
+	"github.com/openshift/moactl/pkg/aws"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+)
+
+// Time between checks of the usage of the AWS limits while watching the installation, as listing
+// the service quotas is slow and throttled:
+const limitCheckInterval = 5 * time.Minute
+
+// limitWatcher warns about the AWS limits that the installation is about to exceed or has
+// exceeded, once for each limit, so that users know which quota to raise.
+type limitWatcher struct {
+	reporter  *rprtr.Object
+	client    aws.Client
+	lastCheck time.Time
+	warned    map[string]bool
+}
+
+func newLimitWatcher(reporter *rprtr.Object, client aws.Client) *limitWatcher {
+	return &limitWatcher{
+		reporter: reporter,
+		client:   client,
+		warned:   map[string]bool{},
+	}
+}
+
+// checkUsage warns about the limits whose usage is close to the quota. It does nothing if the
+// usage was checked recently.
+func (w *limitWatcher) checkUsage() {
+	if time.Since(w.lastCheck) < limitCheckInterval {
+		return
+	}
+	w.lastCheck = time.Now()
+
+	usages, err := w.client.GetLimitUsage()
+	if err != nil {
+		w.reporter.Debugf("Failed to check usage of AWS limits: %v", err)
+		return
+	}
+	for _, usage := range usages {
+		if !usage.Nearing() || w.warned[usage.QuotaCode] {
+			continue
+		}
+		w.warned[usage.QuotaCode] = true
+		w.reporter.Warnf("AWS account is nearing the limit of '%s': %d of %d used. If the installation "+
+			"fails, raise service %s quota code %s", usage.QuotaName, int(usage.Used), int(usage.Value),
+			usage.ServiceCode, usage.QuotaCode)
+	}
+}
+
+// checkLogs warns about the limits that the given log lines say were exceeded.
+func (w *limitWatcher) checkLogs(lines string) {
+	for _, limitError := range aws.FindLimitErrors(lines) {
+		if w.warned[limitError.Code] {
+			continue
+		}
+		w.warned[limitError.Code] = true
+		if limitError.QuotaCode != "" {
+			w.warned[limitError.QuotaCode] = true
+		}
+		w.reporter.Warnf("%v", limitError)
+	}
+}
@@ -18,26 +18,21 @@ package uninstall
 
 import (
 	"fmt"
-	"os"
 	"strings"
-	"time"
 
-	"github.com/briandowns/spinner"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 	errors "github.com/zgalor/weberr"
 
-	"github.com/openshift/moactl/pkg/aws"
 	clusterprovider "github.com/openshift/moactl/pkg/cluster"
-	"github.com/openshift/moactl/pkg/logging"
-	"github.com/openshift/moactl/pkg/ocm"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
 var args struct {
-	clusterKey string
-	tail       int
-	watch      bool
+	tail  int
+	watch bool
 }
 
 var Cmd = &cobra.Command{
@@ -49,19 +44,13 @@ var Cmd = &cobra.Command{
 
   # Show uninstall logs for a cluster using the --cluster flag
   rosa logs uninstall --cluster=mycluster`,
-	Run: run,
+	RunE: run,
 }
 
 func init() {
 	flags := Cmd.Flags()
 
-	flags.StringVarP(
-		&args.clusterKey,
-		"cluster",
-		"c",
-		"",
-		"Name or ID of the cluster to get logs for.",
-	)
+	clusterprovider.UseKey(Cmd)
 
 	flags.IntVar(
 		&args.tail,
@@ -79,131 +68,85 @@ func init() {
 	)
 }
 
-func run(cmd *cobra.Command, argv []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
 
 	// Determine whether the user wants to watch logs streaming.
 	// We check the flag value this way to allow other commands to watch logs
 	watch := cmd.Flags().Lookup("watch").Value.String() == "true"
 
-	// Check command line arguments:
-	clusterKey := args.clusterKey
-	if clusterKey == "" {
-		if len(argv) != 1 {
-			reporter.Errorf(
-				"Expected exactly one command line argument or flag containing the name " +
-					"or identifier of the cluster",
-			)
-			os.Exit(1)
-		}
-		clusterKey = argv[0]
-	}
-
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
-	if !clusterprovider.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
-	}
-
-	// Create the AWS client:
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
-	}
-
-	awsCreator, err := awsClient.GetCreator()
-	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+	if args.tail <= 0 {
+		return rosaerrors.Usagef("Option '--tail' must be a positive number of lines")
 	}
 
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
+	clusterKey, err := clusterprovider.GetKey(r, argv)
 	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		return err
 	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
-
-	// Get the client for the OCM collection of clusters:
-	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
 
 	// Try to find the cluster:
 	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := clusterprovider.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
 	if err != nil {
-		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
 	}
 
 	if cluster.State() != cmv1.ClusterStateUninstalling && !watch {
-		reporter.Warnf("Cluster '%s' is not currently uninstalling", clusterKey)
-		os.Exit(1)
+		return rosaerrors.Conflictf("Cluster '%s' is not currently uninstalling", clusterKey)
 	}
 
 	// Get logs from Hive
-	logs, err := ocm.GetUninstallLogs(clustersCollection, cluster.ID(), args.tail)
+	logs, err := r.OCM().GetUninstallLogs(cluster.ID(), args.tail)
 	if err != nil {
 		if errors.GetType(err) == errors.NotFound {
 			reporter.Warnf("Logs for cluster '%s' are not available", clusterKey)
 		} else {
-			reporter.Errorf("Failed to get logs for cluster '%s': %v", clusterKey, err)
-			os.Exit(1)
+			return fmt.Errorf("Failed to get logs for cluster '%s': %w", clusterKey, err)
 		}
 	}
 	printLog(logs, nil)
 
 	if watch {
-		spin := spinner.New(spinner.CharSets[9], 100*time.Millisecond)
-		spin.Start()
+		progress := reporter.StartProgress("Cluster '%s' is %s", clusterKey, cluster.State())
 
 		// Poll for changing logs:
-		response, err := ocm.PollUninstallLogs(clustersCollection, cluster.ID(), func(logResponse *cmv1.LogGetResponse) bool {
-			state, err := ocm.GetClusterState(clustersCollection, cluster.ID())
+		response, err := r.OCM().PollUninstallLogs(cluster.ID(), func(logResponse *cmv1.LogGetResponse) bool {
+			state, err := r.OCM().GetClusterState(cluster.ID())
 			if err != nil || state == cmv1.ClusterState("") {
 				return true
 			}
-			printLog(logResponse.Body(), spin)
+			progress.Phasef("Cluster '%s' is %s", clusterKey, state)
+			printLog(logResponse.Body(), progress)
 			return false
 		})
 		if err != nil {
 			if errors.GetType(err) != errors.NotFound {
-				reporter.Errorf(fmt.Sprintf("Failed to watch logs for cluster '%s': %v", clusterKey, err))
-				os.Exit(1)
+				progress.Stop()
+				return fmt.Errorf("Failed to watch logs for cluster '%s': %w", clusterKey, err)
 			}
 		}
-		printLog(response, spin)
+		printLog(response, progress)
+		progress.Stop()
+		reporter.Progressf(rprtr.CodeClusterUninstalled, rprtr.Fields{"cluster": cluster.ID()},
+			"Cluster '%s' has been uninstalled after %s", clusterKey, progress.Elapsed())
 	}
+
+	return nil
 }
 
 var lastLine string
 
-// Print next log lines
-func printLog(logs *cmv1.Log, spin *spinner.Spinner) {
+// Print next log lines, above the progress if it is given
+func printLog(logs *cmv1.Log, progress *rprtr.Progress) {
 	lines := findNextLines(logs)
-	if lines != "" {
+	if lines == "" {
+		return
+	}
+	if progress != nil {
+		progress.Printf("%s\n", lines)
+	} else {
 		fmt.Printf("%s\n", lines)
-		if spin != nil {
-			spin.Stop()
-		}
-	} else if spin != nil {
-		spin.Restart()
 	}
 }
 
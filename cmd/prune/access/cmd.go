@@ -0,0 +1,142 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package access
+
+import (
+	"fmt"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var args struct {
+	dryRun bool
+}
+
+var Cmd = &cobra.Command{
+	Use:   "access",
+	Short: "Revoke expired temporary access",
+	Long: "Revoke the roles granted with 'rosa grant user --duration' that have expired. It doesn't " +
+		"ask for confirmation, so it can be run periodically, for example from a cron job.",
+	Example: `  # Revoke expired roles in all clusters
+  rosa prune access
+
+  # Show the expired roles in a cluster named "mycluster" without revoking them
+  rosa prune access --cluster=mycluster --dry-run`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	clusterprovider.UseKey(Cmd)
+
+	flags.BoolVar(
+		&args.dryRun,
+		"dry-run",
+		false,
+		"Report the expired roles without revoking them.",
+	)
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	// The default cluster isn't used, all the clusters are checked unless one is given explicitly:
+	clusterKey := clusterprovider.Key()
+	var clusters []*cmv1.Cluster
+	if clusterKey != "" {
+		// Check that the cluster key (name, identifier or external identifier) given by the user
+		// is reasonably safe so that there is no risk of SQL injection:
+		if !ocm.IsValidClusterKey(clusterKey) {
+			return fmt.Errorf(
+				"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+					"must contain only letters, digits, dashes and underscores",
+				clusterKey,
+			)
+		}
+		reporter.Debugf("Loading cluster '%s'", clusterKey)
+		cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
+		if err != nil {
+			return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+		}
+		clusters = []*cmv1.Cluster{cluster}
+	} else {
+		reporter.Debugf("Loading clusters")
+		var err error
+		clusters, err = r.OCM().GetClusters(r.Creator().ARN, 100)
+		if err != nil {
+			return fmt.Errorf("Failed to get clusters: %w", err)
+		}
+	}
+
+	now := time.Now()
+	failed := false
+	pruned := 0
+	for _, cluster := range clusters {
+		revoked := []*ocm.AccessExpiration{}
+		for _, expiration := range ocm.GetAccessExpirations(cluster) {
+			if !expiration.Expired(now) {
+				continue
+			}
+			if args.dryRun {
+				reporter.Infof("Role '%s' of user '%s' on cluster '%s' expired at %s",
+					expiration.Group, expiration.Username, cluster.Name(),
+					expiration.Time.Format(time.RFC3339))
+				pruned++
+				continue
+			}
+
+			reporter.Debugf("Removing user '%s' from group '%s' in cluster '%s'",
+				expiration.Username, expiration.Group, cluster.Name())
+			// Users that were already removed only need the expiration to be cleaned up, and
+			// DeleteGroupUser succeeds for them:
+			err := r.OCM().DeleteGroupUser(cluster.ID(), expiration.Group, expiration.Username)
+			if err != nil {
+				reporter.Errorf("Failed to revoke '%s' from user '%s' in cluster '%s': %v",
+					expiration.Group, expiration.Username, cluster.Name(), err)
+				failed = true
+				continue
+			}
+			reporter.Infof("Revoked expired role '%s' from user '%s' on cluster '%s'",
+				expiration.Group, expiration.Username, cluster.Name())
+			revoked = append(revoked, expiration)
+			pruned++
+		}
+
+		err := r.OCM().RemoveAccessExpirations(cluster, revoked)
+		if err != nil {
+			reporter.Errorf("Failed to remove expired roles from cluster '%s': %v", cluster.Name(), err)
+			failed = true
+		}
+	}
+
+	if pruned == 0 && !failed {
+		reporter.Infof("There are no expired roles")
+	}
+	if failed {
+		return fmt.Errorf("Some of the expired roles couldn't be pruned")
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/dryrun"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var args struct {
+	watch bool
+}
+
+var Cmd = &cobra.Command{
+	Use:   "cluster [ID|NAME]",
+	Short: "Resume a hibernating cluster",
+	Long:  "Resume a cluster hibernated with 'rosa hibernate cluster', starting its nodes again.",
+	Example: `  # Resume a cluster named "mycluster"
+  rosa resume cluster mycluster
+
+  # Resume a cluster and wait until it is ready
+  rosa resume cluster --cluster=mycluster --watch
+
+  # Resume the clusters named "dev-1" and "dev-2"
+  rosa resume cluster --cluster=dev-1,dev-2`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	clusterprovider.UseBatch(Cmd)
+
+	flags.BoolVar(
+		&args.watch,
+		"watch",
+		false,
+		"Wait until the cluster is ready, reporting the changes of its state.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	clusterKey, err := clusterprovider.GetKey(r, argv)
+	if err != nil {
+		return err
+	}
+
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	err = ocm.ValidateResume(cluster.State())
+	if err != nil {
+		return rosaerrors.Conflictf("Cluster '%s' can't be resumed: %v", clusterKey, err)
+	}
+
+	reporter.Debugf("Resuming cluster '%s'", clusterKey)
+	err = r.OCM().ResumeCluster(cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to resume cluster '%s': %w", clusterKey, err)
+	}
+	if dryrun.Enabled() {
+		return nil
+	}
+	reporter.Infof("Cluster '%s' is resuming. To check its state run 'rosa describe cluster -c %s'",
+		clusterKey, clusterKey)
+
+	if args.watch {
+		return clusterprovider.WatchState(r, cluster, cmv1.ClusterStateReady)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package install
+
+import (
+	"fmt"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	logsInstall "github.com/openshift/moactl/cmd/logs/install"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/confirm"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var args struct {
+	watch bool
+}
+
+var Cmd = &cobra.Command{
+	Use:   "install [ID|NAME]",
+	Short: "Retry the installation of a cluster",
+	Long: "Provision again a cluster whose installation failed, after fixing the problems of the " +
+		"AWS account. The cluster keeps its identifier and configuration, unlike deleting and creating " +
+		"it again.",
+	Example: `  # Retry the installation of a cluster named "mycluster"
+  rosa retry install --cluster=mycluster
+
+  # Retry the installation and watch the logs
+  rosa retry install mycluster --watch`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	clusterprovider.UseKey(Cmd)
+
+	flags.BoolVar(
+		&args.watch,
+		"watch",
+		false,
+		"Watch cluster installation logs.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	clusterKey, err := clusterprovider.GetKey(r, argv)
+	if err != nil {
+		return err
+	}
+
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	if cluster.State() != cmv1.ClusterStateError {
+		return rosaerrors.Conflictf("Cluster '%s' is in state '%s', only the installation of clusters in state '%s' "+
+			"can be retried", clusterKey, cluster.State(), cmv1.ClusterStateError)
+	}
+
+	// Remind the user of the cause, as retrying won't help if it hasn't been fixed:
+	remediation := clusterprovider.TriageProvisionError(cluster)
+	if remediation != nil {
+		reporter.Infof("The installation of cluster '%s' failed: %s. To check that it has been fixed run '%s'",
+			clusterKey, remediation.Cause, remediation.Command)
+	}
+
+	if !confirm.Confirm("retry the installation of cluster %s", clusterKey) {
+		return nil
+	}
+
+	reporter.Debugf("Retrying installation of cluster '%s'", clusterKey)
+	err = r.OCM().RetryInstall(cluster.ID())
+	if err == ocm.ErrRetryNotSupported {
+		return rosaerrors.Conflictf("%w. Delete the cluster with 'rosa delete cluster %s' and create it again",
+			err, clusterKey)
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to retry installation of cluster '%s': %w", clusterKey, err)
+	}
+	reporter.Infof("Retrying installation of cluster '%s'. To follow it run 'rosa logs install -c %s --watch'",
+		clusterKey, clusterKey)
+
+	if args.watch {
+		return logsInstall.Cmd.RunE(cmd, []string{cluster.ID()})
+	}
+
+	return nil
+}
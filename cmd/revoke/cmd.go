@@ -20,7 +20,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/cmd/revoke/user"
-	"github.com/openshift/moactl/pkg/confirm"
+	"github.com/openshift/moactl/pkg/ocm/roles"
 )
 
 var Cmd = &cobra.Command{
@@ -30,8 +30,7 @@ var Cmd = &cobra.Command{
 }
 
 func init() {
-	flags := Cmd.PersistentFlags()
-	confirm.AddFlag(flags)
+	roles.Require(Cmd, roles.Update)
 
 	Cmd.AddCommand(user.Cmd)
 }
@@ -18,20 +18,17 @@ package user
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/moactl/pkg/aws"
-	"github.com/openshift/moactl/pkg/confirm"
-	"github.com/openshift/moactl/pkg/logging"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/ocm"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
 var args struct {
-	clusterKey string
-	username   string
+	username string
 }
 
 var Cmd = &cobra.Command{
@@ -43,24 +40,14 @@ var Cmd = &cobra.Command{
   rosa revoke user cluster-admins --user=myusername --cluster=mycluster
 
   # Revoke dedicated-admin role from a user
-  rosa revoke user dedicate-admins --user=myusername --cluster=mycluster`,
-	Run: run,
+  rosa revoke user dedicated-admins --user=myusername --cluster=mycluster`,
+	RunE: run,
 }
 
-var validRoles = []string{"cluster-admins", "dedicated-admins"}
-var validRolesAliases = []string{"cluster-admin", "dedicated-admin"}
-
 func init() {
 	flags := Cmd.Flags()
 
-	flags.StringVarP(
-		&args.clusterKey,
-		"cluster",
-		"c",
-		"",
-		"Name or ID of the cluster to delete the users from (required).",
-	)
-	Cmd.MarkFlagRequired("cluster")
+	clusterprovider.UseKey(Cmd)
 
 	flags.StringVarP(
 		&args.username,
@@ -72,120 +59,69 @@ func init() {
 	Cmd.MarkFlagRequired("user")
 }
 
-func run(_ *cobra.Command, argv []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
-
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
-	clusterKey := args.clusterKey
-	if !ocm.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	clusterKey, err := clusterprovider.GetKey(r, nil)
+	if err != nil {
+		return err
 	}
 
 	username := args.username
 	if !ocm.IsValidUsername(username) {
-		reporter.Errorf(
+		return rosaerrors.Usagef(
 			"username '%s' isn't valid: it must contain only letters, digits, dashes and underscores",
 			username,
 		)
-		os.Exit(1)
 	}
 
 	if len(argv) != 1 {
-		reporter.Errorf(
+		return fmt.Errorf(
 			"Expected exactly one command line argument or flag containing the name " +
 				"of the group or role to grant the user.",
 		)
-		os.Exit(1)
-	}
-	role := argv[0]
-	// Allow role aliases
-	for _, validAlias := range validRolesAliases {
-		if role == validAlias {
-			role = fmt.Sprintf("%ss", role)
-		}
-	}
-	isRoleValid := false
-	// Determine if role is valid
-	for _, validRole := range validRoles {
-		if role == validRole {
-			isRoleValid = true
-		}
-	}
-	if !isRoleValid {
-		reporter.Errorf("Expected at least one of %s", validRoles)
-	}
-
-	// Create the AWS client:
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
-	}
-
-	awsCreator, err := awsClient.GetCreator()
-	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
 	}
-
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
+	role, err := ocm.GroupForRole(argv[0])
 	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		return err
 	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
-
-	// Get the client for the OCM collection of clusters:
-	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
 
 	// Try to find the cluster:
 	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
 	if err != nil {
-		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
 	}
 
 	// Try to find the user:
 	reporter.Debugf("Loading '%s' users for cluster '%s'", role, clusterKey)
-	user, err := ocm.GetUser(clustersCollection, cluster.ID(), role, username)
+	user, err := r.OCM().GetUser(cluster.ID(), role, username)
 	if err != nil {
-		reporter.Errorf(err.Error())
-		os.Exit(1)
+		return err
 	}
 
 	if user == nil {
 		reporter.Warnf("Cannot find user '%s' with role '%s' on cluster '%s'", username, role, clusterKey)
-		os.Exit(0)
+		return nil
 	}
 
-	if !confirm.Confirm("revoke role %s from user %s in cluster %s", role, username, clusterKey) {
-		os.Exit(0)
+	if !clusterprovider.ConfirmDestructive(r, cluster, "revoke role %s from user %s in cluster %s", role, username, clusterKey) {
+		return nil
 	}
 
 	reporter.Debugf("Removing user '%s' from group '%s' in cluster '%s'", username, role, clusterKey)
-	res, err := clustersCollection.Cluster(cluster.ID()).Groups().Group(role).Users().User(username).Delete().Send()
+	err = r.OCM().DeleteGroupUser(cluster.ID(), role, username)
 	if err != nil {
-		reporter.Debugf(err.Error())
-		reporter.Errorf("Failed to revoke '%s' from user '%s' in cluster '%s': %s",
-			role, username, clusterKey, res.Error().Reason())
-		os.Exit(1)
+		return fmt.Errorf("Failed to revoke '%s' from user '%s' in cluster '%s': %w",
+			role, username, clusterKey, err)
 	}
+
+	err = r.OCM().RemoveAccessExpiration(cluster, role, username)
+	if err != nil {
+		return fmt.Errorf("Failed to remove expiration of role '%s' for user '%s': %w", role, username, err)
+	}
+	reporter.Infof("Revoked role '%s' from user '%s' in cluster '%s'", role, username, clusterKey)
+
+	return nil
 }
@@ -0,0 +1,173 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"regexp"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
+	"github.com/openshift/moactl/pkg/fleet"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// runForClusters runs the command for each of the clusters selected by the command line, as a
+// separate process, several of them at the same time, and prints a table with the result of each
+// cluster.
+func runForClusters(r *runtime.Runtime, cmd *cobra.Command, argv []string) {
+	reporter := r.Reporter()
+	if len(cmd.Flags().Args()) > 0 {
+		reporter.Errorf("Clusters can't be given as command line arguments when several are " +
+			"selected, use '--cluster' instead")
+		exit.Exit(rosaerrors.ExitUsage)
+	}
+
+	reporter.Debugf("Loading selected clusters")
+	clusters, err := cluster.GetBatchClusters(r.OCM(), r.Creator().ARN)
+	if err != nil {
+		reporter.Errorf("Failed to get clusters: %v", err)
+		exit.Exit(rosaerrors.ExitCode(err))
+	}
+	if len(clusters) == 0 {
+		reporter.Warnf("There are no clusters matching the selection")
+		return
+	}
+
+	command := strings.TrimPrefix(cmd.CommandPath(), root.Name()+" ")
+	names := make([]string, len(clusters))
+	for i, item := range clusters {
+		names[i] = item.Name()
+	}
+	reporter.Infof("Selected %d clusters: %s", len(clusters), strings.Join(names, ", "))
+	if !cluster.ConfirmDestructiveBatch(r, clusters, "run '%s' on %d clusters", command, len(clusters)) {
+		return
+	}
+
+	// The operation has already been confirmed for all the clusters, and the processes of the
+	// clusters can't ask as they don't share the terminal:
+	args := withoutFlags(argv,
+		[]string{"--" + cluster.KeyFlag, "-c", "--" + cluster.FilterFlag, "--" + cluster.MaxParallelFlag},
+		[]string{"--" + cluster.AllFlag})
+	args = append(args, "--yes")
+
+	state, err := fleet.LoadState("")
+	if err != nil {
+		reporter.Errorf("%v", err)
+		exit.Exit(1)
+	}
+	err = fleet.Run(clusters, cluster.MaxParallel(), state, func(item *cmv1.Cluster) (fleet.Status, string) {
+		reporter.Infof("Running for cluster '%s'", item.Name())
+		output, code, err := runSelf(withCluster(args, item.ID()), nil, true)
+		if err != nil {
+			return fleet.StatusFailed, err.Error()
+		}
+		reporter.Debugf("Output for cluster '%s':\n%s", item.Name(), output)
+		status, message := parseOutput(output)
+		if code != 0 {
+			return fleet.StatusFailed, message
+		}
+		return status, message
+	})
+	if err != nil {
+		reporter.Errorf("%v", err)
+	}
+
+	results := state.Results()
+	fleet.PrintResults(os.Stdout, results)
+	if fleet.Failed(results) {
+		reporter.Errorf("The command failed for some clusters")
+		r.Cleanup()
+		exit.Exit(1)
+	}
+}
+
+// withCluster returns a copy of the command line arguments that selects the given cluster. The
+// arguments are shared by the clusters that run at the same time, so they are never appended to
+// directly.
+func withCluster(argv []string, clusterID string) []string {
+	result := make([]string, 0, len(argv)+2)
+	result = append(result, argv...)
+	return append(result, "--"+cluster.KeyFlag, clusterID)
+}
+
+// batchSelected checks if the given flags of a command line select several clusters.
+func batchSelected(flags *pflag.FlagSet) bool {
+	all, _ := flags.GetBool(cluster.AllFlag)
+	filter, _ := flags.GetString(cluster.FilterFlag)
+	key, _ := flags.GetString(cluster.KeyFlag)
+	return all || filter != "" || cluster.IsBatchKey(key)
+}
+
+// colorRE matches the ANSI escape sequences that set the colors of the messages.
+var colorRE = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// parseOutput returns the status and the message of the last line of the output of the command
+// for a cluster. Commands that finish with a warning, like when there is nothing to do, are
+// considered skipped.
+func parseOutput(output []byte) (fleet.Status, string) {
+	lines := strings.Split(string(bytes.TrimSpace(output)), "\n")
+	message := strings.TrimSpace(colorRE.ReplaceAllString(lines[len(lines)-1], ""))
+	for _, prefix := range []string{"W: ", "WARN: "} {
+		if strings.HasPrefix(message, prefix) {
+			return fleet.StatusSkipped, strings.TrimPrefix(message, prefix)
+		}
+	}
+	for _, prefix := range []string{"I: ", "E: ", "INFO: ", "ERR: "} {
+		message = strings.TrimPrefix(message, prefix)
+	}
+	return fleet.StatusSucceeded, message
+}
+
+// withoutFlags returns the command line arguments without the given flags. The value flags are
+// removed together with their values, that can be in the same argument, after '=', or in the next
+// one.
+func withoutFlags(argv []string, valueFlags []string, boolFlags []string) []string {
+	result := []string{}
+	for i := 0; i < len(argv); i++ {
+		arg := argv[i]
+		removed := false
+		for _, flag := range valueFlags {
+			if arg == flag {
+				// The value is in the next argument:
+				i++
+				removed = true
+				break
+			}
+			if strings.HasPrefix(arg, flag+"=") {
+				removed = true
+				break
+			}
+		}
+		for _, flag := range boolFlags {
+			if arg == flag || strings.HasPrefix(arg, flag+"=") {
+				removed = true
+			}
+		}
+		if !removed {
+			result = append(result, arg)
+		}
+	}
+	return result
+}
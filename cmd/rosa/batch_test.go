@@ -0,0 +1,100 @@
+package main
+
+import (
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/cluster"
+)
+
+var _ = Describe("Selection of several clusters", func() {
+	// selects checks if the command line selects several clusters for a command that supports it:
+	selects := func(argv []string) bool {
+		cmd, flagArgs, err := root.Find(argv)
+		Expect(err).ToNot(HaveOccurred())
+		flags, err := parseCopy(cmd, flagArgs)
+		Expect(err).ToNot(HaveOccurred())
+		return cluster.UsesBatch(cmd) && batchSelected(flags)
+	}
+
+	It("Is detected from the flags of the command", func() {
+		selections := map[string][]string{
+			"list of clusters":  {"delete", "cluster", "--cluster", "a,b"},
+			"pattern":           {"delete", "cluster", "-c", "dev-*"},
+			"all the clusters":  {"delete", "cluster", "--all"},
+			"search expression": {"hibernate", "cluster", "--filter", "region.id = 'us-east-1'"},
+		}
+		for name, argv := range selections {
+			Expect(selects(argv)).To(BeTrue(), "Expected %s to be a selection", name)
+		}
+	})
+
+	It("Isn't detected in the values of other flags", func() {
+		singles := map[string][]string{
+			"one cluster":        {"delete", "cluster", "--cluster", "mycluster"},
+			"all disabled":       {"delete", "cluster", "--all=false", "-c", "mycluster"},
+			"list of log levels": {"delete", "cluster", "-c", "mycluster", "--v", "aws=debug,ocm=info"},
+			"unsupported":        {"describe", "cluster", "--cluster", "a,b"},
+		}
+		for name, argv := range singles {
+			Expect(selects(argv)).To(BeFalse(), "Expected %s not to be a selection", name)
+		}
+	})
+
+	It("Doesn't change the flags of the command", func() {
+		Expect(selects([]string{"delete", "cluster", "--cluster", "a,b", "--all"})).To(BeTrue())
+		cmd, _, err := root.Find([]string{"delete", "cluster"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cluster.Key()).To(BeEmpty())
+		Expect(cmd.Flags().Changed(cluster.AllFlag)).To(BeFalse())
+	})
+})
+
+var _ = Describe("Arguments of the clusters of a batch", func() {
+	It("Select a different cluster for each process", func() {
+		// Spare capacity would make the appends of the clusters share the same array:
+		args := make([]string, 0, 10)
+		args = append(args, "delete", "cluster", "--yes")
+		ids := []string{"id1", "id2", "id3", "id4", "id5", "id6", "id7", "id8"}
+		results := make([][]string, len(ids))
+		var wg sync.WaitGroup
+		for i, id := range ids {
+			wg.Add(1)
+			go func(i int, id string) {
+				defer wg.Done()
+				results[i] = withCluster(args, id)
+			}(i, id)
+		}
+		wg.Wait()
+		for i, id := range ids {
+			Expect(results[i]).To(Equal([]string{"delete", "cluster", "--yes", "--cluster", id}))
+		}
+		Expect(args).To(Equal([]string{"delete", "cluster", "--yes"}))
+	})
+
+	It("Don't contain the flags that select the clusters", func() {
+		valueFlags := []string{"--cluster", "-c", "--filter"}
+		boolFlags := []string{"--all"}
+		Expect(withoutFlags(
+			[]string{"delete", "cluster", "--cluster", "a,b", "--yes"}, valueFlags, boolFlags,
+		)).To(Equal([]string{"delete", "cluster", "--yes"}))
+		Expect(withoutFlags(
+			[]string{"delete", "cluster", "--cluster=a,b", "-c", "dev-*", "--all"}, valueFlags, boolFlags,
+		)).To(Equal([]string{"delete", "cluster"}))
+		Expect(withoutFlags(
+			[]string{"delete", "cluster", "-c=dev-*", "--all=true", "--filter", "state = 'ready'"},
+			valueFlags, boolFlags,
+		)).To(Equal([]string{"delete", "cluster"}))
+	})
+
+	It("Keep the arguments that only start like the flags", func() {
+		valueFlags := []string{"--cluster", "-c"}
+		boolFlags := []string{"--all"}
+		Expect(withoutFlags(
+			[]string{"grant", "user", "-c", "dev-*", "--user", "-cluster-admin", "--clusters", "--allow"},
+			valueFlags, boolFlags,
+		)).To(Equal([]string{"grant", "user", "--user", "-cluster-admin", "--clusters", "--allow"}))
+	})
+})
@@ -0,0 +1,58 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/openshift/moactl/pkg/info"
+	"github.com/openshift/moactl/pkg/output"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/simulate"
+)
+
+// uncheckedCommands are the commands that don't check the version of the tool, because they are
+// used to check or update it, or by the shell.
+var uncheckedCommands = map[string]bool{
+	"rosa":                  true,
+	"rosa completion":       true,
+	"rosa docs":             true,
+	"rosa download rosa":    true,
+	"rosa upgrade rosa":     true,
+	"rosa version":          true,
+	"rosa __complete":       true,
+	"rosa __completeNoDesc": true,
+}
+
+// checkVersion warns when the tool is too old to create clusters with the currently supported
+// versions of OpenShift, unless the check is disabled in the configuration file. It is skipped
+// when the output is meant for other programs, and in simulation mode.
+func checkVersion(argv []string) {
+	cmd, _, err := root.Find(argv)
+	if err != nil || uncheckedCommands[cmd.CommandPath()] {
+		return
+	}
+	if output.Structured() || !info.CheckEnabled() || simulate.Enabled() {
+		return
+	}
+	reporter := runtime.FromContext(root.Context()).Reporter()
+	warning, err := info.CheckVersion()
+	if err != nil {
+		reporter.Debugf("Failed to check the newest release: %v", err)
+	}
+	if warning != "" {
+		reporter.Warnf("%s", warning)
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/deprecation"
+	"github.com/openshift/moactl/pkg/info"
+)
+
+var _ = Describe("Deprecated flags", func() {
+	It("Are removed in the version announced to the users", func() {
+		// The deprecated flags are registered when the packages of the commands are imported:
+		Expect(deprecation.Flags()).ToNot(BeEmpty())
+		for _, flag := range deprecation.Flags() {
+			comparison, err := info.CompareVersions(info.Version, flag.RemovedIn)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(comparison).To(
+				BeNumerically("<", 0),
+				"Flag --%s of command '%s' should have been removed in version %s",
+				flag.Name, flag.Command.CommandPath(), flag.RemovedIn,
+			)
+		}
+	})
+})
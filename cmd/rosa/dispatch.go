@@ -0,0 +1,150 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the dispatcher that decides if the command selected by the command line runs
+// in this process or as separate processes of the tool: once for each cluster of a group or of a
+// batch, or between the hooks of the configuration file.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// dispatch resolves the command selected by the command line and inspects its flags. If the
+// command has to run as separate processes it runs them and returns true, otherwise it returns
+// false and the command runs in this process.
+func dispatch(r *runtime.Runtime, argv []string) bool {
+	cmd, flagArgs, err := root.Find(argv)
+	if err != nil || cmd == root {
+		return false
+	}
+	// The flags are inspected with a copy, as parsing the flags of the command again when it is
+	// executed would duplicate the values of list flags. Errors are ignored here, the command will
+	// report them when it is executed:
+	flags, err := parseCopy(cmd, flagArgs)
+	if err != nil {
+		return false
+	}
+	group, _ := flags.GetString(cluster.GroupFlag)
+	var run func()
+	switch {
+	case group != "" && cluster.UsesKey(cmd):
+		run = func() { runForClusterGroup(r, argv) }
+	case cluster.UsesBatch(cmd) && batchSelected(flags):
+		run = func() { runForClusters(r, cmd, argv) }
+	default:
+		hooks := findHooks(r, cmd)
+		if len(hooks) == 0 {
+			return false
+		}
+		run = func() { runWithHooks(r, cmd, argv, hooks) }
+	}
+	// The command doesn't run in this process, so its flags can now be parsed:
+	if cmd.ParseFlags(flagArgs) != nil {
+		return false
+	}
+	run()
+	return true
+}
+
+// runSelf runs the tool as a separate process with the given arguments, as commands keep their
+// flags in package variables, adding the given variables to its environment. The process shares the terminal,
+// unless its output is captured and returned. The result is the exit code of the process, and an
+// error if it couldn't be executed.
+func runSelf(argv []string, env []string, capture bool) ([]byte, int, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, 1, err
+	}
+	// #nosec G204
+	child := exec.Command(executable, argv...)
+	child.Env = append(os.Environ(), env...)
+	var output []byte
+	if capture {
+		output, err = child.CombinedOutput()
+	} else {
+		child.Stdin = os.Stdin
+		child.Stdout = os.Stdout
+		child.Stderr = os.Stderr
+		err = child.Run()
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		code := exitErr.ExitCode()
+		if code <= 0 {
+			code = 1
+		}
+		return output, code, nil
+	}
+	if err != nil {
+		return output, 1, err
+	}
+	return output, 0, nil
+}
+
+// parseCopy parses the given arguments with a copy of the flags of the command, that has their
+// names, shorthands and types but whose values are independent of the variables of the command.
+func parseCopy(cmd *cobra.Command, flagArgs []string) (*pflag.FlagSet, error) {
+	result := pflag.NewFlagSet(cmd.Name(), pflag.ContinueOnError)
+	result.SetOutput(ioutil.Discard)
+	add := func(flag *pflag.Flag) {
+		if result.Lookup(flag.Name) != nil {
+			return
+		}
+		result.AddFlag(&pflag.Flag{
+			Name:        flag.Name,
+			Shorthand:   flag.Shorthand,
+			Usage:       flag.Usage,
+			NoOptDefVal: flag.NoOptDefVal,
+			Value:       &flagCopy{kind: flag.Value.Type(), value: flag.DefValue},
+		})
+	}
+	cmd.Flags().VisitAll(add)
+	cmd.InheritedFlags().VisitAll(add)
+	err := result.Parse(flagArgs)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// flagCopy is the value of a copied flag. It keeps the last value given in the command line, which
+// is enough to inspect the flags that select clusters.
+type flagCopy struct {
+	kind  string
+	value string
+}
+
+func (f *flagCopy) String() string {
+	return f.value
+}
+
+func (f *flagCopy) Set(value string) error {
+	f.value = value
+	return nil
+}
+
+func (f *flagCopy) Type() string {
+	return f.kind
+}
@@ -0,0 +1,75 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// setupErrors makes cobra return the errors of the command line instead of printing them, so that
+// they are reported like the rest and exit with the usage exit code.
+func setupErrors() {
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+	root.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return rosaerrors.Wrap(rosaerrors.ExitUsage, err)
+	})
+	wrapArgs(root)
+}
+
+// wrapArgs makes the errors of the validators of the positional arguments of the given command and
+// its subcommands usage errors.
+func wrapArgs(cmd *cobra.Command) {
+	if validate := cmd.Args; validate != nil {
+		cmd.Args = func(cmd *cobra.Command, argv []string) error {
+			return rosaerrors.Wrap(rosaerrors.ExitUsage, validate(cmd, argv))
+		}
+	}
+	for _, child := range cmd.Commands() {
+		wrapArgs(child)
+	}
+}
+
+// reportError reports the error returned by the command line, if any, and returns the exit code
+// for it.
+func reportError(r *runtime.Runtime, argv []string, err error) int {
+	if err == nil {
+		return 0
+	}
+	code := rosaerrors.ExitCode(err)
+	cmd, _, findErr := root.Find(argv)
+	if findErr != nil {
+		// Unknown commands are also usage errors:
+		code = rosaerrors.ExitUsage
+	}
+	var event *rprtr.EventError
+	if errors.As(err, &event) {
+		r.Reporter().ErrorEventf(event.Code, event.Fields, "%v", err)
+	} else {
+		r.Reporter().Errorf("%v", err)
+	}
+	if code == rosaerrors.ExitUsage && cmd != nil {
+		r.Reporter().Infof("Run '%s --help' for usage", cmd.CommandPath())
+	}
+	return code
+}
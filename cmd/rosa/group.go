@@ -0,0 +1,75 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"strings"
+
+	"github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// runForClusterGroup runs the command once for each cluster of the group selected by the command
+// line, as a separate process.
+func runForClusterGroup(r *runtime.Runtime, argv []string) {
+	reporter := r.Reporter()
+	if cluster.Key() != "" {
+		reporter.Errorf("At most one of '--cluster' or '--%s' may be specified", cluster.GroupFlag)
+		exit.Exit(rosaerrors.ExitUsage)
+	}
+
+	search, err := cluster.GetGroupSearch(cluster.Group())
+	if err != nil {
+		reporter.Errorf("%v", err)
+		exit.Exit(rosaerrors.ExitCode(err))
+	}
+	reporter.Debugf("Loading clusters of group '%s'", cluster.Group())
+	clusters, err := r.OCM().SearchClusters(r.Creator().ARN, search)
+	if err != nil {
+		reporter.Errorf("Failed to get clusters of group '%s': %v", cluster.Group(), err)
+		exit.Exit(rosaerrors.ExitCode(err))
+	}
+	if len(clusters) == 0 {
+		reporter.Warnf("There are no clusters in group '%s'", cluster.Group())
+		return
+	}
+
+	args := withoutGroupFlag(argv)
+	failed := []string{}
+	for _, item := range clusters {
+		reporter.Infof("Running for cluster '%s'", item.Name())
+		_, code, err := runSelf(withCluster(args, item.ID()), nil, false)
+		if err != nil {
+			reporter.Errorf("Failed to run command for cluster '%s': %v", item.Name(), err)
+		}
+		if err != nil || code != 0 {
+			failed = append(failed, item.Name())
+		}
+	}
+	if len(failed) > 0 {
+		reporter.Errorf("The command failed for clusters %s", strings.Join(failed, ", "))
+		r.Cleanup()
+		exit.Exit(1)
+	}
+}
+
+// withoutGroupFlag returns the command line arguments without the flag that selects the group.
+func withoutGroupFlag(argv []string) []string {
+	return withoutFlags(argv, []string{"--" + cluster.GroupFlag}, nil)
+}
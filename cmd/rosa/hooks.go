@@ -0,0 +1,139 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/config"
+	"github.com/openshift/moactl/pkg/exit"
+	rosaruntime "github.com/openshift/moactl/pkg/runtime"
+)
+
+// hooksDisabledEnv is the environment variable that disables the hooks. It is set for the process
+// that runs the command, so that the hooks don't run twice, and it can also be set by users.
+const hooksDisabledEnv = "ROSA_HOOKS_DISABLED"
+
+// findHooks returns the hooks of the configuration file for the given command. There are none
+// when the hooks are disabled.
+func findHooks(r *rosaruntime.Runtime, cmd *cobra.Command) []*config.Hook {
+	if os.Getenv(hooksDisabledEnv) == "true" {
+		return nil
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		r.Reporter().Errorf("Failed to load config file: %v", err)
+		exit.Exit(1)
+	}
+	command := strings.TrimPrefix(cmd.CommandPath(), root.Name()+" ")
+	hooks := []*config.Hook{}
+	for _, hook := range cfg.Hooks {
+		if command == hook.Command || strings.HasPrefix(command, hook.Command+" ") {
+			hooks = append(hooks, hook)
+		}
+	}
+	return hooks
+}
+
+// runWithHooks runs the 'pre' hooks, then the command as a separate process, and then the 'post'
+// hooks. The hooks get the name of the command, the cluster and the result of the command in
+// environment variables. If a 'pre' hook fails the command isn't executed.
+func runWithHooks(r *rosaruntime.Runtime, cmd *cobra.Command, argv []string, hooks []*config.Hook) {
+	reporter := r.Reporter()
+	command := strings.TrimPrefix(cmd.CommandPath(), root.Name()+" ")
+	cluster := ""
+	if clusterprovider.UsesKey(cmd) {
+		cluster = clusterprovider.Key()
+		if cluster == "" && len(cmd.Flags().Args()) > 0 {
+			cluster = cmd.Flags().Args()[0]
+		}
+		if cluster == "" {
+			cluster, _ = clusterprovider.GetDefaultKey()
+		}
+	}
+	env := append(os.Environ(),
+		"ROSA_COMMAND="+command,
+		"ROSA_CLUSTER="+cluster,
+	)
+
+	for _, hook := range hooks {
+		if hook.Pre == "" {
+			continue
+		}
+		reporter.Debugf("Running pre hook of command '%s': %s", hook.Command, hook.Pre)
+		err := runHook(hook.Pre, env)
+		if err != nil {
+			reporter.Errorf("Hook '%s' failed, command '%s' won't be executed: %v", hook.Pre, command, err)
+			r.Cleanup()
+			exit.Exit(1)
+		}
+	}
+
+	// Interrupting the command shouldn't prevent the 'post' hooks from running, so the signal is
+	// only delivered to the process of the command:
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	_, exitCode, err := runSelf(argv, []string{hooksDisabledEnv + "=true"}, false)
+	signal.Stop(signals)
+	if err != nil {
+		reporter.Errorf("Failed to run command '%s': %v", command, err)
+	}
+
+	env = append(env,
+		"ROSA_EXIT_CODE="+strconv.Itoa(exitCode),
+		"ROSA_SUCCESS="+strconv.FormatBool(exitCode == 0),
+	)
+	for _, hook := range hooks {
+		if hook.Post == "" {
+			continue
+		}
+		reporter.Debugf("Running post hook of command '%s': %s", hook.Command, hook.Post)
+		err := runHook(hook.Post, env)
+		if err != nil {
+			reporter.Warnf("Hook '%s' failed: %v", hook.Post, err)
+		}
+	}
+
+	if exitCode != 0 {
+		r.Cleanup()
+		exit.Exit(exitCode)
+	}
+}
+
+// runHook runs the given hook with the shell of the operating system.
+func runHook(hook string, env []string) error {
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	// #nosec G204
+	child := exec.Command(shell, flag, hook)
+	child.Env = env
+	child.Stdin = os.Stdin
+	child.Stdout = os.Stdout
+	child.Stderr = os.Stderr
+	return child.Run()
+}
@@ -17,39 +17,68 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
-	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
+	"github.com/openshift/moactl/cmd/cache"
+	"github.com/openshift/moactl/cmd/clone"
 	"github.com/openshift/moactl/cmd/completion"
+	"github.com/openshift/moactl/cmd/config"
 	"github.com/openshift/moactl/cmd/create"
 	"github.com/openshift/moactl/cmd/describe"
 	"github.com/openshift/moactl/cmd/dlt"
 	"github.com/openshift/moactl/cmd/docs"
 	"github.com/openshift/moactl/cmd/download"
 	"github.com/openshift/moactl/cmd/edit"
+	"github.com/openshift/moactl/cmd/env"
+	"github.com/openshift/moactl/cmd/fleet"
 	"github.com/openshift/moactl/cmd/grant"
+	"github.com/openshift/moactl/cmd/hibernate"
+	"github.com/openshift/moactl/cmd/imprt"
 	"github.com/openshift/moactl/cmd/initialize"
+	"github.com/openshift/moactl/cmd/install"
 	"github.com/openshift/moactl/cmd/list"
 	"github.com/openshift/moactl/cmd/login"
 	"github.com/openshift/moactl/cmd/logout"
 	"github.com/openshift/moactl/cmd/logs"
+	"github.com/openshift/moactl/cmd/prune"
+	"github.com/openshift/moactl/cmd/resume"
+	"github.com/openshift/moactl/cmd/retry"
 	"github.com/openshift/moactl/cmd/revoke"
+	"github.com/openshift/moactl/cmd/rotate"
+	"github.com/openshift/moactl/cmd/search"
+	"github.com/openshift/moactl/cmd/shell"
+	"github.com/openshift/moactl/cmd/tools"
+	"github.com/openshift/moactl/cmd/uninstall"
 	"github.com/openshift/moactl/cmd/upgrade"
 	"github.com/openshift/moactl/cmd/verify"
 	"github.com/openshift/moactl/cmd/version"
 	"github.com/openshift/moactl/cmd/whoami"
 
 	"github.com/openshift/moactl/pkg/arguments"
+	"github.com/openshift/moactl/pkg/ci"
+	"github.com/openshift/moactl/pkg/cluster"
+	rosaconfig "github.com/openshift/moactl/pkg/config"
+	"github.com/openshift/moactl/pkg/confirm"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
+	ocmconfig "github.com/openshift/moactl/pkg/ocm/config"
+	"github.com/openshift/moactl/pkg/ocm/regions"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/simulate"
+	"github.com/openshift/moactl/pkg/tracing"
+	"github.com/openshift/moactl/pkg/transport"
 )
 
 var root = &cobra.Command{
 	Use:   "rosa",
 	Short: "Command line tool for ROSA.",
 	Long:  "Command line tool for Red Hat OpenShift Service on AWS.",
+	RunE:  runRoot,
 }
 
 func init() {
@@ -60,35 +89,135 @@ func init() {
 	// Add the command line flags:
 	fs := root.PersistentFlags()
 	arguments.AddDebugFlag(fs)
+	arguments.AddLogLevelsFlag(fs)
+	arguments.AddLogFormatFlag(fs)
 	arguments.AddProfileFlag(fs)
+	arguments.AddAssumeRoleFlags(fs)
+	arguments.AddOCMConfigFlag(fs)
+	arguments.AddEnvFlag(fs)
+	arguments.AddRetryFlags(fs)
+	arguments.AddRefreshFlag(fs)
+	arguments.AddCIFlags(fs)
+	arguments.AddCAFileFlag(fs)
+	arguments.AddSimulateFlag(fs)
+	confirm.AddFlag(fs)
+	cluster.AddKeyFlag(fs)
+	cluster.AddGroupFlag(fs)
+
+	// Nothing that the commands do in simulation mode is real, so users are reminded of it:
+	cobra.OnInitialize(func() {
+		if simulate.Enabled() {
+			runtime.FromContext(root.Context()).Reporter().Warnf("Simulation mode: the " +
+				"responses of OCM and AWS are canned and nothing is changed")
+		}
+	})
+
+	// The configuration file of rosa is checked once the profile has been selected:
+	cobra.OnInitialize(func() {
+		err := rosaconfig.Validate()
+		if err != nil {
+			r := runtime.FromContext(root.Context())
+			r.Reporter().Errorf("Failed to load configuration: %v", err)
+			r.Cleanup()
+			exit.Exit(1)
+		}
+	})
+
+	// The additional certificate authorities may come from the profile, so they are loaded once
+	// the configuration file has been checked:
+	cobra.OnInitialize(func() {
+		err := transport.Setup()
+		if err != nil {
+			r := runtime.FromContext(root.Context())
+			r.Reporter().Errorf("%v", err)
+			r.Cleanup()
+			exit.Exit(rosaerrors.ExitUsage)
+		}
+	})
+
+	// Start recording the result and the trace once the flags have been parsed:
+	cobra.OnInitialize(func() {
+		ci.Start(os.Args[1:])
+		tracing.Start(os.Args[1:])
+	})
+
+	// Commands that the OCM account isn't allowed to run fail before they start, and aren't
+	// offered in the help:
+	cobra.OnInitialize(func() {
+		checkRoles(os.Args[1:])
+	})
+	hideDeniedCommands()
+
+	// Tools that are too old are detected at most once a day:
+	cobra.OnInitialize(func() {
+		checkVersion(os.Args[1:])
+	})
 
 	// Register the subcommands:
+	root.AddCommand(cache.Cmd)
+	root.AddCommand(clone.Cmd)
 	root.AddCommand(completion.Cmd)
+	root.AddCommand(config.Cmd)
 	root.AddCommand(create.Cmd)
 	root.AddCommand(describe.Cmd)
 	root.AddCommand(dlt.Cmd)
 	root.AddCommand(docs.Cmd)
 	root.AddCommand(download.Cmd)
 	root.AddCommand(edit.Cmd)
+	root.AddCommand(env.Cmd)
+	root.AddCommand(fleet.Cmd)
 	root.AddCommand(grant.Cmd)
+	root.AddCommand(hibernate.Cmd)
+	root.AddCommand(imprt.Cmd)
+	root.AddCommand(install.Cmd)
 	root.AddCommand(list.Cmd)
 	root.AddCommand(initialize.Cmd)
 	root.AddCommand(login.Cmd)
 	root.AddCommand(logout.Cmd)
 	root.AddCommand(logs.Cmd)
+	root.AddCommand(prune.Cmd)
+	root.AddCommand(resume.Cmd)
+	root.AddCommand(retry.Cmd)
 	root.AddCommand(revoke.Cmd)
+	root.AddCommand(rotate.Cmd)
+	root.AddCommand(search.Cmd)
+	root.AddCommand(shell.Cmd)
+	root.AddCommand(tools.Cmd)
+	root.AddCommand(uninstall.Cmd)
 	root.AddCommand(upgrade.Cmd)
 	root.AddCommand(verify.Cmd)
 	root.AddCommand(version.Cmd)
 	root.AddCommand(whoami.Cmd)
+
+	// Complete the values of the flags that select clusters and regions in the shell:
+	_ = root.RegisterFlagCompletionFunc(cluster.KeyFlag, cluster.CompleteKey)
+	regions.RegisterCompletion(root)
+
+	// Errors are reported by main, with the exit code of their category:
+	setupErrors()
 }
 
 func main() {
+	// The configuration file and the simulation mode are needed before the command line is
+	// parsed:
+	ocmconfig.ParseFlag(os.Args[1:])
+	simulate.ParseFlag(os.Args[1:])
+
+	// Create the runtime that is shared by all the commands:
+	r := runtime.New()
+
+	// Commands run as separate processes for each cluster of a group or of a batch, or between the
+	// hooks of the configuration file:
+	if dispatch(r, os.Args[1:]) {
+		r.Cleanup()
+		return
+	}
+
 	// Execute the root command:
 	root.SetArgs(os.Args[1:])
-	err := root.Execute()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to execute root command: %s\n", err)
-		os.Exit(1)
-	}
+	err := root.ExecuteContext(runtime.NewContext(context.Background(), r))
+	// Reporting the error also records it in the result and in the trace:
+	code := reportError(r, os.Args[1:], err)
+	r.Cleanup()
+	exit.Exit(code)
 }
@@ -0,0 +1,147 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/ocm/config"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// runRoot runs when no command is given. The first time that the tool is used, when there is no
+// configuration file yet, it guides the user through the setup in interactive terminals. Otherwise
+// it prints the help.
+func runRoot(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("Failed to load config file: %w", err)
+	}
+	if len(argv) > 0 || cfg != nil || !interactive.IsTerminal() {
+		return cmd.Help()
+	}
+	return onboard(r)
+}
+
+// onboard checks the login to the Red Hat account, offering to log in with a device code, then the
+// AWS credentials, permissions and quotas, and suggests the next command to run.
+func onboard(r *runtime.Runtime) error {
+	reporter := r.Reporter()
+
+	reporter.Infof("Welcome to rosa! Let's check that everything is ready to create your first " +
+		"cluster.")
+
+	// The login runs as a separate process so that its output and failures are those of 'rosa login':
+	loggedIn := false
+	login, err := interactive.GetBool(interactive.Input{
+		Question: "Log in to your Red Hat account now, approving a code in your browser",
+		Help:     "You can also log in later with 'rosa login', using a token.",
+		Default:  true,
+	})
+	if err != nil {
+		return fmt.Errorf("Expected a valid answer: %w", err)
+	}
+	if login {
+		executable, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("Failed to find executable: %w", err)
+		}
+		// #nosec G204
+		child := exec.Command(executable, "login", "--use-device-code")
+		child.Stdin = os.Stdin
+		child.Stdout = os.Stdout
+		child.Stderr = os.Stderr
+		loggedIn = child.Run() == nil
+	}
+
+	// Errors are reported as warnings so that all the checks run, and the suggested command is the
+	// one that fixes the first problem:
+	next := ""
+	if !loggedIn {
+		next = "rosa login"
+	}
+
+	reporter.Infof("Checking AWS credentials...")
+	region, err := aws.GetRegion("")
+	if err != nil {
+		region = aws.DefaultRegion
+	}
+	client, err := aws.NewClient().
+		Logger(r.Logger()).
+		Region(region).
+		Build()
+	if err == nil {
+		_, err = client.ValidateCredentials()
+	}
+	if err != nil {
+		reporter.Warnf("No valid AWS credentials found: %v", err)
+		reporter.Warnf("Configure them with 'aws configure', or select a profile with '--profile'")
+		if next == "" {
+			next = "aws configure"
+		}
+		suggest(r, next)
+		return nil
+	}
+	creator, err := client.GetCreator()
+	if err == nil {
+		reporter.Infof("Using AWS account '%s' as '%s' in region '%s'",
+			creator.AccountID, creator.ARN, region)
+	}
+
+	reporter.Infof("Checking AWS permissions...")
+	missing, err := client.VerifyPermissions()
+	switch {
+	case err != nil:
+		reporter.Warnf("Failed to verify AWS permissions: %v", err)
+	case len(missing) > 0:
+		reporter.Warnf("The AWS credentials are missing %d permissions, run 'rosa verify "+
+			"permissions' for details", len(missing))
+	}
+
+	reporter.Infof("Checking AWS quotas in region '%s'...", region)
+	checksByRegion, err := aws.CheckQuotasByRegion(r.Logger(), []string{region})
+	switch {
+	case err != nil:
+		reporter.Warnf("Failed to verify AWS quotas: %v", err)
+	case len(aws.InsufficientQuotas(checksByRegion[region])) > 0:
+		reporter.Warnf("Some AWS quotas in region '%s' are insufficient, run 'rosa verify quota' "+
+			"for details", region)
+	}
+
+	ready, _, err := client.CheckStackReadyOrNotExisting(aws.OsdCcsAdminStackName)
+	if next == "" && (err != nil || !ready) {
+		next = "rosa init"
+	}
+	if next == "" {
+		next = "rosa create cluster --interactive"
+	}
+	suggest(r, next)
+	return nil
+}
+
+// suggest tells the user which command to run next.
+func suggest(r *runtime.Runtime, next string) {
+	r.Reporter().Infof("Next, run '%s'. Run 'rosa --help' to see all the commands.", next)
+}
@@ -0,0 +1,80 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/dryrun"
+	"github.com/openshift/moactl/pkg/exit"
+	"github.com/openshift/moactl/pkg/ocm/config"
+	"github.com/openshift/moactl/pkg/ocm/roles"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// getAccess checks the actions that the OCM account is allowed to perform. It returns nil if the
+// user isn't logged in or if the check fails, as in that case the commands will report the errors
+// themselves.
+func getAccess(r *runtime.Runtime) *roles.Access {
+	cfg, err := config.Load()
+	if err != nil || cfg == nil {
+		return nil
+	}
+	access, err := r.OCM().GetAccess()
+	if err != nil {
+		r.Reporter().Debugf("Failed to check the roles of the account: %v", err)
+		return nil
+	}
+	return access
+}
+
+// checkRoles exits with a specific exit code and explains which role is needed when the OCM
+// account isn't allowed to run the command selected by the command line.
+func checkRoles(argv []string) {
+	if dryrun.Enabled() {
+		return
+	}
+	cmd, _, err := root.Find(argv)
+	if err != nil {
+		return
+	}
+	requirement := roles.Required(cmd)
+	if requirement == nil {
+		return
+	}
+	r := runtime.FromContext(root.Context())
+	access := getAccess(r)
+	if access == nil || !access.Denies(requirement) {
+		return
+	}
+	r.Reporter().Errorf("%s", roles.DeniedMessage(cmd, requirement))
+	r.Cleanup()
+	exit.Exit(roles.ExitCode)
+}
+
+// hideDeniedCommands wraps the help of the root command so that the commands that the OCM account
+// isn't allowed to run aren't offered.
+func hideDeniedCommands() {
+	help := root.HelpFunc()
+	root.SetHelpFunc(func(cmd *cobra.Command, argv []string) {
+		access := getAccess(runtime.FromContext(cmd.Context()))
+		if access != nil {
+			access.Hide(root)
+		}
+		help(cmd, argv)
+	})
+}
@@ -0,0 +1,127 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/confirm"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var args struct {
+	temporaryCredentials time.Duration
+}
+
+var Cmd = &cobra.Command{
+	Use:   "credentials [ID|NAME]",
+	Short: "Rotate the AWS credentials of a cluster",
+	Long: fmt.Sprintf("Replace the AWS credentials that OCM keeps for a cluster with a new access "+
+		"key of the '%s' user, or with new temporary credentials. The previous access keys of "+
+		"the user are deleted. Clusters that use AWS STS don't have credentials to rotate.",
+		aws.AdminUserName),
+	Example: `  # Rotate the AWS credentials of a cluster named "mycluster"
+  rosa rotate credentials --cluster=mycluster
+
+  # Replace the AWS credentials of a cluster with temporary credentials that expire in 4 hours
+  rosa rotate credentials --cluster=mycluster --temporary-credentials=4h`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	clusterprovider.UseKey(Cmd)
+
+	flags.DurationVar(
+		&args.temporaryCredentials,
+		"temporary-credentials",
+		0,
+		"Replace the credentials with temporary credentials that expire after the given time, "+
+			"like '4h', instead of a new access key.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	if args.temporaryCredentials != 0 && (args.temporaryCredentials < aws.MinTemporaryCredentialsDuration ||
+		args.temporaryCredentials > aws.MaxTemporaryCredentialsDuration) {
+		return rosaerrors.Usagef("Duration of temporary credentials must be between %s and %s",
+			aws.MinTemporaryCredentialsDuration, aws.MaxTemporaryCredentialsDuration)
+	}
+
+	clusterKey, err := clusterprovider.GetKey(r, argv)
+	if err != nil {
+		return err
+	}
+
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	sts, err := clusterprovider.GetSTS(r.OCM(), cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get STS details of cluster '%s': %w", clusterKey, err)
+	}
+	if sts != nil {
+		return rosaerrors.Conflictf("Cluster '%s' uses AWS STS, it doesn't have credentials to rotate", clusterKey)
+	}
+
+	if !confirm.Confirm("rotate the AWS credentials of cluster %s", clusterKey) {
+		return nil
+	}
+
+	var accessKey *aws.AccessKey
+	if args.temporaryCredentials != 0 {
+		reporter.Debugf("Getting temporary credentials for cluster '%s'", clusterKey)
+		accessKey, err = r.AWSClient().GetTemporaryAccessKeys(args.temporaryCredentials)
+		if err != nil {
+			return err
+		}
+	} else {
+		reporter.Debugf("Replacing access key of user '%s'", aws.AdminUserName)
+		accessKey, err = r.AWSClient().GetAWSAccessKeys()
+		if err != nil {
+			return fmt.Errorf("Failed to get access keys for user '%s': %w\n"+
+				"Run 'rosa init' and try again", aws.AdminUserName, err)
+		}
+	}
+
+	reporter.Debugf("Updating AWS credentials of cluster '%s'", clusterKey)
+	err = clusterprovider.UpdateCredentials(r.OCM(), cluster.ID(), accessKey)
+	if err != nil {
+		return fmt.Errorf("Failed to update AWS credentials of cluster '%s': %w", clusterKey, err)
+	}
+	if accessKey.SessionToken != "" && !accessKey.Expiration.IsZero() {
+		reporter.Infof("Rotated AWS credentials of cluster '%s', they expire at %s", clusterKey,
+			accessKey.Expiration.Local().Format("Jan _2 2006 15:04:05 MST"))
+		return nil
+	}
+	reporter.Infof("Rotated AWS credentials of cluster '%s'", clusterKey)
+
+	return nil
+}
@@ -0,0 +1,116 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package search
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/output"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/search"
+	"github.com/openshift/moactl/pkg/table"
+)
+
+var args struct {
+	concurrency int
+}
+
+var Cmd = &cobra.Command{
+	Use:   "search TEXT",
+	Short: "Search clusters and their resources",
+	Long: "Search for a text, ignoring case, in the names, identifiers and other fields of your " +
+		"clusters and of their machine pools, identity providers and upgrade policies, so that you " +
+		"don't need to remember which list command shows a resource.",
+	Example: `  # Find everything related to payments
+  rosa search payments
+
+  # Find the machine pools and clusters that use an instance type
+  rosa search m5.2xlarge`,
+	Args: cobra.ExactArgs(1),
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	output.AddFlag(flags)
+
+	flags.IntVar(
+		&args.concurrency,
+		"concurrency",
+		5,
+		"Number of clusters whose resources are loaded at the same time.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	err := output.Validate()
+	if err != nil {
+		return err
+	}
+
+	text := strings.TrimSpace(argv[0])
+	if text == "" {
+		return rosaerrors.Usagef("Expected a non empty text to search for")
+	}
+	if args.concurrency < 1 {
+		return rosaerrors.Usagef("Option '--concurrency' must be a positive number")
+	}
+
+	reporter.Debugf("Loading clusters")
+	clusters, err := r.OCM().GetClusters(r.Creator().ARN, 100)
+	if err != nil {
+		return fmt.Errorf("Failed to get clusters: %w", err)
+	}
+
+	reporter.Debugf("Searching '%s' in %d clusters", text, len(clusters))
+	matches, errs := search.Search(r.OCM(), clusters, text, args.concurrency)
+	for _, err := range errs {
+		reporter.Warnf("%v", err)
+	}
+
+	if output.Structured() {
+		err = output.PrintValue(matches)
+		if err != nil {
+			return fmt.Errorf("Failed to print matches: %w", err)
+		}
+		return nil
+	}
+
+	if len(matches) == 0 {
+		reporter.Infof("Nothing matches '%s'", text)
+		return nil
+	}
+
+	// Create the writer that will be used to print the tabulated results:
+	writer := table.NewWriter(os.Stdout)
+	fmt.Fprintf(writer, "TYPE\tCLUSTER\tID\tNAME\tMATCH\n")
+	for _, match := range matches {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s: %s\n",
+			match.Type, match.Cluster, match.ID, match.Name, match.Field, match.Value)
+	}
+	writer.Flush()
+
+	return nil
+}
@@ -0,0 +1,172 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shell
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+const prompt = "rosa> "
+
+var Cmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Run commands interactively",
+	Long: "Start an interactive shell that runs the commands of the tool without the 'rosa' prefix.\n\n" +
+		"The commands are kept in a history that is saved across sessions. Use 'history' to list it, " +
+		"'!!' to repeat the last command and '!n' to repeat the command number 'n' of the history. " +
+		"Use 'exit' or Ctrl-D to leave the shell.",
+	Example: `  # Start the shell and list the clusters
+  rosa shell
+  rosa> list clusters`,
+	Args: cobra.NoArgs,
+	RunE: run,
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("Failed to find executable: %w", err)
+	}
+
+	history, err := loadHistory()
+	if err != nil {
+		reporter.Warnf("Failed to load command history: %v", err)
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Print(prompt)
+		if !scanner.Scan() {
+			fmt.Println()
+			break
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		expanded, err := history.expand(line)
+		if err != nil {
+			reporter.Errorf("%v", err)
+			continue
+		}
+		if expanded != line {
+			fmt.Println(expanded)
+			line = expanded
+		}
+
+		switch line {
+		case "exit", "quit":
+			return nil
+		case "history":
+			history.print()
+			continue
+		}
+
+		err = history.add(line)
+		if err != nil {
+			reporter.Warnf("Failed to save command history: %v", err)
+		}
+
+		argv, err := splitLine(line)
+		if err != nil {
+			reporter.Errorf("%v", err)
+			continue
+		}
+		if argv[0] == "rosa" {
+			argv = argv[1:]
+		}
+		if len(argv) > 0 && argv[0] == cmd.Name() {
+			reporter.Errorf("Already running in the shell")
+			continue
+		}
+
+		// Commands keep their flags in package variables, so each one runs as a separate process:
+		// #nosec G204
+		child := exec.Command(executable, argv...)
+		child.Stdin = os.Stdin
+		child.Stdout = os.Stdout
+		child.Stderr = os.Stderr
+		err = child.Run()
+		if _, ok := err.(*exec.ExitError); err != nil && !ok {
+			reporter.Errorf("Failed to run command '%s': %v", line, err)
+		}
+	}
+	err = scanner.Err()
+	if err != nil {
+		return fmt.Errorf("Failed to read command: %w", err)
+	}
+
+	return nil
+}
+
+// splitLine splits the given command line into arguments, separated by spaces. Quotes and
+// backslashes can be used to include spaces in the arguments, like in the shells of the operating
+// system.
+func splitLine(line string) ([]string, error) {
+	argv := []string{}
+	var current strings.Builder
+	inArg := false
+	var quote rune
+	escaped := false
+	for _, c := range line {
+		switch {
+		case escaped:
+			current.WriteRune(c)
+			escaped = false
+		case c == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				current.WriteRune(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inArg = true
+		case c == ' ' || c == '\t':
+			if inArg {
+				argv = append(argv, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(c)
+			inArg = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("Unterminated quote or escape in command '%s'", line)
+	}
+	if inArg {
+		argv = append(argv, current.String())
+	}
+	return argv, nil
+}
@@ -0,0 +1,126 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shell
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// maxHistory is the number of commands kept in the history file.
+const maxHistory = 1000
+
+// history contains the commands executed in the shell, oldest first, and the file where they are
+// saved so that they are available in the next sessions.
+type history struct {
+	path     string
+	commands []string
+}
+
+// historyPath returns the location of the history file, inside the cache directory of the user.
+func historyPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "rosa", "history"), nil
+}
+
+// loadHistory loads the commands saved by previous sessions. The returned history is usable even
+// if there is an error, but then it isn't saved.
+func loadHistory() (*history, error) {
+	h := &history{}
+	path, err := historyPath()
+	if err != nil {
+		return h, err
+	}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		h.path = path
+		return h, nil
+	}
+	if err != nil {
+		return h, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if scanner.Text() != "" {
+			h.commands = append(h.commands, scanner.Text())
+		}
+	}
+	err = scanner.Err()
+	if err != nil {
+		return h, err
+	}
+	h.path = path
+	return h, nil
+}
+
+// add appends the given command to the history and saves it, dropping the oldest commands when
+// there are more than the maximum.
+func (h *history) add(command string) error {
+	h.commands = append(h.commands, command)
+	if len(h.commands) > maxHistory {
+		h.commands = h.commands[len(h.commands)-maxHistory:]
+	}
+	if h.path == "" {
+		return nil
+	}
+	err := os.MkdirAll(filepath.Dir(h.path), 0700)
+	if err != nil {
+		return err
+	}
+	data := strings.Join(h.commands, "\n") + "\n"
+	return ioutil.WriteFile(h.path, []byte(data), 0600)
+}
+
+// expand replaces a leading '!!' with the last command of the history, and a leading '!n' with the
+// command number 'n', keeping the rest of the line. Other lines are returned unchanged.
+func (h *history) expand(line string) (string, error) {
+	if !strings.HasPrefix(line, "!") {
+		return line, nil
+	}
+	event := line
+	rest := ""
+	if i := strings.IndexAny(line, " \t"); i >= 0 {
+		event, rest = line[:i], line[i:]
+	}
+	if event == "!!" {
+		if len(h.commands) == 0 {
+			return "", fmt.Errorf("History is empty")
+		}
+		return h.commands[len(h.commands)-1] + rest, nil
+	}
+	n, err := strconv.Atoi(event[1:])
+	if err != nil || n < 1 || n > len(h.commands) {
+		return "", fmt.Errorf("Command '%s' not found in history", event)
+	}
+	return h.commands[n-1] + rest, nil
+}
+
+// print writes the numbered commands of the history to the standard output.
+func (h *history) print() {
+	for i, command := range h.commands {
+		fmt.Printf("%5d  %s\n", i+1, command)
+	}
+}
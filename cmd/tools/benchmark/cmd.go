@@ -0,0 +1,266 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptrace"
+	"os"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm/config"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/table"
+	"github.com/openshift/moactl/pkg/transport"
+)
+
+var args struct {
+	region  string
+	count   int
+	timeout time.Duration
+}
+
+var Cmd = &cobra.Command{
+	Use:   "benchmark",
+	Short: "Measure the latency of the OCM and AWS endpoints",
+	Long: "Measure the round trip latency of the requests to the OpenShift Cluster Manager, the " +
+		"Red Hat single sign-on service and the regional AWS endpoints, broken down into name " +
+		"resolution, connection, TLS handshake and first byte, to find out why commands are slow. " +
+		"No credentials are needed, the responses of the endpoints are ignored.",
+	Example: `  # Measure the latency of the endpoints used by the tool
+  rosa tools benchmark
+
+  # Measure the latency of the endpoints of a different region with more requests
+  rosa tools benchmark --region=us-west-2 --count=10`,
+	Args: cobra.NoArgs,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.region,
+		"region",
+		"r",
+		"",
+		"AWS region of the endpoints to measure (overrides the AWS_REGION environment variable).",
+	)
+	flags.IntVar(
+		&args.count,
+		"count",
+		3,
+		"Number of requests sent to each endpoint.",
+	)
+	flags.DurationVar(
+		&args.timeout,
+		"timeout",
+		10*time.Second,
+		"Maximum time to wait for each request.",
+	)
+}
+
+// endpoint is a destination whose latency is measured.
+type endpoint struct {
+	category string
+	url      string
+}
+
+// sample contains the durations of the phases of a single request.
+type sample struct {
+	dns       time.Duration
+	connect   time.Duration
+	tls       time.Duration
+	firstByte time.Duration
+	total     time.Duration
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	if args.count <= 0 {
+		return rosaerrors.Usagef("Option '--count' must be greater than zero")
+	}
+
+	// Get AWS region
+	region, err := aws.GetRegion(args.region)
+	if err != nil {
+		return fmt.Errorf("Error getting region: %w", err)
+	}
+
+	// Measure the OCM environment that the user is logged in to, if any:
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("Failed to load config file: %w", err)
+	}
+	apiURL := sdk.DefaultURL
+	tokenURL := sdk.DefaultTokenURL
+	if cfg != nil {
+		if cfg.URL != "" {
+			apiURL = cfg.URL
+		}
+		if cfg.TokenURL != "" {
+			tokenURL = cfg.TokenURL
+		}
+	}
+	endpoints := []endpoint{
+		{"OCM", apiURL + "/api/clusters_mgmt/v1"},
+		{"SSO", tokenURL},
+	}
+	for _, service := range []string{"sts", "ec2", "elasticloadbalancing", "servicequotas"} {
+		host := fmt.Sprintf("%s.%s.%s", service, region, aws.DNSSuffix(region))
+		endpoints = append(endpoints, endpoint{"AWS", "https://" + host})
+	}
+
+	// The requests are sent one after the other, so that they don't compete for the bandwidth and
+	// the measures only reflect the latency of the network and the endpoints:
+	reporter.Infof("Sending %d requests to each of %d endpoints...", args.count, len(endpoints))
+	writer := table.NewWriter(os.Stdout).Numeric("FAILED")
+	fmt.Fprintf(writer, "CATEGORY\tENDPOINT\tDNS\tCONNECT\tTLS\tFIRST BYTE\tTOTAL\tMIN\tMAX\tFAILED\n")
+	unreachable := 0
+	for _, e := range endpoints {
+		samples := []sample{}
+		failed := 0
+		for i := 0; i < args.count; i++ {
+			reporter.Debugf("Sending request %d to '%s'", i+1, e.url)
+			s, err := measure(e.url)
+			if err != nil {
+				reporter.Debugf("Request to '%s' failed: %v", e.url, err)
+				failed++
+				continue
+			}
+			samples = append(samples, s)
+		}
+		if len(samples) == 0 {
+			unreachable++
+			fmt.Fprintf(writer, "%s\t%s\t-\t-\t-\t-\t-\t-\t-\t%d\n", e.category, e.url, failed)
+			continue
+		}
+		avg, min, max := summarize(samples)
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\n",
+			e.category, e.url,
+			milliseconds(avg.dns), milliseconds(avg.connect), milliseconds(avg.tls),
+			milliseconds(avg.firstByte), milliseconds(avg.total),
+			milliseconds(min), milliseconds(max), failed,
+		)
+	}
+	writer.Flush()
+
+	if unreachable > 0 {
+		return rosaerrors.Transientf(
+			"%d of %d endpoints can't be reached. Run 'rosa verify network' to check the connectivity.",
+			unreachable, len(endpoints),
+		)
+	}
+
+	return nil
+}
+
+// measure sends a request to the given URL using a new connection, so that the name resolution,
+// the connection and the TLS handshake are part of the measure, and returns the duration of each
+// phase. Any response, even an error response, counts as a successful round trip.
+func measure(url string) (s sample, err error) {
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return
+	}
+	var dnsStart, connectStart, tlsStart time.Time
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			s.dns = time.Since(dnsStart)
+		},
+		ConnectStart: func(string, string) {
+			connectStart = time.Now()
+		},
+		ConnectDone: func(string, string, error) {
+			s.connect = time.Since(connectStart)
+		},
+		TLSHandshakeStart: func() {
+			tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			s.tls = time.Since(tlsStart)
+		},
+	}
+	start := time.Now()
+	trace.GotFirstResponseByte = func() {
+		s.firstByte = time.Since(start)
+	}
+	request = request.WithContext(httptrace.WithClientTrace(request.Context(), trace))
+	clientTransport, err := transport.New(false)
+	if err != nil {
+		return
+	}
+	clientTransport.DisableKeepAlives = true
+	client := &http.Client{
+		Timeout:   args.timeout,
+		Transport: clientTransport,
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+	_, err = io.Copy(ioutil.Discard, response.Body)
+	if err != nil {
+		return
+	}
+	s.total = time.Since(start)
+	return
+}
+
+// summarize returns the average of the phases of the given samples, and the minimum and maximum
+// total durations.
+func summarize(samples []sample) (avg sample, min time.Duration, max time.Duration) {
+	for i, s := range samples {
+		avg.dns += s.dns
+		avg.connect += s.connect
+		avg.tls += s.tls
+		avg.firstByte += s.firstByte
+		avg.total += s.total
+		if i == 0 || s.total < min {
+			min = s.total
+		}
+		if s.total > max {
+			max = s.total
+		}
+	}
+	n := time.Duration(len(samples))
+	avg.dns /= n
+	avg.connect /= n
+	avg.tls /= n
+	avg.firstByte /= n
+	avg.total /= n
+	return
+}
+
+func milliseconds(d time.Duration) string {
+	return fmt.Sprintf("%dms", d.Milliseconds())
+}
@@ -0,0 +1,135 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package splitcidr
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/network"
+	"github.com/openshift/moactl/pkg/table"
+)
+
+var args struct {
+	subnets     int
+	serviceCIDR net.IPNet
+	podCIDR     net.IPNet
+	hostPrefix  int
+}
+
+var Cmd = &cobra.Command{
+	Use:     "split-cidr CIDR",
+	Aliases: []string{"splitcidr"},
+	Short:   "Propose the blocks of IP addresses of a cluster",
+	Long: "Propose machine, service and pod blocks that don't overlap, and split the machine block " +
+		"into subnets of the same size. A multi-AZ cluster installed into an existing VPC needs a " +
+		"public and a private subnet in each of its three availability zones, that is six subnets.",
+	Example: `  # Propose the blocks of a multi-AZ cluster in an existing VPC
+  rosa tools split-cidr 10.0.0.0/16 --subnets 6
+
+  # Propose the subnets and the pod block for a given service block
+  rosa tools split-cidr 10.0.0.0/16 --subnets 2 --service-cidr 172.31.0.0/16`,
+	Args: cobra.ExactArgs(1),
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.IntVar(
+		&args.subnets,
+		"subnets",
+		2,
+		"Number of subnets to split the machine block into.",
+	)
+	flags.IPNetVar(
+		&args.serviceCIDR,
+		"service-cidr",
+		net.IPNet{},
+		"Block of IP addresses for services. If not specified, a block that doesn't overlap with the "+
+			"others is proposed.",
+	)
+	flags.IPNetVar(
+		&args.podCIDR,
+		"pod-cidr",
+		net.IPNet{},
+		"Block of IP addresses for pods. If not specified, a block that doesn't overlap with the "+
+			"others is proposed.",
+	)
+	flags.IntVar(
+		&args.hostPrefix,
+		"host-prefix",
+		network.DefaultHostPrefix,
+		"Subnet prefix length to assign to each individual node.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	_, machineCIDR, err := net.ParseCIDR(argv[0])
+	if err != nil {
+		return rosaerrors.Usagef("Expected a valid CIDR value: %w", err)
+	}
+
+	layout, err := network.ProposeLayout(
+		machineCIDR,
+		args.subnets,
+		optionalCIDR(cmd, "service-cidr", args.serviceCIDR),
+		optionalCIDR(cmd, "pod-cidr", args.podCIDR),
+		args.hostPrefix,
+	)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf(""+
+		"Machine CIDR: %s\n"+
+		"Service CIDR: %s\n"+
+		"Pod CIDR:     %s\n"+
+		"Host prefix:  %d (up to %d nodes)\n"+
+		"\n",
+		layout.MachineCIDR,
+		layout.ServiceCIDR,
+		layout.PodCIDR,
+		layout.HostPrefix,
+		network.MaxNodes(layout.PodCIDR, layout.HostPrefix),
+	)
+
+	writer := table.NewWriter(os.Stdout).Numeric("USABLE ADDRESSES")
+	fmt.Fprintf(writer, "SUBNET\tCIDR\tUSABLE ADDRESSES\n")
+	for i, subnet := range layout.Subnets {
+		fmt.Fprintf(writer, "%d\t%s\t%d\n", i+1, subnet, network.UsableAddresses(subnet))
+	}
+	writer.Flush()
+
+	fmt.Printf("\nTo use these blocks run:\n\n"+
+		"   rosa create cluster --machine-cidr %s --service-cidr %s --pod-cidr %s --host-prefix %d\n\n",
+		layout.MachineCIDR, layout.ServiceCIDR, layout.PodCIDR, layout.HostPrefix)
+
+	return nil
+}
+
+// optionalCIDR returns the value of the given flag, or nil if it wasn't used.
+func optionalCIDR(cmd *cobra.Command, name string, value net.IPNet) *net.IPNet {
+	if !cmd.Flags().Changed(name) {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,95 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/dryrun"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var Cmd = &cobra.Command{
+	Use:     "addon ID",
+	Aliases: []string{"addons", "add-on", "add-ons"},
+	Short:   "Uninstall an add-on from a cluster",
+	Long: "Uninstall a Red Hat managed add-on from a cluster, removing the resources that it " +
+		"created.",
+	Example: `  # Uninstall the CodeReady Workspaces add-on from a cluster named "mycluster"
+  rosa uninstall addon --cluster=mycluster codeready-workspaces`,
+	RunE: run,
+}
+
+func init() {
+	clusterprovider.UseKey(Cmd)
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	// Check command line arguments:
+	if len(argv) != 1 || argv[0] == "" {
+		return rosaerrors.Usagef("Expected exactly one command line parameter containing the identifier " +
+			"of the add-on")
+	}
+	addOnID := argv[0]
+
+	clusterKey, err := clusterprovider.GetKey(r, nil)
+	if err != nil {
+		return err
+	}
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	reporter.Debugf("Checking if add-on '%s' is installed on cluster '%s'", addOnID, clusterKey)
+	addOnInstallation, err := r.OCM().GetAddOnInstallation(cluster.ID(), addOnID)
+	if err != nil {
+		return fmt.Errorf("Failed to get add-on '%s' for cluster '%s': %w", addOnID, clusterKey, err)
+	}
+	if addOnInstallation == nil {
+		return rosaerrors.NotFoundf("Add-on '%s' isn't installed on cluster '%s'", addOnID, clusterKey)
+	}
+
+	if !clusterprovider.ConfirmDestructive(r, cluster, "uninstall add-on %s from cluster %s",
+		addOnID, clusterKey) {
+		return nil
+	}
+
+	reporter.Debugf("Uninstalling add-on '%s' from cluster '%s'", addOnID, clusterKey)
+	err = r.OCM().UninstallAddOn(cluster.ID(), addOnID)
+	if err != nil {
+		return fmt.Errorf("Failed to uninstall add-on '%s' from cluster '%s': %w",
+			addOnID, clusterKey, err)
+	}
+	if dryrun.Enabled() {
+		return nil
+	}
+	reporter.Infof("Add-on '%s' is now uninstalling. To check the status run 'rosa list addons -c %s'",
+		addOnID, clusterKey)
+
+	return nil
+}
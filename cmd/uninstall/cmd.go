@@ -0,0 +1,42 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uninstall
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/cmd/uninstall/addon"
+	"github.com/openshift/moactl/pkg/dryrun"
+	"github.com/openshift/moactl/pkg/ocm/roles"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "uninstall RESOURCE",
+	Short: "Uninstall a resource from a cluster",
+	Long:  "Uninstall a resource, like an add-on, from a cluster",
+	Example: `  # Uninstall the CodeReady Workspaces add-on from a cluster named 'mycluster'
+  rosa uninstall addon --cluster=mycluster codeready-workspaces`,
+}
+
+func init() {
+	roles.Require(Cmd, roles.Update)
+
+	flags := Cmd.PersistentFlags()
+	dryrun.AddFlag(flags)
+
+	Cmd.AddCommand(addon.Cmd)
+}
@@ -18,7 +18,6 @@ package cluster
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -26,18 +25,15 @@ import (
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/moactl/pkg/aws"
 	c "github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/interactive"
-	"github.com/openshift/moactl/pkg/logging"
-	"github.com/openshift/moactl/pkg/ocm"
 	"github.com/openshift/moactl/pkg/ocm/upgrades"
 	"github.com/openshift/moactl/pkg/ocm/versions"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
 var args struct {
-	clusterKey           string
 	version              string
 	scheduleDate         string
 	scheduleTime         string
@@ -52,22 +48,21 @@ var Cmd = &cobra.Command{
   rosa upgrade cluster --cluster=mycluster --interactive
 
   # Schedule a cluster upgrade within the hour
-  rosa upgade cluster -c mycluster --version 4.5.20`,
-	Run: run,
+  rosa upgrade cluster -c mycluster --version 4.5.20
+
+  # Schedule a cluster upgrade for a maintenance window, in UTC
+  rosa upgrade cluster -c mycluster --version 4.5.20 --schedule-date 2020-12-24 --schedule-time 02:00
+
+  # Schedule an upgrade on all the clusters whose name starts with "prod-"
+  rosa upgrade cluster --cluster="prod-*" --version 4.5.20`,
+	RunE: run,
 }
 
 func init() {
 	flags := Cmd.Flags()
 	flags.SortFlags = false
 
-	flags.StringVarP(
-		&args.clusterKey,
-		"cluster",
-		"c",
-		"",
-		"Name or ID of the cluster to schedule the upgrade for (required)",
-	)
-	Cmd.MarkFlagRequired("cluster")
+	c.UseBatch(Cmd)
 
 	flags.StringVar(
 		&args.version,
@@ -80,14 +75,16 @@ func init() {
 		&args.scheduleDate,
 		"schedule-date",
 		"",
-		"Next date the upgrade should run at the specified time. Format should be 'yyyy-mm-dd'",
+		"Next date the upgrade should run at the specified time. Format should be 'yyyy-mm-dd'. "+
+			"The default is the current date",
 	)
 
 	flags.StringVar(
 		&args.scheduleTime,
 		"schedule-time",
 		"",
-		"Next time the upgrade should run on the specified date. Format should be 'HH:mm'",
+		"Next UTC time the upgrade should run on the specified date. Format should be 'HH:mm'. "+
+			"The default is 10 minutes from now",
 	)
 
 	flags.StringVar(
@@ -100,94 +97,48 @@ func init() {
 	)
 }
 
-func run(cmd *cobra.Command, _ []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
-
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
-	clusterKey := args.clusterKey
-	if !c.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
-	}
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
 
-	// Create the AWS client:
-	var err error
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Build()
+	clusterKey, err := c.GetKey(r, nil)
 	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		return err
 	}
 
-	awsCreator, err := awsClient.GetCreator()
-	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
-	}
-
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
-	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
-
-	// Get the client for the OCM collection of clusters:
-	ocmClient := ocmConnection.ClustersMgmt().V1()
-
 	// Try to find the cluster:
 	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := ocm.GetCluster(ocmClient.Clusters(), clusterKey, awsCreator.ARN)
+	cluster, err := r.OCM().GetCluster(clusterKey, r.Creator().ARN)
 	if err != nil {
-		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
 	}
 
 	if cluster.State() != cmv1.ClusterStateReady {
-		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
-		os.Exit(1)
+		return rosaerrors.Conflictf("Cluster '%s' is not yet ready", clusterKey)
 	}
 
-	scheduledUpgrade, err := upgrades.GetScheduledUpgrade(ocmClient, cluster.ID())
+	scheduledUpgrade, err := r.OCM().GetScheduledUpgrade(cluster.ID())
 	if err != nil {
-		reporter.Errorf("Failed to get scheduled upgrades for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get scheduled upgrades for cluster '%s': %w", clusterKey, err)
 	}
 	if scheduledUpgrade != nil {
-		reporter.Warnf("There is already a scheduled upgrade to version %s on %s",
-			scheduledUpgrade.Version(),
-			scheduledUpgrade.NextRun().Format("2006-01-02 15:04 MST"),
-		)
-		os.Exit(0)
+		reporter.Warnf("There is already a scheduled upgrade to version %s, use 'rosa delete upgrade' "+
+			"to cancel it before scheduling a new one",
+			upgrades.FormatScheduledUpgrade(scheduledUpgrade, nil))
+		return nil
 	}
 
 	version := args.version
 	scheduleDate := args.scheduleDate
 	scheduleTime := args.scheduleTime
 
-	availableUpgrades, err := versions.GetAvailableUpgrades(ocmClient, versions.GetVersionID(cluster))
+	availableUpgrades, err := r.OCM().GetAvailableUpgrades(versions.GetVersionID(cluster))
 	if err != nil {
-		reporter.Errorf("Failed to find available upgrades: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to find available upgrades: %w", err)
 	}
 	if len(availableUpgrades) == 0 {
 		reporter.Warnf("There are no available upgrades")
-		os.Exit(0)
+		return nil
 	}
 
 	if version == "" || interactive.Enabled() {
@@ -202,8 +153,7 @@ func run(cmd *cobra.Command, _ []string) {
 			Required: true,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid version to upgrade to: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid version to upgrade to: %s", err)
 		}
 	}
 
@@ -216,8 +166,7 @@ func run(cmd *cobra.Command, _ []string) {
 		}
 	}
 	if !validVersion {
-		reporter.Errorf("Expected a valid version to upgrade to")
-		os.Exit(1)
+		return rosaerrors.Usagef("Expected a valid version to upgrade to")
 	}
 
 	// Set the default next run within the next 10 minutes
@@ -244,13 +193,11 @@ func run(cmd *cobra.Command, _ []string) {
 			Required: true,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid date: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid date: %s", err)
 		}
 		_, err = time.Parse("2006-01-02", scheduleDate)
 		if err != nil {
-			reporter.Errorf("Date format '%s' invalid", scheduleDate)
-			os.Exit(1)
+			return rosaerrors.Usagef("Date format '%s' invalid", scheduleDate)
 		}
 
 		scheduleTime, err = interactive.GetString(interactive.Input{
@@ -259,27 +206,31 @@ func run(cmd *cobra.Command, _ []string) {
 			Required: true,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid time: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid time: %s", err)
 		}
 		_, err = time.Parse("15:04", scheduleTime)
 		if err != nil {
-			reporter.Errorf("Time format '%s' invalid", scheduleTime)
-			os.Exit(1)
+			return rosaerrors.Usagef("Time format '%s' invalid", scheduleTime)
 		}
 	}
 
 	// Parse next run to time.Time
+	_, err = time.Parse("2006-01-02", scheduleDate)
+	if err != nil {
+		return rosaerrors.Usagef("Date format '%s' invalid, it should be 'yyyy-mm-dd'", scheduleDate)
+	}
+	_, err = time.Parse("15:04", scheduleTime)
+	if err != nil {
+		return rosaerrors.Usagef("Time format '%s' invalid, it should be 'HH:mm'", scheduleTime)
+	}
 	nextRun, err := time.Parse("2006-01-02 15:04", fmt.Sprintf("%s %s", scheduleDate, scheduleTime))
 	if err != nil {
-		reporter.Errorf("Time format invalid: %s", err)
-		os.Exit(1)
+		return rosaerrors.Usagef("Time format invalid: %s", err)
+	}
+	if nextRun.Before(time.Now()) {
+		return rosaerrors.Usagef("Upgrade can't be scheduled in the past, %s is before the current "+
+			"time", nextRun.Format("2006-01-02 15:04 MST"))
 	}
-
-	upgradePolicyBuilder := cmv1.NewUpgradePolicy().
-		ScheduleType("manual").
-		Version(version).
-		NextRun(nextRun)
 
 	nodeDrainGracePeriod := ""
 	// Determine if the cluster already has a node drain grace period set and use that as the default
@@ -320,15 +271,13 @@ func run(cmd *cobra.Command, _ []string) {
 			Required: true,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid node drain grace period: %s", err)
-			os.Exit(1)
+			return rosaerrors.Usagef("Expected a valid node drain grace period: %s", err)
 		}
 	}
 	nodeDrainParsed := strings.Split(nodeDrainGracePeriod, " ")
 	nodeDrainValue, err := strconv.ParseFloat(nodeDrainParsed[0], 64)
 	if err != nil {
-		reporter.Errorf("Expected a valid node drain grace period: %s", err)
-		os.Exit(1)
+		return rosaerrors.Usagef("Expected a valid node drain grace period: %s", err)
 	}
 	if nodeDrainParsed[1] == "hours" || nodeDrainParsed[1] == "hour" {
 		nodeDrainValue = nodeDrainValue * 60
@@ -340,36 +289,20 @@ func run(cmd *cobra.Command, _ []string) {
 			Unit("minutes")).
 		Build()
 	if err != nil {
-		reporter.Errorf("Failed to update cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
-	}
-
-	upgradePolicy, err := upgradePolicyBuilder.Build()
-	if err != nil {
-		reporter.Errorf("Failed to schedule upgrade for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to update cluster '%s': %w", clusterKey, err)
 	}
 
-	_, err = ocmClient.Clusters().
-		Cluster(cluster.ID()).
-		UpgradePolicies().
-		Add().
-		Body(upgradePolicy).
-		Send()
+	err = r.OCM().ScheduleUpgrade(cluster.ID(), version, nextRun)
 	if err != nil {
-		reporter.Errorf("Failed to schedule upgrade for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to schedule upgrade for cluster '%s': %w", clusterKey, err)
 	}
 
-	_, err = ocmClient.Clusters().
-		Cluster(cluster.ID()).
-		Update().
-		Body(clusterSpec).
-		Send()
+	err = r.OCM().UpdateCluster(cluster.ID(), clusterSpec)
 	if err != nil {
-		reporter.Errorf("Failed to update cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to update cluster '%s': %w", clusterKey, err)
 	}
 
-	reporter.Infof("Upgrade successfully scheduled for cluster '%s'", clusterKey)
+	reporter.Infof("Upgrade to version %s successfully scheduled for cluster '%s' on %s",
+		version, clusterKey, nextRun.Format("2006-01-02 15:04 MST"))
+	return nil
 }
@@ -20,7 +20,9 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/cmd/upgrade/cluster"
+	"github.com/openshift/moactl/cmd/upgrade/rosa"
 	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/ocm/roles"
 )
 
 var Cmd = &cobra.Command{
@@ -30,7 +32,10 @@ var Cmd = &cobra.Command{
 }
 
 func init() {
+	roles.Require(Cmd, roles.Update)
+
 	Cmd.AddCommand(cluster.Cmd)
+	Cmd.AddCommand(rosa.Cmd)
 
 	flags := Cmd.PersistentFlags()
 	interactive.AddFlag(flags)
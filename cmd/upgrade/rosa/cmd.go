@@ -0,0 +1,45 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rosa
+
+import (
+	"github.com/spf13/cobra"
+
+	downloadrosa "github.com/openshift/moactl/cmd/download/rosa"
+	"github.com/openshift/moactl/pkg/ocm/roles"
+)
+
+// Cmd is the same as 'rosa download rosa', where the update was originally implemented, under the
+// name that users look for.
+var Cmd = &cobra.Command{
+	Use:   "rosa",
+	Short: "Update the rosa tool",
+	Long:  downloadrosa.Cmd.Long,
+	Example: `  # Update rosa to the newest stable release
+  rosa upgrade rosa
+
+  # Check if there is a newer release without updating
+  rosa upgrade rosa --check`,
+	RunE: downloadrosa.Cmd.RunE,
+}
+
+func init() {
+	// Updating the tool doesn't change OCM resources:
+	roles.Require(Cmd, roles.None)
+
+	Cmd.Flags().AddFlagSet(downloadrosa.Cmd.Flags())
+}
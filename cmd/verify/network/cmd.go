@@ -0,0 +1,171 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/network"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/table"
+)
+
+var args struct {
+	timeout   time.Duration
+	subnetIDs []string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "network",
+	Aliases: []string{"firewall"},
+	Short:   "Verify outbound connectivity to the endpoints required for cluster install",
+	Long: "Verify that the network allows outbound connections to the endpoints that clusters need " +
+		"to reach, such as the OpenShift Cluster Manager, the container registries and telemetry. " +
+		"The connections are made from the machine where the command runs. When subnets are given " +
+		"the VPC where the cluster will be installed is also checked: the subnets need a default " +
+		"route and network ACLs that allow HTTPS connections to any address.",
+	Example: `  # Verify that the required endpoints can be reached
+  rosa verify network
+
+  # Verify the endpoints of a different region
+  rosa verify network --region=us-west-2
+
+  # Verify also the egress of the subnets where the cluster will be installed
+  rosa verify network --subnet-ids=subnet-1,subnet-2`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.DurationVar(
+		&args.timeout,
+		"timeout",
+		5*time.Second,
+		"Maximum time to wait for each connection to be established.",
+	)
+
+	flags.StringSliceVar(
+		&args.subnetIDs,
+		"subnet-ids",
+		nil,
+		"Subnets of the VPC where the cluster will be installed, for example: "+
+			"--subnet-ids=subnet-1,subnet-2. Their routes and network ACLs are checked.",
+	)
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	// Get AWS region
+	region, err := aws.GetRegion(cmd.Flags().Lookup("region").Value.String())
+	if err != nil {
+		return fmt.Errorf("Error getting region: %w", err)
+	}
+
+	endpoints := network.RequiredEndpoints(region, aws.PartitionDefaultRegion(region), aws.DNSSuffix(region))
+	reporter.Infof("Verifying connectivity to %d endpoints...", len(endpoints))
+	results := network.CheckEndpoints(endpoints, args.timeout)
+
+	// Create the writer that will be used to print the tabulated results:
+	writer := table.NewWriter(os.Stdout)
+	fmt.Fprintf(writer, "CATEGORY\tDESTINATION\tSTATUS\n")
+	blocked := 0
+	for _, result := range results {
+		status := "pass"
+		if result.Err != nil {
+			status = "fail"
+			blocked++
+			reporter.Debugf("Failed to connect to '%s': %v", result.Endpoint.Address(), result.Err)
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", result.Endpoint.Category, result.Endpoint.Address(), status)
+	}
+	writer.Flush()
+
+	failed := 0
+	if len(args.subnetIDs) > 0 {
+		failed, err = verifySubnets(r, region)
+		if err != nil {
+			return err
+		}
+	}
+
+	var blockedErr error
+	if blocked > 0 {
+		blockedErr = rosaerrors.Transientf(
+			"%d of %d required endpoints can't be reached. "+
+				"Allow outbound connections to them in your firewall and try again.",
+			blocked, len(endpoints),
+		)
+	}
+	if failed > 0 {
+		if blockedErr != nil {
+			reporter.Errorf("%v", blockedErr)
+		}
+		return fmt.Errorf("%d of %d subnets don't allow the nodes to reach the required endpoints",
+			failed, len(args.subnetIDs))
+	}
+	if blockedErr != nil {
+		return blockedErr
+	}
+	reporter.Infof("All required endpoints can be reached")
+
+	return nil
+}
+
+// verifySubnets prints the egress of the subnets given in the command line, and returns the number
+// of subnets that have problems.
+func verifySubnets(r *runtime.Runtime, region string) (int, error) {
+	reporter := r.Reporter()
+	awsClient := r.WithAWSRegion(region).AWSClient()
+
+	reporter.Infof("Verifying egress of %d subnets...", len(args.subnetIDs))
+	subnets, err := awsClient.GetSubnetsEgress(args.subnetIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	writer := table.NewWriter(os.Stdout)
+	fmt.Fprintf(writer, "\nSUBNET\tDEFAULT ROUTE\tNETWORK ACL\tSTATUS\n")
+	failed := 0
+	for _, subnet := range subnets {
+		status := "pass"
+		if len(subnet.Problems) > 0 {
+			status = "fail: " + strings.Join(subnet.Problems, "; ")
+			failed++
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n",
+			subnet.SubnetID, valueOrNone(subnet.DefaultRoute), valueOrNone(subnet.NetworkACL), status)
+	}
+	writer.Flush()
+	return failed, nil
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
@@ -17,13 +17,14 @@ limitations under the License.
 package oc
 
 import (
+	"fmt"
 	"os/exec"
 	"regexp"
 	"strings"
 
 	"github.com/spf13/cobra"
 
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
 var Cmd = &cobra.Command{
@@ -33,11 +34,12 @@ var Cmd = &cobra.Command{
 	Long:    "Verify that the OpenShift client tools is installed and compatible.",
 	Example: `  # Verify oc client tools
   rosa verify oc`,
-	Run: run,
+	RunE: run,
 }
 
-func run(_ *cobra.Command, _ []string) {
-	reporter := rprtr.CreateReporterOrExit()
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
 
 	// Verify whether `oc` is installed
 	reporter.Infof("Verifying whether OpenShift command-line tool is available...")
@@ -46,23 +48,24 @@ func run(_ *cobra.Command, _ []string) {
 	if output == nil && err != nil {
 		reporter.Warnf("OpenShift command-line tool is not installed.\n" +
 			"Run 'rosa download oc' to download the latest version, then add it to your PATH.")
-		return
+		return nil
 	}
 
 	// Parse the version for the OpenShift Client
 	version := strings.Replace(strings.Split(string(output), "\n")[0], "\n", "", 1)
 	isCorrectVersion, err := regexp.Match(`\W4.\d*`, output)
 	if err != nil {
-		reporter.Errorf("Failed to parse OpenShift Client version: %v", err)
-		return
+		return fmt.Errorf("Failed to parse OpenShift Client version: %w", err)
 	}
 
 	if !isCorrectVersion {
 		reporter.Warnf("Current OpenShift %s", version)
 		reporter.Warnf("Your version of the OpenShift command-line tool is not supported.\n" +
 			"Run 'rosa download oc' to download the latest version, then add it to your PATH.")
-		return
+		return nil
 	}
 
 	reporter.Infof("Current OpenShift %s", version)
+
+	return nil
 }
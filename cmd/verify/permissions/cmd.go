@@ -17,62 +17,90 @@ limitations under the License.
 package permissions
 
 import (
+	"fmt"
 	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/pkg/aws"
-	"github.com/openshift/moactl/pkg/logging"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/output"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/table"
 )
 
 var Cmd = &cobra.Command{
 	Use:   "permissions",
 	Short: "Verify AWS permissions are ok for cluster install",
-	Long:  "Verify AWS permissions needed to create a cluster are configured as expected",
+	Long: "Verify AWS permissions needed to create a cluster are configured as expected. The IAM " +
+		"policy simulator checks the current credentials against each of the actions that the " +
+		"installer and the 'osdCcsAdmin' user need, and the actions that aren't allowed are " +
+		"reported. The credentials need the 'iam:SimulatePrincipalPolicy' permission.",
 	Example: `  # Verify AWS permissions are configured correctly
   rosa verify permissions
 
   # Verify AWS permissions in a different region
-  rosa verify permissions --region=us-west-2`,
-	Run: run,
+  rosa verify permissions --region=us-west-2
+
+  # Report the missing permissions as JSON
+  rosa verify permissions --output=json`,
+	RunE: run,
+}
+
+func init() {
+	output.AddFlag(Cmd.Flags())
 }
 
-func run(cmd *cobra.Command, argv []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
+
+	err := output.Validate()
+	if err != nil {
+		return err
+	}
 
 	// Get AWS region
 	region, err := aws.GetRegion(cmd.Flags().Lookup("region").Value.String())
 	if err != nil {
-		reporter.Errorf("Error getting region: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("Error getting region: %w", err)
 	}
 
 	// Create the AWS client:
-	client, err := aws.NewClient().
-		Logger(logger).
-		Region(region).
-		Build()
-	if err != nil {
-		reporter.Errorf("Error creating AWS client: %v", err)
-		os.Exit(1)
-	}
+	client := r.WithAWSRegion(region).AWSClient()
 
-	reporter.Infof("Validating SCP policies...")
-	ok, err := client.ValidateSCP(nil)
+	if !output.Structured() {
+		reporter.Infof("Validating AWS permissions...")
+	}
+	missing, err := client.VerifyPermissions()
 	if err != nil {
-		reporter.Errorf("Unable to validate SCP policies")
+		reporter.Errorf("Unable to validate AWS permissions")
 		if strings.Contains(err.Error(), "Throttling: Rate exceeded") {
-			reporter.Errorf("Throttling: Rate exceeded. Please wait 3-5 minutes before retrying.")
-			os.Exit(1)
+			return rosaerrors.Transientf("Throttling: Rate exceeded. Please wait 3-5 minutes before retrying.")
 		}
-		reporter.Errorf("%v", err)
-		os.Exit(1)
+		return err
 	}
-	if !ok {
-		reporter.Warnf("Failed to validate SCP policies. Will try to continue anyway...")
+
+	if output.Structured() {
+		err = output.PrintValue(missing)
+		if err != nil {
+			return fmt.Errorf("Failed to print permissions: %w", err)
+		}
+	} else if len(missing) > 0 {
+		writer := table.NewWriter(os.Stdout)
+		fmt.Fprintf(writer, "POLICY\tACTION\tDECISION\n")
+		for _, permission := range missing {
+			fmt.Fprintf(writer, "%s\t%s\t%s\n", permission.Policy, permission.Action, permission.Decision)
+		}
+		writer.Flush()
 	}
-	reporter.Infof("AWS SCP policies ok")
+	if len(missing) > 0 {
+		return rosaerrors.Authf("The AWS credentials aren't allowed to perform %d actions", len(missing))
+	}
+	if !output.Structured() {
+		reporter.Infof("AWS permissions ok")
+	}
+
+	return nil
 }
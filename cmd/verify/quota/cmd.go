@@ -17,54 +17,109 @@ limitations under the License.
 package quota
 
 import (
+	"fmt"
 	"os"
+	"sort"
 
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/pkg/aws"
-	"github.com/openshift/moactl/pkg/logging"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm/regions"
+	"github.com/openshift/moactl/pkg/output"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/table"
 )
 
+// allRegions is the value of the '--region' flag that checks all the regions available to the
+// account.
+const allRegions = "all"
+
 var Cmd = &cobra.Command{
 	Use:   "quota",
 	Short: "Verify AWS quota is ok for cluster install",
-	Long:  "Verify AWS quota needed to create a cluster is configured as expected",
+	Long: "Verify AWS quota needed to create a cluster is configured as expected. Use " +
+		"'--region=all' to verify all the regions available to the account, and '--output' to " +
+		"get a report of the quotas that are insufficient and by how much.",
 	Example: `  # Verify AWS quotas are configured correctly
   rosa verify quota
 
   # Verify AWS quotas in a different region
-  rosa verify quota --region=us-west-2`,
-	Run: run,
+  rosa verify quota --region=us-west-2
+
+  # Verify AWS quotas in all the regions and report the insufficient ones as JSON
+  rosa verify quota --region=all --output=json`,
+	RunE: run,
+}
+
+func init() {
+	output.AddFlag(Cmd.Flags())
 }
 
-func run(cmd *cobra.Command, argv []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+	reporter := r.Reporter()
 
-	// Get AWS region
-	region, err := aws.GetRegion(cmd.Flags().Lookup("region").Value.String())
+	err := output.Validate()
 	if err != nil {
-		reporter.Errorf("Error getting region: %v", err)
-		os.Exit(1)
+		return err
 	}
 
-	// Create the AWS client:
-	client, err := aws.NewClient().
-		Logger(logger).
-		Region(region).
-		Build()
-	if err != nil {
-		reporter.Errorf("Error creating AWS client: %v", err)
-		os.Exit(1)
+	regionIDs := []string{}
+	region := cmd.Flags().Lookup("region").Value.String()
+	if region == allRegions {
+		reporter.Debugf("Fetching regions")
+		cloudRegions, err := regions.GetRegions(r.OCM(),
+			r.WithAWSRegion(aws.GlobalRegion()).AWSClient())
+		if err != nil {
+			return fmt.Errorf("Failed to fetch regions: %w", err)
+		}
+		for _, cloudRegion := range cloudRegions {
+			regionIDs = append(regionIDs, cloudRegion.ID())
+		}
+		sort.Strings(regionIDs)
+	} else {
+		// Get AWS region
+		region, err = aws.GetRegion(region)
+		if err != nil {
+			return fmt.Errorf("Error getting region: %w", err)
+		}
+		regionIDs = append(regionIDs, region)
 	}
 
-	reporter.Infof("Validating AWS quota...")
-	_, err = client.ValidateQuota()
+	if !output.Structured() {
+		reporter.Infof("Validating AWS quota...")
+	}
+	checksByRegion, err := aws.CheckQuotasByRegion(r.Logger(), regionIDs)
 	if err != nil {
-		reporter.Errorf("Insufficient AWS quotas")
-		reporter.Errorf("%v", err)
-		os.Exit(1)
+		return err
 	}
-	reporter.Infof("AWS quota ok")
+	insufficient := []*aws.QuotaCheck{}
+	for _, regionID := range regionIDs {
+		insufficient = append(insufficient, aws.InsufficientQuotas(checksByRegion[regionID])...)
+	}
+
+	if output.Structured() {
+		err = output.PrintValue(insufficient)
+		if err != nil {
+			return fmt.Errorf("Failed to print quotas: %w", err)
+		}
+	} else if len(insufficient) > 0 {
+		writer := table.NewWriter(os.Stdout).Numeric("REQUIRED", "VALUE", "MISSING")
+		fmt.Fprintf(writer, "REGION\tSERVICE\tQUOTA CODE\tQUOTA NAME\tREQUIRED\tVALUE\tMISSING\n")
+		for _, check := range insufficient {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%d\t%d\t%d\n",
+				check.Region, check.ServiceCode, check.QuotaCode, check.QuotaName,
+				int(check.Required), int(check.Value), int(check.Missing))
+		}
+		writer.Flush()
+	}
+	if len(insufficient) > 0 {
+		return rosaerrors.Quotaf("Insufficient AWS quotas")
+	}
+	if !output.Structured() {
+		reporter.Infof("AWS quota ok")
+	}
+
+	return nil
 }
@@ -19,19 +19,120 @@ package version
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/moactl/pkg/arguments"
+	"github.com/openshift/moactl/pkg/deprecation"
 	"github.com/openshift/moactl/pkg/info"
+	"github.com/openshift/moactl/pkg/runtime"
+	"github.com/openshift/moactl/pkg/table"
 )
 
+var args struct {
+	deprecations bool
+	changes      bool
+}
+
 var Cmd = &cobra.Command{
 	Use:   "version",
 	Short: "Prints the version of the tool",
 	Long:  "Prints the version number of the tool.",
-	Run:   run,
+	Example: `  # Print the version of the tool
+  rosa version
+
+  # List the deprecated flags that are still accepted
+  rosa version --deprecations
+
+  # Show the changes of the releases newer than the installed version
+  rosa version --changes`,
+	PreRunE: arguments.CheckFlagGroups,
+	RunE:    run,
 }
 
-func run(cmd *cobra.Command, argv []string) {
-	fmt.Fprintf(os.Stdout, "%s\n", info.Version)
+func init() {
+	flags := Cmd.Flags()
+
+	flags.BoolVar(
+		&args.deprecations,
+		"deprecations",
+		false,
+		"List the deprecated flags that are still accepted and the version where they will be removed.",
+	)
+	flags.BoolVar(
+		&args.changes,
+		"changes",
+		false,
+		"Show the release notes of the published releases of the update channel that are newer "+
+			"than this version.",
+	)
+	arguments.MarkFlagsMutuallyExclusive(flags, "deprecations", "changes")
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	r := runtime.FromContext(cmd.Context())
+
+	switch {
+	case args.deprecations:
+		printDeprecations()
+	case args.changes:
+		return printChanges(r)
+	default:
+		fmt.Fprintf(os.Stdout, "%s\n", info.Version)
+	}
+
+	return nil
+}
+
+func printDeprecations() {
+
+	// Create the writer that will be used to print the tabulated results:
+	writer := table.NewWriter(os.Stdout)
+	fmt.Fprintf(writer, "COMMAND\tFLAG\tREPLACEMENT\tREMOVED IN\n")
+	for _, flag := range deprecation.Flags() {
+		fmt.Fprintf(
+			writer,
+			"%s\t--%s\t--%s\t%s\n",
+			flag.Command.CommandPath(),
+			flag.Name,
+			flag.Replacement,
+			flag.RemovedIn,
+		)
+	}
+	writer.Flush()
+}
+
+func printChanges(r *runtime.Runtime) error {
+	reporter := r.Reporter()
+
+	channel, err := info.ConfiguredChannel()
+	if err != nil {
+		return fmt.Errorf("Failed to get the update channel: %w", err)
+	}
+	releases, err := info.GetChannelReleases(channel)
+	if err != nil {
+		return fmt.Errorf("Failed to get the published releases: %w", err)
+	}
+	newer, err := info.ReleasesSince(releases, info.Version)
+	if err != nil {
+		return err
+	}
+	if len(newer) == 0 {
+		reporter.Infof("Version '%s' is the latest release", info.Version)
+		return nil
+	}
+
+	reporter.Infof("There are %d releases newer than version '%s', the latest is '%s'",
+		len(newer), info.Version, newer[0].Version)
+	for _, release := range newer {
+		fmt.Printf("\n%s (%s)\n\n", release.Version, release.Published.Format("2006-01-02"))
+		notes := strings.TrimSpace(strings.ReplaceAll(release.Notes, "\r\n", "\n"))
+		if notes == "" {
+			notes = "No release notes."
+		}
+		fmt.Printf("%s\n", notes)
+	}
+
+	return nil
 }
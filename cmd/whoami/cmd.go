@@ -18,17 +18,14 @@ package whoami
 
 import (
 	"fmt"
-	"net/http"
-	"os"
 
 	amsv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/pkg/aws"
-	"github.com/openshift/moactl/pkg/logging"
-	"github.com/openshift/moactl/pkg/ocm"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/ocm/config"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runtime"
 )
 
 var Cmd = &cobra.Command{
@@ -37,102 +34,56 @@ var Cmd = &cobra.Command{
 	Long:  "Displays information about your AWS and Red Hat accounts",
 	Example: `  # Displays user information
   rosa whoami`,
-	Run: run,
+	RunE: run,
 }
 
-func run(_ *cobra.Command, _ []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
-
-	// Create the AWS client:
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("failed to create AWS client: %v", err)
-		os.Exit(1)
-	}
+func run(cmd *cobra.Command, _ []string) error {
+	r := runtime.FromContext(cmd.Context())
 
 	// Get current AWS account information:
-	awsCreator, err := awsClient.GetCreator()
-	if err != nil {
-		reporter.Errorf("failed to get AWS creator: %v", err)
-		os.Exit(1)
-	}
+	awsCreator := r.Creator()
 
 	// Get default AWS region:
 	awsRegion, err := aws.GetRegion("")
 	if err != nil {
-		reporter.Errorf("Error getting AWS region: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("Error getting AWS region: %w", err)
 	}
 
 	// Load the configuration file:
 	cfg, err := config.Load()
 	if err != nil {
-		reporter.Errorf("Failed to load config file: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to load config file: %w", err)
 	}
 	if cfg == nil {
-		reporter.Errorf("User is not logged in to OCM")
-		os.Exit(0)
+		return rosaerrors.Authf("User is not logged in to OCM")
 	}
 
 	// Verify configuration file:
 	loggedIn, err := cfg.Armed()
 	if err != nil {
-		reporter.Errorf("Failed to verify configuration: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to verify configuration: %w", err)
 	}
 	if !loggedIn {
-		reporter.Errorf("User is not logged in to OCM")
-		os.Exit(0)
+		return rosaerrors.Authf("User is not logged in to OCM")
 	}
 
-	// Create a connection to OCM:
-	connection, err := ocm.NewConnection().
-		Config(cfg).
-		Logger(logger).
-		Build()
+	// Get current OCM account, or the data of the token for users without an account:
+	account, err := r.OCM().GetCurrentAccount()
 	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get current account: %w", err)
 	}
-	defer func() {
-		err = connection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
-
-	// Get current OCM account:
-	useTokenData := false
-	response, err := connection.AccountsMgmt().V1().CurrentAccount().Get().Send()
-	if err != nil {
-		reporter.Debugf(err.Error())
-		if response.Status() == http.StatusNotFound {
-			useTokenData = true
-		} else {
-			reporter.Errorf("Failed to get current account: %s", response.Error().Reason())
-			os.Exit(1)
-		}
-	}
-
-	var account *amsv1.Account
-	if useTokenData {
+	if account == nil {
 		account, err = getAccountDataFromToken(cfg)
 		if err != nil {
-			reporter.Errorf("Failed to get account data from token: %v", err)
-			os.Exit(1)
+			return fmt.Errorf("Failed to get account data from token: %w", err)
 		}
-	} else {
-		account = response.Body()
 	}
 	fmt.Printf(""+
 		"AWS Account ID:               %s\n"+
 		"AWS Default Region:           %s\n"+
 		"AWS ARN:                      %s\n"+
 		"OCM API:                      %s\n"+
+		"OCM Environment:              %s\n"+
 		"OCM Account ID:               %s\n"+
 		"OCM Account Name:             %s %s\n"+
 		"OCM Account Username:         %s\n"+
@@ -143,7 +94,8 @@ func run(_ *cobra.Command, _ []string) {
 		awsCreator.AccountID,
 		awsRegion,
 		awsCreator.ARN,
-		cfg.URL,
+		r.OCM().URL(),
+		config.EnvName(r.OCM().URL()),
 		account.ID(),
 		account.FirstName(), account.LastName(),
 		account.Username(),
@@ -153,6 +105,8 @@ func run(_ *cobra.Command, _ []string) {
 		account.Organization().ExternalID(),
 	)
 	fmt.Println()
+
+	return nil
 }
 
 func getAccountDataFromToken(cfg *config.Config) (*amsv1.Account, error) {
@@ -21,8 +21,17 @@ package arguments
 import (
 	"github.com/spf13/pflag"
 
-	"github.com/openshift/moactl/pkg/aws/profile"
+	"github.com/openshift/moactl/pkg/aws/assumerole"
+	"github.com/openshift/moactl/pkg/cache"
+	"github.com/openshift/moactl/pkg/ci"
+	"github.com/openshift/moactl/pkg/config"
 	"github.com/openshift/moactl/pkg/debug"
+	"github.com/openshift/moactl/pkg/logging"
+	ocmconfig "github.com/openshift/moactl/pkg/ocm/config"
+	"github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/retry"
+	"github.com/openshift/moactl/pkg/simulate"
+	"github.com/openshift/moactl/pkg/transport"
 )
 
 // AddDebugFlag adds the '--debug' flag to the given set of command line flags.
@@ -30,7 +39,64 @@ func AddDebugFlag(fs *pflag.FlagSet) {
 	debug.AddFlag(fs)
 }
 
-// AddProfileFlag adds the '--profile' flag to the given set of command line flags.
+// AddLogLevelsFlag adds the '--v' flag to the given set of command line flags.
+func AddLogLevelsFlag(fs *pflag.FlagSet) {
+	logging.AddLevelsFlag(fs)
+}
+
+// AddLogFormatFlag adds the '--log-format' flag to the given set of command line flags.
+func AddLogFormatFlag(fs *pflag.FlagSet) {
+	reporter.AddFormatFlag(fs)
+}
+
+// AddRetryFlags adds the '--max-retries' and '--request-timeout' flags to the given set of command
+// line flags.
+func AddRetryFlags(fs *pflag.FlagSet) {
+	retry.AddFlags(fs)
+}
+
+// AddRefreshFlag adds the '--refresh' flag to the given set of command line flags.
+func AddRefreshFlag(fs *pflag.FlagSet) {
+	cache.AddFlag(fs)
+}
+
+// AddCIFlags adds the '--ci', '--ci-timeout' and '--result-file' flags to the given set of command
+// line flags.
+func AddCIFlags(fs *pflag.FlagSet) {
+	ci.AddFlags(fs)
+}
+
+// AddProfileFlag adds the '--profile' flag, which selects a profile of the configuration file or an
+// AWS profile, to the given set of command line flags.
 func AddProfileFlag(fs *pflag.FlagSet) {
-	profile.AddFlag(fs)
+	config.AddFlag(fs)
+}
+
+// AddAssumeRoleFlags adds the '--assume-role-arn', '--external-id' and '--role-session-name' flags
+// to the given set of command line flags.
+func AddAssumeRoleFlags(fs *pflag.FlagSet) {
+	assumerole.AddFlags(fs)
+}
+
+// AddCAFileFlag adds the '--ca-file' flag, which adds trusted certificate authorities, to the given
+// set of command line flags.
+func AddCAFileFlag(fs *pflag.FlagSet) {
+	transport.AddFlag(fs)
+}
+
+// AddEnvFlag adds the '--env' flag, which selects the OCM environment, to the given set of command
+// line flags.
+func AddEnvFlag(fs *pflag.FlagSet) {
+	ocmconfig.AddEnvFlag(fs)
+}
+
+// AddOCMConfigFlag adds the '--ocm-config' flag to the given set of command line flags.
+func AddOCMConfigFlag(fs *pflag.FlagSet) {
+	ocmconfig.AddFlag(fs)
+}
+
+// AddSimulateFlag adds the hidden '--simulate' flag, which answers the requests to the OCM and AWS
+// APIs with canned responses, to the given set of command line flags.
+func AddSimulateFlag(fs *pflag.FlagSet) {
+	simulate.AddFlag(fs)
 }
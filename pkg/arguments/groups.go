@@ -0,0 +1,163 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions that declare groups of related command line flags and check that
+// the flags given by the user satisfy them.
+
+package arguments
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// Names of the flag annotations used to store the groups. Each value of the annotation is the list
+// of names of the flags of one group, separated by spaces.
+const (
+	mutuallyExclusiveAnnotation = "rosa_group_mutually_exclusive"
+	requiredTogetherAnnotation  = "rosa_group_required_together"
+	requiresAnnotation          = "rosa_group_requires"
+)
+
+// MarkFlagsMutuallyExclusive declares that at most one of the given flags can be used at the same
+// time.
+func MarkFlagsMutuallyExclusive(fs *pflag.FlagSet, names ...string) {
+	addGroup(fs, mutuallyExclusiveAnnotation, names, names)
+}
+
+// MarkFlagsRequiredTogether declares that if any of the given flags is used then all of them have
+// to be used.
+func MarkFlagsRequiredTogether(fs *pflag.FlagSet, names ...string) {
+	addGroup(fs, requiredTogetherAnnotation, names, names)
+}
+
+// MarkFlagRequires declares that if the given flag is used then all the required flags have to be
+// used as well. The required flags can still be used on their own.
+func MarkFlagRequires(fs *pflag.FlagSet, name string, required ...string) {
+	addGroup(fs, requiresAnnotation, append([]string{name}, required...), []string{name})
+}
+
+func addGroup(fs *pflag.FlagSet, annotation string, names []string, owners []string) {
+	for _, name := range names {
+		if fs.Lookup(name) == nil {
+			panic(fmt.Sprintf("flag '%s' used in a flag group doesn't exist", name))
+		}
+	}
+	group := strings.Join(names, " ")
+	for _, owner := range owners {
+		flag := fs.Lookup(owner)
+		if flag.Annotations == nil {
+			flag.Annotations = map[string][]string{}
+		}
+		flag.Annotations[annotation] = append(flag.Annotations[annotation], group)
+	}
+}
+
+// ValidateFlagGroups checks that the flags that have been explicitly set satisfy all the groups
+// declared in the given flag set. It returns an error describing the first group that isn't
+// satisfied. It doesn't call any API, so it is intended to be called before doing any other work.
+func ValidateFlagGroups(fs *pflag.FlagSet) (err error) {
+	checked := map[string]bool{}
+	fs.VisitAll(func(flag *pflag.Flag) {
+		if err != nil {
+			return
+		}
+		for _, annotation := range []string{
+			mutuallyExclusiveAnnotation,
+			requiredTogetherAnnotation,
+			requiresAnnotation,
+		} {
+			for _, group := range flag.Annotations[annotation] {
+				key := annotation + "=" + group
+				if checked[key] {
+					continue
+				}
+				checked[key] = true
+				err = validateGroup(fs, annotation, strings.Fields(group))
+				if err != nil {
+					return
+				}
+			}
+		}
+	})
+	return
+}
+
+// CheckFlagGroups checks the flag groups of the given command, returning a usage error if they
+// aren't satisfied. It is intended to be used as the PreRunE function of the commands that declare
+// flag groups, so that they are checked before the command does any work.
+func CheckFlagGroups(cmd *cobra.Command, _ []string) error {
+	return rosaerrors.Wrap(rosaerrors.ExitUsage, ValidateFlagGroups(cmd.Flags()))
+}
+
+func validateGroup(fs *pflag.FlagSet, annotation string, names []string) error {
+	set := []string{}
+	unset := []string{}
+	for _, name := range names {
+		if fs.Changed(name) {
+			set = append(set, name)
+		} else {
+			unset = append(unset, name)
+		}
+	}
+
+	switch annotation {
+	case mutuallyExclusiveAnnotation:
+		if len(set) > 1 {
+			return fmt.Errorf("At most one of %s may be specified", joinFlags(names, "or"))
+		}
+	case requiredTogetherAnnotation:
+		if len(set) > 0 && len(unset) > 0 {
+			return fmt.Errorf(
+				"Flags %s must be specified together, missing %s",
+				joinFlags(names, "and"), joinFlags(unset, "and"),
+			)
+		}
+	case requiresAnnotation:
+		if fs.Changed(names[0]) {
+			missing := []string{}
+			for _, name := range names[1:] {
+				if !fs.Changed(name) {
+					missing = append(missing, name)
+				}
+			}
+			if len(missing) > 0 {
+				return fmt.Errorf(
+					"Flag %s requires %s",
+					joinFlags(names[:1], "and"), joinFlags(missing, "and"),
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// joinFlags returns a human readable list of flag names, for example "'--a', '--b' or '--c'".
+func joinFlags(names []string, conjunction string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = fmt.Sprintf("'--%s'", name)
+	}
+	if len(quoted) == 1 {
+		return quoted[0]
+	}
+	return strings.Join(quoted[:len(quoted)-1], ", ") + " " + conjunction + " " + quoted[len(quoted)-1]
+}
@@ -0,0 +1,213 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the function that describes the flags of a command as a JSON schema, so that
+// tools that wrap the command line can generate forms and validate the inputs before running it.
+
+package arguments
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// SchemaVersion is the version of the JSON schema specification that the generated schemas follow.
+const SchemaVersion = "http://json-schema.org/draft-07/schema#"
+
+// OwnFlags returns the flags defined by the command itself. Unlike the LocalFlags method of the
+// command it keeps the flags that shadow persistent flags of the parents, like the '--dry-run' of
+// 'rosa create cluster'.
+func OwnFlags(cmd *cobra.Command) *pflag.FlagSet {
+	own := pflag.NewFlagSet(cmd.Name(), pflag.ContinueOnError)
+	inherited := cmd.InheritedFlags()
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if inherited.Lookup(flag.Name) != flag {
+			own.AddFlag(flag)
+		}
+	})
+	return own
+}
+
+// Schema returns a JSON schema describing the given flags as the properties of an object. Hidden
+// flags and the excluded flags are left out. The flag groups declared with MarkFlagsMutuallyExclusive,
+// MarkFlagsRequiredTogether and MarkFlagRequires are translated into the equivalent schema
+// keywords, so that the schema rejects the same combinations that ValidateFlagGroups rejects.
+func Schema(fs *pflag.FlagSet, title string, description string, exclude ...string) map[string]interface{} {
+	properties := map[string]interface{}{}
+	dependencies := map[string][]string{}
+	exclusions := []interface{}{}
+	checked := map[string]bool{}
+	fs.VisitAll(func(flag *pflag.Flag) {
+		if flag.Hidden || contains(exclude, flag.Name) {
+			return
+		}
+		properties[flag.Name] = flagSchema(flag)
+		for _, annotation := range []string{
+			mutuallyExclusiveAnnotation,
+			requiredTogetherAnnotation,
+			requiresAnnotation,
+		} {
+			for _, group := range flag.Annotations[annotation] {
+				key := annotation + "=" + group
+				if checked[key] {
+					continue
+				}
+				checked[key] = true
+				names := strings.Fields(group)
+				if hasHidden(fs, names) || containsAny(exclude, names) {
+					continue
+				}
+				switch annotation {
+				case mutuallyExclusiveAnnotation:
+					exclusions = append(exclusions, exclusion(names))
+				case requiredTogetherAnnotation:
+					for _, name := range names {
+						dependencies[name] = appendMissing(dependencies[name], without(names, name)...)
+					}
+				case requiresAnnotation:
+					dependencies[names[0]] = appendMissing(dependencies[names[0]], names[1:]...)
+				}
+			}
+		}
+	})
+
+	schema := map[string]interface{}{
+		"$schema":              SchemaVersion,
+		"title":                title,
+		"description":          description,
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(dependencies) > 0 {
+		schema["dependencies"] = dependencies
+	}
+	if len(exclusions) > 0 {
+		schema["allOf"] = exclusions
+	}
+	return schema
+}
+
+// flagSchema returns the schema of the value of a single flag, derived from its type and default
+// value.
+func flagSchema(flag *pflag.Flag) map[string]interface{} {
+	schema := map[string]interface{}{
+		"description": flag.Usage,
+	}
+	def := flag.DefValue
+	switch flag.Value.Type() {
+	case "bool":
+		schema["type"] = "boolean"
+		if value, err := strconv.ParseBool(def); err == nil {
+			schema["default"] = value
+		}
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		schema["type"] = "integer"
+		if value, err := strconv.ParseInt(def, 10, 64); err == nil {
+			schema["default"] = value
+		}
+	case "float32", "float64":
+		schema["type"] = "number"
+		if value, err := strconv.ParseFloat(def, 64); err == nil {
+			schema["default"] = value
+		}
+	case "stringSlice", "stringArray":
+		schema["type"] = "array"
+		schema["items"] = map[string]interface{}{"type": "string"}
+	case "ipNet":
+		schema["type"] = "string"
+		schema["format"] = "cidr"
+	case "duration":
+		schema["type"] = "string"
+		schema["format"] = "duration"
+		if def != "0s" {
+			schema["default"] = def
+		}
+	default:
+		schema["type"] = "string"
+		if def != "" {
+			schema["default"] = def
+		}
+	}
+	return schema
+}
+
+// exclusion returns a schema that rejects objects that contain more than one of the given
+// properties.
+func exclusion(names []string) map[string]interface{} {
+	pairs := []interface{}{}
+	for i := range names {
+		for j := i + 1; j < len(names); j++ {
+			pairs = append(pairs, map[string]interface{}{
+				"required": []string{names[i], names[j]},
+			})
+		}
+	}
+	return map[string]interface{}{
+		"not": map[string]interface{}{
+			"anyOf": pairs,
+		},
+	}
+}
+
+func hasHidden(fs *pflag.FlagSet, names []string) bool {
+	for _, name := range names {
+		if fs.Lookup(name).Hidden {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(names []string, name string) bool {
+	for _, candidate := range names {
+		if candidate == name {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAny(names []string, candidates []string) bool {
+	for _, candidate := range candidates {
+		if contains(names, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+func without(names []string, name string) []string {
+	result := []string{}
+	for _, candidate := range names {
+		if candidate != name {
+			result = append(result, candidate)
+		}
+	}
+	return result
+}
+
+func appendMissing(names []string, added ...string) []string {
+	for _, name := range added {
+		if !contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
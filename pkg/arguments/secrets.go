@@ -0,0 +1,149 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions that allow reading the values of the flags that contain secrets
+// from files or from the standard input, so that the secrets don't appear in the process list or
+// in the history of the shell.
+
+package arguments
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// Name of the annotation of the '--...-file' flags that contains the name of the flag whose value
+// is read from the file.
+const secretFileAnnotation = "rosa_secret_file"
+
+// StdinValue is the value of a secret flag, or of its file flag, that indicates that the secret
+// has to be read from the standard input.
+const StdinValue = "-"
+
+// usagePrefixRE matches the prefix of the usage of flags that only apply to some kinds of
+// resources, like 'LDAP: '.
+var usagePrefixRE = regexp.MustCompile(`^[A-Za-z]+: `)
+
+// stdinRead indicates if a secret has already been read from the standard input, as that can only
+// be done once.
+var stdinRead bool
+
+// AddSecretFileFlag adds a '--<name>-file' flag that can be used instead of the given secret flag
+// to read the secret from a file. The secrets are read by ResolveSecrets.
+func AddSecretFileFlag(fs *pflag.FlagSet, name string) {
+	secret := fs.Lookup(name)
+	if secret == nil {
+		panic(fmt.Sprintf("secret flag '%s' doesn't exist", name))
+	}
+	// The file flag goes right after the secret flag in the help, so it takes the prefix that
+	// indicates what the flag applies to, like 'LDAP: ', and the trailing newline that separates
+	// the groups of flags:
+	usage := strings.TrimRight(secret.Usage, "\n")
+	separator := secret.Usage[len(usage):]
+	prefix := ""
+	if match := usagePrefixRE.FindString(usage); match != "" {
+		prefix = match
+	}
+	secret.Usage = fmt.Sprintf("%s Use '%s' to read it from the standard input.", usage, StdinValue)
+
+	fileName := name + "-file"
+	fs.String(
+		fileName,
+		"",
+		fmt.Sprintf("%sPath of a file containing the value of '--%s', or '%s' to read it from the "+
+			"standard input.%s", prefix, name, StdinValue, separator),
+	)
+	fs.Lookup(fileName).Annotations = map[string][]string{
+		secretFileAnnotation: {name},
+	}
+	MarkFlagsMutuallyExclusive(fs, name, fileName)
+}
+
+// ResolveSecrets replaces the values of the secret flags that are given with their file flags, or
+// with the '-' value, with the content of the files or of the standard input. The trailing newline
+// is removed. It is intended to be called after ValidateFlagGroups and before the values of the
+// flags are used.
+func ResolveSecrets(fs *pflag.FlagSet) (err error) {
+	fs.VisitAll(func(flag *pflag.Flag) {
+		if err != nil || !flag.Changed {
+			return
+		}
+		names, ok := flag.Annotations[secretFileAnnotation]
+		if ok {
+			var value string
+			value, err = readSecretFile(flag.Value.String())
+			if err != nil {
+				err = fmt.Errorf("Failed to read value of '--%s': %v", names[0], err)
+				return
+			}
+			err = fs.Set(names[0], value)
+			return
+		}
+		if flag.Value.Type() == "string" && flag.Value.String() == StdinValue && isSecret(fs, flag.Name) {
+			var value string
+			value, err = ReadSecret(StdinValue)
+			if err != nil {
+				err = fmt.Errorf("Failed to read value of '--%s': %v", flag.Name, err)
+				return
+			}
+			err = fs.Set(flag.Name, value)
+		}
+	})
+	return
+}
+
+// isSecret checks if the given flag has a file flag added by AddSecretFileFlag.
+func isSecret(fs *pflag.FlagSet, name string) bool {
+	file := fs.Lookup(name + "-file")
+	if file == nil {
+		return false
+	}
+	_, ok := file.Annotations[secretFileAnnotation]
+	return ok
+}
+
+// ReadSecret returns the given value of a secret, unless it is '-', in which case the secret is
+// read from the standard input. This is intended for secrets that are part of other values, like
+// the password in 'user:password'.
+func ReadSecret(value string) (string, error) {
+	if value != StdinValue {
+		return value, nil
+	}
+	return readSecretFile(StdinValue)
+}
+
+func readSecretFile(path string) (string, error) {
+	var data []byte
+	var err error
+	if path == StdinValue {
+		if stdinRead {
+			return "", fmt.Errorf("the standard input can only be used for one secret")
+		}
+		stdinRead = true
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
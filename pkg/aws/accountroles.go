@@ -0,0 +1,242 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that create and describe the account roles, the IAM roles that
+// are created once per AWS account and shared by all its clusters when they use AWS STS instead of
+// the access keys of the admin user.
+
+package aws
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+
+	"github.com/openshift/moactl/assets"
+	"github.com/openshift/moactl/pkg/aws/tags"
+)
+
+// DefaultAccountRolePrefix is the prefix of the names of the account roles when users don't choose
+// one.
+const DefaultAccountRolePrefix = "ManagedOpenShift"
+
+// AccountRoleVersion is the version of the policies of the account roles that this version of the
+// tool expects. Roles are tagged with the version of their policies.
+const AccountRoleVersion = "1"
+
+// The prefix is part of the role names, so it has to follow the IAM naming rules and leave room for
+// the longest suffix:
+var accountRolePrefixRE = regexp.MustCompile(`^[\w+=,.@-]{1,32}$`)
+
+// ValidateAccountRolePrefix checks that the given prefix can be used for the names of the account
+// roles.
+func ValidateAccountRolePrefix(prefix string) error {
+	if !accountRolePrefixRE.MatchString(prefix) {
+		return fmt.Errorf("Prefix '%s' isn't valid: it must be at most 32 characters long and contain "+
+			"only letters, digits and the characters '+=,.@_-'", prefix)
+	}
+	return nil
+}
+
+// SupportPrincipal is the Red Hat principal that the support role has to trust.
+const SupportPrincipal = "arn:aws:iam::710019948333:role/RH-Technical-Support-Access"
+
+// Names of the types of account roles:
+const (
+	InstallerRoleType    = "installer"
+	ControlPlaneRoleType = "control-plane"
+	WorkerRoleType       = "worker"
+	SupportRoleType      = "support"
+)
+
+// AccountRoleTypes are the types of the account roles, in the order they are described. The name of
+// each role is the prefix followed by the suffix of its type. The installer and support roles are
+// assumed by Red Hat, and the control plane and worker roles by the instances of the cluster.
+var AccountRoleTypes = []AccountRoleType{
+	{
+		Name:       InstallerRoleType,
+		Suffix:     "Installer-Role",
+		Principal:  InstallerPrincipal,
+		PolicyPath: "templates/policies/sts_installer_permission_policy.json",
+	},
+	{
+		Name:       ControlPlaneRoleType,
+		Suffix:     "ControlPlane-Role",
+		Service:    "ec2.amazonaws.com",
+		PolicyPath: "templates/policies/sts_instance_controlplane_permission_policy.json",
+	},
+	{
+		Name:       WorkerRoleType,
+		Suffix:     "Worker-Role",
+		Service:    "ec2.amazonaws.com",
+		PolicyPath: "templates/policies/sts_instance_worker_permission_policy.json",
+	},
+	{
+		Name:       SupportRoleType,
+		Suffix:     "Support-Role",
+		Principal:  SupportPrincipal,
+		PolicyPath: "templates/policies/sts_support_permission_policy.json",
+	},
+}
+
+// AccountRoleType describes one of the types of account roles. The role trusts either the AWS
+// principal or the AWS service.
+type AccountRoleType struct {
+	Name       string
+	Suffix     string
+	Principal  string
+	Service    string
+	PolicyPath string
+}
+
+// RoleName returns the name of the role of this type with the given prefix.
+func (t AccountRoleType) RoleName(prefix string) string {
+	return fmt.Sprintf("%s-%s", prefix, t.Suffix)
+}
+
+// PolicyName returns the name of the managed policy of the role of this type with the given prefix.
+func (t AccountRoleType) PolicyName(prefix string) string {
+	return fmt.Sprintf("%s-Policy", t.RoleName(prefix))
+}
+
+// FindAccountRole returns the role of the given type from the given list, or nil if it isn't in
+// the list.
+func FindAccountRole(roles []*AccountRole, roleType string) *AccountRole {
+	for _, role := range roles {
+		if role.Type == roleType {
+			return role
+		}
+	}
+	return nil
+}
+
+// AccountRole describes an account role and the managed policies attached to it.
+type AccountRole struct {
+	Type                string            `json:"type"`
+	Name                string            `json:"name"`
+	ARN                 string            `json:"arn"`
+	Path                string            `json:"path"`
+	PermissionsBoundary string            `json:"permissions_boundary,omitempty"`
+	Created             time.Time         `json:"created"`
+	Version             string            `json:"version"`
+	Policies            []*AttachedPolicy `json:"policies"`
+}
+
+// UpToDate checks if the policies of the role have the version that this version of the tool
+// expects.
+func (r *AccountRole) UpToDate() bool {
+	return r.Version == AccountRoleVersion
+}
+
+// AttachedPolicy describes a managed policy attached to a role.
+type AttachedPolicy struct {
+	Name           string `json:"name"`
+	ARN            string `json:"arn"`
+	DefaultVersion string `json:"default_version"`
+}
+
+// GetAccountRoles returns the account roles with the given prefix. Roles that don't exist aren't
+// returned.
+func (c *awsClient) GetAccountRoles(prefix string) ([]*AccountRole, error) {
+	result := []*AccountRole{}
+	for _, roleType := range AccountRoleTypes {
+		roleName := roleType.RoleName(prefix)
+		output, err := c.iamClient.GetRole(&iam.GetRoleInput{
+			RoleName: aws.String(roleName),
+		})
+		if err != nil {
+			if aerr, ok := err.(awserr.Error); ok && aerr.Code() == iam.ErrCodeNoSuchEntityException {
+				continue
+			}
+			return nil, fmt.Errorf("Failed to get role '%s': %v", roleName, err)
+		}
+		role := &AccountRole{
+			Type:    roleType.Name,
+			Name:    roleName,
+			ARN:     aws.StringValue(output.Role.Arn),
+			Path:    aws.StringValue(output.Role.Path),
+			Created: aws.TimeValue(output.Role.CreateDate),
+		}
+		if output.Role.PermissionsBoundary != nil {
+			role.PermissionsBoundary = aws.StringValue(output.Role.PermissionsBoundary.PermissionsBoundaryArn)
+		}
+		for _, tag := range output.Role.Tags {
+			if aws.StringValue(tag.Key) == tags.RoleVersion {
+				role.Version = aws.StringValue(tag.Value)
+			}
+		}
+
+		attached := []*iam.AttachedPolicy{}
+		err = c.iamClient.ListAttachedRolePoliciesPages(&iam.ListAttachedRolePoliciesInput{
+			RoleName: aws.String(roleName),
+		}, func(page *iam.ListAttachedRolePoliciesOutput, lastPage bool) bool {
+			attached = append(attached, page.AttachedPolicies...)
+			return true
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Failed to list policies of role '%s': %v", roleName, err)
+		}
+		for _, item := range attached {
+			policy, err := c.iamClient.GetPolicy(&iam.GetPolicyInput{
+				PolicyArn: item.PolicyArn,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("Failed to get policy '%s': %v", aws.StringValue(item.PolicyArn), err)
+			}
+			role.Policies = append(role.Policies, &AttachedPolicy{
+				Name:           aws.StringValue(item.PolicyName),
+				ARN:            aws.StringValue(item.PolicyArn),
+				DefaultVersion: aws.StringValue(policy.Policy.DefaultVersionId),
+			})
+		}
+		result = append(result, role)
+	}
+	return result, nil
+}
+
+// CreateAccountRole creates the account role of the given type with the given prefix, or updates
+// it if it already exists, and returns its ARN. The permissions of the role are in a managed policy
+// attached to it, and the role is tagged with the type and the version of the policy.
+func (c *awsClient) CreateAccountRole(prefix string, roleType AccountRoleType,
+	settings IAMSettings) (string, error) {
+	document, err := assets.Asset(roleType.PolicyPath)
+	if err != nil {
+		return "", fmt.Errorf("Failed to load policy of role type '%s': %v", roleType.Name, err)
+	}
+	principal := map[string]interface{}{"AWS": []string{roleType.Principal}}
+	if roleType.Service != "" {
+		principal = map[string]interface{}{"Service": []string{roleType.Service}}
+	}
+	trustPolicy, err := trustPolicyDocument(principal, "sts:AssumeRole", nil)
+	if err != nil {
+		return "", err
+	}
+	return c.createRoleWithPolicy(&roleWithPolicy{
+		RoleName:    roleType.RoleName(prefix),
+		TrustPolicy: trustPolicy,
+		PolicyName:  roleType.PolicyName(prefix),
+		Policy:      string(document),
+		Tags: map[string]string{
+			tags.RoleType:    roleType.Name,
+			tags.RoleVersion: AccountRoleVersion,
+		},
+		Settings: settings,
+	})
+}
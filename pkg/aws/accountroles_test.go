@@ -0,0 +1,172 @@
+package aws_test
+
+import (
+	"net/url"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/assets"
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mocks"
+)
+
+var _ = Describe("CreateAccountRole", func() {
+	const (
+		roleARN   = "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"
+		policyARN = "arn:aws:iam::123456789012:policy/ManagedOpenShift-Worker-Role-Policy"
+	)
+
+	var (
+		client   aws.Client
+		mockCtrl *gomock.Controller
+
+		mockIamAPI *mocks.MockIAMAPI
+
+		workerType aws.AccountRoleType
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		var apis *apiMocks
+		client, apis = newClient(mockCtrl, regionSession("us-east-1"))
+		mockIamAPI = apis.IAM
+		for _, roleType := range aws.AccountRoleTypes {
+			if roleType.Name == aws.WorkerRoleType {
+				workerType = roleType
+			}
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("Creates the role, its policy and attaches them", func() {
+		mockIamAPI.EXPECT().CreateRole(gomock.Any()).DoAndReturn(
+			func(input *iam.CreateRoleInput) (*iam.CreateRoleOutput, error) {
+				Expect(*input.RoleName).To(Equal("ManagedOpenShift-Worker-Role"))
+				Expect(*input.AssumeRolePolicyDocument).To(ContainSubstring("ec2.amazonaws.com"))
+				Expect(input.Tags).To(HaveLen(2))
+				return &iam.CreateRoleOutput{Role: &iam.Role{Arn: awssdk.String(roleARN)}}, nil
+			})
+		mockIamAPI.EXPECT().CreatePolicy(gomock.Any()).Return(&iam.CreatePolicyOutput{}, nil)
+		mockIamAPI.EXPECT().AttachRolePolicy(&iam.AttachRolePolicyInput{
+			RoleName:  awssdk.String("ManagedOpenShift-Worker-Role"),
+			PolicyArn: awssdk.String(policyARN),
+		}).Return(&iam.AttachRolePolicyOutput{}, nil)
+
+		arn, err := client.CreateAccountRole(aws.DefaultAccountRolePrefix, workerType, aws.IAMSettings{})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(arn).To(Equal(roleARN))
+	})
+
+	It("Doesn't add policy versions to existing roles that are up to date", func() {
+		exists := awserr.New(iam.ErrCodeEntityAlreadyExistsException, "exists", nil)
+		document := assets.MustAsset(workerType.PolicyPath)
+		mockIamAPI.EXPECT().CreateRole(gomock.Any()).Return(nil, exists)
+		mockIamAPI.EXPECT().UpdateAssumeRolePolicy(gomock.Any()).Return(&iam.UpdateAssumeRolePolicyOutput{}, nil)
+		mockIamAPI.EXPECT().TagRole(gomock.Any()).Return(&iam.TagRoleOutput{}, nil)
+		mockIamAPI.EXPECT().GetRole(gomock.Any()).Return(&iam.GetRoleOutput{
+			Role: &iam.Role{Arn: awssdk.String(roleARN)},
+		}, nil)
+		mockIamAPI.EXPECT().CreatePolicy(gomock.Any()).Return(nil, exists)
+		mockIamAPI.EXPECT().ListPolicyVersions(gomock.Any()).Return(&iam.ListPolicyVersionsOutput{
+			Versions: []*iam.PolicyVersion{{
+				VersionId:        awssdk.String("v1"),
+				IsDefaultVersion: awssdk.Bool(true),
+			}},
+		}, nil)
+		mockIamAPI.EXPECT().GetPolicyVersion(gomock.Any()).Return(&iam.GetPolicyVersionOutput{
+			PolicyVersion: &iam.PolicyVersion{Document: awssdk.String(url.QueryEscape(string(document)))},
+		}, nil)
+		mockIamAPI.EXPECT().AttachRolePolicy(gomock.Any()).Return(&iam.AttachRolePolicyOutput{}, nil)
+
+		arn, err := client.CreateAccountRole(aws.DefaultAccountRolePrefix, workerType, aws.IAMSettings{})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(arn).To(Equal(roleARN))
+	})
+
+	It("Creates the role and its policy with the path and permissions boundary", func() {
+		const (
+			boundary      = "arn:aws:iam::123456789012:policy/Boundary"
+			pathRoleARN   = "arn:aws:iam::123456789012:role/rosa/ManagedOpenShift-Worker-Role"
+			pathPolicyARN = "arn:aws:iam::123456789012:policy/rosa/ManagedOpenShift-Worker-Role-Policy"
+		)
+		mockIamAPI.EXPECT().CreateRole(gomock.Any()).DoAndReturn(
+			func(input *iam.CreateRoleInput) (*iam.CreateRoleOutput, error) {
+				Expect(awssdk.StringValue(input.Path)).To(Equal("/rosa/"))
+				Expect(awssdk.StringValue(input.PermissionsBoundary)).To(Equal(boundary))
+				return &iam.CreateRoleOutput{Role: &iam.Role{
+					Arn:  awssdk.String(pathRoleARN),
+					Path: awssdk.String("/rosa/"),
+				}}, nil
+			})
+		mockIamAPI.EXPECT().CreatePolicy(gomock.Any()).DoAndReturn(
+			func(input *iam.CreatePolicyInput) (*iam.CreatePolicyOutput, error) {
+				Expect(awssdk.StringValue(input.Path)).To(Equal("/rosa/"))
+				return &iam.CreatePolicyOutput{}, nil
+			})
+		mockIamAPI.EXPECT().AttachRolePolicy(&iam.AttachRolePolicyInput{
+			RoleName:  awssdk.String("ManagedOpenShift-Worker-Role"),
+			PolicyArn: awssdk.String(pathPolicyARN),
+		}).Return(&iam.AttachRolePolicyOutput{}, nil)
+
+		arn, err := client.CreateAccountRole(aws.DefaultAccountRolePrefix, workerType, aws.IAMSettings{
+			PermissionsBoundary: boundary,
+			Path:                "/rosa/",
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(arn).To(Equal(pathRoleARN))
+	})
+
+	It("Sets the permissions boundary of existing roles", func() {
+		const boundary = "arn:aws:iam::123456789012:policy/Boundary"
+		exists := awserr.New(iam.ErrCodeEntityAlreadyExistsException, "exists", nil)
+		mockIamAPI.EXPECT().CreateRole(gomock.Any()).Return(nil, exists)
+		mockIamAPI.EXPECT().GetRole(gomock.Any()).Return(&iam.GetRoleOutput{
+			Role: &iam.Role{Arn: awssdk.String(roleARN), Path: awssdk.String("/")},
+		}, nil)
+		mockIamAPI.EXPECT().UpdateAssumeRolePolicy(gomock.Any()).Return(&iam.UpdateAssumeRolePolicyOutput{}, nil)
+		mockIamAPI.EXPECT().TagRole(gomock.Any()).Return(&iam.TagRoleOutput{}, nil)
+		mockIamAPI.EXPECT().PutRolePermissionsBoundary(&iam.PutRolePermissionsBoundaryInput{
+			RoleName:            awssdk.String("ManagedOpenShift-Worker-Role"),
+			PermissionsBoundary: awssdk.String(boundary),
+		}).Return(&iam.PutRolePermissionsBoundaryOutput{}, nil)
+		mockIamAPI.EXPECT().CreatePolicy(gomock.Any()).Return(&iam.CreatePolicyOutput{}, nil)
+		mockIamAPI.EXPECT().AttachRolePolicy(gomock.Any()).Return(&iam.AttachRolePolicyOutput{}, nil)
+
+		_, err := client.CreateAccountRole(aws.DefaultAccountRolePrefix, workerType, aws.IAMSettings{
+			PermissionsBoundary: boundary,
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Fails when an existing role has a different path", func() {
+		exists := awserr.New(iam.ErrCodeEntityAlreadyExistsException, "exists", nil)
+		mockIamAPI.EXPECT().CreateRole(gomock.Any()).Return(nil, exists)
+		mockIamAPI.EXPECT().GetRole(gomock.Any()).Return(&iam.GetRoleOutput{
+			Role: &iam.Role{Arn: awssdk.String(roleARN), Path: awssdk.String("/")},
+		}, nil)
+
+		_, err := client.CreateAccountRole(aws.DefaultAccountRolePrefix, workerType, aws.IAMSettings{
+			Path: "/rosa/",
+		})
+
+		Expect(err).To(MatchError(ContainSubstring("already exists with path '/' instead of '/rosa/'")))
+	})
+
+	It("Truncates the names of the operator roles", func() {
+		for _, operator := range aws.OperatorRoles {
+			Expect(len(operator.RoleName("mycluster-with-long-name"))).To(BeNumerically("<=", 64))
+		}
+	})
+})
@@ -0,0 +1,74 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the support for assuming a role given in the command line, so that commands
+// can work with other AWS accounts using the credentials of the selected profile.
+
+package aws
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+
+	"github.com/openshift/moactl/pkg/aws/assumerole"
+	"github.com/openshift/moactl/pkg/aws/profile"
+)
+
+// assumedRoleCredentials contains the credentials of the roles that have been assumed, indexed by
+// profile and role. Like the credentials of credential processes they are shared by the sessions
+// of all the regions, so that the role is assumed only once, and again when they expire.
+var (
+	assumedRoleCredentials      = map[string]*credentials.Credentials{}
+	assumedRoleCredentialsMutex sync.Mutex
+)
+
+// assumeRole returns the credentials of the role selected in the command line, obtained with the
+// credentials of the given session, or nil if no role was selected.
+func assumeRole(sess *session.Session) (*credentials.Credentials, error) {
+	roleARN := assumerole.RoleARN()
+	if roleARN == "" {
+		return nil, nil
+	}
+	parsed, err := arn.Parse(roleARN)
+	if err != nil || parsed.Service != "iam" {
+		return nil, fmt.Errorf("Role ARN '%s' isn't valid", roleARN)
+	}
+
+	assumedRoleCredentialsMutex.Lock()
+	defer assumedRoleCredentialsMutex.Unlock()
+	key := profile.Profile() + "|" + roleARN
+	creds := assumedRoleCredentials[key]
+	if creds == nil {
+		creds = stscreds.NewCredentials(sess, roleARN, func(provider *stscreds.AssumeRoleProvider) {
+			if assumerole.ExternalID() != "" {
+				provider.ExternalID = aws.String(assumerole.ExternalID())
+			}
+			provider.RoleSessionName = assumerole.SessionName()
+			if provider.RoleSessionName == "" {
+				provider.RoleSessionName = fmt.Sprintf("rosa-%d", time.Now().Unix())
+			}
+		})
+		assumedRoleCredentials[key] = creds
+	}
+	return creds, nil
+}
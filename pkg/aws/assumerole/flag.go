@@ -0,0 +1,73 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to implement the '--assume-role-arn', '--external-id' and
+// '--role-session-name' command line options. The role flag isn't named '--role-arn' because some
+// commands, like 'create cluster', already use that name for the roles of STS clusters.
+
+package assumerole
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// AddFlags adds the flags that select the AWS role to assume to the given set of command line
+// flags.
+func AddFlags(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&roleARN,
+		"assume-role-arn",
+		"",
+		"ARN of an AWS role to assume with the credentials of the profile, to work with another "+
+			"AWS account.",
+	)
+	flags.StringVar(
+		&externalID,
+		"external-id",
+		"",
+		"External ID required by the trust policy of the role given with '--assume-role-arn'.",
+	)
+	flags.StringVar(
+		&sessionName,
+		"role-session-name",
+		"",
+		"Name of the session of the role given with '--assume-role-arn', recorded in CloudTrail. "+
+			"The default is generated.",
+	)
+}
+
+// RoleARN returns the ARN of the role to assume, or an empty string if no role should be assumed.
+func RoleARN() string {
+	return roleARN
+}
+
+// ExternalID returns the external ID used to assume the role.
+func ExternalID() string {
+	return externalID
+}
+
+// SessionName returns the name of the session of the assumed role, or an empty string if it
+// should be generated.
+func SessionName() string {
+	return sessionName
+}
+
+// roleARN, externalID and sessionName are the values of the flags.
+var (
+	roleARN     string
+	externalID  string
+	sessionName string
+)
@@ -3,11 +3,68 @@ package aws_test
 import (
 	"testing"
 
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mocks"
 )
 
 func TestAws(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Aws Suite")
 }
+
+// apiMocks contains the mocks of the AWS APIs used by the clients created with newClient, so that
+// tests can set the expectations of the APIs that they use.
+type apiMocks struct {
+	IAM            *mocks.MockIAMAPI
+	EC2            *mocks.MockEC2API
+	Organizations  *mocks.MockOrganizationsAPI
+	STS            *mocks.MockSTSAPI
+	CloudFormation *mocks.MockCloudFormationAPI
+	ServiceQuotas  *mocks.MockServiceQuotasAPI
+	Route53        *mocks.MockRoute53API
+	S3             *mocks.MockS3API
+	SecretsManager *mocks.MockSecretsManagerAPI
+}
+
+// newClient creates a client that uses mocks of all the AWS APIs and the given session, and
+// returns it together with the mocks.
+func newClient(ctrl *gomock.Controller, awsSession *session.Session) (aws.Client, *apiMocks) {
+	apis := &apiMocks{
+		IAM:            mocks.NewMockIAMAPI(ctrl),
+		EC2:            mocks.NewMockEC2API(ctrl),
+		Organizations:  mocks.NewMockOrganizationsAPI(ctrl),
+		STS:            mocks.NewMockSTSAPI(ctrl),
+		CloudFormation: mocks.NewMockCloudFormationAPI(ctrl),
+		ServiceQuotas:  mocks.NewMockServiceQuotasAPI(ctrl),
+		Route53:        mocks.NewMockRoute53API(ctrl),
+		S3:             mocks.NewMockS3API(ctrl),
+		SecretsManager: mocks.NewMockSecretsManagerAPI(ctrl),
+	}
+	client := aws.New(
+		logrus.New(),
+		apis.IAM,
+		apis.EC2,
+		apis.Organizations,
+		apis.STS,
+		apis.CloudFormation,
+		apis.ServiceQuotas,
+		apis.Route53,
+		apis.S3,
+		apis.SecretsManager,
+		awsSession,
+		&aws.AccessKey{},
+	)
+	return client, apis
+}
+
+// regionSession returns a session that only has the given region.
+func regionSession(region string) *session.Session {
+	return &session.Session{Config: &awssdk.Config{Region: awssdk.String(region)}}
+}
@@ -19,6 +19,7 @@ package aws
 import (
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
@@ -37,15 +38,26 @@ import (
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
 	"github.com/aws/aws-sdk-go/service/organizations"
 	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
 	"github.com/aws/aws-sdk-go/service/servicequotas"
 	"github.com/aws/aws-sdk-go/service/servicequotas/servicequotasiface"
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 	"github.com/sirupsen/logrus"
 
+	"github.com/openshift/moactl/pkg/aws/assumerole"
 	"github.com/openshift/moactl/pkg/aws/profile"
 	"github.com/openshift/moactl/pkg/aws/tags"
+	"github.com/openshift/moactl/pkg/dryrun"
 	"github.com/openshift/moactl/pkg/logging"
+	"github.com/openshift/moactl/pkg/retry"
+	"github.com/openshift/moactl/pkg/simulate"
+	"github.com/openshift/moactl/pkg/tracing"
 )
 
 // Name of the AWS user that will be used to create all the resources of the cluster:
@@ -54,7 +66,8 @@ const (
 	OsdCcsAdminStackName = "osdCcsAdminIAMUser"
 
 	// Since CloudFormation stacks are region-dependent, we hard-code OCM's default region and
-	// then use it to ensure that the user always gets the stack from the same region.
+	// then use it to ensure that the user always gets the stack from the same region. The
+	// GovCloud and China partitions use their own default regions, see GlobalRegion.
 	DefaultRegion = "us-east-1"
 )
 
@@ -62,17 +75,69 @@ const (
 type Client interface {
 	CheckAdminUserNotExisting(userName string) (err error)
 	CheckStackReadyOrNotExisting(stackName string) (stackReady bool, stackStatus *string, err error)
+	GetStackProgress(stackName string) (string, error)
 	GetIAMCredentials() (credentials.Value, error)
 	GetRegion() string
 	ValidateCredentials() (bool, error)
-	EnsureOsdCcsAdminUser(stackName string, adminUserName string) (bool, error)
+	EnsureOsdCcsAdminUser(stackName string, adminUserName string, tags map[string]string,
+		settings IAMSettings) (bool, error)
 	DeleteOsdCcsAdminUser(stackName string) error
+	DetectStackDrift(stackName string) (*StackDrift, error)
+	RepairOsdCcsAdminUser(stackName string, tags map[string]string, settings IAMSettings) (*StackDrift, error)
 	GetAWSAccessKeys() (*AccessKey, error)
+	GetAWSAccessKeysFromSecret(secretARN string) (*AccessKey, error)
+	GetTemporaryAccessKeys(duration time.Duration) (*AccessKey, error)
 	GetCreator() (*Creator, error)
-	TagUser(username string, clusterID string, clusterName string) error
+	GetIdentity() (*Identity, error)
+	TagUser(username string, clusterID string, clusterName string, extraTags map[string]string) error
 	ValidateSCP(*string) (bool, error)
+	SimulatePermissions(principalARN string, actions []string, region string) (map[string]string, error)
+	VerifyPermissions() ([]*MissingPermission, error)
 	GetSubnetIDs() ([]*ec2.Subnet, error)
+	FindSubnets(subnetIDs []string) ([]*ec2.Subnet, error)
+	ValidateSubnets(subnetIDs []string, multiAZ bool, private bool) error
+	ValidatePrivateLinkVPC(subnetIDs []string) error
+	GetSubnetsEgress(subnetIDs []string) ([]*SubnetEgress, error)
+	ValidateSecurityGroups(groupIDs []string, subnetIDs []string, private bool,
+		clusterCIDRs map[string]*net.IPNet) error
+	ValidateIPv6Subnets(subnetIDs []string, machineCIDR *net.IPNet) error
+	GetPrivateLinkEndpointServices(infraID string) ([]*PrivateLinkEndpointService, error)
+	GetPartition() (string, error)
+	GetAvailabilityZones() ([]string, error)
+	ValidateAvailabilityZones(zones []string) error
+	GetLocalZones() ([]string, error)
+	GetInstanceTypeZones() (map[string][]string, error)
+	GetEdgeSubnet(subnetID string) (*EdgeSubnet, error)
+	ValidateEdgeInstanceType(subnet *EdgeSubnet, instanceType string) error
+	GetClusterDNSRecords(domain string) ([]DNSRecord, error)
+	GetClusterInfrastructure(infraID string) (*Infrastructure, error)
+	FindLeftovers(infraID string, clusterID string) ([]*Leftover, error)
+	DeleteLeftover(leftover *Leftover) error
+	GetClusterNetwork(infraID string, subnetIDs []string) (*Network, error)
+	FindVPCClusters(subnetIDs []string) ([]*VPCCluster, error)
+	ValidateOIDCBucket(bucketName string) (exists bool, err error)
+	CreateOIDCBucket(bucketName string) error
+	PutOIDCBucketPolicy(bucketName string) error
+	UploadOIDCDocuments(bucketName string, discovery []byte, jwks []byte) error
+	CreateOIDCPrivateKeySecret(name string, privateKey []byte) (string, error)
+	ValidateRoleARN(roleARN string, trustedPrincipal string) error
+	GetAccountRoles(prefix string) ([]*AccountRole, error)
+	CreateAccountRole(prefix string, roleType AccountRoleType, settings IAMSettings) (string, error)
+	CreateOperatorRole(operator OperatorRole, roleName string, clusterID string, oidcProviderARN string,
+		issuerURL string, settings IAMSettings) (string, error)
+	GetOIDCProvider(issuerURL string) (string, error)
+	CreateOIDCProvider(issuerURL string, thumbprint string) (string, error)
+	HasELBServiceLinkedRole() (bool, error)
+	CreateELBServiceLinkedRole() error
+	ValidateTags(tags map[string]string) (warnings []string, err error)
 	ValidateQuota() (bool, error)
+	CheckQuotas() ([]*QuotaCheck, error)
+	GetLimitUsage() ([]*LimitUsage, error)
+	GetClusterQuotaUsage(plan *ClusterPlan) ([]*QuotaUsage, error)
+	GetGPUInfo(instanceType string) (*GPUInfo, error)
+	GetInstancePrices(instanceTypes []string) (map[string]float64, error)
+	GetVolumePrice(volumeType string) (float64, error)
+	ValidateGPUQuota(info *GPUInfo, replicas int) error
 }
 
 // ClientBuilder contains the information and logic needed to build a new AWS client.
@@ -90,6 +155,9 @@ type awsClient struct {
 	stsClient           stsiface.STSAPI
 	cfClient            cloudformationiface.CloudFormationAPI
 	servicequotasClient servicequotasiface.ServiceQuotasAPI
+	route53Client       route53iface.Route53API
+	s3Client            s3iface.S3API
+	secretsClient       secretsmanageriface.SecretsManagerAPI
 	awsSession          *session.Session
 	awsAccessKeys       *AccessKey
 }
@@ -107,6 +175,9 @@ func New(
 	stsClient stsiface.STSAPI,
 	cfClient cloudformationiface.CloudFormationAPI,
 	servicequotasClient servicequotasiface.ServiceQuotasAPI,
+	route53Client route53iface.Route53API,
+	s3Client s3iface.S3API,
+	secretsClient secretsmanageriface.SecretsManagerAPI,
 	awsSession *session.Session,
 	awsAccessKeys *AccessKey,
 
@@ -119,6 +190,9 @@ func New(
 		stsClient,
 		cfClient,
 		servicequotasClient,
+		route53Client,
+		s3Client,
+		secretsClient,
 		awsSession,
 		awsAccessKeys,
 	}
@@ -152,6 +226,8 @@ func (b *ClientBuilder) BuildSessionWithOptionsCredentials(value *AccessKey) (*s
 	})
 }
 
+// Create AWS session using the shared configuration of the selected profile. If the credentials of
+// the profile were already obtained running its 'credential_process' they are reused.
 func (b *ClientBuilder) BuildSessionWithOptions() (*session.Session, error) {
 	return session.NewSessionWithOptions(session.Options{
 		SharedConfigState: session.SharedConfigEnable,
@@ -159,6 +235,7 @@ func (b *ClientBuilder) BuildSessionWithOptions() (*session.Session, error) {
 		Config: aws.Config{
 			CredentialsChainVerboseErrors: aws.Bool(true),
 			Region:                        b.region,
+			Credentials:                   cachedProcessCredentials(profile.Profile()),
 		},
 	})
 }
@@ -170,6 +247,9 @@ func (b *ClientBuilder) Build() (Client, error) {
 		return nil, fmt.Errorf("Logger is mandatory")
 	}
 
+	// The AWS SDK is noisy, so the level of its messages can be selected separately:
+	b.logger = logging.Subsystem(b.logger, logging.AWS)
+
 	// Create the AWS logger:
 	logger, err := logging.NewAWSLogger().
 		Logger(b.logger).
@@ -178,6 +258,25 @@ func (b *ClientBuilder) Build() (Client, error) {
 		return nil, err
 	}
 
+	// The simulation mode uses the simulated account instead of the credentials of the
+	// environment:
+	if simulate.Enabled() {
+		b.credentials = &AccessKey{
+			AccessKeyID:     simulate.AWSAccessKeyID,
+			SecretAccessKey: simulate.AWSSecretAccessKey,
+		}
+		if b.region == nil {
+			b.region = aws.String(simulate.Region)
+		}
+	}
+
+	// Use the region of the profile when the command doesn't select one:
+	if b.region == nil {
+		if region := profileRegion(); region != "" {
+			b.region = aws.String(region)
+		}
+	}
+
 	var sess *session.Session
 
 	// Create the AWS session:
@@ -194,12 +293,39 @@ func (b *ClientBuilder) Build() (Client, error) {
 		b.logger.Debugf("Using AWS profile: %s", profile.Profile())
 	}
 
+	// Assume the role selected in the command line, if any. The explicit credentials are those of
+	// the osdCcsAdmin user, and they are used as they are:
+	if b.credentials == nil {
+		var creds *credentials.Credentials
+		creds, err = assumeRole(sess)
+		if err != nil {
+			return nil, err
+		}
+		if creds != nil {
+			b.logger.Debugf("Assuming AWS role: %s", assumerole.RoleARN())
+			sess = sess.Copy(&aws.Config{
+				Credentials: creds,
+			})
+		}
+	}
+
 	// Check that the AWS credentials are available:
-	_, err = sess.Config.Credentials.Get()
+	value, err := sess.Config.Credentials.Get()
 	if err != nil {
 		b.logger.Debugf("Failed to find credentials: %v", err)
+		if message := processCredentialsError(err); message != "" {
+			return nil, fmt.Errorf("%s", message)
+		}
+		if b.credentials == nil && assumerole.RoleARN() != "" {
+			return nil, fmt.Errorf("Failed to assume role '%s'. Check that the trust policy of the "+
+				"role allows it, and the external ID if it requires one", assumerole.RoleARN())
+		}
 		return nil, fmt.Errorf("Failed to find credentials. Check your AWS configuration and try again")
 	}
+	if b.credentials == nil {
+		cacheProcessCredentials(profile.Profile(), sess.Config.Credentials, value)
+	}
+	b.logger.Debugf("Using AWS credentials from '%s'", value.ProviderName)
 
 	// Check that the region is set:
 	region := aws.StringValue(sess.Config.Region)
@@ -209,16 +335,22 @@ func (b *ClientBuilder) Build() (Client, error) {
 
 	// Update session config
 	sess = sess.Copy(&aws.Config{
-		// MaxRetries to limit the number of attempts on failed API calls
-		MaxRetries: aws.Int(25),
-		// Set MinThrottleDelay to 1 second
+		// Throttling and server errors are retried with the same policy used for OCM, selected
+		// with the '--max-retries' flag. The retryer also respects the 'Retry-After' header:
+		MaxRetries: aws.Int(retry.MaxRetries()),
 		Retryer: client.DefaultRetryer{
-			NumMaxRetries:    5,
-			MinThrottleDelay: 1 * time.Second,
+			NumMaxRetries:    retry.MaxRetries(),
+			MinRetryDelay:    retry.BaseDelay,
+			MinThrottleDelay: retry.BaseDelay,
+			MaxRetryDelay:    retry.MaxDelay,
+			MaxThrottleDelay: retry.MaxDelay,
 		},
 		Logger: logger,
+		// The default transport uses the proxy selected with the environment and trusts the
+		// certificate authorities of '--ca-file':
 		HTTPClient: &http.Client{
 			Transport: http.DefaultTransport,
+			Timeout:   retry.RequestTimeout(),
 		},
 	})
 
@@ -234,6 +366,15 @@ func (b *ClientBuilder) Build() (Client, error) {
 		sess.Config.HTTPClient.Transport = dumper
 	}
 
+	// Record a span for each call, in case traces are exported:
+	tracing.AddAWSHandlers(&sess.Handlers)
+
+	// Print instead of sending the calls that change resources, in dry run mode:
+	dryrun.AddAWSHandlers(&sess.Handlers)
+
+	// Answer all the calls with canned results, in simulation mode:
+	simulate.AddAWSHandlers(&sess.Handlers)
+
 	// Create and populate the object:
 	c := &awsClient{
 		logger:              b.logger,
@@ -243,6 +384,9 @@ func (b *ClientBuilder) Build() (Client, error) {
 		stsClient:           sts.New(sess),
 		cfClient:            cloudformation.New(sess),
 		servicequotasClient: servicequotas.New(sess),
+		route53Client:       route53.New(sess),
+		s3Client:            s3.New(sess),
+		secretsClient:       secretsmanager.New(sess),
 		awsSession:          sess,
 	}
 
@@ -275,6 +419,31 @@ func (c *awsClient) GetSubnetIDs() ([]*ec2.Subnet, error) {
 	return res.Subnets, nil
 }
 
+// GetAvailabilityZones returns the names of the availability zones of the region of the client.
+// Local Zones aren't included, see GetLocalZones.
+func (c *awsClient) GetAvailabilityZones() ([]string, error) {
+	res, err := c.ec2Client.DescribeAvailabilityZones(&ec2.DescribeAvailabilityZonesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("state"),
+				Values: []*string{aws.String("available")},
+			},
+			{
+				Name:   aws.String("opt-in-status"),
+				Values: []*string{aws.String(ec2.AvailabilityZoneOptInStatusOptInNotRequired)},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	zones := make([]string, 0, len(res.AvailabilityZones))
+	for _, zone := range res.AvailabilityZones {
+		zones = append(zones, aws.StringValue(zone.ZoneName))
+	}
+	return zones, nil
+}
+
 type Creator struct {
 	ARN       string
 	AccountID string
@@ -307,8 +476,10 @@ func (c *awsClient) ValidateCredentials() (bool, error) {
 	return true, nil
 }
 
-// Ensure osdCcsAdmin IAM user is created
-func (c *awsClient) EnsureOsdCcsAdminUser(stackName string, adminUserName string) (bool, error) {
+// Ensure osdCcsAdmin IAM user is created, with the given tags added to the stack and the user, and
+// with the given permissions boundary and path
+func (c *awsClient) EnsureOsdCcsAdminUser(stackName string, adminUserName string,
+	tags map[string]string, settings IAMSettings) (bool, error) {
 	// Check already existing cloudformation stack status
 	stackReady, stackStatus, err := c.CheckStackReadyOrNotExisting(stackName)
 	if err != nil {
@@ -326,7 +497,12 @@ func (c *awsClient) EnsureOsdCcsAdminUser(stackName string, adminUserName string
 	if stackStatus != nil {
 		if (*stackStatus == cloudformation.StackStatusCreateComplete) ||
 			(*stackStatus == cloudformation.StackStatusUpdateComplete) {
-			_, err = c.UpdateStack(cfTemplateBody, stackName)
+			stack, err := c.describeStack(stackName)
+			if err != nil {
+				return false, err
+			}
+			_, err = c.UpdateStack(cfTemplateBody, stackName, tags,
+				settings.stackParameters(stackParameterValues(stack)))
 			if err != nil {
 				return false, err
 			}
@@ -345,7 +521,7 @@ func (c *awsClient) EnsureOsdCcsAdminUser(stackName string, adminUserName string
 	}
 
 	// Create stack
-	_, err = c.CreateStack(cfTemplateBody, stackName)
+	_, err = c.CreateStack(cfTemplateBody, stackName, tags, settings.stackParameters(nil))
 	if err != nil {
 		return false, err
 	}
@@ -353,13 +529,19 @@ func (c *awsClient) EnsureOsdCcsAdminUser(stackName string, adminUserName string
 	return true, nil
 }
 
-func (c *awsClient) CreateStack(cfTemplateBody, stackName string) (bool, error) {
+func (c *awsClient) CreateStack(cfTemplateBody, stackName string, tags map[string]string,
+	parameters []*cloudformation.Parameter) (bool, error) {
 	// Create cloudformation stack
-	_, err := c.cfClient.CreateStack(buildCreateStackInput(cfTemplateBody, stackName))
+	_, err := c.cfClient.CreateStack(buildCreateStackInput(cfTemplateBody, stackName, tags, parameters))
 	if err != nil {
 		return false, err
 	}
 
+	// Nothing was created in dry run mode, so there is nothing to wait for:
+	if dryrun.Enabled() {
+		return true, nil
+	}
+
 	// Wait until cloudformation stack creates
 	err = c.cfClient.WaitUntilStackCreateComplete(&cloudformation.DescribeStacksInput{
 		StackName: aws.String(stackName),
@@ -379,8 +561,9 @@ func (c *awsClient) CreateStack(cfTemplateBody, stackName string) (bool, error)
 	return true, nil
 }
 
-func (c *awsClient) UpdateStack(cfTemplateBody, stackName string) (bool, error) {
-	_, err := c.cfClient.UpdateStack(buildUpdateStackInput(cfTemplateBody, stackName))
+func (c *awsClient) UpdateStack(cfTemplateBody, stackName string, tags map[string]string,
+	parameters []*cloudformation.Parameter) (bool, error) {
+	_, err := c.cfClient.UpdateStack(buildUpdateStackInput(cfTemplateBody, stackName, tags, parameters))
 	if err != nil {
 		switch typed := err.(type) {
 		case awserr.Error:
@@ -394,6 +577,11 @@ func (c *awsClient) UpdateStack(cfTemplateBody, stackName string) (bool, error)
 		return false, err
 	}
 
+	// Nothing was updated in dry run mode, so there is nothing to wait for:
+	if dryrun.Enabled() {
+		return true, nil
+	}
+
 	// Wait for CloudFormation update to complete
 	err = c.cfClient.WaitUntilStackUpdateComplete(&cloudformation.DescribeStacksInput{
 		StackName: aws.String(stackName),
@@ -438,6 +626,24 @@ func (c *awsClient) CheckStackReadyOrNotExisting(stackName string) (stackReady b
 	return false, nil, nil
 }
 
+// GetStackProgress returns a description of the latest event of the given stack, like the resource
+// that is being created, or an empty string if there are no events yet.
+func (c *awsClient) GetStackProgress(stackName string) (string, error) {
+	output, err := c.cfClient.DescribeStackEvents(&cloudformation.DescribeStackEventsInput{
+		StackName: aws.String(stackName),
+	})
+	if err != nil {
+		return "", err
+	}
+	// Events are returned in reverse chronological order:
+	if len(output.StackEvents) == 0 {
+		return "", nil
+	}
+	event := output.StackEvents[0]
+	return fmt.Sprintf("%s %s '%s'", aws.StringValue(event.ResourceStatus),
+		aws.StringValue(event.ResourceType), aws.StringValue(event.LogicalResourceId)), nil
+}
+
 func (c *awsClient) CheckAdminUserNotExisting(userName string) (err error) {
 	userList, err := c.iamClient.ListUsers(&iam.ListUsersInput{})
 	if err != nil {
@@ -459,8 +665,22 @@ func (c *awsClient) DeleteOsdCcsAdminUser(stackName string) error {
 		StackName: aws.String(stackName),
 	}
 
+	// CloudFormation can't delete the user while it has access keys, and the user may have been
+	// deleted already outside of CloudFormation
+	err := c.DeleteAccessKeys(AdminUserName)
+	if err != nil {
+		switch typed := err.(type) {
+		case awserr.Error:
+			if typed.Code() != iam.ErrCodeNoSuchEntityException {
+				return err
+			}
+		default:
+			return err
+		}
+	}
+
 	// Delete cloudformation stack
-	_, err := c.cfClient.DeleteStack(deleteStackInput)
+	_, err = c.cfClient.DeleteStack(deleteStackInput)
 	if err != nil {
 		switch typed := err.(type) {
 		case awserr.Error:
@@ -471,6 +691,11 @@ func (c *awsClient) DeleteOsdCcsAdminUser(stackName string) error {
 		return err
 	}
 
+	// Nothing was deleted in dry run mode, so there is nothing to wait for:
+	if dryrun.Enabled() {
+		return nil
+	}
+
 	// Wait until cloudformation stack deletes
 	err = c.cfClient.WaitUntilStackDeleteComplete(&cloudformation.DescribeStacksInput{
 		StackName: aws.String(stackName),
@@ -492,19 +717,27 @@ func (c *awsClient) DeleteOsdCcsAdminUser(stackName string) error {
 
 // FIXME: Since we support multiple clusters per user, we need to find a better way to
 // tag the user so that the tags don't overwrite each other with each new cluster.
-func (c *awsClient) TagUser(username string, clusterID string, clusterName string) error {
+func (c *awsClient) TagUser(username string, clusterID string, clusterName string,
+	extraTags map[string]string) error {
+	userTags := []*iam.Tag{
+		{
+			Key:   aws.String(tags.ClusterID),
+			Value: aws.String(clusterID),
+		},
+		{
+			Key:   aws.String(tags.ClusterName),
+			Value: aws.String(clusterName),
+		},
+	}
+	for _, tag := range buildStackTags(extraTags) {
+		userTags = append(userTags, &iam.Tag{
+			Key:   tag.Key,
+			Value: tag.Value,
+		})
+	}
 	_, err := c.iamClient.TagUser(&iam.TagUserInput{
 		UserName: aws.String(username),
-		Tags: []*iam.Tag{
-			{
-				Key:   aws.String(tags.ClusterID),
-				Value: aws.String(clusterID),
-			},
-			{
-				Key:   aws.String(tags.ClusterName),
-				Value: aws.String(clusterName),
-			},
-		},
+		Tags:     userTags,
 	})
 	if err != nil {
 		return err
@@ -515,6 +748,10 @@ func (c *awsClient) TagUser(username string, clusterID string, clusterName strin
 type AccessKey struct {
 	AccessKeyID     string
 	SecretAccessKey string
+
+	// SessionToken and Expiration are only set for temporary credentials.
+	SessionToken string
+	Expiration   time.Time
 }
 
 // GetAWSAccessKeys uses UpsertAccessKey to delete and create new access keys
@@ -561,7 +798,7 @@ func (c *awsClient) ValidateAccessKeys(AccessKey *AccessKey) error {
 		// Create the AWS client
 		_, err := NewClient().
 			Logger(logger).
-			Region(DefaultRegion).
+			Region(GlobalRegion()).
 			AccessKeys(AccessKey).
 			Build()
 
@@ -665,29 +902,17 @@ func (c *awsClient) DeleteAccessKeys(username string) error {
 	return nil
 }
 
-// ValidateQuota
+// ValidateQuota checks that the service quotas of the region of the client are sufficient to
+// install clusters. If they aren't the returned error is a *QuotasError.
 func (c *awsClient) ValidateQuota() (bool, error) {
-	for _, quota := range serviceQuotaServices {
-		serviceQuotas, err := ListServiceQuotas(c, quota.ServiceCode)
-		if err != nil {
-			return false, fmt.Errorf("Error listing AWS service quotas: %s %v", quota.ServiceCode, err)
-		}
-
-		serviceQuota, err := GetServiceQuota(serviceQuotas, quota.QuotaCode)
-		if err != nil || serviceQuota == nil || (*serviceQuota).Value == nil {
-			return false, fmt.Errorf("Error getting AWS service quota: %s %v", quota.ServiceCode, err)
-		}
-
-		if *serviceQuota.Value < *quota.DesiredValue {
-			return false, fmt.Errorf(
-				"Service %s quota code %s %s not valid, expected quota of at least %d, but got %d",
-				quota.ServiceCode, quota.QuotaCode, quota.QuotaName,
-				int(*quota.DesiredValue), int(*serviceQuota.Value))
-		}
-
-		c.logger.Debug(fmt.Sprintf("Service %s quota code %s is ok", quota.ServiceCode, quota.QuotaCode))
+	checks, err := c.CheckQuotas()
+	if err != nil {
+		return false, err
+	}
+	insufficient := InsufficientQuotas(checks)
+	if len(insufficient) > 0 {
+		return false, &QuotasError{Checks: insufficient}
 	}
-
 	return true, nil
 }
 
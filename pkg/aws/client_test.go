@@ -1,13 +1,13 @@
 package aws_test
 
 import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/moactl/pkg/aws"
 	"github.com/openshift/moactl/pkg/aws/mocks"
@@ -18,27 +18,16 @@ var _ = Describe("Client", func() {
 		client   aws.Client
 		mockCtrl *gomock.Controller
 
-		mockEC2API *mocks.MockEC2API
 		mockCfAPI  *mocks.MockCloudFormationAPI
 		mockIamAPI *mocks.MockIAMAPI
 	)
 
 	BeforeEach(func() {
 		mockCtrl = gomock.NewController(GinkgoT())
-		mockCfAPI = mocks.NewMockCloudFormationAPI(mockCtrl)
-		mockIamAPI = mocks.NewMockIAMAPI(mockCtrl)
-		mockEC2API = mocks.NewMockEC2API(mockCtrl)
-		client = aws.New(
-			logrus.New(),
-			mockIamAPI,
-			mockEC2API,
-			mocks.NewMockOrganizationsAPI(mockCtrl),
-			mocks.NewMockSTSAPI(mockCtrl),
-			mockCfAPI,
-			mocks.NewMockServiceQuotasAPI(mockCtrl),
-			&session.Session{},
-			&aws.AccessKey{},
-		)
+		var apis *apiMocks
+		client, apis = newClient(mockCtrl, &session.Session{})
+		mockIamAPI = apis.IAM
+		mockCfAPI = apis.CloudFormation
 	})
 
 	AfterEach(func() {
@@ -70,11 +59,27 @@ var _ = Describe("Client", func() {
 			Context("When stack is in CREATE_COMPLETE state", func() {
 				BeforeEach(func() {
 					stackStatus = cloudformation.StackStatusCreateComplete
-					mockCfAPI.EXPECT().UpdateStack(gomock.Any()).Return(nil, nil)
+					mockCfAPI.EXPECT().DescribeStacks(gomock.Any()).Return(&cloudformation.DescribeStacksOutput{
+						Stacks: []*cloudformation.Stack{{
+							Parameters: []*cloudformation.Parameter{{
+								ParameterKey:   awssdk.String("Path"),
+								ParameterValue: awssdk.String("/rosa/"),
+							}},
+						}},
+					}, nil)
+					mockCfAPI.EXPECT().UpdateStack(gomock.Any()).DoAndReturn(
+						func(input *cloudformation.UpdateStackInput) (*cloudformation.UpdateStackOutput, error) {
+							// The path isn't given, so the stack keeps the one it has:
+							Expect(input.Parameters).To(ConsistOf(&cloudformation.Parameter{
+								ParameterKey:   awssdk.String("Path"),
+								ParameterValue: awssdk.String("/rosa/"),
+							}))
+							return nil, nil
+						})
 					mockCfAPI.EXPECT().WaitUntilStackUpdateComplete(gomock.Any()).Return(nil)
 				})
 				It("Returns without error", func() {
-					stackCreated, err := client.EnsureOsdCcsAdminUser(stackName, adminUserName)
+					stackCreated, err := client.EnsureOsdCcsAdminUser(stackName, adminUserName, nil, aws.IAMSettings{})
 
 					Expect(stackCreated).To(BeFalse())
 					Expect(err).NotTo(HaveOccurred())
@@ -89,7 +94,7 @@ var _ = Describe("Client", func() {
 					mockCfAPI.EXPECT().WaitUntilStackCreateComplete(gomock.Any()).Return(nil)
 				})
 				It("Creates a cloudformation stack", func() {
-					stackCreated, err := client.EnsureOsdCcsAdminUser(stackName, adminUserName)
+					stackCreated, err := client.EnsureOsdCcsAdminUser(stackName, adminUserName, nil, aws.IAMSettings{})
 
 					Expect(stackCreated).To(BeTrue())
 					Expect(err).NotTo(HaveOccurred())
@@ -102,7 +107,7 @@ var _ = Describe("Client", func() {
 				})
 
 				It("Returns error telling the stack is in an invalid state", func() {
-					stackCreated, err := client.EnsureOsdCcsAdminUser(stackName, adminUserName)
+					stackCreated, err := client.EnsureOsdCcsAdminUser(stackName, adminUserName, nil, aws.IAMSettings{})
 
 					Expect(stackCreated).To(BeFalse())
 					Expect(err).To(HaveOccurred())
@@ -123,7 +128,7 @@ var _ = Describe("Client", func() {
 			})
 
 			It("Creates a cloudformation stack", func() {
-				stackCreated, err := client.EnsureOsdCcsAdminUser(stackName, adminUserName)
+				stackCreated, err := client.EnsureOsdCcsAdminUser(stackName, adminUserName, nil, aws.IAMSettings{})
 
 				Expect(err).NotTo(HaveOccurred())
 				Expect(stackCreated).To(BeTrue())
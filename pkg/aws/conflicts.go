@@ -0,0 +1,114 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that detect other clusters using the VPC where a new cluster
+// will be installed.
+
+package aws
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// VPCCluster is another cluster found in the VPC of the subnets where a new cluster will be
+// installed.
+type VPCCluster struct {
+	// InfraID is the infrastructure identifier of the cluster, taken from its tags.
+	InfraID string
+
+	// SharedSubnets are the given subnets that are already tagged for the cluster.
+	SharedSubnets []string
+
+	// OwnedSubnets are the given subnets that were created by the installer of the cluster, so
+	// they will be deleted when the cluster is deleted.
+	OwnedSubnets []string
+
+	// LoadBalancers are the names of the load balancers of the cluster in the VPC.
+	LoadBalancers []string
+}
+
+// FindVPCClusters finds the clusters that already use the VPCs of the given subnets, either because
+// the subnets are tagged for them or because they have load balancers in those VPCs. The result is
+// sorted by infrastructure identifier.
+func (c *awsClient) FindVPCClusters(subnetIDs []string) ([]*VPCCluster, error) {
+	if len(subnetIDs) == 0 {
+		return nil, nil
+	}
+	subnets, err := c.ec2Client.DescribeSubnets(&ec2.DescribeSubnetsInput{
+		SubnetIds: aws.StringSlice(subnetIDs),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to describe subnets: %v", err)
+	}
+
+	clusters := map[string]*VPCCluster{}
+	clusterFor := func(infraID string) *VPCCluster {
+		cluster, ok := clusters[infraID]
+		if !ok {
+			cluster = &VPCCluster{InfraID: infraID}
+			clusters[infraID] = cluster
+		}
+		return cluster
+	}
+
+	vpcIDs := map[string]bool{}
+	for _, subnet := range subnets.Subnets {
+		subnetID := aws.StringValue(subnet.SubnetId)
+		vpcIDs[aws.StringValue(subnet.VpcId)] = true
+		for _, tag := range subnet.Tags {
+			key := aws.StringValue(tag.Key)
+			if !strings.HasPrefix(key, clusterTagPrefix) {
+				continue
+			}
+			cluster := clusterFor(strings.TrimPrefix(key, clusterTagPrefix))
+			if aws.StringValue(tag.Value) == "owned" {
+				cluster.OwnedSubnets = append(cluster.OwnedSubnets, subnetID)
+			} else {
+				cluster.SharedSubnets = append(cluster.SharedSubnets, subnetID)
+			}
+		}
+	}
+
+	balancers, err := c.listLoadBalancers()
+	if err != nil {
+		return nil, err
+	}
+	for _, balancer := range balancers {
+		if !vpcIDs[balancer.vpcID] {
+			continue
+		}
+		for key := range balancer.tags {
+			if strings.HasPrefix(key, clusterTagPrefix) {
+				cluster := clusterFor(strings.TrimPrefix(key, clusterTagPrefix))
+				cluster.LoadBalancers = append(cluster.LoadBalancers, balancer.Name)
+			}
+		}
+	}
+
+	result := make([]*VPCCluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		result = append(result, cluster)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].InfraID < result[j].InfraID
+	})
+	return result, nil
+}
@@ -0,0 +1,155 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that keep the access keys of the admin user in AWS Secrets
+// Manager, so that they are reused instead of being replaced each time a cluster is created.
+
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+)
+
+// credentialsSecret is the content of the secret that contains the access keys. The names of the
+// fields are the same that the AWS configuration files use.
+type credentialsSecret struct {
+	AccessKeyID     string `json:"aws_access_key_id"`
+	SecretAccessKey string `json:"aws_secret_access_key"`
+}
+
+// ValidateCredentialsSecretARN checks that the given ARN is the ARN of a secret of AWS Secrets
+// Manager.
+func ValidateCredentialsSecretARN(secretARN string) error {
+	parsed, err := arn.Parse(secretARN)
+	if err != nil {
+		return fmt.Errorf("Secret ARN '%s' isn't valid: %v", secretARN, err)
+	}
+	if parsed.Service != secretsmanager.ServiceName {
+		return fmt.Errorf("Secret ARN '%s' isn't valid: it isn't the ARN of a secret of AWS Secrets Manager",
+			secretARN)
+	}
+	return nil
+}
+
+// GetAWSAccessKeysFromSecret returns the access keys of the admin user stored in the given secret.
+// If the secret doesn't contain keys yet, or they are no longer active, the keys are replaced as
+// GetAWSAccessKeys does and the new keys are stored in the secret, so that the secret always
+// contains the current keys.
+func (c *awsClient) GetAWSAccessKeysFromSecret(secretARN string) (*AccessKey, error) {
+	if c.awsAccessKeys != nil {
+		return c.awsAccessKeys, nil
+	}
+	client, err := c.secretsClientFor(secretARN)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.GetSecretValue(&secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretARN),
+	})
+	if err != nil {
+		// A secret without any value, as created without '--secret-string', is reported as not
+		// found as well, so check if the secret itself exists:
+		aerr, ok := err.(awserr.Error)
+		if !ok || aerr.Code() != secretsmanager.ErrCodeResourceNotFoundException {
+			return nil, fmt.Errorf("Failed to read secret '%s': %v", secretARN, err)
+		}
+		_, err = client.DescribeSecret(&secretsmanager.DescribeSecretInput{
+			SecretId: aws.String(secretARN),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read secret '%s': %v", secretARN, err)
+		}
+	} else {
+		stored := &credentialsSecret{}
+		err = json.Unmarshal([]byte(aws.StringValue(output.SecretString)), stored)
+		if err == nil && stored.AccessKeyID != "" {
+			active, err := c.isActiveAccessKey(AdminUserName, stored.AccessKeyID)
+			if err != nil {
+				return nil, err
+			}
+			if active {
+				c.logger.Debug(fmt.Sprintf("Using access key '%s' from secret '%s'",
+					stored.AccessKeyID, secretARN))
+				c.awsAccessKeys = &AccessKey{
+					AccessKeyID:     stored.AccessKeyID,
+					SecretAccessKey: stored.SecretAccessKey,
+				}
+				return c.awsAccessKeys, nil
+			}
+		}
+		c.logger.Debug(fmt.Sprintf("Secret '%s' doesn't contain an active access key", secretARN))
+	}
+
+	accessKey, err := c.GetAWSAccessKeys()
+	if err != nil {
+		return nil, err
+	}
+	value, err := json.Marshal(&credentialsSecret{
+		AccessKeyID:     accessKey.AccessKeyID,
+		SecretAccessKey: accessKey.SecretAccessKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	_, err = client.PutSecretValue(&secretsmanager.PutSecretValueInput{
+		SecretId:     aws.String(secretARN),
+		SecretString: aws.String(string(value)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to store access keys in secret '%s': %v", secretARN, err)
+	}
+	c.logger.Debug(fmt.Sprintf("Stored access key '%s' in secret '%s'", accessKey.AccessKeyID, secretARN))
+	return accessKey, nil
+}
+
+// secretsClientFor returns a client for the region of the given secret, which may be different to
+// the region of this client.
+func (c *awsClient) secretsClientFor(secretARN string) (secretsmanageriface.SecretsManagerAPI, error) {
+	err := ValidateCredentialsSecretARN(secretARN)
+	if err != nil {
+		return nil, err
+	}
+	parsed, _ := arn.Parse(secretARN)
+	if parsed.Region == c.GetRegion() {
+		return c.secretsClient, nil
+	}
+	return secretsmanager.New(c.awsSession, aws.NewConfig().WithRegion(parsed.Region)), nil
+}
+
+// isActiveAccessKey checks if the given access key belongs to the given user and is active.
+func (c *awsClient) isActiveAccessKey(username string, accessKeyID string) (bool, error) {
+	output, err := c.iamClient.ListAccessKeys(&iam.ListAccessKeysInput{
+		UserName: aws.String(username),
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, key := range output.AccessKeyMetadata {
+		if aws.StringValue(key.AccessKeyId) == accessKeyID {
+			return aws.StringValue(key.Status) == iam.StatusTypeActive, nil
+		}
+	}
+	return false, nil
+}
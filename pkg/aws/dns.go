@@ -0,0 +1,107 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+)
+
+// DNSRecord is a record of one of the Route53 hosted zones of a cluster.
+type DNSRecord struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Values  []string `json:"values"`
+	Zone    string   `json:"zone"`
+	Private bool     `json:"private"`
+}
+
+// GetClusterDNSRecords returns the records of the Route53 hosted zones created for the given cluster
+// domain, for example 'mycluster.a1b2.p1.openshiftapps.com'. The NS and SOA records that Route53
+// creates for every zone are not included.
+func (c *awsClient) GetClusterDNSRecords(domain string) ([]DNSRecord, error) {
+	zoneName := strings.TrimSuffix(domain, ".") + "."
+	zones, err := c.getHostedZonesByName(zoneName)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []DNSRecord{}
+	for _, zone := range zones {
+		private := zone.Config != nil && aws.BoolValue(zone.Config.PrivateZone)
+		err = c.route53Client.ListResourceRecordSetsPages(&route53.ListResourceRecordSetsInput{
+			HostedZoneId: zone.Id,
+		}, func(page *route53.ListResourceRecordSetsOutput, lastPage bool) bool {
+			for _, set := range page.ResourceRecordSets {
+				recordType := aws.StringValue(set.Type)
+				if recordType == route53.RRTypeNs || recordType == route53.RRTypeSoa {
+					continue
+				}
+				values := []string{}
+				for _, record := range set.ResourceRecords {
+					values = append(values, aws.StringValue(record.Value))
+				}
+				if set.AliasTarget != nil {
+					values = append(values, aws.StringValue(set.AliasTarget.DNSName))
+				}
+				records = append(records, DNSRecord{
+					Name:    strings.ReplaceAll(aws.StringValue(set.Name), `\052`, "*"),
+					Type:    recordType,
+					Values:  values,
+					Zone:    aws.StringValue(zone.Name),
+					Private: private,
+				})
+			}
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+// getHostedZonesByName returns the public and private hosted zones that have the given name. The
+// zones are listed sorted by name starting with the given one, so the pages are read until a zone
+// with a different name is found.
+func (c *awsClient) getHostedZonesByName(zoneName string) ([]*route53.HostedZone, error) {
+	zones := []*route53.HostedZone{}
+	input := &route53.ListHostedZonesByNameInput{
+		DNSName: aws.String(zoneName),
+	}
+	for {
+		output, err := c.route53Client.ListHostedZonesByName(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, zone := range output.HostedZones {
+			if aws.StringValue(zone.Name) != zoneName {
+				return zones, nil
+			}
+			zones = append(zones, zone)
+		}
+		if !aws.BoolValue(output.IsTruncated) {
+			return zones, nil
+		}
+		input = &route53.ListHostedZonesByNameInput{
+			DNSName:      output.NextDNSName,
+			HostedZoneId: output.NextHostedZoneId,
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package aws_test
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mocks"
+)
+
+var _ = Describe("GetClusterDNSRecords", func() {
+	var (
+		client         aws.Client
+		mockCtrl       *gomock.Controller
+		mockRoute53API *mocks.MockRoute53API
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		var apis *apiMocks
+		client, apis = newClient(mockCtrl, &session.Session{})
+		mockRoute53API = apis.Route53
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("Reads the zones of the cluster from all the pages", func() {
+		const zoneName = "mycluster.a1b2.p1.openshiftapps.com."
+		first := mockRoute53API.EXPECT().ListHostedZonesByName(&route53.ListHostedZonesByNameInput{
+			DNSName: awssdk.String(zoneName),
+		}).Return(&route53.ListHostedZonesByNameOutput{
+			HostedZones: []*route53.HostedZone{{
+				Id:   awssdk.String("public"),
+				Name: awssdk.String(zoneName),
+			}},
+			IsTruncated:      awssdk.Bool(true),
+			NextDNSName:      awssdk.String(zoneName),
+			NextHostedZoneId: awssdk.String("private"),
+		}, nil)
+		mockRoute53API.EXPECT().ListHostedZonesByName(&route53.ListHostedZonesByNameInput{
+			DNSName:      awssdk.String(zoneName),
+			HostedZoneId: awssdk.String("private"),
+		}).After(first).Return(&route53.ListHostedZonesByNameOutput{
+			HostedZones: []*route53.HostedZone{
+				{
+					Id:     awssdk.String("private"),
+					Name:   awssdk.String(zoneName),
+					Config: &route53.HostedZoneConfig{PrivateZone: awssdk.Bool(true)},
+				},
+				{
+					Id:   awssdk.String("other"),
+					Name: awssdk.String("othercluster.a1b2.p1.openshiftapps.com."),
+				},
+			},
+			IsTruncated:      awssdk.Bool(true),
+			NextDNSName:      awssdk.String("yetanother.a1b2.p1.openshiftapps.com."),
+			NextHostedZoneId: awssdk.String("yetanother"),
+		}, nil)
+		mockRoute53API.EXPECT().ListResourceRecordSetsPages(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(input *route53.ListResourceRecordSetsInput,
+				fn func(*route53.ListResourceRecordSetsOutput, bool) bool) error {
+				fn(&route53.ListResourceRecordSetsOutput{
+					ResourceRecordSets: []*route53.ResourceRecordSet{{
+						Name: awssdk.String(`\052.apps.` + zoneName),
+						Type: awssdk.String(route53.RRTypeA),
+						ResourceRecords: []*route53.ResourceRecord{{
+							Value: awssdk.String(awssdk.StringValue(input.HostedZoneId)),
+						}},
+					}},
+				}, true)
+				return nil
+			}).Times(2)
+
+		records, err := client.GetClusterDNSRecords("mycluster.a1b2.p1.openshiftapps.com")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(2))
+		Expect(records[0].Values).To(Equal([]string{"public"}))
+		Expect(records[0].Private).To(BeFalse())
+		Expect(records[1].Values).To(Equal([]string{"private"}))
+		Expect(records[1].Private).To(BeTrue())
+		Expect(records[1].Name).To(Equal("*.apps." + zoneName))
+	})
+})
@@ -0,0 +1,225 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that detect and repair the changes made outside of
+// CloudFormation to the resources of the stack of the admin user.
+
+package aws
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+// Limits of the wait for CloudFormation to finish the detection of the drift of a stack:
+const (
+	driftDetectionInterval = 5 * time.Second
+	driftDetectionTimeout  = 5 * time.Minute
+)
+
+// StackDrift describes the differences between the resources of a stack and its template.
+type StackDrift struct {
+	// Status is the drift status of the stack, for example DRIFTED or IN_SYNC.
+	Status string
+
+	// Resources are the resources that were modified or deleted outside of CloudFormation.
+	Resources []*StackResourceDrift
+}
+
+// StackResourceDrift describes a resource of a stack that was modified or deleted outside of
+// CloudFormation.
+type StackResourceDrift struct {
+	LogicalID  string
+	PhysicalID string
+	Type       string
+	Status     string
+}
+
+// Drifted checks if any resource of the stack was modified or deleted.
+func (d *StackDrift) Drifted() bool {
+	return len(d.Resources) > 0
+}
+
+// Deleted checks if any resource of the stack was deleted, which an update of the stack can't
+// recreate.
+func (d *StackDrift) Deleted() bool {
+	for _, resource := range d.Resources {
+		if resource.Status == cloudformation.StackResourceDriftStatusDeleted {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectStackDrift asks CloudFormation to compare the resources of the given stack with its
+// template, waits till the comparison finishes and returns the resources that don't match.
+func (c *awsClient) DetectStackDrift(stackName string) (*StackDrift, error) {
+	detectOutput, err := c.cfClient.DetectStackDrift(&cloudformation.DetectStackDriftInput{
+		StackName: aws.String(stackName),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(driftDetectionTimeout)
+	var statusOutput *cloudformation.DescribeStackDriftDetectionStatusOutput
+	for {
+		statusOutput, err = c.cfClient.DescribeStackDriftDetectionStatus(
+			&cloudformation.DescribeStackDriftDetectionStatusInput{
+				StackDriftDetectionId: detectOutput.StackDriftDetectionId,
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if aws.StringValue(statusOutput.DetectionStatus) !=
+			cloudformation.StackDriftDetectionStatusDetectionInProgress {
+			break
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("Timed out waiting for drift detection of stack '%s'", stackName)
+		}
+		time.Sleep(driftDetectionInterval)
+	}
+	if aws.StringValue(statusOutput.DetectionStatus) ==
+		cloudformation.StackDriftDetectionStatusDetectionFailed {
+		return nil, fmt.Errorf("Failed to detect drift of stack '%s': %s",
+			stackName, aws.StringValue(statusOutput.DetectionStatusReason))
+	}
+
+	drift := &StackDrift{
+		Status: aws.StringValue(statusOutput.StackDriftStatus),
+	}
+	if drift.Status == cloudformation.StackDriftStatusInSync {
+		return drift, nil
+	}
+	err = c.cfClient.DescribeStackResourceDriftsPages(
+		&cloudformation.DescribeStackResourceDriftsInput{
+			StackName: aws.String(stackName),
+			StackResourceDriftStatusFilters: aws.StringSlice([]string{
+				cloudformation.StackResourceDriftStatusModified,
+				cloudformation.StackResourceDriftStatusDeleted,
+			}),
+		},
+		func(page *cloudformation.DescribeStackResourceDriftsOutput, lastPage bool) bool {
+			for _, item := range page.StackResourceDrifts {
+				drift.Resources = append(drift.Resources, &StackResourceDrift{
+					LogicalID:  aws.StringValue(item.LogicalResourceId),
+					PhysicalID: aws.StringValue(item.PhysicalResourceId),
+					Type:       aws.StringValue(item.ResourceType),
+					Status:     aws.StringValue(item.StackResourceDriftStatus),
+				})
+			}
+			return true
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return drift, nil
+}
+
+// RepairOsdCcsAdminUser brings the stack of the admin user back in line with the template. When
+// nothing was changed outside of CloudFormation the stack is updated in place. Otherwise the stack
+// is deleted and created again, as updates don't revert those changes. The given tags are added to
+// the ones that the stack already has, and the given IAM settings replace the ones that it has. It
+// returns the drift that was found.
+func (c *awsClient) RepairOsdCcsAdminUser(stackName string, tags map[string]string,
+	settings IAMSettings) (*StackDrift, error) {
+	stackReady, _, err := c.CheckStackReadyOrNotExisting(stackName)
+	if err != nil {
+		return nil, err
+	}
+	if !stackReady {
+		return nil, fmt.Errorf("Stack '%s' doesn't exist, run 'rosa init' to create it", stackName)
+	}
+
+	cfTemplateBody, err := readCFTemplate()
+	if err != nil {
+		return nil, err
+	}
+
+	stack, err := c.describeStack(stackName)
+	if err != nil {
+		return nil, err
+	}
+	parameters := settings.stackParameters(stackParameterValues(stack))
+
+	drift, err := c.DetectStackDrift(stackName)
+	if err != nil {
+		return nil, err
+	}
+	if !drift.Drifted() {
+		_, err = c.UpdateStack(cfTemplateBody, stackName, tags, parameters)
+		if err != nil {
+			return nil, err
+		}
+		return drift, nil
+	}
+
+	// The stack will be created again, so keep its current tags:
+	previousTags := stackTags(stack)
+	for key, value := range tags {
+		previousTags[key] = value
+	}
+
+	err = c.DeleteOsdCcsAdminUser(stackName)
+	if err != nil {
+		return nil, err
+	}
+	_, err = c.CreateStack(cfTemplateBody, stackName, previousTags, parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	return drift, nil
+}
+
+// describeStack returns the given stack.
+func (c *awsClient) describeStack(stackName string) (*cloudformation.Stack, error) {
+	output, err := c.cfClient.DescribeStacks(&cloudformation.DescribeStacksInput{
+		StackName: aws.String(stackName),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(output.Stacks) == 0 {
+		return nil, fmt.Errorf("Stack '%s' doesn't exist", stackName)
+	}
+	return output.Stacks[0], nil
+}
+
+// stackTags returns the tags of the given stack.
+func stackTags(stack *cloudformation.Stack) map[string]string {
+	result := map[string]string{}
+	for _, tag := range stack.Tags {
+		result[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return result
+}
+
+// stackParameterValues returns the values of the parameters of the given stack.
+func stackParameterValues(stack *cloudformation.Stack) map[string]string {
+	result := map[string]string{}
+	for _, parameter := range stack.Parameters {
+		result[aws.StringValue(parameter.ParameterKey)] = aws.StringValue(parameter.ParameterValue)
+	}
+	return result
+}
@@ -0,0 +1,160 @@
+package aws_test
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mocks"
+)
+
+var _ = Describe("Stack drift", func() {
+	var (
+		client     aws.Client
+		mockCtrl   *gomock.Controller
+		mockCfAPI  *mocks.MockCloudFormationAPI
+		mockIamAPI *mocks.MockIAMAPI
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		var apis *apiMocks
+		client, apis = newClient(mockCtrl, &session.Session{})
+		mockIamAPI = apis.IAM
+		mockCfAPI = apis.CloudFormation
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	expectDetection := func(stackStatus string, resources ...*cloudformation.StackResourceDrift) {
+		mockCfAPI.EXPECT().DetectStackDrift(gomock.Any()).Return(&cloudformation.DetectStackDriftOutput{
+			StackDriftDetectionId: awssdk.String("fake-detection"),
+		}, nil)
+		mockCfAPI.EXPECT().DescribeStackDriftDetectionStatus(gomock.Any()).Return(
+			&cloudformation.DescribeStackDriftDetectionStatusOutput{
+				DetectionStatus:  awssdk.String(cloudformation.StackDriftDetectionStatusDetectionComplete),
+				StackDriftStatus: awssdk.String(stackStatus),
+			}, nil)
+		if len(resources) == 0 {
+			return
+		}
+		mockCfAPI.EXPECT().DescribeStackResourceDriftsPages(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(input *cloudformation.DescribeStackResourceDriftsInput,
+				fn func(*cloudformation.DescribeStackResourceDriftsOutput, bool) bool) error {
+				fn(&cloudformation.DescribeStackResourceDriftsOutput{
+					StackResourceDrifts: resources,
+				}, true)
+				return nil
+			})
+	}
+
+	Context("DetectStackDrift", func() {
+		It("Returns no resources when the stack is in sync", func() {
+			expectDetection(cloudformation.StackDriftStatusInSync)
+
+			drift, err := client.DetectStackDrift(aws.OsdCcsAdminStackName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(drift.Drifted()).To(BeFalse())
+		})
+
+		It("Returns the resources that were deleted", func() {
+			expectDetection(cloudformation.StackDriftStatusDrifted, &cloudformation.StackResourceDrift{
+				LogicalResourceId:        awssdk.String("osdCcsAdmin"),
+				PhysicalResourceId:       awssdk.String("osdCcsAdmin"),
+				ResourceType:             awssdk.String("AWS::IAM::User"),
+				StackResourceDriftStatus: awssdk.String(cloudformation.StackResourceDriftStatusDeleted),
+			})
+
+			drift, err := client.DetectStackDrift(aws.OsdCcsAdminStackName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(drift.Drifted()).To(BeTrue())
+			Expect(drift.Deleted()).To(BeTrue())
+			Expect(drift.Resources).To(HaveLen(1))
+			Expect(drift.Resources[0].Type).To(Equal("AWS::IAM::User"))
+		})
+
+		It("Fails when the detection fails", func() {
+			mockCfAPI.EXPECT().DetectStackDrift(gomock.Any()).Return(&cloudformation.DetectStackDriftOutput{
+				StackDriftDetectionId: awssdk.String("fake-detection"),
+			}, nil)
+			mockCfAPI.EXPECT().DescribeStackDriftDetectionStatus(gomock.Any()).Return(
+				&cloudformation.DescribeStackDriftDetectionStatusOutput{
+					DetectionStatus:       awssdk.String(cloudformation.StackDriftDetectionStatusDetectionFailed),
+					DetectionStatusReason: awssdk.String("fake reason"),
+				}, nil)
+
+			_, err := client.DetectStackDrift(aws.OsdCcsAdminStackName)
+			Expect(err).To(MatchError(ContainSubstring("fake reason")))
+		})
+	})
+
+	Context("RepairOsdCcsAdminUser", func() {
+		BeforeEach(func() {
+			mockCfAPI.EXPECT().ListStacks(gomock.Any()).Return(&cloudformation.ListStacksOutput{
+				StackSummaries: []*cloudformation.StackSummary{
+					{
+						StackName:   awssdk.String(aws.OsdCcsAdminStackName),
+						StackStatus: awssdk.String(cloudformation.StackStatusCreateComplete),
+					},
+				},
+			}, nil)
+		})
+
+		It("Updates the stack in place when it is in sync", func() {
+			expectDetection(cloudformation.StackDriftStatusInSync)
+			mockCfAPI.EXPECT().DescribeStacks(gomock.Any()).Return(&cloudformation.DescribeStacksOutput{
+				Stacks: []*cloudformation.Stack{{}},
+			}, nil)
+			mockCfAPI.EXPECT().UpdateStack(gomock.Any()).Return(nil, nil)
+			mockCfAPI.EXPECT().WaitUntilStackUpdateComplete(gomock.Any()).Return(nil)
+
+			drift, err := client.RepairOsdCcsAdminUser(aws.OsdCcsAdminStackName, nil, aws.IAMSettings{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(drift.Drifted()).To(BeFalse())
+		})
+
+		It("Recreates the stack when the user was modified", func() {
+			expectDetection(cloudformation.StackDriftStatusDrifted, &cloudformation.StackResourceDrift{
+				LogicalResourceId:        awssdk.String("osdCcsAdmin"),
+				PhysicalResourceId:       awssdk.String("osdCcsAdmin"),
+				ResourceType:             awssdk.String("AWS::IAM::User"),
+				StackResourceDriftStatus: awssdk.String(cloudformation.StackResourceDriftStatusModified),
+			})
+			mockCfAPI.EXPECT().DescribeStacks(gomock.Any()).Return(&cloudformation.DescribeStacksOutput{
+				Stacks: []*cloudformation.Stack{
+					{
+						Tags: []*cloudformation.Tag{
+							{Key: awssdk.String("CostCenter"), Value: awssdk.String("1234")},
+						},
+					},
+				},
+			}, nil)
+			mockIamAPI.EXPECT().ListAccessKeys(gomock.Any()).Return(&iam.ListAccessKeysOutput{
+				AccessKeyMetadata: []*iam.AccessKeyMetadata{
+					{AccessKeyId: awssdk.String("AKIDADMIN")},
+				},
+			}, nil)
+			mockIamAPI.EXPECT().DeleteAccessKey(gomock.Any()).Return(&iam.DeleteAccessKeyOutput{}, nil)
+			mockCfAPI.EXPECT().DeleteStack(gomock.Any()).Return(&cloudformation.DeleteStackOutput{}, nil)
+			mockCfAPI.EXPECT().WaitUntilStackDeleteComplete(gomock.Any()).Return(nil)
+			mockCfAPI.EXPECT().CreateStack(gomock.Any()).DoAndReturn(
+				func(input *cloudformation.CreateStackInput) (*cloudformation.CreateStackOutput, error) {
+					Expect(input.Tags).To(HaveLen(1))
+					Expect(awssdk.StringValue(input.Tags[0].Key)).To(Equal("CostCenter"))
+					return &cloudformation.CreateStackOutput{}, nil
+				})
+			mockCfAPI.EXPECT().WaitUntilStackCreateComplete(gomock.Any()).Return(nil)
+
+			drift, err := client.RepairOsdCcsAdminUser(aws.OsdCcsAdminStackName, nil, aws.IAMSettings{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(drift.Drifted()).To(BeTrue())
+		})
+	})
+})
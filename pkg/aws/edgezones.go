@@ -0,0 +1,145 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions for machine pools whose nodes run in AWS Local Zones or
+// Outposts, closer to their users than the availability zones of the region. Local Zones need to
+// be enabled in the account before they can be used, so they are the zones that require opting in.
+
+package aws
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// Locations of the subnets of machine pools:
+const (
+	SubnetLocationAvailabilityZone = "availability-zone"
+	SubnetLocationLocalZone        = "local-zone"
+	SubnetLocationOutpost          = "outpost"
+)
+
+// EdgeSubnet is a subnet in a Local Zone or in an Outpost.
+type EdgeSubnet struct {
+	ID         string
+	VPCID      string
+	Zone       string
+	Location   string
+	OutpostARN string
+}
+
+// GetLocalZones returns the names of the Local Zones of the region of the client that are enabled
+// in the account.
+func (c *awsClient) GetLocalZones() ([]string, error) {
+	res, err := c.ec2Client.DescribeAvailabilityZones(&ec2.DescribeAvailabilityZonesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("state"),
+				Values: aws.StringSlice([]string{ec2.AvailabilityZoneStateAvailable}),
+			},
+			{
+				Name:   aws.String("opt-in-status"),
+				Values: aws.StringSlice([]string{ec2.AvailabilityZoneOptInStatusOptedIn}),
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	zones := make([]string, 0, len(res.AvailabilityZones))
+	for _, zone := range res.AvailabilityZones {
+		zones = append(zones, aws.StringValue(zone.ZoneName))
+	}
+	sort.Strings(zones)
+	return zones, nil
+}
+
+// GetEdgeSubnet returns the description of the given subnet, checking that it is in a Local Zone
+// or in an Outpost. Subnets in the availability zones of the region are rejected, as they are used
+// with the regular machine pools.
+func (c *awsClient) GetEdgeSubnet(subnetID string) (*EdgeSubnet, error) {
+	subnets, err := c.FindSubnets([]string{subnetID})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to describe subnet '%s': %v", subnetID, err)
+	}
+	if len(subnets) == 0 {
+		return nil, fmt.Errorf("Subnet '%s' doesn't exist in region '%s'", subnetID, c.GetRegion())
+	}
+	subnet := subnets[0]
+	result := &EdgeSubnet{
+		ID:    subnetID,
+		VPCID: aws.StringValue(subnet.VpcId),
+		Zone:  aws.StringValue(subnet.AvailabilityZone),
+	}
+	if outpost := aws.StringValue(subnet.OutpostArn); outpost != "" {
+		result.Location = SubnetLocationOutpost
+		result.OutpostARN = outpost
+		return result, nil
+	}
+
+	res, err := c.ec2Client.DescribeAvailabilityZones(&ec2.DescribeAvailabilityZonesInput{
+		ZoneNames: aws.StringSlice([]string{result.Zone}),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to describe zone '%s' of subnet '%s': %v", result.Zone, subnetID, err)
+	}
+	if len(res.AvailabilityZones) == 0 {
+		return nil, fmt.Errorf("Zone '%s' of subnet '%s' doesn't exist", result.Zone, subnetID)
+	}
+	if aws.StringValue(res.AvailabilityZones[0].OptInStatus) == ec2.AvailabilityZoneOptInStatusOptInNotRequired {
+		return nil, fmt.Errorf("Subnet '%s' is in availability zone '%s', but it must be in a Local Zone "+
+			"or in an Outpost", subnetID, result.Zone)
+	}
+	result.Location = SubnetLocationLocalZone
+	return result, nil
+}
+
+// ValidateEdgeInstanceType checks that the given instance type is offered in the Local Zone of the
+// given subnet, as Local Zones only offer a few of the instance types of the region. The capacity
+// of Outposts is ordered with the Outpost, so it isn't checked.
+func (c *awsClient) ValidateEdgeInstanceType(subnet *EdgeSubnet, instanceType string) error {
+	if subnet.Location != SubnetLocationLocalZone {
+		return nil
+	}
+	offered := []string{}
+	err := c.ec2Client.DescribeInstanceTypeOfferingsPages(&ec2.DescribeInstanceTypeOfferingsInput{
+		LocationType: aws.String(ec2.LocationTypeAvailabilityZone),
+		Filters: []*ec2.Filter{{
+			Name:   aws.String("location"),
+			Values: aws.StringSlice([]string{subnet.Zone}),
+		}},
+	}, func(page *ec2.DescribeInstanceTypeOfferingsOutput, lastPage bool) bool {
+		for _, offering := range page.InstanceTypeOfferings {
+			offered = append(offered, aws.StringValue(offering.InstanceType))
+		}
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to describe instance types of Local Zone '%s': %v", subnet.Zone, err)
+	}
+	for _, offering := range offered {
+		if offering == instanceType {
+			return nil
+		}
+	}
+	sort.Strings(offered)
+	return fmt.Errorf("Instance type '%s' isn't offered in Local Zone '%s', the instance types offered "+
+		"there are: %s", instanceType, subnet.Zone, strings.Join(offered, ", "))
+}
@@ -0,0 +1,108 @@
+package aws_test
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mocks"
+)
+
+var _ = Describe("Edge subnets", func() {
+	var (
+		client   aws.Client
+		mockCtrl *gomock.Controller
+
+		mockEC2API *mocks.MockEC2API
+	)
+
+	subnet := func(zone string, outpostARN string) {
+		subnet := &ec2.Subnet{
+			SubnetId:         awssdk.String("subnet-1"),
+			VpcId:            awssdk.String("vpc-1"),
+			AvailabilityZone: awssdk.String(zone),
+		}
+		if outpostARN != "" {
+			subnet.OutpostArn = awssdk.String(outpostARN)
+		}
+		mockEC2API.EXPECT().DescribeSubnets(gomock.Any()).Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []*ec2.Subnet{subnet},
+		}, nil)
+	}
+
+	zone := func(name string, optInStatus string) {
+		mockEC2API.EXPECT().DescribeAvailabilityZones(gomock.Any()).Return(&ec2.DescribeAvailabilityZonesOutput{
+			AvailabilityZones: []*ec2.AvailabilityZone{{
+				ZoneName:    awssdk.String(name),
+				OptInStatus: awssdk.String(optInStatus),
+			}},
+		}, nil)
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		var apis *apiMocks
+		client, apis = newClient(mockCtrl, regionSession("us-west-2"))
+		mockEC2API = apis.EC2
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("Accepts subnets in Local Zones", func() {
+		subnet("us-west-2-lax-1a", "")
+		zone("us-west-2-lax-1a", ec2.AvailabilityZoneOptInStatusOptedIn)
+
+		result, err := client.GetEdgeSubnet("subnet-1")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Location).To(Equal(aws.SubnetLocationLocalZone))
+		Expect(result.VPCID).To(Equal("vpc-1"))
+	})
+
+	It("Accepts subnets in Outposts", func() {
+		subnet("us-west-2a", "arn:aws:outposts:us-west-2:123456789012:outpost/op-1")
+
+		result, err := client.GetEdgeSubnet("subnet-1")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Location).To(Equal(aws.SubnetLocationOutpost))
+		Expect(result.OutpostARN).To(Equal("arn:aws:outposts:us-west-2:123456789012:outpost/op-1"))
+	})
+
+	It("Rejects subnets in the availability zones of the region", func() {
+		subnet("us-west-2a", "")
+		zone("us-west-2a", ec2.AvailabilityZoneOptInStatusOptInNotRequired)
+
+		_, err := client.GetEdgeSubnet("subnet-1")
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("must be in a Local Zone or in an Outpost"))
+	})
+
+	It("Rejects instance types that aren't offered in the Local Zone", func() {
+		mockEC2API.EXPECT().DescribeInstanceTypeOfferingsPages(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ *ec2.DescribeInstanceTypeOfferingsInput,
+				fn func(*ec2.DescribeInstanceTypeOfferingsOutput, bool) bool) error {
+				fn(&ec2.DescribeInstanceTypeOfferingsOutput{
+					InstanceTypeOfferings: []*ec2.InstanceTypeOffering{
+						{InstanceType: awssdk.String("r5.2xlarge")},
+						{InstanceType: awssdk.String("c5.2xlarge")},
+					},
+				}, true)
+				return nil
+			})
+
+		err := client.ValidateEdgeInstanceType(&aws.EdgeSubnet{
+			Zone:     "us-west-2-lax-1a",
+			Location: aws.SubnetLocationLocalZone,
+		}, "m5.xlarge")
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("c5.2xlarge, r5.2xlarge"))
+	})
+})
@@ -0,0 +1,224 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the checks of the egress of the subnets of a VPC where a cluster will be
+// installed. They only look at the configuration of the VPC, so they don't need to run anything
+// inside it: the nodes need a default route to leave the VPC, and the network ACLs of the subnets
+// need to allow HTTPS connections and the responses to them.
+
+package aws
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// Port used by the nodes to connect to the required endpoints, and the ephemeral ports of the
+// nodes where the responses are received:
+const (
+	egressPort         = 443
+	ephemeralPortsFrom = 32768
+	ephemeralPortsTo   = 60999
+)
+
+// Block of addresses of the default route, and of the network ACL entries that apply to any
+// address:
+const anyAddressCIDR = "0.0.0.0/0"
+
+// Protocol numbers used in the entries of network ACLs:
+const (
+	networkACLProtocolAll = "-1"
+	networkACLProtocolTCP = "6"
+)
+
+// SubnetEgress describes how the nodes in a subnet reach the endpoints outside of the VPC.
+type SubnetEgress struct {
+	SubnetID string `json:"subnet_id"`
+
+	// Target of the default route of the subnet, for example the identifier of a NAT gateway. It
+	// is empty if the subnet doesn't have a default route.
+	DefaultRoute string `json:"default_route"`
+
+	NetworkACL string   `json:"network_acl"`
+	Problems   []string `json:"problems"`
+}
+
+// GetSubnetsEgress checks the default routes and the network ACLs of the given subnets, returning
+// the problems found for each of them.
+func (c *awsClient) GetSubnetsEgress(subnetIDs []string) ([]*SubnetEgress, error) {
+	subnets, err := c.FindSubnets(subnetIDs)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to describe subnets: %v", err)
+	}
+	found := map[string]*ec2.Subnet{}
+	for _, subnet := range subnets {
+		found[aws.StringValue(subnet.SubnetId)] = subnet
+	}
+
+	result := []*SubnetEgress{}
+	for _, subnetID := range subnetIDs {
+		egress := &SubnetEgress{
+			SubnetID: subnetID,
+			Problems: []string{},
+		}
+		result = append(result, egress)
+		subnet, ok := found[subnetID]
+		if !ok {
+			egress.Problems = append(egress.Problems, fmt.Sprintf("subnet doesn't exist in region '%s'",
+				c.GetRegion()))
+			continue
+		}
+		vpcID := aws.StringValue(subnet.VpcId)
+
+		egress.DefaultRoute, err = c.getDefaultRoute(vpcID, subnetID)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get route table of subnet '%s': %v", subnetID, err)
+		}
+		if egress.DefaultRoute == "" {
+			egress.Problems = append(egress.Problems, "there is no active default route, so the nodes "+
+				"can't reach the endpoints outside of the VPC")
+		}
+
+		acl, err := c.getSubnetNetworkACL(subnetID)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get network ACL of subnet '%s': %v", subnetID, err)
+		}
+		if acl == nil {
+			continue
+		}
+		egress.NetworkACL = aws.StringValue(acl.NetworkAclId)
+		if !networkACLAllows(acl.Entries, true, egressPort, egressPort) {
+			egress.Problems = append(egress.Problems, fmt.Sprintf("network ACL '%s' doesn't allow "+
+				"outbound connections to port %d", egress.NetworkACL, egressPort))
+		}
+		if !networkACLAllows(acl.Entries, false, ephemeralPortsFrom, ephemeralPortsTo) {
+			egress.Problems = append(egress.Problems, fmt.Sprintf("network ACL '%s' doesn't allow "+
+				"inbound responses to ports %d-%d", egress.NetworkACL, ephemeralPortsFrom, ephemeralPortsTo))
+		}
+	}
+	return result, nil
+}
+
+// getDefaultRoute returns the target of the active default route of the route table of the given
+// subnet, or an empty string if there is none. Subnets without an explicit association use the
+// main route table of the VPC.
+func (c *awsClient) getDefaultRoute(vpcID string, subnetID string) (string, error) {
+	var table *ec2.RouteTable
+	var mainTable *ec2.RouteTable
+	err := c.ec2Client.DescribeRouteTablesPages(&ec2.DescribeRouteTablesInput{
+		Filters: []*ec2.Filter{{
+			Name:   aws.String("vpc-id"),
+			Values: aws.StringSlice([]string{vpcID}),
+		}},
+	}, func(page *ec2.DescribeRouteTablesOutput, lastPage bool) bool {
+		for _, candidate := range page.RouteTables {
+			for _, association := range candidate.Associations {
+				if aws.BoolValue(association.Main) {
+					mainTable = candidate
+				}
+				if aws.StringValue(association.SubnetId) == subnetID {
+					table = candidate
+				}
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return "", err
+	}
+	if table == nil {
+		table = mainTable
+	}
+	if table == nil {
+		return "", nil
+	}
+
+	for _, route := range table.Routes {
+		if aws.StringValue(route.DestinationCidrBlock) != anyAddressCIDR ||
+			aws.StringValue(route.State) != ec2.RouteStateActive {
+			continue
+		}
+		for _, target := range []*string{
+			route.NatGatewayId,
+			route.GatewayId,
+			route.TransitGatewayId,
+			route.NetworkInterfaceId,
+			route.VpcPeeringConnectionId,
+			route.InstanceId,
+		} {
+			if value := aws.StringValue(target); value != "" {
+				return value, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+func (c *awsClient) getSubnetNetworkACL(subnetID string) (*ec2.NetworkAcl, error) {
+	output, err := c.ec2Client.DescribeNetworkAcls(&ec2.DescribeNetworkAclsInput{
+		Filters: []*ec2.Filter{{
+			Name:   aws.String("association.subnet-id"),
+			Values: aws.StringSlice([]string{subnetID}),
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(output.NetworkAcls) == 0 {
+		return nil, nil
+	}
+	return output.NetworkAcls[0], nil
+}
+
+// networkACLAllows checks if the given entries of a network ACL allow TCP traffic from or to any
+// address on the given range of ports. The entries are evaluated in the order of their rule
+// numbers, and only the ones that apply to all the addresses decide, as the endpoints can have any
+// address.
+func networkACLAllows(entries []*ec2.NetworkAclEntry, egress bool, from int64, to int64) bool {
+	sorted := []*ec2.NetworkAclEntry{}
+	for _, entry := range entries {
+		if aws.BoolValue(entry.Egress) == egress && aws.StringValue(entry.CidrBlock) == anyAddressCIDR {
+			sorted = append(sorted, entry)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return aws.Int64Value(sorted[i].RuleNumber) < aws.Int64Value(sorted[j].RuleNumber)
+	})
+
+	for _, entry := range sorted {
+		allow := aws.StringValue(entry.RuleAction) == ec2.RuleActionAllow
+		switch aws.StringValue(entry.Protocol) {
+		case networkACLProtocolAll:
+			return allow
+		case networkACLProtocolTCP:
+			if entry.PortRange == nil {
+				return allow
+			}
+			first := aws.Int64Value(entry.PortRange.From)
+			last := aws.Int64Value(entry.PortRange.To)
+			if allow && first <= from && to <= last {
+				return true
+			}
+			if !allow && first <= to && from <= last {
+				return false
+			}
+		}
+	}
+	return false
+}
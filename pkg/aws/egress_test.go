@@ -0,0 +1,120 @@
+package aws_test
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mocks"
+)
+
+var _ = Describe("GetSubnetsEgress", func() {
+	var (
+		client   aws.Client
+		mockCtrl *gomock.Controller
+
+		mockEC2API *mocks.MockEC2API
+	)
+
+	// The subnet uses the main route table of the VPC, with the given default route:
+	subnet := func(route *ec2.Route) {
+		mockEC2API.EXPECT().DescribeSubnets(gomock.Any()).Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []*ec2.Subnet{{SubnetId: awssdk.String("subnet-1"), VpcId: awssdk.String("vpc-1")}},
+		}, nil)
+		table := &ec2.RouteTable{
+			Associations: []*ec2.RouteTableAssociation{{Main: awssdk.Bool(true)}},
+		}
+		if route != nil {
+			table.Routes = []*ec2.Route{route}
+		}
+		mockEC2API.EXPECT().DescribeRouteTablesPages(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ *ec2.DescribeRouteTablesInput, fn func(*ec2.DescribeRouteTablesOutput, bool) bool) error {
+				fn(&ec2.DescribeRouteTablesOutput{RouteTables: []*ec2.RouteTable{table}}, true)
+				return nil
+			})
+	}
+
+	entry := func(number int64, egress bool, action string, protocol string, from int64, to int64) *ec2.NetworkAclEntry {
+		return &ec2.NetworkAclEntry{
+			RuleNumber: awssdk.Int64(number),
+			Egress:     awssdk.Bool(egress),
+			RuleAction: awssdk.String(action),
+			Protocol:   awssdk.String(protocol),
+			CidrBlock:  awssdk.String("0.0.0.0/0"),
+			PortRange:  &ec2.PortRange{From: awssdk.Int64(from), To: awssdk.Int64(to)},
+		}
+	}
+
+	networkACL := func(entries ...*ec2.NetworkAclEntry) {
+		mockEC2API.EXPECT().DescribeNetworkAcls(gomock.Any()).Return(&ec2.DescribeNetworkAclsOutput{
+			NetworkAcls: []*ec2.NetworkAcl{{NetworkAclId: awssdk.String("acl-1"), Entries: entries}},
+		}, nil)
+	}
+
+	natRoute := &ec2.Route{
+		DestinationCidrBlock: awssdk.String("0.0.0.0/0"),
+		NatGatewayId:         awssdk.String("nat-1"),
+		State:                awssdk.String(ec2.RouteStateActive),
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		var apis *apiMocks
+		client, apis = newClient(mockCtrl, regionSession("us-east-1"))
+		mockEC2API = apis.EC2
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("Accepts subnets with a NAT gateway and the default network ACL", func() {
+		subnet(natRoute)
+		networkACL(
+			entry(100, true, ec2.RuleActionAllow, "-1", 0, 0),
+			entry(100, false, ec2.RuleActionAllow, "-1", 0, 0),
+		)
+
+		result, err := client.GetSubnetsEgress([]string{"subnet-1"})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].DefaultRoute).To(Equal("nat-1"))
+		Expect(result[0].Problems).To(BeEmpty())
+	})
+
+	It("Reports subnets without a default route", func() {
+		subnet(&ec2.Route{
+			DestinationCidrBlock: awssdk.String("0.0.0.0/0"),
+			NatGatewayId:         awssdk.String("nat-1"),
+			State:                awssdk.String(ec2.RouteStateBlackhole),
+		})
+		networkACL(
+			entry(100, true, ec2.RuleActionAllow, "-1", 0, 0),
+			entry(100, false, ec2.RuleActionAllow, "-1", 0, 0),
+		)
+
+		result, err := client.GetSubnetsEgress([]string{"subnet-1"})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result[0].DefaultRoute).To(BeEmpty())
+		Expect(result[0].Problems).To(ConsistOf(ContainSubstring("no active default route")))
+	})
+
+	It("Evaluates the network ACL entries in order", func() {
+		subnet(natRoute)
+		networkACL(
+			entry(200, true, ec2.RuleActionAllow, "6", 443, 443),
+			entry(100, true, ec2.RuleActionDeny, "6", 0, 1024),
+			entry(100, false, ec2.RuleActionAllow, "6", 1024, 65535),
+		)
+
+		result, err := client.GetSubnetsEgress([]string{"subnet-1"})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result[0].Problems).To(ConsistOf(ContainSubstring("doesn't allow outbound connections")))
+	})
+})
@@ -0,0 +1,174 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that check if an AWS account can run the GPU instances of a
+// machine pool.
+
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+)
+
+// GPUInfo describes the GPU accelerators of an instance type.
+type GPUInfo struct {
+	InstanceType string
+	VCPUs        int
+	GPUs         int
+	Manufacturer string
+	Name         string
+}
+
+// gpuQuota is the EC2 quota that limits the number of vCPUs of the running on-demand instances of
+// a group of GPU instance families.
+type gpuQuota struct {
+	QuotaCode string
+	QuotaName string
+	Classes   []string
+}
+
+var gpuQuotas = []gpuQuota{
+	{
+		QuotaCode: "L-417A185B",
+		QuotaName: "Running On-Demand P instances",
+		Classes:   []string{"p"},
+	},
+	{
+		QuotaCode: "L-DB2E81BA",
+		QuotaName: "Running On-Demand G and VT instances",
+		Classes:   []string{"g", "gr", "vt"},
+	},
+}
+
+// instanceClass returns the letters that precede the generation of the given instance type, for
+// example 'g' for 'g4dn.xlarge' or 'vt' for 'vt1.3xlarge'.
+func instanceClass(instanceType string) string {
+	end := strings.IndexAny(instanceType, "0123456789.")
+	if end < 0 {
+		return instanceType
+	}
+	return instanceType[:end]
+}
+
+// getGPUQuota returns the quota that applies to the given instance type, or nil if it isn't a GPU
+// instance type.
+func getGPUQuota(instanceType string) *gpuQuota {
+	class := instanceClass(instanceType)
+	for i, quota := range gpuQuotas {
+		for _, item := range quota.Classes {
+			if class == item {
+				return &gpuQuotas[i]
+			}
+		}
+	}
+	return nil
+}
+
+// IsGPUInstanceType checks if the given instance type belongs to one of the GPU instance families
+// that have their own vCPU quota.
+func IsGPUInstanceType(instanceType string) bool {
+	return getGPUQuota(instanceType) != nil
+}
+
+// GPUQuotaError is returned when the vCPU quota of a GPU instance family isn't enough for the
+// requested nodes.
+type GPUQuotaError struct {
+	QuotaCode string
+	QuotaName string
+	Quota     int
+	Used      int
+	Required  int
+}
+
+func (e *GPUQuotaError) Error() string {
+	return fmt.Sprintf("Quota '%s' (%s) allows %d vCPUs and %d are in use, but %d more are required. "+
+		"Request a quota increase in the Service Quotas console",
+		e.QuotaName, e.QuotaCode, e.Quota, e.Used, e.Required)
+}
+
+// GetGPUInfo returns the number of vCPUs and the GPUs of the given instance type.
+func (c *awsClient) GetGPUInfo(instanceType string) (*GPUInfo, error) {
+	output, err := c.ec2Client.DescribeInstanceTypes(&ec2.DescribeInstanceTypesInput{
+		InstanceTypes: []*string{aws.String(instanceType)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(output.InstanceTypes) == 0 {
+		return nil, fmt.Errorf("Instance type '%s' isn't available in region '%s'", instanceType, c.GetRegion())
+	}
+	description := output.InstanceTypes[0]
+	info := &GPUInfo{
+		InstanceType: instanceType,
+	}
+	if description.VCpuInfo != nil {
+		info.VCPUs = int(aws.Int64Value(description.VCpuInfo.DefaultVCpus))
+	}
+	if description.GpuInfo != nil {
+		for _, gpu := range description.GpuInfo.Gpus {
+			info.GPUs += int(aws.Int64Value(gpu.Count))
+			info.Manufacturer = aws.StringValue(gpu.Manufacturer)
+			info.Name = aws.StringValue(gpu.Name)
+		}
+	}
+	return info, nil
+}
+
+// ValidateGPUQuota checks that the vCPU quota of the family of the given instance type allows
+// running the given number of additional instances, on top of the instances of the families of
+// the same quota that are already running. If it doesn't the returned error is a *GPUQuotaError.
+func (c *awsClient) ValidateGPUQuota(info *GPUInfo, replicas int) error {
+	quota := getGPUQuota(info.InstanceType)
+	if quota == nil {
+		return nil
+	}
+
+	output, err := c.servicequotasClient.GetServiceQuota(&servicequotas.GetServiceQuotaInput{
+		ServiceCode: aws.String("ec2"),
+		QuotaCode:   aws.String(quota.QuotaCode),
+	})
+	if err != nil {
+		return fmt.Errorf("Error getting AWS service quota '%s': %v", quota.QuotaName, err)
+	}
+	limit := int(aws.Float64Value(output.Quota.Value))
+
+	// Only the running and pending instances count towards the quota:
+	used, err := c.getRunningVCPUs(func(instanceType string) bool {
+		return getGPUQuota(instanceType) == quota
+	})
+	if err != nil {
+		return err
+	}
+
+	required := replicas * info.VCPUs
+	c.logger.Debug(fmt.Sprintf("Quota '%s' allows %d vCPUs, %d are in use and %d are required",
+		quota.QuotaName, limit, used, required))
+	if used+required > limit {
+		return &GPUQuotaError{
+			QuotaCode: quota.QuotaCode,
+			QuotaName: quota.QuotaName,
+			Quota:     limit,
+			Used:      used,
+			Required:  required,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package aws_test
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mocks"
+)
+
+var _ = Describe("ValidateGPUQuota", func() {
+	var (
+		client   aws.Client
+		mockCtrl *gomock.Controller
+
+		mockEC2API           *mocks.MockEC2API
+		mockServiceQuotasAPI *mocks.MockServiceQuotasAPI
+	)
+
+	// g4dn.xlarge instances have 4 vCPUs:
+	info := &aws.GPUInfo{InstanceType: "g4dn.xlarge", VCPUs: 4, GPUs: 1}
+
+	instance := func(instanceType string) *ec2.Instance {
+		return &ec2.Instance{
+			InstanceType: awssdk.String(instanceType),
+			CpuOptions: &ec2.CpuOptions{
+				CoreCount:      awssdk.Int64(2),
+				ThreadsPerCore: awssdk.Int64(2),
+			},
+		}
+	}
+
+	expectInstances := func(instances ...*ec2.Instance) {
+		mockEC2API.EXPECT().DescribeInstancesPages(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool) error {
+				fn(&ec2.DescribeInstancesOutput{
+					Reservations: []*ec2.Reservation{{Instances: instances}},
+				}, true)
+				return nil
+			})
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		var apis *apiMocks
+		client, apis = newClient(mockCtrl, regionSession("us-east-1"))
+		mockEC2API = apis.EC2
+		mockServiceQuotasAPI = apis.ServiceQuotas
+		mockServiceQuotasAPI.EXPECT().GetServiceQuota(gomock.Any()).Return(&servicequotas.GetServiceQuotaOutput{
+			Quota: &servicequotas.ServiceQuota{Value: awssdk.Float64(16)},
+		}, nil).AnyTimes()
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("Recognizes GPU instance types", func() {
+		Expect(aws.IsGPUInstanceType("g4dn.xlarge")).To(BeTrue())
+		Expect(aws.IsGPUInstanceType("p3.2xlarge")).To(BeTrue())
+		Expect(aws.IsGPUInstanceType("vt1.3xlarge")).To(BeTrue())
+		Expect(aws.IsGPUInstanceType("m5.xlarge")).To(BeFalse())
+	})
+
+	It("Accepts instances that fit in the quota", func() {
+		expectInstances(instance("g4dn.xlarge"), instance("p3.2xlarge"), instance("m5.xlarge"))
+
+		err := client.ValidateGPUQuota(info, 3)
+
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Counts the running instances of the same quota", func() {
+		expectInstances(instance("g5.xlarge"), instance("vt1.3xlarge"))
+
+		err := client.ValidateGPUQuota(info, 3)
+
+		Expect(err).To(BeAssignableToTypeOf(&aws.GPUQuotaError{}))
+		Expect(err.(*aws.GPUQuotaError).Used).To(Equal(8))
+		Expect(err.(*aws.GPUQuotaError).Required).To(Equal(12))
+	})
+})
@@ -0,0 +1,111 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the settings of the IAM users, roles and policies created by the tool that
+// organizations with strict IAM governance require: the permissions boundary and the path.
+
+package aws
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/iam"
+)
+
+// IAMSettings contains the permissions boundary and the path of the IAM users, roles and policies
+// created by the tool. Empty fields use the defaults of IAM for new resources, and keep the values
+// that existing resources already have.
+type IAMSettings struct {
+	// PermissionsBoundary is the ARN of the managed policy that is the permissions boundary of the
+	// users and roles.
+	PermissionsBoundary string
+
+	// Path is the path of the users, roles and policies, for example '/rosa/'.
+	Path string
+}
+
+// DefaultIAMPath is the path of IAM resources created without one.
+const DefaultIAMPath = "/"
+
+// IAM paths begin and end with a slash and contain printable ASCII characters:
+var iamPathRE = regexp.MustCompile(`^/([\x21-\x7e]{1,510}/)?$`)
+
+// ValidateIAMSettings checks that the given path is valid and that the permissions boundary is the
+// ARN of a managed policy.
+func ValidateIAMSettings(settings IAMSettings) error {
+	if settings.Path != "" && !iamPathRE.MatchString(settings.Path) {
+		return fmt.Errorf("IAM path '%s' isn't valid: it must begin and end with '/', be at most 512 "+
+			"characters long and contain only printable ASCII characters", settings.Path)
+	}
+	if settings.PermissionsBoundary != "" {
+		parsed, err := arn.Parse(settings.PermissionsBoundary)
+		if err != nil || parsed.Service != iam.ServiceName || !strings.HasPrefix(parsed.Resource, "policy/") {
+			return fmt.Errorf("Permissions boundary '%s' isn't valid: it must be the ARN of a managed "+
+				"policy", settings.PermissionsBoundary)
+		}
+	}
+	return nil
+}
+
+// IAMPath returns the path of the IAM resource with the given ARN, for example '/rosa/' for the
+// role 'arn:aws:iam::123456789012:role/rosa/MyRole'.
+func IAMPath(resourceARN string) (string, error) {
+	parsed, err := arn.Parse(resourceARN)
+	if err != nil {
+		return "", err
+	}
+	first := strings.Index(parsed.Resource, "/")
+	last := strings.LastIndex(parsed.Resource, "/")
+	if parsed.Service != iam.ServiceName || first == -1 {
+		return "", fmt.Errorf("'%s' isn't the ARN of an IAM resource", resourceARN)
+	}
+	return parsed.Resource[first : last+1], nil
+}
+
+// Parameters of the template of the stack of the admin user:
+const (
+	stackPermissionsBoundaryParameter = "PermissionsBoundary"
+	stackPathParameter                = "Path"
+)
+
+// stackParameters returns the parameters of the stack of the admin user for these settings. The
+// parameters that the settings don't give keep the previous values of the stack, if any, as
+// otherwise CloudFormation would use the defaults of the template.
+func (s IAMSettings) stackParameters(previous map[string]string) []*cloudformation.Parameter {
+	values := map[string]string{
+		stackPermissionsBoundaryParameter: s.PermissionsBoundary,
+		stackPathParameter:                s.Path,
+	}
+	result := []*cloudformation.Parameter{}
+	for _, key := range []string{stackPermissionsBoundaryParameter, stackPathParameter} {
+		value, ok := values[key], values[key] != ""
+		if !ok {
+			value, ok = previous[key]
+		}
+		if ok {
+			result = append(result, &cloudformation.Parameter{
+				ParameterKey:   aws.String(key),
+				ParameterValue: aws.String(value),
+			})
+		}
+	}
+	return result
+}
@@ -0,0 +1,66 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to implement the '--permissions-boundary' and '--iam-path'
+// command line options of the commands that create IAM users, roles and policies.
+
+package iamsettings
+
+import (
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/moactl/pkg/aws"
+)
+
+// AddFlags adds the '--permissions-boundary' and '--iam-path' flags to the given set of command
+// line flags.
+func AddFlags(flags *pflag.FlagSet) {
+	AddPermissionsBoundaryFlag(flags)
+	flags.StringVar(
+		&path,
+		"iam-path",
+		"",
+		"Path of the IAM users, roles and policies that are created, for example '/rosa/'. The "+
+			"path of existing roles can't be changed. The default is '/'.",
+	)
+}
+
+// AddPermissionsBoundaryFlag adds only the '--permissions-boundary' flag to the given set of command
+// line flags, for commands that create roles whose path is already decided.
+func AddPermissionsBoundaryFlag(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&permissionsBoundary,
+		"permissions-boundary",
+		"",
+		"ARN of the managed policy that is the permissions boundary of the IAM users and roles that "+
+			"are created, as required by some organizations. Existing users and roles are updated "+
+			"to use it.",
+	)
+}
+
+// Settings returns the IAM settings given with the flags.
+func Settings() aws.IAMSettings {
+	return aws.IAMSettings{
+		PermissionsBoundary: permissionsBoundary,
+		Path:                path,
+	}
+}
+
+// permissionsBoundary and path are the values of the flags.
+var (
+	permissionsBoundary string
+	path                string
+)
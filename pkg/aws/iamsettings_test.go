@@ -0,0 +1,39 @@
+package aws_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws"
+)
+
+var _ = Describe("IAM settings", func() {
+	It("Accepts valid paths and permissions boundaries", func() {
+		Expect(aws.ValidateIAMSettings(aws.IAMSettings{})).To(Succeed())
+		Expect(aws.ValidateIAMSettings(aws.IAMSettings{
+			PermissionsBoundary: "arn:aws:iam::123456789012:policy/teams/Boundary",
+			Path:                "/rosa/clusters/",
+		})).To(Succeed())
+		Expect(aws.ValidateIAMSettings(aws.IAMSettings{Path: "/"})).To(Succeed())
+	})
+
+	It("Rejects paths that don't begin and end with a slash", func() {
+		Expect(aws.ValidateIAMSettings(aws.IAMSettings{Path: "rosa/"})).NotTo(Succeed())
+		Expect(aws.ValidateIAMSettings(aws.IAMSettings{Path: "/rosa"})).NotTo(Succeed())
+		Expect(aws.ValidateIAMSettings(aws.IAMSettings{Path: "/my path/"})).NotTo(Succeed())
+	})
+
+	It("Rejects permissions boundaries that aren't managed policies", func() {
+		Expect(aws.ValidateIAMSettings(aws.IAMSettings{
+			PermissionsBoundary: "arn:aws:iam::123456789012:role/Boundary",
+		})).NotTo(Succeed())
+		Expect(aws.ValidateIAMSettings(aws.IAMSettings{PermissionsBoundary: "Boundary"})).NotTo(Succeed())
+	})
+
+	It("Returns the path of IAM resources", func() {
+		Expect(aws.IAMPath("arn:aws:iam::123456789012:role/MyRole")).To(Equal("/"))
+		Expect(aws.IAMPath("arn:aws:iam::123456789012:role/rosa/clusters/MyRole")).To(Equal("/rosa/clusters/"))
+		_, err := aws.IAMPath("arn:aws:s3:::bucket")
+		Expect(err).To(HaveOccurred())
+	})
+})
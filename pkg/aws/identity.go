@@ -0,0 +1,135 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that check if the AWS identity running a command is appropriate
+// for operations that create or change resources.
+
+package aws
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+// IdentityType is the kind of AWS identity that runs a command.
+type IdentityType string
+
+const (
+	RootIdentity    IdentityType = "root"
+	UserIdentity    IdentityType = "user"
+	SessionIdentity IdentityType = "session"
+)
+
+// Sessions that expire in less than this time are reported, even if there is enough time left for
+// the operation:
+const sessionWarningThreshold = time.Hour
+
+// Identity describes the AWS identity that runs a command.
+type Identity struct {
+	ARN  string
+	Type IdentityType
+
+	// Name is the name of the user, or the name of the role of a session.
+	Name string
+
+	// MFA indicates if the user has an MFA device. It is only set for IAM users.
+	MFA bool
+
+	// Expiration is the time when the credentials expire. It is zero when they don't expire or
+	// when the provider of the credentials doesn't report it.
+	Expiration time.Time
+}
+
+// GetIdentity returns the AWS identity that the client uses.
+func (c *awsClient) GetIdentity() (*Identity, error) {
+	output, err := c.stsClient.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, err
+	}
+	identity := &Identity{
+		ARN: aws.StringValue(output.Arn),
+	}
+	parsed, err := arn.Parse(identity.ARN)
+	if err != nil {
+		return nil, err
+	}
+
+	// The resource is 'root', 'user/[PATH/]NAME', 'assumed-role/ROLE/SESSION' or
+	// 'federated-user/NAME':
+	segments := strings.Split(parsed.Resource, "/")
+	switch segments[0] {
+	case "root":
+		identity.Type = RootIdentity
+	case "user":
+		identity.Type = UserIdentity
+		identity.Name = segments[len(segments)-1]
+		devices, err := c.iamClient.ListMFADevices(&iam.ListMFADevicesInput{
+			UserName: aws.String(identity.Name),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Failed to list MFA devices of user '%s': %v", identity.Name, err)
+		}
+		identity.MFA = len(devices.MFADevices) > 0
+	default:
+		identity.Type = SessionIdentity
+		if len(segments) > 1 {
+			identity.Name = segments[1]
+		}
+	}
+
+	if c.awsSession != nil && c.awsSession.Config.Credentials != nil {
+		expiration, err := c.awsSession.Config.Credentials.ExpiresAt()
+		if err == nil {
+			identity.Expiration = expiration
+		}
+	}
+
+	return identity, nil
+}
+
+// Check checks that the identity can be used for an operation that takes the given time. Root
+// users and sessions that expire before the operation ends are rejected with an error. Other
+// problems, like users without MFA or sessions that expire soon, are returned as warnings.
+func (i *Identity) Check(now time.Time, duration time.Duration) (warnings []string, err error) {
+	if i.Type == RootIdentity {
+		return nil, fmt.Errorf("The AWS credentials belong to the root user of the account. " +
+			"Use the credentials of an IAM user or role instead")
+	}
+	if i.Type == UserIdentity && !i.MFA {
+		warnings = append(warnings, fmt.Sprintf(
+			"IAM user '%s' doesn't have an MFA device. Consider enabling MFA or using an IAM role",
+			i.Name))
+	}
+	if !i.Expiration.IsZero() {
+		remaining := i.Expiration.Sub(now).Round(time.Minute)
+		switch {
+		case remaining <= 0:
+			return warnings, fmt.Errorf("The AWS session has expired. Renew the credentials and try again")
+		case remaining < duration:
+			return warnings, fmt.Errorf("The AWS session expires in %s, before the operation can "+
+				"complete. Renew the credentials and try again", remaining)
+		case remaining < sessionWarningThreshold:
+			warnings = append(warnings, fmt.Sprintf("The AWS session expires in %s", remaining))
+		}
+	}
+	return warnings, nil
+}
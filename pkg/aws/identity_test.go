@@ -0,0 +1,100 @@
+package aws_test
+
+import (
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mocks"
+)
+
+var _ = Describe("Identity", func() {
+	var (
+		client   aws.Client
+		mockCtrl *gomock.Controller
+
+		mockIamAPI *mocks.MockIAMAPI
+		mockStsAPI *mocks.MockSTSAPI
+	)
+
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	expectCaller := func(arn string) {
+		mockStsAPI.EXPECT().GetCallerIdentity(gomock.Any()).Return(&sts.GetCallerIdentityOutput{
+			Account: awssdk.String("123456789012"),
+			Arn:     awssdk.String(arn),
+		}, nil)
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		var apis *apiMocks
+		client, apis = newClient(mockCtrl, regionSession("us-east-1"))
+		mockIamAPI = apis.IAM
+		mockStsAPI = apis.STS
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("Rejects the root user", func() {
+		expectCaller("arn:aws:iam::123456789012:root")
+
+		identity, err := client.GetIdentity()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(identity.Type).To(Equal(aws.RootIdentity))
+
+		_, err = identity.Check(now, 15*time.Minute)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Warns about users without MFA", func() {
+		expectCaller("arn:aws:iam::123456789012:user/admins/alice")
+		mockIamAPI.EXPECT().ListMFADevices(&iam.ListMFADevicesInput{
+			UserName: awssdk.String("alice"),
+		}).Return(&iam.ListMFADevicesOutput{}, nil)
+
+		identity, err := client.GetIdentity()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(identity.Type).To(Equal(aws.UserIdentity))
+		Expect(identity.MFA).To(BeFalse())
+
+		warnings, err := identity.Check(now, 15*time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(HaveLen(1))
+	})
+
+	It("Identifies the role of assumed role sessions", func() {
+		expectCaller("arn:aws:sts::123456789012:assumed-role/Deployer/session-1")
+
+		identity, err := client.GetIdentity()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(identity.Type).To(Equal(aws.SessionIdentity))
+		Expect(identity.Name).To(Equal("Deployer"))
+	})
+
+	It("Warns about sessions that expire soon", func() {
+		identity := &aws.Identity{Type: aws.SessionIdentity, Expiration: now.Add(30 * time.Minute)}
+
+		warnings, err := identity.Check(now, 15*time.Minute)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(ConsistOf("The AWS session expires in 30m0s"))
+	})
+
+	It("Rejects sessions that expire before the operation completes", func() {
+		identity := &aws.Identity{Type: aws.SessionIdentity, Expiration: now.Add(10 * time.Minute)}
+
+		_, err := identity.Check(now, 15*time.Minute)
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("expires in 10m0s"))
+	})
+})
@@ -0,0 +1,306 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that find the AWS resources created for a cluster.
+
+package aws
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+// Infrastructure contains the AWS resources of a cluster, with the attributes that are relevant to
+// understand what the cluster costs.
+type Infrastructure struct {
+	VPCs           []*VPC           `json:"vpcs"`
+	Subnets        []*Subnet        `json:"subnets"`
+	NATGateways    []*NATGateway    `json:"nat_gateways"`
+	LoadBalancers  []*LoadBalancer  `json:"load_balancers"`
+	SecurityGroups []*SecurityGroup `json:"security_groups"`
+}
+
+// VPC is a VPC of a cluster. It isn't owned by the cluster when the cluster was installed into an
+// existing VPC.
+type VPC struct {
+	ID    string `json:"id"`
+	CIDR  string `json:"cidr"`
+	Owned bool   `json:"owned"`
+}
+
+// Subnet is a subnet of a cluster. Public subnets assign public IP addresses on launch.
+type Subnet struct {
+	ID               string `json:"id"`
+	Name             string `json:"name"`
+	AvailabilityZone string `json:"availability_zone"`
+	CIDR             string `json:"cidr"`
+	Public           bool   `json:"public"`
+}
+
+// NATGateway is a NAT gateway of a cluster. Each one is billed by the hour and by the amount of
+// data processed, and uses an Elastic IP address.
+type NATGateway struct {
+	ID        string   `json:"id"`
+	SubnetID  string   `json:"subnet_id"`
+	State     string   `json:"state"`
+	PublicIPs []string `json:"public_ips"`
+}
+
+// LoadBalancer is a classic, application or network load balancer of a cluster.
+type LoadBalancer struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Scheme string `json:"scheme"`
+	DNS    string `json:"dns"`
+}
+
+// SecurityGroup is a security group of a cluster, with the number of ingress and egress rules.
+type SecurityGroup struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Rules int    `json:"rules"`
+}
+
+// Prefix of the keys of the tags that the installer adds to the resources of a cluster:
+const clusterTagPrefix = "kubernetes.io/cluster/"
+
+// clusterTagKey returns the key of the tag that the installer adds to the resources of the
+// cluster. The value is 'owned' for the resources created by the installer and 'shared' for
+// existing resources, like the subnets of an existing VPC.
+func clusterTagKey(infraID string) string {
+	return clusterTagPrefix + infraID
+}
+
+// GetClusterInfrastructure finds the resources tagged with the given infrastructure identifier in
+// the region of the client.
+func (c *awsClient) GetClusterInfrastructure(infraID string) (*Infrastructure, error) {
+	result := &Infrastructure{}
+	tagKey := clusterTagKey(infraID)
+	filters := []*ec2.Filter{{
+		Name:   aws.String("tag-key"),
+		Values: aws.StringSlice([]string{tagKey}),
+	}}
+
+	vpcs, err := c.ec2Client.DescribeVpcs(&ec2.DescribeVpcsInput{Filters: filters})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list VPCs: %v", err)
+	}
+	for _, vpc := range vpcs.Vpcs {
+		result.VPCs = append(result.VPCs, &VPC{
+			ID:    aws.StringValue(vpc.VpcId),
+			CIDR:  aws.StringValue(vpc.CidrBlock),
+			Owned: tagValue(vpc.Tags, tagKey) == "owned",
+		})
+	}
+
+	subnets, err := c.ec2Client.DescribeSubnets(&ec2.DescribeSubnetsInput{Filters: filters})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list subnets: %v", err)
+	}
+	for _, subnet := range subnets.Subnets {
+		result.Subnets = append(result.Subnets, &Subnet{
+			ID:               aws.StringValue(subnet.SubnetId),
+			Name:             tagValue(subnet.Tags, "Name"),
+			AvailabilityZone: aws.StringValue(subnet.AvailabilityZone),
+			CIDR:             aws.StringValue(subnet.CidrBlock),
+			Public:           aws.BoolValue(subnet.MapPublicIpOnLaunch),
+		})
+	}
+
+	err = c.ec2Client.DescribeNatGatewaysPages(&ec2.DescribeNatGatewaysInput{Filter: filters},
+		func(page *ec2.DescribeNatGatewaysOutput, lastPage bool) bool {
+			for _, gateway := range page.NatGateways {
+				result.NATGateways = append(result.NATGateways, newNATGateway(gateway))
+			}
+			return true
+		})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list NAT gateways: %v", err)
+	}
+
+	groups, err := c.ec2Client.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{Filters: filters})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list security groups: %v", err)
+	}
+	for _, group := range groups.SecurityGroups {
+		result.SecurityGroups = append(result.SecurityGroups, &SecurityGroup{
+			ID:    aws.StringValue(group.GroupId),
+			Name:  aws.StringValue(group.GroupName),
+			Rules: len(group.IpPermissions) + len(group.IpPermissionsEgress),
+		})
+	}
+
+	result.LoadBalancers, err = c.getClusterLoadBalancers(tagKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// newNATGateway converts the given EC2 NAT gateway.
+func newNATGateway(gateway *ec2.NatGateway) *NATGateway {
+	result := &NATGateway{
+		ID:       aws.StringValue(gateway.NatGatewayId),
+		SubnetID: aws.StringValue(gateway.SubnetId),
+		State:    aws.StringValue(gateway.State),
+	}
+	for _, address := range gateway.NatGatewayAddresses {
+		if address.PublicIp != nil {
+			result.PublicIPs = append(result.PublicIPs, aws.StringValue(address.PublicIp))
+		}
+	}
+	return result
+}
+
+// getClusterLoadBalancers finds the classic, application and network load balancers that have the
+// given tag.
+func (c *awsClient) getClusterLoadBalancers(tagKey string) ([]*LoadBalancer, error) {
+	balancers, err := c.listLoadBalancers()
+	if err != nil {
+		return nil, err
+	}
+	result := []*LoadBalancer{}
+	for _, balancer := range balancers {
+		if _, ok := balancer.tags[tagKey]; ok {
+			result = append(result, balancer.LoadBalancer)
+		}
+	}
+	return result, nil
+}
+
+// taggedLoadBalancer is a load balancer together with the VPC where it runs and its tags. The ARN
+// is empty for classic load balancers, which are identified by their names.
+type taggedLoadBalancer struct {
+	*LoadBalancer
+	arn   string
+	vpcID string
+	tags  map[string]string
+}
+
+// listLoadBalancers returns all the classic, application and network load balancers of the region
+// with their tags. The load balancer APIs don't support filtering by tag, so callers have to check
+// the tags themselves.
+func (c *awsClient) listLoadBalancers() ([]*taggedLoadBalancer, error) {
+	result := []*taggedLoadBalancer{}
+
+	// The tags can be retrieved for at most 20 load balancers at a time:
+	const batchSize = 20
+
+	elbClient := elb.New(c.awsSession)
+	classic := []*elb.LoadBalancerDescription{}
+	err := elbClient.DescribeLoadBalancersPages(&elb.DescribeLoadBalancersInput{},
+		func(page *elb.DescribeLoadBalancersOutput, lastPage bool) bool {
+			classic = append(classic, page.LoadBalancerDescriptions...)
+			return true
+		})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list classic load balancers: %v", err)
+	}
+	for start := 0; start < len(classic); start += batchSize {
+		end := start + batchSize
+		if end > len(classic) {
+			end = len(classic)
+		}
+		names := []*string{}
+		descriptions := map[string]*elb.LoadBalancerDescription{}
+		for _, description := range classic[start:end] {
+			names = append(names, description.LoadBalancerName)
+			descriptions[aws.StringValue(description.LoadBalancerName)] = description
+		}
+		tags, err := elbClient.DescribeTags(&elb.DescribeTagsInput{LoadBalancerNames: names})
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get tags of classic load balancers: %v", err)
+		}
+		for _, item := range tags.TagDescriptions {
+			description := descriptions[aws.StringValue(item.LoadBalancerName)]
+			balancer := &taggedLoadBalancer{
+				LoadBalancer: &LoadBalancer{
+					Name:   aws.StringValue(description.LoadBalancerName),
+					Type:   "classic",
+					Scheme: aws.StringValue(description.Scheme),
+					DNS:    aws.StringValue(description.DNSName),
+				},
+				vpcID: aws.StringValue(description.VPCId),
+				tags:  map[string]string{},
+			}
+			for _, tag := range item.Tags {
+				balancer.tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+			}
+			result = append(result, balancer)
+		}
+	}
+
+	elbv2Client := elbv2.New(c.awsSession)
+	balancers := []*elbv2.LoadBalancer{}
+	err = elbv2Client.DescribeLoadBalancersPages(&elbv2.DescribeLoadBalancersInput{},
+		func(page *elbv2.DescribeLoadBalancersOutput, lastPage bool) bool {
+			balancers = append(balancers, page.LoadBalancers...)
+			return true
+		})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list load balancers: %v", err)
+	}
+	for start := 0; start < len(balancers); start += batchSize {
+		end := start + batchSize
+		if end > len(balancers) {
+			end = len(balancers)
+		}
+		arns := []*string{}
+		descriptions := map[string]*elbv2.LoadBalancer{}
+		for _, balancer := range balancers[start:end] {
+			arns = append(arns, balancer.LoadBalancerArn)
+			descriptions[aws.StringValue(balancer.LoadBalancerArn)] = balancer
+		}
+		tags, err := elbv2Client.DescribeTags(&elbv2.DescribeTagsInput{ResourceArns: arns})
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get tags of load balancers: %v", err)
+		}
+		for _, item := range tags.TagDescriptions {
+			description := descriptions[aws.StringValue(item.ResourceArn)]
+			balancer := &taggedLoadBalancer{
+				LoadBalancer: &LoadBalancer{
+					Name:   aws.StringValue(description.LoadBalancerName),
+					Type:   aws.StringValue(description.Type),
+					Scheme: aws.StringValue(description.Scheme),
+					DNS:    aws.StringValue(description.DNSName),
+				},
+				arn:   aws.StringValue(description.LoadBalancerArn),
+				vpcID: aws.StringValue(description.VpcId),
+				tags:  map[string]string{},
+			}
+			for _, tag := range item.Tags {
+				balancer.tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+			}
+			result = append(result, balancer)
+		}
+	}
+
+	return result, nil
+}
+
+func tagValue(tags []*ec2.Tag, key string) string {
+	for _, tag := range tags {
+		if aws.StringValue(tag.Key) == key {
+			return aws.StringValue(tag.Value)
+		}
+	}
+	return ""
+}
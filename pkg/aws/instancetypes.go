@@ -0,0 +1,63 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that find the instance types that EC2 offers in each of the
+// availability zones of a region, as not all the instance types supported by the service are
+// offered in all the regions and zones.
+
+package aws
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// GetInstanceTypeZones returns the availability zones of the region of the client where each
+// instance type is offered, indexed by instance type. Local Zones aren't included.
+func (c *awsClient) GetInstanceTypeZones() (map[string][]string, error) {
+	zones, err := c.GetAvailabilityZones()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get availability zones of region '%s': %v", c.GetRegion(), err)
+	}
+	if len(zones) == 0 {
+		return map[string][]string{}, nil
+	}
+	result := map[string][]string{}
+	err = c.ec2Client.DescribeInstanceTypeOfferingsPages(&ec2.DescribeInstanceTypeOfferingsInput{
+		LocationType: aws.String(ec2.LocationTypeAvailabilityZone),
+		Filters: []*ec2.Filter{{
+			Name:   aws.String("location"),
+			Values: aws.StringSlice(zones),
+		}},
+	}, func(page *ec2.DescribeInstanceTypeOfferingsOutput, lastPage bool) bool {
+		for _, offering := range page.InstanceTypeOfferings {
+			instanceType := aws.StringValue(offering.InstanceType)
+			result[instanceType] = append(result[instanceType], aws.StringValue(offering.Location))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to describe instance types offered in region '%s': %v",
+			c.GetRegion(), err)
+	}
+	for _, offered := range result {
+		sort.Strings(offered)
+	}
+	return result, nil
+}
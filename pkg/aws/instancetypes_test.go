@@ -0,0 +1,89 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws_test
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mocks"
+)
+
+var _ = Describe("Instance types", func() {
+	var (
+		client   aws.Client
+		mockCtrl *gomock.Controller
+
+		mockEC2API *mocks.MockEC2API
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		var apis *apiMocks
+		client, apis = newClient(mockCtrl, regionSession("us-east-1"))
+		mockEC2API = apis.EC2
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("Returns the availability zones where each instance type is offered", func() {
+		mockEC2API.EXPECT().DescribeAvailabilityZones(gomock.Any()).Return(&ec2.DescribeAvailabilityZonesOutput{
+			AvailabilityZones: []*ec2.AvailabilityZone{
+				{ZoneName: awssdk.String("us-east-1a")},
+				{ZoneName: awssdk.String("us-east-1b")},
+			},
+		}, nil)
+		mockEC2API.EXPECT().DescribeInstanceTypeOfferingsPages(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(input *ec2.DescribeInstanceTypeOfferingsInput,
+				fn func(*ec2.DescribeInstanceTypeOfferingsOutput, bool) bool) error {
+				Expect(awssdk.StringValueSlice(input.Filters[0].Values)).To(
+					Equal([]string{"us-east-1a", "us-east-1b"}))
+				fn(&ec2.DescribeInstanceTypeOfferingsOutput{
+					InstanceTypeOfferings: []*ec2.InstanceTypeOffering{
+						{InstanceType: awssdk.String("m5.xlarge"), Location: awssdk.String("us-east-1b")},
+						{InstanceType: awssdk.String("m5.xlarge"), Location: awssdk.String("us-east-1a")},
+						{InstanceType: awssdk.String("p4d.24xlarge"), Location: awssdk.String("us-east-1b")},
+					},
+				}, true)
+				return nil
+			})
+
+		offerings, err := client.GetInstanceTypeZones()
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(offerings).To(Equal(map[string][]string{
+			"m5.xlarge":    {"us-east-1a", "us-east-1b"},
+			"p4d.24xlarge": {"us-east-1b"},
+		}))
+	})
+
+	It("Doesn't describe the offerings when the region has no availability zones", func() {
+		mockEC2API.EXPECT().DescribeAvailabilityZones(gomock.Any()).Return(
+			&ec2.DescribeAvailabilityZonesOutput{}, nil)
+
+		offerings, err := client.GetInstanceTypeZones()
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(offerings).To(BeEmpty())
+	})
+})
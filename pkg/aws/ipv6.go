@@ -0,0 +1,91 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the checks of the subnets of an existing VPC where a dual-stack cluster is
+// installed, as the nodes only get IPv6 addresses if their subnets have an IPv6 block.
+
+package aws
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// ValidateIPv6Subnets checks that each of the given subnets has an associated IPv6 block and, if
+// the IPv6 machine block of the cluster is given, that the blocks of the subnets are inside it.
+// The IPv4 configuration of the subnets is checked by ValidateSubnets. If the subnets can't be used
+// the returned error is a *SubnetsError listing all the problems found.
+func (c *awsClient) ValidateIPv6Subnets(subnetIDs []string, machineCIDR *net.IPNet) error {
+	subnets, err := c.FindSubnets(subnetIDs)
+	if err != nil {
+		return fmt.Errorf("Failed to describe subnets: %v", err)
+	}
+	if len(subnets) == 0 {
+		return &SubnetsError{Problems: []string{
+			fmt.Sprintf("none of the subnets exist in region '%s'", c.GetRegion()),
+		}}
+	}
+
+	problems := []string{}
+	for _, subnet := range subnets {
+		subnetID := aws.StringValue(subnet.SubnetId)
+		blocks := associatedIPv6Blocks(subnet)
+		if len(blocks) == 0 {
+			problems = append(problems, fmt.Sprintf("subnet '%s' doesn't have an associated IPv6 block, "+
+				"which dual-stack clusters need", subnetID))
+			continue
+		}
+		if machineCIDR == nil {
+			continue
+		}
+		for _, block := range blocks {
+			_, cidr, err := net.ParseCIDR(block)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("IPv6 block '%s' of subnet '%s' isn't valid: %v",
+					block, subnetID, err))
+				continue
+			}
+			ones, _ := cidr.Mask.Size()
+			machineOnes, _ := machineCIDR.Mask.Size()
+			if !machineCIDR.Contains(cidr.IP) || ones < machineOnes {
+				problems = append(problems, fmt.Sprintf("IPv6 block '%s' of subnet '%s' isn't inside the "+
+					"IPv6 machine block '%s'", block, subnetID, machineCIDR))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &SubnetsError{Problems: problems}
+	}
+	return nil
+}
+
+// associatedIPv6Blocks returns the IPv6 blocks of the given subnet whose association is complete.
+// Blocks that are being associated or disassociated can't be used yet.
+func associatedIPv6Blocks(subnet *ec2.Subnet) []string {
+	blocks := []string{}
+	for _, association := range subnet.Ipv6CidrBlockAssociationSet {
+		if association.Ipv6CidrBlockState == nil ||
+			aws.StringValue(association.Ipv6CidrBlockState.State) != ec2.SubnetCidrBlockStateCodeAssociated {
+			continue
+		}
+		blocks = append(blocks, aws.StringValue(association.Ipv6CidrBlock))
+	}
+	return blocks
+}
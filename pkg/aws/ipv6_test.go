@@ -0,0 +1,95 @@
+package aws_test
+
+import (
+	"net"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mocks"
+)
+
+var _ = Describe("ValidateIPv6Subnets", func() {
+	var (
+		client   aws.Client
+		mockCtrl *gomock.Controller
+
+		mockEC2API *mocks.MockEC2API
+	)
+
+	subnet := func(id string, block string, state string) *ec2.Subnet {
+		subnet := &ec2.Subnet{
+			SubnetId: awssdk.String(id),
+			VpcId:    awssdk.String("vpc-1"),
+		}
+		if block != "" {
+			subnet.Ipv6CidrBlockAssociationSet = []*ec2.SubnetIpv6CidrBlockAssociation{{
+				Ipv6CidrBlock: awssdk.String(block),
+				Ipv6CidrBlockState: &ec2.SubnetCidrBlockState{
+					State: awssdk.String(state),
+				},
+			}}
+		}
+		return subnet
+	}
+
+	subnets := func(subnets ...*ec2.Subnet) {
+		mockEC2API.EXPECT().DescribeSubnets(gomock.Any()).Return(&ec2.DescribeSubnetsOutput{
+			Subnets: subnets,
+		}, nil)
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		var apis *apiMocks
+		client, apis = newClient(mockCtrl, regionSession("us-east-1"))
+		mockEC2API = apis.EC2
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("Accepts subnets with associated IPv6 blocks", func() {
+		subnets(
+			subnet("subnet-1", "2600:1f18:1::/64", ec2.SubnetCidrBlockStateCodeAssociated),
+			subnet("subnet-2", "2600:1f18:2::/64", ec2.SubnetCidrBlockStateCodeAssociated),
+		)
+
+		err := client.ValidateIPv6Subnets([]string{"subnet-1", "subnet-2"}, nil)
+
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Reports subnets without IPv6 blocks or with blocks that aren't associated yet", func() {
+		subnets(
+			subnet("subnet-1", "", ""),
+			subnet("subnet-2", "2600:1f18:2::/64", ec2.SubnetCidrBlockStateCodeAssociating),
+		)
+
+		err := client.ValidateIPv6Subnets([]string{"subnet-1", "subnet-2"}, nil)
+
+		Expect(err).To(BeAssignableToTypeOf(&aws.SubnetsError{}))
+		Expect(err.(*aws.SubnetsError).Problems).To(HaveLen(2))
+		Expect(err.Error()).To(ContainSubstring("subnet 'subnet-1' doesn't have an associated IPv6 block"))
+		Expect(err.Error()).To(ContainSubstring("subnet 'subnet-2' doesn't have an associated IPv6 block"))
+	})
+
+	It("Reports IPv6 blocks outside of the machine block", func() {
+		subnets(
+			subnet("subnet-1", "2600:1f18:0:1::/64", ec2.SubnetCidrBlockStateCodeAssociated),
+			subnet("subnet-2", "2600:1f19:2::/64", ec2.SubnetCidrBlockStateCodeAssociated),
+		)
+		_, machineCIDR, _ := net.ParseCIDR("2600:1f18::/48")
+
+		err := client.ValidateIPv6Subnets([]string{"subnet-1", "subnet-2"}, machineCIDR)
+
+		Expect(err).To(BeAssignableToTypeOf(&aws.SubnetsError{}))
+		Expect(err.(*aws.SubnetsError).Problems).To(HaveLen(1))
+		Expect(err.Error()).To(ContainSubstring("IPv6 block '2600:1f19:2::/64' of subnet 'subnet-2'"))
+	})
+})
@@ -0,0 +1,54 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to check the customer managed KMS keys that encrypt the
+// data of clusters.
+
+package aws
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+)
+
+var kmsKeyIDRE = regexp.MustCompile(`^[a-zA-Z0-9-]+$`)
+
+// ValidateKMSKeyARN checks that the given ARN is the ARN of a KMS key, and that the key is in the
+// region of the cluster, as keys can't be used from other regions. Aliases aren't accepted, as
+// they can be changed to point to a different key after the cluster is created.
+func ValidateKMSKeyARN(keyARN string, region string) error {
+	parsed, err := arn.Parse(keyARN)
+	if err != nil {
+		return fmt.Errorf("KMS key ARN '%s' isn't valid: it isn't a valid ARN", keyARN)
+	}
+	if parsed.Service != "kms" {
+		return fmt.Errorf("KMS key ARN '%s' isn't valid: it is an ARN of service '%s' instead of 'kms'",
+			keyARN, parsed.Service)
+	}
+	if !strings.HasPrefix(parsed.Resource, "key/") ||
+		!kmsKeyIDRE.MatchString(strings.TrimPrefix(parsed.Resource, "key/")) {
+		return fmt.Errorf("KMS key ARN '%s' isn't valid: the resource must be 'key/' followed by the "+
+			"identifier of the key", keyARN)
+	}
+	if parsed.Region != region {
+		return fmt.Errorf("KMS key '%s' is in region '%s', but the cluster is in region '%s'",
+			keyARN, parsed.Region, region)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package aws_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws"
+)
+
+var _ = Describe("ValidateKMSKeyARN", func() {
+	const keyARN = "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+
+	It("Accepts keys in the region of the cluster", func() {
+		Expect(aws.ValidateKMSKeyARN(keyARN, "us-east-1")).To(Succeed())
+	})
+
+	It("Rejects malformed ARNs", func() {
+		err := aws.ValidateKMSKeyARN("1234abcd-12ab-34cd-56ef-1234567890ab", "us-east-1")
+
+		Expect(err).To(MatchError(ContainSubstring("it isn't a valid ARN")))
+	})
+
+	It("Rejects ARNs of other services", func() {
+		err := aws.ValidateKMSKeyARN("arn:aws:iam::123456789012:role/test", "us-east-1")
+
+		Expect(err).To(MatchError(ContainSubstring("instead of 'kms'")))
+	})
+
+	It("Rejects aliases", func() {
+		err := aws.ValidateKMSKeyARN("arn:aws:kms:us-east-1:123456789012:alias/mykey", "us-east-1")
+
+		Expect(err).To(MatchError(ContainSubstring("the resource must be 'key/'")))
+	})
+
+	It("Rejects keys in other regions", func() {
+		err := aws.ValidateKMSKeyARN(keyARN, "eu-west-1")
+
+		Expect(err).To(MatchError(ContainSubstring("is in region 'us-east-1'")))
+	})
+})
@@ -0,0 +1,297 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that find and delete the AWS resources that are left behind
+// after a cluster has been uninstalled.
+
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/openshift/moactl/pkg/aws/tags"
+)
+
+// Types of the resources that can be left behind by a cluster:
+const (
+	LeftoverClassicLoadBalancer = "classic-load-balancer"
+	LeftoverLoadBalancer        = "load-balancer"
+	LeftoverBucket              = "s3-bucket"
+	LeftoverRole                = "iam-role"
+)
+
+// Leftover is an AWS resource of a cluster that still exists after the cluster has been
+// uninstalled. The identifier is the name of classic load balancers, buckets and roles, and the
+// ARN of application and network load balancers.
+type Leftover struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// maxDeletedObjects is the maximum number of objects that can be deleted from a bucket with one
+// request.
+const maxDeletedObjects = 1000
+
+// FindLeftovers finds the load balancers, S3 buckets and IAM roles of the cluster with the given
+// infrastructure identifier and cluster identifier. Load balancers and buckets are those tagged by
+// the installer as owned by the cluster, and roles are also those tagged with the identifier of the
+// cluster, like the operator roles. Load balancers are only searched in the region of the client.
+func (c *awsClient) FindLeftovers(infraID string, clusterID string) ([]*Leftover, error) {
+	tagKey := clusterTagKey(infraID)
+	result := []*Leftover{}
+
+	balancers, err := c.listLoadBalancers()
+	if err != nil {
+		return nil, err
+	}
+	for _, balancer := range balancers {
+		if balancer.tags[tagKey] != "owned" {
+			continue
+		}
+		leftover := &Leftover{
+			Type: LeftoverLoadBalancer,
+			ID:   balancer.arn,
+			Name: balancer.Name,
+		}
+		if balancer.Type == "classic" {
+			leftover.Type = LeftoverClassicLoadBalancer
+			leftover.ID = balancer.Name
+		}
+		result = append(result, leftover)
+	}
+
+	buckets, err := c.findLeftoverBuckets(infraID, tagKey)
+	if err != nil {
+		return nil, err
+	}
+	result = append(result, buckets...)
+
+	roles, err := c.findLeftoverRoles(tagKey, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	result = append(result, roles...)
+
+	return result, nil
+}
+
+// findLeftoverBuckets finds the buckets owned by the cluster. The installer names them starting
+// with the infrastructure identifier, like the bucket of the image registry, so only those are
+// checked.
+func (c *awsClient) findLeftoverBuckets(infraID string, tagKey string) ([]*Leftover, error) {
+	buckets, err := c.s3Client.ListBuckets(&s3.ListBucketsInput{})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list S3 buckets: %v", err)
+	}
+	result := []*Leftover{}
+	for _, bucket := range buckets.Buckets {
+		name := aws.StringValue(bucket.Name)
+		if !strings.HasPrefix(name, infraID) {
+			continue
+		}
+		tagging, err := c.s3Client.GetBucketTagging(&s3.GetBucketTaggingInput{
+			Bucket: bucket.Name,
+		})
+		if err != nil {
+			// Buckets without tags return an error, and they aren't owned by the cluster:
+			c.logger.Debugf("Failed to get tags of S3 bucket '%s': %v", name, err)
+			continue
+		}
+		for _, tag := range tagging.TagSet {
+			if aws.StringValue(tag.Key) == tagKey && aws.StringValue(tag.Value) == "owned" {
+				result = append(result, &Leftover{
+					Type: LeftoverBucket,
+					ID:   name,
+					Name: name,
+				})
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// findLeftoverRoles finds the roles owned by the cluster or tagged with its identifier. Roles
+// aren't returned with their tags when they are listed, so the tags of each role are requested.
+func (c *awsClient) findLeftoverRoles(tagKey string, clusterID string) ([]*Leftover, error) {
+	names := []string{}
+	err := c.iamClient.ListRolesPages(&iam.ListRolesInput{},
+		func(page *iam.ListRolesOutput, lastPage bool) bool {
+			for _, role := range page.Roles {
+				names = append(names, aws.StringValue(role.RoleName))
+			}
+			return true
+		})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list IAM roles: %v", err)
+	}
+	result := []*Leftover{}
+	for _, name := range names {
+		output, err := c.iamClient.ListRoleTags(&iam.ListRoleTagsInput{
+			RoleName: aws.String(name),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get tags of IAM role '%s': %v", name, err)
+		}
+		for _, tag := range output.Tags {
+			key := aws.StringValue(tag.Key)
+			value := aws.StringValue(tag.Value)
+			if (key == tagKey && value == "owned") || (key == tags.ClusterID && value == clusterID) {
+				result = append(result, &Leftover{
+					Type: LeftoverRole,
+					ID:   name,
+					Name: name,
+				})
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// DeleteLeftover deletes the given resource. Buckets are emptied first, and the policies and the
+// instance profiles of roles are removed first.
+func (c *awsClient) DeleteLeftover(leftover *Leftover) error {
+	switch leftover.Type {
+	case LeftoverClassicLoadBalancer:
+		_, err := elb.New(c.awsSession).DeleteLoadBalancer(&elb.DeleteLoadBalancerInput{
+			LoadBalancerName: aws.String(leftover.ID),
+		})
+		return err
+	case LeftoverLoadBalancer:
+		_, err := elbv2.New(c.awsSession).DeleteLoadBalancer(&elbv2.DeleteLoadBalancerInput{
+			LoadBalancerArn: aws.String(leftover.ID),
+		})
+		return err
+	case LeftoverBucket:
+		return c.deleteBucket(leftover.ID)
+	case LeftoverRole:
+		return c.deleteRole(leftover.ID)
+	default:
+		return fmt.Errorf("Unknown type of resource '%s'", leftover.Type)
+	}
+}
+
+// deleteBucket deletes all the versions of the objects of the given bucket, and then the bucket.
+func (c *awsClient) deleteBucket(name string) error {
+	objects := []*s3.ObjectIdentifier{}
+	err := c.s3Client.ListObjectVersionsPages(&s3.ListObjectVersionsInput{Bucket: aws.String(name)},
+		func(page *s3.ListObjectVersionsOutput, lastPage bool) bool {
+			for _, version := range page.Versions {
+				objects = append(objects, &s3.ObjectIdentifier{
+					Key:       version.Key,
+					VersionId: version.VersionId,
+				})
+			}
+			for _, marker := range page.DeleteMarkers {
+				objects = append(objects, &s3.ObjectIdentifier{
+					Key:       marker.Key,
+					VersionId: marker.VersionId,
+				})
+			}
+			return true
+		})
+	if err != nil {
+		return fmt.Errorf("Failed to list objects: %v", err)
+	}
+	for start := 0; start < len(objects); start += maxDeletedObjects {
+		end := start + maxDeletedObjects
+		if end > len(objects) {
+			end = len(objects)
+		}
+		_, err = c.s3Client.DeleteObjects(&s3.DeleteObjectsInput{
+			Bucket: aws.String(name),
+			Delete: &s3.Delete{
+				Objects: objects[start:end],
+				Quiet:   aws.Bool(true),
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to delete objects: %v", err)
+		}
+	}
+	_, err = c.s3Client.DeleteBucket(&s3.DeleteBucketInput{Bucket: aws.String(name)})
+	return err
+}
+
+// deleteRole removes the given role from its instance profiles, deletes its inline policies and
+// detaches its managed policies, and then deletes the role. The managed policies themselves are
+// kept, as they may be used by other roles.
+func (c *awsClient) deleteRole(name string) error {
+	profiles, err := c.iamClient.ListInstanceProfilesForRole(&iam.ListInstanceProfilesForRoleInput{
+		RoleName: aws.String(name),
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to list instance profiles: %v", err)
+	}
+	for _, profile := range profiles.InstanceProfiles {
+		_, err = c.iamClient.RemoveRoleFromInstanceProfile(&iam.RemoveRoleFromInstanceProfileInput{
+			InstanceProfileName: profile.InstanceProfileName,
+			RoleName:            aws.String(name),
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to remove role from instance profile '%s': %v",
+				aws.StringValue(profile.InstanceProfileName), err)
+		}
+	}
+
+	policies, err := c.iamClient.ListRolePolicies(&iam.ListRolePoliciesInput{
+		RoleName: aws.String(name),
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to list inline policies: %v", err)
+	}
+	for _, policy := range policies.PolicyNames {
+		_, err = c.iamClient.DeleteRolePolicy(&iam.DeleteRolePolicyInput{
+			PolicyName: policy,
+			RoleName:   aws.String(name),
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to delete inline policy '%s': %v", aws.StringValue(policy), err)
+		}
+	}
+
+	attached, err := c.iamClient.ListAttachedRolePolicies(&iam.ListAttachedRolePoliciesInput{
+		RoleName: aws.String(name),
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to list attached policies: %v", err)
+	}
+	for _, policy := range attached.AttachedPolicies {
+		_, err = c.iamClient.DetachRolePolicy(&iam.DetachRolePolicyInput{
+			PolicyArn: policy.PolicyArn,
+			RoleName:  aws.String(name),
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to detach policy '%s': %v", aws.StringValue(policy.PolicyArn), err)
+		}
+	}
+
+	_, err = c.iamClient.DeleteRole(&iam.DeleteRoleInput{RoleName: aws.String(name)})
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == iam.ErrCodeNoSuchEntityException {
+		return nil
+	}
+	return err
+}
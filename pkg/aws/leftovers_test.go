@@ -0,0 +1,102 @@
+package aws_test
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mocks"
+)
+
+var _ = Describe("DeleteLeftover", func() {
+	var (
+		client     aws.Client
+		mockCtrl   *gomock.Controller
+		mockIamAPI *mocks.MockIAMAPI
+		mockS3API  *mocks.MockS3API
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		var apis *apiMocks
+		client, apis = newClient(mockCtrl, &session.Session{})
+		mockIamAPI = apis.IAM
+		mockS3API = apis.S3
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("Removes the policies and the instance profiles of roles before deleting them", func() {
+		mockIamAPI.EXPECT().ListInstanceProfilesForRole(gomock.Any()).Return(
+			&iam.ListInstanceProfilesForRoleOutput{
+				InstanceProfiles: []*iam.InstanceProfile{
+					{InstanceProfileName: awssdk.String("fake-profile")},
+				},
+			}, nil)
+		removed := mockIamAPI.EXPECT().RemoveRoleFromInstanceProfile(gomock.Any()).Return(nil, nil)
+		mockIamAPI.EXPECT().ListRolePolicies(gomock.Any()).Return(&iam.ListRolePoliciesOutput{
+			PolicyNames: []*string{awssdk.String("fake-inline")},
+		}, nil)
+		deleted := mockIamAPI.EXPECT().DeleteRolePolicy(gomock.Any()).Return(nil, nil)
+		mockIamAPI.EXPECT().ListAttachedRolePolicies(gomock.Any()).Return(
+			&iam.ListAttachedRolePoliciesOutput{
+				AttachedPolicies: []*iam.AttachedPolicy{
+					{PolicyArn: awssdk.String("arn:aws:iam::123456789012:policy/fake")},
+				},
+			}, nil)
+		detached := mockIamAPI.EXPECT().DetachRolePolicy(gomock.Any()).Return(nil, nil)
+		mockIamAPI.EXPECT().DeleteRole(&iam.DeleteRoleInput{
+			RoleName: awssdk.String("fake-role"),
+		}).After(removed).After(deleted).After(detached).Return(nil, nil)
+
+		err := client.DeleteLeftover(&aws.Leftover{
+			Type: aws.LeftoverRole,
+			ID:   "fake-role",
+			Name: "fake-role",
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Empties buckets before deleting them", func() {
+		mockS3API.EXPECT().ListObjectVersionsPages(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(input *s3.ListObjectVersionsInput, fn func(*s3.ListObjectVersionsOutput, bool) bool) error {
+				fn(&s3.ListObjectVersionsOutput{
+					Versions: []*s3.ObjectVersion{
+						{Key: awssdk.String("a"), VersionId: awssdk.String("1")},
+						{Key: awssdk.String("b"), VersionId: awssdk.String("2")},
+					},
+				}, true)
+				return nil
+			})
+		emptied := mockS3API.EXPECT().DeleteObjects(gomock.Any()).DoAndReturn(
+			func(input *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error) {
+				Expect(input.Delete.Objects).To(HaveLen(2))
+				return &s3.DeleteObjectsOutput{}, nil
+			})
+		mockS3API.EXPECT().DeleteBucket(&s3.DeleteBucketInput{
+			Bucket: awssdk.String("fake-bucket"),
+		}).After(emptied).Return(nil, nil)
+
+		err := client.DeleteLeftover(&aws.Leftover{
+			Type: aws.LeftoverBucket,
+			ID:   "fake-bucket",
+			Name: "fake-bucket",
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Fails for unknown types of resources", func() {
+		err := client.DeleteLeftover(&aws.Leftover{
+			Type: "fake-type",
+			ID:   "fake",
+		})
+		Expect(err).To(HaveOccurred())
+	})
+})
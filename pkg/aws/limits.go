@@ -0,0 +1,244 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that check how close the account is to the AWS limits that
+// most often make installations fail, and that recognize the errors caused by those limits in the
+// install logs, so that users know which quota to raise.
+
+package aws
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+
+	"github.com/openshift/moactl/pkg/aws/quotas"
+)
+
+// limitUsageThreshold is the fraction of a limit above which the usage is reported as nearing it.
+const limitUsageThreshold = 0.9
+
+// limit describes one of the AWS limits that installations run into, and the codes of the errors
+// that AWS returns when it is exceeded.
+type limit struct {
+	quotas.Quota
+	errorCodes []string
+}
+
+// limits are the AWS limits whose usage is checked while watching installations.
+var limits = []limit{
+	{
+		Quota: quotas.Quota{
+			ServiceCode: "ec2",
+			QuotaCode:   "L-0263D0A3",
+			QuotaName:   "Number of EIPs - VPC EIPs",
+		},
+		errorCodes: []string{"AddressLimitExceeded"},
+	},
+	{
+		Quota: quotas.Quota{
+			ServiceCode: "vpc",
+			QuotaCode:   "L-F678F1CE",
+			QuotaName:   "VPCs per Region",
+		},
+		errorCodes: []string{"VpcLimitExceeded"},
+	},
+	{
+		Quota: quotas.Quota{
+			ServiceCode: "vpc",
+			QuotaCode:   "L-0EA8095F",
+			QuotaName:   "Inbound or outbound rules per security group",
+		},
+		errorCodes: []string{"RulesPerSecurityGroupLimitExceeded"},
+	},
+	{
+		Quota: quotas.Quota{
+			ServiceCode: "vpc",
+			QuotaCode:   "L-A4707A72",
+			QuotaName:   "Internet gateways per Region",
+		},
+		errorCodes: []string{"InternetGatewayLimitExceeded"},
+	},
+	{
+		Quota: quotas.Quota{
+			ServiceCode: "vpc",
+			QuotaCode:   "L-FE5A380F",
+			QuotaName:   "NAT gateways per Availability Zone",
+		},
+		errorCodes: []string{"NatGatewayLimitExceeded"},
+	},
+	{
+		Quota: quotas.Quota{
+			ServiceCode: "ec2",
+			QuotaCode:   "L-1216C47A",
+			QuotaName:   "Running On-Demand Standard (A, C, D, H, I, M, R, T, Z) instances",
+		},
+		errorCodes: []string{"InstanceLimitExceeded", "VcpuLimitExceeded"},
+	},
+}
+
+// limitErrorRE matches the codes of the AWS errors about exceeded limits, as they appear in the
+// install logs and in the CloudFormation and Hive events.
+var limitErrorRE = regexp.MustCompile(`\b[A-Za-z]*LimitExceeded\b`)
+
+// LimitUsage is the usage of one of the AWS limits that installations run into.
+type LimitUsage struct {
+	ServiceCode string
+	QuotaCode   string
+	QuotaName   string
+	Used        float64
+	Value       float64
+}
+
+// Nearing checks if the usage is close enough to the limit that the installation may exceed it.
+func (u *LimitUsage) Nearing() bool {
+	return u.Used >= u.Value*limitUsageThreshold
+}
+
+// LimitError is a limit that an error of the install logs says was exceeded.
+type LimitError struct {
+	// Code is the code of the AWS error, for example AddressLimitExceeded.
+	Code string
+
+	// ServiceCode, QuotaCode and QuotaName identify the quota to raise. They are empty when the
+	// error isn't about one of the known limits.
+	ServiceCode string
+	QuotaCode   string
+	QuotaName   string
+}
+
+func (e *LimitError) Error() string {
+	if e.QuotaCode == "" {
+		return fmt.Sprintf("AWS limit exceeded (%s)", e.Code)
+	}
+	return fmt.Sprintf("AWS limit exceeded (%s): raise service %s quota code %s '%s'",
+		e.Code, e.ServiceCode, e.QuotaCode, e.QuotaName)
+}
+
+// FindLimitErrors returns the limits that the given log lines say were exceeded, once each, in the
+// order they appear.
+func FindLimitErrors(lines string) []*LimitError {
+	result := []*LimitError{}
+	seen := map[string]bool{}
+	for _, code := range limitErrorRE.FindAllString(lines, -1) {
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		limitError := &LimitError{Code: code}
+		for _, limit := range limits {
+			for _, errorCode := range limit.errorCodes {
+				if errorCode == code {
+					limitError.ServiceCode = limit.ServiceCode
+					limitError.QuotaCode = limit.QuotaCode
+					limitError.QuotaName = limit.QuotaName
+				}
+			}
+		}
+		result = append(result, limitError)
+	}
+	return result
+}
+
+// GetLimitUsage returns the usage in the region of the client of the limits that installations
+// run into most often and that can be counted: Elastic IPs, VPCs and rules per security group,
+// where the usage is that of the security group with the most rules.
+func (c *awsClient) GetLimitUsage() ([]*LimitUsage, error) {
+	used := map[string]float64{}
+
+	addresses, err := c.ec2Client.DescribeAddresses(&ec2.DescribeAddressesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("domain"),
+				Values: aws.StringSlice([]string{ec2.DomainTypeVpc}),
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	used["L-0263D0A3"] = float64(len(addresses.Addresses))
+
+	vpcs := 0
+	err = c.ec2Client.DescribeVpcsPages(&ec2.DescribeVpcsInput{},
+		func(page *ec2.DescribeVpcsOutput, lastPage bool) bool {
+			vpcs += len(page.Vpcs)
+			return true
+		})
+	if err != nil {
+		return nil, err
+	}
+	used["L-F678F1CE"] = float64(vpcs)
+
+	rules := 0
+	err = c.ec2Client.DescribeSecurityGroupsPages(&ec2.DescribeSecurityGroupsInput{},
+		func(page *ec2.DescribeSecurityGroupsOutput, lastPage bool) bool {
+			for _, group := range page.SecurityGroups {
+				if count := countRules(group.IpPermissions); count > rules {
+					rules = count
+				}
+				if count := countRules(group.IpPermissionsEgress); count > rules {
+					rules = count
+				}
+			}
+			return true
+		})
+	if err != nil {
+		return nil, err
+	}
+	used["L-0EA8095F"] = float64(rules)
+
+	quotas := map[string][]*servicequotas.ServiceQuota{}
+	result := []*LimitUsage{}
+	for _, limit := range limits {
+		value, ok := used[limit.QuotaCode]
+		if !ok {
+			continue
+		}
+		if _, ok := quotas[limit.ServiceCode]; !ok {
+			quotas[limit.ServiceCode], err = ListServiceQuotas(c, limit.ServiceCode)
+			if err != nil {
+				return nil, err
+			}
+		}
+		serviceQuota, err := GetServiceQuota(quotas[limit.ServiceCode], limit.QuotaCode)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &LimitUsage{
+			ServiceCode: limit.ServiceCode,
+			QuotaCode:   limit.QuotaCode,
+			QuotaName:   limit.QuotaName,
+			Used:        value,
+			Value:       aws.Float64Value(serviceQuota.Value),
+		})
+	}
+	return result, nil
+}
+
+// countRules returns the number of rules of a security group, which AWS counts per source or
+// destination rather than per permission.
+func countRules(permissions []*ec2.IpPermission) int {
+	count := 0
+	for _, permission := range permissions {
+		count += len(permission.IpRanges) + len(permission.Ipv6Ranges) + len(permission.PrefixListIds) +
+			len(permission.UserIdGroupPairs)
+	}
+	return count
+}
@@ -0,0 +1,101 @@
+package aws_test
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mocks"
+)
+
+var _ = Describe("Limits", func() {
+	Context("FindLimitErrors", func() {
+		It("Finds the quota to raise for known limits", func() {
+			errors := aws.FindLimitErrors(
+				"level=info msg=Creating infrastructure resources...\n" +
+					"level=error msg=Error: Error creating EIP: AddressLimitExceeded: The maximum number " +
+					"of addresses has been reached.\n" +
+					"level=error msg=Error: AddressLimitExceeded again\n")
+
+			Expect(errors).To(HaveLen(1))
+			Expect(errors[0].Code).To(Equal("AddressLimitExceeded"))
+			Expect(errors[0].QuotaCode).To(Equal("L-0263D0A3"))
+		})
+
+		It("Reports unknown limits without a quota", func() {
+			errors := aws.FindLimitErrors("Error: LoadBalancerLimitExceeded: too many")
+
+			Expect(errors).To(HaveLen(1))
+			Expect(errors[0].QuotaCode).To(BeEmpty())
+		})
+
+		It("Ignores logs without limit errors", func() {
+			Expect(aws.FindLimitErrors("level=info msg=Install complete!")).To(BeEmpty())
+		})
+	})
+
+	Context("GetLimitUsage", func() {
+		var (
+			mockCtrl             *gomock.Controller
+			mockEC2API           *mocks.MockEC2API
+			mockServiceQuotasAPI *mocks.MockServiceQuotasAPI
+			client               aws.Client
+		)
+
+		BeforeEach(func() {
+			mockCtrl = gomock.NewController(GinkgoT())
+			var apis *apiMocks
+			client, apis = newClient(mockCtrl, &session.Session{})
+			mockEC2API = apis.EC2
+			mockServiceQuotasAPI = apis.ServiceQuotas
+		})
+
+		AfterEach(func() {
+			mockCtrl.Finish()
+		})
+
+		It("Compares the usage with the quotas", func() {
+			mockEC2API.EXPECT().DescribeAddresses(gomock.Any()).Return(&ec2.DescribeAddressesOutput{
+				Addresses: []*ec2.Address{{}, {}, {}, {}, {}},
+			}, nil)
+			mockEC2API.EXPECT().DescribeVpcsPages(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(input *ec2.DescribeVpcsInput, fn func(*ec2.DescribeVpcsOutput, bool) bool) error {
+					fn(&ec2.DescribeVpcsOutput{Vpcs: []*ec2.Vpc{{}}}, true)
+					return nil
+				})
+			mockEC2API.EXPECT().DescribeSecurityGroupsPages(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(input *ec2.DescribeSecurityGroupsInput,
+					fn func(*ec2.DescribeSecurityGroupsOutput, bool) bool) error {
+					fn(&ec2.DescribeSecurityGroupsOutput{SecurityGroups: []*ec2.SecurityGroup{{
+						IpPermissions: []*ec2.IpPermission{{
+							IpRanges: []*ec2.IpRange{{}, {}},
+						}},
+					}}}, true)
+					return nil
+				})
+			mockServiceQuotasAPI.EXPECT().ListServiceQuotasPages(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(input *servicequotas.ListServiceQuotasInput,
+					fn func(*servicequotas.ListServiceQuotasOutput, bool) bool) error {
+					fn(&servicequotas.ListServiceQuotasOutput{Quotas: []*servicequotas.ServiceQuota{
+						{QuotaCode: awssdk.String("L-0263D0A3"), Value: awssdk.Float64(5)},
+						{QuotaCode: awssdk.String("L-F678F1CE"), Value: awssdk.Float64(5)},
+						{QuotaCode: awssdk.String("L-0EA8095F"), Value: awssdk.Float64(60)},
+					}}, true)
+					return nil
+				}).Times(2)
+
+			usages, err := client.GetLimitUsage()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(usages).To(HaveLen(3))
+			Expect(usages[0].Nearing()).To(BeTrue())
+			Expect(usages[1].Nearing()).To(BeFalse())
+			Expect(usages[2].Used).To(Equal(2.0))
+		})
+	})
+})
@@ -0,0 +1,952 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/openshift/moactl/pkg/aws (interfaces: Client)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	credentials "github.com/aws/aws-sdk-go/aws/credentials"
+	ec2 "github.com/aws/aws-sdk-go/service/ec2"
+	gomock "github.com/golang/mock/gomock"
+	aws "github.com/openshift/moactl/pkg/aws"
+	net "net"
+	reflect "reflect"
+	time "time"
+)
+
+// MockClient is a mock of Client interface
+type MockClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientMockRecorder
+}
+
+// MockClientMockRecorder is the mock recorder for MockClient
+type MockClientMockRecorder struct {
+	mock *MockClient
+}
+
+// NewMockClient creates a new mock instance
+func NewMockClient(ctrl *gomock.Controller) *MockClient {
+	mock := &MockClient{ctrl: ctrl}
+	mock.recorder = &MockClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockClient) EXPECT() *MockClientMockRecorder {
+	return m.recorder
+}
+
+// CheckAdminUserNotExisting mocks base method
+func (m *MockClient) CheckAdminUserNotExisting(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckAdminUserNotExisting", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckAdminUserNotExisting indicates an expected call of CheckAdminUserNotExisting
+func (mr *MockClientMockRecorder) CheckAdminUserNotExisting(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckAdminUserNotExisting", reflect.TypeOf((*MockClient)(nil).CheckAdminUserNotExisting), arg0)
+}
+
+// CheckQuotas mocks base method
+func (m *MockClient) CheckQuotas() ([]*aws.QuotaCheck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckQuotas")
+	ret0, _ := ret[0].([]*aws.QuotaCheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckQuotas indicates an expected call of CheckQuotas
+func (mr *MockClientMockRecorder) CheckQuotas() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckQuotas", reflect.TypeOf((*MockClient)(nil).CheckQuotas))
+}
+
+// CheckStackReadyOrNotExisting mocks base method
+func (m *MockClient) CheckStackReadyOrNotExisting(arg0 string) (bool, *string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckStackReadyOrNotExisting", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(*string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CheckStackReadyOrNotExisting indicates an expected call of CheckStackReadyOrNotExisting
+func (mr *MockClientMockRecorder) CheckStackReadyOrNotExisting(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckStackReadyOrNotExisting", reflect.TypeOf((*MockClient)(nil).CheckStackReadyOrNotExisting), arg0)
+}
+
+// CreateAccountRole mocks base method
+func (m *MockClient) CreateAccountRole(arg0 string, arg1 aws.AccountRoleType, arg2 aws.IAMSettings) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccountRole", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccountRole indicates an expected call of CreateAccountRole
+func (mr *MockClientMockRecorder) CreateAccountRole(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountRole", reflect.TypeOf((*MockClient)(nil).CreateAccountRole), arg0, arg1, arg2)
+}
+
+// CreateELBServiceLinkedRole mocks base method
+func (m *MockClient) CreateELBServiceLinkedRole() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateELBServiceLinkedRole")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateELBServiceLinkedRole indicates an expected call of CreateELBServiceLinkedRole
+func (mr *MockClientMockRecorder) CreateELBServiceLinkedRole() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateELBServiceLinkedRole", reflect.TypeOf((*MockClient)(nil).CreateELBServiceLinkedRole))
+}
+
+// CreateOIDCBucket mocks base method
+func (m *MockClient) CreateOIDCBucket(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOIDCBucket", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOIDCBucket indicates an expected call of CreateOIDCBucket
+func (mr *MockClientMockRecorder) CreateOIDCBucket(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOIDCBucket", reflect.TypeOf((*MockClient)(nil).CreateOIDCBucket), arg0)
+}
+
+// CreateOIDCPrivateKeySecret mocks base method
+func (m *MockClient) CreateOIDCPrivateKeySecret(arg0 string, arg1 []byte) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOIDCPrivateKeySecret", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOIDCPrivateKeySecret indicates an expected call of CreateOIDCPrivateKeySecret
+func (mr *MockClientMockRecorder) CreateOIDCPrivateKeySecret(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOIDCPrivateKeySecret", reflect.TypeOf((*MockClient)(nil).CreateOIDCPrivateKeySecret), arg0, arg1)
+}
+
+// CreateOIDCProvider mocks base method
+func (m *MockClient) CreateOIDCProvider(arg0, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOIDCProvider", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOIDCProvider indicates an expected call of CreateOIDCProvider
+func (mr *MockClientMockRecorder) CreateOIDCProvider(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOIDCProvider", reflect.TypeOf((*MockClient)(nil).CreateOIDCProvider), arg0, arg1)
+}
+
+// CreateOperatorRole mocks base method
+func (m *MockClient) CreateOperatorRole(arg0 aws.OperatorRole, arg1, arg2, arg3, arg4 string, arg5 aws.IAMSettings) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOperatorRole", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOperatorRole indicates an expected call of CreateOperatorRole
+func (mr *MockClientMockRecorder) CreateOperatorRole(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOperatorRole", reflect.TypeOf((*MockClient)(nil).CreateOperatorRole), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// DeleteLeftover mocks base method
+func (m *MockClient) DeleteLeftover(arg0 *aws.Leftover) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLeftover", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLeftover indicates an expected call of DeleteLeftover
+func (mr *MockClientMockRecorder) DeleteLeftover(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLeftover", reflect.TypeOf((*MockClient)(nil).DeleteLeftover), arg0)
+}
+
+// DeleteOsdCcsAdminUser mocks base method
+func (m *MockClient) DeleteOsdCcsAdminUser(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOsdCcsAdminUser", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOsdCcsAdminUser indicates an expected call of DeleteOsdCcsAdminUser
+func (mr *MockClientMockRecorder) DeleteOsdCcsAdminUser(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOsdCcsAdminUser", reflect.TypeOf((*MockClient)(nil).DeleteOsdCcsAdminUser), arg0)
+}
+
+// DetectStackDrift mocks base method
+func (m *MockClient) DetectStackDrift(arg0 string) (*aws.StackDrift, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectStackDrift", arg0)
+	ret0, _ := ret[0].(*aws.StackDrift)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetectStackDrift indicates an expected call of DetectStackDrift
+func (mr *MockClientMockRecorder) DetectStackDrift(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectStackDrift", reflect.TypeOf((*MockClient)(nil).DetectStackDrift), arg0)
+}
+
+// EnsureOsdCcsAdminUser mocks base method
+func (m *MockClient) EnsureOsdCcsAdminUser(arg0, arg1 string, arg2 map[string]string, arg3 aws.IAMSettings) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureOsdCcsAdminUser", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnsureOsdCcsAdminUser indicates an expected call of EnsureOsdCcsAdminUser
+func (mr *MockClientMockRecorder) EnsureOsdCcsAdminUser(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureOsdCcsAdminUser", reflect.TypeOf((*MockClient)(nil).EnsureOsdCcsAdminUser), arg0, arg1, arg2, arg3)
+}
+
+// FindLeftovers mocks base method
+func (m *MockClient) FindLeftovers(arg0, arg1 string) ([]*aws.Leftover, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLeftovers", arg0, arg1)
+	ret0, _ := ret[0].([]*aws.Leftover)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLeftovers indicates an expected call of FindLeftovers
+func (mr *MockClientMockRecorder) FindLeftovers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLeftovers", reflect.TypeOf((*MockClient)(nil).FindLeftovers), arg0, arg1)
+}
+
+// FindSubnets mocks base method
+func (m *MockClient) FindSubnets(arg0 []string) ([]*ec2.Subnet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSubnets", arg0)
+	ret0, _ := ret[0].([]*ec2.Subnet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSubnets indicates an expected call of FindSubnets
+func (mr *MockClientMockRecorder) FindSubnets(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSubnets", reflect.TypeOf((*MockClient)(nil).FindSubnets), arg0)
+}
+
+// FindVPCClusters mocks base method
+func (m *MockClient) FindVPCClusters(arg0 []string) ([]*aws.VPCCluster, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindVPCClusters", arg0)
+	ret0, _ := ret[0].([]*aws.VPCCluster)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindVPCClusters indicates an expected call of FindVPCClusters
+func (mr *MockClientMockRecorder) FindVPCClusters(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindVPCClusters", reflect.TypeOf((*MockClient)(nil).FindVPCClusters), arg0)
+}
+
+// GetAWSAccessKeys mocks base method
+func (m *MockClient) GetAWSAccessKeys() (*aws.AccessKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAWSAccessKeys")
+	ret0, _ := ret[0].(*aws.AccessKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAWSAccessKeys indicates an expected call of GetAWSAccessKeys
+func (mr *MockClientMockRecorder) GetAWSAccessKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAWSAccessKeys", reflect.TypeOf((*MockClient)(nil).GetAWSAccessKeys))
+}
+
+// GetAWSAccessKeysFromSecret mocks base method
+func (m *MockClient) GetAWSAccessKeysFromSecret(arg0 string) (*aws.AccessKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAWSAccessKeysFromSecret", arg0)
+	ret0, _ := ret[0].(*aws.AccessKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAWSAccessKeysFromSecret indicates an expected call of GetAWSAccessKeysFromSecret
+func (mr *MockClientMockRecorder) GetAWSAccessKeysFromSecret(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAWSAccessKeysFromSecret", reflect.TypeOf((*MockClient)(nil).GetAWSAccessKeysFromSecret), arg0)
+}
+
+// GetAccountRoles mocks base method
+func (m *MockClient) GetAccountRoles(arg0 string) ([]*aws.AccountRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountRoles", arg0)
+	ret0, _ := ret[0].([]*aws.AccountRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountRoles indicates an expected call of GetAccountRoles
+func (mr *MockClientMockRecorder) GetAccountRoles(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountRoles", reflect.TypeOf((*MockClient)(nil).GetAccountRoles), arg0)
+}
+
+// GetAvailabilityZones mocks base method
+func (m *MockClient) GetAvailabilityZones() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAvailabilityZones")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAvailabilityZones indicates an expected call of GetAvailabilityZones
+func (mr *MockClientMockRecorder) GetAvailabilityZones() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailabilityZones", reflect.TypeOf((*MockClient)(nil).GetAvailabilityZones))
+}
+
+// GetClusterDNSRecords mocks base method
+func (m *MockClient) GetClusterDNSRecords(arg0 string) ([]aws.DNSRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClusterDNSRecords", arg0)
+	ret0, _ := ret[0].([]aws.DNSRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClusterDNSRecords indicates an expected call of GetClusterDNSRecords
+func (mr *MockClientMockRecorder) GetClusterDNSRecords(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClusterDNSRecords", reflect.TypeOf((*MockClient)(nil).GetClusterDNSRecords), arg0)
+}
+
+// GetClusterInfrastructure mocks base method
+func (m *MockClient) GetClusterInfrastructure(arg0 string) (*aws.Infrastructure, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClusterInfrastructure", arg0)
+	ret0, _ := ret[0].(*aws.Infrastructure)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClusterInfrastructure indicates an expected call of GetClusterInfrastructure
+func (mr *MockClientMockRecorder) GetClusterInfrastructure(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClusterInfrastructure", reflect.TypeOf((*MockClient)(nil).GetClusterInfrastructure), arg0)
+}
+
+// GetClusterNetwork mocks base method
+func (m *MockClient) GetClusterNetwork(arg0 string, arg1 []string) (*aws.Network, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClusterNetwork", arg0, arg1)
+	ret0, _ := ret[0].(*aws.Network)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClusterNetwork indicates an expected call of GetClusterNetwork
+func (mr *MockClientMockRecorder) GetClusterNetwork(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClusterNetwork", reflect.TypeOf((*MockClient)(nil).GetClusterNetwork), arg0, arg1)
+}
+
+// GetClusterQuotaUsage mocks base method
+func (m *MockClient) GetClusterQuotaUsage(arg0 *aws.ClusterPlan) ([]*aws.QuotaUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClusterQuotaUsage", arg0)
+	ret0, _ := ret[0].([]*aws.QuotaUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClusterQuotaUsage indicates an expected call of GetClusterQuotaUsage
+func (mr *MockClientMockRecorder) GetClusterQuotaUsage(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClusterQuotaUsage", reflect.TypeOf((*MockClient)(nil).GetClusterQuotaUsage), arg0)
+}
+
+// GetCreator mocks base method
+func (m *MockClient) GetCreator() (*aws.Creator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCreator")
+	ret0, _ := ret[0].(*aws.Creator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCreator indicates an expected call of GetCreator
+func (mr *MockClientMockRecorder) GetCreator() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCreator", reflect.TypeOf((*MockClient)(nil).GetCreator))
+}
+
+// GetEdgeSubnet mocks base method
+func (m *MockClient) GetEdgeSubnet(arg0 string) (*aws.EdgeSubnet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEdgeSubnet", arg0)
+	ret0, _ := ret[0].(*aws.EdgeSubnet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEdgeSubnet indicates an expected call of GetEdgeSubnet
+func (mr *MockClientMockRecorder) GetEdgeSubnet(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEdgeSubnet", reflect.TypeOf((*MockClient)(nil).GetEdgeSubnet), arg0)
+}
+
+// GetGPUInfo mocks base method
+func (m *MockClient) GetGPUInfo(arg0 string) (*aws.GPUInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGPUInfo", arg0)
+	ret0, _ := ret[0].(*aws.GPUInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGPUInfo indicates an expected call of GetGPUInfo
+func (mr *MockClientMockRecorder) GetGPUInfo(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGPUInfo", reflect.TypeOf((*MockClient)(nil).GetGPUInfo), arg0)
+}
+
+// GetIAMCredentials mocks base method
+func (m *MockClient) GetIAMCredentials() (credentials.Value, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIAMCredentials")
+	ret0, _ := ret[0].(credentials.Value)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIAMCredentials indicates an expected call of GetIAMCredentials
+func (mr *MockClientMockRecorder) GetIAMCredentials() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIAMCredentials", reflect.TypeOf((*MockClient)(nil).GetIAMCredentials))
+}
+
+// GetIdentity mocks base method
+func (m *MockClient) GetIdentity() (*aws.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdentity")
+	ret0, _ := ret[0].(*aws.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdentity indicates an expected call of GetIdentity
+func (mr *MockClientMockRecorder) GetIdentity() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdentity", reflect.TypeOf((*MockClient)(nil).GetIdentity))
+}
+
+// GetInstancePrices mocks base method
+func (m *MockClient) GetInstancePrices(arg0 []string) (map[string]float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInstancePrices", arg0)
+	ret0, _ := ret[0].(map[string]float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInstancePrices indicates an expected call of GetInstancePrices
+func (mr *MockClientMockRecorder) GetInstancePrices(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstancePrices", reflect.TypeOf((*MockClient)(nil).GetInstancePrices), arg0)
+}
+
+// GetInstanceTypeZones mocks base method
+func (m *MockClient) GetInstanceTypeZones() (map[string][]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInstanceTypeZones")
+	ret0, _ := ret[0].(map[string][]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInstanceTypeZones indicates an expected call of GetInstanceTypeZones
+func (mr *MockClientMockRecorder) GetInstanceTypeZones() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstanceTypeZones", reflect.TypeOf((*MockClient)(nil).GetInstanceTypeZones))
+}
+
+// GetLimitUsage mocks base method
+func (m *MockClient) GetLimitUsage() ([]*aws.LimitUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLimitUsage")
+	ret0, _ := ret[0].([]*aws.LimitUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLimitUsage indicates an expected call of GetLimitUsage
+func (mr *MockClientMockRecorder) GetLimitUsage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLimitUsage", reflect.TypeOf((*MockClient)(nil).GetLimitUsage))
+}
+
+// GetLocalZones mocks base method
+func (m *MockClient) GetLocalZones() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLocalZones")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLocalZones indicates an expected call of GetLocalZones
+func (mr *MockClientMockRecorder) GetLocalZones() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLocalZones", reflect.TypeOf((*MockClient)(nil).GetLocalZones))
+}
+
+// GetOIDCProvider mocks base method
+func (m *MockClient) GetOIDCProvider(arg0 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOIDCProvider", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOIDCProvider indicates an expected call of GetOIDCProvider
+func (mr *MockClientMockRecorder) GetOIDCProvider(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOIDCProvider", reflect.TypeOf((*MockClient)(nil).GetOIDCProvider), arg0)
+}
+
+// GetPartition mocks base method
+func (m *MockClient) GetPartition() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPartition")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPartition indicates an expected call of GetPartition
+func (mr *MockClientMockRecorder) GetPartition() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPartition", reflect.TypeOf((*MockClient)(nil).GetPartition))
+}
+
+// GetPrivateLinkEndpointServices mocks base method
+func (m *MockClient) GetPrivateLinkEndpointServices(arg0 string) ([]*aws.PrivateLinkEndpointService, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrivateLinkEndpointServices", arg0)
+	ret0, _ := ret[0].([]*aws.PrivateLinkEndpointService)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPrivateLinkEndpointServices indicates an expected call of GetPrivateLinkEndpointServices
+func (mr *MockClientMockRecorder) GetPrivateLinkEndpointServices(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrivateLinkEndpointServices", reflect.TypeOf((*MockClient)(nil).GetPrivateLinkEndpointServices), arg0)
+}
+
+// GetRegion mocks base method
+func (m *MockClient) GetRegion() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRegion")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetRegion indicates an expected call of GetRegion
+func (mr *MockClientMockRecorder) GetRegion() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegion", reflect.TypeOf((*MockClient)(nil).GetRegion))
+}
+
+// GetStackProgress mocks base method
+func (m *MockClient) GetStackProgress(arg0 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStackProgress", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStackProgress indicates an expected call of GetStackProgress
+func (mr *MockClientMockRecorder) GetStackProgress(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStackProgress", reflect.TypeOf((*MockClient)(nil).GetStackProgress), arg0)
+}
+
+// GetSubnetIDs mocks base method
+func (m *MockClient) GetSubnetIDs() ([]*ec2.Subnet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubnetIDs")
+	ret0, _ := ret[0].([]*ec2.Subnet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubnetIDs indicates an expected call of GetSubnetIDs
+func (mr *MockClientMockRecorder) GetSubnetIDs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnetIDs", reflect.TypeOf((*MockClient)(nil).GetSubnetIDs))
+}
+
+// GetSubnetsEgress mocks base method
+func (m *MockClient) GetSubnetsEgress(arg0 []string) ([]*aws.SubnetEgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubnetsEgress", arg0)
+	ret0, _ := ret[0].([]*aws.SubnetEgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubnetsEgress indicates an expected call of GetSubnetsEgress
+func (mr *MockClientMockRecorder) GetSubnetsEgress(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnetsEgress", reflect.TypeOf((*MockClient)(nil).GetSubnetsEgress), arg0)
+}
+
+// GetTemporaryAccessKeys mocks base method
+func (m *MockClient) GetTemporaryAccessKeys(arg0 time.Duration) (*aws.AccessKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemporaryAccessKeys", arg0)
+	ret0, _ := ret[0].(*aws.AccessKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemporaryAccessKeys indicates an expected call of GetTemporaryAccessKeys
+func (mr *MockClientMockRecorder) GetTemporaryAccessKeys(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemporaryAccessKeys", reflect.TypeOf((*MockClient)(nil).GetTemporaryAccessKeys), arg0)
+}
+
+// GetVolumePrice mocks base method
+func (m *MockClient) GetVolumePrice(arg0 string) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVolumePrice", arg0)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVolumePrice indicates an expected call of GetVolumePrice
+func (mr *MockClientMockRecorder) GetVolumePrice(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVolumePrice", reflect.TypeOf((*MockClient)(nil).GetVolumePrice), arg0)
+}
+
+// HasELBServiceLinkedRole mocks base method
+func (m *MockClient) HasELBServiceLinkedRole() (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasELBServiceLinkedRole")
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasELBServiceLinkedRole indicates an expected call of HasELBServiceLinkedRole
+func (mr *MockClientMockRecorder) HasELBServiceLinkedRole() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasELBServiceLinkedRole", reflect.TypeOf((*MockClient)(nil).HasELBServiceLinkedRole))
+}
+
+// PutOIDCBucketPolicy mocks base method
+func (m *MockClient) PutOIDCBucketPolicy(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutOIDCBucketPolicy", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutOIDCBucketPolicy indicates an expected call of PutOIDCBucketPolicy
+func (mr *MockClientMockRecorder) PutOIDCBucketPolicy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutOIDCBucketPolicy", reflect.TypeOf((*MockClient)(nil).PutOIDCBucketPolicy), arg0)
+}
+
+// RepairOsdCcsAdminUser mocks base method
+func (m *MockClient) RepairOsdCcsAdminUser(arg0 string, arg1 map[string]string, arg2 aws.IAMSettings) (*aws.StackDrift, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RepairOsdCcsAdminUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*aws.StackDrift)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RepairOsdCcsAdminUser indicates an expected call of RepairOsdCcsAdminUser
+func (mr *MockClientMockRecorder) RepairOsdCcsAdminUser(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepairOsdCcsAdminUser", reflect.TypeOf((*MockClient)(nil).RepairOsdCcsAdminUser), arg0, arg1, arg2)
+}
+
+// SimulatePermissions mocks base method
+func (m *MockClient) SimulatePermissions(arg0 string, arg1 []string, arg2 string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SimulatePermissions", arg0, arg1, arg2)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SimulatePermissions indicates an expected call of SimulatePermissions
+func (mr *MockClientMockRecorder) SimulatePermissions(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SimulatePermissions", reflect.TypeOf((*MockClient)(nil).SimulatePermissions), arg0, arg1, arg2)
+}
+
+// TagUser mocks base method
+func (m *MockClient) TagUser(arg0, arg1, arg2 string, arg3 map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TagUser", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TagUser indicates an expected call of TagUser
+func (mr *MockClientMockRecorder) TagUser(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagUser", reflect.TypeOf((*MockClient)(nil).TagUser), arg0, arg1, arg2, arg3)
+}
+
+// UploadOIDCDocuments mocks base method
+func (m *MockClient) UploadOIDCDocuments(arg0 string, arg1, arg2 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadOIDCDocuments", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UploadOIDCDocuments indicates an expected call of UploadOIDCDocuments
+func (mr *MockClientMockRecorder) UploadOIDCDocuments(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadOIDCDocuments", reflect.TypeOf((*MockClient)(nil).UploadOIDCDocuments), arg0, arg1, arg2)
+}
+
+// ValidateAvailabilityZones mocks base method
+func (m *MockClient) ValidateAvailabilityZones(arg0 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateAvailabilityZones", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateAvailabilityZones indicates an expected call of ValidateAvailabilityZones
+func (mr *MockClientMockRecorder) ValidateAvailabilityZones(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateAvailabilityZones", reflect.TypeOf((*MockClient)(nil).ValidateAvailabilityZones), arg0)
+}
+
+// ValidateCredentials mocks base method
+func (m *MockClient) ValidateCredentials() (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateCredentials")
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateCredentials indicates an expected call of ValidateCredentials
+func (mr *MockClientMockRecorder) ValidateCredentials() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateCredentials", reflect.TypeOf((*MockClient)(nil).ValidateCredentials))
+}
+
+// ValidateEdgeInstanceType mocks base method
+func (m *MockClient) ValidateEdgeInstanceType(arg0 *aws.EdgeSubnet, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateEdgeInstanceType", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateEdgeInstanceType indicates an expected call of ValidateEdgeInstanceType
+func (mr *MockClientMockRecorder) ValidateEdgeInstanceType(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateEdgeInstanceType", reflect.TypeOf((*MockClient)(nil).ValidateEdgeInstanceType), arg0, arg1)
+}
+
+// ValidateGPUQuota mocks base method
+func (m *MockClient) ValidateGPUQuota(arg0 *aws.GPUInfo, arg1 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateGPUQuota", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateGPUQuota indicates an expected call of ValidateGPUQuota
+func (mr *MockClientMockRecorder) ValidateGPUQuota(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateGPUQuota", reflect.TypeOf((*MockClient)(nil).ValidateGPUQuota), arg0, arg1)
+}
+
+// ValidateIPv6Subnets mocks base method
+func (m *MockClient) ValidateIPv6Subnets(arg0 []string, arg1 *net.IPNet) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateIPv6Subnets", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateIPv6Subnets indicates an expected call of ValidateIPv6Subnets
+func (mr *MockClientMockRecorder) ValidateIPv6Subnets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateIPv6Subnets", reflect.TypeOf((*MockClient)(nil).ValidateIPv6Subnets), arg0, arg1)
+}
+
+// ValidateOIDCBucket mocks base method
+func (m *MockClient) ValidateOIDCBucket(arg0 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateOIDCBucket", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateOIDCBucket indicates an expected call of ValidateOIDCBucket
+func (mr *MockClientMockRecorder) ValidateOIDCBucket(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateOIDCBucket", reflect.TypeOf((*MockClient)(nil).ValidateOIDCBucket), arg0)
+}
+
+// ValidatePrivateLinkVPC mocks base method
+func (m *MockClient) ValidatePrivateLinkVPC(arg0 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidatePrivateLinkVPC", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidatePrivateLinkVPC indicates an expected call of ValidatePrivateLinkVPC
+func (mr *MockClientMockRecorder) ValidatePrivateLinkVPC(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidatePrivateLinkVPC", reflect.TypeOf((*MockClient)(nil).ValidatePrivateLinkVPC), arg0)
+}
+
+// ValidateQuota mocks base method
+func (m *MockClient) ValidateQuota() (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateQuota")
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateQuota indicates an expected call of ValidateQuota
+func (mr *MockClientMockRecorder) ValidateQuota() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateQuota", reflect.TypeOf((*MockClient)(nil).ValidateQuota))
+}
+
+// ValidateRoleARN mocks base method
+func (m *MockClient) ValidateRoleARN(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateRoleARN", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateRoleARN indicates an expected call of ValidateRoleARN
+func (mr *MockClientMockRecorder) ValidateRoleARN(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateRoleARN", reflect.TypeOf((*MockClient)(nil).ValidateRoleARN), arg0, arg1)
+}
+
+// ValidateSCP mocks base method
+func (m *MockClient) ValidateSCP(arg0 *string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateSCP", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateSCP indicates an expected call of ValidateSCP
+func (mr *MockClientMockRecorder) ValidateSCP(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateSCP", reflect.TypeOf((*MockClient)(nil).ValidateSCP), arg0)
+}
+
+// ValidateSecurityGroups mocks base method
+func (m *MockClient) ValidateSecurityGroups(arg0, arg1 []string, arg2 bool, arg3 map[string]*net.IPNet) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateSecurityGroups", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateSecurityGroups indicates an expected call of ValidateSecurityGroups
+func (mr *MockClientMockRecorder) ValidateSecurityGroups(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateSecurityGroups", reflect.TypeOf((*MockClient)(nil).ValidateSecurityGroups), arg0, arg1, arg2, arg3)
+}
+
+// ValidateSubnets mocks base method
+func (m *MockClient) ValidateSubnets(arg0 []string, arg1, arg2 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateSubnets", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateSubnets indicates an expected call of ValidateSubnets
+func (mr *MockClientMockRecorder) ValidateSubnets(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateSubnets", reflect.TypeOf((*MockClient)(nil).ValidateSubnets), arg0, arg1, arg2)
+}
+
+// ValidateTags mocks base method
+func (m *MockClient) ValidateTags(arg0 map[string]string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateTags", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateTags indicates an expected call of ValidateTags
+func (mr *MockClientMockRecorder) ValidateTags(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateTags", reflect.TypeOf((*MockClient)(nil).ValidateTags), arg0)
+}
+
+// VerifyPermissions mocks base method
+func (m *MockClient) VerifyPermissions() ([]*aws.MissingPermission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyPermissions")
+	ret0, _ := ret[0].([]*aws.MissingPermission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyPermissions indicates an expected call of VerifyPermissions
+func (mr *MockClientMockRecorder) VerifyPermissions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyPermissions", reflect.TypeOf((*MockClient)(nil).VerifyPermissions))
+}
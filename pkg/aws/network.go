@@ -0,0 +1,140 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the function that collects the network resources of a cluster, including
+// the ones of an existing VPC that the installer doesn't tag as owned by the cluster.
+
+package aws
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// Network contains the AWS network resources used by a cluster. A subnet is public if its route
+// table sends traffic to an internet gateway.
+type Network struct {
+	VPCs             []*VPC        `json:"vpcs"`
+	Subnets          []*Subnet     `json:"subnets"`
+	InternetGateways []string      `json:"internet_gateways"`
+	NATGateways      []*NATGateway `json:"nat_gateways"`
+}
+
+// GetClusterNetwork finds the subnets of the cluster with the given infrastructure identifier, or
+// the given subnets if the cluster was installed into an existing VPC, together with their VPC and
+// the internet and NAT gateways of that VPC.
+func (c *awsClient) GetClusterNetwork(infraID string, subnetIDs []string) (*Network, error) {
+	result := &Network{}
+
+	var subnets []*ec2.Subnet
+	var err error
+	if len(subnetIDs) > 0 {
+		subnets, err = c.FindSubnets(subnetIDs)
+	} else {
+		var output *ec2.DescribeSubnetsOutput
+		output, err = c.ec2Client.DescribeSubnets(&ec2.DescribeSubnetsInput{
+			Filters: []*ec2.Filter{{
+				Name:   aws.String("tag-key"),
+				Values: aws.StringSlice([]string{clusterTagKey(infraID)}),
+			}},
+		})
+		if output != nil {
+			subnets = output.Subnets
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list subnets: %v", err)
+	}
+
+	vpcSubnets := map[string][]string{}
+	vpcs := map[string]bool{}
+	for _, subnet := range subnets {
+		vpcID := aws.StringValue(subnet.VpcId)
+		vpcSubnets[vpcID] = append(vpcSubnets[vpcID], aws.StringValue(subnet.SubnetId))
+		vpcs[vpcID] = true
+	}
+	vpcIDs := sortedKeys(vpcs)
+	if len(vpcIDs) == 0 {
+		return result, nil
+	}
+
+	public := map[string]bool{}
+	for _, vpcID := range vpcIDs {
+		vpcPublic, err := c.publicSubnets(vpcID, vpcSubnets[vpcID])
+		if err != nil {
+			return nil, fmt.Errorf("Failed to describe route tables of VPC '%s': %v", vpcID, err)
+		}
+		for subnetID := range vpcPublic {
+			public[subnetID] = true
+		}
+	}
+	for _, subnet := range subnets {
+		subnetID := aws.StringValue(subnet.SubnetId)
+		result.Subnets = append(result.Subnets, &Subnet{
+			ID:               subnetID,
+			Name:             tagValue(subnet.Tags, "Name"),
+			AvailabilityZone: aws.StringValue(subnet.AvailabilityZone),
+			CIDR:             aws.StringValue(subnet.CidrBlock),
+			Public:           public[subnetID],
+		})
+	}
+
+	vpcFilters := []*ec2.Filter{{
+		Name:   aws.String("vpc-id"),
+		Values: aws.StringSlice(vpcIDs),
+	}}
+	vpcsOutput, err := c.ec2Client.DescribeVpcs(&ec2.DescribeVpcsInput{
+		VpcIds: aws.StringSlice(vpcIDs),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list VPCs: %v", err)
+	}
+	for _, vpc := range vpcsOutput.Vpcs {
+		result.VPCs = append(result.VPCs, &VPC{
+			ID:    aws.StringValue(vpc.VpcId),
+			CIDR:  aws.StringValue(vpc.CidrBlock),
+			Owned: tagValue(vpc.Tags, clusterTagKey(infraID)) == "owned",
+		})
+	}
+
+	gateways, err := c.ec2Client.DescribeInternetGateways(&ec2.DescribeInternetGatewaysInput{
+		Filters: []*ec2.Filter{{
+			Name:   aws.String("attachment.vpc-id"),
+			Values: aws.StringSlice(vpcIDs),
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list internet gateways: %v", err)
+	}
+	for _, gateway := range gateways.InternetGateways {
+		result.InternetGateways = append(result.InternetGateways, aws.StringValue(gateway.InternetGatewayId))
+	}
+
+	err = c.ec2Client.DescribeNatGatewaysPages(&ec2.DescribeNatGatewaysInput{Filter: vpcFilters},
+		func(page *ec2.DescribeNatGatewaysOutput, lastPage bool) bool {
+			for _, gateway := range page.NatGateways {
+				result.NATGateways = append(result.NATGateways, newNATGateway(gateway))
+			}
+			return true
+		})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list NAT gateways: %v", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,256 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that provision the AWS resources of unmanaged OIDC
+// configurations: the S3 bucket that serves the discovery documents and the secret that holds
+// the private key used to sign the service account tokens.
+
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+)
+
+// Paths of the documents that an OIDC issuer has to serve:
+const (
+	OIDCDiscoveryPath = ".well-known/openid-configuration"
+	OIDCKeysPath      = "keys.json"
+)
+
+// OIDCIssuerURL returns the URL of the issuer served from the given bucket of the given region,
+// using the domain of the partition of the region.
+func OIDCIssuerURL(bucketName string, region string) string {
+	return fmt.Sprintf("https://%s.s3.%s.%s", bucketName, region, DNSSuffix(region))
+}
+
+var bucketNameRE = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+
+// ValidateBucketName checks that the given name follows the S3 bucket naming rules. Names with
+// dots are rejected as well, as the certificates of the virtual host style URLs that the issuer
+// uses don't cover them.
+func ValidateBucketName(name string) error {
+	switch {
+	case !bucketNameRE.MatchString(name):
+		return fmt.Errorf("Bucket name '%s' isn't valid: it must be between 3 and 63 characters long "+
+			"and contain only lowercase letters, digits and dashes", name)
+	case strings.Contains(name, "."):
+		return fmt.Errorf("Bucket name '%s' isn't valid: it must not contain dots", name)
+	case net.ParseIP(name) != nil:
+		return fmt.Errorf("Bucket name '%s' isn't valid: it must not be formatted as an IP address", name)
+	case strings.HasPrefix(name, "xn--"):
+		return fmt.Errorf("Bucket name '%s' isn't valid: it must not start with 'xn--'", name)
+	}
+	return nil
+}
+
+// BucketError contains all the problems that prevent using an existing bucket, so that they can be
+// reported to the user at once.
+type BucketError struct {
+	BucketName string
+	Problems   []string
+}
+
+func (e *BucketError) Error() string {
+	return fmt.Sprintf("Bucket '%s' can't be used:\n  - %s", e.BucketName, strings.Join(e.Problems, "\n  - "))
+}
+
+// ValidateOIDCBucket checks if the given bucket already exists and, if it does, that it can be
+// reused for an OIDC configuration: it has to be owned by the current account, be in the region
+// of the client and allow public read access to the documents. If it can't be reused the returned
+// error is a *BucketError listing all the problems found.
+func (c *awsClient) ValidateOIDCBucket(bucketName string) (exists bool, err error) {
+	_, err = c.s3Client.HeadBucket(&s3.HeadBucketInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.RequestFailure); ok {
+			switch aerr.StatusCode() {
+			case http.StatusNotFound:
+				return false, nil
+			case http.StatusForbidden:
+				return true, &BucketError{BucketName: bucketName, Problems: []string{
+					"it is owned by another AWS account",
+				}}
+			case http.StatusMovedPermanently:
+				return true, &BucketError{BucketName: bucketName, Problems: []string{
+					fmt.Sprintf("it isn't in region '%s'", c.GetRegion()),
+				}}
+			}
+		}
+		return false, err
+	}
+
+	// The bucket is accessible, but it may still belong to another account that has granted
+	// access to this one:
+	buckets, err := c.s3Client.ListBuckets(&s3.ListBucketsInput{})
+	if err != nil {
+		return true, err
+	}
+	owned := false
+	for _, bucket := range buckets.Buckets {
+		if aws.StringValue(bucket.Name) == bucketName {
+			owned = true
+			break
+		}
+	}
+	if !owned {
+		return true, &BucketError{BucketName: bucketName, Problems: []string{
+			"it is owned by another AWS account",
+		}}
+	}
+
+	problems := []string{}
+	location, err := c.s3Client.GetBucketLocation(&s3.GetBucketLocationInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		return true, err
+	}
+	// Buckets in the default region of the partition have an empty location constraint:
+	region := aws.StringValue(location.LocationConstraint)
+	if region == "" {
+		region = PartitionDefaultRegion(c.GetRegion())
+	}
+	if region != c.GetRegion() {
+		problems = append(problems, fmt.Sprintf(
+			"it is in region '%s' instead of region '%s'", region, c.GetRegion()))
+	}
+
+	block, err := c.s3Client.GetPublicAccessBlock(&s3.GetPublicAccessBlockInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		aerr, ok := err.(awserr.Error)
+		if !ok || aerr.Code() != "NoSuchPublicAccessBlockConfiguration" {
+			return true, err
+		}
+	} else if config := block.PublicAccessBlockConfiguration; config != nil &&
+		(aws.BoolValue(config.BlockPublicPolicy) || aws.BoolValue(config.RestrictPublicBuckets)) {
+		problems = append(problems,
+			"its public access block prevents the public read access that AWS STS needs")
+	}
+
+	if len(problems) > 0 {
+		return true, &BucketError{BucketName: bucketName, Problems: problems}
+	}
+	return true, nil
+}
+
+// CreateOIDCBucket creates the bucket in the region of the client and allows anonymous users to
+// read its objects, as that is how AWS STS reads the documents of the issuer.
+func (c *awsClient) CreateOIDCBucket(bucketName string) error {
+	input := &s3.CreateBucketInput{
+		Bucket: aws.String(bucketName),
+	}
+	// Buckets in the default region of the partition must not have a location constraint:
+	if c.GetRegion() != PartitionDefaultRegion(c.GetRegion()) {
+		input.CreateBucketConfiguration = &s3.CreateBucketConfiguration{
+			LocationConstraint: aws.String(c.GetRegion()),
+		}
+	}
+	_, err := c.s3Client.CreateBucket(input)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.s3Client.PutPublicAccessBlock(&s3.PutPublicAccessBlockInput{
+		Bucket: aws.String(bucketName),
+		PublicAccessBlockConfiguration: &s3.PublicAccessBlockConfiguration{
+			BlockPublicAcls:       aws.Bool(true),
+			IgnorePublicAcls:      aws.Bool(true),
+			BlockPublicPolicy:     aws.Bool(false),
+			RestrictPublicBuckets: aws.Bool(false),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.PutOIDCBucketPolicy(bucketName)
+}
+
+// PutOIDCBucketPolicy sets the policy of the bucket so that anonymous users can read the documents
+// of the issuer.
+func (c *awsClient) PutOIDCBucketPolicy(bucketName string) error {
+	partition := GetPartition(c.GetRegion())
+	policy := fmt.Sprintf(`{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Principal": "*",
+      "Action": "s3:GetObject",
+      "Resource": [
+        "%s",
+        "%s"
+      ]
+    }
+  ]
+}`,
+		BuildARN(partition, "s3", "", "", bucketName+"/"+OIDCDiscoveryPath),
+		BuildARN(partition, "s3", "", "", bucketName+"/"+OIDCKeysPath),
+	)
+	_, err := c.s3Client.PutBucketPolicy(&s3.PutBucketPolicyInput{
+		Bucket: aws.String(bucketName),
+		Policy: aws.String(policy),
+	})
+	return err
+}
+
+// UploadOIDCDocuments uploads the discovery document and the public keys of the issuer to the
+// bucket.
+func (c *awsClient) UploadOIDCDocuments(bucketName string, discovery []byte, jwks []byte) error {
+	documents := map[string][]byte{
+		OIDCDiscoveryPath: discovery,
+		OIDCKeysPath:      jwks,
+	}
+	for key, body := range documents {
+		_, err := c.s3Client.PutObject(&s3.PutObjectInput{
+			Bucket:      aws.String(bucketName),
+			Key:         aws.String(key),
+			Body:        bytes.NewReader(body),
+			ContentType: aws.String("application/json"),
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to upload '%s': %v", key, err)
+		}
+	}
+	return nil
+}
+
+// CreateOIDCPrivateKeySecret stores the private key of the issuer in a new secret and returns the
+// ARN of the secret.
+func (c *awsClient) CreateOIDCPrivateKeySecret(name string, privateKey []byte) (string, error) {
+	output, err := c.secretsClient.CreateSecret(&secretsmanager.CreateSecretInput{
+		Name:         aws.String(name),
+		Description:  aws.String("Private key used to sign the service account tokens of OpenShift clusters"),
+		SecretBinary: privateKey,
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(output.ARN), nil
+}
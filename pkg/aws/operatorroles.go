@@ -0,0 +1,212 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that create the operator roles and the OIDC provider of
+// clusters that use AWS STS. The operators of the cluster get credentials for their roles
+// presenting the tokens of their service accounts, signed by the OIDC issuer of the cluster.
+
+package aws
+
+import (
+	"crypto/sha1" // #nosec G505
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+
+	"github.com/openshift/moactl/assets"
+	"github.com/openshift/moactl/pkg/aws/tags"
+)
+
+// maxRoleNameLength is the maximum length of the names of IAM roles.
+const maxRoleNameLength = 64
+
+// OperatorRole describes the role of an operator of the cluster. The operator reads its
+// credentials from the secret with the given name and namespace, and the role is assumed by the
+// given service accounts of that namespace.
+type OperatorRole struct {
+	Namespace       string
+	Name            string
+	ServiceAccounts []string
+}
+
+// OperatorRoles are the roles of the operators that need access to AWS.
+var OperatorRoles = []OperatorRole{
+	{
+		Namespace:       "openshift-ingress-operator",
+		Name:            "cloud-credentials",
+		ServiceAccounts: []string{"ingress-operator"},
+	},
+	{
+		Namespace:       "openshift-image-registry",
+		Name:            "installer-cloud-credentials",
+		ServiceAccounts: []string{"cluster-image-registry-operator", "registry"},
+	},
+	{
+		Namespace:       "openshift-cluster-csi-drivers",
+		Name:            "ebs-cloud-credentials",
+		ServiceAccounts: []string{"aws-ebs-csi-driver-operator", "aws-ebs-csi-driver-controller-sa"},
+	},
+	{
+		Namespace:       "openshift-machine-api",
+		Name:            "aws-cloud-credentials",
+		ServiceAccounts: []string{"machine-api-controllers"},
+	},
+	{
+		Namespace:       "openshift-cloud-credential-operator",
+		Name:            "cloud-credential-operator-iam-ro-creds",
+		ServiceAccounts: []string{"cloud-credential-operator"},
+	},
+}
+
+// RoleName returns the name of the role of this operator with the given prefix. Names are
+// truncated to the maximum length that IAM accepts.
+func (o OperatorRole) RoleName(prefix string) string {
+	name := fmt.Sprintf("%s-%s-%s", prefix, o.Namespace, o.Name)
+	if len(name) > maxRoleNameLength {
+		name = name[:maxRoleNameLength]
+	}
+	return name
+}
+
+// RoleARN returns the ARN that the role of this operator with the given prefix has in the given
+// partition and account, with the given path.
+func (o OperatorRole) RoleARN(partition string, accountID string, path string, prefix string) string {
+	return fmt.Sprintf("arn:%s:iam::%s:role%s%s", partition, accountID, path, o.RoleName(prefix))
+}
+
+// policyPath returns the path of the template of the permission policy of this operator.
+func (o OperatorRole) policyPath() string {
+	name := strings.ReplaceAll(fmt.Sprintf("%s_%s", o.Namespace, o.Name), "-", "_")
+	return fmt.Sprintf("templates/policies/%s_policy.json", name)
+}
+
+// FindOperatorRole returns the operator that reads its credentials from the secret with the given
+// namespace and name, or nil if there is no such operator.
+func FindOperatorRole(namespace string, name string) *OperatorRole {
+	for i, operator := range OperatorRoles {
+		if operator.Namespace == namespace && operator.Name == name {
+			return &OperatorRoles[i]
+		}
+	}
+	return nil
+}
+
+// CreateOperatorRole creates the role of the given operator with the given name, trusting the given
+// OIDC provider, or updates it if it already exists, and returns its ARN. The role is tagged with
+// the identifier of the cluster.
+func (c *awsClient) CreateOperatorRole(operator OperatorRole, roleName string, clusterID string,
+	oidcProviderARN string, issuerURL string, settings IAMSettings) (string, error) {
+	document, err := assets.Asset(operator.policyPath())
+	if err != nil {
+		return "", fmt.Errorf("Failed to load policy of operator '%s': %v", operator.Namespace, err)
+	}
+	subjects := make([]string, len(operator.ServiceAccounts))
+	for i, serviceAccount := range operator.ServiceAccounts {
+		subjects[i] = fmt.Sprintf("system:serviceaccount:%s:%s", operator.Namespace, serviceAccount)
+	}
+	trustPolicy, err := trustPolicyDocument(
+		map[string]interface{}{"Federated": oidcProviderARN},
+		"sts:AssumeRoleWithWebIdentity",
+		map[string]interface{}{
+			"StringEquals": map[string]interface{}{
+				issuerHostPath(issuerURL) + ":sub": subjects,
+			},
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	return c.createRoleWithPolicy(&roleWithPolicy{
+		RoleName:    roleName,
+		TrustPolicy: trustPolicy,
+		PolicyName:  fmt.Sprintf("%s-Policy", roleName),
+		Policy:      string(document),
+		Tags: map[string]string{
+			tags.ClusterID: clusterID,
+		},
+		Settings: settings,
+	})
+}
+
+// GetOIDCProvider returns the ARN of the OIDC provider of the given issuer, or an empty string if
+// it doesn't exist.
+func (c *awsClient) GetOIDCProvider(issuerURL string) (string, error) {
+	output, err := c.iamClient.ListOpenIDConnectProviders(&iam.ListOpenIDConnectProvidersInput{})
+	if err != nil {
+		return "", err
+	}
+	suffix := "oidc-provider/" + issuerHostPath(issuerURL)
+	for _, provider := range output.OpenIDConnectProviderList {
+		if strings.HasSuffix(aws.StringValue(provider.Arn), suffix) {
+			return aws.StringValue(provider.Arn), nil
+		}
+	}
+	return "", nil
+}
+
+// CreateOIDCProvider creates the OIDC provider of the given issuer, trusting the certificate that
+// it presents, and returns its ARN. The audiences are the ones that the operators use when they
+// request credentials.
+func (c *awsClient) CreateOIDCProvider(issuerURL string, thumbprint string) (string, error) {
+	output, err := c.iamClient.CreateOpenIDConnectProvider(&iam.CreateOpenIDConnectProviderInput{
+		Url:            aws.String(issuerURL),
+		ClientIDList:   aws.StringSlice([]string{"openshift", "sts.amazonaws.com"}),
+		ThumbprintList: aws.StringSlice([]string{thumbprint}),
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(output.OpenIDConnectProviderArn), nil
+}
+
+// OIDCThumbprint returns the thumbprint that IAM needs to trust the given issuer: the SHA-1
+// fingerprint of the root certificate of the chain that the server presents.
+func OIDCThumbprint(issuerURL string) (string, error) {
+	parsed, err := url.Parse(issuerURL)
+	if err != nil {
+		return "", fmt.Errorf("Issuer URL '%s' isn't valid: %v", issuerURL, err)
+	}
+	host := parsed.Host
+	if parsed.Port() == "" {
+		host = net.JoinHostPort(parsed.Hostname(), "443")
+	}
+	connection, err := tls.Dial("tcp", host, &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	})
+	if err != nil {
+		return "", fmt.Errorf("Failed to connect to issuer '%s': %v", issuerURL, err)
+	}
+	defer connection.Close()
+	certificates := connection.ConnectionState().PeerCertificates
+	if len(certificates) == 0 {
+		return "", fmt.Errorf("Issuer '%s' didn't present any certificate", issuerURL)
+	}
+	// #nosec G401
+	fingerprint := sha1.Sum(certificates[len(certificates)-1].Raw)
+	return hex.EncodeToString(fingerprint[:]), nil
+}
+
+// issuerHostPath returns the issuer URL without the scheme, as used in the ARNs of the OIDC
+// providers and in the conditions of the trust policies.
+func issuerHostPath(issuerURL string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(issuerURL, "https://"), "/")
+}
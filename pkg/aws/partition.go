@@ -0,0 +1,111 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that take into account the AWS partition of the regions and
+// credentials, so that the tool works in the GovCloud and China partitions as well as in the
+// standard one.
+
+package aws
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+)
+
+// partitionDefaultRegions are the regions used for the CloudFormation stack and the global
+// services, like IAM and STS, in each partition:
+var partitionDefaultRegions = map[string]string{
+	endpoints.AwsPartitionID:      DefaultRegion,
+	endpoints.AwsUsGovPartitionID: "us-gov-west-1",
+	endpoints.AwsCnPartitionID:    "cn-north-1",
+}
+
+// GetPartition returns the identifier of the partition of the given region, like 'aws-us-gov'.
+// Unknown regions are assumed to be in the standard partition.
+func GetPartition(region string) string {
+	partition, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region)
+	if !ok {
+		return endpoints.AwsPartitionID
+	}
+	return partition.ID()
+}
+
+// PartitionDefaultRegion returns the default region of the partition of the given region.
+func PartitionDefaultRegion(region string) string {
+	result, ok := partitionDefaultRegions[GetPartition(region)]
+	if !ok {
+		return DefaultRegion
+	}
+	return result
+}
+
+// GlobalRegion returns the region used for the CloudFormation stack and the global services: the
+// default region of the partition of the region configured for the AWS credentials.
+func GlobalRegion() string {
+	region, err := GetRegion("")
+	if err != nil || region == "" {
+		return DefaultRegion
+	}
+	return PartitionDefaultRegion(region)
+}
+
+// DNSSuffix returns the domain of the endpoints of the partition of the given region, like
+// 'amazonaws.com.cn' for the regions in China.
+func DNSSuffix(region string) string {
+	partition, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region)
+	if !ok {
+		return "amazonaws.com"
+	}
+	return partition.DNSSuffix()
+}
+
+// BuildARN returns the ARN of the given resource in the partition of the given region. The region
+// is only included in the ARN for regional services, so it is empty for IAM and S3 resources.
+func BuildARN(partition string, service string, region string, accountID string, resource string) string {
+	return arn.ARN{
+		Partition: partition,
+		Service:   service,
+		Region:    region,
+		AccountID: accountID,
+		Resource:  resource,
+	}.String()
+}
+
+// ValidateRegionPartition checks that the given region is in the given partition, as credentials
+// can only be used in the regions of their own partition.
+func ValidateRegionPartition(region string, partition string) error {
+	if GetPartition(region) != partition {
+		return fmt.Errorf("Region '%s' is in partition '%s', but the AWS credentials belong to "+
+			"partition '%s'", region, GetPartition(region), partition)
+	}
+	return nil
+}
+
+// GetPartition returns the partition of the credentials of the client, taken from the ARN of the
+// user or role that they belong to.
+func (c *awsClient) GetPartition() (string, error) {
+	creator, err := c.GetCreator()
+	if err != nil {
+		return "", err
+	}
+	parsed, err := arn.Parse(creator.ARN)
+	if err != nil {
+		return "", err
+	}
+	return parsed.Partition, nil
+}
@@ -0,0 +1,40 @@
+package aws_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws"
+)
+
+var _ = Describe("Partitions", func() {
+	It("Finds the partition of the region", func() {
+		Expect(aws.GetPartition("us-east-1")).To(Equal("aws"))
+		Expect(aws.GetPartition("us-gov-east-1")).To(Equal("aws-us-gov"))
+		Expect(aws.GetPartition("cn-northwest-1")).To(Equal("aws-cn"))
+		Expect(aws.GetPartition("unknown")).To(Equal("aws"))
+	})
+
+	It("Uses the default region of the partition", func() {
+		Expect(aws.PartitionDefaultRegion("eu-west-1")).To(Equal("us-east-1"))
+		Expect(aws.PartitionDefaultRegion("us-gov-east-1")).To(Equal("us-gov-west-1"))
+		Expect(aws.PartitionDefaultRegion("cn-northwest-1")).To(Equal("cn-north-1"))
+	})
+
+	It("Uses the DNS suffix of the partition", func() {
+		Expect(aws.DNSSuffix("eu-west-1")).To(Equal("amazonaws.com"))
+		Expect(aws.DNSSuffix("cn-north-1")).To(Equal("amazonaws.com.cn"))
+	})
+
+	It("Builds ARNs in the partition", func() {
+		Expect(aws.BuildARN("aws-us-gov", "s3", "", "", "my-bucket/*")).
+			To(Equal("arn:aws-us-gov:s3:::my-bucket/*"))
+		Expect(aws.BuildARN("aws-cn", "iam", "", "123456789012", "role/my-role")).
+			To(Equal("arn:aws-cn:iam::123456789012:role/my-role"))
+	})
+
+	It("Rejects regions of other partitions", func() {
+		Expect(aws.ValidateRegionPartition("cn-north-1", "aws")).To(HaveOccurred())
+		Expect(aws.ValidateRegionPartition("cn-north-1", "aws-cn")).To(Succeed())
+	})
+})
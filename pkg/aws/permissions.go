@@ -3,9 +3,12 @@ package aws
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/aws/aws-sdk-go/service/iam"
+
 	"github.com/openshift/moactl/assets"
 )
 
@@ -28,63 +31,181 @@ type PolicyDocument struct {
 	Statement []PolicyStatement `json:"statement"`
 }
 
-// SimulateParams captures any additional details that should be used
-// when simulating permissions.
-type SimulateParams struct {
-	Region string
-}
-
-// checkPermissionsUsingQueryClient will use queryClient to query whether the credentials in targetClient can perform
-// the actions listed in the statementEntries. queryClient will need iam:GetUser and iam:SimulatePrincipalPolicy
-func checkPermissionsUsingQueryClient(queryClient *awsClient, targetUser *iam.User, policyDocument PolicyDocument,
-	params *SimulateParams) (bool, error) {
-	// Ignoring isRoot here since we only warn the user that its not best practice to use it.
-	// TODO: Add a check for isRoot in the initialize
-	allowList := []*string{}
-	for _, statement := range policyDocument.Statement {
+// Actions returns the actions of all the statements of the policy document, without duplicates.
+func (p PolicyDocument) Actions() []string {
+	actions := []string{}
+	seen := map[string]bool{}
+	for _, statement := range p.Statement {
 		for _, action := range statement.Action {
-			allowList = append(allowList, aws.String(action))
+			if !seen[action] {
+				seen[action] = true
+				actions = append(actions, action)
+			}
 		}
 	}
+	return actions
+}
+
+// RequiredPolicy is a policy document whose actions the credentials used to create clusters need
+// to be allowed to perform.
+type RequiredPolicy struct {
+	// Name identifies the policy in the reports, it is the identity that uses the permissions.
+	Name string
+
+	// Path is the location of the policy document in the assets.
+	Path string
+}
+
+// RequiredPolicies are the policies checked by VerifyPermissions: the permissions of the installer
+// and those that the 'osdCcsAdmin' user needs.
+var RequiredPolicies = []*RequiredPolicy{
+	{
+		Name: "installer",
+		Path: "templates/policies/sts_installer_permission_policy.json",
+	},
+	{
+		Name: AdminUserName,
+		Path: "templates/policies/osd_scp_policy.json",
+	},
+}
 
+// MissingPermission is an action of a required policy that the credentials aren't allowed to
+// perform. The decision is the one returned by the IAM policy simulator, 'implicitDeny' or
+// 'explicitDeny'.
+type MissingPermission struct {
+	Policy   string `json:"policy"`
+	Action   string `json:"action"`
+	Decision string `json:"decision"`
+}
+
+// SimulatePermissions uses the IAM policy simulator to check if the given principal can perform the
+// given actions in the given region, or in any region if it is empty. It returns the decision for
+// each of the actions that aren't allowed.
+func (c *awsClient) SimulatePermissions(principalARN string, actions []string,
+	region string) (map[string]string, error) {
 	input := &iam.SimulatePrincipalPolicyInput{
-		PolicySourceArn: targetUser.Arn,
-		ActionNames:     allowList,
+		PolicySourceArn: aws.String(principalARN),
+		ActionNames:     aws.StringSlice(actions),
 		ContextEntries:  []*iam.ContextEntry{},
 	}
-
-	if params != nil {
-		if params.Region != "" {
-			input.ContextEntries = append(input.ContextEntries, &iam.ContextEntry{
-				ContextKeyName:   aws.String("aws:RequestedRegion"),
-				ContextKeyType:   aws.String("stringList"),
-				ContextKeyValues: []*string{aws.String(params.Region)},
-			})
-		}
+	if region != "" {
+		input.ContextEntries = append(input.ContextEntries, &iam.ContextEntry{
+			ContextKeyName:   aws.String("aws:RequestedRegion"),
+			ContextKeyType:   aws.String("stringList"),
+			ContextKeyValues: []*string{aws.String(region)},
+		})
 	}
 
-	// Either all actions are allowed and we'll return 'true', or it's a failure
-	allClear := true
-	// Collect all failed actions
-	var failedActions []string
-
-	err := queryClient.iamClient.SimulatePrincipalPolicyPages(input,
+	denied := map[string]string{}
+	err := c.iamClient.SimulatePrincipalPolicyPages(input,
 		func(response *iam.SimulatePolicyResponse, lastPage bool) bool {
 			for _, result := range response.EvaluationResults {
-				if *result.EvalDecision != "allowed" {
-					// Don't bail out after the first failure, so we can log the full list
-					// of failed/denied actions
-					failedActions = append(failedActions, *result.EvalActionName)
-					allClear = false
+				decision := aws.StringValue(result.EvalDecision)
+				if decision != iam.PolicyEvaluationDecisionTypeAllowed {
+					denied[aws.StringValue(result.EvalActionName)] = decision
 				}
 			}
 			return !lastPage
 		})
 	if err != nil {
-		return false, fmt.Errorf("Error simulating policy: %v", err)
+		return nil, fmt.Errorf("Error simulating policy: %v", err)
+	}
+	return denied, nil
+}
+
+// VerifyPermissions checks the credentials of the client against the actions of the required
+// policies, in the region of the client, and returns the actions that they can't perform. The
+// simulation needs the 'iam:SimulatePrincipalPolicy' permission.
+func (c *awsClient) VerifyPermissions() ([]*MissingPermission, error) {
+	identity, err := c.GetIdentity()
+	if err != nil {
+		return nil, err
+	}
+	principalARN, err := c.getPolicySourceARN(identity)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := []*MissingPermission{}
+	for _, policy := range RequiredPolicies {
+		document, err := readPolicyDocument(policy.Path)
+		if err != nil {
+			return nil, err
+		}
+		actions := document.Actions()
+		denied, err := c.SimulatePermissions(principalARN, actions, c.GetRegion())
+		if err != nil {
+			return nil, err
+		}
+		for _, action := range actions {
+			if decision, ok := denied[action]; ok {
+				missing = append(missing, &MissingPermission{
+					Policy:   policy.Name,
+					Action:   action,
+					Decision: decision,
+				})
+			}
+		}
+	}
+	return missing, nil
+}
+
+// getPolicySourceARN returns the ARN of the IAM user or role of the given identity, as the policy
+// simulator doesn't accept the ARNs of sessions. The ARN of a session doesn't contain the path of
+// the role, so it is read from IAM.
+func (c *awsClient) getPolicySourceARN(identity *Identity) (string, error) {
+	switch identity.Type {
+	case RootIdentity:
+		return "", fmt.Errorf("The AWS credentials belong to the root user of the account, " +
+			"whose permissions can't be simulated. Use the credentials of an IAM user or role instead")
+	case UserIdentity:
+		return identity.ARN, nil
+	}
+	parsed, err := arn.Parse(identity.ARN)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(parsed.Resource, "assumed-role/") {
+		return "", fmt.Errorf("The permissions of AWS identity '%s' can't be simulated, use the "+
+			"credentials of an IAM user or role instead", identity.ARN)
+	}
+	output, err := c.iamClient.GetRole(&iam.GetRoleInput{RoleName: aws.String(identity.Name)})
+	if err != nil {
+		return "", fmt.Errorf("Failed to get role '%s': %v", identity.Name, err)
 	}
+	return aws.StringValue(output.Role.Arn), nil
+}
+
+// SimulateParams captures any additional details that should be used
+// when simulating permissions.
+type SimulateParams struct {
+	Region string
+}
 
-	if !allClear {
+// checkPermissionsUsingQueryClient will use queryClient to query whether the credentials in targetClient can perform
+// the actions listed in the statementEntries. queryClient will need iam:GetUser and iam:SimulatePrincipalPolicy
+func checkPermissionsUsingQueryClient(queryClient *awsClient, targetUser *iam.User, policyDocument PolicyDocument,
+	params *SimulateParams) (bool, error) {
+	// Ignoring isRoot here since we only warn the user that its not best practice to use it.
+	// TODO: Add a check for isRoot in the initialize
+	region := ""
+	if params != nil {
+		region = params.Region
+	}
+	denied, err := queryClient.SimulatePermissions(aws.StringValue(targetUser.Arn),
+		policyDocument.Actions(), region)
+	if err != nil {
+		return false, err
+	}
+
+	// Collect all failed actions, so that the full list is logged:
+	var failedActions []string
+	for _, action := range policyDocument.Actions() {
+		if _, ok := denied[action]; ok {
+			failedActions = append(failedActions, action)
+		}
+	}
+	if len(failedActions) > 0 {
 		return false, fmt.Errorf("Actions not allowed with tested credentials: %v", failedActions)
 	}
 
@@ -106,6 +227,20 @@ func validatePolicyDocuments(queryClient *awsClient, targetUser *iam.User, polic
 	return true, nil
 }
 
+// readPolicyDocument reads the policy document with the given path from the assets.
+func readPolicyDocument(path string) (PolicyDocument, error) {
+	policyDocument := PolicyDocument{}
+	data, err := assets.Asset(path)
+	if err != nil {
+		return policyDocument, fmt.Errorf("Unable to load file: %s", path)
+	}
+	err = json.Unmarshal(data, &policyDocument)
+	if err != nil {
+		return policyDocument, fmt.Errorf("Error unmarshalling policy document '%s': %v", path, err)
+	}
+	return policyDocument, nil
+}
+
 // readSCPPolicy reads a SCP policy from file into a Policy Document
 // SCP policies are structured the same as a IAM Policy Document the contain
 // IAM Policy Statements
@@ -0,0 +1,90 @@
+package aws_test
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mocks"
+)
+
+var _ = Describe("VerifyPermissions", func() {
+	var (
+		client   aws.Client
+		mockCtrl *gomock.Controller
+
+		mockIAMAPI *mocks.MockIAMAPI
+		mockSTSAPI *mocks.MockSTSAPI
+	)
+
+	const roleARN = "arn:aws:iam::123456789012:role/admins/installer"
+
+	callerIdentity := func(arn string) {
+		mockSTSAPI.EXPECT().GetCallerIdentity(gomock.Any()).Return(&sts.GetCallerIdentityOutput{
+			Arn: awssdk.String(arn),
+		}, nil)
+	}
+
+	// The simulation denies the given action and allows all the others:
+	simulation := func(deniedAction string) {
+		mockIAMAPI.EXPECT().SimulatePrincipalPolicyPages(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(input *iam.SimulatePrincipalPolicyInput,
+				fn func(*iam.SimulatePolicyResponse, bool) bool) error {
+				Expect(awssdk.StringValue(input.PolicySourceArn)).To(Equal(roleARN))
+				response := &iam.SimulatePolicyResponse{}
+				for _, action := range input.ActionNames {
+					decision := iam.PolicyEvaluationDecisionTypeAllowed
+					if awssdk.StringValue(action) == deniedAction {
+						decision = iam.PolicyEvaluationDecisionTypeImplicitDeny
+					}
+					response.EvaluationResults = append(response.EvaluationResults, &iam.EvaluationResult{
+						EvalActionName: action,
+						EvalDecision:   awssdk.String(decision),
+					})
+				}
+				fn(response, true)
+				return nil
+			}).Times(len(aws.RequiredPolicies))
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		var apis *apiMocks
+		client, apis = newClient(mockCtrl, regionSession("us-east-1"))
+		mockIAMAPI = apis.IAM
+		mockSTSAPI = apis.STS
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("Simulates the role of a session and reports the denied actions", func() {
+		callerIdentity("arn:aws:sts::123456789012:assumed-role/installer/session")
+		mockIAMAPI.EXPECT().GetRole(gomock.Any()).Return(&iam.GetRoleOutput{
+			Role: &iam.Role{Arn: awssdk.String(roleARN)},
+		}, nil)
+		simulation("ec2:AllocateAddress")
+
+		missing, err := client.VerifyPermissions()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(missing).To(ConsistOf(&aws.MissingPermission{
+			Policy:   "installer",
+			Action:   "ec2:AllocateAddress",
+			Decision: iam.PolicyEvaluationDecisionTypeImplicitDeny,
+		}))
+	})
+
+	It("Rejects the root user", func() {
+		callerIdentity("arn:aws:iam::123456789012:root")
+
+		_, err := client.VerifyPermissions()
+
+		Expect(err).To(MatchError(ContainSubstring("root user")))
+	})
+})
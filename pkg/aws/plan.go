@@ -0,0 +1,385 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that calculate the AWS quota that a cluster that hasn't been
+// created yet will use.
+
+package aws
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+)
+
+// Instance types and number of the nodes that every cluster has, in addition to the compute nodes.
+// The bootstrap node only runs during the installation, but it still needs quota.
+const (
+	controlPlaneMachineType = "m5.xlarge"
+	controlPlaneNodes       = 3
+	infraMachineType        = "r5.xlarge"
+	singleAZInfraNodes      = 2
+	multiAZInfraNodes       = 3
+	bootstrapMachineType    = "m5.large"
+)
+
+// standardClasses are the instance classes that count towards the quota of running on-demand
+// standard instances.
+var standardClasses = []string{"a", "c", "d", "h", "i", "m", "r", "t", "z"}
+
+// ClusterPlan describes a cluster that hasn't been created yet.
+type ClusterPlan struct {
+	MultiAZ            bool
+	Private            bool
+	ComputeMachineType string
+	ComputeNodes       int
+}
+
+// Zones returns the number of availability zones that the cluster uses.
+func (p *ClusterPlan) Zones() int {
+	if p.MultiAZ {
+		return 3
+	}
+	return 1
+}
+
+// InfraNodes returns the number of infrastructure nodes of the cluster.
+func (p *ClusterPlan) InfraNodes() int {
+	if p.MultiAZ {
+		return multiAZInfraNodes
+	}
+	return singleAZInfraNodes
+}
+
+// ZoneNodes is the number of nodes of each kind that a planned cluster has in one availability
+// zone.
+type ZoneNodes struct {
+	ControlPlane int
+	Infra        int
+	Compute      int
+}
+
+// NodesPerZone returns how the nodes of the cluster are spread across its availability zones,
+// which the installer does round robin.
+func (p *ClusterPlan) NodesPerZone() []*ZoneNodes {
+	result := make([]*ZoneNodes, p.Zones())
+	for i := range result {
+		result[i] = &ZoneNodes{}
+	}
+	for i := 0; i < controlPlaneNodes; i++ {
+		result[i%len(result)].ControlPlane++
+	}
+	for i := 0; i < p.InfraNodes(); i++ {
+		result[i%len(result)].Infra++
+	}
+	for i := 0; i < p.ComputeNodes; i++ {
+		result[i%len(result)].Compute++
+	}
+	return result
+}
+
+// QuotaUsage describes how much of an AWS quota a planned cluster requires and how much of it is
+// already used by other resources of the account.
+type QuotaUsage struct {
+	ServiceCode string `json:"service_code"`
+	QuotaCode   string `json:"quota_code"`
+	QuotaName   string `json:"quota_name"`
+	Required    int    `json:"required"`
+	Used        int    `json:"used"`
+	Limit       int    `json:"limit"`
+}
+
+// Available returns the part of the quota that isn't used yet.
+func (u *QuotaUsage) Available() int {
+	return u.Limit - u.Used
+}
+
+// Sufficient checks if the part of the quota that isn't used yet is enough for the cluster.
+func (u *QuotaUsage) Sufficient() bool {
+	return u.Required <= u.Available()
+}
+
+// GetClusterQuotaUsage calculates the quota that the given planned cluster requires, and how much
+// of it is already used in the region of the client.
+func (c *awsClient) GetClusterQuotaUsage(plan *ClusterPlan) ([]*QuotaUsage, error) {
+	zones := plan.Zones()
+	infraNodes := plan.InfraNodes()
+
+	// The number of vCPUs of each instance type is needed to calculate the instance quotas:
+	vcpus, err := c.getInstanceTypeVCPUs(controlPlaneMachineType, infraMachineType, bootstrapMachineType,
+		plan.ComputeMachineType)
+	if err != nil {
+		return nil, err
+	}
+	standardVCPUs := controlPlaneNodes*vcpus[controlPlaneMachineType] +
+		infraNodes*vcpus[infraMachineType] +
+		vcpus[bootstrapMachineType]
+	computeVCPUs := plan.ComputeNodes * vcpus[plan.ComputeMachineType]
+
+	usages := []*QuotaUsage{}
+	usedVCPUs, err := c.getRunningVCPUs(isStandardInstanceType)
+	if err != nil {
+		return nil, err
+	}
+	standard := &QuotaUsage{
+		ServiceCode: "ec2",
+		QuotaCode:   "L-1216C47A",
+		QuotaName:   "Running On-Demand Standard (A, C, D, H, I, M, R, T, Z) instances (vCPUs)",
+		Required:    standardVCPUs,
+		Used:        usedVCPUs,
+	}
+	usages = append(usages, standard)
+	switch {
+	case isStandardInstanceType(plan.ComputeMachineType):
+		standard.Required += computeVCPUs
+	case IsGPUInstanceType(plan.ComputeMachineType):
+		quota := getGPUQuota(plan.ComputeMachineType)
+		usedVCPUs, err := c.getRunningVCPUs(func(instanceType string) bool {
+			return getGPUQuota(instanceType) == quota
+		})
+		if err != nil {
+			return nil, err
+		}
+		usages = append(usages, &QuotaUsage{
+			ServiceCode: "ec2",
+			QuotaCode:   quota.QuotaCode,
+			QuotaName:   quota.QuotaName + " (vCPUs)",
+			Required:    computeVCPUs,
+			Used:        usedVCPUs,
+		})
+	default:
+		c.logger.Debug(fmt.Sprintf("Instance type '%s' doesn't count towards a known quota",
+			plan.ComputeMachineType))
+	}
+
+	// Each availability zone has a NAT gateway with its own Elastic IP address:
+	addresses, err := c.ec2Client.DescribeAddresses(&ec2.DescribeAddressesInput{
+		Filters: []*ec2.Filter{{
+			Name:   aws.String("domain"),
+			Values: aws.StringSlice([]string{"vpc"}),
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list Elastic IP addresses: %v", err)
+	}
+	usages = append(usages, &QuotaUsage{
+		ServiceCode: "ec2",
+		QuotaCode:   "L-0263D0A3",
+		QuotaName:   "Number of EIPs - VPC EIPs",
+		Required:    zones,
+		Used:        len(addresses.Addresses),
+	})
+	usedNATGateways, err := c.getMaxNATGatewaysPerZone()
+	if err != nil {
+		return nil, err
+	}
+	usages = append(usages, &QuotaUsage{
+		ServiceCode: "vpc",
+		QuotaCode:   "L-FE5A380F",
+		QuotaName:   "NAT gateways per Availability Zone",
+		Required:    1,
+		Used:        usedNATGateways,
+	})
+
+	vpcs, err := c.ec2Client.DescribeVpcs(&ec2.DescribeVpcsInput{})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list VPCs: %v", err)
+	}
+	usages = append(usages, &QuotaUsage{
+		ServiceCode: "vpc",
+		QuotaCode:   "L-F678F1CE",
+		QuotaName:   "VPCs per Region",
+		Required:    1,
+		Used:        len(vpcs.Vpcs),
+	})
+	gateways, err := c.ec2Client.DescribeInternetGateways(&ec2.DescribeInternetGatewaysInput{})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list internet gateways: %v", err)
+	}
+	usages = append(usages, &QuotaUsage{
+		ServiceCode: "vpc",
+		QuotaCode:   "L-A4707A72",
+		QuotaName:   "Internet gateways per Region",
+		Required:    1,
+		Used:        len(gateways.InternetGateways),
+	})
+
+	// The default router uses a classic load balancer, and the API uses an internal network load
+	// balancer and, unless the cluster is private, an external one:
+	balancers, err := c.listLoadBalancers()
+	if err != nil {
+		return nil, err
+	}
+	usedClassic := 0
+	usedNetwork := 0
+	for _, balancer := range balancers {
+		switch balancer.Type {
+		case "classic":
+			usedClassic++
+		case elbv2.LoadBalancerTypeEnumNetwork:
+			usedNetwork++
+		}
+	}
+	requiredNetwork := 2
+	if plan.Private {
+		requiredNetwork = 1
+	}
+	usages = append(usages, &QuotaUsage{
+		ServiceCode: "elasticloadbalancing",
+		QuotaCode:   "L-E9E9831D",
+		QuotaName:   "Classic Load Balancers per Region",
+		Required:    1,
+		Used:        usedClassic,
+	}, &QuotaUsage{
+		ServiceCode: "elasticloadbalancing",
+		QuotaCode:   "L-69A177A2",
+		QuotaName:   "Network Load Balancers per Region",
+		Required:    requiredNetwork,
+		Used:        usedNetwork,
+	})
+
+	for _, usage := range usages {
+		output, err := c.servicequotasClient.GetServiceQuota(&servicequotas.GetServiceQuotaInput{
+			ServiceCode: aws.String(usage.ServiceCode),
+			QuotaCode:   aws.String(usage.QuotaCode),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error getting AWS service quota '%s': %v", usage.QuotaName, err)
+		}
+		usage.Limit = int(aws.Float64Value(output.Quota.Value))
+	}
+	return usages, nil
+}
+
+// isStandardInstanceType checks if the given instance type counts towards the quota of running
+// on-demand standard instances.
+func isStandardInstanceType(instanceType string) bool {
+	class := instanceClass(instanceType)
+	for _, item := range standardClasses {
+		if class == item {
+			return true
+		}
+	}
+	return false
+}
+
+// getInstanceTypeVCPUs returns the number of vCPUs of each of the given instance types.
+func (c *awsClient) getInstanceTypeVCPUs(instanceTypes ...string) (map[string]int, error) {
+	unique := map[string]bool{}
+	for _, instanceType := range instanceTypes {
+		unique[instanceType] = true
+	}
+	input := &ec2.DescribeInstanceTypesInput{}
+	for instanceType := range unique {
+		input.InstanceTypes = append(input.InstanceTypes, aws.String(instanceType))
+	}
+	output, err := c.ec2Client.DescribeInstanceTypes(input)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to describe instance types: %v", err)
+	}
+	result := map[string]int{}
+	for _, description := range output.InstanceTypes {
+		if description.VCpuInfo != nil {
+			result[aws.StringValue(description.InstanceType)] =
+				int(aws.Int64Value(description.VCpuInfo.DefaultVCpus))
+		}
+	}
+	for instanceType := range unique {
+		if _, ok := result[instanceType]; !ok {
+			return nil, fmt.Errorf("Instance type '%s' isn't available in region '%s'", instanceType, c.GetRegion())
+		}
+	}
+	return result, nil
+}
+
+// getRunningVCPUs returns the number of vCPUs of the running and pending instances whose type is
+// accepted by the given function.
+func (c *awsClient) getRunningVCPUs(accept func(instanceType string) bool) (int, error) {
+	used := 0
+	err := c.ec2Client.DescribeInstancesPages(&ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{{
+			Name:   aws.String("instance-state-name"),
+			Values: aws.StringSlice([]string{"pending", "running"}),
+		}},
+	}, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				if !accept(aws.StringValue(instance.InstanceType)) || instance.CpuOptions == nil {
+					continue
+				}
+				used += int(aws.Int64Value(instance.CpuOptions.CoreCount) *
+					aws.Int64Value(instance.CpuOptions.ThreadsPerCore))
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return 0, fmt.Errorf("Error listing EC2 instances: %v", err)
+	}
+	return used, nil
+}
+
+// getMaxNATGatewaysPerZone returns the number of NAT gateways of the availability zone that has the
+// most of them, as that is the zone where the quota runs out first.
+func (c *awsClient) getMaxNATGatewaysPerZone() (int, error) {
+	subnetIDs := []string{}
+	err := c.ec2Client.DescribeNatGatewaysPages(&ec2.DescribeNatGatewaysInput{
+		Filter: []*ec2.Filter{{
+			Name:   aws.String("state"),
+			Values: aws.StringSlice([]string{"pending", "available"}),
+		}},
+	}, func(page *ec2.DescribeNatGatewaysOutput, lastPage bool) bool {
+		for _, gateway := range page.NatGateways {
+			subnetIDs = append(subnetIDs, aws.StringValue(gateway.SubnetId))
+		}
+		return true
+	})
+	if err != nil {
+		return 0, fmt.Errorf("Failed to list NAT gateways: %v", err)
+	}
+	if len(subnetIDs) == 0 {
+		return 0, nil
+	}
+	unique := map[string]bool{}
+	for _, subnetID := range subnetIDs {
+		unique[subnetID] = true
+	}
+	input := &ec2.DescribeSubnetsInput{}
+	for subnetID := range unique {
+		input.SubnetIds = append(input.SubnetIds, aws.String(subnetID))
+	}
+	subnets, err := c.ec2Client.DescribeSubnets(input)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to describe subnets of NAT gateways: %v", err)
+	}
+	zones := map[string]string{}
+	for _, subnet := range subnets.Subnets {
+		zones[aws.StringValue(subnet.SubnetId)] = aws.StringValue(subnet.AvailabilityZone)
+	}
+	counts := map[string]int{}
+	result := 0
+	for _, subnetID := range subnetIDs {
+		zone := zones[subnetID]
+		counts[zone]++
+		if counts[zone] > result {
+			result = counts[zone]
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,28 @@
+package aws_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws"
+)
+
+var _ = Describe("ClusterPlan", func() {
+	It("Puts all the nodes of a single-AZ cluster in one zone", func() {
+		plan := &aws.ClusterPlan{ComputeNodes: 2}
+
+		Expect(plan.NodesPerZone()).To(Equal([]*aws.ZoneNodes{
+			{ControlPlane: 3, Infra: 2, Compute: 2},
+		}))
+	})
+
+	It("Spreads the nodes of a multi-AZ cluster round robin", func() {
+		plan := &aws.ClusterPlan{MultiAZ: true, ComputeNodes: 4}
+
+		Expect(plan.NodesPerZone()).To(Equal([]*aws.ZoneNodes{
+			{ControlPlane: 1, Infra: 1, Compute: 2},
+			{ControlPlane: 1, Infra: 1, Compute: 1},
+			{ControlPlane: 1, Infra: 1, Compute: 1},
+		}))
+	})
+})
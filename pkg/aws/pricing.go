@@ -0,0 +1,185 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that estimate what the nodes of a cluster cost, using the
+// on-demand prices of the AWS Price List API. The estimates don't include taxes, discounts, data
+// transfer or the fees of the service.
+
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/pricing"
+)
+
+// HoursPerMonth is the average number of hours in a month that AWS uses for monthly estimates.
+const HoursPerMonth = 730
+
+// Size and type of the root volumes of the nodes when the machine pool doesn't say otherwise:
+const (
+	DefaultRootVolumeSize = 300
+	DefaultRootVolumeType = "gp3"
+)
+
+// pricingRegion is the region of the endpoint of the Price List API, which returns the prices of
+// all the regions.
+const pricingRegion = "us-east-1"
+
+// PoolCost is the projected monthly cost of the nodes of a machine pool. Pools that aren't
+// autoscaled have the same minimum and maximum number of nodes.
+type PoolCost struct {
+	Name           string  `json:"name"`
+	InstanceType   string  `json:"instance_type"`
+	MinNodes       int     `json:"min_nodes"`
+	MaxNodes       int     `json:"max_nodes"`
+	DiskSize       int     `json:"disk_size_gib"`
+	InstanceHourly float64 `json:"instance_hourly_usd"`
+	DiskMonthly    float64 `json:"disk_monthly_usd"`
+	MinMonthly     float64 `json:"min_monthly_usd"`
+	MaxMonthly     float64 `json:"max_monthly_usd"`
+}
+
+// EstimatePoolCost calculates the monthly cost of a pool from the hourly price of its instance type
+// and the monthly price of a GiB of its root volumes.
+func EstimatePoolCost(name string, instanceType string, minNodes int, maxNodes int, diskSize int,
+	instanceHourly float64, diskGiBMonthly float64) *PoolCost {
+	diskMonthly := float64(diskSize) * diskGiBMonthly
+	nodeMonthly := instanceHourly*HoursPerMonth + diskMonthly
+	return &PoolCost{
+		Name:           name,
+		InstanceType:   instanceType,
+		MinNodes:       minNodes,
+		MaxNodes:       maxNodes,
+		DiskSize:       diskSize,
+		InstanceHourly: instanceHourly,
+		DiskMonthly:    diskMonthly,
+		MinMonthly:     float64(minNodes) * nodeMonthly,
+		MaxMonthly:     float64(maxNodes) * nodeMonthly,
+	}
+}
+
+// TotalCost adds the minimum and maximum monthly costs of the given pools.
+func TotalCost(pools []*PoolCost) (minMonthly float64, maxMonthly float64) {
+	for _, pool := range pools {
+		minMonthly += pool.MinMonthly
+		maxMonthly += pool.MaxMonthly
+	}
+	return
+}
+
+// GetInstancePrices returns the on-demand hourly price in US dollars of each of the given instance
+// types in the region of the client, for the Linux instances used by the nodes.
+func (c *awsClient) GetInstancePrices(instanceTypes []string) (map[string]float64, error) {
+	prices := map[string]float64{}
+	for _, instanceType := range instanceTypes {
+		if _, ok := prices[instanceType]; ok {
+			continue
+		}
+		price, err := c.getPrice("AmazonEC2", map[string]string{
+			"regionCode":      c.GetRegion(),
+			"instanceType":    instanceType,
+			"operatingSystem": "Linux",
+			"tenancy":         "Shared",
+			"preInstalledSw":  "NA",
+			"capacitystatus":  "Used",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get price of instance type '%s': %v", instanceType, err)
+		}
+		prices[instanceType] = price
+	}
+	return prices, nil
+}
+
+// GetVolumePrice returns the monthly price in US dollars of a GiB of EBS volumes of the given type
+// in the region of the client.
+func (c *awsClient) GetVolumePrice(volumeType string) (float64, error) {
+	price, err := c.getPrice("AmazonEC2", map[string]string{
+		"regionCode":    c.GetRegion(),
+		"productFamily": "Storage",
+		"volumeApiName": volumeType,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("Failed to get price of '%s' volumes: %v", volumeType, err)
+	}
+	return price, nil
+}
+
+// getPrice returns the on-demand price of the only product of the given service that matches all
+// the given attributes.
+func (c *awsClient) getPrice(service string, attributes map[string]string) (float64, error) {
+	filters := []*pricing.Filter{}
+	for name, value := range attributes {
+		filters = append(filters, &pricing.Filter{
+			Type:  aws.String(pricing.FilterTypeTermMatch),
+			Field: aws.String(name),
+			Value: aws.String(value),
+		})
+	}
+	client := pricing.New(c.awsSession, aws.NewConfig().WithRegion(pricingRegion))
+	output, err := client.GetProducts(&pricing.GetProductsInput{
+		ServiceCode: aws.String(service),
+		Filters:     filters,
+		MaxResults:  aws.Int64(1),
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(output.PriceList) == 0 {
+		return 0, fmt.Errorf("No price found")
+	}
+	return parseOnDemandPrice(output.PriceList[0])
+}
+
+// priceListItem contains the subset of the attributes of a product of the Price List API that
+// describe its on-demand price.
+type priceListItem struct {
+	Terms struct {
+		OnDemand map[string]struct {
+			PriceDimensions map[string]struct {
+				PricePerUnit map[string]string `json:"pricePerUnit"`
+			} `json:"priceDimensions"`
+		} `json:"OnDemand"`
+	} `json:"terms"`
+}
+
+// parseOnDemandPrice extracts the price in US dollars of the first on-demand term of the given
+// product of the Price List API.
+func parseOnDemandPrice(value aws.JSONValue) (float64, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return 0, err
+	}
+	var item priceListItem
+	err = json.Unmarshal(data, &item)
+	if err != nil {
+		return 0, err
+	}
+	for _, term := range item.Terms.OnDemand {
+		for _, dimension := range term.PriceDimensions {
+			usd, ok := dimension.PricePerUnit["USD"]
+			if !ok {
+				continue
+			}
+			return strconv.ParseFloat(usd, 64)
+		}
+	}
+	return 0, fmt.Errorf("No on-demand price found")
+}
@@ -0,0 +1,44 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws"
+)
+
+var _ = Describe("Pricing", func() {
+	It("Estimates the monthly cost of the nodes and their disks", func() {
+		cost := aws.EstimatePoolCost("mp-1", "m5.xlarge", 2, 4, 100, 0.192, 0.08)
+
+		Expect(cost.DiskMonthly).To(BeNumerically("~", 8, 0.001))
+		Expect(cost.MinMonthly).To(BeNumerically("~", 2*(0.192*aws.HoursPerMonth+8), 0.001))
+		Expect(cost.MaxMonthly).To(BeNumerically("~", 4*(0.192*aws.HoursPerMonth+8), 0.001))
+	})
+
+	It("Adds the costs of all the pools", func() {
+		minMonthly, maxMonthly := aws.TotalCost([]*aws.PoolCost{
+			aws.EstimatePoolCost("default", "m5.xlarge", 2, 2, 300, 0.192, 0.08),
+			aws.EstimatePoolCost("mp-1", "r5.xlarge", 0, 3, 300, 0.252, 0.08),
+		})
+
+		Expect(minMonthly).To(BeNumerically("~", 2*(0.192*aws.HoursPerMonth+24), 0.001))
+		Expect(maxMonthly).To(BeNumerically("~", minMonthly+3*(0.252*aws.HoursPerMonth+24), 0.001))
+	})
+})
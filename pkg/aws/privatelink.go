@@ -0,0 +1,108 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions for clusters whose API is only reachable over AWS PrivateLink:
+// the checks of the VPC where they are installed and the endpoint services that expose their API.
+
+package aws
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// PrivateLinkEndpointService is a VPC endpoint service that exposes the API of a PrivateLink
+// cluster through its network load balancers.
+type PrivateLinkEndpointService struct {
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	State         string   `json:"state"`
+	LoadBalancers []string `json:"load_balancers"`
+}
+
+// ValidatePrivateLinkVPC checks that the VPC of the given subnets resolves the private DNS names
+// of the VPC endpoints, as PrivateLink clusters need both DNS support and DNS hostnames enabled.
+// The subnets themselves are checked by ValidateSubnets. If the VPC can't be used the returned
+// error is a *SubnetsError listing all the problems found.
+func (c *awsClient) ValidatePrivateLinkVPC(subnetIDs []string) error {
+	subnets, err := c.FindSubnets(subnetIDs)
+	if err != nil {
+		return fmt.Errorf("Failed to describe subnets: %v", err)
+	}
+	if len(subnets) == 0 {
+		return &SubnetsError{Problems: []string{
+			fmt.Sprintf("none of the subnets exist in region '%s'", c.GetRegion()),
+		}}
+	}
+	vpcID := aws.StringValue(subnets[0].VpcId)
+
+	problems := []string{}
+	for _, attribute := range []string{ec2.VpcAttributeNameEnableDnsSupport, ec2.VpcAttributeNameEnableDnsHostnames} {
+		output, err := c.ec2Client.DescribeVpcAttribute(&ec2.DescribeVpcAttributeInput{
+			VpcId:     aws.String(vpcID),
+			Attribute: aws.String(attribute),
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to describe attribute '%s' of VPC '%s': %v", attribute, vpcID, err)
+		}
+		var value *ec2.AttributeBooleanValue
+		if attribute == ec2.VpcAttributeNameEnableDnsSupport {
+			value = output.EnableDnsSupport
+		} else {
+			value = output.EnableDnsHostnames
+		}
+		if value == nil || !aws.BoolValue(value.Value) {
+			problems = append(problems, fmt.Sprintf("VPC '%s' doesn't have '%s' enabled, which PrivateLink "+
+				"clusters need", vpcID, attribute))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &SubnetsError{Problems: problems}
+	}
+	return nil
+}
+
+// GetPrivateLinkEndpointServices returns the VPC endpoint services of the cluster with the given
+// infrastructure identifier in the region of the client. They are created when the cluster is
+// installed, so there are none before that.
+func (c *awsClient) GetPrivateLinkEndpointServices(infraID string) ([]*PrivateLinkEndpointService, error) {
+	result := []*PrivateLinkEndpointService{}
+	err := c.ec2Client.DescribeVpcEndpointServiceConfigurationsPages(
+		&ec2.DescribeVpcEndpointServiceConfigurationsInput{
+			Filters: []*ec2.Filter{{
+				Name:   aws.String("tag-key"),
+				Values: aws.StringSlice([]string{clusterTagKey(infraID)}),
+			}},
+		},
+		func(page *ec2.DescribeVpcEndpointServiceConfigurationsOutput, lastPage bool) bool {
+			for _, service := range page.ServiceConfigurations {
+				result = append(result, &PrivateLinkEndpointService{
+					ID:            aws.StringValue(service.ServiceId),
+					Name:          aws.StringValue(service.ServiceName),
+					State:         aws.StringValue(service.ServiceState),
+					LoadBalancers: aws.StringValueSlice(service.NetworkLoadBalancerArns),
+				})
+			}
+			return true
+		})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
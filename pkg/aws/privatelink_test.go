@@ -0,0 +1,71 @@
+package aws_test
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mocks"
+)
+
+var _ = Describe("ValidatePrivateLinkVPC", func() {
+	var (
+		client   aws.Client
+		mockCtrl *gomock.Controller
+
+		mockEC2API *mocks.MockEC2API
+	)
+
+	vpcAttributes := func(dnsSupport bool, dnsHostnames bool) {
+		mockEC2API.EXPECT().DescribeVpcAttribute(&ec2.DescribeVpcAttributeInput{
+			VpcId:     awssdk.String("vpc-1"),
+			Attribute: awssdk.String(ec2.VpcAttributeNameEnableDnsSupport),
+		}).Return(&ec2.DescribeVpcAttributeOutput{
+			EnableDnsSupport: &ec2.AttributeBooleanValue{Value: awssdk.Bool(dnsSupport)},
+		}, nil)
+		mockEC2API.EXPECT().DescribeVpcAttribute(&ec2.DescribeVpcAttributeInput{
+			VpcId:     awssdk.String("vpc-1"),
+			Attribute: awssdk.String(ec2.VpcAttributeNameEnableDnsHostnames),
+		}).Return(&ec2.DescribeVpcAttributeOutput{
+			EnableDnsHostnames: &ec2.AttributeBooleanValue{Value: awssdk.Bool(dnsHostnames)},
+		}, nil)
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		var apis *apiMocks
+		client, apis = newClient(mockCtrl, regionSession("us-east-1"))
+		mockEC2API = apis.EC2
+		mockEC2API.EXPECT().DescribeSubnets(gomock.Any()).Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []*ec2.Subnet{{
+				SubnetId: awssdk.String("subnet-1"),
+				VpcId:    awssdk.String("vpc-1"),
+			}},
+		}, nil)
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("Accepts VPCs with DNS support and hostnames", func() {
+		vpcAttributes(true, true)
+
+		err := client.ValidatePrivateLinkVPC([]string{"subnet-1"})
+
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Reports VPCs without DNS hostnames", func() {
+		vpcAttributes(true, false)
+
+		err := client.ValidatePrivateLinkVPC([]string{"subnet-1"})
+
+		Expect(err).To(BeAssignableToTypeOf(&aws.SubnetsError{}))
+		Expect(err.(*aws.SubnetsError).Problems).To(HaveLen(1))
+		Expect(err.Error()).To(ContainSubstring("doesn't have 'enableDnsHostnames' enabled"))
+	})
+})
@@ -0,0 +1,76 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the support for the 'credential_process' setting of the AWS shared
+// configuration, used by credential brokers that generate short lived credentials on demand.
+
+package aws
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/processcreds"
+)
+
+// processCredentials contains the credentials obtained running the credential process of each
+// profile. The tool creates a new session for each region that it uses, and the credentials of
+// each session would run the process again, so they are shared instead. The credentials object
+// only runs the process again when the credentials that it returned expire, like the AWS CLI does.
+var (
+	processCredentials      = map[string]*credentials.Credentials{}
+	processCredentialsMutex sync.Mutex
+)
+
+// cachedProcessCredentials returns the credentials of the credential process of the given profile
+// that were saved by a previous session, or nil if there are none.
+func cachedProcessCredentials(profile string) *credentials.Credentials {
+	processCredentialsMutex.Lock()
+	defer processCredentialsMutex.Unlock()
+	return processCredentials[profile]
+}
+
+// cacheProcessCredentials saves the credentials of the given profile so that they are reused by
+// the following sessions, but only if they were obtained running a credential process.
+func cacheProcessCredentials(profile string, creds *credentials.Credentials, value credentials.Value) {
+	if value.ProviderName != processcreds.ProviderName {
+		return
+	}
+	processCredentialsMutex.Lock()
+	defer processCredentialsMutex.Unlock()
+	processCredentials[profile] = creds
+}
+
+// processCredentialsError returns a message explaining the failure of the credential process, or
+// an empty string if the error didn't come from the credential process.
+func processCredentialsError(err error) string {
+	aerr, ok := err.(awserr.Error)
+	if !ok {
+		return ""
+	}
+	switch aerr.Code() {
+	case processcreds.ErrCodeProcessProviderExecution:
+		return fmt.Sprintf("Failed to run the 'credential_process' of the AWS profile: %v", aerr.OrigErr())
+	case processcreds.ErrCodeProcessProviderParse,
+		processcreds.ErrCodeProcessProviderVersion,
+		processcreds.ErrCodeProcessProviderRequired:
+		return fmt.Sprintf("The 'credential_process' of the AWS profile returned invalid credentials: %s",
+			aerr.Message())
+	}
+	return ""
+}
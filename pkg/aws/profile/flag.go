@@ -14,37 +14,26 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// This file contains functions used to implement the '--profile' command line option.
+// This file contains functions used to select the AWS profile. The profile is selected with the
+// '--profile' command line option when it isn't the name of a profile of the configuration file of
+// rosa, with the 'AWS_PROFILE' environment variable, or with that configuration file.
 
 package profile
 
 import (
 	"os"
 
-	"github.com/spf13/pflag"
+	"github.com/openshift/moactl/pkg/config"
 )
 
-// AddFlag adds the debug flag to the given set of command line flags.
-func AddFlag(flags *pflag.FlagSet) {
-	flags.StringVar(
-		&profile,
-		"profile",
-		"",
-		"Use a specific AWS profile from your credential file.",
-	)
-}
-
 // Profile returns a string with the name of the AWS profile being used.
 func Profile() string {
-	if profile != "" {
-		return profile
+	if flag := config.Flag(); flag != "" && !config.IsProfile(flag) {
+		return flag
 	}
 	awsProfile := os.Getenv("AWS_PROFILE")
 	if awsProfile != "" {
 		return awsProfile
 	}
-	return ""
+	return config.AWSProfile()
 }
-
-// profile is a string flag that indicates which AWS profile is being used.
-var profile string
@@ -2,87 +2,199 @@ package aws
 
 import (
 	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/moactl/pkg/aws/quotas"
 )
 
-type quota struct {
-	ServiceCode  string
-	QuotaName    string
-	QuotaCode    string
-	DesiredValue *float64
+// Maximum number of services whose quotas are listed at the same time, for each region:
+const maxQuotaWorkers = 4
+
+// QuotaCheck is the result of comparing one of the service quotas that clusters need with the
+// value of the quota in the account. Missing is zero when the quota is sufficient.
+type QuotaCheck struct {
+	Region      string  `json:"region"`
+	ServiceCode string  `json:"service_code"`
+	QuotaCode   string  `json:"quota_code"`
+	QuotaName   string  `json:"quota_name"`
+	Required    float64 `json:"required"`
+	Value       float64 `json:"value"`
+	Missing     float64 `json:"missing"`
+}
+
+// Sufficient checks if the quota is at least the value that clusters need.
+func (q *QuotaCheck) Sufficient() bool {
+	return q.Missing == 0
+}
+
+// InsufficientQuotas returns the checks of the given list whose quota isn't sufficient.
+func InsufficientQuotas(checks []*QuotaCheck) []*QuotaCheck {
+	insufficient := []*QuotaCheck{}
+	for _, check := range checks {
+		if !check.Sufficient() {
+			insufficient = append(insufficient, check)
+		}
+	}
+	return insufficient
+}
+
+// QuotasError contains all the quotas that are lower than what clusters need, so that they can be
+// reported to the user at once.
+type QuotasError struct {
+	Checks []*QuotaCheck
+}
+
+func (e *QuotasError) Error() string {
+	problems := make([]string, len(e.Checks))
+	for i, check := range e.Checks {
+		problems[i] = fmt.Sprintf(
+			"Service %s quota code %s %s not valid, expected quota of at least %d, but got %d",
+			check.ServiceCode, check.QuotaCode, check.QuotaName,
+			int(check.Required), int(check.Value))
+	}
+	return strings.Join(problems, "\n")
+}
+
+// CheckQuotas compares the service quotas that clusters need with the quotas of the region of the
+// client. The quotas of each service are listed only once, and the services are queried in
+// parallel using at most a fixed number of workers, so that the account isn't throttled by the
+// Service Quotas API. The checks are returned in the same order for all the regions.
+func (c *awsClient) CheckQuotas() ([]*QuotaCheck, error) {
+	serviceCodes := []string{}
+	for _, quota := range quotas.Required {
+		found := false
+		for _, serviceCode := range serviceCodes {
+			if serviceCode == quota.ServiceCode {
+				found = true
+				break
+			}
+		}
+		if !found {
+			serviceCodes = append(serviceCodes, quota.ServiceCode)
+		}
+	}
+
+	type result struct {
+		serviceCode string
+		quotas      []*servicequotas.ServiceQuota
+		err         error
+	}
+
+	jobs := make(chan string)
+	results := make(chan result, len(serviceCodes))
+
+	var wg sync.WaitGroup
+	workers := maxQuotaWorkers
+	if len(serviceCodes) < workers {
+		workers = len(serviceCodes)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for serviceCode := range jobs {
+				quotas, err := ListServiceQuotas(c, serviceCode)
+				results <- result{serviceCode: serviceCode, quotas: quotas, err: err}
+			}
+		}()
+	}
+
+	for _, serviceCode := range serviceCodes {
+		jobs <- serviceCode
+	}
+	close(jobs)
+	wg.Wait()
+	close(results)
+
+	quotasByService := map[string][]*servicequotas.ServiceQuota{}
+	for r := range results {
+		if r.err != nil {
+			return nil, fmt.Errorf("Error listing AWS service quotas: %s %v", r.serviceCode, r.err)
+		}
+		quotasByService[r.serviceCode] = r.quotas
+	}
+
+	region := c.GetRegion()
+	checks := make([]*QuotaCheck, len(quotas.Required))
+	for i, quota := range quotas.Required {
+		serviceQuota, err := GetServiceQuota(quotasByService[quota.ServiceCode], quota.QuotaCode)
+		if err != nil || serviceQuota.Value == nil {
+			return nil, fmt.Errorf("Error getting AWS service quota: %s %v", quota.ServiceCode, err)
+		}
+		check := &QuotaCheck{
+			Region:      region,
+			ServiceCode: quota.ServiceCode,
+			QuotaCode:   quota.QuotaCode,
+			QuotaName:   quota.QuotaName,
+			Required:    quota.Required,
+			Value:       aws.Float64Value(serviceQuota.Value),
+		}
+		if check.Value < check.Required {
+			check.Missing = check.Required - check.Value
+		} else {
+			c.logger.Debug(fmt.Sprintf("Service %s quota code %s is ok", quota.ServiceCode, quota.QuotaCode))
+		}
+		checks[i] = check
+	}
+	return checks, nil
 }
 
-// List of service quotas we verify for cluster installs
-// to support 5 x multi zone clusters
-var serviceQuotaServices = []quota{
-	{
-		ServiceCode:  "ec2",
-		QuotaCode:    "L-0263D0A3",
-		QuotaName:    "Number of EIPs - VPC EIPs",
-		DesiredValue: aws.Float64(5.0),
-	},
-	{
-		ServiceCode:  "ec2",
-		QuotaCode:    "L-1216C47A",
-		QuotaName:    "Running On-Demand Standard (A, C, D, H, I, M, R, T, Z) instances",
-		DesiredValue: aws.Float64(100.0),
-	},
-	{
-		ServiceCode:  "vpc",
-		QuotaCode:    "L-F678F1CE",
-		QuotaName:    "VPCs per Region",
-		DesiredValue: aws.Float64(5.0),
-	},
-	{
-		ServiceCode:  "vpc",
-		QuotaCode:    "L-A4707A72",
-		QuotaName:    "Internet gateways per Region",
-		DesiredValue: aws.Float64(5.0),
-	},
-	{
-		ServiceCode:  "vpc",
-		QuotaCode:    "L-DF5E4CA3",
-		QuotaName:    "Network interfaces per Region",
-		DesiredValue: aws.Float64(5000.0),
-	},
-	{
-		ServiceCode:  "ebs",
-		QuotaCode:    "L-D18FCD1D",
-		QuotaName:    "General Purpose SSD (gp2) volume storage",
-		DesiredValue: aws.Float64(300.0),
-	},
-	{
-		ServiceCode:  "ebs",
-		QuotaCode:    "L-309BACF6",
-		QuotaName:    "Number of EBS snapshots",
-		DesiredValue: aws.Float64(300.0),
-	},
-	{
-		ServiceCode:  "ebs",
-		QuotaCode:    "L-B3A130E6",
-		QuotaName:    "Provisioned IOPS",
-		DesiredValue: aws.Float64(300000.0),
-	},
-	{
-		ServiceCode:  "ebs",
-		QuotaCode:    "L-FD252861",
-		QuotaName:    "Provisioned IOPS SSD (io1) volume storage",
-		DesiredValue: aws.Float64(300.0),
-	},
-	{
-		ServiceCode:  "elasticloadbalancing",
-		QuotaCode:    "L-53DA6B97",
-		QuotaName:    "Application Load Balancers per Region",
-		DesiredValue: aws.Float64(50.0),
-	},
-	{
-		ServiceCode:  "elasticloadbalancing",
-		QuotaCode:    "L-E9E9831D",
-		QuotaName:    "Classic Load Balancers per Region",
-		DesiredValue: aws.Float64(20.0),
-	},
+// CheckQuotasByRegion runs CheckQuotas for each of the given regions. The regions are queried in
+// parallel, like the availability zones.
+func CheckQuotasByRegion(logger *logrus.Logger, regions []string) (map[string][]*QuotaCheck, error) {
+	type result struct {
+		region string
+		checks []*QuotaCheck
+		err    error
+	}
+
+	jobs := make(chan string)
+	results := make(chan result, len(regions))
+
+	var wg sync.WaitGroup
+	workers := maxZoneWorkers
+	if len(regions) < workers {
+		workers = len(regions)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for region := range jobs {
+				client, err := NewClient().
+					Logger(logger).
+					Region(region).
+					Build()
+				if err != nil {
+					results <- result{region: region, err: err}
+					continue
+				}
+				checks, err := client.CheckQuotas()
+				results <- result{region: region, checks: checks, err: err}
+			}
+		}()
+	}
+
+	for _, region := range regions {
+		jobs <- region
+	}
+	close(jobs)
+	wg.Wait()
+	close(results)
+
+	checksByRegion := make(map[string][]*QuotaCheck, len(regions))
+	for r := range results {
+		if r.err != nil {
+			return nil, fmt.Errorf("Failed to check quotas for region '%s': %v", r.region, r.err)
+		}
+		checksByRegion[r.region] = r.checks
+	}
+	return checksByRegion, nil
 }
 
 // ListServiceQuotas list available quotas for service
@@ -0,0 +1,114 @@
+package aws_test
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mocks"
+	"github.com/openshift/moactl/pkg/simulate"
+)
+
+var _ = Describe("CheckQuotas", func() {
+	var (
+		client   aws.Client
+		mockCtrl *gomock.Controller
+
+		mockServiceQuotasAPI *mocks.MockServiceQuotasAPI
+	)
+
+	// Every quota of every service has the given value, except the network interfaces:
+	quotas := func(value float64, networkInterfaces float64) {
+		mockServiceQuotasAPI.EXPECT().ListServiceQuotasPages(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(input *servicequotas.ListServiceQuotasInput,
+				fn func(*servicequotas.ListServiceQuotasOutput, bool) bool) error {
+				page := &servicequotas.ListServiceQuotasOutput{}
+				for _, code := range []string{
+					"L-0263D0A3", "L-1216C47A", "L-F678F1CE", "L-A4707A72", "L-D18FCD1D",
+					"L-309BACF6", "L-B3A130E6", "L-FD252861", "L-53DA6B97", "L-E9E9831D",
+				} {
+					page.Quotas = append(page.Quotas, &servicequotas.ServiceQuota{
+						ServiceCode: input.ServiceCode,
+						QuotaCode:   awssdk.String(code),
+						Value:       awssdk.Float64(value),
+					})
+				}
+				page.Quotas = append(page.Quotas, &servicequotas.ServiceQuota{
+					ServiceCode: input.ServiceCode,
+					QuotaCode:   awssdk.String("L-DF5E4CA3"),
+					Value:       awssdk.Float64(networkInterfaces),
+				})
+				fn(page, true)
+				return nil
+			}).Times(4)
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		var apis *apiMocks
+		client, apis = newClient(mockCtrl, regionSession("us-east-1"))
+		mockServiceQuotasAPI = apis.ServiceQuotas
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("Lists the quotas of each service once", func() {
+		quotas(1000000, 1000000)
+
+		checks, err := client.CheckQuotas()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checks).To(HaveLen(11))
+		Expect(aws.InsufficientQuotas(checks)).To(BeEmpty())
+	})
+
+	It("Reports by how much the quotas are insufficient", func() {
+		quotas(1000000, 3000)
+
+		checks, err := client.CheckQuotas()
+
+		Expect(err).NotTo(HaveOccurred())
+		insufficient := aws.InsufficientQuotas(checks)
+		Expect(insufficient).To(HaveLen(1))
+		Expect(insufficient[0].Region).To(Equal("us-east-1"))
+		Expect(insufficient[0].QuotaCode).To(Equal("L-DF5E4CA3"))
+		Expect(insufficient[0].Missing).To(Equal(2000.0))
+	})
+
+	It("Fails validation with all the insufficient quotas", func() {
+		quotas(1, 1)
+
+		valid, err := client.ValidateQuota()
+
+		Expect(valid).To(BeFalse())
+		Expect(err).To(BeAssignableToTypeOf(&aws.QuotasError{}))
+		Expect(err.(*aws.QuotasError).Checks).To(HaveLen(11))
+	})
+})
+
+var _ = Describe("CheckQuotas in simulation mode", func() {
+	BeforeEach(func() {
+		simulate.Enable()
+	})
+
+	AfterEach(func() {
+		simulate.Disable()
+	})
+
+	It("Finds all the quotas in the simulated account", func() {
+		client, err := aws.NewClient().Logger(logrus.New()).Region("us-east-1").Build()
+		Expect(err).NotTo(HaveOccurred())
+
+		checks, err := client.CheckQuotas()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checks).To(HaveLen(11))
+		Expect(aws.InsufficientQuotas(checks)).To(BeEmpty())
+	})
+})
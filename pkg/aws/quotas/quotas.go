@@ -0,0 +1,99 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package quotas contains the AWS service quotas that clusters need. They are shared by the AWS
+// client, which checks them, and by the simulation mode, which lists them for the simulated
+// account.
+package quotas
+
+// Quota is an AWS service quota that clusters need, with the minimum value that they need.
+type Quota struct {
+	ServiceCode string
+	QuotaCode   string
+	QuotaName   string
+	Required    float64
+}
+
+// Required is the list of service quotas verified for cluster installs, to support 5 multi zone
+// clusters.
+var Required = []Quota{
+	{
+		ServiceCode: "ec2",
+		QuotaCode:   "L-0263D0A3",
+		QuotaName:   "Number of EIPs - VPC EIPs",
+		Required:    5,
+	},
+	{
+		ServiceCode: "ec2",
+		QuotaCode:   "L-1216C47A",
+		QuotaName:   "Running On-Demand Standard (A, C, D, H, I, M, R, T, Z) instances",
+		Required:    100,
+	},
+	{
+		ServiceCode: "vpc",
+		QuotaCode:   "L-F678F1CE",
+		QuotaName:   "VPCs per Region",
+		Required:    5,
+	},
+	{
+		ServiceCode: "vpc",
+		QuotaCode:   "L-A4707A72",
+		QuotaName:   "Internet gateways per Region",
+		Required:    5,
+	},
+	{
+		ServiceCode: "vpc",
+		QuotaCode:   "L-DF5E4CA3",
+		QuotaName:   "Network interfaces per Region",
+		Required:    5000,
+	},
+	{
+		ServiceCode: "ebs",
+		QuotaCode:   "L-D18FCD1D",
+		QuotaName:   "General Purpose SSD (gp2) volume storage",
+		Required:    300,
+	},
+	{
+		ServiceCode: "ebs",
+		QuotaCode:   "L-309BACF6",
+		QuotaName:   "Number of EBS snapshots",
+		Required:    300,
+	},
+	{
+		ServiceCode: "ebs",
+		QuotaCode:   "L-B3A130E6",
+		QuotaName:   "Provisioned IOPS",
+		Required:    300000,
+	},
+	{
+		ServiceCode: "ebs",
+		QuotaCode:   "L-FD252861",
+		QuotaName:   "Provisioned IOPS SSD (io1) volume storage",
+		Required:    300,
+	},
+	{
+		ServiceCode: "elasticloadbalancing",
+		QuotaCode:   "L-53DA6B97",
+		QuotaName:   "Application Load Balancers per Region",
+		Required:    50,
+	},
+	{
+		ServiceCode: "elasticloadbalancing",
+		QuotaCode:   "L-E9E9831D",
+		QuotaName:   "Classic Load Balancers per Region",
+		Required:    20,
+	},
+}
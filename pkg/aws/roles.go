@@ -0,0 +1,433 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/service/iam"
+)
+
+// InstallerPrincipal is the Red Hat principal that the installer role has to trust.
+const InstallerPrincipal = "arn:aws:iam::710019948333:role/RH-Managed-OpenShift-Installer"
+
+// RoleARNError contains all the problems found while validating a role ARN, so that they can be
+// reported to the user at once.
+type RoleARNError struct {
+	RoleARN  string
+	Problems []string
+}
+
+func (e *RoleARNError) Error() string {
+	return fmt.Sprintf("Role ARN '%s' isn't valid:\n  - %s", e.RoleARN, strings.Join(e.Problems, "\n  - "))
+}
+
+// ValidateRoleARN checks that the given role ARN is well formed, that it belongs to the partition
+// of the region of the client and to the account of the current user, and that the role exists
+// and trusts the given principal. If the ARN isn't valid the returned error is a *RoleARNError
+// listing all the problems found.
+func (c *awsClient) ValidateRoleARN(roleARN string, trustedPrincipal string) error {
+	problems := []string{}
+
+	parsed, err := arn.Parse(roleARN)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("it isn't a valid ARN: %v", err))
+		return &RoleARNError{RoleARN: roleARN, Problems: problems}
+	}
+	if parsed.Service != iam.ServiceName || !strings.HasPrefix(parsed.Resource, "role/") {
+		problems = append(problems, "it isn't the ARN of an IAM role")
+	}
+
+	region := c.GetRegion()
+	partition, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region)
+	if ok && parsed.Partition != partition.ID() {
+		problems = append(problems, fmt.Sprintf(
+			"partition '%s' doesn't match partition '%s' of region '%s'",
+			parsed.Partition, partition.ID(), region,
+		))
+	}
+
+	creator, err := c.GetCreator()
+	if err != nil {
+		return err
+	}
+	if parsed.AccountID != creator.AccountID {
+		problems = append(problems, fmt.Sprintf(
+			"account '%s' isn't the current AWS account '%s'",
+			parsed.AccountID, creator.AccountID,
+		))
+	}
+
+	// Only check the role itself when the ARN points to something that can exist:
+	if len(problems) > 0 {
+		return &RoleARNError{RoleARN: roleARN, Problems: problems}
+	}
+	roleName := parsed.Resource[strings.LastIndex(parsed.Resource, "/")+1:]
+	output, err := c.iamClient.GetRole(&iam.GetRoleInput{
+		RoleName: aws.String(roleName),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == iam.ErrCodeNoSuchEntityException {
+			problems = append(problems, fmt.Sprintf("role '%s' doesn't exist", roleName))
+			return &RoleARNError{RoleARN: roleARN, Problems: problems}
+		}
+		return err
+	}
+
+	trusted, err := trustsPrincipal(aws.StringValue(output.Role.AssumeRolePolicyDocument), trustedPrincipal)
+	if err != nil {
+		return err
+	}
+	if !trusted {
+		problems = append(problems, fmt.Sprintf("role '%s' doesn't trust principal '%s'", roleName, trustedPrincipal))
+		return &RoleARNError{RoleARN: roleARN, Problems: problems}
+	}
+
+	return nil
+}
+
+// trustsPrincipal checks if the given trust policy, URL encoded as returned by the IAM API, allows
+// the given AWS principal to assume the role.
+func trustsPrincipal(document string, principal string) (bool, error) {
+	decoded, err := url.QueryUnescape(document)
+	if err != nil {
+		return false, err
+	}
+	var policy struct {
+		Statement []struct {
+			Effect    string
+			Action    interface{}
+			Principal struct {
+				AWS interface{}
+			}
+		}
+	}
+	err = json.Unmarshal([]byte(decoded), &policy)
+	if err != nil {
+		return false, fmt.Errorf("Failed to parse trust policy: %v", err)
+	}
+	for _, statement := range policy.Statement {
+		if statement.Effect != "Allow" || !contains(statement.Action, "sts:AssumeRole") {
+			continue
+		}
+		if contains(statement.Principal.AWS, principal) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// contains checks if a policy element, which can be either a string or a list of strings,
+// contains the given value.
+func contains(element interface{}, value string) bool {
+	switch typed := element.(type) {
+	case string:
+		return typed == value
+	case []interface{}:
+		for _, item := range typed {
+			if item == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Name of the service linked role that Elastic Load Balancing needs to create load balancers, and
+// command that creates it. The role only exists in accounts where a load balancer was created
+// before, and installing clusters fails without it.
+const (
+	ELBServiceLinkedRoleName    = "AWSServiceRoleForElasticLoadBalancing"
+	ELBServiceLinkedRoleCommand = "aws iam create-service-linked-role --aws-service-name " + elbServiceName
+	elbServiceName              = "elasticloadbalancing.amazonaws.com"
+)
+
+// HasELBServiceLinkedRole checks if the service linked role of Elastic Load Balancing exists in the
+// account.
+func (c *awsClient) HasELBServiceLinkedRole() (bool, error) {
+	_, err := c.iamClient.GetRole(&iam.GetRoleInput{
+		RoleName: aws.String(ELBServiceLinkedRoleName),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == iam.ErrCodeNoSuchEntityException {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateELBServiceLinkedRole creates the service linked role of Elastic Load Balancing.
+func (c *awsClient) CreateELBServiceLinkedRole() error {
+	_, err := c.iamClient.CreateServiceLinkedRole(&iam.CreateServiceLinkedRoleInput{
+		AWSServiceName: aws.String(elbServiceName),
+	})
+	return err
+}
+
+// maxPolicyVersions is the number of versions of a managed policy that IAM keeps.
+const maxPolicyVersions = 5
+
+// roleWithPolicy describes a role and the managed policy that contains its permissions.
+type roleWithPolicy struct {
+	RoleName    string
+	TrustPolicy string
+	PolicyName  string
+	Policy      string
+	Tags        map[string]string
+	Settings    IAMSettings
+}
+
+// trustPolicyDocument returns a trust policy that allows the given principal to assume the role
+// with the given action, optionally with a condition.
+func trustPolicyDocument(principal map[string]interface{}, action string,
+	condition map[string]interface{}) (string, error) {
+	statement := map[string]interface{}{
+		"Effect":    "Allow",
+		"Principal": principal,
+		"Action":    action,
+	}
+	if condition != nil {
+		statement["Condition"] = condition
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		"Version":   "2012-10-17",
+		"Statement": []interface{}{statement},
+	})
+	if err != nil {
+		return "", fmt.Errorf("Failed to marshal trust policy: %v", err)
+	}
+	return string(data), nil
+}
+
+// createRoleWithPolicy creates the given role and its managed policy, and attaches the policy to
+// the role. Roles and policies that already exist are updated, so running it again brings them up
+// to date. The policy has the same path as the role. IAM doesn't allow changing the path of a role,
+// so it fails if an existing role has a path different to the one of the settings. It returns the
+// ARN of the role.
+func (c *awsClient) createRoleWithPolicy(role *roleWithPolicy) (string, error) {
+	keys := make([]string, 0, len(role.Tags))
+	for key := range role.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	roleTags := make([]*iam.Tag, 0, len(keys))
+	for _, key := range keys {
+		roleTags = append(roleTags, &iam.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(role.Tags[key]),
+		})
+	}
+
+	input := &iam.CreateRoleInput{
+		RoleName:                 aws.String(role.RoleName),
+		AssumeRolePolicyDocument: aws.String(role.TrustPolicy),
+		Tags:                     roleTags,
+	}
+	if role.Settings.Path != "" {
+		input.Path = aws.String(role.Settings.Path)
+	}
+	if role.Settings.PermissionsBoundary != "" {
+		input.PermissionsBoundary = aws.String(role.Settings.PermissionsBoundary)
+	}
+	var current *iam.Role
+	created, err := c.iamClient.CreateRole(input)
+	switch {
+	case err == nil:
+		current = created.Role
+	case isEntityAlreadyExists(err):
+		c.logger.Debugf("Role '%s' already exists, updating it", role.RoleName)
+		current, err = c.updateRole(role, roleTags)
+		if err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("Failed to create role '%s': %v", role.RoleName, err)
+	}
+	roleARN := aws.StringValue(current.Arn)
+	rolePath := aws.StringValue(current.Path)
+	if rolePath == "" {
+		rolePath = DefaultIAMPath
+	}
+
+	// Policies are in the same partition and account as the role:
+	parsed, err := arn.Parse(roleARN)
+	if err != nil {
+		return "", err
+	}
+	policyARN := fmt.Sprintf("arn:%s:iam::%s:policy%s%s", parsed.Partition, parsed.AccountID, rolePath,
+		role.PolicyName)
+	_, err = c.iamClient.CreatePolicy(&iam.CreatePolicyInput{
+		PolicyName:     aws.String(role.PolicyName),
+		PolicyDocument: aws.String(role.Policy),
+		Path:           aws.String(rolePath),
+	})
+	if err != nil {
+		if !isEntityAlreadyExists(err) {
+			return "", fmt.Errorf("Failed to create policy '%s': %v", role.PolicyName, err)
+		}
+		err = c.updatePolicy(policyARN, role.Policy)
+		if err != nil {
+			return "", fmt.Errorf("Failed to update policy '%s': %v", role.PolicyName, err)
+		}
+	}
+
+	_, err = c.iamClient.AttachRolePolicy(&iam.AttachRolePolicyInput{
+		RoleName:  aws.String(role.RoleName),
+		PolicyArn: aws.String(policyARN),
+	})
+	if err != nil {
+		return "", fmt.Errorf("Failed to attach policy '%s' to role '%s': %v", role.PolicyName, role.RoleName, err)
+	}
+	return roleARN, nil
+}
+
+// updateRole updates the trust policy, the tags and the permissions boundary of the given existing
+// role, after checking that it has the path of the settings. It returns the role.
+func (c *awsClient) updateRole(role *roleWithPolicy, roleTags []*iam.Tag) (*iam.Role, error) {
+	existing, err := c.iamClient.GetRole(&iam.GetRoleInput{
+		RoleName: aws.String(role.RoleName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get role '%s': %v", role.RoleName, err)
+	}
+	path := aws.StringValue(existing.Role.Path)
+	if role.Settings.Path != "" && path != role.Settings.Path {
+		return nil, fmt.Errorf("Role '%s' already exists with path '%s' instead of '%s'. The path of a "+
+			"role can't be changed, delete the role to create it again with the new path",
+			role.RoleName, path, role.Settings.Path)
+	}
+
+	_, err = c.iamClient.UpdateAssumeRolePolicy(&iam.UpdateAssumeRolePolicyInput{
+		RoleName:       aws.String(role.RoleName),
+		PolicyDocument: aws.String(role.TrustPolicy),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update trust policy of role '%s': %v", role.RoleName, err)
+	}
+	if len(roleTags) > 0 {
+		_, err = c.iamClient.TagRole(&iam.TagRoleInput{
+			RoleName: aws.String(role.RoleName),
+			Tags:     roleTags,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Failed to tag role '%s': %v", role.RoleName, err)
+		}
+	}
+
+	var boundary string
+	if existing.Role.PermissionsBoundary != nil {
+		boundary = aws.StringValue(existing.Role.PermissionsBoundary.PermissionsBoundaryArn)
+	}
+	if role.Settings.PermissionsBoundary != "" && boundary != role.Settings.PermissionsBoundary {
+		c.logger.Debugf("Changing permissions boundary of role '%s' from '%s' to '%s'", role.RoleName,
+			boundary, role.Settings.PermissionsBoundary)
+		_, err = c.iamClient.PutRolePermissionsBoundary(&iam.PutRolePermissionsBoundaryInput{
+			RoleName:            aws.String(role.RoleName),
+			PermissionsBoundary: aws.String(role.Settings.PermissionsBoundary),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Failed to set permissions boundary of role '%s': %v", role.RoleName, err)
+		}
+	}
+	return existing.Role, nil
+}
+
+// updatePolicy makes the given document the default version of the policy, unless it already is.
+// When the policy has the maximum number of versions the oldest one that isn't the default is
+// deleted first.
+func (c *awsClient) updatePolicy(policyARN string, document string) error {
+	versions, err := c.iamClient.ListPolicyVersions(&iam.ListPolicyVersionsInput{
+		PolicyArn: aws.String(policyARN),
+	})
+	if err != nil {
+		return err
+	}
+	var oldest *iam.PolicyVersion
+	for _, version := range versions.Versions {
+		if aws.BoolValue(version.IsDefaultVersion) {
+			current, err := c.iamClient.GetPolicyVersion(&iam.GetPolicyVersionInput{
+				PolicyArn: aws.String(policyARN),
+				VersionId: version.VersionId,
+			})
+			if err != nil {
+				return err
+			}
+			equal, err := equalPolicyDocuments(aws.StringValue(current.PolicyVersion.Document), document)
+			if err != nil {
+				return err
+			}
+			if equal {
+				return nil
+			}
+			continue
+		}
+		if oldest == nil || aws.TimeValue(version.CreateDate).Before(aws.TimeValue(oldest.CreateDate)) {
+			oldest = version
+		}
+	}
+	if len(versions.Versions) >= maxPolicyVersions && oldest != nil {
+		_, err = c.iamClient.DeletePolicyVersion(&iam.DeletePolicyVersionInput{
+			PolicyArn: aws.String(policyARN),
+			VersionId: oldest.VersionId,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	_, err = c.iamClient.CreatePolicyVersion(&iam.CreatePolicyVersionInput{
+		PolicyArn:      aws.String(policyARN),
+		PolicyDocument: aws.String(document),
+		SetAsDefault:   aws.Bool(true),
+	})
+	return err
+}
+
+// equalPolicyDocuments checks if the given policy document, URL encoded as returned by the IAM API,
+// has the same content as the other given document.
+func equalPolicyDocuments(encoded string, document string) (bool, error) {
+	decoded, err := url.QueryUnescape(encoded)
+	if err != nil {
+		return false, err
+	}
+	var current, expected interface{}
+	err = json.Unmarshal([]byte(decoded), &current)
+	if err != nil {
+		return false, fmt.Errorf("Failed to parse policy: %v", err)
+	}
+	err = json.Unmarshal([]byte(document), &expected)
+	if err != nil {
+		return false, fmt.Errorf("Failed to parse policy: %v", err)
+	}
+	return reflect.DeepEqual(current, expected), nil
+}
+
+// isEntityAlreadyExists checks if the given error is the one that IAM returns when the entity that
+// is being created already exists.
+func isEntityAlreadyExists(err error) bool {
+	aerr, ok := err.(awserr.Error)
+	return ok && aerr.Code() == iam.ErrCodeEntityAlreadyExistsException
+}
@@ -0,0 +1,123 @@
+package aws_test
+
+import (
+	"net/url"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mocks"
+)
+
+var _ = Describe("ValidateRoleARN", func() {
+	const (
+		accountID = "123456789012"
+		roleARN   = "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role"
+	)
+
+	var (
+		client   aws.Client
+		mockCtrl *gomock.Controller
+
+		mockIamAPI *mocks.MockIAMAPI
+		mockStsAPI *mocks.MockSTSAPI
+	)
+
+	trustPolicy := func(principal string) *string {
+		return awssdk.String(url.QueryEscape(`{
+			"Version": "2012-10-17",
+			"Statement": [{
+				"Effect": "Allow",
+				"Action": "sts:AssumeRole",
+				"Principal": {"AWS": ["` + principal + `"]}
+			}]
+		}`))
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		var apis *apiMocks
+		client, apis = newClient(mockCtrl, regionSession("us-east-1"))
+		mockIamAPI = apis.IAM
+		mockStsAPI = apis.STS
+		mockStsAPI.EXPECT().GetCallerIdentity(gomock.Any()).Return(&sts.GetCallerIdentityOutput{
+			Account: awssdk.String(accountID),
+			Arn:     awssdk.String("arn:aws:iam::123456789012:user/test"),
+		}, nil).AnyTimes()
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("Rejects malformed ARNs without calling IAM", func() {
+		err := client.ValidateRoleARN("not-an-arn", aws.InstallerPrincipal)
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("it isn't a valid ARN"))
+	})
+
+	It("Reports all the problems of the ARN together", func() {
+		err := client.ValidateRoleARN("arn:aws-cn:iam::999999999999:user/test", aws.InstallerPrincipal)
+
+		Expect(err).To(BeAssignableToTypeOf(&aws.RoleARNError{}))
+		Expect(err.(*aws.RoleARNError).Problems).To(HaveLen(3))
+	})
+
+	It("Reports roles that don't exist", func() {
+		mockIamAPI.EXPECT().GetRole(gomock.Any()).Return(
+			nil, awserr.New(iam.ErrCodeNoSuchEntityException, "not found", nil))
+
+		err := client.ValidateRoleARN(roleARN, aws.InstallerPrincipal)
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("doesn't exist"))
+	})
+
+	It("Reports roles that don't trust the principal", func() {
+		mockIamAPI.EXPECT().GetRole(gomock.Any()).Return(&iam.GetRoleOutput{
+			Role: &iam.Role{AssumeRolePolicyDocument: trustPolicy("arn:aws:iam::111111111111:root")},
+		}, nil)
+
+		err := client.ValidateRoleARN(roleARN, aws.InstallerPrincipal)
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("doesn't trust principal"))
+	})
+
+	It("Accepts roles that trust the principal", func() {
+		mockIamAPI.EXPECT().GetRole(gomock.Any()).Return(&iam.GetRoleOutput{
+			Role: &iam.Role{AssumeRolePolicyDocument: trustPolicy(aws.InstallerPrincipal)},
+		}, nil)
+
+		err := client.ValidateRoleARN(roleARN, aws.InstallerPrincipal)
+
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Reports that the ELB service linked role doesn't exist", func() {
+		mockIamAPI.EXPECT().GetRole(gomock.Any()).Return(
+			nil, awserr.New(iam.ErrCodeNoSuchEntityException, "not found", nil))
+
+		exists, err := client.HasELBServiceLinkedRole()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exists).To(BeFalse())
+	})
+
+	It("Creates the ELB service linked role", func() {
+		mockIamAPI.EXPECT().CreateServiceLinkedRole(&iam.CreateServiceLinkedRoleInput{
+			AWSServiceName: awssdk.String("elasticloadbalancing.amazonaws.com"),
+		}).Return(&iam.CreateServiceLinkedRoleOutput{}, nil)
+
+		err := client.CreateELBServiceLinkedRole()
+
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
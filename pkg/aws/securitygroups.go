@@ -0,0 +1,210 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the checks of the additional security groups that are attached to the load
+// balancers of the API and of the default ingress of clusters installed into an existing VPC. The
+// problems with those groups are only visible once the cluster is installed, as connections that
+// are silently dropped, so they are checked before creating the cluster.
+
+package aws
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+
+	"github.com/openshift/moactl/pkg/network"
+)
+
+// Quotas of the security groups, and their default values that are used when the quotas can't be
+// read:
+const (
+	securityGroupRulesQuotaCode      = "L-0EA8095F"
+	securityGroupRulesDefault        = 60
+	interfaceSecurityGroupsQuotaCode = "L-2AFB9258"
+	interfaceSecurityGroupsDefault   = 5
+)
+
+// installerSecurityGroups is the number of security groups that the installer attaches to the load
+// balancers itself, which count towards the limit of groups per network interface.
+const installerSecurityGroups = 1
+
+// Ports of the API and of the default ingress:
+const (
+	apiPort     = 6443
+	ingressPort = 443
+)
+
+// SecurityGroupsError is returned by ValidateSecurityGroups when the security groups can't be used.
+type SecurityGroupsError struct {
+	Problems []string
+}
+
+func (e *SecurityGroupsError) Error() string {
+	return fmt.Sprintf("Security groups can't be used:\n  - %s", strings.Join(e.Problems, "\n  - "))
+}
+
+// ValidateSecurityGroups checks that the given additional security groups exist in the VPC of the
+// given subnets, that together with the groups of the installer they fit in the limit of groups per
+// network interface, and that they don't have more rules than allowed in each direction. Inbound
+// rules for addresses inside the blocks of the cluster network given in the map, indexed by name,
+// are rejected, as those addresses are never seen outside of the nodes, and the groups of a private
+// cluster must not open the API or the ingress to the internet. If the groups can't be used the
+// returned error is a *SecurityGroupsError listing all the problems found.
+func (c *awsClient) ValidateSecurityGroups(groupIDs []string, subnetIDs []string, private bool,
+	clusterCIDRs map[string]*net.IPNet) error {
+	subnets, err := c.FindSubnets(subnetIDs)
+	if err != nil {
+		return fmt.Errorf("Failed to describe subnets: %v", err)
+	}
+	if len(subnets) == 0 {
+		return &SecurityGroupsError{Problems: []string{
+			fmt.Sprintf("none of the subnets exist in region '%s'", c.GetRegion()),
+		}}
+	}
+	vpcID := aws.StringValue(subnets[0].VpcId)
+
+	output, err := c.ec2Client.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+		GroupIds: aws.StringSlice(groupIDs),
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to describe security groups: %v", err)
+	}
+	groups := map[string]*ec2.SecurityGroup{}
+	for _, group := range output.SecurityGroups {
+		groups[aws.StringValue(group.GroupId)] = group
+	}
+
+	problems := []string{}
+	maxGroups := c.getSecurityGroupQuota(interfaceSecurityGroupsQuotaCode, interfaceSecurityGroupsDefault)
+	if len(groupIDs)+installerSecurityGroups > maxGroups {
+		problems = append(problems, fmt.Sprintf("at most %d additional security groups can be given, "+
+			"as network interfaces can have %d security groups and the installer uses %d",
+			maxGroups-installerSecurityGroups, maxGroups, installerSecurityGroups))
+	}
+	maxRules := c.getSecurityGroupQuota(securityGroupRulesQuotaCode, securityGroupRulesDefault)
+
+	names := make([]string, 0, len(clusterCIDRs))
+	for name := range clusterCIDRs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, groupID := range groupIDs {
+		group, ok := groups[groupID]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("security group '%s' doesn't exist in region '%s'",
+				groupID, c.GetRegion()))
+			continue
+		}
+		if groupVPC := aws.StringValue(group.VpcId); groupVPC != vpcID {
+			problems = append(problems, fmt.Sprintf("security group '%s' is in VPC '%s', but the subnets "+
+				"are in VPC '%s'", groupID, groupVPC, vpcID))
+		}
+		if count := countSecurityGroupRules(group.IpPermissions); count > maxRules {
+			problems = append(problems, fmt.Sprintf("security group '%s' has %d inbound rules, but at most "+
+				"%d are allowed", groupID, count, maxRules))
+		}
+		if count := countSecurityGroupRules(group.IpPermissionsEgress); count > maxRules {
+			problems = append(problems, fmt.Sprintf("security group '%s' has %d outbound rules, but at most "+
+				"%d are allowed", groupID, count, maxRules))
+		}
+		for _, permission := range group.IpPermissions {
+			for _, cidr := range permissionCIDRs(permission) {
+				_, block, err := net.ParseCIDR(cidr)
+				if err != nil {
+					continue
+				}
+				for _, name := range names {
+					if clusterCIDRs[name] != nil && network.Within(block, clusterCIDRs[name]) {
+						problems = append(problems, fmt.Sprintf("security group '%s' allows inbound "+
+							"traffic from '%s', which is inside the %s block '%s' of the cluster and "+
+							"will never match", groupID, cidr, name, clusterCIDRs[name]))
+					}
+				}
+				if private && isInternetCIDR(block) &&
+					(permissionAllowsPort(permission, apiPort) || permissionAllowsPort(permission, ingressPort)) {
+					problems = append(problems, fmt.Sprintf("security group '%s' allows inbound traffic "+
+						"from '%s' to the API or the ingress, but the cluster is private", groupID, cidr))
+				}
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &SecurityGroupsError{Problems: problems}
+	}
+	return nil
+}
+
+// getSecurityGroupQuota returns the value of the given VPC quota, or the given default value if it
+// can't be read, for example because the user isn't allowed to read the quotas.
+func (c *awsClient) getSecurityGroupQuota(quotaCode string, defaultValue int) int {
+	output, err := c.servicequotasClient.GetServiceQuota(&servicequotas.GetServiceQuotaInput{
+		ServiceCode: aws.String("vpc"),
+		QuotaCode:   aws.String(quotaCode),
+	})
+	if err != nil || output.Quota == nil {
+		c.logger.Debug(fmt.Sprintf("Failed to get quota '%s', using the default value %d: %v",
+			quotaCode, defaultValue, err))
+		return defaultValue
+	}
+	return int(aws.Float64Value(output.Quota.Value))
+}
+
+// countSecurityGroupRules returns the number of rules of the given permissions the way AWS counts
+// them for the quota: each block of addresses, prefix list and referenced group is a rule.
+func countSecurityGroupRules(permissions []*ec2.IpPermission) int {
+	count := 0
+	for _, permission := range permissions {
+		count += len(permission.IpRanges) + len(permission.Ipv6Ranges) +
+			len(permission.PrefixListIds) + len(permission.UserIdGroupPairs)
+	}
+	return count
+}
+
+func permissionCIDRs(permission *ec2.IpPermission) []string {
+	cidrs := []string{}
+	for _, ipRange := range permission.IpRanges {
+		cidrs = append(cidrs, aws.StringValue(ipRange.CidrIp))
+	}
+	for _, ipRange := range permission.Ipv6Ranges {
+		cidrs = append(cidrs, aws.StringValue(ipRange.CidrIpv6))
+	}
+	return cidrs
+}
+
+// permissionAllowsPort checks if the given permission allows TCP traffic to the given port. The '-1'
+// protocol allows all the protocols and all the ports.
+func permissionAllowsPort(permission *ec2.IpPermission, port int64) bool {
+	switch aws.StringValue(permission.IpProtocol) {
+	case "-1":
+		return true
+	case "tcp", "6":
+		return aws.Int64Value(permission.FromPort) <= port && port <= aws.Int64Value(permission.ToPort)
+	}
+	return false
+}
+
+func isInternetCIDR(block *net.IPNet) bool {
+	ones, _ := block.Mask.Size()
+	return ones == 0
+}
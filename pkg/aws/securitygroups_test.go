@@ -0,0 +1,129 @@
+package aws_test
+
+import (
+	"net"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mocks"
+)
+
+var _ = Describe("ValidateSecurityGroups", func() {
+	var (
+		client   aws.Client
+		mockCtrl *gomock.Controller
+
+		mockEC2API           *mocks.MockEC2API
+		mockServiceQuotasAPI *mocks.MockServiceQuotasAPI
+	)
+
+	_, podCIDR, _ := net.ParseCIDR("10.128.0.0/14")
+	clusterCIDRs := map[string]*net.IPNet{"pod": podCIDR}
+
+	ingress := func(cidr string, port int64) *ec2.IpPermission {
+		return &ec2.IpPermission{
+			IpProtocol: awssdk.String("tcp"),
+			FromPort:   awssdk.Int64(port),
+			ToPort:     awssdk.Int64(port),
+			IpRanges:   []*ec2.IpRange{{CidrIp: awssdk.String(cidr)}},
+		}
+	}
+
+	// The default values of the quotas are used when they can't be read:
+	defaultQuotas := func() {
+		mockServiceQuotasAPI.EXPECT().GetServiceQuota(gomock.Any()).Return(nil, awssdk.ErrMissingEndpoint).
+			AnyTimes()
+	}
+
+	groups := func(groups ...*ec2.SecurityGroup) {
+		mockEC2API.EXPECT().DescribeSubnets(gomock.Any()).Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []*ec2.Subnet{{SubnetId: awssdk.String("subnet-1"), VpcId: awssdk.String("vpc-1")}},
+		}, nil)
+		mockEC2API.EXPECT().DescribeSecurityGroups(gomock.Any()).Return(&ec2.DescribeSecurityGroupsOutput{
+			SecurityGroups: groups,
+		}, nil)
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		var apis *apiMocks
+		client, apis = newClient(mockCtrl, regionSession("us-east-1"))
+		mockEC2API = apis.EC2
+		mockServiceQuotasAPI = apis.ServiceQuotas
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("Accepts groups in the VPC of the subnets", func() {
+		defaultQuotas()
+		groups(&ec2.SecurityGroup{
+			GroupId:       awssdk.String("sg-1"),
+			VpcId:         awssdk.String("vpc-1"),
+			IpPermissions: []*ec2.IpPermission{ingress("10.0.0.0/16", 6443)},
+		})
+
+		err := client.ValidateSecurityGroups([]string{"sg-1"}, []string{"subnet-1"}, false, clusterCIDRs)
+
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Reports all the problems of the groups together", func() {
+		defaultQuotas()
+		groups(&ec2.SecurityGroup{
+			GroupId: awssdk.String("sg-1"),
+			VpcId:   awssdk.String("vpc-2"),
+			IpPermissions: []*ec2.IpPermission{
+				ingress("10.128.0.0/16", 6443),
+				ingress("0.0.0.0/0", 443),
+			},
+		})
+
+		err := client.ValidateSecurityGroups([]string{"sg-1", "sg-2"}, []string{"subnet-1"}, true, clusterCIDRs)
+
+		Expect(err).To(BeAssignableToTypeOf(&aws.SecurityGroupsError{}))
+		problems := err.(*aws.SecurityGroupsError).Problems
+		Expect(problems).To(HaveLen(4))
+		Expect(problems[0]).To(ContainSubstring("is in VPC 'vpc-2'"))
+		Expect(problems[1]).To(ContainSubstring("inside the pod block"))
+		Expect(problems[2]).To(ContainSubstring("but the cluster is private"))
+		Expect(problems[3]).To(ContainSubstring("'sg-2' doesn't exist"))
+	})
+
+	It("Rejects groups with more rules than allowed by the quota", func() {
+		defaultQuotas()
+		permission := &ec2.IpPermission{IpProtocol: awssdk.String("-1")}
+		for i := 0; i < 61; i++ {
+			permission.UserIdGroupPairs = append(permission.UserIdGroupPairs, &ec2.UserIdGroupPair{})
+		}
+		groups(&ec2.SecurityGroup{
+			GroupId:             awssdk.String("sg-1"),
+			VpcId:               awssdk.String("vpc-1"),
+			IpPermissionsEgress: []*ec2.IpPermission{permission},
+		})
+
+		err := client.ValidateSecurityGroups([]string{"sg-1"}, []string{"subnet-1"}, false, clusterCIDRs)
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("has 61 outbound rules, but at most 60 are allowed"))
+	})
+
+	It("Uses the quota of groups per network interface", func() {
+		mockServiceQuotasAPI.EXPECT().GetServiceQuota(gomock.Any()).Return(&servicequotas.GetServiceQuotaOutput{
+			Quota: &servicequotas.ServiceQuota{Value: awssdk.Float64(2)},
+		}, nil).AnyTimes()
+		groups()
+
+		err := client.ValidateSecurityGroups([]string{"sg-1", "sg-2"}, []string{"subnet-1"}, false, nil)
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("at most 1 additional security groups"))
+	})
+})
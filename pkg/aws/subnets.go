@@ -0,0 +1,142 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that find the region of existing subnets, so that users that
+// install clusters in existing VPCs don't need to give the region as well.
+
+package aws
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/sirupsen/logrus"
+)
+
+// FindSubnets returns the subnets of the region of the client that have any of the given
+// identifiers. Unlike describing the subnets by identifier, it isn't an error if some of them
+// don't exist in the region.
+func (c *awsClient) FindSubnets(subnetIDs []string) ([]*ec2.Subnet, error) {
+	output, err := c.ec2Client.DescribeSubnets(&ec2.DescribeSubnetsInput{
+		Filters: []*ec2.Filter{{
+			Name:   aws.String("subnet-id"),
+			Values: aws.StringSlice(subnetIDs),
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.Subnets, nil
+}
+
+// GetSubnetsRegion finds which of the given regions contains the given subnets. It fails if some
+// of the subnets don't exist in any of the regions, or if they are in more than one region, as a
+// cluster can only use subnets of its own region. The regions are queried in parallel, like
+// the availability zones.
+func GetSubnetsRegion(logger *logrus.Logger, regions []string, subnetIDs []string) (string, error) {
+	type result struct {
+		region  string
+		subnets []*ec2.Subnet
+		err     error
+	}
+
+	jobs := make(chan string)
+	results := make(chan result, len(regions))
+
+	var wg sync.WaitGroup
+	workers := maxZoneWorkers
+	if len(regions) < workers {
+		workers = len(regions)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for region := range jobs {
+				client, err := NewClient().
+					Logger(logger).
+					Region(region).
+					Build()
+				if err != nil {
+					results <- result{region: region, err: err}
+					continue
+				}
+				subnets, err := client.FindSubnets(subnetIDs)
+				results <- result{region: region, subnets: subnets, err: err}
+			}
+		}()
+	}
+
+	for _, region := range regions {
+		jobs <- region
+	}
+	close(jobs)
+	wg.Wait()
+	close(results)
+
+	// Regions that aren't enabled in the account fail, so errors only matter when the subnets
+	// aren't found anywhere else:
+	regionsBySubnet := map[string]string{}
+	subnetsByRegion := map[string][]string{}
+	failures := []string{}
+	for r := range results {
+		if r.err != nil {
+			logger.Debug(fmt.Sprintf("Failed to find subnets in region '%s': %v", r.region, r.err))
+			failures = append(failures, r.region)
+			continue
+		}
+		for _, subnet := range r.subnets {
+			subnetID := aws.StringValue(subnet.SubnetId)
+			regionsBySubnet[subnetID] = r.region
+			subnetsByRegion[r.region] = append(subnetsByRegion[r.region], subnetID)
+		}
+	}
+
+	missing := []string{}
+	for _, subnetID := range subnetIDs {
+		if _, ok := regionsBySubnet[subnetID]; !ok {
+			missing = append(missing, subnetID)
+		}
+	}
+	if len(missing) > 0 {
+		message := fmt.Sprintf("Subnets '%s' don't exist in any of the supported regions",
+			strings.Join(missing, "', '"))
+		if len(failures) > 0 {
+			sort.Strings(failures)
+			message += fmt.Sprintf(", but regions '%s' couldn't be checked", strings.Join(failures, "', '"))
+		}
+		return "", fmt.Errorf("%s", message)
+	}
+
+	if len(subnetsByRegion) > 1 {
+		found := []string{}
+		for region, subnets := range subnetsByRegion {
+			sort.Strings(subnets)
+			found = append(found, fmt.Sprintf("'%s' in region '%s'", strings.Join(subnets, "', '"), region))
+		}
+		sort.Strings(found)
+		return "", fmt.Errorf("Subnets are in different regions, but all of them must be in the region of "+
+			"the cluster: %s", strings.Join(found, ", "))
+	}
+	for region := range subnetsByRegion {
+		return region, nil
+	}
+	return "", fmt.Errorf("No subnets given")
+}
@@ -0,0 +1,250 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that check the tags that users want to add to the resources of
+// a cluster against the syntax rules of AWS and the tag policies of the organization of the
+// account, as resources whose tags don't comply with an enforced tag policy can't be created, and
+// that would make the installation fail after it has already created other resources.
+
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/organizations"
+)
+
+// MaxTags is the maximum number of tags that users can add. AWS allows 50 tags per resource, and
+// the installer and OCM add their own tags to the resources of the cluster.
+const MaxTags = 40
+
+// tagRE is the regular expression of the characters that AWS allows in the keys and values of tags.
+var tagRE = regexp.MustCompile(`^[\p{L}\p{Z}\p{N}_.:/=+\-@]*$`)
+
+// ParseTags parses tags given as 'key=value' or 'key:value' pairs and checks that they follow the
+// AWS rules for the keys and values of tags. Keys end at the first equals sign or colon, so they
+// can't contain them, and that also rules out the 'aws:' prefix that is reserved for AWS.
+func ParseTags(values []string) (map[string]string, error) {
+	if len(values) > MaxTags {
+		return nil, fmt.Errorf("Too many tags: at most %d tags can be given", MaxTags)
+	}
+	result := map[string]string{}
+	for _, value := range values {
+		separator := strings.IndexAny(value, "=:")
+		if separator < 0 {
+			return nil, fmt.Errorf("Tag '%s' isn't valid: it must be a key and a value separated by an "+
+				"equals sign or a colon, like 'CostCenter=1234'", value)
+		}
+		key := strings.TrimSpace(value[:separator])
+		tagValue := strings.TrimSpace(value[separator+1:])
+		switch {
+		case key == "" || len(key) > 128:
+			return nil, fmt.Errorf("Key of tag '%s' isn't valid: it must be between 1 and 128 characters long",
+				value)
+		case len(tagValue) > 256:
+			return nil, fmt.Errorf("Value of tag '%s' isn't valid: it must be at most 256 characters long", key)
+		case !tagRE.MatchString(key):
+			return nil, fmt.Errorf("Key of tag '%s' isn't valid: it can only contain letters, digits, "+
+				"spaces and the characters _ . : / = + - @", key)
+		case !tagRE.MatchString(tagValue):
+			return nil, fmt.Errorf("Value of tag '%s' isn't valid: it can only contain letters, digits, "+
+				"spaces and the characters _ . : / = + - @", key)
+		case strings.HasPrefix(key, clusterTagPrefix) || strings.HasPrefix(key, "rosa_"):
+			return nil, fmt.Errorf("Key of tag '%s' isn't valid: it is reserved for the tags that are added "+
+				"to all the clusters", key)
+		}
+		if _, ok := result[key]; ok {
+			return nil, fmt.Errorf("Tag '%s' is given more than once", key)
+		}
+		result[key] = tagValue
+	}
+	return result, nil
+}
+
+// TagPolicyError contains all the tags that don't comply with the tag policies of the organization,
+// so that they can be reported to the user at once.
+type TagPolicyError struct {
+	Problems []string
+}
+
+func (e *TagPolicyError) Error() string {
+	return fmt.Sprintf("Tags don't comply with the tag policies of the AWS organization:\n  - %s",
+		strings.Join(e.Problems, "\n  - "))
+}
+
+// tagPolicy is the part of the effective tag policy of an account that describes one tag.
+type tagPolicy struct {
+	Key         string
+	Values      []string
+	EnforcedFor []string
+}
+
+// ValidateTags checks the given tags against the effective tag policy of the account. Tags with a
+// value that isn't allowed, or with a key that only differs in capitalization from the key of the
+// policy, are errors when the policy is enforced for EC2 resources and warnings otherwise, and the
+// returned error is a *TagPolicyError listing them. Keys of the policy that aren't given are
+// warnings, as AWS doesn't require them. Accounts that don't belong to an organization, or that
+// aren't allowed to read the policy, can use any tags.
+func (c *awsClient) ValidateTags(tags map[string]string) (warnings []string, err error) {
+	policies, err := c.getTagPolicies()
+	if err != nil || len(policies) == 0 {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(policies))
+	for key := range policies {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	problems := []string{}
+	for _, name := range keys {
+		policy := policies[name]
+		enforced := policy.enforcedForEC2()
+		report := func(format string, args ...interface{}) {
+			message := fmt.Sprintf(format, args...)
+			if enforced {
+				problems = append(problems, message)
+			} else {
+				warnings = append(warnings, message)
+			}
+		}
+
+		found := false
+		for key, value := range tags {
+			if !strings.EqualFold(key, policy.Key) {
+				continue
+			}
+			found = true
+			if key != policy.Key {
+				report("Tag '%s' must be written as '%s'", key, policy.Key)
+			}
+			if !policy.allows(value) {
+				report("Value '%s' of tag '%s' isn't allowed, it must be one of '%s'",
+					value, key, strings.Join(policy.Values, "', '"))
+			}
+		}
+		if !found {
+			warnings = append(warnings, fmt.Sprintf(
+				"The tag policy of the AWS organization describes tag '%s', but it isn't given", policy.Key))
+		}
+	}
+	if len(problems) > 0 {
+		return warnings, &TagPolicyError{Problems: problems}
+	}
+	return warnings, nil
+}
+
+// getTagPolicies returns the tags described by the effective tag policy of the account, indexed by
+// the lower case key.
+func (c *awsClient) getTagPolicies() (map[string]*tagPolicy, error) {
+	output, err := c.orgClient.DescribeEffectivePolicy(&organizations.DescribeEffectivePolicyInput{
+		PolicyType: aws.String(organizations.EffectivePolicyTypeTagPolicy),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			case organizations.ErrCodeAWSOrganizationsNotInUseException,
+				organizations.ErrCodeEffectivePolicyNotFoundException,
+				organizations.ErrCodeAccessDeniedException:
+				c.logger.Debug(fmt.Sprintf("Not checking tag policies: %v", aerr.Code()))
+				return nil, nil
+			}
+		}
+		return nil, fmt.Errorf("Failed to get the tag policy of the AWS organization: %v", err)
+	}
+	if output.EffectivePolicy == nil {
+		return nil, nil
+	}
+	return parseTagPolicy(aws.StringValue(output.EffectivePolicy.PolicyContent))
+}
+
+// parseTagPolicy parses the content of an effective tag policy. Values of effective policies are
+// either strings or lists of strings.
+func parseTagPolicy(content string) (map[string]*tagPolicy, error) {
+	var document struct {
+		Tags map[string]struct {
+			TagKey      interface{} `json:"tag_key"`
+			TagValue    interface{} `json:"tag_value"`
+			EnforcedFor interface{} `json:"enforced_for"`
+		} `json:"tags"`
+	}
+	err := json.Unmarshal([]byte(content), &document)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse the tag policy of the AWS organization: %v", err)
+	}
+	result := map[string]*tagPolicy{}
+	for name, tag := range document.Tags {
+		policy := &tagPolicy{
+			Key:         name,
+			Values:      stringList(tag.TagValue),
+			EnforcedFor: stringList(tag.EnforcedFor),
+		}
+		if keys := stringList(tag.TagKey); len(keys) > 0 {
+			policy.Key = keys[0]
+		}
+		result[strings.ToLower(policy.Key)] = policy
+	}
+	return result, nil
+}
+
+func stringList(element interface{}) []string {
+	switch typed := element.(type) {
+	case string:
+		return []string{typed}
+	case []interface{}:
+		result := []string{}
+		for _, item := range typed {
+			if text, ok := item.(string); ok {
+				result = append(result, text)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// allows checks if the given value is allowed by the policy. Allowed values can end with an
+// asterisk that matches anything.
+func (p *tagPolicy) allows(value string) bool {
+	if len(p.Values) == 0 {
+		return true
+	}
+	for _, allowed := range p.Values {
+		if allowed == value ||
+			strings.HasSuffix(allowed, "*") && strings.HasPrefix(value, strings.TrimSuffix(allowed, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// enforcedForEC2 checks if the policy prevents creating EC2 resources with tags that don't comply
+// with it, as those are the resources that the installer creates.
+func (p *tagPolicy) enforcedForEC2() bool {
+	for _, resource := range p.EnforcedFor {
+		if strings.HasPrefix(resource, "ec2:") || strings.HasPrefix(resource, "elasticloadbalancing:") {
+			return true
+		}
+	}
+	return false
+}
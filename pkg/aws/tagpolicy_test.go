@@ -0,0 +1,123 @@
+package aws_test
+
+import (
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mocks"
+)
+
+var _ = Describe("ValidateTags", func() {
+	var (
+		client   aws.Client
+		mockCtrl *gomock.Controller
+
+		mockOrgAPI *mocks.MockOrganizationsAPI
+	)
+
+	expectPolicy := func(content string) {
+		mockOrgAPI.EXPECT().DescribeEffectivePolicy(gomock.Any()).Return(&organizations.DescribeEffectivePolicyOutput{
+			EffectivePolicy: &organizations.EffectivePolicy{PolicyContent: awssdk.String(content)},
+		}, nil)
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		var apis *apiMocks
+		client, apis = newClient(mockCtrl, regionSession("us-east-1"))
+		mockOrgAPI = apis.Organizations
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("Accepts any tags when the account isn't in an organization", func() {
+		mockOrgAPI.EXPECT().DescribeEffectivePolicy(gomock.Any()).Return(nil,
+			awserr.New(organizations.ErrCodeAWSOrganizationsNotInUseException, "not in use", nil))
+
+		warnings, err := client.ValidateTags(map[string]string{"Team": "infra"})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+
+	It("Rejects values that an enforced policy doesn't allow", func() {
+		expectPolicy(`{"tags": {"costcenter": {
+			"tag_key": "CostCenter",
+			"tag_value": ["100", "200*"],
+			"enforced_for": ["ec2:instance"]
+		}}}`)
+
+		_, err := client.ValidateTags(map[string]string{"CostCenter": "300"})
+
+		Expect(err).To(BeAssignableToTypeOf(&aws.TagPolicyError{}))
+		Expect(err.(*aws.TagPolicyError).Problems).To(HaveLen(1))
+	})
+
+	It("Accepts values that match a wildcard", func() {
+		expectPolicy(`{"tags": {"costcenter": {
+			"tag_key": "CostCenter",
+			"tag_value": ["100", "200*"],
+			"enforced_for": ["ec2:instance"]
+		}}}`)
+
+		warnings, err := client.ValidateTags(map[string]string{"CostCenter": "2001"})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+
+	It("Only warns about policies that aren't enforced", func() {
+		expectPolicy(`{"tags": {"costcenter": {
+			"tag_key": "CostCenter",
+			"tag_value": ["100"]
+		}, "team": {"tag_key": "Team"}}}`)
+
+		warnings, err := client.ValidateTags(map[string]string{"costcenter": "300"})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(HaveLen(3))
+	})
+
+	It("Rejects reserved keys", func() {
+		_, err := aws.ParseTags([]string{"kubernetes.io/cluster/mycluster:owned"})
+
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Accepts both separators", func() {
+		tags, err := aws.ParseTags([]string{"CostCenter=1234", "Team:infra", "Owner=a=b"})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tags).To(Equal(map[string]string{
+			"CostCenter": "1234",
+			"Team":       "infra",
+			"Owner":      "a=b",
+		}))
+	})
+
+	It("Rejects characters that AWS doesn't allow", func() {
+		_, err := aws.ParseTags([]string{"Cost*Center=1234"})
+
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Rejects too many tags", func() {
+		values := make([]string, aws.MaxTags+1)
+		for i := range values {
+			values[i] = fmt.Sprintf("key%d=value", i)
+		}
+
+		_, err := aws.ParseTags(values)
+
+		Expect(err).To(HaveOccurred())
+	})
+})
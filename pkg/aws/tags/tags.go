@@ -27,3 +27,11 @@ const ClusterName = prefix + "cluster_name"
 
 // ClusterID is the name of the tag that will contain the identifier of the cluster.
 const ClusterID = prefix + "cluster_id"
+
+// RoleType is the name of the tag that will contain the type of an account role, for example
+// 'installer'.
+const RoleType = prefix + "role_type"
+
+// RoleVersion is the name of the tag that will contain the version of the policies of an account
+// role.
+const RoleVersion = prefix + "role_version"
@@ -0,0 +1,101 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that mint temporary credentials to give to OCM instead of the
+// long lived access keys of the admin user.
+
+package aws
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+
+	"github.com/openshift/moactl/assets"
+)
+
+// Limits of the duration of the credentials returned by the GetFederationToken operation:
+const (
+	MinTemporaryCredentialsDuration = 15 * time.Minute
+	MaxTemporaryCredentialsDuration = 36 * time.Hour
+)
+
+// temporaryCredentialsName is the name of the federated user of the temporary credentials, which
+// appears in CloudTrail.
+const temporaryCredentialsName = "rosa-" + AdminUserName
+
+// temporaryCredentialsPolicy is the session policy that limits the temporary credentials to the
+// permissions that the admin user needs.
+const temporaryCredentialsPolicy = "templates/policies/osd_scp_policy.json"
+
+// GetTemporaryAccessKeys returns credentials that expire after the given duration and that only
+// have the permissions that the admin user needs. They are obtained with GetFederationToken, which
+// requires the credentials of an IAM user. Credentials that are already temporary, like those of
+// an assumed role, are returned as they are, as they can't mint others.
+func (c *awsClient) GetTemporaryAccessKeys(duration time.Duration) (*AccessKey, error) {
+	if duration < MinTemporaryCredentialsDuration || duration > MaxTemporaryCredentialsDuration {
+		return nil, fmt.Errorf("Duration of temporary credentials must be between %s and %s",
+			MinTemporaryCredentialsDuration, MaxTemporaryCredentialsDuration)
+	}
+
+	value, err := c.awsSession.Config.Credentials.Get()
+	if err != nil {
+		return nil, err
+	}
+	if value.SessionToken != "" {
+		c.logger.Debug("Using the temporary credentials of the session")
+		expiration, err := c.awsSession.Config.Credentials.ExpiresAt()
+		if err != nil {
+			// Not all the providers know when their credentials expire:
+			expiration = time.Time{}
+		}
+		return &AccessKey{
+			AccessKeyID:     value.AccessKeyID,
+			SecretAccessKey: value.SecretAccessKey,
+			SessionToken:    value.SessionToken,
+			Expiration:      expiration,
+		}, nil
+	}
+
+	// The size of session policies is limited, so the spaces of the document are removed:
+	document, err := assets.Asset(temporaryCredentialsPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to load file: %s", temporaryCredentialsPolicy)
+	}
+	var data bytes.Buffer
+	err = json.Compact(&data, document)
+	if err != nil {
+		return nil, fmt.Errorf("Error compacting policy document '%s': %v", temporaryCredentialsPolicy, err)
+	}
+	output, err := c.stsClient.GetFederationToken(&sts.GetFederationTokenInput{
+		Name:            aws.String(temporaryCredentialsName),
+		DurationSeconds: aws.Int64(int64(duration.Seconds())),
+		Policy:          aws.String(data.String()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get temporary credentials: %v", err)
+	}
+	return &AccessKey{
+		AccessKeyID:     aws.StringValue(output.Credentials.AccessKeyId),
+		SecretAccessKey: aws.StringValue(output.Credentials.SecretAccessKey),
+		SessionToken:    aws.StringValue(output.Credentials.SessionToken),
+		Expiration:      aws.TimeValue(output.Credentials.Expiration),
+	}, nil
+}
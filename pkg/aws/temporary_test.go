@@ -0,0 +1,87 @@
+package aws_test
+
+import (
+	"encoding/json"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mocks"
+)
+
+var _ = Describe("GetTemporaryAccessKeys", func() {
+	var (
+		mockCtrl   *gomock.Controller
+		mockSTSAPI *mocks.MockSTSAPI
+	)
+
+	// clientWithToken creates a client whose credentials have the given session token:
+	clientWithToken := func(sessionToken string) aws.Client {
+		client, apis := newClient(mockCtrl, &session.Session{Config: &awssdk.Config{
+			Region:      awssdk.String("us-east-1"),
+			Credentials: credentials.NewStaticCredentials("AKIDUSER", "secret", sessionToken),
+		}})
+		mockSTSAPI = apis.STS
+		return client
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("Gets a federation token limited to the permissions of the admin user", func() {
+		client := clientWithToken("")
+		expiration := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+		mockSTSAPI.EXPECT().GetFederationToken(gomock.Any()).DoAndReturn(
+			func(input *sts.GetFederationTokenInput) (*sts.GetFederationTokenOutput, error) {
+				Expect(awssdk.Int64Value(input.DurationSeconds)).To(Equal(int64(7200)))
+				Expect(awssdk.StringValue(input.Name)).To(Equal("rosa-osdCcsAdmin"))
+				var policy map[string]interface{}
+				Expect(json.Unmarshal([]byte(awssdk.StringValue(input.Policy)), &policy)).To(Succeed())
+				Expect(policy).To(HaveKey("Statement"))
+				return &sts.GetFederationTokenOutput{
+					Credentials: &sts.Credentials{
+						AccessKeyId:     awssdk.String("ASIAFEDERATED"),
+						SecretAccessKey: awssdk.String("federated-secret"),
+						SessionToken:    awssdk.String("token"),
+						Expiration:      awssdk.Time(expiration),
+					},
+				}, nil
+			})
+
+		accessKey, err := client.GetTemporaryAccessKeys(2 * time.Hour)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(accessKey).To(Equal(&aws.AccessKey{
+			AccessKeyID:     "ASIAFEDERATED",
+			SecretAccessKey: "federated-secret",
+			SessionToken:    "token",
+			Expiration:      expiration,
+		}))
+	})
+
+	It("Returns credentials that are already temporary", func() {
+		accessKey, err := clientWithToken("session").GetTemporaryAccessKeys(time.Hour)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(accessKey.AccessKeyID).To(Equal("AKIDUSER"))
+		Expect(accessKey.SessionToken).To(Equal("session"))
+	})
+
+	It("Rejects durations that AWS doesn't support", func() {
+		_, err := clientWithToken("").GetTemporaryAccessKeys(48 * time.Hour)
+
+		Expect(err).To(MatchError(ContainSubstring("must be between 15m0s and 36h0m0s")))
+	})
+})
@@ -2,6 +2,8 @@ package aws
 
 import (
 	"fmt"
+	"os"
+	"sort"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/arn"
@@ -10,15 +12,25 @@ import (
 	"github.com/aws/aws-sdk-go/service/iam"
 
 	"github.com/openshift/moactl/assets"
+	"github.com/openshift/moactl/pkg/aws/profile"
+	"github.com/openshift/moactl/pkg/config"
+	"github.com/openshift/moactl/pkg/simulate"
 )
 
 // GetRegion will return a region selected by the user or given as a default to the AWS client.
 // If the region given is empty, it will first attempt to use the default, and, failing that, will
 // prompt for user input.
 func GetRegion(region string) (string, error) {
+	if region == "" {
+		region = profileRegion()
+	}
+	if region == "" && simulate.Enabled() {
+		region = simulate.Region
+	}
 	if region == "" {
 		defaultSession, err := session.NewSessionWithOptions(session.Options{
 			SharedConfigState: session.SharedConfigEnable,
+			Profile:           profile.Profile(),
 		})
 
 		if err != nil {
@@ -30,6 +42,15 @@ func GetRegion(region string) (string, error) {
 	return region, nil
 }
 
+// profileRegion returns the region of the profile of the configuration file of rosa, unless the
+// 'AWS_REGION' environment variable is set, as the environment takes precedence over the profile.
+func profileRegion() string {
+	if os.Getenv("AWS_REGION") != "" {
+		return ""
+	}
+	return config.Region()
+}
+
 // getClientDetails will return the *iam.User associated with the provided client's credentials,
 // a boolean indicating whether the user is the 'root' account, and any error encountered
 // while trying to gather the info.
@@ -54,7 +75,8 @@ func getClientDetails(awsClient *awsClient) (*iam.User, bool, error) {
 }
 
 // Build cloudformation create stack input
-func buildCreateStackInput(cfTemplateBody, stackName string) *cloudformation.CreateStackInput {
+func buildCreateStackInput(cfTemplateBody, stackName string, tags map[string]string,
+	parameters []*cloudformation.Parameter) *cloudformation.CreateStackInput {
 	// Special cloudformation capabilities are required to create IAM resources in AWS
 	cfCapabilityIAM := "CAPABILITY_IAM"
 	cfCapabilityNamedIAM := "CAPABILITY_NAMED_IAM"
@@ -64,11 +86,14 @@ func buildCreateStackInput(cfTemplateBody, stackName string) *cloudformation.Cre
 		Capabilities: cfTemplateCapabilities,
 		StackName:    aws.String(stackName),
 		TemplateBody: aws.String(cfTemplateBody),
+		Tags:         buildStackTags(tags),
+		Parameters:   parameters,
 	}
 }
 
-// Build cloudformation update stack input
-func buildUpdateStackInput(cfTemplateBody, stackName string) *cloudformation.UpdateStackInput {
+// Build cloudformation update stack input. Stacks keep their tags when no tags are given.
+func buildUpdateStackInput(cfTemplateBody, stackName string, tags map[string]string,
+	parameters []*cloudformation.Parameter) *cloudformation.UpdateStackInput {
 	// Special cloudformation capabilities are required to update IAM resources in AWS
 	cfCapabilityIAM := "CAPABILITY_IAM"
 	cfCapabilityNamedIAM := "CAPABILITY_NAMED_IAM"
@@ -78,7 +103,30 @@ func buildUpdateStackInput(cfTemplateBody, stackName string) *cloudformation.Upd
 		Capabilities: cfTemplateCapabilities,
 		StackName:    aws.String(stackName),
 		TemplateBody: aws.String(cfTemplateBody),
+		Tags:         buildStackTags(tags),
+		Parameters:   parameters,
+	}
+}
+
+// Build cloudformation stack tags, which CloudFormation also adds to the resources of the stack.
+// The keys are sorted so that the input doesn't change from one run to the next.
+func buildStackTags(tags map[string]string) []*cloudformation.Tag {
+	if len(tags) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := make([]*cloudformation.Tag, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, &cloudformation.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(tags[key]),
+		})
 	}
+	return result
 }
 
 // Read cloudformation template
@@ -0,0 +1,213 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that check that the subnets of an existing VPC can be used to
+// install a cluster, before sending them to OCM, as otherwise the problems are only found when the
+// installation fails.
+
+package aws
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// MinSubnetFreeAddresses is the number of free IP addresses that each subnet needs for the
+// instances and the load balancers of the cluster.
+const MinSubnetFreeAddresses = 32
+
+// Number of availability zones of single and multi-AZ clusters:
+const (
+	singleAZZones = 1
+	multiAZZones  = 3
+)
+
+// SubnetsError contains all the problems that prevent using the given subnets, so that they can be
+// reported to the user at once.
+type SubnetsError struct {
+	Problems []string
+}
+
+func (e *SubnetsError) Error() string {
+	return fmt.Sprintf("Subnets can't be used:\n  - %s", strings.Join(e.Problems, "\n  - "))
+}
+
+// ValidateSubnets checks that the given subnets exist in the region of the client and in the same
+// VPC, that they are spread over the availability zones that the cluster needs, that each zone has
+// a private subnet, and a public one unless the cluster is private, and that they have enough free
+// IP addresses. A subnet is public if its route table sends traffic to an internet gateway. If the
+// subnets can't be used the returned error is a *SubnetsError listing all the problems found.
+func (c *awsClient) ValidateSubnets(subnetIDs []string, multiAZ bool, private bool) error {
+	subnets, err := c.FindSubnets(subnetIDs)
+	if err != nil {
+		return fmt.Errorf("Failed to describe subnets: %v", err)
+	}
+	problems := []string{}
+	found := map[string]*ec2.Subnet{}
+	for _, subnet := range subnets {
+		found[aws.StringValue(subnet.SubnetId)] = subnet
+	}
+	for _, subnetID := range subnetIDs {
+		if found[subnetID] == nil {
+			problems = append(problems, fmt.Sprintf("subnet '%s' doesn't exist in region '%s'",
+				subnetID, c.GetRegion()))
+		}
+	}
+	if len(subnets) == 0 {
+		return &SubnetsError{Problems: problems}
+	}
+
+	vpcs := map[string]bool{}
+	for _, subnet := range subnets {
+		vpcs[aws.StringValue(subnet.VpcId)] = true
+	}
+	if len(vpcs) > 1 {
+		problems = append(problems, fmt.Sprintf("subnets are in different VPCs %s, they must all be in "+
+			"the same VPC", strings.Join(sortedKeys(vpcs), ", ")))
+		return &SubnetsError{Problems: problems}
+	}
+	vpcID := aws.StringValue(subnets[0].VpcId)
+
+	public, err := c.publicSubnets(vpcID, subnetIDs)
+	if err != nil {
+		return fmt.Errorf("Failed to describe route tables of VPC '%s': %v", vpcID, err)
+	}
+
+	type zone struct {
+		public  int
+		private int
+	}
+	zones := map[string]*zone{}
+	for _, subnet := range subnets {
+		subnetID := aws.StringValue(subnet.SubnetId)
+		name := aws.StringValue(subnet.AvailabilityZone)
+		if zones[name] == nil {
+			zones[name] = &zone{}
+		}
+		if public[subnetID] {
+			c.logger.Debugf("Subnet '%s' in zone '%s' is public", subnetID, name)
+			zones[name].public++
+		} else {
+			c.logger.Debugf("Subnet '%s' in zone '%s' is private", subnetID, name)
+			zones[name].private++
+		}
+		free := aws.Int64Value(subnet.AvailableIpAddressCount)
+		if free < MinSubnetFreeAddresses {
+			problems = append(problems, fmt.Sprintf("subnet '%s' has %d free IP addresses, it needs at "+
+				"least %d", subnetID, free, MinSubnetFreeAddresses))
+		}
+	}
+
+	expected := singleAZZones
+	if multiAZ {
+		expected = multiAZZones
+	}
+	names := make([]string, 0, len(zones))
+	for name := range zones {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(zones) != expected {
+		problems = append(problems, fmt.Sprintf("subnets are in %d availability zones (%s), a %s cluster "+
+			"needs subnets in exactly %d", len(zones), strings.Join(names, ", "), azDescription(multiAZ),
+			expected))
+	}
+	for _, name := range names {
+		if zones[name].private == 0 {
+			problems = append(problems, fmt.Sprintf("availability zone '%s' doesn't have a private subnet",
+				name))
+		}
+		if !private && zones[name].public == 0 {
+			problems = append(problems, fmt.Sprintf("availability zone '%s' doesn't have a public subnet, "+
+				"which clusters that aren't private need", name))
+		}
+		if private && zones[name].public > 0 {
+			problems = append(problems, fmt.Sprintf("availability zone '%s' has a public subnet, private "+
+				"clusters only use private subnets", name))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &SubnetsError{Problems: problems}
+	}
+	return nil
+}
+
+// publicSubnets checks which of the given subnets of the given VPC have a route table with a route
+// to an internet gateway. Subnets without an explicit association use the main route table of the
+// VPC.
+func (c *awsClient) publicSubnets(vpcID string, subnetIDs []string) (map[string]bool, error) {
+	tables := []*ec2.RouteTable{}
+	err := c.ec2Client.DescribeRouteTablesPages(&ec2.DescribeRouteTablesInput{
+		Filters: []*ec2.Filter{{
+			Name:   aws.String("vpc-id"),
+			Values: aws.StringSlice([]string{vpcID}),
+		}},
+	}, func(page *ec2.DescribeRouteTablesOutput, lastPage bool) bool {
+		tables = append(tables, page.RouteTables...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	mainPublic := false
+	explicit := map[string]bool{}
+	result := map[string]bool{}
+	for _, table := range tables {
+		toGateway := false
+		for _, route := range table.Routes {
+			if strings.HasPrefix(aws.StringValue(route.GatewayId), "igw-") {
+				toGateway = true
+			}
+		}
+		for _, association := range table.Associations {
+			if aws.BoolValue(association.Main) {
+				mainPublic = toGateway
+			}
+			if subnetID := aws.StringValue(association.SubnetId); subnetID != "" {
+				explicit[subnetID] = true
+				result[subnetID] = toGateway
+			}
+		}
+	}
+	for _, subnetID := range subnetIDs {
+		if !explicit[subnetID] {
+			result[subnetID] = mainPublic
+		}
+	}
+	return result, nil
+}
+
+func azDescription(multiAZ bool) string {
+	if multiAZ {
+		return "multi-AZ"
+	}
+	return "single-AZ"
+}
+
+func sortedKeys(values map[string]bool) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
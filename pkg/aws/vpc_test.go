@@ -0,0 +1,112 @@
+package aws_test
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mocks"
+)
+
+var _ = Describe("ValidateSubnets", func() {
+	var (
+		client   aws.Client
+		mockCtrl *gomock.Controller
+
+		mockEC2API *mocks.MockEC2API
+	)
+
+	subnet := func(id string, zone string, free int64) *ec2.Subnet {
+		return &ec2.Subnet{
+			SubnetId:                awssdk.String(id),
+			VpcId:                   awssdk.String("vpc-1"),
+			AvailabilityZone:        awssdk.String(zone),
+			AvailableIpAddressCount: awssdk.Int64(free),
+		}
+	}
+
+	// The public subnets are associated to a route table with an internet gateway, the others use
+	// the main route table that doesn't have one:
+	routeTables := func(publicSubnets ...string) {
+		public := &ec2.RouteTable{
+			Routes: []*ec2.Route{{GatewayId: awssdk.String("igw-1")}},
+		}
+		for _, id := range publicSubnets {
+			public.Associations = append(public.Associations, &ec2.RouteTableAssociation{
+				SubnetId: awssdk.String(id),
+			})
+		}
+		main := &ec2.RouteTable{
+			Routes:       []*ec2.Route{{NatGatewayId: awssdk.String("nat-1")}},
+			Associations: []*ec2.RouteTableAssociation{{Main: awssdk.Bool(true)}},
+		}
+		mockEC2API.EXPECT().DescribeRouteTablesPages(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ *ec2.DescribeRouteTablesInput, fn func(*ec2.DescribeRouteTablesOutput, bool) bool) error {
+				fn(&ec2.DescribeRouteTablesOutput{RouteTables: []*ec2.RouteTable{public, main}}, true)
+				return nil
+			})
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		var apis *apiMocks
+		client, apis = newClient(mockCtrl, regionSession("us-east-1"))
+		mockEC2API = apis.EC2
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("Accepts a public and a private subnet in one zone", func() {
+		mockEC2API.EXPECT().DescribeSubnets(gomock.Any()).Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []*ec2.Subnet{subnet("subnet-1", "us-east-1a", 250), subnet("subnet-2", "us-east-1a", 250)},
+		}, nil)
+		routeTables("subnet-1")
+
+		err := client.ValidateSubnets([]string{"subnet-1", "subnet-2"}, false, false)
+
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Reports subnets that don't exist", func() {
+		mockEC2API.EXPECT().DescribeSubnets(gomock.Any()).Return(&ec2.DescribeSubnetsOutput{}, nil)
+
+		err := client.ValidateSubnets([]string{"subnet-1"}, false, false)
+
+		Expect(err).To(BeAssignableToTypeOf(&aws.SubnetsError{}))
+		Expect(err.Error()).To(ContainSubstring("subnet 'subnet-1' doesn't exist"))
+	})
+
+	It("Reports all the problems of the subnets together", func() {
+		mockEC2API.EXPECT().DescribeSubnets(gomock.Any()).Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []*ec2.Subnet{subnet("subnet-1", "us-east-1a", 10), subnet("subnet-2", "us-east-1b", 250)},
+		}, nil)
+		routeTables()
+
+		err := client.ValidateSubnets([]string{"subnet-1", "subnet-2"}, true, false)
+
+		Expect(err).To(BeAssignableToTypeOf(&aws.SubnetsError{}))
+		problems := err.(*aws.SubnetsError).Problems
+		Expect(problems).To(HaveLen(4))
+		Expect(problems[0]).To(ContainSubstring("10 free IP addresses"))
+		Expect(problems[1]).To(ContainSubstring("needs subnets in exactly 3"))
+		Expect(problems[2]).To(ContainSubstring("'us-east-1a' doesn't have a public subnet"))
+		Expect(problems[3]).To(ContainSubstring("'us-east-1b' doesn't have a public subnet"))
+	})
+
+	It("Rejects public subnets for private clusters", func() {
+		mockEC2API.EXPECT().DescribeSubnets(gomock.Any()).Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []*ec2.Subnet{subnet("subnet-1", "us-east-1a", 250), subnet("subnet-2", "us-east-1a", 250)},
+		}, nil)
+		routeTables("subnet-1")
+
+		err := client.ValidateSubnets([]string{"subnet-1", "subnet-2"}, false, true)
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("private clusters only use private subnets"))
+	})
+})
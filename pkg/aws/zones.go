@@ -0,0 +1,120 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Maximum number of regions that are queried at the same time when retrieving availability zones:
+const maxZoneWorkers = 8
+
+// GetAvailabilityZonesByRegion returns the availability zones of each of the given regions. The
+// regions are queried in parallel, using at most a fixed number of workers so that the account
+// isn't throttled by the EC2 API.
+func GetAvailabilityZonesByRegion(logger *logrus.Logger, regions []string) (map[string][]string, error) {
+	return zonesByRegion(logger, regions, "availability zones", Client.GetAvailabilityZones)
+}
+
+// GetLocalZonesByRegion returns the Local Zones enabled in the account for each of the given
+// regions, querying them like GetAvailabilityZonesByRegion.
+func GetLocalZonesByRegion(logger *logrus.Logger, regions []string) (map[string][]string, error) {
+	return zonesByRegion(logger, regions, "Local Zones", Client.GetLocalZones)
+}
+
+// ValidateAvailabilityZones checks that the given zones are availability zones of the region of
+// the client and that none of them is repeated.
+func (c *awsClient) ValidateAvailabilityZones(zones []string) error {
+	regionZones, err := c.GetAvailabilityZones()
+	if err != nil {
+		return fmt.Errorf("Failed to get the availability zones of region '%s': %v", c.GetRegion(), err)
+	}
+	valid := make(map[string]bool, len(regionZones))
+	for _, zone := range regionZones {
+		valid[zone] = true
+	}
+	seen := make(map[string]bool, len(zones))
+	for _, zone := range zones {
+		if seen[zone] {
+			return fmt.Errorf("Availability zone '%s' is given more than once", zone)
+		}
+		seen[zone] = true
+		if !valid[zone] {
+			sort.Strings(regionZones)
+			return fmt.Errorf("Availability zone '%s' isn't a zone of region '%s', the available zones "+
+				"are: %s", zone, c.GetRegion(), strings.Join(regionZones, ", "))
+		}
+	}
+	return nil
+}
+
+func zonesByRegion(logger *logrus.Logger, regions []string, description string,
+	get func(Client) ([]string, error)) (map[string][]string, error) {
+	type result struct {
+		region string
+		zones  []string
+		err    error
+	}
+
+	jobs := make(chan string)
+	results := make(chan result, len(regions))
+
+	var wg sync.WaitGroup
+	workers := maxZoneWorkers
+	if len(regions) < workers {
+		workers = len(regions)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for region := range jobs {
+				client, err := NewClient().
+					Logger(logger).
+					Region(region).
+					Build()
+				if err != nil {
+					results <- result{region: region, err: err}
+					continue
+				}
+				zones, err := get(client)
+				results <- result{region: region, zones: zones, err: err}
+			}
+		}()
+	}
+
+	for _, region := range regions {
+		jobs <- region
+	}
+	close(jobs)
+	wg.Wait()
+	close(results)
+
+	zonesByRegion := make(map[string][]string, len(regions))
+	for r := range results {
+		if r.err != nil {
+			return nil, fmt.Errorf("Failed to get %s for region '%s': %v", description, r.region, r.err)
+		}
+		zonesByRegion[r.region] = r.zones
+	}
+	return zonesByRegion, nil
+}
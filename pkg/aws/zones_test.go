@@ -0,0 +1,60 @@
+package aws_test
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mocks"
+)
+
+var _ = Describe("Availability zones", func() {
+	var (
+		client   aws.Client
+		mockCtrl *gomock.Controller
+
+		mockEC2API *mocks.MockEC2API
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		var apis *apiMocks
+		client, apis = newClient(mockCtrl, regionSession("us-east-1"))
+		mockEC2API = apis.EC2
+		mockEC2API.EXPECT().DescribeAvailabilityZones(gomock.Any()).Return(&ec2.DescribeAvailabilityZonesOutput{
+			AvailabilityZones: []*ec2.AvailabilityZone{
+				{ZoneName: awssdk.String("us-east-1b")},
+				{ZoneName: awssdk.String("us-east-1a")},
+				{ZoneName: awssdk.String("us-east-1c")},
+			},
+		}, nil)
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("Accepts zones of the region", func() {
+		err := client.ValidateAvailabilityZones([]string{"us-east-1a", "us-east-1c"})
+
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Rejects zones of other regions", func() {
+		err := client.ValidateAvailabilityZones([]string{"us-east-1a", "us-west-2a"})
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("'us-west-2a' isn't a zone of region 'us-east-1'"))
+		Expect(err.Error()).To(ContainSubstring("us-east-1a, us-east-1b, us-east-1c"))
+	})
+
+	It("Rejects repeated zones", func() {
+		err := client.ValidateAvailabilityZones([]string{"us-east-1a", "us-east-1a"})
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("more than once"))
+	})
+})
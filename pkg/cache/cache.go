@@ -0,0 +1,159 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains a small on-disk cache for data that rarely changes, like the lists of regions,
+// versions and machine types, so that commands don't need to retrieve it from the API every time.
+
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/openshift/moactl/pkg/ocm/config"
+	"github.com/openshift/moactl/pkg/simulate"
+)
+
+// Time to live of the entries of the cache, for each kind of data.
+const (
+	RegionsTTL      = 24 * time.Hour
+	VersionsTTL     = time.Hour
+	MachineTypesTTL = 24 * time.Hour
+	ReleasesTTL     = 24 * time.Hour
+)
+
+// entry is an item of the cache file.
+type entry struct {
+	Time time.Time       `json:"time"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Location returns the location of the cache file, '~/.config/rosa/cache.json' in Linux.
+func Location() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "rosa", "cache.json"), nil
+}
+
+// Key returns the key of the cache entry for the given kind of data and parts. The URL of the API
+// is part of the key, so that data of different environments isn't mixed. Parts that may be
+// sensitive, like credentials, are hashed.
+func Key(kind string, parts ...string) string {
+	cfg, _ := config.Load()
+	key := kind + ":" + cfg.APIURL()
+	if len(parts) > 0 {
+		sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+		key += ":" + hex.EncodeToString(sum[:8])
+	}
+	return key
+}
+
+// Get returns the data stored in the cache with the given key, if it exists and is younger than
+// the given time to live. It always returns false when the '--refresh' flag is used, and in
+// simulation mode. Errors reading the cache are treated as missing entries, as the data can always
+// be retrieved again.
+func Get(key string, ttl time.Duration) ([]byte, bool) {
+	if refresh || simulate.Enabled() {
+		return nil, false
+	}
+	entries, err := load()
+	if err != nil {
+		return nil, false
+	}
+	item, ok := entries[key]
+	if !ok || time.Since(item.Time) > ttl {
+		return nil, false
+	}
+	return item.Data, true
+}
+
+// Set stores the given data in the cache with the given key. Errors writing the cache are ignored,
+// as it is only used to avoid requests to the API. Nothing is stored in simulation mode, so that
+// the canned data isn't mixed with the real one.
+func Set(key string, data []byte) {
+	if simulate.Enabled() {
+		return
+	}
+	entries, err := load()
+	if err != nil {
+		entries = map[string]*entry{}
+	}
+	entries[key] = &entry{
+		Time: time.Now(),
+		Data: data,
+	}
+	_ = save(entries)
+}
+
+// Clear removes the cache file. It isn't an error if it doesn't exist.
+func Clear() error {
+	file, err := Location()
+	if err != nil {
+		return err
+	}
+	err = os.Remove(file)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func load() (map[string]*entry, error) {
+	file, err := Location()
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	entries := map[string]*entry{}
+	err = json.Unmarshal(data, &entries)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func save(entries map[string]*entry) error {
+	file, err := Location()
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(file), 0700)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first, so that concurrent commands never read a partial file:
+	tmp := file + ".tmp"
+	err = ioutil.WriteFile(tmp, data, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
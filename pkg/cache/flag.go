@@ -0,0 +1,42 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the '--refresh' flag, which bypasses the cache.
+
+package cache
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// AddFlag adds the '--refresh' flag to the given set of command line flags.
+func AddFlag(fs *pflag.FlagSet) {
+	fs.BoolVar(
+		&refresh,
+		"refresh",
+		false,
+		"Retrieve regions, versions and machine types from the API instead of using the local "+
+			"cache, and update the cache with the result.",
+	)
+}
+
+// Refresh returns true if the '--refresh' flag has been used.
+func Refresh() bool {
+	return refresh
+}
+
+// refresh indicates if the cache should be bypassed.
+var refresh bool
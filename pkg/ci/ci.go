@@ -0,0 +1,222 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to implement the '--ci' command line option, which makes the
+// commands suitable for continuous integration pipelines: they never prompt, they print messages
+// and objects as JSON without colors, they are stopped when they take too long and they write a
+// result document describing what they did.
+
+package ci
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// DefaultTimeout is the maximum time that a command can run in CI mode, unless changed with the
+// '--ci-timeout' option. It is long enough to watch the installation of a cluster.
+const DefaultTimeout = 2 * time.Hour
+
+// AddFlags adds the flags of the CI mode to the given set of command line flags.
+func AddFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(
+		&enabled,
+		"ci",
+		false,
+		"Enable CI mode: disable prompts and colors, print messages as JSON objects and the output "+
+			"of the list and describe commands as JSON, stop the command if it takes longer than "+
+			"'--ci-timeout' and write the result to '--result-file'.",
+	)
+	flags.DurationVar(
+		&timeout,
+		"ci-timeout",
+		DefaultTimeout,
+		"Maximum time that a command can run in CI mode.",
+	)
+	flags.StringVar(
+		&resultFile,
+		"result-file",
+		"",
+		"Path of the file where the JSON result document is written in CI mode.",
+	)
+}
+
+// Enabled returns a boolean flag that indicates if the CI mode is enabled.
+func Enabled() bool {
+	return enabled
+}
+
+var (
+	enabled    bool
+	timeout    time.Duration
+	resultFile string
+)
+
+// Resource is a resource created by a command. The cluster is the identifier of the cluster that the
+// resource belongs to, if any. The identifier of the resource is empty when OCM doesn't return it.
+type Resource struct {
+	Kind    string    `json:"kind"`
+	Cluster string    `json:"cluster,omitempty"`
+	ID      string    `json:"id,omitempty"`
+	Name    string    `json:"name,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+// Result is the document written to the result file. It is written when the command starts and
+// updated every time that something changes, so that it is complete even if the command exits
+// abruptly.
+type Result struct {
+	Command   string      `json:"command"`
+	Success   bool        `json:"success"`
+	Finished  bool        `json:"finished"`
+	Errors    []string    `json:"errors,omitempty"`
+	Resources []*Resource `json:"resources"`
+	Started   time.Time   `json:"started"`
+	Updated   time.Time   `json:"updated"`
+	Duration  float64     `json:"duration_seconds"`
+}
+
+var (
+	mutex  sync.Mutex
+	result *Result
+)
+
+// Start starts recording the result of the command with the given arguments and stops the process
+// when the timeout expires. It does nothing if the CI mode isn't enabled.
+func Start(argv []string) {
+	if !enabled {
+		return
+	}
+
+	// Only the names of the command and subcommands are kept, as the flags may contain secrets:
+	command := []string{}
+	for _, arg := range argv {
+		if strings.HasPrefix(arg, "-") {
+			break
+		}
+		command = append(command, arg)
+	}
+
+	mutex.Lock()
+	now := time.Now()
+	result = &Result{
+		Command:   strings.Join(command, " "),
+		Resources: []*Resource{},
+		Started:   now,
+	}
+	writeLocked()
+	mutex.Unlock()
+
+	go func() {
+		time.Sleep(timeout)
+		err := fmt.Errorf("Command didn't finish in %s", timeout)
+		Finish(err)
+		fmt.Fprintf(os.Stderr, "%s\n", Message("error", err.Error()))
+		os.Exit(1)
+	}()
+}
+
+// Bound returns the given duration, or the time left before the timeout of the CI mode if that is
+// shorter. Commands use it to limit the time that they wait for something.
+func Bound(duration time.Duration) time.Duration {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if result == nil {
+		return duration
+	}
+	left := time.Until(result.Started.Add(timeout))
+	if left < duration {
+		return left
+	}
+	return duration
+}
+
+// RecordResource adds a resource created by the command to the result. The creation time is set
+// to the current time.
+func RecordResource(resource *Resource) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if result == nil {
+		return
+	}
+	resource.Created = time.Now()
+	result.Resources = append(result.Resources, resource)
+	writeLocked()
+}
+
+// RecordError adds an error reported by the command to the result.
+func RecordError(message string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if result == nil {
+		return
+	}
+	result.Errors = append(result.Errors, message)
+	writeLocked()
+}
+
+// Finish marks the result as finished. The command succeeded if the given error is nil and no
+// error was reported.
+func Finish(err error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if result == nil {
+		return
+	}
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+	result.Finished = true
+	result.Success = len(result.Errors) == 0
+	writeLocked()
+}
+
+// Message returns the given message as a JSON object, as printed in CI mode.
+func Message(level string, message string) string {
+	data, err := json.Marshal(map[string]string{
+		"level":   level,
+		"message": message,
+	})
+	if err != nil {
+		return message
+	}
+	return string(data)
+}
+
+// writeLocked writes the result file. It must be called with the mutex locked.
+func writeLocked() {
+	now := time.Now()
+	result.Updated = now
+	result.Duration = now.Sub(result.Started).Seconds()
+	if resultFile == "" {
+		return
+	}
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err == nil {
+		err = ioutil.WriteFile(resultFile, append(data, '\n'), 0600)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", Message("error", fmt.Sprintf(
+			"Failed to write result file '%s': %v", resultFile, err)))
+	}
+}
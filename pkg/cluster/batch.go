@@ -0,0 +1,170 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to run a command on several clusters at the same time,
+// selected with a list of names or patterns in the '--cluster' flag or with the '--all' and
+// '--filter' flags.
+
+package cluster
+
+import (
+	"path"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+)
+
+// Names of the command line flags that select the clusters of a batch operation:
+const (
+	AllFlag         = "all"
+	FilterFlag      = "filter"
+	MaxParallelFlag = "max-parallel"
+)
+
+// batchAnnotation is the annotation of the commands that can run on several clusters at the same
+// time.
+const batchAnnotation = "cluster.batch"
+
+var batch struct {
+	all         bool
+	filter      string
+	maxParallel int
+}
+
+// UseBatch marks the given command as one that can run on several clusters at the same time, and
+// adds the flags that select them. The command also needs to use the '--cluster' flag.
+func UseBatch(cmd *cobra.Command) {
+	UseKey(cmd)
+	cmd.Annotations[batchAnnotation] = "true"
+
+	flags := cmd.Flags()
+	flags.BoolVar(
+		&batch.all,
+		AllFlag,
+		false,
+		"Run the command on all the clusters, or on the ones that match '--filter'.",
+	)
+	flags.StringVar(
+		&batch.filter,
+		FilterFlag,
+		"",
+		"OCM search expression selecting the clusters when several are given, for example "+
+			"\"region.id = 'us-east-1' and state = 'ready'\".",
+	)
+	flags.IntVar(
+		&batch.maxParallel,
+		MaxParallelFlag,
+		5,
+		"Maximum number of clusters processed at the same time when several are given.",
+	)
+}
+
+// UsesBatch checks if the given command can run on several clusters at the same time.
+func UsesBatch(cmd *cobra.Command) bool {
+	return cmd.Annotations[batchAnnotation] == "true"
+}
+
+// IsBatchKey checks if the given value of the '--cluster' flag selects several clusters, because
+// it is a comma separated list or contains glob patterns.
+func IsBatchKey(clusterKey string) bool {
+	return strings.ContainsAny(clusterKey, ",*?[")
+}
+
+// BatchSelected checks if the command line flags select several clusters.
+func BatchSelected() bool {
+	return batch.all || batch.filter != "" || IsBatchKey(key)
+}
+
+// MaxParallel returns the maximum number of clusters processed at the same time.
+func MaxParallel() int {
+	return batch.maxParallel
+}
+
+// GetBatchClusters returns the clusters selected by the command line flags: the ones whose name or
+// identifier matches any of the names or glob patterns of the '--cluster' flag, or all of them if
+// '--all' is used. In both cases only the clusters that match the '--filter' search expression are
+// taken into account. It fails if a name that isn't a pattern doesn't match any cluster.
+func GetBatchClusters(client ocm.Client, creatorARN string) ([]*cmv1.Cluster, error) {
+	if batch.all && key != "" {
+		return nil, rosaerrors.Usagef("At most one of '--%s' or '--%s' may be specified", KeyFlag, AllFlag)
+	}
+	if batch.maxParallel < 1 {
+		return nil, rosaerrors.Usagef("Expected a positive value for '--%s'", MaxParallelFlag)
+	}
+	patterns := []string{}
+	for _, pattern := range strings.Split(key, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, rosaerrors.Usagef("Cluster pattern '%s' isn't valid: %v", pattern, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	if !batch.all && len(patterns) == 0 {
+		return nil, rosaerrors.Usagef("Expected at least one cluster name or pattern in '--%s'", KeyFlag)
+	}
+
+	clusters := []*cmv1.Cluster{}
+	err := client.StreamClusters(creatorARN, batch.filter, 100, func(page []*cmv1.Cluster) bool {
+		clusters = append(clusters, page...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if batch.all {
+		return clusters, nil
+	}
+
+	result := []*cmv1.Cluster{}
+	matched := map[string]bool{}
+	for _, pattern := range patterns {
+		found := false
+		for _, cluster := range clusters {
+			if !matchesPattern(cluster, pattern) {
+				continue
+			}
+			found = true
+			if !matched[cluster.ID()] {
+				matched[cluster.ID()] = true
+				result = append(result, cluster)
+			}
+		}
+		if !found && !IsBatchKey(pattern) {
+			return nil, rosaerrors.NotFoundf("There is no cluster with identifier or name '%s'", pattern)
+		}
+	}
+	return result, nil
+}
+
+// matchesPattern checks if the name or identifier of the cluster matches the given glob pattern.
+func matchesPattern(cluster *cmv1.Cluster, pattern string) bool {
+	for _, value := range []string{cluster.Name(), cluster.ID(), cluster.ExternalID()} {
+		if value == "" {
+			continue
+		}
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that export the definition of an existing cluster, so that an
+// equivalent cluster can be created in a different region.
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/versions"
+)
+
+// defaultMachinePoolID is the identifier of the machine pool that contains the compute nodes
+// created with the cluster, which are part of the settings of the cluster itself.
+const defaultMachinePoolID = "default"
+
+// ExportDefinition returns the definition of the given cluster, with its settings and its machine
+// pools. Identity providers aren't included, as their secrets can't be read back.
+func ExportDefinition(client ocm.Client, cluster *cmv1.Cluster) (*Definition, error) {
+	// The AWS details are read from the document of the cluster:
+	data, err := client.GetClusterDocument(cluster.ID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get cluster '%s': %v", cluster.Name(), err)
+	}
+	var body struct {
+		AWS struct {
+			Tags                       map[string]string `json:"tags"`
+			SubnetIDs                  []string          `json:"subnet_ids"`
+			AdditionalSecurityGroupIDs []string          `json:"additional_security_group_ids"`
+			PrivateLink                bool              `json:"private_link"`
+			KMSKeyARN                  string            `json:"kms_key_arn"`
+			STS                        *stsJSON          `json:"sts"`
+		} `json:"aws"`
+	}
+	err = json.Unmarshal(data, &body)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse cluster '%s': %v", cluster.Name(), err)
+	}
+
+	definition := &Definition{
+		Name:               cluster.Name(),
+		Region:             cluster.Region().ID(),
+		Version:            versions.GetRawID(cluster.Version()),
+		ChannelGroup:       cluster.Version().ChannelGroup(),
+		MultiAZ:            boolPtr(cluster.MultiAZ()),
+		ComputeMachineType: cluster.Nodes().ComputeMachineType().ID(),
+		Private:            boolPtr(cluster.API().Listening() == cmv1.ListeningMethodInternal),
+		PrivateLink:        boolPtr(body.AWS.PrivateLink),
+		EtcdEncryption:     boolPtr(cluster.EtcdEncryption()),
+		KMSKeyARN:          body.AWS.KMSKeyARN,
+		DeleteProtection:   boolPtr(ocm.IsDeleteProtected(cluster)),
+		STS:                boolPtr(body.AWS.STS != nil && body.AWS.STS.RoleARN != ""),
+		Tags:               body.AWS.Tags,
+		Network: &NetworkDefinition{
+			MachineCIDR:                cluster.Network().MachineCIDR(),
+			ServiceCIDR:                cluster.Network().ServiceCIDR(),
+			PodCIDR:                    cluster.Network().PodCIDR(),
+			SubnetIDs:                  body.AWS.SubnetIDs,
+			AdditionalSecurityGroupIDs: body.AWS.AdditionalSecurityGroupIDs,
+		},
+	}
+	if hostPrefix, ok := cluster.Network().GetHostPrefix(); ok {
+		definition.Network.HostPrefix = intPtr(hostPrefix)
+	}
+
+	// The compute nodes created with the cluster have a fixed number of replicas, so clusters
+	// that autoscale them start with the minimum:
+	computeNodes := cluster.Nodes().Compute()
+	if autoscaling, ok := cluster.Nodes().GetAutoscaleCompute(); ok {
+		computeNodes = autoscaling.MinReplicas()
+	}
+	definition.ComputeNodes = intPtr(computeNodes)
+
+	pools, err := client.GetMachinePools(cluster.ID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get machine pools of cluster '%s': %v", cluster.Name(), err)
+	}
+	for _, pool := range pools {
+		if pool.ID() == defaultMachinePoolID {
+			continue
+		}
+		poolDefinition := &MachinePoolDefinition{
+			Name:         pool.ID(),
+			InstanceType: pool.InstanceType(),
+			Labels:       pool.Labels(),
+		}
+		if autoscaling, ok := pool.GetAutoscaling(); ok {
+			poolDefinition.MinReplicas = intPtr(autoscaling.MinReplicas())
+			poolDefinition.MaxReplicas = intPtr(autoscaling.MaxReplicas())
+		} else {
+			poolDefinition.Replicas = intPtr(pool.Replicas())
+		}
+		definition.MachinePools = append(definition.MachinePools, poolDefinition)
+	}
+	return definition, nil
+}
+
+// ExportIdentityProviders adds the identity providers of the given cluster to the definition, in
+// the format generated by 'rosa describe idp -o yaml', without their secrets.
+func (d *Definition) ExportIdentityProviders(client ocm.Client, cluster *cmv1.Cluster) error {
+	idps, err := client.GetIdentityProviders(cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get identity providers of cluster '%s': %v", cluster.Name(), err)
+	}
+	for _, idp := range idps {
+		config, err := ocm.IdentityProviderConfig(idp)
+		if err != nil {
+			return fmt.Errorf("Failed to export identity provider '%s' of cluster '%s': %v",
+				idp.Name(), cluster.Name(), err)
+		}
+		d.IdentityProviders = append(d.IdentityProviders, config)
+	}
+	return nil
+}
+
+// Relocate changes the definition so that the cluster is created in the given region, with the
+// given subnets. The settings that refer to resources of the original region, like the subnets,
+// and therefore the availability zones, the security groups and the KMS key, are removed. It
+// returns the descriptions of the settings that have been removed, so that users can review them.
+func (d *Definition) Relocate(region string, subnetIDs []string) []string {
+	removed := []string{}
+	d.Region = region
+
+	if d.Network == nil {
+		d.Network = &NetworkDefinition{}
+	}
+	if len(d.Network.SubnetIDs) > 0 && len(subnetIDs) == 0 {
+		removed = append(removed, "the subnets, the cluster will be created in a new VPC using the "+
+			"availability zones chosen by the installer")
+	}
+	d.Network.SubnetIDs = subnetIDs
+	if len(d.Network.AdditionalSecurityGroupIDs) > 0 {
+		removed = append(removed, "the additional security groups, which belong to the VPC of the "+
+			"original cluster")
+		d.Network.AdditionalSecurityGroupIDs = nil
+	}
+	if d.KMSKeyARN != "" {
+		removed = append(removed, fmt.Sprintf("the KMS key '%s', which belongs to the original region",
+			d.KMSKeyARN))
+		d.KMSKeyARN = ""
+	}
+	// PrivateLink clusters must be installed in existing subnets:
+	if d.PrivateLink != nil && *d.PrivateLink && len(subnetIDs) == 0 {
+		removed = append(removed, "PrivateLink, which needs the subnets of an existing VPC")
+		d.PrivateLink = nil
+	}
+	return removed
+}
+
+func boolPtr(value bool) *bool {
+	return &value
+}
+
+func intPtr(value int) *int {
+	return &value
+}
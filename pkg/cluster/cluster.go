@@ -17,18 +17,18 @@ limitations under the License.
 package cluster
 
 import (
-	"errors"
 	"fmt"
 	"net"
 	"regexp"
 	"time"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
-	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
 
 	"github.com/openshift/moactl/pkg/aws"
 	"github.com/openshift/moactl/pkg/info"
-	"github.com/openshift/moactl/pkg/logging"
+	"github.com/openshift/moactl/pkg/network"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/machinepools"
 	"github.com/openshift/moactl/pkg/ocm/properties"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
 )
@@ -55,6 +55,9 @@ type Spec struct {
 	ComputeMachineType string
 	ComputeNodes       int
 
+	// Size in GiB of the root volume of the compute nodes, zero for the default size
+	WorkerDiskSize int
+
 	// SubnetIDs
 	SubnetIds []string
 
@@ -68,9 +71,34 @@ type Spec struct {
 	HostPrefix  int
 	Private     *bool
 
+	// IPv6 blocks of dual-stack clusters. The machine block is empty when AWS assigns it to the
+	// VPC created by the installer.
+	DualStack     bool
+	MachineCIDRv6 net.IPNet
+	ServiceCIDRv6 net.IPNet
+	PodCIDRv6     net.IPNet
+
+	// Make the API only reachable over AWS PrivateLink
+	PrivateLink bool
+
+	// Security groups attached to the load balancers of the API and the default ingress, in
+	// addition to the ones created by the installer
+	AdditionalSecurityGroupIDs []string
+
+	// Encryption options
+	EtcdEncryption bool
+	KMSKeyARN      string
+
+	// Compliance options
+	FIPS                      bool
+	DisableWorkloadMonitoring bool
+
 	// Properties
 	CustomProperties map[string]string
 
+	// Refuse to delete the cluster unless the deletion is forced
+	DeleteProtection bool
+
 	// Access control config
 	ClusterAdmins *bool
 
@@ -79,6 +107,19 @@ type Spec struct {
 
 	// Disable SCP checks in the installer by setting credentials mode as mint
 	DisableSCPChecks *bool
+
+	// ARN of the AWS Secrets Manager secret where the access keys of the admin user are kept
+	CredentialsSecretARN string
+
+	// Give OCM temporary credentials that expire after this time instead of the access keys of the
+	// admin user
+	TemporaryCredentials time.Duration
+
+	// Additional tags for the AWS resources of the cluster
+	Tags map[string]string
+
+	// Roles assumed by the cluster when it uses AWS STS instead of the access keys of the admin user
+	STS *STS
 }
 
 func IsValidClusterKey(clusterKey string) bool {
@@ -89,21 +130,10 @@ func IsValidClusterName(clusterName string) bool {
 	return clusterNameRE.MatchString(clusterName)
 }
 
-func HasClusters(client *cmv1.ClustersClient, creatorARN string) (bool, error) {
-	query := fmt.Sprintf("properties.%s = '%s'", properties.CreatorARN, creatorARN)
-	response, err := client.List().
-		Search(query).
-		Page(1).
-		Size(1).
-		Send()
-	if err != nil {
-		return false, handleErr(response.Error(), err)
-	}
-
-	return response.Total() > 0, nil
-}
-
-func CreateCluster(client *cmv1.ClustersClient, config Spec) (*cmv1.Cluster, error) {
+// CreateCluster creates the cluster described by the given configuration. The AWS client is used to
+// get the credentials given to OCM and to tag the osdCcsAdmin user, so it can be the client of any
+// region of the partition of the cluster.
+func CreateCluster(client ocm.Client, awsClient aws.Client, config Spec) (*cmv1.Cluster, error) {
 	reporter, err := rprtr.New().
 		Build()
 
@@ -111,95 +141,74 @@ func CreateCluster(client *cmv1.ClustersClient, config Spec) (*cmv1.Cluster, err
 		return nil, fmt.Errorf("Unable to create reporter: %v", err)
 	}
 
-	logger, err := logging.NewLogger().
-		Build()
+	spec, awsAccessKey, err := createClusterSpec(config, awsClient)
 	if err != nil {
-		return nil, fmt.Errorf("Unable to create AWS logger: %v", err)
+		return nil, fmt.Errorf("Unable to create cluster spec: %v", err)
 	}
 
-	// Create the AWS client:
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Region(aws.DefaultRegion).
-		Build()
-	if err != nil {
-		return nil, fmt.Errorf("Failed to create AWS client: %v", err)
+	awsDetails := map[string]interface{}{}
+	if awsAccessKey != nil && awsAccessKey.SessionToken != "" {
+		awsDetails["session_token"] = awsAccessKey.SessionToken
+	}
+	if len(config.Tags) > 0 {
+		awsDetails["tags"] = config.Tags
+	}
+	if config.STS != nil {
+		awsDetails["sts"] = config.STS.toJSON()
+	}
+	if config.PrivateLink {
+		awsDetails["private_link"] = true
+	}
+	if config.KMSKeyARN != "" {
+		awsDetails["kms_key_arn"] = config.KMSKeyARN
+	}
+	if len(config.AdditionalSecurityGroupIDs) > 0 {
+		awsDetails["additional_security_group_ids"] = config.AdditionalSecurityGroupIDs
 	}
 
-	spec, err := createClusterSpec(config, awsClient)
-	if err != nil {
-		return nil, fmt.Errorf("Unable to create cluster spec: %v", err)
+	details := map[string]interface{}{}
+	if len(awsDetails) > 0 {
+		details["aws"] = awsDetails
+	}
+	if config.DualStack {
+		details["network"] = dualStackDetails(config)
+	}
+	if config.FIPS {
+		details["fips"] = true
+	}
+	if config.DisableWorkloadMonitoring {
+		details["disable_user_workload_monitoring"] = true
+	}
+	if config.WorkerDiskSize != 0 {
+		details["nodes"] = map[string]interface{}{
+			"compute_root_volume": machinepools.RootVolume(config.WorkerDiskSize),
+		}
 	}
 
-	cluster, err := client.Add().Parameter("dryRun", *config.DryRun).Body(spec).Send()
+	clusterObject, err := client.AddCluster(spec, details, *config.DryRun)
 	if err != nil {
-		return nil, handleErr(cluster.Error(), err)
+		return nil, err
 	}
 	if config.DryRun != nil && *config.DryRun {
 		return nil, nil
 	}
 
-	clusterObject := cluster.Body()
+	// Clusters that use AWS STS or temporary credentials don't use the AWS administrator user:
+	if config.STS != nil || config.TemporaryCredentials != 0 {
+		return clusterObject, nil
+	}
 
-	// Add tags to the AWS administrator user containing the identifier and name of the cluster:
-	err = awsClient.TagUser(aws.AdminUserName, clusterObject.ID(), clusterObject.Name())
+	// Add tags to the AWS administrator user containing the identifier and name of the cluster,
+	// and the tags given by the user:
+	err = awsClient.TagUser(aws.AdminUserName, clusterObject.ID(), clusterObject.Name(), config.Tags)
 	if err != nil {
 		reporter.Warnf("Failed to add cluster tags to user '%s'", aws.AdminUserName)
 	}
 	return clusterObject, nil
 }
 
-func GetClusters(client *cmv1.ClustersClient, creatorARN string, count int) (clusters []*cmv1.Cluster, err error) {
-	if count < 1 {
-		err = errors.New("Cannot fetch fewer than 1 cluster")
-		return
-	}
-	query := fmt.Sprintf("properties.%s = '%s'", properties.CreatorARN, creatorARN)
-	request := client.List().Search(query)
-	page := 1
-	for {
-		response, err := request.Page(page).Size(count).Send()
-		if err != nil {
-			return clusters, err
-		}
-		response.Items().Each(func(cluster *cmv1.Cluster) bool {
-			clusters = append(clusters, cluster)
-			return true
-		})
-		if response.Size() != count {
-			break
-		}
-		page++
-	}
-	return clusters, nil
-}
-
-func GetCluster(client *cmv1.ClustersClient, clusterKey string, creatorARN string) (*cmv1.Cluster, error) {
-	query := fmt.Sprintf(
-		"(id = '%s' or name = '%s') and properties.%s = '%s'",
-		clusterKey, clusterKey, properties.CreatorARN, creatorARN,
-	)
-	response, err := client.List().
-		Search(query).
-		Page(1).
-		Size(1).
-		Send()
-	if err != nil {
-		return nil, handleErr(response.Error(), err)
-	}
-
-	switch response.Total() {
-	case 0:
-		return nil, fmt.Errorf("There is no cluster with identifier or name '%s'", clusterKey)
-	case 1:
-		return response.Items().Slice()[0], nil
-	default:
-		return nil, fmt.Errorf("There are %d clusters with identifier or name '%s'", response.Total(), clusterKey)
-	}
-}
-
-func UpdateCluster(client *cmv1.ClustersClient, clusterKey string, creatorARN string, config Spec) error {
-	cluster, err := GetCluster(client, clusterKey, creatorARN)
+func UpdateCluster(client ocm.Client, clusterKey string, creatorARN string, config Spec) error {
+	cluster, err := client.GetCluster(clusterKey, creatorARN)
 	if err != nil {
 		return err
 	}
@@ -244,70 +253,67 @@ func UpdateCluster(client *cmv1.ClustersClient, clusterKey string, creatorARN st
 		return err
 	}
 
-	response, err := client.Cluster(cluster.ID()).Update().Body(clusterSpec).Send()
-	if err != nil {
-		return handleErr(response.Error(), err)
-	}
-
-	return nil
+	return client.UpdateCluster(cluster.ID(), clusterSpec)
 }
 
-func DeleteCluster(client *cmv1.ClustersClient, clusterKey string, creatorARN string) (*cmv1.Cluster, error) {
-	cluster, err := GetCluster(client, clusterKey, creatorARN)
+func DeleteCluster(client ocm.Client, clusterKey string, creatorARN string) (*cmv1.Cluster, error) {
+	cluster, err := client.GetCluster(clusterKey, creatorARN)
 	if err != nil {
 		return nil, err
 	}
 
-	response, err := client.Cluster(cluster.ID()).Delete().Send()
+	err = client.DeleteCluster(cluster.ID())
 	if err != nil {
-		return nil, handleErr(response.Error(), err)
+		return nil, err
 	}
 
 	return cluster, nil
 }
 
-func InstallAddOn(client *cmv1.ClustersClient, clusterKey string, creatorARN string, addOnID string) error {
-	cluster, err := GetCluster(client, clusterKey, creatorARN)
-	if err != nil {
-		return err
-	}
-
-	addOnInstallation, err := cmv1.NewAddOnInstallation().
-		Addon(cmv1.NewAddOn().ID(addOnID)).
-		Build()
+func InstallAddOn(client ocm.Client, clusterKey string, creatorARN string, addOnID string) error {
+	cluster, err := client.GetCluster(clusterKey, creatorARN)
 	if err != nil {
 		return err
 	}
-
-	response, err := client.Cluster(cluster.ID()).Addons().Add().Body(addOnInstallation).Send()
-	if err != nil {
-		return handleErr(response.Error(), err)
-	}
-
-	return nil
+	return client.InstallAddOn(cluster.ID(), addOnID, nil)
 }
 
-func createClusterSpec(config Spec, awsClient aws.Client) (*cmv1.Cluster, error) {
+func createClusterSpec(config Spec, awsClient aws.Client) (*cmv1.Cluster, *aws.AccessKey, error) {
 	reporter, err := rprtr.New().
 		Build()
 
 	if err != nil {
-		return nil, fmt.Errorf("Error creating cluster reporter: %v", err)
+		return nil, nil, fmt.Errorf("Error creating cluster reporter: %v", err)
 	}
 
 	awsCreator, err := awsClient.GetCreator()
 	if err != nil {
-		return nil, fmt.Errorf("Failed to get AWS creator: %v", err)
+		return nil, nil, fmt.Errorf("Failed to get AWS creator: %v", err)
 	}
 
-	// Create the access key for the AWS user:
-	awsAccessKey, err := awsClient.GetAWSAccessKeys()
-	if err != nil {
-		return nil, fmt.Errorf("Failed to get access keys for user '%s': %v\n"+
-			"Run 'rosa init' and try again", aws.AdminUserName, err)
+	// Create the access key for the AWS user, reuse the one stored in the secret, or mint temporary
+	// credentials. Clusters that use AWS STS assume roles instead:
+	var awsAccessKey *aws.AccessKey
+	if config.STS == nil && config.TemporaryCredentials != 0 {
+		awsAccessKey, err = awsClient.GetTemporaryAccessKeys(config.TemporaryCredentials)
+		if err != nil {
+			return nil, nil, err
+		}
+		reporter.Debugf("Temporary access key identifier is '%s', it expires at %s",
+			awsAccessKey.AccessKeyID, awsAccessKey.Expiration)
+	} else if config.STS == nil {
+		if config.CredentialsSecretARN != "" {
+			awsAccessKey, err = awsClient.GetAWSAccessKeysFromSecret(config.CredentialsSecretARN)
+		} else {
+			awsAccessKey, err = awsClient.GetAWSAccessKeys()
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed to get access keys for user '%s': %v\n"+
+				"Run 'rosa init' and try again", aws.AdminUserName, err)
+		}
+		reporter.Debugf("Access key identifier is '%s'", awsAccessKey.AccessKeyID)
+		reporter.Debugf("Secret access key is '%s'", awsAccessKey.SecretAccessKey)
 	}
-	reporter.Debugf("Access key identifier is '%s'", awsAccessKey.AccessKeyID)
-	reporter.Debugf("Secret access key is '%s'", awsAccessKey.SecretAccessKey)
 
 	clusterProperties := map[string]string{}
 
@@ -319,15 +325,18 @@ func createClusterSpec(config Spec, awsClient aws.Client) (*cmv1.Cluster, error)
 
 	// Make sure we don't have a custom properties collision
 	if _, present := clusterProperties[properties.CreatorARN]; present {
-		return nil, fmt.Errorf("Custom properties key %s collides with a property needed by rosa", properties.CreatorARN)
+		return nil, nil, fmt.Errorf("Custom properties key %s collides with a property needed by rosa", properties.CreatorARN)
 	}
 
 	if _, present := clusterProperties[properties.CLIVersion]; present {
-		return nil, fmt.Errorf("Custom properties key %s collides with a property needed by rosa", properties.CLIVersion)
+		return nil, nil, fmt.Errorf("Custom properties key %s collides with a property needed by rosa", properties.CLIVersion)
 	}
 
 	clusterProperties[properties.CreatorARN] = awsCreator.ARN
 	clusterProperties[properties.CLIVersion] = info.Version
+	if config.DeleteProtection {
+		clusterProperties[properties.DeleteProtection] = "true"
+	}
 
 	// Create the cluster:
 	clusterBuilder := cmv1.NewCluster().
@@ -344,6 +353,10 @@ func createClusterSpec(config Spec, awsClient aws.Client) (*cmv1.Cluster, error)
 		).
 		Properties(clusterProperties)
 
+	if config.EtcdEncryption {
+		clusterBuilder = clusterBuilder.EtcdEncryption(true)
+	}
+
 	if config.Version != "" {
 		clusterBuilder = clusterBuilder.Version(
 			cmv1.NewVersion().
@@ -413,9 +426,12 @@ func createClusterSpec(config Spec, awsClient aws.Client) (*cmv1.Cluster, error)
 	}
 
 	awsBuilder := cmv1.NewAWS().
-		AccountID(awsCreator.AccountID).
-		AccessKeyID(awsAccessKey.AccessKeyID).
-		SecretAccessKey(awsAccessKey.SecretAccessKey)
+		AccountID(awsCreator.AccountID)
+	if awsAccessKey != nil {
+		awsBuilder = awsBuilder.
+			AccessKeyID(awsAccessKey.AccessKeyID).
+			SecretAccessKey(awsAccessKey.SecretAccessKey)
+	}
 
 	if config.SubnetIds != nil {
 		awsBuilder.SubnetIDs(config.SubnetIds...)
@@ -428,24 +444,35 @@ func createClusterSpec(config Spec, awsClient aws.Client) (*cmv1.Cluster, error)
 			Enabled(true).
 			DisableSCPChecks(true),
 		)
+	} else if config.STS != nil {
+		clusterBuilder = clusterBuilder.CCS(cmv1.NewCCS().
+			Enabled(true),
+		)
 	}
 
 	clusterSpec, err := clusterBuilder.Build()
 	if err != nil {
-		return nil, fmt.Errorf("Failed to create description of cluster: %v", err)
+		return nil, nil, fmt.Errorf("Failed to create description of cluster: %v", err)
 	}
 
-	return clusterSpec, nil
+	return clusterSpec, awsAccessKey, nil
 }
 
-func cidrIsEmpty(cidr net.IPNet) bool {
-	return cidr.String() == "<nil>"
+// dualStackDetails returns the fields of the network of a dual-stack cluster, which are added to the
+// JSON description of the cluster.
+func dualStackDetails(config Spec) map[string]interface{} {
+	details := map[string]interface{}{
+		"stack":           "dual",
+		"service_cidr_v6": config.ServiceCIDRv6.String(),
+		"pod_cidr_v6":     config.PodCIDRv6.String(),
+		"host_prefix_v6":  network.HostPrefixV6,
+	}
+	if !cidrIsEmpty(config.MachineCIDRv6) {
+		details["machine_cidr_v6"] = config.MachineCIDRv6.String()
+	}
+	return details
 }
 
-func handleErr(res *ocmerrors.Error, err error) error {
-	msg := res.Reason()
-	if msg == "" {
-		msg = err.Error()
-	}
-	return errors.New(msg)
+func cidrIsEmpty(cidr net.IPNet) bool {
+	return cidr.String() == "<nil>"
 }
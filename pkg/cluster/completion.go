@@ -0,0 +1,71 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the function that completes the '--cluster' flag in the shell with the names
+// of the clusters of the user.
+
+package cluster
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// maxCompletedClusters is the maximum number of clusters loaded to complete the flag, so that
+// completion stays fast for users with many clusters.
+const maxCompletedClusters = 100
+
+// CompleteKey returns the names of the clusters of the user that start with the given text. It
+// doesn't exit or print errors when the clusters can't be loaded, for example when the user isn't
+// logged in, as that would break the completion of the shell; it just offers nothing.
+func CompleteKey(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	logger := runtime.FromContext(cmd.Context()).Logger()
+
+	awsClient, err := aws.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	creator, err := awsClient.GetCreator()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	connection, err := ocm.NewConnection().
+		Logger(logger).
+		Build()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer connection.Close()
+
+	clusters, err := ocm.NewClient(connection).GetClusters(creator.ARN, maxCompletedClusters)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names := []string{}
+	for _, cluster := range clusters {
+		if strings.HasPrefix(cluster.Name(), toComplete) {
+			names = append(names, cluster.Name())
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
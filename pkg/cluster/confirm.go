@@ -0,0 +1,99 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to confirm destructive operations on clusters.
+
+package cluster
+
+import (
+	"fmt"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/confirm"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// Names and values of the tags that mark production clusters, compared ignoring case:
+var (
+	productionTagKeys   = []string{"environment", "env"}
+	productionTagValues = []string{"production", "prod"}
+)
+
+// IsProduction checks if the given tags mark a production cluster.
+func IsProduction(tags map[string]string) bool {
+	for key, value := range tags {
+		for _, productionKey := range productionTagKeys {
+			if !strings.EqualFold(key, productionKey) {
+				continue
+			}
+			for _, productionValue := range productionTagValues {
+				if strings.EqualFold(value, productionValue) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// ConfirmDestructive asks the user to confirm a destructive operation on the cluster. Depending on
+// the safety level of the configuration file, and on whether the cluster is tagged as production,
+// the user has to type the name of the cluster instead of answering yes.
+func ConfirmDestructive(r *runtime.Runtime, cluster *cmv1.Cluster, q string, v ...interface{}) bool {
+	if !nameRequired(r, cluster) {
+		return confirm.Confirm(q, v...)
+	}
+	return confirm.ConfirmName(cluster.Name(), q, v...)
+}
+
+// ConfirmDestructiveBatch asks the user to confirm a destructive operation on several clusters.
+// The user answers yes once, unless the safety level requires typing the name of some of the
+// clusters, and then the user has to type the name of each of them.
+func ConfirmDestructiveBatch(r *runtime.Runtime, clusters []*cmv1.Cluster, q string, v ...interface{}) bool {
+	protected := []*cmv1.Cluster{}
+	// The tags are only loaded when the safety level may require the names:
+	if confirm.NameRequired(true) {
+		for _, cluster := range clusters {
+			if nameRequired(r, cluster) {
+				protected = append(protected, cluster)
+			}
+		}
+	}
+	if len(protected) == 0 {
+		return confirm.Confirm(q, v...)
+	}
+	operation := fmt.Sprintf(q, v...)
+	for _, cluster := range protected {
+		if !confirm.ConfirmName(cluster.Name(), "%s, including cluster '%s'", operation, cluster.Name()) {
+			return false
+		}
+	}
+	return true
+}
+
+// nameRequired checks if the safety level requires typing the name of the cluster to confirm a
+// destructive operation on it.
+func nameRequired(r *runtime.Runtime, cluster *cmv1.Cluster) bool {
+	// Clusters whose tags can't be loaded are treated as production ones, as that is the safe
+	// choice:
+	tags, err := GetTags(r.OCM(), cluster.ID())
+	if err != nil {
+		r.Reporter().Debugf("Failed to get tags of cluster '%s': %v", cluster.Name(), err)
+	}
+	return confirm.NameRequired(err != nil || IsProduction(tags))
+}
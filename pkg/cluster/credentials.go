@@ -0,0 +1,37 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/ocm"
+)
+
+// UpdateCredentials replaces the AWS credentials that OCM keeps for the cluster with the given
+// identifier, including the session token of temporary credentials.
+func UpdateCredentials(client ocm.Client, clusterID string, accessKey *aws.AccessKey) error {
+	details := map[string]interface{}{
+		"access_key_id":     accessKey.AccessKeyID,
+		"secret_access_key": accessKey.SecretAccessKey,
+	}
+	if accessKey.SessionToken != "" {
+		details["session_token"] = accessKey.SessionToken
+	}
+	return client.PatchCluster(clusterID, map[string]interface{}{
+		"aws": details,
+	})
+}
@@ -0,0 +1,229 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the definition of a cluster that is read from a YAML or JSON file, so that
+// teams can keep the definitions of their clusters in version control.
+
+package cluster
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Machine pool names must be valid DNS-1035 labels:
+var machinePoolNameRE = regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
+
+// Definition is the complete description of a cluster: the settings used to create it, and the
+// machine pools and identity providers that it should have. The settings are given to the same
+// flags of 'rosa create cluster', so they are validated in the same way.
+type Definition struct {
+	Name               string            `yaml:"name"`
+	Region             string            `yaml:"region,omitempty"`
+	Version            string            `yaml:"version,omitempty"`
+	ChannelGroup       string            `yaml:"channel_group,omitempty"`
+	MultiAZ            *bool             `yaml:"multi_az,omitempty"`
+	ComputeMachineType string            `yaml:"compute_machine_type,omitempty"`
+	ComputeNodes       *int              `yaml:"compute_nodes,omitempty"`
+	Private            *bool             `yaml:"private,omitempty"`
+	PrivateLink        *bool             `yaml:"private_link,omitempty"`
+	EtcdEncryption     *bool             `yaml:"etcd_encryption,omitempty"`
+	KMSKeyARN          string            `yaml:"kms_key_arn,omitempty"`
+	DeleteProtection   *bool             `yaml:"delete_protection,omitempty"`
+	STS                *bool             `yaml:"sts,omitempty"`
+	Tags               map[string]string `yaml:"tags,omitempty"`
+
+	Network *NetworkDefinition `yaml:"network,omitempty"`
+
+	MachinePools []*MachinePoolDefinition `yaml:"machine_pools,omitempty"`
+
+	// IdentityProviders have the format generated by 'rosa describe idp -o yaml', without the
+	// secrets, which are provided when the cluster is created.
+	IdentityProviders []map[string]interface{} `yaml:"identity_providers,omitempty"`
+}
+
+// NetworkDefinition contains the network settings of a cluster definition.
+type NetworkDefinition struct {
+	MachineCIDR                string   `yaml:"machine_cidr,omitempty"`
+	ServiceCIDR                string   `yaml:"service_cidr,omitempty"`
+	PodCIDR                    string   `yaml:"pod_cidr,omitempty"`
+	HostPrefix                 *int     `yaml:"host_prefix,omitempty"`
+	DualStack                  *bool    `yaml:"dual_stack,omitempty"`
+	SubnetIDs                  []string `yaml:"subnet_ids,omitempty"`
+	AdditionalSecurityGroupIDs []string `yaml:"additional_security_group_ids,omitempty"`
+}
+
+// MachinePoolDefinition describes a machine pool of a cluster definition. The number of nodes is
+// either fixed with Replicas, or a range with MinReplicas and MaxReplicas to enable autoscaling.
+type MachinePoolDefinition struct {
+	Name         string            `yaml:"name"`
+	InstanceType string            `yaml:"instance_type"`
+	Replicas     *int              `yaml:"replicas,omitempty"`
+	MinReplicas  *int              `yaml:"min_replicas,omitempty"`
+	MaxReplicas  *int              `yaml:"max_replicas,omitempty"`
+	Labels       map[string]string `yaml:"labels,omitempty"`
+}
+
+// Autoscaling checks if the machine pool uses a range of replicas instead of a fixed number.
+func (m *MachinePoolDefinition) Autoscaling() bool {
+	return m.MinReplicas != nil || m.MaxReplicas != nil
+}
+
+// LoadDefinition reads and validates the cluster definition contained in the given YAML or JSON
+// file. Unknown fields are rejected, so that typos aren't silently ignored.
+func LoadDefinition(path string) (*Definition, error) {
+	// #nosec G304
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read file '%s': %v", path, err)
+	}
+	definition := &Definition{}
+	err = yaml.UnmarshalStrict(data, definition)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse cluster definition '%s': %v", path, err)
+	}
+	err = definition.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("Cluster definition '%s' isn't valid: %v", path, err)
+	}
+	return definition, nil
+}
+
+// Validate checks the parts of the definition that aren't checked by the flags of
+// 'rosa create cluster': the name, the machine pools and the identity providers.
+func (d *Definition) Validate() error {
+	if d.Name == "" {
+		return fmt.Errorf("the 'name' of the cluster is mandatory")
+	}
+	if !IsValidClusterName(d.Name) {
+		return fmt.Errorf("cluster name '%s' isn't valid", d.Name)
+	}
+
+	names := map[string]bool{}
+	for i, pool := range d.MachinePools {
+		if !machinePoolNameRE.MatchString(pool.Name) {
+			return fmt.Errorf("name '%s' of machine pool %d isn't valid", pool.Name, i+1)
+		}
+		if names[pool.Name] {
+			return fmt.Errorf("there is more than one machine pool named '%s'", pool.Name)
+		}
+		names[pool.Name] = true
+		if pool.InstanceType == "" {
+			return fmt.Errorf("the 'instance_type' of machine pool '%s' is mandatory", pool.Name)
+		}
+		switch {
+		case pool.Autoscaling() && pool.Replicas != nil:
+			return fmt.Errorf("machine pool '%s' can't have both 'replicas' and 'min_replicas' or "+
+				"'max_replicas'", pool.Name)
+		case pool.Autoscaling() && (pool.MinReplicas == nil || pool.MaxReplicas == nil):
+			return fmt.Errorf("machine pool '%s' needs both 'min_replicas' and 'max_replicas'", pool.Name)
+		case pool.Autoscaling() && *pool.MinReplicas > *pool.MaxReplicas:
+			return fmt.Errorf("'min_replicas' of machine pool '%s' can't be greater than "+
+				"'max_replicas'", pool.Name)
+		case !pool.Autoscaling() && pool.Replicas == nil:
+			return fmt.Errorf("machine pool '%s' needs 'replicas', or 'min_replicas' and "+
+				"'max_replicas'", pool.Name)
+		case pool.Replicas != nil && *pool.Replicas < 0:
+			return fmt.Errorf("'replicas' of machine pool '%s' can't be negative", pool.Name)
+		}
+	}
+
+	names = map[string]bool{}
+	for i, idp := range d.IdentityProviders {
+		name, _ := idp["name"].(string)
+		if name == "" {
+			return fmt.Errorf("identity provider %d doesn't have a 'name'", i+1)
+		}
+		if names[name] {
+			return fmt.Errorf("there is more than one identity provider named '%s'", name)
+		}
+		names[name] = true
+	}
+	return nil
+}
+
+// Flags returns the values of the flags of 'rosa create cluster' that correspond to the settings
+// of the definition, indexed by the name of the flag. Settings that aren't in the definition are
+// omitted, so that the defaults of the flags apply.
+func (d *Definition) Flags() map[string]string {
+	flags := map[string]string{
+		"cluster-name": d.Name,
+	}
+	setString := func(name string, value string) {
+		if value != "" {
+			flags[name] = value
+		}
+	}
+	setBool := func(name string, value *bool) {
+		if value != nil {
+			flags[name] = strconv.FormatBool(*value)
+		}
+	}
+	setInt := func(name string, value *int) {
+		if value != nil {
+			flags[name] = strconv.Itoa(*value)
+		}
+	}
+	// List flags parse their values as a line of CSV, so values that contain commas are quoted:
+	setList := func(name string, values []string) {
+		if len(values) > 0 {
+			buffer := &bytes.Buffer{}
+			writer := csv.NewWriter(buffer)
+			_ = writer.Write(values)
+			writer.Flush()
+			flags[name] = strings.TrimSuffix(buffer.String(), "\n")
+		}
+	}
+
+	setString("region", d.Region)
+	setString("version", d.Version)
+	setString("channel-group", d.ChannelGroup)
+	setBool("multi-az", d.MultiAZ)
+	setString("compute-machine-type", d.ComputeMachineType)
+	setInt("compute-nodes", d.ComputeNodes)
+	setBool("private", d.Private)
+	setBool("private-link", d.PrivateLink)
+	setBool("etcd-encryption", d.EtcdEncryption)
+	setString("kms-key-arn", d.KMSKeyARN)
+	setBool("enable-delete-protection", d.DeleteProtection)
+	setBool("sts", d.STS)
+
+	tags := make([]string, 0, len(d.Tags))
+	for key, value := range d.Tags {
+		tags = append(tags, key+":"+value)
+	}
+	sort.Strings(tags)
+	setList("tags", tags)
+
+	if d.Network != nil {
+		setString("machine-cidr", d.Network.MachineCIDR)
+		setString("service-cidr", d.Network.ServiceCIDR)
+		setString("pod-cidr", d.Network.PodCIDR)
+		setInt("host-prefix", d.Network.HostPrefix)
+		setBool("dual-stack", d.Network.DualStack)
+		setList("subnet-ids", d.Network.SubnetIDs)
+		setList("additional-security-group-ids", d.Network.AdditionalSecurityGroupIDs)
+	}
+	return flags
+}
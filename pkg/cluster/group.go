@@ -0,0 +1,107 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to implement the '--cluster-group' command line option, which
+// selects the clusters of a group defined in the configuration file.
+
+package cluster
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/moactl/pkg/config"
+)
+
+// GroupFlag is the name of the command line flag that selects a group of clusters.
+const GroupFlag = "cluster-group"
+
+var groupNameRE = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// group is the name of the group of clusters selected with the command line flag.
+var group string
+
+// AddGroupFlag adds the '--cluster-group' flag to the given set of command line flags.
+func AddGroupFlag(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&group,
+		GroupFlag,
+		"",
+		"Run the command for each cluster of a group defined with 'rosa create cluster-group', "+
+			"instead of a single cluster. It can be used with all the commands that accept '--cluster'.",
+	)
+}
+
+// Group returns the name of the group of clusters selected with the command line flag.
+func Group() string {
+	return group
+}
+
+// IsValidGroupName checks that the given name can be used for a group of clusters.
+func IsValidGroupName(name string) bool {
+	return groupNameRE.MatchString(name)
+}
+
+// GetGroups returns the groups of clusters defined in the configuration file.
+func GetGroups() (map[string]string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.ClusterGroups == nil {
+		return map[string]string{}, nil
+	}
+	return cfg.ClusterGroups, nil
+}
+
+// GetGroupSearch returns the search expression that selects the clusters of the given group.
+func GetGroupSearch(name string) (string, error) {
+	groups, err := GetGroups()
+	if err != nil {
+		return "", err
+	}
+	search, ok := groups[name]
+	if !ok {
+		names := make([]string, 0, len(groups))
+		for name := range groups {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", fmt.Errorf("There is no cluster group named '%s'. Existing groups are %v", name, names)
+	}
+	return search, nil
+}
+
+// SaveGroup adds a group with the given search expression to the configuration file, or removes
+// it if the expression is empty.
+func SaveGroup(name string, search string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if cfg.ClusterGroups == nil {
+		cfg.ClusterGroups = make(map[string]string)
+	}
+	if search == "" {
+		delete(cfg.ClusterGroups, name)
+	} else {
+		cfg.ClusterGroups[name] = search
+	}
+	return config.Save(cfg)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the function that follows the hibernation and the resumption of clusters.
+
+package cluster
+
+import (
+	"fmt"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/ocm"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// WatchState reports the changes of the state of the given cluster until it is the given one. It
+// fails if the cluster gets into error state or can't be polled, and succeeds if the user presses
+// Ctrl-C, as the operation continues anyway.
+func WatchState(r *runtime.Runtime, cluster *cmv1.Cluster, target cmv1.ClusterState) error {
+	reporter := r.Reporter()
+
+	last := cmv1.ClusterState("")
+	err := r.OCM().WaitForState(cluster.ID(), target, ocm.DefaultWatchInterval,
+		ocm.DefaultWatchTimeout, func(state cmv1.ClusterState) {
+			if state != last {
+				reporter.Progressf(rprtr.CodeClusterState,
+					rprtr.Fields{"cluster": cluster.ID(), "state": state},
+					"Cluster '%s' is %s", cluster.Name(), state)
+				last = state
+			}
+		})
+	if err == ocm.ErrWatchInterrupted {
+		reporter.Progressf(rprtr.CodeWatchStopped, rprtr.Fields{"cluster": cluster.ID()},
+			"Stopped watching cluster '%s', run 'rosa describe cluster -c %s' to check its state",
+			cluster.Name(), cluster.Name())
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to watch cluster '%s': %w", cluster.Name(), err)
+	}
+	return nil
+}
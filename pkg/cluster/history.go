@@ -0,0 +1,270 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that reconstruct what a cluster looked like at a point in the
+// past, using the history that OCM keeps in the service log and in the upgrade policies. OCM
+// doesn't keep previous versions of the cluster object, so the history is recognized from the
+// text of the service log entries, and the fields without any recorded change since that time are
+// assumed to have their current value.
+
+package cluster
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+)
+
+// Kinds of events of the history of a cluster:
+const (
+	EventInstalled        = "installed"
+	EventUpgradeStarted   = "upgrade-started"
+	EventUpgradeCompleted = "upgrade-completed"
+	EventScaled           = "scaled"
+)
+
+// Event is a change of a cluster recognized in its history.
+type Event struct {
+	Time    time.Time `json:"time"`
+	Kind    string    `json:"kind"`
+	From    string    `json:"from,omitempty"`
+	To      string    `json:"to,omitempty"`
+	Summary string    `json:"summary"`
+}
+
+// Snapshot is what a cluster looked like at a point in time.
+type Snapshot struct {
+	Time    time.Time `json:"time"`
+	State   string    `json:"state"`
+	Version string    `json:"version"`
+	Master  int       `json:"master_nodes"`
+	Infra   int       `json:"infra_nodes"`
+	Compute int       `json:"compute_nodes"`
+	// Events are the changes recognized in the history up to the time of the snapshot, oldest
+	// first.
+	Events []Event `json:"events"`
+	// Unknown are the fields that couldn't be reconstructed, and that are empty.
+	Unknown []string `json:"unknown,omitempty"`
+}
+
+// Regular expressions used to recognize the events in the text of the service log entries:
+var (
+	versionRE    = `v?(\d+\.\d+\.\d+[^\s,;'"]*?)\.?(?:[\s,;'"]|$)`
+	addOnRE      = regexp.MustCompile(`(?i)add-?on|operator`)
+	installRE    = regexp.MustCompile(`(?i)install\w*\b.*\b(complete|succe|finish|ready)`)
+	upgradeRE    = regexp.MustCompile(`(?i)upgrad`)
+	upgradeEndRE = regexp.MustCompile(`(?i)(complete|succe|finish|has been upgraded|was upgraded)`)
+	fromRE       = regexp.MustCompile(`(?i)\bfrom (?:version )?` + versionRE)
+	toRE         = regexp.MustCompile(`(?i)\bto (?:version )?` + versionRE)
+	scaleRE      = regexp.MustCompile(`(?i)(scal|resiz)\w*\b.*\b(compute|worker)|(compute|worker)\w*\b.*\b(scal|resiz)`)
+	fromNodesRE  = regexp.MustCompile(`(?i)\bfrom (\d+)\b`)
+	toNodesRE    = regexp.MustCompile(`(?i)\bto (\d+)\b`)
+)
+
+// ParseServiceLog returns the event described by the given entry of the service log, or nil if it
+// doesn't describe a change that is part of a snapshot.
+func ParseServiceLog(entry *slv1.LogEntry) *Event {
+	// Changes of add-ons and operators aren't changes of the cluster itself:
+	text := entry.Summary() + ". " + entry.Description()
+	if addOnRE.MatchString(text) {
+		return nil
+	}
+	event := &Event{
+		Time:    entry.Timestamp(),
+		Summary: entry.Summary(),
+	}
+	switch {
+	case upgradeRE.MatchString(text):
+		event.Kind = EventUpgradeStarted
+		if upgradeEndRE.MatchString(text) {
+			event.Kind = EventUpgradeCompleted
+		}
+		event.From = submatch(fromRE, text)
+		event.To = submatch(toRE, text)
+	case installRE.MatchString(text):
+		event.Kind = EventInstalled
+	case scaleRE.MatchString(text):
+		event.Kind = EventScaled
+		event.From = submatch(fromNodesRE, text)
+		event.To = submatch(toNodesRE, text)
+		if event.To == "" {
+			return nil
+		}
+	default:
+		return nil
+	}
+	return event
+}
+
+// submatch returns the first group matched by the given regular expression, or an empty string.
+func submatch(re *regexp.Regexp, text string) string {
+	match := re.FindStringSubmatch(text)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// Reconstruct returns what the given cluster looked like at the given time, using the entries of
+// its service log and its upgrade policies.
+func Reconstruct(cluster *cmv1.Cluster, entries []*slv1.LogEntry, policies []*cmv1.UpgradePolicy,
+	at time.Time) (*Snapshot, error) {
+	if at.Before(cluster.CreationTimestamp()) {
+		return nil, fmt.Errorf("The cluster didn't exist yet, it was created on %s",
+			cluster.CreationTimestamp().Format(time.RFC3339))
+	}
+	if at.After(time.Now()) {
+		return nil, fmt.Errorf("The time is in the future")
+	}
+
+	events := []Event{}
+	for _, entry := range entries {
+		event := ParseServiceLog(entry)
+		if event != nil {
+			events = append(events, *event)
+		}
+	}
+
+	// Upgrade policies whose time has passed are upgrades that have started, even if the service
+	// log doesn't mention them yet:
+	for _, policy := range policies {
+		if policy.UpgradeType() == "OSD" && policy.NextRun().Before(time.Now()) {
+			events = append(events, Event{
+				Time:    policy.NextRun(),
+				Kind:    EventUpgradeStarted,
+				To:      policy.Version(),
+				Summary: fmt.Sprintf("Upgrade to version %s scheduled", policy.Version()),
+			})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+
+	snapshot := &Snapshot{
+		Time:    at,
+		Master:  cluster.Nodes().Master(),
+		Infra:   cluster.Nodes().Infra(),
+		Events:  []Event{},
+		Unknown: []string{},
+	}
+	for _, event := range events {
+		if !event.Time.After(at) {
+			snapshot.Events = append(snapshot.Events, event)
+		}
+	}
+
+	version, ok := valueAt(events, EventUpgradeCompleted, at, cluster.OpenshiftVersion())
+	if !ok {
+		snapshot.Unknown = append(snapshot.Unknown, "version")
+	}
+	snapshot.Version = version
+
+	compute, ok := valueAt(events, EventScaled, at, strconv.Itoa(cluster.Nodes().Compute()))
+	if ok && cluster.Nodes().AutoscaleCompute() != nil && compute == strconv.Itoa(cluster.Nodes().Compute()) {
+		// The number of nodes of autoscaled clusters changes without leaving a record:
+		ok = false
+	}
+	if ok {
+		snapshot.Compute, _ = strconv.Atoi(compute)
+	} else {
+		snapshot.Unknown = append(snapshot.Unknown, "compute nodes")
+	}
+
+	snapshot.State = stateAt(cluster, events, at)
+	return snapshot, nil
+}
+
+// valueAt returns the value that a field had at the given time, using the events of the given kind
+// that changed it and its current value. The result is false if the value can't be known.
+func valueAt(events []Event, kind string, at time.Time, current string) (string, bool) {
+	var before, after *Event
+	for i := range events {
+		event := &events[i]
+		if event.Kind != kind {
+			continue
+		}
+		if !event.Time.After(at) {
+			before = event
+		} else if after == nil {
+			after = event
+		}
+	}
+	switch {
+	case after == nil:
+		return current, current != ""
+	case before != nil && before.To != "":
+		return before.To, true
+	case after.From != "":
+		return after.From, true
+	}
+	return "", false
+}
+
+// stateAt returns the state that the cluster had at the given time.
+func stateAt(cluster *cmv1.Cluster, events []Event, at time.Time) string {
+	installed := false
+	recorded := false
+	for _, event := range events {
+		if event.Kind == EventInstalled {
+			recorded = true
+			if !event.Time.After(at) {
+				installed = true
+			}
+		}
+	}
+	if !recorded {
+		// Without a record of the install the cluster is assumed to have been installed, unless
+		// it still isn't:
+		switch cluster.State() {
+		case cmv1.ClusterStatePending, cmv1.ClusterStateInstalling, cmv1.ClusterStateError:
+			return string(cluster.State())
+		}
+		installed = true
+	}
+	if !installed {
+		return string(cmv1.ClusterStateInstalling)
+	}
+
+	// The cluster was upgrading if the last upgrade that started before that time finished after
+	// it, or hasn't finished yet:
+	var started *Event
+	for i := range events {
+		event := &events[i]
+		if event.Time.After(at) {
+			if started != nil && event.Kind == EventUpgradeCompleted {
+				return string(cmv1.ClusterStateReady) + " (upgrading)"
+			}
+			continue
+		}
+		switch event.Kind {
+		case EventUpgradeStarted:
+			started = event
+		case EventUpgradeCompleted:
+			started = nil
+		}
+	}
+	if started != nil && started.To != "" && !strings.HasPrefix(cluster.OpenshiftVersion(), started.To) {
+		return string(cmv1.ClusterStateReady) + " (upgrading)"
+	}
+	return string(cmv1.ClusterStateReady)
+}
@@ -0,0 +1,140 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the '--cluster' flag that selects the cluster used by the commands, and the
+// functions that resolve it, using the default cluster of the configuration file when it isn't
+// given.
+
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/moactl/pkg/config"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// KeyFlag is the name of the command line flag that selects the cluster.
+const KeyFlag = "cluster"
+
+// keyAnnotation is the annotation of the commands that use the cluster selected with the flag.
+const keyAnnotation = "cluster.key"
+
+// key is the name, identifier or external identifier of the cluster given with the flag.
+var key string
+
+// AddKeyFlag adds the '--cluster' flag to the given set of command line flags.
+func AddKeyFlag(flags *pflag.FlagSet) {
+	flags.StringVarP(
+		&key,
+		KeyFlag,
+		"c",
+		"",
+		"Name or ID of the cluster. The default is the cluster set with 'rosa config set cluster', "+
+			"if any.",
+	)
+}
+
+// UseKey marks the given command as one that uses the cluster selected with the '--cluster' flag.
+func UseKey(cmd *cobra.Command) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[keyAnnotation] = "true"
+}
+
+// UsesKey checks if the given command uses the cluster selected with the '--cluster' flag.
+func UsesKey(cmd *cobra.Command) bool {
+	return cmd.Annotations[keyAnnotation] == "true"
+}
+
+// Key returns the cluster given with the '--cluster' flag, without taking into account the default
+// cluster.
+func Key() string {
+	return key
+}
+
+// GetDefaultKey returns the default cluster of the configuration file, or an empty string if there
+// is none.
+func GetDefaultKey() (string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return "", err
+	}
+	return cfg.Cluster, nil
+}
+
+// SaveDefaultKey saves the default cluster in the configuration file, or removes it if the given
+// key is empty.
+func SaveDefaultKey(clusterKey string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	cfg.Cluster = clusterKey
+	return config.Save(cfg)
+}
+
+// GetKey returns the cluster that the command should use: the one given with the '--cluster'
+// flag or, for the commands that accept the cluster as their only positional argument, the one
+// given in the argv parameter. Other commands pass nil. When neither is given the default cluster
+// of the configuration file is used, and if there is none the user selects the cluster from a list
+// in interactive terminals. It fails with a usage error if there is no cluster or if the key isn't
+// valid.
+func GetKey(r *runtime.Runtime, argv []string) (string, error) {
+	if len(argv) > 1 {
+		return "", rosaerrors.Usagef("Expected at most one command line argument containing the " +
+			"name or identifier of the cluster")
+	}
+	clusterKey := key
+	if clusterKey == "" && len(argv) == 1 {
+		clusterKey = argv[0]
+	}
+	if clusterKey == "" {
+		defaultKey, err := GetDefaultKey()
+		if err != nil {
+			return "", fmt.Errorf("Failed to load default cluster: %v", err)
+		}
+		if defaultKey != "" {
+			r.Reporter().Debugf("Using default cluster '%s'", defaultKey)
+			clusterKey = defaultKey
+		}
+	}
+	if clusterKey == "" {
+		if !interactive.IsTerminal() {
+			return "", rosaerrors.Usagef("Option '--cluster' is mandatory, or set a default " +
+				"cluster with 'rosa config set cluster'")
+		}
+		selected, err := SelectCluster(r)
+		if err != nil {
+			return "", err
+		}
+		clusterKey = selected
+	}
+
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	if !IsValidClusterKey(clusterKey) {
+		return "", rosaerrors.Usagef("Cluster name, identifier or external identifier '%s' isn't "+
+			"valid: it must contain only letters, digits, dashes and underscores", clusterKey)
+	}
+	return clusterKey, nil
+}
@@ -0,0 +1,58 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// SelectCluster prompts the user to select one of their clusters, showing the name, region and
+// state of each one and filtering them as the user types. It returns the identifier of the selected
+// cluster.
+func SelectCluster(r *runtime.Runtime) (string, error) {
+	clusters, err := r.OCM().GetClusters(r.Creator().ARN, 100)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get clusters: %w", err)
+	}
+	if len(clusters) == 0 {
+		return "", rosaerrors.NotFoundf("There are no clusters available")
+	}
+
+	options := make([]string, len(clusters))
+	optionIDs := make(map[string]string, len(clusters))
+	for i, cluster := range clusters {
+		options[i] = fmt.Sprintf("%-15s  %-14s  %s", cluster.Name(), cluster.Region().ID(), cluster.State())
+		optionIDs[options[i]] = cluster.ID()
+	}
+
+	option, err := interactive.GetOption(interactive.Input{
+		Question: "Cluster",
+		Help:     "Type to search clusters by name, region or state.",
+		Options:  options,
+		Required: true,
+		Fuzzy:    true,
+	})
+	if err != nil {
+		return "", rosaerrors.Usagef("Expected a valid cluster: %w", err)
+	}
+
+	return optionIDs[option], nil
+}
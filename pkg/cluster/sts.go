@@ -0,0 +1,121 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the types and functions of the clusters that use AWS STS: instead of the
+// access keys of the admin user they get short lived credentials assuming IAM roles.
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/openshift/moactl/pkg/ocm"
+)
+
+// STS contains the roles that a cluster assumes. The URL of the OIDC endpoint is set by OCM when
+// the cluster is created, and it is the issuer that the operator roles trust.
+type STS struct {
+	RoleARN             string
+	SupportRoleARN      string
+	ControlPlaneRoleARN string
+	WorkerRoleARN       string
+	OperatorRoles       []*OperatorIAMRole
+	OIDCEndpointURL     string
+}
+
+// OperatorIAMRole is the role assumed by the operator that reads its credentials from the secret
+// with the given name and namespace.
+type OperatorIAMRole struct {
+	Namespace string
+	Name      string
+	RoleARN   string
+}
+
+// stsJSON is the representation of the STS details in the AWS details of the cluster.
+type stsJSON struct {
+	RoleARN          string `json:"role_arn,omitempty"`
+	SupportRoleARN   string `json:"support_role_arn,omitempty"`
+	OIDCEndpointURL  string `json:"oidc_endpoint_url,omitempty"`
+	InstanceIAMRoles struct {
+		MasterRoleARN string `json:"master_role_arn,omitempty"`
+		WorkerRoleARN string `json:"worker_role_arn,omitempty"`
+	} `json:"instance_iam_roles"`
+	OperatorIAMRoles []operatorIAMRoleJSON `json:"operator_iam_roles,omitempty"`
+}
+
+type operatorIAMRoleJSON struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	RoleARN   string `json:"role_arn"`
+}
+
+func (s *STS) toJSON() *stsJSON {
+	result := &stsJSON{
+		RoleARN:        s.RoleARN,
+		SupportRoleARN: s.SupportRoleARN,
+	}
+	result.InstanceIAMRoles.MasterRoleARN = s.ControlPlaneRoleARN
+	result.InstanceIAMRoles.WorkerRoleARN = s.WorkerRoleARN
+	for _, role := range s.OperatorRoles {
+		result.OperatorIAMRoles = append(result.OperatorIAMRoles, operatorIAMRoleJSON{
+			Namespace: role.Namespace,
+			Name:      role.Name,
+			RoleARN:   role.RoleARN,
+		})
+	}
+	return result
+}
+
+func (s *stsJSON) toSTS() *STS {
+	result := &STS{
+		RoleARN:             s.RoleARN,
+		SupportRoleARN:      s.SupportRoleARN,
+		ControlPlaneRoleARN: s.InstanceIAMRoles.MasterRoleARN,
+		WorkerRoleARN:       s.InstanceIAMRoles.WorkerRoleARN,
+		OIDCEndpointURL:     s.OIDCEndpointURL,
+	}
+	for _, role := range s.OperatorIAMRoles {
+		result.OperatorRoles = append(result.OperatorRoles, &OperatorIAMRole{
+			Namespace: role.Namespace,
+			Name:      role.Name,
+			RoleARN:   role.RoleARN,
+		})
+	}
+	return result
+}
+
+// GetSTS returns the STS details of the cluster with the given identifier, or nil if the cluster
+// doesn't use AWS STS.
+func GetSTS(client ocm.Client, clusterID string) (*STS, error) {
+	data, err := client.GetClusterDocument(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	var body struct {
+		AWS struct {
+			STS *stsJSON `json:"sts"`
+		} `json:"aws"`
+	}
+	err = json.Unmarshal(data, &body)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse cluster: %v", err)
+	}
+	if body.AWS.STS == nil || body.AWS.STS.RoleARN == "" {
+		return nil, nil
+	}
+	return body.AWS.STS.toSTS(), nil
+}
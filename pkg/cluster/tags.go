@@ -0,0 +1,41 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"encoding/json"
+
+	"github.com/openshift/moactl/pkg/ocm"
+)
+
+// GetTags returns the tags added to the AWS resources of the cluster.
+func GetTags(client ocm.Client, clusterID string) (map[string]string, error) {
+	data, err := client.GetClusterDocument(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	var body struct {
+		AWS struct {
+			Tags map[string]string `json:"tags"`
+		} `json:"aws"`
+	}
+	err = json.Unmarshal(data, &body)
+	if err != nil {
+		return nil, err
+	}
+	return body.AWS.Tags, nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/aws"
+)
+
+// Remediation describes how to fix a known cause of a failed install, and the command that checks
+// that the cause has been fixed.
+type Remediation struct {
+	Cause   string
+	Fix     string
+	Command string
+}
+
+// remediation is a known cause of failed installs. It is recognized either by the provisioning
+// error code reported by OCM, or by one of the fragments of the error message, as older clusters
+// don't always have a specific code.
+type remediation struct {
+	codes     []string
+	fragments []string
+	cause     string
+	fix       string
+	// command is the verify command, with '%[1]s' replaced by the cluster key and '%[2]s' by the
+	// region of the cluster.
+	command string
+}
+
+// The service linked role is checked before the permissions, as its errors also mention them:
+var remediations = []remediation{
+	{
+		codes:     []string{"OCM3003"},
+		fragments: []string{"quota", "limitexceeded", "vcpulimitexceeded"},
+		cause:     "The AWS account doesn't have enough quota for the cluster",
+		fix:       "Request a quota increase in the Service Quotas console of the region",
+		command:   "rosa verify quota --region=%[2]s",
+	},
+	{
+		codes:     []string{"OCM3006"},
+		fragments: []string{"awsserviceroleforelasticloadbalancing", "service linked role"},
+		cause:     "The service linked role of Elastic Load Balancing doesn't exist",
+		fix:       "Create the role with '" + aws.ELBServiceLinkedRoleCommand + "'",
+		command:   "rosa verify permissions --region=%[2]s",
+	},
+	{
+		codes:     []string{"OCM3004", "OCM3026"},
+		fragments: []string{"accessdenied", "unauthorizedoperation", "not authorized", "permission"},
+		cause:     "The credentials used by the installer don't have the required permissions",
+		fix:       "Check the policies of the osdCcsAdmin user and the service control policies of the organization",
+		command:   "rosa verify permissions --region=%[2]s",
+	},
+	{
+		codes:     []string{"OCM3005"},
+		fragments: []string{"egress", "dial tcp", "i/o timeout", "connection refused"},
+		cause:     "The cluster can't reach the endpoints that it needs during install",
+		fix:       "Allow outbound traffic to the required endpoints in the firewall or proxy",
+		command:   "rosa verify network --region=%[2]s",
+	},
+	{
+		codes:     []string{"OCM3007"},
+		fragments: []string{"dns", "hosted zone", "route53"},
+		cause:     "The DNS records of the cluster couldn't be created or resolved",
+		fix:       "Check that the VPC has DNS hostnames and DNS resolution enabled",
+		command:   "rosa describe cluster --cluster=%[1]s --endpoints",
+	},
+}
+
+// TriageProvisionError returns the remediation of the provisioning error of the given cluster, or
+// nil if the cluster has no provisioning error. Errors that aren't known get a generic remediation
+// that points to the install logs.
+func TriageProvisionError(cluster *cmv1.Cluster) *Remediation {
+	code := cluster.Status().ProvisionErrorCode()
+	message := cluster.Status().ProvisionErrorMessage()
+	if code == "" && message == "" {
+		return nil
+	}
+
+	match := func(candidate remediation) *Remediation {
+		return &Remediation{
+			Cause:   candidate.cause,
+			Fix:     candidate.fix,
+			Command: fmt.Sprintf(candidate.command, cluster.Name(), cluster.Region().ID()),
+		}
+	}
+	// Codes are more precise than messages, so check all of them first:
+	for _, candidate := range remediations {
+		for _, known := range candidate.codes {
+			if strings.EqualFold(code, known) {
+				return match(candidate)
+			}
+		}
+	}
+	lower := strings.ToLower(message)
+	for _, candidate := range remediations {
+		for _, fragment := range candidate.fragments {
+			if strings.Contains(lower, fragment) {
+				return match(candidate)
+			}
+		}
+	}
+
+	return &Remediation{
+		Cause:   "The cause of the error isn't known",
+		Fix:     "Check the install logs for the details of the error",
+		Command: fmt.Sprintf("rosa logs install --cluster=%s", cluster.Name()),
+	}
+}
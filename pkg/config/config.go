@@ -0,0 +1,290 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package config loads the configuration file of rosa itself, '~/.config/rosa/config.yaml', which
+// contains the preferences of the user, like the default cluster, the cluster groups and the
+// hooks, and named profiles with the defaults of the commands, like the OCM environment, the AWS
+// profile, the region and the output format. The profile is selected with the '--profile' flag or
+// the 'ROSA_PROFILE' environment variable, and the profile named 'default' is used when none is
+// selected. The credentials aren't part of this file, they are kept in the OCM configuration file,
+// so logging out doesn't change the preferences.
+//
+// The values of the profile are the last resort before the built-in defaults: packages that own a
+// setting check first their flag and their environment variable, and only then the profile.
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/openshift/moactl/pkg/simulate"
+)
+
+// DefaultProfile is the name of the profile used when none is selected.
+const DefaultProfile = "default"
+
+// profileEnv is the environment variable that selects the profile.
+const profileEnv = "ROSA_PROFILE"
+
+// File is the content of the configuration file.
+type File struct {
+	// Cluster is the name or identifier of the cluster used by the commands when the
+	// '--cluster' flag isn't given.
+	Cluster string `yaml:"cluster,omitempty"`
+
+	// ClusterGroups maps the names of groups of clusters to the OCM search expressions that
+	// select them.
+	ClusterGroups map[string]string `yaml:"cluster_groups,omitempty"`
+
+	// Hooks are shell commands that run before or after some of the commands of the tool.
+	Hooks []*Hook `yaml:"hooks,omitempty"`
+
+	// Safety controls how destructive operations are confirmed.
+	Safety *Safety `yaml:"safety,omitempty"`
+
+	// Tracing contains the settings of the export of traces of the commands.
+	Tracing *Tracing `yaml:"tracing,omitempty"`
+
+	// Update contains the settings of the updates of the tool.
+	Update *Update `yaml:"update,omitempty"`
+
+	Profiles map[string]*Profile `yaml:"profiles,omitempty"`
+}
+
+// Update contains the settings of the updates of the tool. The channel is 'stable', the default,
+// to update to the newest release, 'latest' to include pre-releases, or a minor version, like
+// '1.2', to update only to the patch releases of that minor version. The commands check once a
+// day if the tool is too old, unless the check is disabled.
+type Update struct {
+	Channel      string `yaml:"channel,omitempty"`
+	DisableCheck bool   `yaml:"disable_check,omitempty"`
+}
+
+// Tracing contains the settings of the export of traces of the commands. The endpoint is the URL
+// where the OTLP collector receives traces, for example 'http://localhost:4318/v1/traces'.
+type Tracing struct {
+	Endpoint string            `yaml:"endpoint,omitempty"`
+	Headers  map[string]string `yaml:"headers,omitempty"`
+}
+
+// Safety contains the settings that control how destructive operations are confirmed. The level
+// is 'low' to answer yes for all clusters, 'medium' to type the name of clusters tagged as
+// production, which is the default, or 'high' to type the name of all clusters.
+type Safety struct {
+	Level string `yaml:"level,omitempty"`
+}
+
+// Hook is a shell command that runs before or after the commands whose names start with the
+// given command, for example 'delete cluster'.
+type Hook struct {
+	Command string `yaml:"command"`
+	Pre     string `yaml:"pre,omitempty"`
+	Post    string `yaml:"post,omitempty"`
+}
+
+// Profile contains the defaults of the commands. Empty values mean that the built-in default
+// should be used.
+type Profile struct {
+	// Environment is the OCM environment used by the commands when '--env' isn't given, either
+	// an alias like 'staging' or the complete URL of the API.
+	Environment string `yaml:"environment,omitempty"`
+
+	// AWSProfile is the profile of the AWS credentials file.
+	AWSProfile string `yaml:"aws_profile,omitempty"`
+
+	// Region is the AWS region used when the commands don't get one with '--region'.
+	Region string `yaml:"region,omitempty"`
+
+	// Output is the format used by the commands that support '--output', 'json' or 'yaml'.
+	Output string `yaml:"output,omitempty"`
+
+	// CAFile is the file of additional certificate authorities used when '--ca-file' isn't given.
+	CAFile string `yaml:"ca_file,omitempty"`
+}
+
+// Location returns the location of the configuration file, '~/.config/rosa/config.yaml' in Linux.
+func Location() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "rosa", "config.yaml"), nil
+}
+
+// Load reads the configuration file. It returns an empty configuration if the file doesn't exist.
+// The file is read only once, so later calls return the same result.
+func Load() (*File, error) {
+	if loaded == nil && loadErr == nil {
+		loaded, loadErr = load()
+	}
+	return loaded, loadErr
+}
+
+func load() (*File, error) {
+	location, err := Location()
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(location)
+	if os.IsNotExist(err) {
+		return &File{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	file := &File{}
+	err = yaml.UnmarshalStrict(data, file)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse '%s': %v", location, err)
+	}
+	for name, profile := range file.Profiles {
+		if profile == nil {
+			file.Profiles[name] = &Profile{}
+			continue
+		}
+		if profile.Output != "" && profile.Output != "json" && profile.Output != "yaml" {
+			return nil, fmt.Errorf("Output format '%s' of profile '%s' in '%s' isn't valid, it "+
+				"must be 'json' or 'yaml'", profile.Output, name, location)
+		}
+	}
+	return file, nil
+}
+
+// Save writes the given configuration to the configuration file, creating its directory if needed.
+// Comments of a file edited by hand aren't preserved. In simulation mode the configuration is only
+// kept in memory, so that the preferences of the user aren't changed.
+func Save(file *File) error {
+	loaded, loadErr = file, nil
+	if simulate.Enabled() {
+		return nil
+	}
+	location, err := Location()
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal configuration: %v", err)
+	}
+	err = os.MkdirAll(filepath.Dir(location), 0700)
+	if err != nil {
+		return fmt.Errorf("Failed to create directory of '%s': %v", location, err)
+	}
+	err = ioutil.WriteFile(location, data, 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to write file '%s': %v", location, err)
+	}
+	return nil
+}
+
+// Validate checks that the configuration file can be loaded and that the profile selected with the
+// 'ROSA_PROFILE' environment variable exists. A profile given with the '--profile' flag that
+// doesn't exist isn't an error, as the flag then selects only the AWS profile, like it always did.
+// It is intended to be called once the command line has been parsed.
+func Validate() error {
+	file, err := Load()
+	if err != nil {
+		return err
+	}
+	name := os.Getenv(profileEnv)
+	if name == "" || IsProfile(flag) {
+		return nil
+	}
+	if _, ok := file.Profiles[name]; !ok {
+		names := make([]string, 0, len(file.Profiles))
+		for item := range file.Profiles {
+			names = append(names, item)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return fmt.Errorf("Profile '%s' selected with '%s' doesn't exist, there are no "+
+				"profiles in the configuration file", name, profileEnv)
+		}
+		return fmt.Errorf("Profile '%s' selected with '%s' doesn't exist, the profiles are '%s'",
+			name, profileEnv, strings.Join(names, "', '"))
+	}
+	return nil
+}
+
+// Name returns the name of the selected profile, or an empty string if no profile is in use. A
+// '--profile' flag that isn't the name of a profile selects only the AWS profile, so the profile is
+// then selected as if the flag hadn't been used.
+func Name() string {
+	file, err := Load()
+	if err != nil {
+		return ""
+	}
+	if _, ok := file.Profiles[flag]; ok && flag != "" {
+		return flag
+	}
+	if name := os.Getenv(profileEnv); name != "" {
+		if _, ok := file.Profiles[name]; ok {
+			return name
+		}
+		return ""
+	}
+	if _, ok := file.Profiles[DefaultProfile]; ok {
+		return DefaultProfile
+	}
+	return ""
+}
+
+// Current returns the selected profile. It returns an empty profile if no profile is in use, so the
+// result is never nil.
+func Current() *Profile {
+	name := Name()
+	if name == "" {
+		return &Profile{}
+	}
+	return loaded.Profiles[name]
+}
+
+// Environment returns the OCM environment of the selected profile.
+func Environment() string {
+	return Current().Environment
+}
+
+// AWSProfile returns the AWS profile of the selected profile.
+func AWSProfile() string {
+	return Current().AWSProfile
+}
+
+// Region returns the AWS region of the selected profile.
+func Region() string {
+	return Current().Region
+}
+
+// Output returns the output format of the selected profile.
+func Output() string {
+	return Current().Output
+}
+
+// CAFile returns the file of additional certificate authorities of the selected profile.
+func CAFile() string {
+	return Current().CAFile
+}
+
+// loaded and loadErr are the result of loading the configuration file.
+var (
+	loaded  *File
+	loadErr error
+)
@@ -0,0 +1,53 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the '--profile' flag, which selects a profile of the configuration file or,
+// when there is no profile with that name, an AWS profile.
+
+package config
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// AddFlag adds the '--profile' flag to the given set of command line flags.
+func AddFlag(fs *pflag.FlagSet) {
+	fs.StringVar(
+		&flag,
+		"profile",
+		"",
+		"Use a profile of '~/.config/rosa/config.yaml', or a specific AWS profile from your "+
+			"credential file if there is no profile with that name.",
+	)
+}
+
+// Flag returns the value of the '--profile' flag.
+func Flag() string {
+	return flag
+}
+
+// IsProfile checks if the given name is the name of a profile of the configuration file.
+func IsProfile(name string) bool {
+	file, err := Load()
+	if err != nil {
+		return false
+	}
+	_, ok := file.Profiles[name]
+	return ok
+}
+
+// flag is the value of the '--profile' flag.
+var flag string
@@ -21,11 +21,27 @@ import (
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/spf13/pflag"
+
+	"github.com/openshift/moactl/pkg/ci"
+	"github.com/openshift/moactl/pkg/config"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
+	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/reporter"
+)
+
+// Safety levels that control when destructive operations require typing the name of the resource
+// instead of answering yes:
+const (
+	SafetyLevelLow    = "low"
+	SafetyLevelMedium = "medium"
+	SafetyLevelHigh   = "high"
 )
 
 var yes bool
 
-// AddFlag adds the --yes flag to the given set of command line flags.
+// AddFlag adds the --yes flag to the given set of command line flags. It is added once to the
+// persistent flags of the root command, so that it is available for all the commands.
 func AddFlag(flags *pflag.FlagSet) {
 	flags.BoolVarP(
 		&yes,
@@ -36,10 +52,18 @@ func AddFlag(flags *pflag.FlagSet) {
 	)
 }
 
+// Confirm asks the user to confirm the operation described by the given format and arguments,
+// unless the '--yes' flag was given. When the standard input isn't a terminal there is nobody to
+// ask, so the tool exits asking for '--yes' instead of running or skipping the operation silently.
 func Confirm(q string, v ...interface{}) bool {
 	if yes {
 		return yes
 	}
+	// Operations aren't confirmed in CI mode unless '--yes' is used, as there is nobody to ask:
+	if ci.Enabled() {
+		return false
+	}
+	requireTerminal(q, v...)
 	prompt := &survey.Confirm{
 		Message: fmt.Sprintf("Are you sure you want to %s?", fmt.Sprintf(q, v...)),
 		Default: false,
@@ -47,3 +71,64 @@ func Confirm(q string, v ...interface{}) bool {
 	survey.AskOne(prompt, &yes, survey.WithValidator(survey.Required))
 	return yes
 }
+
+// SafetyLevel returns the safety level of the configuration file. Unknown levels are treated as
+// the highest one, so that a typo doesn't disable the protection.
+func SafetyLevel() string {
+	cfg, err := config.Load()
+	if err != nil || cfg.Safety == nil || cfg.Safety.Level == "" {
+		return SafetyLevelMedium
+	}
+	switch cfg.Safety.Level {
+	case SafetyLevelLow, SafetyLevelMedium, SafetyLevelHigh:
+		return cfg.Safety.Level
+	default:
+		return SafetyLevelHigh
+	}
+}
+
+// NameRequired checks if the safety level requires typing the name of the resource to confirm a
+// destructive operation on it.
+func NameRequired(production bool) bool {
+	switch SafetyLevel() {
+	case SafetyLevelLow:
+		return false
+	case SafetyLevelMedium:
+		return production
+	default:
+		return true
+	}
+}
+
+// ConfirmName asks the user to confirm the operation by typing the given name, instead of answering
+// yes, so that operations aren't confirmed by habit.
+func ConfirmName(name string, q string, v ...interface{}) bool {
+	if yes {
+		return yes
+	}
+	if ci.Enabled() {
+		return false
+	}
+	requireTerminal(q, v...)
+	var answer string
+	prompt := &survey.Input{
+		Message: fmt.Sprintf("Are you sure you want to %s? Type '%s' to confirm:", fmt.Sprintf(q, v...), name),
+	}
+	err := survey.AskOne(prompt, &answer)
+	if err != nil {
+		return false
+	}
+	return answer == name
+}
+
+// requireTerminal exits with an error if the user can't be asked to confirm the operation because
+// the standard input isn't a terminal.
+func requireTerminal(q string, v ...interface{}) {
+	if interactive.IsInputTerminal() {
+		return
+	}
+	reporter := reporter.CreateReporterOrExit()
+	reporter.Errorf("Refusing to %s without confirmation, as the standard input isn't a "+
+		"terminal. Use the '--yes' option to confirm it", fmt.Sprintf(q, v...))
+	exit.Exit(rosaerrors.ExitUsage)
+}
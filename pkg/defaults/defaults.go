@@ -0,0 +1,189 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the defaults and limits of the number of compute nodes of clusters and of the
+// size of their root disks. The
+// built-in values can be changed for an organization with capabilities in OCM, so commands should
+// get them from here instead of using their own numbers. The cluster settings that organizations
+// set with labels are in policies.go.
+
+package defaults
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+
+	"github.com/openshift/moactl/pkg/ocm/raw"
+)
+
+// Names of the capabilities of the organization that override the built-in values:
+const (
+	singleAZDefaultCapability = "capability.cluster.compute_nodes_single_az_default"
+	singleAZMinCapability     = "capability.cluster.compute_nodes_single_az_min"
+	multiAZDefaultCapability  = "capability.cluster.compute_nodes_multi_az_default"
+	multiAZMinCapability      = "capability.cluster.compute_nodes_multi_az_min"
+	dualStackCapability       = "capability.cluster.dual_stack"
+	diskSizeMinCapability     = "capability.cluster.worker_disk_size_min"
+	diskSizeMaxCapability     = "capability.cluster.worker_disk_size_max"
+)
+
+// ComputeNodes contains the default and the minimum number of compute nodes of a cluster.
+type ComputeNodes struct {
+	Default int
+	Min     int
+}
+
+// Validate checks that the given number of compute nodes isn't below the minimum.
+func (n ComputeNodes) Validate(nodes int) error {
+	if nodes < n.Min {
+		return fmt.Errorf("Number of compute nodes %d isn't valid: it must be at least %d", nodes, n.Min)
+	}
+	return nil
+}
+
+// DiskSize contains the limits, in GiB, of the size of the root disk of worker nodes.
+type DiskSize struct {
+	Min int
+	Max int
+}
+
+// Validate checks that the given size, in GiB, is within the limits.
+func (s DiskSize) Validate(size int) error {
+	if size < s.Min || size > s.Max {
+		return fmt.Errorf("Worker disk size %d GiB isn't valid: it must be between %d GiB and %d GiB",
+			size, s.Min, s.Max)
+	}
+	return nil
+}
+
+// Defaults contains the number of compute nodes of single and multi-AZ clusters, the limits of the
+// size of the root disk of worker nodes, and the cluster settings of the organization.
+type Defaults struct {
+	SingleAZ ComputeNodes
+	MultiAZ  ComputeNodes
+
+	WorkerDiskSize DiskSize
+
+	// DualStack is true when OCM can install clusters with IPv6 addresses in addition to the
+	// IPv4 ones for the organization.
+	DualStack bool
+
+	// Organization is the name of the organization that the values were loaded for, so that
+	// commands can tell users where the values that they didn't choose come from.
+	Organization string
+
+	Policies
+}
+
+// Builtin returns the values that are used when the organization doesn't have capabilities that
+// change them.
+func Builtin() *Defaults {
+	return &Defaults{
+		SingleAZ: ComputeNodes{Default: 2, Min: 2},
+		MultiAZ:  ComputeNodes{Default: 3, Min: 3},
+
+		WorkerDiskSize: DiskSize{Min: 128, Max: 16384},
+	}
+}
+
+// ComputeNodes returns the number of compute nodes for single or multi-AZ clusters.
+func (d *Defaults) ComputeNodes(multiAZ bool) ComputeNodes {
+	if multiAZ {
+		return d.MultiAZ
+	}
+	return d.SingleAZ
+}
+
+// Load returns the values for the organization of the current account, applying its capabilities
+// to the built-in values.
+func Load(connection *sdk.Connection) (*Defaults, error) {
+	account, err := connection.AccountsMgmt().V1().CurrentAccount().Get().Send()
+	if err != nil {
+		return nil, err
+	}
+	organizationID := account.Body().Organization().ID()
+
+	// The capabilities and labels of the organization aren't supported by the SDK yet:
+	response, err := raw.Send(
+		connection.Get().
+			Path(fmt.Sprintf("/api/accounts_mgmt/v1/organizations/%s", organizationID)).
+			Parameter("fetchCapabilities", true).
+			Parameter("fetchLabels", true),
+		http.StatusOK,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var body struct {
+		Name         string `json:"name"`
+		Capabilities []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"capabilities"`
+		Labels []struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		} `json:"labels"`
+	}
+	err = json.Unmarshal(response.Bytes(), &body)
+	if err != nil {
+		return nil, err
+	}
+
+	result := Builtin()
+	targets := map[string]*int{
+		singleAZDefaultCapability: &result.SingleAZ.Default,
+		singleAZMinCapability:     &result.SingleAZ.Min,
+		multiAZDefaultCapability:  &result.MultiAZ.Default,
+		multiAZMinCapability:      &result.MultiAZ.Min,
+		diskSizeMinCapability:     &result.WorkerDiskSize.Min,
+		diskSizeMaxCapability:     &result.WorkerDiskSize.Max,
+	}
+	for _, capability := range body.Capabilities {
+		if capability.Name == dualStackCapability {
+			result.DualStack = capability.Value == "true"
+			continue
+		}
+		target, ok := targets[capability.Name]
+		if !ok {
+			continue
+		}
+		value, err := strconv.Atoi(capability.Value)
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("Value '%s' of capability '%s' isn't a valid number",
+				capability.Value, capability.Name)
+		}
+		*target = value
+	}
+
+	result.Organization = body.Name
+	if result.Organization == "" {
+		result.Organization = organizationID
+	}
+	labels := map[string]string{}
+	for _, label := range body.Labels {
+		labels[label.Key] = label.Value
+	}
+	result.Policies, err = parsePolicies(labels)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the cluster settings that an organization can enforce or suggest for all the
+// clusters that its users create, by adding labels to the organization in OCM.
+
+package defaults
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Keys of the labels of the organization that contain the settings:
+const (
+	channelGroupLabel   = "rosa.default_channel_group"
+	allowedRegionsLabel = "rosa.allowed_regions"
+	requiredTagsLabel   = "rosa.required_tags"
+)
+
+// Policies contains the cluster settings of an organization. The fields are empty when the
+// organization doesn't have the corresponding label.
+type Policies struct {
+	// ChannelGroup is used instead of the default channel group.
+	ChannelGroup string
+
+	// AllowedRegions are the only regions where clusters can be created.
+	AllowedRegions []string
+
+	// RequiredTags are the keys of the tags that all clusters need. Tags with a value are added
+	// with that value when the user doesn't give them, tags without a value have to be given.
+	RequiredTags map[string]string
+}
+
+// parsePolicies extracts the settings from the given labels. The regions and the tags are comma
+// separated lists, and each tag is a key, optionally followed by a colon and a default value,
+// like 'CostCenter:1234'.
+func parsePolicies(labels map[string]string) (result Policies, err error) {
+	result.ChannelGroup = strings.TrimSpace(labels[channelGroupLabel])
+	for _, region := range strings.Split(labels[allowedRegionsLabel], ",") {
+		region = strings.TrimSpace(region)
+		if region != "" {
+			result.AllowedRegions = append(result.AllowedRegions, region)
+		}
+	}
+	for _, tag := range strings.Split(labels[requiredTagsLabel], ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		key, value := tag, ""
+		if colon := strings.Index(tag, ":"); colon >= 0 {
+			key = strings.TrimSpace(tag[:colon])
+			value = strings.TrimSpace(tag[colon+1:])
+		}
+		if key == "" {
+			err = fmt.Errorf("Value '%s' of label '%s' isn't valid: tag '%s' doesn't have a key",
+				labels[requiredTagsLabel], requiredTagsLabel, tag)
+			return
+		}
+		if result.RequiredTags == nil {
+			result.RequiredTags = map[string]string{}
+		}
+		result.RequiredTags[key] = value
+	}
+	return
+}
+
+// ValidateRegion checks that clusters can be created in the given region.
+func (d *Defaults) ValidateRegion(region string) error {
+	if len(d.AllowedRegions) == 0 {
+		return nil
+	}
+	for _, allowed := range d.AllowedRegions {
+		if region == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("Region '%s' isn't allowed by organization '%s', clusters can only be created in "+
+		"regions '%s'", region, d.Organization, strings.Join(d.AllowedRegions, "', '"))
+}
+
+// FilterRegions returns the given regions that clusters can be created in.
+func (d *Defaults) FilterRegions(regions []string) []string {
+	if len(d.AllowedRegions) == 0 {
+		return regions
+	}
+	result := []string{}
+	for _, region := range regions {
+		if d.ValidateRegion(region) == nil {
+			result = append(result, region)
+		}
+	}
+	return result
+}
+
+// ApplyRequiredTags adds to the given tags the required tags that have a default value and that
+// the user didn't give, and returns their keys sorted. It fails if a required tag without default
+// value is missing.
+func (d *Defaults) ApplyRequiredTags(tags map[string]string) (added []string, err error) {
+	missing := []string{}
+	for key, value := range d.RequiredTags {
+		if _, ok := tags[key]; ok {
+			continue
+		}
+		if value == "" {
+			missing = append(missing, key)
+			continue
+		}
+		tags[key] = value
+		added = append(added, key)
+	}
+	sort.Strings(added)
+	sort.Strings(missing)
+	if len(missing) > 0 {
+		err = fmt.Errorf("Organization '%s' requires tags '%s', add them with '--tags'", d.Organization,
+			strings.Join(missing, "', '"))
+	}
+	return
+}
@@ -0,0 +1,70 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to keep accepting command line flags that have been
+// replaced by new ones, warning the user about the replacement.
+
+package deprecation
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// Flag describes a command line flag that has been replaced by a new one.
+type Flag struct {
+	// Command that the flag belongs to.
+	Command *cobra.Command
+
+	// Name of the deprecated flag and of the flag that replaces it.
+	Name        string
+	Replacement string
+
+	// Version of the tool where the deprecated flag will stop being accepted.
+	RemovedIn string
+}
+
+// flags contains all the deprecated flags registered with the AddFlag function.
+var flags []*Flag
+
+// AddFlag adds to the given command a flag with the given name and shorthand that sets the same
+// value as the replacement flag. The replacement flag must already be defined. The deprecated flag
+// is hidden from the help and using it prints a warning explaining which flag should be used
+// instead and in which version the deprecated flag will be removed.
+func AddFlag(cmd *cobra.Command, name string, shorthand string, replacement string, removedIn string) {
+	fs := cmd.Flags()
+	flag := fs.Lookup(replacement)
+	if flag == nil {
+		panic(fmt.Sprintf("Replacement flag '%s' for deprecated flag '%s' doesn't exist", replacement, name))
+	}
+	fs.VarP(flag.Value, name, shorthand, flag.Usage)
+	fs.MarkDeprecated(
+		name,
+		fmt.Sprintf("use --%s instead. Flag --%s will be removed in version %s", replacement, name, removedIn),
+	)
+	flags = append(flags, &Flag{
+		Command:     cmd,
+		Name:        name,
+		Replacement: replacement,
+		RemovedIn:   removedIn,
+	})
+}
+
+// Flags returns the list of deprecated flags that are still accepted.
+func Flags() []*Flag {
+	return flags
+}
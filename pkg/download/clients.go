@@ -0,0 +1,167 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that download the OpenShift client tools, like 'oc' and
+// 'openshift-install', from the official mirror.
+
+package download
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+)
+
+// MirrorURL is the address of the directory of the official mirror that contains the client tools
+// of each version of OpenShift.
+const MirrorURL = "https://mirror.openshift.com/pub/openshift-v4/clients/ocp"
+
+// LatestVersion is the directory of the mirror that contains the newest client tools.
+const LatestVersion = "latest"
+
+// Names of the files of the mirror that contain the checksums of the archives and their signature:
+const (
+	checksumsFile = "sha256sum.txt"
+	signatureFile = "sha256sum.txt.gpg"
+)
+
+// Tool describes a client tool published in the mirror.
+type Tool struct {
+	// Name is the prefix of the name of the archive, like 'openshift-client'.
+	Name string
+
+	// Binaries are the names of the files of the archive that are installed, without the '.exe'
+	// extension used in Windows.
+	Binaries []string
+}
+
+// Client tools that can be downloaded:
+var (
+	OpenShiftClient = &Tool{Name: "openshift-client", Binaries: []string{"oc", "kubectl"}}
+	Installer       = &Tool{Name: "openshift-install", Binaries: []string{"openshift-install"}}
+)
+
+// Options control how the client tools are downloaded.
+type Options struct {
+	// Version is the version of OpenShift, like '4.10.3', or 'latest' for the newest one.
+	Version string
+
+	// Dir is the directory where the binaries are installed.
+	Dir string
+
+	// Keyring is the file that contains the keys used to verify the signature of the checksums.
+	// When it is empty the default keyring is used if it exists, otherwise the signature isn't
+	// verified.
+	Keyring string
+}
+
+// ArchiveName returns the name of the archive of the tool for the current platform.
+func (t *Tool) ArchiveName() (string, error) {
+	platform := runtime.GOOS
+	extension := "tar.gz"
+	switch runtime.GOOS {
+	case "darwin":
+		platform = "mac"
+	case "windows":
+		if t == Installer {
+			return "", fmt.Errorf("Tool '%s' isn't available for Windows", t.Name)
+		}
+		extension = "zip"
+	}
+	if runtime.GOARCH == "arm64" {
+		platform += "-arm64"
+	}
+	return fmt.Sprintf("%s-%s.%s", t.Name, platform, extension), nil
+}
+
+// Install downloads the archive of the tool from the mirror, verifies its checksum, and the
+// signature of the checksums if there is a keyring, and installs its binaries in the directory of
+// the options. It returns the paths of the installed binaries.
+func (t *Tool) Install(reporter *rprtr.Object, options Options) ([]string, error) {
+	version := options.Version
+	if version == "" {
+		version = LatestVersion
+	}
+	archiveName, err := t.ArchiveName()
+	if err != nil {
+		return nil, err
+	}
+	baseURL := fmt.Sprintf("%s/%s", MirrorURL, version)
+
+	reporter.Debugf("Loading checksums of version '%s'", version)
+	checksums, err := Get(baseURL + "/" + checksumsFile)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get checksums of version '%s': %v", version, err)
+	}
+	keyring := options.Keyring
+	if keyring == "" {
+		if _, err := os.Stat(DefaultKeyring); err == nil {
+			keyring = DefaultKeyring
+		}
+	}
+	if keyring != "" {
+		signature, err := Get(baseURL + "/" + signatureFile)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get signature of checksums: %v", err)
+		}
+		err = VerifySignature(checksums, signature, keyring)
+		if err != nil {
+			return nil, err
+		}
+		reporter.Infof("Verified signature of checksums with keyring '%s'", keyring)
+	} else {
+		reporter.Warnf("Signature of checksums isn't verified, use '--keyring' to give the file " +
+			"that contains the Red Hat release keys")
+	}
+	checksum, ok := ParseChecksums(checksums)[archiveName]
+	if !ok {
+		return nil, fmt.Errorf("Version '%s' doesn't contain '%s'", version, archiveName)
+	}
+
+	tmpDir, err := ioutil.TempDir("", "rosa-download")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+	archive := filepath.Join(tmpDir, archiveName)
+	archiveURL := baseURL + "/" + archiveName
+	reporter.Infof("Downloading %s", archiveURL)
+	err = File(reporter, archiveURL, archive, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to download '%s': %v", archiveURL, err)
+	}
+	err = VerifyChecksum(archive, checksum)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(options.Dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+	binaries := make([]string, len(t.Binaries))
+	for i, binary := range t.Binaries {
+		if runtime.GOOS == "windows" {
+			binary += ".exe"
+		}
+		binaries[i] = binary
+	}
+	return Extract(archive, options.Dir, binaries...)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that download files reporting the progress, so that large
+// downloads, like the client tools, don't look stuck.
+
+package download
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/dustin/go-humanize"
+
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+)
+
+// progressInterval is the minimum time between two reports of the progress of a download.
+const progressInterval = 2 * time.Second
+
+// client is the HTTP client used for all the downloads. The timeout is large because the client
+// tools are hundreds of megabytes.
+var client = &http.Client{Timeout: 30 * time.Minute}
+
+// Get returns the content of the given URL, which is expected to be small, like a list of
+// checksums.
+func Get(url string) ([]byte, error) {
+	response, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Unexpected status code %d getting '%s'", response.StatusCode, url)
+	}
+	return ioutil.ReadAll(response.Body)
+}
+
+// File downloads the given URL to the given path, reporting the progress. The content is written
+// to a temporary file that is renamed once the download is complete, so that an interrupted
+// download doesn't leave a partial file behind.
+func File(reporter *rprtr.Object, url string, path string, mode os.FileMode) error {
+	response, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("Unexpected status code %d getting '%s'", response.StatusCode, url)
+	}
+
+	tmpPath := path + ".tmp"
+	// #nosec G304
+	out, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	counter := &progress{
+		reporter: reporter,
+		url:      url,
+		total:    response.ContentLength,
+		last:     time.Now(),
+	}
+	_, err = io.Copy(out, io.TeeReader(response.Body, counter))
+	if err != nil {
+		out.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	err = out.Close()
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	counter.report()
+	return os.Rename(tmpPath, path)
+}
+
+// progress counts the bytes written to it and reports them periodically.
+type progress struct {
+	reporter *rprtr.Object
+	url      string
+	written  int64
+	total    int64
+	last     time.Time
+}
+
+func (p *progress) Write(data []byte) (int, error) {
+	p.written += int64(len(data))
+	if time.Since(p.last) >= progressInterval {
+		p.report()
+		p.last = time.Now()
+	}
+	return len(data), nil
+}
+
+func (p *progress) report() {
+	fields := rprtr.Fields{
+		"url":     p.url,
+		"written": p.written,
+	}
+	if p.total <= 0 {
+		p.reporter.Progressf(rprtr.CodeDownloadProgress, fields, "Downloaded %s",
+			humanize.Bytes(uint64(p.written)))
+		return
+	}
+	fields["total"] = p.total
+	p.reporter.Progressf(rprtr.CodeDownloadProgress, fields, "Downloaded %s of %s (%d%%)",
+		humanize.Bytes(uint64(p.written)), humanize.Bytes(uint64(p.total)), p.written*100/p.total)
+}
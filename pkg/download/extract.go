@@ -0,0 +1,142 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that extract the binaries from the archives of the client
+// tools.
+
+package download
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Extract copies the files with the given names from the tar.gz or zip archive to the given
+// directory, making them executable, and returns their paths. Files of the archive that aren't in
+// the list are ignored, and it fails if any of them isn't in the archive.
+func Extract(archive string, dir string, names ...string) ([]string, error) {
+	wanted := map[string]bool{}
+	for _, name := range names {
+		wanted[name] = true
+	}
+	var paths []string
+	var err error
+	if strings.HasSuffix(archive, ".zip") {
+		paths, err = extractZip(archive, dir, wanted)
+	} else {
+		paths, err = extractTarGz(archive, dir, wanted)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) != len(names) {
+		return nil, fmt.Errorf("Archive '%s' doesn't contain all of %s", archive,
+			strings.Join(names, ", "))
+	}
+	return paths, nil
+}
+
+func extractTarGz(archive string, dir string, wanted map[string]bool) ([]string, error) {
+	// #nosec G304
+	file, err := os.Open(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read archive '%s': %v", archive, err)
+	}
+	defer gz.Close()
+	reader := tar.NewReader(gz)
+	paths := []string{}
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read archive '%s': %v", archive, err)
+		}
+		name := filepath.Base(header.Name)
+		if header.Typeflag != tar.TypeReg || !wanted[name] {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		err = writeExecutable(path, reader)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+func extractZip(archive string, dir string, wanted map[string]bool) ([]string, error) {
+	reader, err := zip.OpenReader(archive)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read archive '%s': %v", archive, err)
+	}
+	defer reader.Close()
+	paths := []string{}
+	for _, item := range reader.File {
+		name := filepath.Base(item.Name)
+		if item.FileInfo().IsDir() || !wanted[name] {
+			continue
+		}
+		content, err := item.Open()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read '%s' from archive '%s': %v", name, archive, err)
+		}
+		path := filepath.Join(dir, name)
+		err = writeExecutable(path, content)
+		content.Close()
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// writeExecutable writes the content to the given path, replacing the existing file only once the
+// content has been completely written.
+func writeExecutable(path string, content io.Reader) error {
+	tmpPath := path + ".tmp"
+	// #nosec G302 G304
+	out, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755)
+	if err != nil {
+		return err
+	}
+	// #nosec G110
+	_, err = io.Copy(out, content)
+	if err == nil {
+		err = out.Close()
+	} else {
+		out.Close()
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("Failed to write '%s': %v", path, err)
+	}
+	return os.Rename(tmpPath, path)
+}
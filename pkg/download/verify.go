@@ -0,0 +1,101 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that verify the downloaded files using the checksums and
+// signatures published next to them.
+
+package download
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/openpgp"
+)
+
+// DefaultKeyring is the file that contains the Red Hat release keys in Red Hat Enterprise Linux and
+// Fedora systems. It is used to verify the signatures when it exists and no other keyring is given.
+const DefaultKeyring = "/etc/pki/rpm-gpg/RPM-GPG-KEY-redhat-release"
+
+// ParseChecksums parses a list of checksums in the format generated by the 'sha256sum' command,
+// returning a map from file name to checksum.
+func ParseChecksums(data []byte) map[string]string {
+	checksums := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		// Files checked in binary mode are prefixed with an asterisk:
+		checksums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+	return checksums
+}
+
+// VerifyChecksum checks that the SHA-256 checksum of the given file is the expected one.
+func VerifyChecksum(path string, expected string) error {
+	// #nosec G304
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return err
+	}
+	actual := hex.EncodeToString(hash.Sum(nil))
+	if actual != strings.ToLower(expected) {
+		return fmt.Errorf("Checksum of '%s' is '%s' but '%s' was expected", path, actual, expected)
+	}
+	return nil
+}
+
+// VerifySignature checks that the given detached signature of the data was made with one of the
+// keys of the given keyring file, which can be armored or binary.
+func VerifySignature(data []byte, signature []byte, keyring string) error {
+	// #nosec G304
+	keys, err := ioutil.ReadFile(keyring)
+	if err != nil {
+		return fmt.Errorf("Failed to read keyring '%s': %v", keyring, err)
+	}
+	entities, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(keys))
+	if err != nil {
+		entities, err = openpgp.ReadKeyRing(bytes.NewReader(keys))
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to parse keyring '%s': %v", keyring, err)
+	}
+	_, err = openpgp.CheckDetachedSignature(entities, bytes.NewReader(data), bytes.NewReader(signature))
+	if err != nil {
+		// The signature may also be armored:
+		_, armoredErr := openpgp.CheckArmoredDetachedSignature(entities, bytes.NewReader(data),
+			bytes.NewReader(signature))
+		if armoredErr != nil {
+			return fmt.Errorf("Signature isn't valid for the keys of '%s': %v", keyring, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// readPrefixes are the prefixes of the names of the AWS operations that don't change anything.
+var readPrefixes = []string{
+	"AssumeRole",
+	"Check",
+	"Decode",
+	"Describe",
+	"Detect",
+	"Estimate",
+	"Get",
+	"Head",
+	"List",
+	"Lookup",
+	"Search",
+	"Simulate",
+}
+
+// AddAWSHandlers adds to the given handlers of an AWS session the handler that, in dry run mode,
+// prints the calls that change AWS resources and replaces them with empty results.
+func AddAWSHandlers(handlers *request.Handlers) {
+	handlers.Validate.PushBack(func(r *request.Request) {
+		if !Enabled() || r.Error != nil || !changes(r.Operation.Name) {
+			return
+		}
+		printRequest(
+			"AWS",
+			fmt.Sprintf("%s %s", r.ClientInfo.ServiceName, r.Operation.Name),
+			awsutil.Prettify(r.Params),
+		)
+
+		// Skip sending the request and processing the response:
+		r.Handlers.Send.Clear()
+		r.Handlers.Send.PushBack(func(r *request.Request) {
+			r.HTTPResponse = &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{},
+				Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+			}
+		})
+		r.Handlers.ValidateResponse.Clear()
+		r.Handlers.UnmarshalMeta.Clear()
+		r.Handlers.UnmarshalError.Clear()
+		r.Handlers.Unmarshal.Clear()
+		r.Handlers.Unmarshal.PushBack(func(r *request.Request) {
+			if r.Data != nil {
+				Fill(r.Data)
+			}
+		})
+	})
+}
+
+// changes checks if the AWS operation with the given name changes something.
+func changes(operation string) bool {
+	for _, prefix := range readPrefixes {
+		if strings.HasPrefix(operation, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// maxFillDepth is the maximum depth of the nested structures that fill populates.
+const maxFillDepth = 4
+
+// Fill populates the nil pointers of the given AWS result with empty values. It is also used by
+// the simulation mode for the calls that don't have a canned result.
+func Fill(result interface{}) {
+	fill(reflect.ValueOf(result), 0)
+}
+
+// fill populates the nil pointers of the given result with empty values, so that the code that
+// processes the result of a call that wasn't sent doesn't dereference nil pointers.
+func fill(value reflect.Value, depth int) {
+	if depth > maxFillDepth {
+		return
+	}
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			if !value.CanSet() {
+				return
+			}
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		fill(value.Elem(), depth+1)
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Field(i)
+			if field.CanSet() {
+				fill(field, depth)
+			}
+		}
+	}
+}
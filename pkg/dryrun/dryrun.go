@@ -0,0 +1,89 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the '--dry-run' command line option of the commands that create, change or
+// delete resources. When it is enabled the requests that would change something in OCM or in AWS
+// are printed instead of sent, while the requests that only read data are still sent, so that
+// the commands can run all their validations.
+
+package dryrun
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/moactl/pkg/redact"
+)
+
+// AddFlag adds the dry run flag to the given set of command line flags.
+func AddFlag(flags *pflag.FlagSet) {
+	flags.BoolVar(
+		&enabled,
+		"dry-run",
+		false,
+		"Print the requests that would change resources in OCM and AWS, instead of sending them.",
+	)
+}
+
+// Enabled returns a boolean flag that indicates if the dry run mode is enabled.
+func Enabled() bool {
+	return enabled
+}
+
+// Enable enables the dry run mode.
+func Enable() {
+	enabled = true
+}
+
+// enabled is a boolean flag that indicates that the dry run mode is enabled.
+var enabled bool
+
+// output is where the requests that aren't sent are printed.
+var output io.Writer = os.Stdout
+
+// notice makes sure that the explanation of the output is printed only once.
+var notice sync.Once
+
+// lock serializes the printing of requests made from multiple goroutines.
+var lock sync.Mutex
+
+// printRequest writes the given request and its pretty printed body, if any. Bodies that are
+// strings are written as they are. Credentials and tokens are redacted from the request and the
+// body, as they are from the log.
+func printRequest(system string, request string, body interface{}) {
+	lock.Lock()
+	defer lock.Unlock()
+	notice.Do(func() {
+		fmt.Fprintf(output, "Dry run: the following requests won't be sent and nothing will be changed\n\n")
+	})
+	fmt.Fprintf(output, "%s: %s\n", system, redact.String(request))
+	switch typed := body.(type) {
+	case nil:
+	case string:
+		fmt.Fprintf(output, "%s\n", redact.String(typed))
+	default:
+		data, err := json.MarshalIndent(typed, "", "  ")
+		if err == nil {
+			fmt.Fprintf(output, "%s\n", redact.String(string(data)))
+		}
+	}
+	fmt.Fprintf(output, "\n")
+}
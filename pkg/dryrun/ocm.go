@@ -0,0 +1,104 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// RoundTripper is a round tripper that, in dry run mode, prints the requests that change OCM
+// resources and answers them without sending them.
+type RoundTripper struct {
+	next http.RoundTripper
+}
+
+// Make sure that we implement the http.RoundTripper interface:
+var _ http.RoundTripper = &RoundTripper{}
+
+// NewRoundTripper creates a round tripper that prints the requests that change OCM resources, in
+// dry run mode, or calls the next one otherwise.
+func NewRoundTripper(next http.RoundTripper) *RoundTripper {
+	return &RoundTripper{
+		next: next,
+	}
+}
+
+// RoundTrip is the implementation of the http.RoundTripper interface.
+func (t *RoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	// Requests that don't change anything are sent, and so are the requests to other servers,
+	// like the ones that request authentication tokens:
+	if !Enabled() ||
+		request.Method == http.MethodGet ||
+		request.Method == http.MethodHead ||
+		!strings.HasPrefix(request.URL.Path, "/api/") {
+		return t.next.RoundTrip(request)
+	}
+
+	var data []byte
+	if request.Body != nil {
+		var err error
+		data, err = ioutil.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	var body interface{}
+	if len(data) > 0 && json.Unmarshal(data, &body) != nil {
+		body = string(data)
+	}
+	printRequest("OCM", request.Method+" "+request.URL.RequestURI(), body)
+
+	// The server validates without changing anything the requests that explicitly ask for it, like
+	// the one sent by 'rosa create cluster --dry-run', so those are still sent:
+	if request.URL.Query().Get("dryRun") == "true" {
+		request.Body = ioutil.NopCloser(bytes.NewReader(data))
+		return t.next.RoundTrip(request)
+	}
+
+	// Answer as the server would, returning the object that was sent, so that the commands can
+	// continue:
+	response := &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Request:    request,
+	}
+	switch request.Method {
+	case http.MethodPost:
+		response.Status = "201 Created"
+		response.StatusCode = http.StatusCreated
+	case http.MethodDelete:
+		response.Status = "204 No Content"
+		response.StatusCode = http.StatusNoContent
+		data = nil
+	}
+	if len(data) == 0 {
+		data = []byte("{}")
+	}
+	response.Header.Set("Content-Type", "application/json")
+	response.Body = ioutil.NopCloser(bytes.NewReader(data))
+	response.ContentLength = int64(len(data))
+	return response, nil
+}
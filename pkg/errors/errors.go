@@ -0,0 +1,167 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the categories of the errors of the commands and the exit codes that they
+// are mapped to, so that scripts can react to the kind of failure without parsing the messages.
+
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
+)
+
+// Exit codes of the commands. They are part of the interface of the tool, so existing ones must
+// not be changed.
+const (
+	// ExitGeneral is used for the errors that don't belong to any other category.
+	ExitGeneral = 1
+
+	// ExitUsage is used when the command line isn't valid, like unknown or missing flags.
+	ExitUsage = 2
+
+	// ExitAuth is used when the user isn't logged in or isn't allowed to perform the operation.
+	ExitAuth = 3
+
+	// ExitNotFound is used when a resource, like a cluster, doesn't exist.
+	ExitNotFound = 4
+
+	// ExitQuota is used when there isn't enough quota in the OCM organization or in the AWS
+	// account.
+	ExitQuota = 5
+
+	// ExitTransient is used for failures that may succeed if retried later, like connection
+	// errors or server errors of the APIs.
+	ExitTransient = 6
+
+	// ExitConflict is used when a resource isn't in a state that allows the operation, like a
+	// cluster that isn't ready yet.
+	ExitConflict = 7
+)
+
+// Error is an error with the exit code of its category.
+type Error struct {
+	Code int
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error that is categorized.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap returns an error that has the given exit code and the message of the given error. It
+// returns nil if the error is nil.
+func Wrap(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// Usagef returns an error for an invalid command line.
+func Usagef(format string, args ...interface{}) error {
+	return Wrap(ExitUsage, fmt.Errorf(format, args...))
+}
+
+// Authf returns an error for a user that isn't logged in or isn't allowed to do something.
+func Authf(format string, args ...interface{}) error {
+	return Wrap(ExitAuth, fmt.Errorf(format, args...))
+}
+
+// NotFoundf returns an error for a resource that doesn't exist.
+func NotFoundf(format string, args ...interface{}) error {
+	return Wrap(ExitNotFound, fmt.Errorf(format, args...))
+}
+
+// Quotaf returns an error for an operation that needs more quota than available.
+func Quotaf(format string, args ...interface{}) error {
+	return Wrap(ExitQuota, fmt.Errorf(format, args...))
+}
+
+// Transientf returns an error for a failure that may succeed if retried later.
+func Transientf(format string, args ...interface{}) error {
+	return Wrap(ExitTransient, fmt.Errorf(format, args...))
+}
+
+// Conflictf returns an error for a resource that isn't in a state that allows the operation.
+func Conflictf(format string, args ...interface{}) error {
+	return Wrap(ExitConflict, fmt.Errorf(format, args...))
+}
+
+// ExitCode returns the exit code for the given error: zero if it is nil, the code of its category
+// if it has one, or the general one otherwise.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var typed *Error
+	if errors.As(err, &typed) {
+		return typed.Code
+	}
+	return ExitGeneral
+}
+
+// FromStatus returns the given error categorized by the HTTP status code of the API response that
+// caused it. A zero status means that there was no response, like with connection errors, and
+// those are considered transient. Errors that mention quota are quota errors regardless of the
+// status.
+func FromStatus(status int, err error) error {
+	if err == nil {
+		return nil
+	}
+	if strings.Contains(strings.ToLower(err.Error()), "quota") {
+		return Wrap(ExitQuota, err)
+	}
+	switch {
+	case status == 0:
+		return Wrap(ExitTransient, err)
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return Wrap(ExitAuth, err)
+	case status == http.StatusNotFound:
+		return Wrap(ExitNotFound, err)
+	case status == http.StatusConflict:
+		return Wrap(ExitConflict, err)
+	case status == http.StatusTooManyRequests || status >= http.StatusInternalServerError:
+		return Wrap(ExitTransient, err)
+	case status >= http.StatusBadRequest:
+		return Wrap(ExitUsage, err)
+	}
+	return err
+}
+
+// FromOCM returns an error with the reason given by the OCM API, categorized by the status code,
+// that the API puts in the identifier of the error.
+func FromOCM(response *ocmerrors.Error, err error) error {
+	if err == nil {
+		return nil
+	}
+	message := response.Reason()
+	if message == "" {
+		message = err.Error()
+	}
+	status, _ := strconv.Atoi(response.ID())
+	return FromStatus(status, errors.New(message))
+}
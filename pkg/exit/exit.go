@@ -0,0 +1,39 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package exit contains the function that the commands use to exit the process, so that the
+// result document of the CI mode and the trace are complete even when a command doesn't return.
+package exit
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/openshift/moactl/pkg/ci"
+	"github.com/openshift/moactl/pkg/tracing"
+)
+
+// Exit finishes the result of the CI mode and the trace of the command, and then exits the process
+// with the given code. A code other than zero marks the command as failed.
+func Exit(code int) {
+	var err error
+	if code != 0 {
+		err = fmt.Errorf("Command exited with code %d", code)
+	}
+	ci.Finish(err)
+	tracing.Finish(err)
+	os.Exit(code)
+}
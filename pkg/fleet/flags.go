@@ -0,0 +1,149 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to implement the command line options shared by the fleet
+// commands.
+
+package fleet
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/moactl/pkg/cluster"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+var options struct {
+	search      string
+	concurrency int
+	stateFile   string
+	outputFile  string
+	metricsDir  string
+}
+
+// AddFlags adds the flags that select the clusters and control how the operation runs to the
+// given set of command line flags.
+func AddFlags(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&options.search,
+		"search",
+		"",
+		"OCM search expression selecting the clusters, for example \"region.id = 'us-east-1'\". "+
+			"Required unless '--cluster-group' is used.",
+	)
+	flags.IntVar(
+		&options.concurrency,
+		"concurrency",
+		5,
+		"Maximum number of clusters processed at the same time.",
+	)
+	flags.StringVar(
+		&options.stateFile,
+		"state-file",
+		"",
+		"File where the result of each cluster is saved as soon as it is known. If the file exists, "+
+			"clusters that already succeeded or were skipped are not processed again.",
+	)
+	flags.StringVar(
+		&options.outputFile,
+		"output-file",
+		"",
+		"File where the results are written in JSON format when the operation finishes.",
+	)
+	flags.StringVar(
+		&options.metricsDir,
+		"metrics-dir",
+		"",
+		"Directory of the textfile collector of the Prometheus node exporter, where the number of "+
+			"clusters that succeeded and failed and the duration of the operation on each cluster are "+
+			"written when the operation finishes. The file is named after the command, for example "+
+			"'"+MetricsFileName("upgrade")+"'.",
+	)
+}
+
+// Execute finds the clusters selected by the command line flags, runs the operation on them and
+// prints the result of each cluster. The name of the command identifies it in the metrics. It
+// fails if the operation failed for any of them.
+func Execute(r *runtime.Runtime, command string, operation Operation) error {
+	reporter := r.Reporter()
+
+	search := options.search
+	if cluster.Group() != "" {
+		if search != "" {
+			return rosaerrors.Usagef("At most one of '--search' or '--%s' may be specified", cluster.GroupFlag)
+		}
+		var err error
+		search, err = cluster.GetGroupSearch(cluster.Group())
+		if err != nil {
+			return err
+		}
+	}
+	if search == "" {
+		return rosaerrors.Usagef("Option '--search' or '--%s' is mandatory", cluster.GroupFlag)
+	}
+	if options.concurrency < 1 {
+		return rosaerrors.Usagef("Expected a positive concurrency")
+	}
+
+	state, err := LoadState(options.stateFile)
+	if err != nil {
+		return err
+	}
+
+	reporter.Debugf("Loading clusters matching '%s'", search)
+	clusters, err := r.OCM().SearchClusters(r.Creator().ARN, search)
+	if err != nil {
+		return fmt.Errorf("Failed to get clusters: %w", err)
+	}
+	if len(clusters) == 0 {
+		reporter.Warnf("There are no clusters matching '%s'", search)
+		return nil
+	}
+	reporter.Infof("Processing %d clusters", len(clusters))
+
+	err = Run(clusters, options.concurrency, state, operation)
+	if err != nil {
+		reporter.Errorf("Failed to save state: %v", err)
+	}
+
+	results := state.Results()
+	PrintResults(os.Stdout, results)
+	if options.outputFile != "" {
+		err = WriteResults(options.outputFile, results)
+		if err != nil {
+			return err
+		}
+	}
+	if options.metricsDir != "" {
+		err = WriteMetrics(options.metricsDir, command, results, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+	if Failed(results) {
+		if options.stateFile != "" {
+			return fmt.Errorf("The operation failed for some clusters. Run the same command again "+
+				"to retry them using the state in '%s'", options.stateFile)
+		}
+		return fmt.Errorf("The operation failed for some clusters")
+	}
+	return nil
+}
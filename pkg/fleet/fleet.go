@@ -0,0 +1,216 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to run an operation on a set of clusters, keeping track of
+// the result for each cluster so that interrupted runs can be resumed.
+
+package fleet
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/table"
+)
+
+// Status is the outcome of the operation on a cluster.
+type Status string
+
+const (
+	StatusSucceeded Status = "succeeded"
+	StatusSkipped   Status = "skipped"
+	StatusFailed    Status = "failed"
+)
+
+// Result is the result of the operation on a cluster.
+type Result struct {
+	ClusterID   string    `json:"cluster_id"`
+	ClusterName string    `json:"cluster_name"`
+	Status      Status    `json:"status"`
+	Message     string    `json:"message,omitempty"`
+	Time        time.Time `json:"time"`
+
+	// Duration is the time in seconds that the operation took for the cluster.
+	Duration float64 `json:"duration,omitempty"`
+}
+
+// Operation is the function that runs the operation on a single cluster. It returns the status
+// and a message describing the outcome.
+type Operation func(cluster *cmv1.Cluster) (Status, string)
+
+// State contains the results of a run. When it is stored in a file, clusters that already
+// succeeded or were skipped aren't processed again, so that an interrupted run can be resumed.
+type State struct {
+	path    string
+	lock    sync.Mutex
+	results map[string]*Result
+}
+
+// LoadState loads the state from the given file, which doesn't need to exist. An empty path
+// returns a state that isn't saved.
+func LoadState(path string) (*State, error) {
+	state := &State{
+		path:    path,
+		results: make(map[string]*Result),
+	}
+	if path == "" {
+		return state, nil
+	}
+	// #nosec G304
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read state file '%s': %v", path, err)
+	}
+	results := []*Result{}
+	err = json.Unmarshal(data, &results)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse state file '%s': %v", path, err)
+	}
+	for _, result := range results {
+		state.results[result.ClusterID] = result
+	}
+	return state, nil
+}
+
+// Done checks if the operation has already completed for the cluster in a previous run.
+func (s *State) Done(clusterID string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	result, ok := s.results[clusterID]
+	return ok && result.Status != StatusFailed
+}
+
+// Results returns the results of all the clusters, sorted by cluster name.
+func (s *State) Results() []*Result {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.sortedResults()
+}
+
+// sortedResults returns the results sorted by cluster name. The caller must hold the lock.
+func (s *State) sortedResults() []*Result {
+	results := make([]*Result, 0, len(s.results))
+	for _, result := range s.results {
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].ClusterName < results[j].ClusterName
+	})
+	return results
+}
+
+// record adds the result of a cluster and saves the state. The file is written while holding the
+// lock, so that the clusters that finish at the same time don't write it concurrently.
+func (s *State) record(result *Result) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.results[result.ClusterID] = result
+	if s.path == "" {
+		return nil
+	}
+	return WriteResults(s.path, s.sortedResults())
+}
+
+// Run runs the operation on the clusters that aren't done yet, at most the given number of them
+// at the same time. The state is saved after each cluster.
+func Run(clusters []*cmv1.Cluster, concurrency int, state *State, operation Operation) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	slots := make(chan struct{}, concurrency)
+	errs := make(chan error, len(clusters))
+	var wait sync.WaitGroup
+	for _, cluster := range clusters {
+		if state.Done(cluster.ID()) {
+			continue
+		}
+		wait.Add(1)
+		slots <- struct{}{}
+		go func(cluster *cmv1.Cluster) {
+			defer func() {
+				<-slots
+				wait.Done()
+			}()
+			start := time.Now()
+			status, message := operation(cluster)
+			err := state.record(&Result{
+				ClusterID:   cluster.ID(),
+				ClusterName: cluster.Name(),
+				Status:      status,
+				Message:     message,
+				Time:        time.Now().UTC(),
+				Duration:    time.Since(start).Seconds(),
+			})
+			if err != nil {
+				errs <- err
+			}
+		}(cluster)
+	}
+	wait.Wait()
+	close(errs)
+	return <-errs
+}
+
+// WriteResults writes the results to the given file in JSON format. The results are written to a
+// temporary file that then replaces the given one, so that an interrupted write doesn't leave a
+// truncated file.
+func WriteResults(path string, results []*Result) error {
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	err = ioutil.WriteFile(tmp, data, 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to write file '%s': %v", tmp, err)
+	}
+	err = os.Rename(tmp, path)
+	if err != nil {
+		return fmt.Errorf("Failed to replace file '%s': %v", path, err)
+	}
+	return nil
+}
+
+// PrintResults prints a table with the status of each cluster.
+func PrintResults(out io.Writer, results []*Result) {
+	writer := table.NewWriter(out)
+	fmt.Fprintf(writer, "ID\tNAME\tSTATUS\tMESSAGE\n")
+	for _, result := range results {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", result.ClusterID, result.ClusterName, result.Status, result.Message)
+	}
+	writer.Flush()
+}
+
+// Failed checks if the operation failed for any of the clusters.
+func Failed(results []*Result) bool {
+	for _, result := range results {
+		if result.Status == StatusFailed {
+			return true
+		}
+	}
+	return false
+}
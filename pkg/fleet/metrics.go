@@ -0,0 +1,113 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that write the results of a run as Prometheus metrics, in the
+// format read by the textfile collector of the node exporter.
+
+package fleet
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// MetricsFileName returns the name of the file where the metrics of the given command are written,
+// so that each command has its own file and runs of different commands don't overwrite each other.
+func MetricsFileName(command string) string {
+	return fmt.Sprintf("rosa_fleet_%s.prom", strings.ReplaceAll(command, "-", "_"))
+}
+
+// WriteMetrics writes the results of a run of the given command to a metrics file in the given
+// directory: the number of clusters with each status, and the success and duration of the
+// operation on each cluster. The file is written to a temporary file first and then renamed, as
+// the collector may read it at any time.
+func WriteMetrics(dir string, command string, results []*Result, now time.Time) error {
+	buffer := &bytes.Buffer{}
+	commandLabel := fmt.Sprintf("command=\"%s\"", escapeLabel(command))
+
+	counts := map[Status]int{}
+	for _, result := range results {
+		counts[result.Status]++
+	}
+	fmt.Fprintf(buffer, "# HELP rosa_fleet_clusters Number of clusters with each status in the last run.\n")
+	fmt.Fprintf(buffer, "# TYPE rosa_fleet_clusters gauge\n")
+	for _, status := range []Status{StatusSucceeded, StatusSkipped, StatusFailed} {
+		fmt.Fprintf(buffer, "rosa_fleet_clusters{%s,status=\"%s\"} %d\n", commandLabel, status, counts[status])
+	}
+
+	fmt.Fprintf(buffer, "# HELP rosa_fleet_cluster_success Whether the operation succeeded or was skipped "+
+		"for the cluster (1) or failed (0).\n")
+	fmt.Fprintf(buffer, "# TYPE rosa_fleet_cluster_success gauge\n")
+	for _, result := range results {
+		success := 1
+		if result.Status == StatusFailed {
+			success = 0
+		}
+		fmt.Fprintf(buffer, "rosa_fleet_cluster_success{%s,%s,status=\"%s\"} %d\n", commandLabel,
+			clusterLabels(result), result.Status, success)
+	}
+
+	fmt.Fprintf(buffer, "# HELP rosa_fleet_cluster_duration_seconds Time that the operation took for "+
+		"the cluster.\n")
+	fmt.Fprintf(buffer, "# TYPE rosa_fleet_cluster_duration_seconds gauge\n")
+	for _, result := range results {
+		fmt.Fprintf(buffer, "rosa_fleet_cluster_duration_seconds{%s,%s} %g\n", commandLabel,
+			clusterLabels(result), result.Duration)
+	}
+
+	fmt.Fprintf(buffer, "# HELP rosa_fleet_last_run_timestamp_seconds Time when the last run finished.\n")
+	fmt.Fprintf(buffer, "# TYPE rosa_fleet_last_run_timestamp_seconds gauge\n")
+	fmt.Fprintf(buffer, "rosa_fleet_last_run_timestamp_seconds{%s} %d\n", commandLabel, now.Unix())
+
+	path := filepath.Join(dir, MetricsFileName(command))
+	temp, err := ioutil.TempFile(dir, ".rosa_fleet_*")
+	if err != nil {
+		return fmt.Errorf("Failed to create metrics file in directory '%s': %v", dir, err)
+	}
+	_, err = temp.Write(buffer.Bytes())
+	if err == nil {
+		err = temp.Close()
+	} else {
+		temp.Close()
+	}
+	if err == nil {
+		// The collector runs as a different user than the tool, so the file must be readable:
+		err = os.Chmod(temp.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(temp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(temp.Name())
+		return fmt.Errorf("Failed to write metrics file '%s': %v", path, err)
+	}
+	return nil
+}
+
+func clusterLabels(result *Result) string {
+	return fmt.Sprintf("cluster_id=\"%s\",cluster_name=\"%s\"", escapeLabel(result.ClusterID),
+		escapeLabel(result.ClusterName))
+}
+
+// escapeLabel escapes the characters that can't appear literally in label values.
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,147 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the renderer of CloudFormation templates.
+
+package iac
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/openshift/moactl/pkg/cluster"
+)
+
+// Types of the CloudFormation resources:
+const (
+	cloudFormationCluster          = "RedHat::ROSA::Cluster"
+	cloudFormationMachinePool      = "RedHat::ROSA::MachinePool"
+	cloudFormationIdentityProvider = "RedHat::ROSA::IdentityProvider"
+)
+
+type cloudFormationTemplate struct {
+	AWSTemplateFormatVersion string                              `json:"AWSTemplateFormatVersion"`
+	Description              string                              `json:"Description"`
+	Parameters               map[string]*cloudFormationParameter `json:"Parameters,omitempty"`
+	Resources                map[string]*cloudFormationResource  `json:"Resources"`
+}
+
+type cloudFormationParameter struct {
+	Type        string `json:"Type"`
+	NoEcho      bool   `json:"NoEcho,omitempty"`
+	Description string `json:"Description,omitempty"`
+}
+
+type cloudFormationResource struct {
+	Type       string                 `json:"Type"`
+	Properties map[string]interface{} `json:"Properties"`
+}
+
+func renderCloudFormation(definition *cluster.Definition) ([]byte, error) {
+	parts, err := split(definition)
+	if err != nil {
+		return nil, err
+	}
+	template := &cloudFormationTemplate{
+		AWSTemplateFormatVersion: "2010-09-09",
+		Description: fmt.Sprintf("Cluster '%s' exported with 'rosa describe cluster --export %s', "+
+			"it requires the %s, %s and %s resource types", definition.Name, CloudFormation,
+			cloudFormationCluster, cloudFormationMachinePool, cloudFormationIdentityProvider),
+		Parameters: map[string]*cloudFormationParameter{},
+		Resources: map[string]*cloudFormationResource{
+			"Cluster": {
+				Type:       cloudFormationCluster,
+				Properties: cloudFormationProperties(parts.cluster),
+			},
+		},
+	}
+	clusterRef := map[string]interface{}{"Ref": "Cluster"}
+	for i, pool := range parts.machinePools {
+		properties := cloudFormationProperties(pool)
+		properties["cluster"] = clusterRef
+		template.Resources[cloudFormationID("MachinePool", definition.MachinePools[i].Name)] =
+			&cloudFormationResource{
+				Type:       cloudFormationMachinePool,
+				Properties: properties,
+			}
+	}
+	for _, idp := range parts.identityProviders {
+		id := cloudFormationID("IdentityProvider", idp.name)
+		settings := idp.settings
+		// Secrets of identity providers are never exported, so they are parameters:
+		if idp.secret != "" {
+			parameter := cloudFormationID(id, idp.secret)
+			template.Parameters[parameter] = &cloudFormationParameter{
+				Type:   "String",
+				NoEcho: true,
+				Description: fmt.Sprintf("The %s of identity provider '%s'",
+					strings.ReplaceAll(idp.secret, "_", " "), idp.name),
+			}
+			settings = idp.withSecret(map[string]interface{}{"Ref": parameter})
+		}
+		properties := cloudFormationProperties(settings)
+		properties["cluster"] = clusterRef
+		template.Resources[id] = &cloudFormationResource{
+			Type:       cloudFormationIdentityProvider,
+			Properties: properties,
+		}
+	}
+	data, err := json.MarshalIndent(template, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// cloudFormationProperties converts the given settings to the properties of a resource.
+func cloudFormationProperties(settings yaml.MapSlice) map[string]interface{} {
+	result := map[string]interface{}{}
+	for _, item := range settings {
+		result[fmt.Sprint(item.Key)] = cloudFormationValue(item.Value)
+	}
+	return result
+}
+
+func cloudFormationValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case yaml.MapSlice:
+		return cloudFormationProperties(value)
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, element := range value {
+			result[i] = cloudFormationValue(element)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+// cloudFormationID returns the logical identifier of the resource or parameter with the given
+// names, which can only contain letters and digits.
+func cloudFormationID(names ...string) string {
+	id := &strings.Builder{}
+	for _, name := range names {
+		for _, word := range words(name) {
+			id.WriteString(strings.ToUpper(word[:1]))
+			id.WriteString(word[1:])
+		}
+	}
+	return id.String()
+}
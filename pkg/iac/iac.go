@@ -0,0 +1,164 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package iac renders the definition of a cluster as infrastructure as code, so that clusters
+// created with the command line can be managed with Terraform or CloudFormation. The settings of
+// the resources have the same names as in the cluster definition files accepted by
+// 'rosa create cluster --file', and the machine pools and identity providers are separate
+// resources that refer to the cluster. The types of the resources aren't provided by any provider
+// or registry that the tool installs, so applying the output requires a Terraform provider or
+// CloudFormation resource types that implement them.
+package iac
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/ocm"
+)
+
+// Formats of the exported definitions:
+const (
+	Terraform      = "terraform"
+	CloudFormation = "cloudformation"
+)
+
+// Formats are the supported formats.
+var Formats = []string{Terraform, CloudFormation}
+
+// Render returns the given definition in the given format.
+func Render(format string, definition *cluster.Definition) ([]byte, error) {
+	switch format {
+	case Terraform:
+		return renderTerraform(definition)
+	case CloudFormation:
+		return renderCloudFormation(definition)
+	default:
+		return nil, fmt.Errorf("Format '%s' isn't valid, it must be '%s'", format,
+			strings.Join(Formats, "' or '"))
+	}
+}
+
+// parts contains the settings of the resources of a definition, in the order of the fields of
+// the definition file.
+type parts struct {
+	cluster           yaml.MapSlice
+	machinePools      []yaml.MapSlice
+	identityProviders []*identityProvider
+}
+
+// identityProvider contains the settings of an identity provider and the field of its secret, if
+// it has one, which isn't part of the settings and needs to be provided when it is created.
+type identityProvider struct {
+	name     string
+	settings yaml.MapSlice
+	provider string
+	secret   string
+}
+
+// split returns the settings of the cluster, its machine pools and its identity providers.
+func split(definition *cluster.Definition) (*parts, error) {
+	clusterOnly := *definition
+	clusterOnly.MachinePools = nil
+	clusterOnly.IdentityProviders = nil
+	settings, err := toMapSlice(&clusterOnly)
+	if err != nil {
+		return nil, err
+	}
+	result := &parts{
+		cluster: settings,
+	}
+	for _, pool := range definition.MachinePools {
+		settings, err = toMapSlice(pool)
+		if err != nil {
+			return nil, err
+		}
+		result.machinePools = append(result.machinePools, settings)
+	}
+	for _, config := range definition.IdentityProviders {
+		settings, err = toMapSlice(config)
+		if err != nil {
+			return nil, err
+		}
+		idp := &identityProvider{
+			settings: settings,
+		}
+		idp.name, _ = config["name"].(string)
+		for provider, secret := range ocm.IdentityProviderSecrets {
+			values, ok := config[provider].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			// LDAP providers only need a password when they bind with a DN:
+			if provider == "ldap" && values["bind_dn"] == nil {
+				continue
+			}
+			idp.provider = provider
+			idp.secret = secret
+		}
+		result.identityProviders = append(result.identityProviders, idp)
+	}
+	return result, nil
+}
+
+// toMapSlice converts the given value to the settings that it has in YAML, keeping the order of
+// the fields of structs. Maps are sorted by key.
+func toMapSlice(value interface{}) (yaml.MapSlice, error) {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	result := yaml.MapSlice{}
+	err = yaml.Unmarshal(data, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// withSecret returns the settings of the identity provider with the given value in the field of
+// its secret.
+func (p *identityProvider) withSecret(value interface{}) yaml.MapSlice {
+	result := make(yaml.MapSlice, len(p.settings))
+	copy(result, p.settings)
+	for i, item := range result {
+		if item.Key != p.provider {
+			continue
+		}
+		values, ok := item.Value.(yaml.MapSlice)
+		if !ok {
+			continue
+		}
+		values = append(append(yaml.MapSlice{}, values...), yaml.MapItem{Key: p.secret, Value: value})
+		sort.SliceStable(values, func(i, j int) bool {
+			return fmt.Sprint(values[i].Key) < fmt.Sprint(values[j].Key)
+		})
+		result[i].Value = values
+	}
+	return result
+}
+
+// words splits the given name into the words that are separated by characters that aren't letters
+// or digits.
+func words(name string) []string {
+	return strings.FieldsFunc(name, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+}
@@ -0,0 +1,13 @@
+package iac_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestIAC(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "IAC Suite")
+}
@@ -0,0 +1,306 @@
+package iac_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/iac"
+)
+
+func boolPtr(value bool) *bool {
+	return &value
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+// definition returns a cluster definition with all the kinds of settings: nested blocks, maps,
+// lists, machine pools and identity providers with and without secrets.
+func definition() *cluster.Definition {
+	return &cluster.Definition{
+		Name:               "my-cluster",
+		Region:             "us-east-1",
+		Version:            "4.6.8",
+		MultiAZ:            boolPtr(true),
+		ComputeMachineType: "m5.xlarge",
+		ComputeNodes:       intPtr(3),
+		STS:                boolPtr(false),
+		Tags: map[string]string{
+			"cost-center": "${team}",
+			"env":         "prod",
+		},
+		Network: &cluster.NetworkDefinition{
+			MachineCIDR: "10.0.0.0/16",
+			HostPrefix:  intPtr(23),
+			SubnetIDs:   []string{"subnet-1", "subnet-2"},
+		},
+		MachinePools: []*cluster.MachinePoolDefinition{
+			{
+				Name:         "gpu",
+				InstanceType: "p3.2xlarge",
+				MinReplicas:  intPtr(1),
+				MaxReplicas:  intPtr(3),
+				Labels:       map[string]string{"node-role": "gpu"},
+			},
+		},
+		IdentityProviders: []map[string]interface{}{
+			{
+				"name":           "github-1",
+				"type":           "GithubIdentityProvider",
+				"mapping_method": "claim",
+				"github": map[string]interface{}{
+					"client_id":     "abc",
+					"organizations": []interface{}{"my-org"},
+				},
+			},
+			{
+				"name": "ldap-1",
+				"type": "LDAPIdentityProvider",
+				"ldap": map[string]interface{}{
+					"url":     "ldap://ldap.example.com/ou=users?uid",
+					"bind_dn": "cn=admin",
+				},
+			},
+		},
+	}
+}
+
+var (
+	terraformIdentifierRE = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+	terraformReferenceRE  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*(\.[A-Za-z_][A-Za-z0-9_-]*)+$`)
+	terraformHeaderRE     = regexp.MustCompile(`^(resource|variable) ((?:"[^"]*" )+)\{$`)
+	terraformAttributeRE  = regexp.MustCompile(`^("[^"]*"|[A-Za-z_][A-Za-z0-9_-]*) *= (.*)$`)
+	terraformBlockRE      = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_-]*) \{$`)
+)
+
+// validateTerraform checks that the given configuration has the syntax of the blocks, attributes
+// and values generated for Terraform, and that all the references to variables and resources are
+// declared. It returns the declared resources.
+func validateTerraform(data string) []string {
+	declared := map[string]bool{}
+	references := []string{}
+	resources := []string{}
+	// Each element is true for maps, which contain quoted keys, and false for blocks:
+	stack := []bool{}
+	for i, line := range strings.Split(strings.TrimSuffix(data, "\n"), "\n") {
+		where := fmt.Sprintf("line %d: %s", i+1, line)
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			Expect(line == "" || len(stack) == 0).To(BeTrue(), where)
+			continue
+		}
+		if line == "}" {
+			Expect(stack).ToNot(BeEmpty(), where)
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		if len(stack) == 0 {
+			match := terraformHeaderRE.FindStringSubmatch(line)
+			Expect(match).ToNot(BeNil(), where)
+			labels := []string{}
+			for _, label := range strings.Fields(match[2]) {
+				value, err := strconv.Unquote(label)
+				Expect(err).ToNot(HaveOccurred(), where)
+				Expect(value).To(MatchRegexp(terraformIdentifierRE.String()), where)
+				labels = append(labels, value)
+			}
+			if match[1] == "variable" {
+				Expect(labels).To(HaveLen(1), where)
+				declared["var."+labels[0]] = true
+			} else {
+				Expect(labels).To(HaveLen(2), where)
+				name := labels[0] + "." + labels[1]
+				Expect(declared).ToNot(HaveKey(name+".id"), where)
+				declared[name+".id"] = true
+				resources = append(resources, name)
+			}
+			stack = append(stack, false)
+			continue
+		}
+		inMap := stack[len(stack)-1]
+		if match := terraformBlockRE.FindStringSubmatch(line); match != nil && !inMap {
+			stack = append(stack, false)
+			continue
+		}
+		match := terraformAttributeRE.FindStringSubmatch(line)
+		Expect(match).ToNot(BeNil(), where)
+		Expect(strings.HasPrefix(match[1], `"`)).To(Equal(inMap), where)
+		if match[2] == "{" {
+			Expect(inMap).To(BeFalse(), where)
+			stack = append(stack, true)
+			continue
+		}
+		references = append(references, validateTerraformValue(match[2], where)...)
+	}
+	Expect(stack).To(BeEmpty())
+	for _, reference := range references {
+		Expect(declared).To(HaveKey(reference))
+	}
+	return resources
+}
+
+// validateTerraformValue checks that the given text is a literal, a list of literals or a
+// reference, and returns the references that it contains.
+func validateTerraformValue(text string, where string) []string {
+	if strings.HasPrefix(text, "[") {
+		Expect(text).To(HaveSuffix("]"), where)
+		references := []string{}
+		elements := strings.TrimSuffix(strings.TrimPrefix(text, "["), "]")
+		for elements != "" {
+			element := elements
+			if strings.HasPrefix(elements, `"`) {
+				prefix, err := strconv.QuotedPrefix(elements)
+				Expect(err).ToNot(HaveOccurred(), where)
+				element = prefix
+			} else if i := strings.Index(elements, ","); i >= 0 {
+				element = elements[:i]
+			}
+			references = append(references, validateTerraformValue(element, where)...)
+			elements = strings.TrimPrefix(strings.TrimPrefix(elements[len(element):], ","), " ")
+		}
+		return references
+	}
+	if strings.HasPrefix(text, `"`) {
+		value, err := strconv.Unquote(text)
+		Expect(err).ToNot(HaveOccurred(), where)
+		Expect(strings.ReplaceAll(value, "$${", "")).ToNot(ContainSubstring("${"), where)
+		return nil
+	}
+	if text == "true" || text == "false" || text == "null" || text == "string" {
+		return nil
+	}
+	if _, err := strconv.ParseFloat(text, 64); err == nil {
+		return nil
+	}
+	Expect(text).To(MatchRegexp(terraformReferenceRE.String()), where)
+	return []string{text}
+}
+
+var (
+	cloudFormationIDRE   = regexp.MustCompile(`^[A-Za-z0-9]{1,255}$`)
+	cloudFormationTypeRE = regexp.MustCompile(`^[A-Za-z0-9]+::[A-Za-z0-9]+::[A-Za-z0-9]+$`)
+)
+
+// cloudFormationRefs returns the targets of the 'Ref' functions contained in the given value.
+func cloudFormationRefs(value interface{}) []string {
+	refs := []string{}
+	switch value := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := value["Ref"]; ok {
+			Expect(value).To(HaveLen(1))
+			Expect(ref).To(BeAssignableToTypeOf(""))
+			return append(refs, ref.(string))
+		}
+		for _, item := range value {
+			refs = append(refs, cloudFormationRefs(item)...)
+		}
+	case []interface{}:
+		for _, item := range value {
+			refs = append(refs, cloudFormationRefs(item)...)
+		}
+	}
+	return refs
+}
+
+var _ = Describe("Terraform", func() {
+	It("Generates a valid configuration", func() {
+		data, err := iac.Render(iac.Terraform, definition())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(validateTerraform(string(data))).To(ConsistOf(
+			"rosa_cluster.my_cluster",
+			"rosa_machine_pool.my_cluster_gpu",
+			"rosa_identity_provider.my_cluster_github_1",
+			"rosa_identity_provider.my_cluster_ldap_1",
+		))
+	})
+
+	It("States the provider that it requires", func() {
+		data, err := iac.Render(iac.Terraform, definition())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring("# Applying it requires a provider that implements " +
+			"the 'rosa_cluster', 'rosa_machine_pool' and\n# 'rosa_identity_provider' resources."))
+	})
+
+	It("Turns the secrets of identity providers into sensitive variables", func() {
+		data, err := iac.Render(iac.Terraform, definition())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring("variable \"github_1_client_secret\" {\n" +
+			"  type      = string\n  sensitive = true\n}\n"))
+		Expect(string(data)).To(ContainSubstring("client_secret = var.github_1_client_secret"))
+		Expect(string(data)).To(ContainSubstring("bind_password = var.ldap_1_bind_password"))
+	})
+
+	It("Escapes interpolation sequences in strings", func() {
+		data, err := iac.Render(iac.Terraform, definition())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring(`"cost-center" = "$${team}"`))
+	})
+})
+
+var _ = Describe("CloudFormation", func() {
+	It("Generates a valid template", func() {
+		data, err := iac.Render(iac.CloudFormation, definition())
+		Expect(err).ToNot(HaveOccurred())
+		var template struct {
+			AWSTemplateFormatVersion string
+			Description              string
+			Parameters               map[string]struct {
+				Type        string
+				NoEcho      bool
+				Description string
+			}
+			Resources map[string]struct {
+				Type       string
+				Properties map[string]interface{}
+			}
+		}
+		decoder := json.NewDecoder(strings.NewReader(string(data)))
+		decoder.DisallowUnknownFields()
+		Expect(decoder.Decode(&template)).To(Succeed())
+		Expect(template.AWSTemplateFormatVersion).To(Equal("2010-09-09"))
+		Expect(len(template.Description)).To(BeNumerically("<=", 1024))
+
+		refs := []string{}
+		types := map[string]string{}
+		for id, resource := range template.Resources {
+			Expect(id).To(MatchRegexp(cloudFormationIDRE.String()))
+			Expect(resource.Type).To(MatchRegexp(cloudFormationTypeRE.String()))
+			types[id] = resource.Type
+			refs = append(refs, cloudFormationRefs(resource.Properties)...)
+		}
+		for id, parameter := range template.Parameters {
+			Expect(id).To(MatchRegexp(cloudFormationIDRE.String()))
+			Expect(template.Resources).ToNot(HaveKey(id))
+			Expect(parameter.Type).To(Equal("String"))
+			Expect(parameter.NoEcho).To(BeTrue())
+			Expect(parameter.Description).ToNot(BeEmpty())
+			Expect(refs).To(ContainElement(id))
+		}
+		for _, ref := range refs {
+			_, isParameter := template.Parameters[ref]
+			_, isResource := template.Resources[ref]
+			Expect(isParameter || isResource).To(BeTrue(), ref)
+		}
+		Expect(types).To(Equal(map[string]string{
+			"Cluster":                 "RedHat::ROSA::Cluster",
+			"MachinePoolGpu":          "RedHat::ROSA::MachinePool",
+			"IdentityProviderGithub1": "RedHat::ROSA::IdentityProvider",
+			"IdentityProviderLdap1":   "RedHat::ROSA::IdentityProvider",
+		}))
+	})
+
+	It("States the resource types that it requires", func() {
+		data, err := iac.Render(iac.CloudFormation, definition())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring("it requires the RedHat::ROSA::Cluster, " +
+			"RedHat::ROSA::MachinePool and RedHat::ROSA::IdentityProvider resource types"))
+	})
+})
@@ -0,0 +1,234 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the renderer of Terraform configurations.
+
+package iac
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/openshift/moactl/pkg/cluster"
+)
+
+// Types of the Terraform resources:
+const (
+	terraformCluster          = "rosa_cluster"
+	terraformMachinePool      = "rosa_machine_pool"
+	terraformIdentityProvider = "rosa_identity_provider"
+)
+
+// terraformMaps are the settings that are maps of arbitrary keys, which are rendered as map
+// attributes instead of nested blocks.
+var terraformMaps = map[string]bool{
+	"tags":   true,
+	"labels": true,
+}
+
+// terraformExpression is a value that is rendered as is, like a reference to a variable or to
+// another resource.
+type terraformExpression string
+
+func renderTerraform(definition *cluster.Definition) ([]byte, error) {
+	parts, err := split(definition)
+	if err != nil {
+		return nil, err
+	}
+	buffer := &bytes.Buffer{}
+	clusterLabel := terraformLabel(definition.Name)
+	clusterID := terraformExpression(fmt.Sprintf("%s.%s.id", terraformCluster, clusterLabel))
+	fmt.Fprintf(buffer, "# Cluster '%s' exported with 'rosa describe cluster --export %s'.\n",
+		definition.Name, Terraform)
+	fmt.Fprintf(buffer, "# Applying it requires a provider that implements the '%s', '%s' and\n"+
+		"# '%s' resources.\n", terraformCluster, terraformMachinePool, terraformIdentityProvider)
+
+	// Secrets of identity providers are never exported, so they are variables:
+	for _, idp := range parts.identityProviders {
+		if idp.secret == "" {
+			continue
+		}
+		fmt.Fprintf(buffer, "\n# The %s of identity provider '%s'.\n", strings.ReplaceAll(idp.secret, "_", " "),
+			idp.name)
+		fmt.Fprintf(buffer, "variable %s {\n", terraformString(terraformVariable(idp)))
+		writeTerraformBody(buffer, 1, yaml.MapSlice{
+			{Key: "type", Value: terraformExpression("string")},
+			{Key: "sensitive", Value: true},
+		})
+		fmt.Fprintf(buffer, "}\n")
+	}
+
+	writeTerraformResource(buffer, terraformCluster, clusterLabel, parts.cluster)
+	for i, pool := range parts.machinePools {
+		label := terraformLabel(definition.Name, definition.MachinePools[i].Name)
+		writeTerraformResource(buffer, terraformMachinePool, label,
+			append(yaml.MapSlice{{Key: "cluster", Value: clusterID}}, pool...))
+	}
+	for _, idp := range parts.identityProviders {
+		settings := idp.settings
+		if idp.secret != "" {
+			settings = idp.withSecret(terraformExpression("var." + terraformVariable(idp)))
+		}
+		writeTerraformResource(buffer, terraformIdentityProvider, terraformLabel(definition.Name, idp.name),
+			append(yaml.MapSlice{{Key: "cluster", Value: clusterID}}, settings...))
+	}
+	return buffer.Bytes(), nil
+}
+
+func writeTerraformResource(buffer *bytes.Buffer, kind, label string, settings yaml.MapSlice) {
+	fmt.Fprintf(buffer, "\nresource %s %s {\n", terraformString(kind), terraformString(label))
+	writeTerraformBody(buffer, 1, settings)
+	fmt.Fprintf(buffer, "}\n")
+}
+
+// writeTerraformBody writes the given settings as the attributes and nested blocks of a block,
+// aligning the equal signs of consecutive attributes like 'terraform fmt' does.
+func writeTerraformBody(buffer *bytes.Buffer, depth int, settings yaml.MapSlice) {
+	indent := strings.Repeat("  ", depth)
+
+	// Each run of consecutive attributes is aligned separately:
+	widths := make([]int, len(settings))
+	start := 0
+	for i := range settings {
+		key := fmt.Sprint(settings[i].Key)
+		if isTerraformBlock(key, settings[i].Value) {
+			start = i + 1
+			continue
+		}
+		for j := start; j < i; j++ {
+			if len(key) > widths[j] {
+				widths[j] = len(key)
+			}
+			if widths[j] > widths[i] {
+				widths[i] = widths[j]
+			}
+		}
+		if len(key) > widths[i] {
+			widths[i] = len(key)
+		}
+	}
+
+	for i, item := range settings {
+		key := fmt.Sprint(item.Key)
+		width := widths[i]
+		switch value := item.Value.(type) {
+		case yaml.MapSlice:
+			if terraformMaps[key] {
+				fmt.Fprintf(buffer, "%s%-*s = {\n", indent, width, key)
+				writeTerraformMap(buffer, depth+1, value)
+				fmt.Fprintf(buffer, "%s}\n", indent)
+				continue
+			}
+			fmt.Fprintf(buffer, "%s%s {\n", indent, key)
+			writeTerraformBody(buffer, depth+1, value)
+			fmt.Fprintf(buffer, "%s}\n", indent)
+		case []interface{}:
+			if isTerraformBlock(key, value) {
+				for _, element := range value {
+					fmt.Fprintf(buffer, "%s%s {\n", indent, key)
+					writeTerraformBody(buffer, depth+1, element.(yaml.MapSlice))
+					fmt.Fprintf(buffer, "%s}\n", indent)
+				}
+				continue
+			}
+			elements := make([]string, len(value))
+			for i, element := range value {
+				elements[i] = terraformValue(element)
+			}
+			fmt.Fprintf(buffer, "%s%-*s = [%s]\n", indent, width, key, strings.Join(elements, ", "))
+		default:
+			fmt.Fprintf(buffer, "%s%-*s = %s\n", indent, width, key, terraformValue(value))
+		}
+	}
+}
+
+// writeTerraformMap writes the given settings as the elements of a map attribute.
+func writeTerraformMap(buffer *bytes.Buffer, depth int, settings yaml.MapSlice) {
+	indent := strings.Repeat("  ", depth)
+	keys := make([]string, len(settings))
+	width := 0
+	for i, item := range settings {
+		keys[i] = terraformString(fmt.Sprint(item.Key))
+		if len(keys[i]) > width {
+			width = len(keys[i])
+		}
+	}
+	for i, item := range settings {
+		fmt.Fprintf(buffer, "%s%-*s = %s\n", indent, width, keys[i], terraformValue(item.Value))
+	}
+}
+
+// isTerraformBlock checks if the given setting is rendered as one or more nested blocks instead of
+// as an attribute.
+func isTerraformBlock(key string, value interface{}) bool {
+	switch value := value.(type) {
+	case yaml.MapSlice:
+		return !terraformMaps[key]
+	case []interface{}:
+		for _, element := range value {
+			if _, ok := element.(yaml.MapSlice); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func terraformValue(value interface{}) string {
+	switch value := value.(type) {
+	case terraformExpression:
+		return string(value)
+	case string:
+		return terraformString(value)
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// terraformString quotes the given string, escaping the sequences that Terraform would otherwise
+// interpret as interpolations or directives.
+func terraformString(value string) string {
+	quoted := strconv.Quote(value)
+	quoted = strings.ReplaceAll(quoted, "${", "$${")
+	quoted = strings.ReplaceAll(quoted, "%{", "%%{")
+	return quoted
+}
+
+// terraformLabel returns the label of the resource with the given names, which can only contain
+// letters, digits, underscores and dashes, and can't start with a digit.
+func terraformLabel(names ...string) string {
+	var result []string
+	for _, name := range names {
+		result = append(result, words(name)...)
+	}
+	label := strings.Join(result, "_")
+	if label == "" || label[0] >= '0' && label[0] <= '9' {
+		label = "_" + label
+	}
+	return label
+}
+
+// terraformVariable returns the name of the variable that contains the secret of the given
+// identity provider.
+func terraformVariable(idp *identityProvider) string {
+	return terraformLabel(idp.name, idp.secret)
+}
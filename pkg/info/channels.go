@@ -0,0 +1,108 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that select the releases of the tool that belong to an update
+// channel.
+
+package info
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/openshift/moactl/pkg/config"
+)
+
+// Update channels. Besides these, a channel can be a minor version, like '1.2', to get only the
+// patch releases of that minor version.
+const (
+	ChannelStable = "stable"
+	ChannelLatest = "latest"
+)
+
+var minorChannelRE = regexp.MustCompile(`^v?[0-9]+\.[0-9]+$`)
+
+// ValidateChannel checks that the given update channel is 'stable', 'latest' or a minor version.
+func ValidateChannel(channel string) error {
+	if channel == ChannelStable || channel == ChannelLatest || minorChannelRE.MatchString(channel) {
+		return nil
+	}
+	return fmt.Errorf("Update channel '%s' isn't valid: it must be '%s', '%s' or a minor version like '1.2'",
+		channel, ChannelStable, ChannelLatest)
+}
+
+// ConfiguredChannel returns the update channel from the configuration file, or 'stable' if there
+// is none.
+func ConfiguredChannel() (string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return "", err
+	}
+	if cfg.Update == nil || cfg.Update.Channel == "" {
+		return ChannelStable, nil
+	}
+	err = ValidateChannel(cfg.Update.Channel)
+	if err != nil {
+		return "", fmt.Errorf("Configuration file: %v", err)
+	}
+	return cfg.Update.Channel, nil
+}
+
+// GetChannelReleases returns the published releases of the tool that belong to the given update
+// channel, newest first. The 'stable' channel contains the releases that aren't pre-releases, the
+// 'latest' channel contains the pre-releases as well, and a minor version channel contains the
+// releases of that minor version that aren't pre-releases.
+func GetChannelReleases(channel string) ([]*Release, error) {
+	return getChannelReleases(channel, releasesClient)
+}
+
+func getChannelReleases(channel string, client *http.Client) ([]*Release, error) {
+	err := ValidateChannel(channel)
+	if err != nil {
+		return nil, err
+	}
+	switch channel {
+	case ChannelStable:
+		return getReleases(client)
+	case ChannelLatest:
+		return getAllReleases(client)
+	}
+	releases, err := getReleases(client)
+	if err != nil {
+		return nil, err
+	}
+	result := []*Release{}
+	for _, release := range releases {
+		if inMinor(release.Version, channel) {
+			result = append(result, release)
+		}
+	}
+	return result, nil
+}
+
+// inMinor checks if the given version belongs to the given minor version.
+func inMinor(version string, minor string) bool {
+	parts, err := parseVersion(version)
+	if err != nil {
+		return false
+	}
+	for len(parts) < 2 {
+		parts = append(parts, 0)
+	}
+	return fmt.Sprintf("%d.%d", parts[0], parts[1]) == strings.TrimPrefix(minor, "v")
+}
@@ -0,0 +1,99 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the check that warns when the tool is too old, done at most once a day so
+// that it doesn't slow down the commands.
+
+package info
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/openshift/moactl/pkg/cache"
+	"github.com/openshift/moactl/pkg/config"
+)
+
+// checkClient is the HTTP client used by the check, with a short timeout so that commands don't
+// hang when the releases can't be reached.
+var checkClient = &http.Client{Timeout: 3 * time.Second}
+
+// CheckEnabled checks if the configuration file allows checking the version of the tool.
+func CheckEnabled() bool {
+	cfg, err := config.Load()
+	if err != nil {
+		return false
+	}
+	return cfg.Update == nil || !cfg.Update.DisableCheck
+}
+
+// NewestVersion returns the newest release of the update channel of the configuration file. The
+// result is cached for a day, including the failures, so that the releases are requested at most
+// once a day. An empty version means that it isn't known.
+func NewestVersion() (string, error) {
+	channel, err := ConfiguredChannel()
+	if err != nil {
+		return "", err
+	}
+	key := cache.Key("releases", channel)
+	if data, ok := cache.Get(key, cache.ReleasesTTL); ok {
+		var version string
+		if json.Unmarshal(data, &version) == nil {
+			return version, nil
+		}
+	}
+	version := ""
+	releases, err := getChannelReleases(channel, checkClient)
+	if err == nil && len(releases) > 0 {
+		version = releases[0].Version
+	}
+	data, _ := json.Marshal(version)
+	cache.Set(key, data)
+	return version, err
+}
+
+// CheckVersion returns a warning if the tool is older than the minor version of the newest
+// release, as older minor versions may not be able to create clusters with the versions of
+// OpenShift that are currently supported. Newer patch releases don't produce a warning. The
+// result is empty if the tool is recent enough or the newest release isn't known.
+func CheckVersion() (string, error) {
+	newest, err := NewestVersion()
+	if err != nil || newest == "" {
+		return "", err
+	}
+	current, err := parseVersion(Version)
+	if err != nil {
+		return "", err
+	}
+	latest, err := parseVersion(newest)
+	if err != nil {
+		return "", err
+	}
+	for len(current) < 2 {
+		current = append(current, 0)
+	}
+	for len(latest) < 2 {
+		latest = append(latest, 0)
+	}
+	if current[0] > latest[0] || (current[0] == latest[0] && current[1] >= latest[1]) {
+		return "", nil
+	}
+	return fmt.Sprintf("Version '%s' of rosa is older than the newest release '%s', and may not "+
+		"be able to create clusters with the currently supported OpenShift versions. Run 'rosa "+
+		"upgrade rosa' to update it", Version, newest), nil
+}
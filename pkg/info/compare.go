@@ -0,0 +1,68 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that compare versions of the tool.
+
+package info
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CompareVersions compares two versions made of dot separated numbers, like '0.1.3', returning
+// a negative number if the first is older than the second, zero if they are the same and a
+// positive number if it is newer. A leading 'v' and anything after a dash, like '-rc1', are
+// ignored. Missing components count as zero, so '0.1' and '0.1.0' are the same version.
+func CompareVersions(a string, b string) (int, error) {
+	left, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	right, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	for len(left) < len(right) {
+		left = append(left, 0)
+	}
+	for len(right) < len(left) {
+		right = append(right, 0)
+	}
+	for i := range left {
+		if left[i] != right[i] {
+			return left[i] - right[i], nil
+		}
+	}
+	return 0, nil
+}
+
+func parseVersion(version string) ([]int, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if dash := strings.Index(trimmed, "-"); dash >= 0 {
+		trimmed = trimmed[:dash]
+	}
+	result := []int{}
+	for _, part := range strings.Split(trimmed, ".") {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return nil, fmt.Errorf("Version '%s' isn't valid: it must be made of dot separated numbers", version)
+		}
+		result = append(result, number)
+	}
+	return result, nil
+}
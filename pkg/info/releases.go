@@ -0,0 +1,135 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that get the release metadata of the tool.
+
+package info
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// ReleasesURL is the address of the metadata of the published releases of the tool.
+const ReleasesURL = "https://api.github.com/repos/openshift/moactl/releases"
+
+// releasesClient is the HTTP client used by the commands that get the releases explicitly.
+var releasesClient = &http.Client{Timeout: 30 * time.Second}
+
+// Release contains the metadata of a published release of the tool.
+type Release struct {
+	Version    string
+	Published  time.Time
+	Notes      string
+	Prerelease bool
+
+	// Assets maps the names of the files attached to the release to their download URLs.
+	Assets map[string]string
+}
+
+// GetReleases returns the published releases of the tool, newest first. Drafts, pre-releases and
+// releases that aren't tagged with a version are ignored.
+func GetReleases() ([]*Release, error) {
+	return getReleases(releasesClient)
+}
+
+func getReleases(client *http.Client) ([]*Release, error) {
+	all, err := getAllReleases(client)
+	if err != nil {
+		return nil, err
+	}
+	releases := []*Release{}
+	for _, release := range all {
+		if !release.Prerelease {
+			releases = append(releases, release)
+		}
+	}
+	return releases, nil
+}
+
+// getAllReleases returns the published releases of the tool, including pre-releases, newest
+// first.
+func getAllReleases(client *http.Client) ([]*Release, error) {
+	response, err := client.Get(ReleasesURL)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Unexpected status code %d getting '%s'", response.StatusCode, ReleasesURL)
+	}
+	var body []struct {
+		TagName     string    `json:"tag_name"`
+		Body        string    `json:"body"`
+		Draft       bool      `json:"draft"`
+		Prerelease  bool      `json:"prerelease"`
+		PublishedAt time.Time `json:"published_at"`
+		Assets      []struct {
+			Name        string `json:"name"`
+			DownloadURL string `json:"browser_download_url"`
+		} `json:"assets"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&body)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse release metadata: %v", err)
+	}
+
+	releases := []*Release{}
+	for _, item := range body {
+		if item.Draft {
+			continue
+		}
+		if _, err := parseVersion(item.TagName); err != nil {
+			continue
+		}
+		assets := map[string]string{}
+		for _, asset := range item.Assets {
+			assets[asset.Name] = asset.DownloadURL
+		}
+		releases = append(releases, &Release{
+			Version:    item.TagName,
+			Published:  item.PublishedAt,
+			Notes:      item.Body,
+			Prerelease: item.Prerelease,
+			Assets:     assets,
+		})
+	}
+	sort.SliceStable(releases, func(i, j int) bool {
+		comparison, _ := CompareVersions(releases[i].Version, releases[j].Version)
+		return comparison > 0
+	})
+	return releases, nil
+}
+
+// ReleasesSince returns the releases, from a list sorted newest first, that are newer than the
+// given version.
+func ReleasesSince(releases []*Release, version string) ([]*Release, error) {
+	result := []*Release{}
+	for _, release := range releases {
+		comparison, err := CompareVersions(release.Version, version)
+		if err != nil {
+			return nil, err
+		}
+		if comparison <= 0 {
+			break
+		}
+		result = append(result, release)
+	}
+	return result, nil
+}
@@ -20,6 +20,8 @@ package interactive
 
 import (
 	"github.com/spf13/pflag"
+
+	"github.com/openshift/moactl/pkg/ci"
 )
 
 // AddFlag adds the interactive flag to the given set of command line flags.
@@ -33,9 +35,10 @@ func AddFlag(flags *pflag.FlagSet) {
 	)
 }
 
-// Enabled retursn a boolean flag that indicates if the interactive mode is enabled.
+// Enabled retursn a boolean flag that indicates if the interactive mode is enabled. It is always
+// disabled in CI mode.
 func Enabled() bool {
-	return enabled
+	return enabled && !ci.Enabled()
 }
 
 //Enables the interactive mode
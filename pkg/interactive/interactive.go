@@ -22,10 +22,14 @@ import (
 	"os"
 	"regexp"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/AlecAivazis/survey/v2/core"
 	"github.com/AlecAivazis/survey/v2/terminal"
+
+	"github.com/openshift/moactl/pkg/ci"
 )
 
 type Input struct {
@@ -34,6 +38,12 @@ type Input struct {
 	Options  []string
 	Default  interface{}
 	Required bool
+	// Validators check the answer before it is accepted. Answers that don't pass them are asked
+	// again. They are only used by the prompts that accept free text.
+	Validators []Validator
+	// Fuzzy makes the option prompts match the options that contain the typed characters in the
+	// same order, instead of the default substring match.
+	Fuzzy bool
 }
 
 // Gets user input from the command line
@@ -60,11 +70,7 @@ func GetString(input Input) (a string, err error) {
 		Help:    input.Help,
 		Default: dflt,
 	}
-	if input.Required {
-		err = survey.AskOne(prompt, &a, survey.WithValidator(survey.Required))
-		return
-	}
-	err = survey.AskOne(prompt, &a)
+	err = survey.AskOne(prompt, &a, askOptions(input)...)
 	return
 }
 
@@ -87,12 +93,8 @@ func GetInt(input Input) (a int, err error) {
 		Help:    input.Help,
 		Default: dfltStr,
 	}
-	if input.Required {
-		err = survey.AskOne(prompt, &a, survey.WithValidator(survey.Required))
-		return
-	}
 	var str string
-	err = survey.AskOne(prompt, &str)
+	err = survey.AskOne(prompt, &str, askOptions(input, IntValidator(func(int) error { return nil }))...)
 	if err != nil {
 		return
 	}
@@ -149,6 +151,9 @@ func GetOption(input Input) (a string, err error) {
 		Options: input.Options,
 		Default: dflt,
 	}
+	if input.Fuzzy {
+		prompt.Filter = fuzzyFilter
+	}
 	if input.Required {
 		err = survey.AskOne(prompt, &a, survey.WithValidator(survey.Required))
 		return
@@ -157,6 +162,43 @@ func GetOption(input Input) (a string, err error) {
 	return
 }
 
+// fuzzyFilter matches the options that contain all the characters typed by the user in the same
+// order, but not necessarily next to each other. For example, 'mc1' matches 'my-cluster-1'.
+func fuzzyFilter(filter string, value string, _ int) bool {
+	value = strings.ToLower(value)
+	for _, r := range strings.ToLower(filter) {
+		i := strings.IndexRune(value, r)
+		if i < 0 {
+			return false
+		}
+		value = value[i+utf8.RuneLen(r):]
+	}
+	return true
+}
+
+// IsTerminal checks if both the standard input and output are connected to a terminal, so that the
+// user can be prompted for input. In CI mode the user is never prompted, so it always returns false.
+func IsTerminal() bool {
+	if ci.Enabled() {
+		return false
+	}
+	return isCharDevice(os.Stdin) && isCharDevice(os.Stdout)
+}
+
+// IsInputTerminal checks if the standard input is connected to a terminal, so that the user can
+// answer questions even if the output is redirected. In CI mode it always returns false.
+func IsInputTerminal() bool {
+	if ci.Enabled() {
+		return false
+	}
+	return isCharDevice(os.Stdin)
+}
+
+func isCharDevice(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
 // Asks for true/false value in the command line
 func GetBool(input Input) (a bool, err error) {
 	dflt, ok := input.Default.(bool)
@@ -199,12 +241,8 @@ func GetIPNet(input Input) (a net.IPNet, err error) {
 		Help:    input.Help,
 		Default: dfltStr,
 	}
-	if input.Required {
-		err = survey.AskOne(prompt, &a, survey.WithValidator(survey.Required))
-		return
-	}
 	var str string
-	err = survey.AskOne(prompt, &str)
+	err = survey.AskOne(prompt, &str, askOptions(input, cidrValidator)...)
 	if err != nil {
 		return
 	}
@@ -222,24 +260,6 @@ func GetIPNet(input Input) (a net.IPNet, err error) {
 	return
 }
 
-// Gets password input from the command line
-func GetPassword(input Input) (a string, err error) {
-	question := input.Question
-	if !input.Required {
-		question = fmt.Sprintf("%s (optional)", question)
-	}
-	prompt := &survey.Password{
-		Message: fmt.Sprintf("%s:", question),
-		Help:    input.Help,
-	}
-	if input.Required {
-		err = survey.AskOne(prompt, &a, survey.WithValidator(survey.Required))
-		return
-	}
-	err = survey.AskOne(prompt, &a)
-	return
-}
-
 // Gets path to certificate file from the command line
 func GetCert(input Input) (a string, err error) {
 	dflt, ok := input.Default.(string)
@@ -0,0 +1,127 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interactive
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"golang.org/x/crypto/ssh/terminal"
+
+	"github.com/openshift/moactl/pkg/exit"
+)
+
+// Gets password input from the command line. Echo is disabled while the password is typed, on all
+// the platforms, and the trailing newline that is added when a password is pasted is removed. If
+// the standard input isn't a terminal the password is read from the first line of the input, so
+// that it can be piped.
+func GetPassword(input Input) (a string, err error) {
+	question := input.Question
+	if !input.Required {
+		question = fmt.Sprintf("%s (optional)", question)
+	}
+	if input.Help != "" {
+		question = fmt.Sprintf("%s [? for help]", question)
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !terminal.IsTerminal(fd) {
+		return readPipedPassword()
+	}
+
+	for {
+		fmt.Printf("? %s: ", question)
+		a, err = readPassword(fd)
+		fmt.Println()
+		if err != nil {
+			return
+		}
+		switch {
+		case a == "?" && input.Help != "":
+			fmt.Printf("  %s\n", input.Help)
+		case a == "" && input.Required:
+			fmt.Println("X Sorry, your reply was invalid: Value is required")
+		default:
+			return
+		}
+	}
+}
+
+// readPassword reads a line from the terminal without echo. If the user interrupts the program
+// while typing the terminal is restored before exiting, otherwise echo would remain disabled in
+// the shell.
+func readPassword(fd int) (string, error) {
+	state, err := terminal.GetState(fd)
+	if err != nil {
+		return "", err
+	}
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	done := make(chan struct{})
+	defer func() {
+		signal.Stop(interrupts)
+		close(done)
+	}()
+	go func() {
+		select {
+		case <-interrupts:
+			_ = terminal.Restore(fd, state)
+			fmt.Println()
+			exit.Exit(1)
+		case <-done:
+		}
+	}()
+
+	line, err := terminal.ReadPassword(fd)
+	if err != nil {
+		return "", err
+	}
+	return trimNewline(string(line)), nil
+}
+
+// readPipedPassword reads a line from the standard input. It reads one byte at a time, so that
+// the rest of the input is left for the next prompt.
+func readPipedPassword() (string, error) {
+	line := []byte{}
+	buffer := make([]byte, 1)
+	for {
+		n, err := os.Stdin.Read(buffer)
+		if n > 0 {
+			if buffer[0] == '\n' {
+				break
+			}
+			line = append(line, buffer[0])
+			continue
+		}
+		if err == io.EOF && len(line) > 0 {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("Failed to read password from the standard input: %v", err)
+		}
+	}
+	return trimNewline(string(line)), nil
+}
+
+// trimNewline removes the line terminators that terminals and clipboards add at the end of pasted
+// text, on Windows as well as on other platforms.
+func trimNewline(value string) string {
+	return strings.TrimRight(value, "\r\n")
+}
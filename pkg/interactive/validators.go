@@ -0,0 +1,86 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the validators that the prompts use to check the answers of the user, so
+// that invalid answers are asked again instead of aborting the command.
+
+package interactive
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+
+	"github.com/AlecAivazis/survey/v2"
+)
+
+// Validator checks the answer typed by the user. The answer is always the string typed, before it
+// is converted to the type of the prompt.
+type Validator func(answer interface{}) error
+
+// IntValidator returns a validator that checks that the answer is a number and that it passes the
+// given check. Empty answers are accepted, as they select the default.
+func IntValidator(check func(int) error) Validator {
+	return func(answer interface{}) error {
+		str, ok := answer.(string)
+		if !ok || str == "" {
+			return nil
+		}
+		value, err := strconv.Atoi(str)
+		if err != nil {
+			return fmt.Errorf("'%s' isn't a valid number", str)
+		}
+		return check(value)
+	}
+}
+
+// RegExpValidator returns a validator that checks that the answer matches the given regular
+// expression, and reports the given message otherwise.
+func RegExpValidator(re *regexp.Regexp, message string) Validator {
+	return func(answer interface{}) error {
+		str, ok := answer.(string)
+		if !ok || str == "" || re.MatchString(str) {
+			return nil
+		}
+		return fmt.Errorf("%s", message)
+	}
+}
+
+// cidrValidator checks that the answer is a valid CIDR block.
+func cidrValidator(answer interface{}) error {
+	str, ok := answer.(string)
+	if !ok || str == "" {
+		return nil
+	}
+	_, _, err := net.ParseCIDR(str)
+	if err != nil {
+		return fmt.Errorf("'%s' isn't a valid CIDR block", str)
+	}
+	return nil
+}
+
+// askOptions returns the survey options that check the answer with the validators of the input.
+func askOptions(input Input, validators ...Validator) []survey.AskOpt {
+	options := []survey.AskOpt{}
+	if input.Required {
+		options = append(options, survey.WithValidator(survey.Required))
+	}
+	for _, validator := range append(validators, input.Validators...) {
+		options = append(options, survey.WithValidator(survey.Validator(validator)))
+	}
+	return options
+}
@@ -0,0 +1,458 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains a minimal LDAP client, enough to check that the settings of an LDAP
+// identity provider can be used to bind to the server and search the base DN before creating it.
+
+package ldap
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Config contains the settings of the LDAP identity provider that are needed for the test.
+type Config struct {
+	URL          string
+	Insecure     bool
+	CA           string
+	BindDN       string
+	BindPassword string
+	Timeout      time.Duration
+}
+
+// LDAP result codes that have specific explanations:
+const (
+	resultSuccess                 = 0
+	resultStrongerAuthRequired    = 8
+	resultReferral                = 10
+	resultConfidentialityRequired = 13
+	resultNoSuchObject            = 32
+	resultInvalidDNSyntax         = 34
+	resultInvalidCredentials      = 49
+	resultInsufficientAccess      = 50
+)
+
+// Tags of the protocol operations used:
+const (
+	tagBindRequest      = 0x60
+	tagBindResponse     = 0x61
+	tagSearchRequest    = 0x63
+	tagSearchEntry      = 0x64
+	tagSearchDone       = 0x65
+	tagSearchReference  = 0x73
+	tagExtendedRequest  = 0x77
+	tagExtendedResponse = 0x78
+)
+
+const startTLSOID = "1.3.6.1.4.1.1466.20037"
+
+// maxLength is the maximum length of the elements read from the server. The responses to the
+// operations used are small, so longer elements are rejected instead of allocating memory for them.
+const maxLength = 1 << 20
+
+// ResultError is an unsuccessful result returned by the LDAP server.
+type ResultError struct {
+	Operation string
+	DN        string
+	Code      int
+	MatchedDN string
+	Message   string
+	Referrals []string
+}
+
+func (e *ResultError) Error() string {
+	var msg string
+	switch e.Code {
+	case resultReferral:
+		msg = fmt.Sprintf("the server returned a referral to %s: use the URL of the server that "+
+			"holds the base DN", strings.Join(e.Referrals, ", "))
+	case resultNoSuchObject:
+		msg = fmt.Sprintf("base DN '%s' doesn't exist", e.DN)
+		if e.MatchedDN != "" {
+			msg += fmt.Sprintf(", the closest existing entry is '%s'", e.MatchedDN)
+		}
+	case resultInvalidDNSyntax:
+		msg = fmt.Sprintf("'%s' isn't a valid DN: check the syntax, for example "+
+			"'cn=admin,dc=example,dc=com'", e.DN)
+	case resultInvalidCredentials:
+		msg = fmt.Sprintf("the server rejected the bind DN '%s' or the bind password", e.DN)
+	case resultInsufficientAccess:
+		msg = fmt.Sprintf("the bind DN isn't allowed to search '%s'", e.DN)
+	case resultStrongerAuthRequired, resultConfidentialityRequired:
+		msg = "the server requires a secure connection: don't use '--insecure'"
+	default:
+		msg = fmt.Sprintf("result code %d", e.Code)
+	}
+	if e.Message != "" {
+		msg += fmt.Sprintf(" (%s)", e.Message)
+	}
+	return fmt.Sprintf("LDAP %s failed: %s", e.Operation, msg)
+}
+
+// Test connects to the server of the given URL, using TLS unless the connection is insecure,
+// binds with the given DN and password, or anonymously if there is no DN, and searches the base
+// DN of the URL. The returned errors explain the most common problems.
+func Test(config Config) error {
+	parsed, err := url.Parse(config.URL)
+	if err != nil {
+		return fmt.Errorf("Expected a valid LDAP URL: %v", err)
+	}
+	host := parsed.Hostname()
+	port := parsed.Port()
+	if port == "" {
+		port = "389"
+		if parsed.Scheme == "ldaps" {
+			port = "636"
+		}
+	}
+	address := net.JoinHostPort(host, port)
+	baseDN := strings.TrimPrefix(parsed.Path, "/")
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return fmt.Errorf("Failed to connect to '%s': %v", address, err)
+	}
+	defer conn.Close()
+	err = conn.SetDeadline(time.Now().Add(timeout))
+	if err != nil {
+		return err
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName: host,
+		MinVersion: tls.VersionTLS12,
+	}
+	if config.CA != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(config.CA)) {
+			return errors.New("The CA bundle doesn't contain any valid certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	c := &client{conn: conn, reader: bufio.NewReader(conn)}
+	switch {
+	case parsed.Scheme == "ldaps":
+		err = c.upgrade(tlsConfig)
+	case !config.Insecure:
+		err = c.startTLS(tlsConfig)
+	}
+	if err != nil {
+		return tlsError(address, err)
+	}
+
+	err = c.bind(config.BindDN, config.BindPassword)
+	if err != nil {
+		return err
+	}
+	if baseDN != "" {
+		err = c.search(baseDN)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tlsError explains the certificate problems that prevent a secure connection.
+func tlsError(address string, err error) error {
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var invalid x509.CertificateInvalidError
+	switch {
+	case errors.As(err, &unknownAuthority):
+		return fmt.Errorf("The certificate of '%s' is signed by an unknown authority: "+
+			"use '--ca' to provide the CA bundle of the server", address)
+	case errors.As(err, &hostname):
+		return fmt.Errorf("The certificate of '%s' isn't valid for the host of the URL: %v", address, err)
+	case errors.As(err, &invalid):
+		return fmt.Errorf("The certificate of '%s' isn't valid: %v", address, err)
+	case errors.As(err, new(*ResultError)):
+		return err
+	}
+	return fmt.Errorf("Failed to establish a secure connection to '%s': %v", address, err)
+}
+
+type client struct {
+	conn      net.Conn
+	reader    *bufio.Reader
+	messageID int
+}
+
+func (c *client) upgrade(config *tls.Config) error {
+	conn := tls.Client(c.conn, config)
+	err := conn.Handshake()
+	if err != nil {
+		return err
+	}
+	c.conn = conn
+	c.reader = bufio.NewReader(conn)
+	return nil
+}
+
+func (c *client) startTLS(config *tls.Config) error {
+	op := tlv(tagExtendedRequest, tlv(0x80, []byte(startTLSOID)))
+	result, err := c.roundTrip(op, tagExtendedResponse)
+	if err != nil {
+		return err
+	}
+	if result.Code != resultSuccess {
+		result.Operation = "StartTLS"
+		return result
+	}
+	return c.upgrade(config)
+}
+
+func (c *client) bind(dn string, password string) error {
+	op := tlv(tagBindRequest, concat(
+		integer(3),
+		octetString(dn),
+		tlv(0x80, []byte(password)),
+	))
+	result, err := c.roundTrip(op, tagBindResponse)
+	if err != nil {
+		return err
+	}
+	if result.Code != resultSuccess {
+		result.Operation = "bind"
+		result.DN = dn
+		return result
+	}
+	return nil
+}
+
+// search performs a base search of the given DN that doesn't return any attribute, just to
+// check that it exists and can be read.
+func (c *client) search(dn string) error {
+	op := tlv(tagSearchRequest, concat(
+		octetString(dn),
+		tlv(0x0a, []byte{0}), // Scope: base object
+		tlv(0x0a, []byte{0}), // Dereference aliases: never
+		integer(1),           // Size limit
+		integer(0),           // Time limit
+		tlv(0x01, []byte{0}), // Types only: false
+		tlv(0x87, []byte("objectClass")),
+		tlv(0x30, octetString("1.1")), // No attributes
+	))
+	err := c.send(op)
+	if err != nil {
+		return err
+	}
+	for {
+		tag, content, err := c.receive()
+		if err != nil {
+			return err
+		}
+		switch tag {
+		case tagSearchEntry, tagSearchReference:
+			continue
+		case tagSearchDone:
+			result, err := parseResult(content)
+			if err != nil {
+				return err
+			}
+			if result.Code != resultSuccess {
+				result.Operation = "search"
+				result.DN = dn
+				return result
+			}
+			return nil
+		default:
+			return fmt.Errorf("Unexpected LDAP response with tag 0x%x", tag)
+		}
+	}
+}
+
+func (c *client) roundTrip(op []byte, expected byte) (*ResultError, error) {
+	err := c.send(op)
+	if err != nil {
+		return nil, err
+	}
+	tag, content, err := c.receive()
+	if err != nil {
+		return nil, err
+	}
+	if tag != expected {
+		return nil, fmt.Errorf("Unexpected LDAP response with tag 0x%x", tag)
+	}
+	return parseResult(content)
+}
+
+func (c *client) send(op []byte) error {
+	c.messageID++
+	_, err := c.conn.Write(tlv(0x30, concat(integer(c.messageID), op)))
+	return err
+}
+
+// receive reads the next message and returns the tag and content of its protocol operation.
+func (c *client) receive() (byte, []byte, error) {
+	tag, message, err := readTLV(c.reader)
+	if err != nil {
+		return 0, nil, fmt.Errorf("Failed to read LDAP response: %v", err)
+	}
+	if tag != 0x30 {
+		return 0, nil, fmt.Errorf("Unexpected LDAP message with tag 0x%x", tag)
+	}
+	elements, err := parseElements(message)
+	if err != nil || len(elements) < 2 {
+		return 0, nil, errors.New("Failed to parse LDAP response")
+	}
+	return elements[1].tag, elements[1].content, nil
+}
+
+// parseResult parses the components of an LDAP result, ignoring the success flag that the
+// caller has to check.
+func parseResult(content []byte) (*ResultError, error) {
+	elements, err := parseElements(content)
+	if err != nil || len(elements) < 3 {
+		return nil, errors.New("Failed to parse LDAP result")
+	}
+	result := &ResultError{
+		Code:      decodeInteger(elements[0].content),
+		MatchedDN: string(elements[1].content),
+		Message:   string(elements[2].content),
+	}
+	for _, element := range elements[3:] {
+		if element.tag != 0xa3 {
+			continue
+		}
+		referrals, err := parseElements(element.content)
+		if err != nil {
+			return nil, err
+		}
+		for _, referral := range referrals {
+			result.Referrals = append(result.Referrals, string(referral.content))
+		}
+	}
+	return result, nil
+}
+
+type element struct {
+	tag     byte
+	content []byte
+}
+
+func parseElements(data []byte) ([]element, error) {
+	elements := []element{}
+	reader := bufio.NewReader(bytes.NewReader(data))
+	for {
+		tag, content, err := readTLV(reader)
+		if err == io.EOF {
+			return elements, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, element{tag: tag, content: content})
+	}
+}
+
+func readTLV(reader *bufio.Reader) (byte, []byte, error) {
+	tag, err := reader.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	first, err := reader.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length := int(first)
+	if first&0x80 != 0 {
+		count := int(first & 0x7f)
+		if count == 0 || count > 4 {
+			return 0, nil, errors.New("Unsupported length")
+		}
+		length = 0
+		for i := 0; i < count; i++ {
+			b, err := reader.ReadByte()
+			if err != nil {
+				return 0, nil, err
+			}
+			length = length<<8 | int(b)
+			if length > maxLength {
+				return 0, nil, fmt.Errorf("Length exceeds the maximum of %d bytes", maxLength)
+			}
+		}
+	}
+	content := make([]byte, length)
+	_, err = io.ReadFull(reader, content)
+	if err != nil {
+		return 0, nil, err
+	}
+	return tag, content, nil
+}
+
+func tlv(tag byte, content []byte) []byte {
+	length := len(content)
+	var header []byte
+	switch {
+	case length < 0x80:
+		header = []byte{tag, byte(length)}
+	case length < 0x100:
+		header = []byte{tag, 0x81, byte(length)}
+	case length < 0x10000:
+		header = []byte{tag, 0x82, byte(length >> 8), byte(length)}
+	default:
+		header = []byte{tag, 0x83, byte(length >> 16), byte(length >> 8), byte(length)}
+	}
+	return append(header, content...)
+}
+
+func integer(value int) []byte {
+	content := []byte{byte(value)}
+	for value >>= 8; value > 0; value >>= 8 {
+		content = append([]byte{byte(value)}, content...)
+	}
+	// Positive values must not have the sign bit set:
+	if content[0]&0x80 != 0 {
+		content = append([]byte{0}, content...)
+	}
+	return tlv(0x02, content)
+}
+
+func decodeInteger(content []byte) int {
+	value := 0
+	for _, b := range content {
+		value = value<<8 | int(b)
+	}
+	return value
+}
+
+func octetString(value string) []byte {
+	return tlv(0x04, []byte(value))
+}
+
+func concat(parts ...[]byte) []byte {
+	result := []byte{}
+	for _, part := range parts {
+		result = append(result, part...)
+	}
+	return result
+}
@@ -0,0 +1,191 @@
+package ldap
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// read decodes a single element from the given bytes.
+func read(data []byte) (byte, []byte, error) {
+	return readTLV(bufio.NewReader(bytes.NewReader(data)))
+}
+
+// result encodes an LDAP result with the given tag, code, matched DN and message.
+func result(tag byte, code int, matchedDN string, message string) []byte {
+	return tlv(tag, concat(
+		tlv(0x0a, []byte{byte(code)}),
+		octetString(matchedDN),
+		octetString(message),
+	))
+}
+
+var _ = Describe("Encoding", func() {
+	It("Encodes short and long lengths", func() {
+		Expect(tlv(0x04, []byte("abc"))).To(Equal([]byte{0x04, 0x03, 'a', 'b', 'c'}))
+		Expect(tlv(0x04, make([]byte, 200))[:3]).To(Equal([]byte{0x04, 0x81, 200}))
+		Expect(tlv(0x04, make([]byte, 300))[:4]).To(Equal([]byte{0x04, 0x82, 0x01, 0x2c}))
+		Expect(tlv(0x04, make([]byte, 0x10000))[:5]).To(Equal([]byte{0x04, 0x83, 0x01, 0x00, 0x00}))
+	})
+
+	It("Encodes positive integers without the sign bit", func() {
+		Expect(integer(0)).To(Equal([]byte{0x02, 0x01, 0x00}))
+		Expect(integer(3)).To(Equal([]byte{0x02, 0x01, 0x03}))
+		Expect(integer(128)).To(Equal([]byte{0x02, 0x02, 0x00, 0x80}))
+		Expect(integer(0x1234)).To(Equal([]byte{0x02, 0x02, 0x12, 0x34}))
+	})
+
+	It("Decodes what it encodes", func() {
+		for _, size := range []int{0, 1, 127, 128, 255, 256, 70000} {
+			content := bytes.Repeat([]byte{'x'}, size)
+			tag, decoded, err := read(tlv(0x04, content))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tag).To(Equal(byte(0x04)))
+			Expect(decoded).To(Equal(content))
+		}
+		_, content, err := read(integer(0x1234))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(decodeInteger(content)).To(Equal(0x1234))
+	})
+})
+
+var _ = Describe("Decoding", func() {
+	It("Parses results with referrals", func() {
+		content := concat(
+			tlv(0x0a, []byte{resultReferral}),
+			octetString(""),
+			octetString("moved"),
+			tlv(0xa3, concat(octetString("ldap://a.example.com"), octetString("ldap://b.example.com"))),
+		)
+		parsed, err := parseResult(content)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsed.Code).To(Equal(resultReferral))
+		Expect(parsed.Message).To(Equal("moved"))
+		Expect(parsed.Referrals).To(Equal([]string{"ldap://a.example.com", "ldap://b.example.com"}))
+	})
+
+	It("Rejects lengths longer than the maximum without allocating them", func() {
+		_, _, err := read([]byte{0x04, 0x84, 0xff, 0xff, 0xff, 0xff})
+		Expect(err).To(MatchError(ContainSubstring("exceeds the maximum")))
+		_, _, err = read([]byte{0x04, 0x83, 0x10, 0x00, 0x01})
+		Expect(err).To(MatchError(ContainSubstring("exceeds the maximum")))
+	})
+
+	It("Rejects lengths with an unsupported number of bytes", func() {
+		_, _, err := read([]byte{0x04, 0x80})
+		Expect(err).To(HaveOccurred())
+		_, _, err = read([]byte{0x04, 0x85, 0, 0, 0, 0, 1})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Rejects truncated elements", func() {
+		_, _, err := read([]byte{0x04})
+		Expect(err).To(HaveOccurred())
+		_, _, err = read([]byte{0x04, 0x82, 0x01})
+		Expect(err).To(HaveOccurred())
+		_, _, err = read([]byte{0x04, 0x05, 'a', 'b'})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Rejects results with missing components", func() {
+		_, err := parseResult(tlv(0x0a, []byte{0}))
+		Expect(err).To(HaveOccurred())
+		_, err = parseResult([]byte{0x0a, 0x05, 0x00})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Test", func() {
+	var listener net.Listener
+
+	// serve answers the requests of a single connection with the given responses, in order:
+	serve := func(responses ...[]byte) {
+		go func() {
+			defer GinkgoRecover()
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			reader := bufio.NewReader(conn)
+			for i, response := range responses {
+				_, _, err := readTLV(reader)
+				if err != nil {
+					return
+				}
+				_, err = conn.Write(tlv(0x30, concat(integer(i+1), response)))
+				if err != nil {
+					return
+				}
+			}
+		}()
+	}
+
+	// config returns the configuration for the test server and the given base DN:
+	config := func(baseDN string) Config {
+		return Config{
+			URL:          fmt.Sprintf("ldap://%s/%s", listener.Addr(), baseDN),
+			Insecure:     true,
+			BindDN:       "cn=admin,dc=example,dc=com",
+			BindPassword: "secret",
+		}
+	}
+
+	BeforeEach(func() {
+		var err error
+		listener, err = net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		listener.Close()
+	})
+
+	It("Succeeds when the bind and the search succeed", func() {
+		serve(
+			result(tagBindResponse, resultSuccess, "", ""),
+			result(tagSearchDone, resultSuccess, "", ""),
+		)
+		Expect(Test(config("dc=example,dc=com"))).To(Succeed())
+	})
+
+	It("Explains invalid credentials", func() {
+		serve(result(tagBindResponse, resultInvalidCredentials, "", "bad password"))
+		err := Test(config("dc=example,dc=com"))
+		Expect(err).To(MatchError(ContainSubstring("rejected the bind DN 'cn=admin,dc=example,dc=com'")))
+		Expect(err).To(MatchError(ContainSubstring("bad password")))
+	})
+
+	It("Explains a base DN that doesn't exist", func() {
+		serve(
+			result(tagBindResponse, resultSuccess, "", ""),
+			result(tagSearchDone, resultNoSuchObject, "dc=example,dc=com", ""),
+		)
+		err := Test(config("ou=missing,dc=example,dc=com"))
+		Expect(err).To(MatchError(ContainSubstring("base DN 'ou=missing,dc=example,dc=com' doesn't exist")))
+		Expect(err).To(MatchError(ContainSubstring("closest existing entry is 'dc=example,dc=com'")))
+	})
+
+	It("Fails on responses with unexpected tags", func() {
+		serve(result(tagSearchDone, resultSuccess, "", ""))
+		Expect(Test(config(""))).To(MatchError(ContainSubstring("Unexpected LDAP response")))
+	})
+
+	It("Fails on responses that are too long", func() {
+		go func() {
+			defer GinkgoRecover()
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			_, _, _ = readTLV(bufio.NewReader(conn))
+			_, _ = conn.Write([]byte{0x30, 0x84, 0x7f, 0xff, 0xff, 0xff})
+		}()
+		Expect(Test(config(""))).To(MatchError(ContainSubstring("exceeds the maximum")))
+	})
+})
@@ -0,0 +1,135 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to implement the '--v' command line option, that selects
+// the log level of each subsystem, so that for example the messages of the AWS SDK can be silenced
+// while debugging the requests sent to OCM.
+
+package logging
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+)
+
+// Subsystems whose log level can be selected:
+const (
+	OCM = "ocm"
+	AWS = "aws"
+)
+
+var subsystems = []string{OCM, AWS}
+
+// levels contains the log levels selected with the '--v' flag, indexed by subsystem.
+var levels = map[string]logrus.Level{}
+
+// AddLevelsFlag adds the '--v' flag to the given set of command line flags.
+func AddLevelsFlag(fs *pflag.FlagSet) {
+	fs.VarP(
+		&levelsValue{},
+		"v",
+		"v",
+		"Log level of each subsystem, for example 'ocm=debug,aws=warn'. The subsystems are 'ocm' "+
+			"and 'aws', and the levels are 'debug', 'info', 'warn' and 'error'. Subsystems that "+
+			"aren't given use the level selected with '--debug'.",
+	)
+}
+
+// ParseLevels parses a comma separated list of 'subsystem=level' pairs.
+func ParseLevels(text string) (map[string]logrus.Level, error) {
+	result := map[string]logrus.Level{}
+	for _, item := range strings.Split(text, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		equals := strings.Index(item, "=")
+		if equals < 0 {
+			return nil, fmt.Errorf("Log level '%s' isn't valid: it must be a 'subsystem=level' pair, "+
+				"like 'ocm=debug'", item)
+		}
+		name := strings.ToLower(strings.TrimSpace(item[:equals]))
+		if !isSubsystem(name) {
+			return nil, fmt.Errorf("Subsystem '%s' isn't valid: it must be one of '%s'", name,
+				strings.Join(subsystems, "', '"))
+		}
+		level, err := logrus.ParseLevel(strings.TrimSpace(item[equals+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("Log level of subsystem '%s' isn't valid: %v", name, err)
+		}
+		result[name] = level
+	}
+	return result, nil
+}
+
+func isSubsystem(name string) bool {
+	for _, subsystem := range subsystems {
+		if name == subsystem {
+			return true
+		}
+	}
+	return false
+}
+
+// Subsystem returns the logger that the given subsystem should use. It writes to the same output
+// as the given logger, with the level selected for the subsystem, if there is one.
+func Subsystem(logger *logrus.Logger, name string) *logrus.Logger {
+	level, ok := levels[name]
+	if !ok {
+		return logger
+	}
+	result := logrus.New()
+	result.SetOutput(logger.Out)
+	result.SetFormatter(logger.Formatter)
+	result.ReplaceHooks(logger.Hooks)
+	result.SetLevel(level)
+	return result
+}
+
+// levelsValue implements the value of the '--v' flag.
+type levelsValue struct {
+	text string
+}
+
+func (v *levelsValue) String() string {
+	return v.text
+}
+
+func (v *levelsValue) Type() string {
+	return "levels"
+}
+
+func (v *levelsValue) Set(text string) error {
+	// The flag used to be the verbosity of the 'glog' package, so numbers are still passed to it:
+	if _, err := strconv.Atoi(text); err == nil && flag.Lookup("v") != nil {
+		v.text = text
+		return flag.Set("v", text)
+	}
+	parsed, err := ParseLevels(text)
+	if err != nil {
+		return err
+	}
+	for name, level := range parsed {
+		levels[name] = level
+	}
+	v.text = text
+	return nil
+}
@@ -19,11 +19,10 @@ limitations under the License.
 package logging
 
 import (
-	"os"
-
 	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/moactl/pkg/debug"
+	"github.com/openshift/moactl/pkg/exit"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
 )
 
@@ -42,10 +41,17 @@ func NewLogger() *LoggerBuilder {
 func (b *LoggerBuilder) Build() (result *logrus.Logger, err error) {
 	// Create the logger:
 	result = logrus.New()
-	result.SetFormatter(&logrus.TextFormatter{
-		DisableColors: true,
-		FullTimestamp: true,
-	})
+	if rprtr.JSONFormat() {
+		result.SetFormatter(&logrus.JSONFormatter{})
+	} else {
+		result.SetFormatter(&logrus.TextFormatter{
+			DisableColors: true,
+			FullTimestamp: true,
+		})
+	}
+
+	// Credentials and tokens are removed from all the messages:
+	result.AddHook(&redactHook{})
 
 	// Enable the debug level if needed:
 	if debug.Enabled() {
@@ -62,7 +68,7 @@ func CreateLoggerOrExit(reporter *rprtr.Object) *logrus.Logger {
 	logger, err := NewLogger().Build()
 	if err != nil {
 		reporter.Errorf("Failed to create logger: %v", err)
-		os.Exit(1)
+		exit.Exit(1)
 	}
 	return logger
 }
@@ -0,0 +1,52 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the redaction of credentials and tokens from the messages sent to the log, so
+// that debug logs can be shared safely. All the loggers created by the project redact their
+// messages before they are written, whatever the SDK that produced them.
+
+package logging
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/moactl/pkg/redact"
+)
+
+// redactHook is a logrus hook that redacts the messages and the string fields of the entries
+// before they are formatted and written.
+type redactHook struct{}
+
+// Make sure that we implement the logrus hook interface:
+var _ logrus.Hook = &redactHook{}
+
+func (h *redactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *redactHook) Fire(entry *logrus.Entry) error {
+	entry.Message = redact.String(entry.Message)
+	for key, value := range entry.Data {
+		if redact.IsSensitive(key) {
+			entry.Data[key] = redact.Replacement
+			continue
+		}
+		if text, ok := value.(string); ok {
+			entry.Data[key] = redact.String(text)
+		}
+	}
+	return nil
+}
@@ -28,10 +28,11 @@ import (
 	"net/http"
 	"net/url"
 	"sort"
-	"strings"
 
 	"github.com/sirupsen/logrus"
 	"gitlab.com/c0b/go-ordered-json"
+
+	"github.com/openshift/moactl/pkg/redact"
 )
 
 // RoundTripperBuilder contains the information an logic needed to build a new round tripper that
@@ -96,7 +97,7 @@ func (b *RoundTripperBuilder) Build() (result *RoundTripper, err error) {
 		return
 	}
 
-	// Copy the set of redactedReplacement fields:
+	// Copy the set of redacted fields:
 	redact := make(map[string]bool)
 	for key, value := range b.redact {
 		redact[key] = value
@@ -174,7 +175,7 @@ func (d *RoundTripper) dumpRequest(request *http.Request, body []byte) {
 	for _, name := range names {
 		values := header[name]
 		for _, value := range values {
-			if strings.ToLower(name) == "authorization" {
+			if redact.IsSensitive(name) {
 				d.logger.Debugf("Request header '%s' is omitted", name)
 			} else {
 				d.logger.Debugf("Request header '%s' is '%s'", name, value)
@@ -200,7 +201,11 @@ func (d *RoundTripper) dumpResponse(response *http.Response, body []byte) {
 	for _, name := range names {
 		values := header[name]
 		for _, value := range values {
-			d.logger.Debugf("Response header '%s' is '%s'", name, value)
+			if redact.IsSensitive(name) {
+				d.logger.Debugf("Response header '%s' is omitted", name)
+			} else {
+				d.logger.Debugf("Response header '%s' is '%s'", name, value)
+			}
 		}
 	}
 	if body != nil {
@@ -247,9 +252,9 @@ func (d *RoundTripper) dumpForm(what string, data []byte) {
 
 	// Redact values corresponding to security sensitive fields:
 	for name, values := range form {
-		if d.redact[name] {
+		if d.isRedacted(name) {
 			for i := range values {
-				values[i] = redactedReplacement
+				values[i] = redact.Replacement
 			}
 		}
 	}
@@ -269,8 +274,8 @@ func (d *RoundTripper) dumpForm(what string, data []byte) {
 		values := form[name]
 		for _, value := range values {
 			var redacted string
-			if d.redact[name] {
-				redacted = redactedReplacement
+			if d.isRedacted(name) {
+				redacted = redact.Replacement
 				d.logger.Debugf("%s field '%s' is redacted", what, name)
 			} else {
 				redacted = url.QueryEscape(value)
@@ -285,7 +290,7 @@ func (d *RoundTripper) dumpForm(what string, data []byte) {
 		}
 	}
 
-	// Send the redactedReplacement data to the log:
+	// Send the redacted data to the log:
 	d.dumpBytes(what, buffer.Bytes())
 }
 
@@ -309,8 +314,10 @@ func (d *RoundTripper) dumpJSON(what string, data []byte) {
 	}
 }
 
-// dumpBytes dump the given data as an array of bytes.
+// dumpBytes dump the given data as an array of bytes. The data is written directly to the output
+// of the logger, bypassing its hooks, so it is redacted here.
 func (d *RoundTripper) dumpBytes(what string, data []byte) {
+	data = []byte(redact.String(string(data)))
 	size := len(data)
 	if size > 0 {
 		d.logger.Debugf("%s body follows", what)
@@ -322,7 +329,8 @@ func (d *RoundTripper) dumpBytes(what string, data []byte) {
 	}
 }
 
-// redactSensitive replaces sensitive fields within a response with redactionStr.
+// redactSensitive replaces sensitive fields within a response with redactionStr, including the
+// ones of nested objects, like the AWS credentials of a cluster.
 func (d *RoundTripper) redactSensitive(body *ordered.OrderedMap) {
 	iterator := body.EntriesIter()
 	for {
@@ -330,11 +338,27 @@ func (d *RoundTripper) redactSensitive(body *ordered.OrderedMap) {
 		if !ok {
 			break
 		}
-		if d.redact[pair.Key] {
-			body.Set(pair.Key, redactedReplacement)
+		if d.isRedacted(pair.Key) {
+			body.Set(pair.Key, redact.Replacement)
+			continue
+		}
+		d.redactValue(pair.Value)
+	}
+}
+
+func (d *RoundTripper) redactValue(value interface{}) {
+	switch typed := value.(type) {
+	case *ordered.OrderedMap:
+		d.redactSensitive(typed)
+	case []interface{}:
+		for _, item := range typed {
+			d.redactValue(item)
 		}
 	}
 }
 
-// String that replaces redactedReplacement fields in messages sent to the log:
-const redactedReplacement = "***"
+// isRedacted checks if the value of the given field must be redacted, either because it was
+// explicitly requested or because it contains credentials.
+func (d *RoundTripper) isRedacted(name string) bool {
+	return d.redact[name] || redact.IsSensitive(name)
+}
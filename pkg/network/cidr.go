@@ -0,0 +1,216 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions that propose the blocks of IP addresses of a cluster, so that they
+// don't overlap with each other.
+
+package network
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+	"net"
+)
+
+// DefaultHostPrefix is the prefix length of the block of pod addresses assigned to each node.
+const DefaultHostPrefix = 23
+
+// Smallest subnet that AWS allows, and number of addresses of each subnet that AWS reserves:
+const (
+	maxSubnetPrefix       = 28
+	reservedSubnetAddress = 5
+)
+
+// Blocks proposed for services and pods, in order of preference. The first one of each list is
+// the default of OpenShift.
+var (
+	serviceCandidates = []string{"172.30.0.0/16", "172.31.0.0/16", "10.96.0.0/16", "192.168.0.0/16"}
+	podCandidates     = []string{"10.128.0.0/14", "10.132.0.0/14", "172.16.0.0/14", "100.64.0.0/14"}
+)
+
+// Layout contains the blocks of IP addresses of a cluster and the subnets that the machine block is
+// split into.
+type Layout struct {
+	MachineCIDR *net.IPNet
+	ServiceCIDR *net.IPNet
+	PodCIDR     *net.IPNet
+	HostPrefix  int
+	Subnets     []*net.IPNet
+}
+
+// UsableAddresses returns the number of addresses of the given subnet that can be assigned to
+// instances, taking into account the ones reserved by AWS.
+func UsableAddresses(subnet *net.IPNet) int {
+	ones, size := subnet.Mask.Size()
+	usable := int(math.Pow(2, float64(size-ones))) - reservedSubnetAddress
+	if usable < 0 {
+		return 0
+	}
+	return usable
+}
+
+// MaxNodes returns the number of nodes that can get a block of pod addresses with the given host
+// prefix.
+func MaxNodes(podCIDR *net.IPNet, hostPrefix int) int {
+	ones, _ := podCIDR.Mask.Size()
+	if hostPrefix < ones {
+		return 0
+	}
+	return int(math.Pow(2, float64(hostPrefix-ones)))
+}
+
+// Overlaps checks if the two given blocks have any address in common.
+func Overlaps(a *net.IPNet, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+// Within checks if all the addresses of the first block are inside the second block.
+func Within(a *net.IPNet, b *net.IPNet) bool {
+	aOnes, aBits := a.Mask.Size()
+	bOnes, bBits := b.Mask.Size()
+	return aBits == bBits && aOnes >= bOnes && b.Contains(a.IP)
+}
+
+// Split splits the given block into the given number of subnets of the same size. The size is the
+// largest that allows that number of subnets, so when the number isn't a power of two part of the
+// block is left unused.
+func Split(cidr *net.IPNet, count int) ([]*net.IPNet, error) {
+	if count < 1 {
+		return nil, fmt.Errorf("Number of subnets must be at least 1")
+	}
+	base := cidr.IP.To4()
+	if base == nil {
+		return nil, fmt.Errorf("Block '%s' isn't an IPv4 block", cidr)
+	}
+	ones, _ := cidr.Mask.Size()
+	prefix := ones + bits.Len(uint(count-1))
+	if prefix > maxSubnetPrefix {
+		return nil, fmt.Errorf(
+			"Block '%s' is too small for %d subnets: AWS subnets must be at least /%d",
+			cidr, count, maxSubnetPrefix,
+		)
+	}
+	start := binary.BigEndian.Uint32(base.Mask(cidr.Mask))
+	step := uint32(1) << uint(32-prefix)
+	subnets := make([]*net.IPNet, count)
+	for i := range subnets {
+		ip := make(net.IP, net.IPv4len)
+		binary.BigEndian.PutUint32(ip, start+uint32(i)*step)
+		subnets[i] = &net.IPNet{
+			IP:   ip,
+			Mask: net.CIDRMask(prefix, 32),
+		}
+	}
+	return subnets, nil
+}
+
+// ProposeLayout splits the given machine block into the given number of subnets and proposes
+// service and pod blocks that don't overlap with it nor with each other. The service and pod blocks
+// and the host prefix are only proposed when they aren't given.
+func ProposeLayout(machineCIDR *net.IPNet, subnets int, serviceCIDR *net.IPNet, podCIDR *net.IPNet,
+	hostPrefix int) (*Layout, error) {
+	var err error
+	layout := &Layout{
+		MachineCIDR: machineCIDR,
+		HostPrefix:  hostPrefix,
+	}
+	layout.Subnets, err = Split(machineCIDR, subnets)
+	if err != nil {
+		return nil, err
+	}
+
+	layout.ServiceCIDR = serviceCIDR
+	if layout.ServiceCIDR == nil {
+		layout.ServiceCIDR, err = FreeServiceCIDR(machineCIDR, podCIDR)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to find a service block: %v", err)
+		}
+	}
+	layout.PodCIDR = podCIDR
+	if layout.PodCIDR == nil {
+		layout.PodCIDR, err = FreePodCIDR(machineCIDR, layout.ServiceCIDR)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to find a pod block: %v", err)
+		}
+	}
+	if layout.HostPrefix == 0 {
+		layout.HostPrefix = DefaultHostPrefix
+	}
+
+	return layout, layout.Validate()
+}
+
+// Validate checks that the blocks of the layout don't overlap and that the host prefix fits in the
+// pod block.
+func (l *Layout) Validate() error {
+	blocks := []struct {
+		name string
+		cidr *net.IPNet
+	}{
+		{"machine", l.MachineCIDR},
+		{"service", l.ServiceCIDR},
+		{"pod", l.PodCIDR},
+	}
+	for i := range blocks {
+		for j := i + 1; j < len(blocks); j++ {
+			if Overlaps(blocks[i].cidr, blocks[j].cidr) {
+				return fmt.Errorf("The %s block '%s' overlaps with the %s block '%s'",
+					blocks[i].name, blocks[i].cidr, blocks[j].name, blocks[j].cidr)
+			}
+		}
+	}
+	ones, _ := l.PodCIDR.Mask.Size()
+	if l.HostPrefix < ones || l.HostPrefix > 32 {
+		return fmt.Errorf("Host prefix %d isn't valid for pod block '%s': it must be between %d and 32",
+			l.HostPrefix, l.PodCIDR, ones)
+	}
+	return nil
+}
+
+// FreeServiceCIDR returns the preferred service block that doesn't overlap with any of the given
+// blocks.
+func FreeServiceCIDR(used ...*net.IPNet) (*net.IPNet, error) {
+	return firstFree(serviceCandidates, used...)
+}
+
+// FreePodCIDR returns the preferred pod block that doesn't overlap with any of the given blocks.
+func FreePodCIDR(used ...*net.IPNet) (*net.IPNet, error) {
+	return firstFree(podCandidates, used...)
+}
+
+// firstFree returns the first of the given candidate blocks that doesn't overlap with any of the
+// used blocks. Used blocks that are nil are ignored.
+func firstFree(candidates []string, used ...*net.IPNet) (*net.IPNet, error) {
+	for _, candidate := range candidates {
+		_, cidr, err := net.ParseCIDR(candidate)
+		if err != nil {
+			return nil, err
+		}
+		free := true
+		for _, block := range used {
+			if block != nil && Overlaps(cidr, block) {
+				free = false
+				break
+			}
+		}
+		if free {
+			return cidr, nil
+		}
+	}
+	return nil, fmt.Errorf("All the candidate blocks %v overlap with the blocks already in use", candidates)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the list of the endpoints that clusters need to reach during and after the
+// installation, and the functions that check that they can be reached.
+
+package network
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	awsendpoints "github.com/aws/aws-sdk-go/aws/endpoints"
+)
+
+// Categories of the required endpoints:
+const (
+	EndpointCategoryOCM       = "OCM"
+	EndpointCategoryRegistry  = "Registry"
+	EndpointCategoryTelemetry = "Telemetry"
+	EndpointCategoryAWS       = "AWS"
+)
+
+// Endpoint is a destination that clusters need to be able to reach.
+type Endpoint struct {
+	Category string `json:"category"`
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+}
+
+// Address returns the 'host:port' address of the endpoint.
+func (e *Endpoint) Address() string {
+	return net.JoinHostPort(e.Host, fmt.Sprint(e.Port))
+}
+
+// EndpointResult is the result of trying to connect to an endpoint. The error is nil if the
+// connection could be established.
+type EndpointResult struct {
+	Endpoint *Endpoint
+	Err      error
+}
+
+// RequiredEndpoints returns the destinations that need to be allowed by the firewall for a cluster
+// installed in the given region. The global region and the DNS suffix are those of the partition of
+// the region: the tagging API of the global region is used for the Route53 resources, as Route53 is
+// a global service, and the suffix is the domain of the AWS endpoints.
+func RequiredEndpoints(region string, globalRegion string, dnsSuffix string) []*Endpoint {
+	endpoints := []*Endpoint{
+		{EndpointCategoryOCM, "api.openshift.com", 443},
+		{EndpointCategoryOCM, "mirror.openshift.com", 443},
+		{EndpointCategoryOCM, "sso.redhat.com", 443},
+		{EndpointCategoryRegistry, "registry.redhat.io", 443},
+		{EndpointCategoryRegistry, "registry.access.redhat.com", 443},
+		{EndpointCategoryRegistry, "quay.io", 443},
+		{EndpointCategoryRegistry, "cdn.quay.io", 443},
+		{EndpointCategoryRegistry, "cdn01.quay.io", 443},
+		{EndpointCategoryRegistry, "cdn02.quay.io", 443},
+		{EndpointCategoryRegistry, "cdn03.quay.io", 443},
+		{EndpointCategoryRegistry, "quay-registry.s3.amazonaws.com", 443},
+		{EndpointCategoryTelemetry, "cert-api.access.redhat.com", 443},
+		{EndpointCategoryTelemetry, "api.access.redhat.com", 443},
+		{EndpointCategoryTelemetry, "infogw.api.openshift.com", 443},
+		{EndpointCategoryTelemetry, "cloud.redhat.com", 443},
+	}
+	for _, service := range []string{"iam", "route53", "sts", "ec2", "elasticloadbalancing", "events",
+		"tagging"} {
+		endpoints = append(endpoints, &Endpoint{EndpointCategoryAWS, awsHost(service, region, dnsSuffix), 443})
+	}
+	if region != globalRegion {
+		endpoints = append(endpoints,
+			&Endpoint{EndpointCategoryAWS, awsHost("tagging", globalRegion, dnsSuffix), 443})
+	}
+	return endpoints
+}
+
+// awsHost returns the host name of the endpoint of the given AWS service in the given region,
+// which depends on the partition of the region. For global services, like IAM, it is the endpoint
+// of the partition. Services unknown to the SDK are assumed to use the usual naming scheme with the
+// given DNS suffix.
+func awsHost(service string, region string, dnsSuffix string) string {
+	endpoint, err := awsendpoints.DefaultResolver().EndpointFor(service, region)
+	if err == nil {
+		parsed, err := url.Parse(endpoint.URL)
+		if err == nil && parsed.Hostname() != "" {
+			return parsed.Hostname()
+		}
+	}
+	return fmt.Sprintf("%s.%s.%s", service, region, dnsSuffix)
+}
+
+// CheckEndpoints tries to connect to all the given endpoints, waiting at most the given timeout
+// for each connection. The results are in the same order than the endpoints.
+func CheckEndpoints(endpoints []*Endpoint, timeout time.Duration) []*EndpointResult {
+	// Try to connect to all the endpoints at the same time, as most of the time is spent waiting
+	// for the connections that are blocked to time out:
+	results := make([]*EndpointResult, len(endpoints))
+	var wg sync.WaitGroup
+	for i, endpoint := range endpoints {
+		wg.Add(1)
+		go func(i int, endpoint *Endpoint) {
+			defer wg.Done()
+			results[i] = &EndpointResult{Endpoint: endpoint}
+			conn, err := net.DialTimeout("tcp", endpoint.Address(), timeout)
+			if err != nil {
+				results[i].Err = err
+				return
+			}
+			conn.Close()
+		}(i, endpoint)
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,125 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that check the IPv6 blocks of dual-stack clusters, which get
+// IPv6 addresses in addition to the IPv4 ones.
+
+package network
+
+import (
+	"fmt"
+	"net"
+)
+
+// Blocks of IPv6 addresses used by dual-stack clusters when they aren't given. They are the
+// defaults of OpenShift. The host prefix is fixed, as OVN-Kubernetes assigns a /64 to each node.
+const (
+	DefaultServiceCIDRv6 = "fd02::/112"
+	DefaultPodCIDRv6     = "fd01::/48"
+	HostPrefixV6         = 64
+)
+
+// Smallest IPv6 block that AWS assigns to a subnet:
+const maxSubnetPrefixV6 = 64
+
+// IPv6Layout contains the blocks of IPv6 addresses of a dual-stack cluster. The machine block is
+// empty when AWS assigns it to the VPC created by the installer.
+type IPv6Layout struct {
+	MachineCIDR *net.IPNet
+	ServiceCIDR *net.IPNet
+	PodCIDR     *net.IPNet
+}
+
+// IsIPv6 checks if the given block is a block of IPv6 addresses.
+func IsIPv6(cidr *net.IPNet) bool {
+	return cidr != nil && cidr.IP.To4() == nil && cidr.IP.To16() != nil
+}
+
+// ValidateIPv6CIDR checks that the given block, described by the given name in the error message,
+// is a block of IPv6 addresses.
+func ValidateIPv6CIDR(name string, cidr *net.IPNet) error {
+	if !IsIPv6(cidr) {
+		return fmt.Errorf("The %s block '%s' isn't an IPv6 block", name, cidr)
+	}
+	return nil
+}
+
+// ProposeIPv6Layout returns the IPv6 blocks of a dual-stack cluster, using the defaults for the
+// service and pod blocks that aren't given.
+func ProposeIPv6Layout(machineCIDR *net.IPNet, serviceCIDR *net.IPNet, podCIDR *net.IPNet) (*IPv6Layout,
+	error) {
+	var err error
+	layout := &IPv6Layout{
+		MachineCIDR: machineCIDR,
+		ServiceCIDR: serviceCIDR,
+		PodCIDR:     podCIDR,
+	}
+	if layout.ServiceCIDR == nil {
+		_, layout.ServiceCIDR, err = net.ParseCIDR(DefaultServiceCIDRv6)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if layout.PodCIDR == nil {
+		_, layout.PodCIDR, err = net.ParseCIDR(DefaultPodCIDRv6)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return layout, layout.Validate()
+}
+
+// Validate checks that the blocks of the layout are IPv6 blocks that don't overlap, that the
+// machine block can be split into AWS subnets and that the pod block is larger than the block
+// assigned to each node.
+func (l *IPv6Layout) Validate() error {
+	type block struct {
+		name string
+		cidr *net.IPNet
+	}
+	blocks := []block{}
+	if l.MachineCIDR != nil {
+		blocks = append(blocks, block{"IPv6 machine", l.MachineCIDR})
+	}
+	blocks = append(blocks, block{"IPv6 service", l.ServiceCIDR}, block{"IPv6 pod", l.PodCIDR})
+	for _, block := range blocks {
+		err := ValidateIPv6CIDR(block.name, block.cidr)
+		if err != nil {
+			return err
+		}
+	}
+	for i := range blocks {
+		for j := i + 1; j < len(blocks); j++ {
+			if Overlaps(blocks[i].cidr, blocks[j].cidr) {
+				return fmt.Errorf("The %s block '%s' overlaps with the %s block '%s'",
+					blocks[i].name, blocks[i].cidr, blocks[j].name, blocks[j].cidr)
+			}
+		}
+	}
+	if l.MachineCIDR != nil {
+		ones, _ := l.MachineCIDR.Mask.Size()
+		if ones >= maxSubnetPrefixV6 {
+			return fmt.Errorf("The IPv6 machine block '%s' is too small: it must be larger than /%d so "+
+				"that it can be split into AWS subnets", l.MachineCIDR, maxSubnetPrefixV6)
+		}
+	}
+	ones, _ := l.PodCIDR.Mask.Size()
+	if ones >= HostPrefixV6 {
+		return fmt.Errorf("The IPv6 pod block '%s' is too small: it must be larger than the /%d "+
+			"assigned to each node", l.PodCIDR, HostPrefixV6)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to manage temporary grants of groups to users. The time
+// when each grant expires is stored in a property of the cluster, so that expired grants can be
+// revoked from any machine.
+
+package ocm
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/ocm/properties"
+)
+
+// AccessExpiration is the time when the membership of a user in a group expires.
+type AccessExpiration struct {
+	Group    string
+	Username string
+	Time     time.Time
+}
+
+// Expired checks if the grant has expired at the given time.
+func (e *AccessExpiration) Expired(now time.Time) bool {
+	return !e.Time.After(now)
+}
+
+func accessExpirationProperty(group string, username string) string {
+	return properties.AccessExpirationPrefix + group + "_" + username
+}
+
+// GetAccessExpirations returns the temporary grants of the cluster, sorted by expiration time.
+// Properties that can't be parsed are ignored.
+func GetAccessExpirations(cluster *cmv1.Cluster) []*AccessExpiration {
+	expirations := []*AccessExpiration{}
+	for name, value := range cluster.Properties() {
+		if !strings.HasPrefix(name, properties.AccessExpirationPrefix) {
+			continue
+		}
+		// Group names don't contain underscores, so the first one separates the user name:
+		parts := strings.SplitN(strings.TrimPrefix(name, properties.AccessExpirationPrefix), "_", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		expiration, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			continue
+		}
+		expirations = append(expirations, &AccessExpiration{
+			Group:    parts[0],
+			Username: parts[1],
+			Time:     expiration,
+		})
+	}
+	sort.Slice(expirations, func(i, j int) bool {
+		return expirations[i].Time.Before(expirations[j].Time)
+	})
+	return expirations
+}
+
+// GetAccessExpiration returns the time when the membership of the user in the group expires, or
+// nil if it is permanent.
+func GetAccessExpiration(cluster *cmv1.Cluster, group string, username string) *AccessExpiration {
+	for _, expiration := range GetAccessExpirations(cluster) {
+		if expiration.Group == group && expiration.Username == username {
+			return expiration
+		}
+	}
+	return nil
+}
+
+// SetAccessExpiration records the time when the membership of the user in the group expires.
+func SetAccessExpiration(client *cmv1.ClustersClient, cluster *cmv1.Cluster, group string, username string,
+	expiration time.Time) error {
+	props := withProperty(cluster.Properties(), accessExpirationProperty(group, username),
+		expiration.UTC().Format(time.RFC3339))
+	return updateProperties(client, cluster, props)
+}
+
+// RemoveAccessExpirations removes the given expiration times from the cluster. The properties
+// are replaced as a whole, so all the expirations of a cluster have to be removed at once.
+func RemoveAccessExpirations(client *cmv1.ClustersClient, cluster *cmv1.Cluster,
+	expirations []*AccessExpiration) error {
+	props := cluster.Properties()
+	changed := false
+	for _, expiration := range expirations {
+		name := accessExpirationProperty(expiration.Group, expiration.Username)
+		if _, ok := props[name]; ok {
+			props = withProperty(props, name, "")
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return updateProperties(client, cluster, props)
+}
+
+// RemoveAccessExpiration removes the expiration time of the membership of the user in the group,
+// if there is one.
+func RemoveAccessExpiration(client *cmv1.ClustersClient, cluster *cmv1.Cluster, group string,
+	username string) error {
+	return RemoveAccessExpirations(client, cluster, []*AccessExpiration{{
+		Group:    group,
+		Username: username,
+	}})
+}
+
+// withProperty returns a copy of the given properties with the value of one of them changed, or
+// removed if the value is empty.
+func withProperty(props map[string]string, name string, value string) map[string]string {
+	result := make(map[string]string)
+	for key, current := range props {
+		result[key] = current
+	}
+	if value == "" {
+		delete(result, name)
+	} else {
+		result[name] = value
+	}
+	return result
+}
+
+// updateProperties replaces the properties of the cluster.
+func updateProperties(client *cmv1.ClustersClient, cluster *cmv1.Cluster, props map[string]string) error {
+	clusterSpec, err := cmv1.NewCluster().Properties(props).Build()
+	if err != nil {
+		return err
+	}
+	response, err := client.Cluster(cluster.ID()).Update().Body(clusterSpec).Send()
+	if err != nil {
+		return handleErr(response.Error(), err)
+	}
+	return nil
+}
@@ -0,0 +1,253 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/ocm/raw"
+)
+
+// AddOnParameter describes a parameter that can be given when an add-on is installed.
+type AddOnParameter struct {
+	ID               string             `json:"id"`
+	Name             string             `json:"name"`
+	Description      string             `json:"description"`
+	ValueType        string             `json:"value_type"`
+	Validation       string             `json:"validation"`
+	ValidationErrMsg string             `json:"validation_err_msg"`
+	Required         bool               `json:"required"`
+	Editable         bool               `json:"editable"`
+	Enabled          bool               `json:"enabled"`
+	DefaultValue     string             `json:"default_value"`
+	Options          []AddOnParamOption `json:"options"`
+}
+
+// AddOnParamOption is one of the values allowed for a parameter of an add-on.
+type AddOnParamOption struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// AddOnRequirement is a condition that the cluster, or other resources, must meet before an add-on
+// can be installed.
+type AddOnRequirement struct {
+	ID       string                 `json:"id"`
+	Resource string                 `json:"resource"`
+	Data     map[string]interface{} `json:"data"`
+	Enabled  bool                   `json:"enabled"`
+}
+
+// AddOnSchema contains the parameters and requirements of an add-on.
+type AddOnSchema struct {
+	Parameters   []AddOnParameter   `json:"parameters"`
+	Requirements []AddOnRequirement `json:"requirements"`
+}
+
+// GetAddOnSchema returns the parameters and requirements of the add-on with the given identifier,
+// including the default values and the options of the parameters.
+func GetAddOnSchema(connection *sdk.Connection, id string) (*AddOnSchema, error) {
+	response, err := raw.Send(
+		connection.Get().
+			Path("/api/clusters_mgmt/v1/addons/"+url.PathEscape(id)),
+		http.StatusOK,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var body struct {
+		Parameters struct {
+			Items []AddOnParameter `json:"items"`
+		} `json:"parameters"`
+		Requirements []AddOnRequirement `json:"requirements"`
+	}
+	err = json.Unmarshal(response.Bytes(), &body)
+	if err != nil {
+		return nil, err
+	}
+	return &AddOnSchema{
+		Parameters:   body.Parameters.Items,
+		Requirements: body.Requirements,
+	}, nil
+}
+
+// ParseAddOnParameters parses the values given with the '--param' flag, in 'key=value' format.
+func ParseAddOnParameters(values []string) (map[string]string, error) {
+	params := map[string]string{}
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("Parameter '%s' isn't valid: it must be in 'key=value' format", value)
+		}
+		if _, ok := params[parts[0]]; ok {
+			return nil, fmt.Errorf("Parameter '%s' is given more than once", parts[0])
+		}
+		params[parts[0]] = parts[1]
+	}
+	return params, nil
+}
+
+// FindParameter returns the parameter of the schema with the given identifier, or nil if the
+// add-on doesn't have it.
+func (s *AddOnSchema) FindParameter(id string) *AddOnParameter {
+	for i := range s.Parameters {
+		if s.Parameters[i].ID == id {
+			return &s.Parameters[i]
+		}
+	}
+	return nil
+}
+
+// MissingParameters returns the enabled parameters that are required and that aren't in the given
+// values.
+func (s *AddOnSchema) MissingParameters(params map[string]string) []AddOnParameter {
+	var missing []AddOnParameter
+	for _, parameter := range s.Parameters {
+		if !parameter.Enabled || !parameter.Required {
+			continue
+		}
+		if _, ok := params[parameter.ID]; !ok {
+			missing = append(missing, parameter)
+		}
+	}
+	return missing
+}
+
+// ValidateParameters checks that the add-on accepts all the given parameters and their values.
+func (s *AddOnSchema) ValidateParameters(params map[string]string) error {
+	ids := make([]string, 0, len(params))
+	for id := range params {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		parameter := s.FindParameter(id)
+		if parameter == nil || !parameter.Enabled {
+			return fmt.Errorf("Add-on doesn't have a parameter named '%s'", id)
+		}
+		err := parameter.Validate(params[id])
+		if err != nil {
+			return fmt.Errorf("Value of parameter '%s' isn't valid: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks that the given value is accepted by the parameter: that it is one of its options,
+// if it has them, and that it matches its validation expression and type.
+func (p *AddOnParameter) Validate(value string) error {
+	if len(p.Options) > 0 {
+		values := make([]string, len(p.Options))
+		for i, option := range p.Options {
+			if option.Value == value {
+				return nil
+			}
+			values[i] = option.Value
+		}
+		return fmt.Errorf("'%s' isn't one of %s", value, strings.Join(values, ", "))
+	}
+	switch p.ValueType {
+	case "boolean":
+		_, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("'%s' isn't 'true' or 'false'", value)
+		}
+	case "number":
+		_, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("'%s' isn't a number", value)
+		}
+	}
+	if p.Validation != "" {
+		// The expressions are written for the web console, so the ones that Go doesn't support
+		// are left to the server:
+		re, err := regexp.Compile(p.Validation)
+		if err == nil && !re.MatchString(value) {
+			if p.ValidationErrMsg != "" {
+				return fmt.Errorf("%s", p.ValidationErrMsg)
+			}
+			return fmt.Errorf("'%s' doesn't match '%s'", value, p.Validation)
+		}
+	}
+	return nil
+}
+
+// InstallAddOn installs the add-on with the given identifier on the cluster, with the given
+// parameters.
+func InstallAddOn(client *cmv1.ClustersClient, clusterID string, addOnID string,
+	params map[string]string) error {
+	builder := cmv1.NewAddOnInstallation().
+		Addon(cmv1.NewAddOn().ID(addOnID))
+	if len(params) > 0 {
+		ids := make([]string, 0, len(params))
+		for id := range params {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		items := make([]*cmv1.AddOnInstallationParameterBuilder, len(ids))
+		for i, id := range ids {
+			items[i] = cmv1.NewAddOnInstallationParameter().ID(id).Value(params[id])
+		}
+		builder = builder.Parameters(cmv1.NewAddOnInstallationParameterList().Items(items...))
+	}
+	addOnInstallation, err := builder.Build()
+	if err != nil {
+		return err
+	}
+
+	response, err := client.Cluster(clusterID).Addons().Add().Body(addOnInstallation).Send()
+	if err != nil {
+		return handleErr(response.Error(), err)
+	}
+	return nil
+}
+
+// GetAddOnInstallation returns the installation of the add-on with the given identifier on the
+// cluster, or nil if it isn't installed.
+func GetAddOnInstallation(client *cmv1.ClustersClient, clusterID string,
+	addOnID string) (*cmv1.AddOnInstallation, error) {
+	response, err := client.Cluster(clusterID).Addons().Addoninstallation(addOnID).Get().Send()
+	if response != nil && response.Status() == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Body(), nil
+}
+
+// UninstallAddOn removes the installation of the add-on with the given identifier from the
+// cluster.
+func UninstallAddOn(connection *sdk.Connection, clusterID string, addOnID string) error {
+	_, err := raw.Send(
+		connection.Delete().
+			Path(fmt.Sprintf("/api/clusters_mgmt/v1/clusters/%s/addons/%s",
+				url.PathEscape(clusterID), url.PathEscape(addOnID))),
+		http.StatusOK, http.StatusAccepted, http.StatusNoContent,
+	)
+	return err
+}
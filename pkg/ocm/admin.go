@@ -0,0 +1,104 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that manage the cluster-admin user created by 'rosa create
+// admin', which is a user of an htpasswd identity provider that belongs to the cluster-admins group.
+
+package ocm
+
+import (
+	"crypto/rand"
+	"math/big"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// Names of the identity provider and of the user of the admin of a cluster:
+const (
+	AdminIdentityProviderName = "Cluster-Admin"
+	AdminUsername             = "cluster-admin"
+)
+
+// Length of the generated passwords of the admin:
+const adminPasswordLength = 23
+
+// GetAdminIdentityProvider returns the htpasswd identity provider of the admin of the cluster, or
+// nil if the cluster doesn't have an admin.
+func GetAdminIdentityProvider(client *cmv1.ClustersClient, clusterID string) (*cmv1.IdentityProvider, error) {
+	idps, err := GetIdentityProviders(client, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	for _, idp := range idps {
+		if idp.Name() == AdminIdentityProviderName && IdentityProviderType(idp) == "htpasswd" {
+			return idp, nil
+		}
+	}
+	return nil, nil
+}
+
+// BuildAdminIdentityProvider returns the htpasswd identity provider of the admin with the given
+// password.
+func BuildAdminIdentityProvider(password string) (*cmv1.IdentityProvider, error) {
+	builder, err := BuildHTPasswdIdentityProvider(&HTPasswdIdentityProviderSpec{
+		Name:          AdminIdentityProviderName,
+		MappingMethod: "claim",
+		Username:      AdminUsername,
+		Password:      password,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return builder.Build()
+}
+
+// GenerateAdminPassword returns a random password for the admin. It doesn't contain characters
+// that are easily confused, like '0' and 'O', and it is split in groups by dashes so that it can be
+// read aloud.
+func GenerateAdminPassword() (string, error) {
+	const (
+		lowerLetters = "abcdefghijkmnopqrstuvwxyz"
+		upperLetters = "ABCDEFGHIJKLMNPQRSTUVWXYZ"
+		digits       = "23456789"
+		all          = lowerLetters + upperLetters + digits
+	)
+	var password string
+	for i := 0; i < adminPasswordLength; i++ {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(all))))
+		if err != nil {
+			return "", err
+		}
+		newchar := string(all[n.Int64()])
+		if password == "" {
+			password = newchar
+		}
+		if i < adminPasswordLength-1 {
+			n, err = rand.Int(rand.Reader, big.NewInt(int64(len(password)+1)))
+			if err != nil {
+				return "", err
+			}
+			j := n.Int64()
+			password = password[0:j] + newchar + password[j:]
+		}
+	}
+
+	pw := []rune(password)
+	for _, replace := range []int{5, 11, 17} {
+		pw[replace] = '-'
+	}
+
+	return string(pw), nil
+}
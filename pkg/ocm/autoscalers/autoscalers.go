@@ -0,0 +1,286 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions that manage the cluster autoscaler configuration of clusters, stored
+// in their 'autoscaler' resource.
+
+package autoscalers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+
+	"github.com/openshift/moactl/pkg/ocm/raw"
+)
+
+// Autoscaler contains the options of the cluster autoscaler, which adds and removes nodes of the
+// machine pools that have autoscaling enabled.
+type Autoscaler struct {
+	BalanceSimilarNodeGroups bool            `json:"balance_similar_node_groups"`
+	MaxNodeProvisionTime     string          `json:"max_node_provision_time,omitempty"`
+	ScaleDown                *ScaleDown      `json:"scale_down,omitempty"`
+	ResourceLimits           *ResourceLimits `json:"resource_limits,omitempty"`
+}
+
+// ScaleDown contains the options that control when nodes are removed.
+type ScaleDown struct {
+	// UtilizationThreshold is the fraction of the resources of a node requested by its pods below
+	// which the node can be removed. OCM stores it as a string.
+	UtilizationThreshold string `json:"utilization_threshold,omitempty"`
+}
+
+// ResourceLimits are the limits of the resources of the whole cluster that the autoscaler
+// respects.
+type ResourceLimits struct {
+	MaxNodesTotal int            `json:"max_nodes_total,omitempty"`
+	Cores         *ResourceRange `json:"cores,omitempty"`
+	Memory        *ResourceRange `json:"memory,omitempty"`
+}
+
+// ResourceRange is the minimum and maximum amount of a resource of the cluster. Memory is in GiB.
+type ResourceRange struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// Builder contains the options of an autoscaler configuration and validates them. Don't create
+// instances of this type directly; use the NewBuilder function instead.
+type Builder struct {
+	autoscaler Autoscaler
+}
+
+// NewBuilder creates a builder that can then be used to configure and build an autoscaler
+// configuration.
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// Copy sets all the options of the builder from the given configuration, so that only the changed
+// ones need to be set.
+func (b *Builder) Copy(autoscaler *Autoscaler) *Builder {
+	if autoscaler == nil {
+		return b
+	}
+	b.autoscaler = *autoscaler
+	if autoscaler.ScaleDown != nil {
+		scaleDown := *autoscaler.ScaleDown
+		b.autoscaler.ScaleDown = &scaleDown
+	}
+	if autoscaler.ResourceLimits != nil {
+		limits := *autoscaler.ResourceLimits
+		if limits.Cores != nil {
+			cores := *limits.Cores
+			limits.Cores = &cores
+		}
+		if limits.Memory != nil {
+			memory := *limits.Memory
+			limits.Memory = &memory
+		}
+		b.autoscaler.ResourceLimits = &limits
+	}
+	return b
+}
+
+// BalanceSimilarNodeGroups sets if the autoscaler keeps the same number of nodes in the machine
+// pools that have the same instance type and labels.
+func (b *Builder) BalanceSimilarNodeGroups(value bool) *Builder {
+	b.autoscaler.BalanceSimilarNodeGroups = value
+	return b
+}
+
+// MaxNodeProvisionTime sets how long the autoscaler waits for a node to be provisioned, as a
+// duration like '15m'.
+func (b *Builder) MaxNodeProvisionTime(value string) *Builder {
+	b.autoscaler.MaxNodeProvisionTime = value
+	return b
+}
+
+// ScaleDownUtilizationThreshold sets the fraction of the resources of a node requested by its pods
+// below which the node can be removed.
+func (b *Builder) ScaleDownUtilizationThreshold(value float64) *Builder {
+	if b.autoscaler.ScaleDown == nil {
+		b.autoscaler.ScaleDown = &ScaleDown{}
+	}
+	b.autoscaler.ScaleDown.UtilizationThreshold = strconv.FormatFloat(value, 'f', -1, 64)
+	return b
+}
+
+// MaxNodesTotal sets the maximum number of nodes of the cluster.
+func (b *Builder) MaxNodesTotal(value int) *Builder {
+	b.limits().MaxNodesTotal = value
+	return b
+}
+
+// Cores sets the minimum and maximum number of cores of the cluster.
+func (b *Builder) Cores(min int, max int) *Builder {
+	b.limits().Cores = &ResourceRange{Min: min, Max: max}
+	return b
+}
+
+// Memory sets the minimum and maximum memory of the cluster, in GiB.
+func (b *Builder) Memory(min int, max int) *Builder {
+	b.limits().Memory = &ResourceRange{Min: min, Max: max}
+	return b
+}
+
+func (b *Builder) limits() *ResourceLimits {
+	if b.autoscaler.ResourceLimits == nil {
+		b.autoscaler.ResourceLimits = &ResourceLimits{}
+	}
+	return b.autoscaler.ResourceLimits
+}
+
+// Build validates the options of the builder and returns the autoscaler configuration.
+func (b *Builder) Build() (*Autoscaler, error) {
+	autoscaler := b.autoscaler
+	if autoscaler.MaxNodeProvisionTime != "" {
+		err := ValidateMaxNodeProvisionTime(autoscaler.MaxNodeProvisionTime)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if autoscaler.ScaleDown != nil && autoscaler.ScaleDown.UtilizationThreshold != "" {
+		threshold, err := strconv.ParseFloat(autoscaler.ScaleDown.UtilizationThreshold, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Scale down utilization threshold '%s' isn't a number",
+				autoscaler.ScaleDown.UtilizationThreshold)
+		}
+		err = ValidateUtilizationThreshold(threshold)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if limits := autoscaler.ResourceLimits; limits != nil {
+		if limits.MaxNodesTotal < 0 {
+			return nil, fmt.Errorf("Maximum number of nodes %d isn't valid: it must be positive",
+				limits.MaxNodesTotal)
+		}
+		err := ValidateRange("cores", limits.Cores)
+		if err != nil {
+			return nil, err
+		}
+		err = ValidateRange("memory", limits.Memory)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &autoscaler, nil
+}
+
+// ValidateUtilizationThreshold checks that the scale down utilization threshold is a fraction
+// between 0 and 1.
+func ValidateUtilizationThreshold(threshold float64) error {
+	if threshold < 0 || threshold > 1 {
+		return fmt.Errorf("Scale down utilization threshold %g isn't valid: it must be between "+
+			"0 and 1", threshold)
+	}
+	return nil
+}
+
+// ValidateMaxNodeProvisionTime checks that the maximum node provision time is a positive duration,
+// like '15m'.
+func ValidateMaxNodeProvisionTime(value string) error {
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return fmt.Errorf("Maximum node provision time '%s' isn't valid: it must be a positive "+
+			"duration, like '15m'", value)
+	}
+	return nil
+}
+
+// ValidateRange checks that the minimum of the given range of a resource isn't negative and that
+// it isn't greater than the maximum.
+func ValidateRange(resource string, value *ResourceRange) error {
+	if value == nil {
+		return nil
+	}
+	if value.Min < 0 {
+		return fmt.Errorf("Minimum %s %d isn't valid: it must be positive", resource, value.Min)
+	}
+	if value.Max < value.Min {
+		return fmt.Errorf("Maximum %s %d isn't valid: it must be at least the minimum %d",
+			resource, value.Max, value.Min)
+	}
+	return nil
+}
+
+func resourcePath(clusterID string) string {
+	return fmt.Sprintf("/api/clusters_mgmt/v1/clusters/%s/autoscaler", clusterID)
+}
+
+// GetAutoscaler returns the autoscaler configuration of the given cluster, or nil if it doesn't
+// have one and the defaults are used.
+func GetAutoscaler(connection *sdk.Connection, clusterID string) (*Autoscaler, error) {
+	response, err := raw.Send(
+		connection.Get().
+			Path(resourcePath(clusterID)),
+		http.StatusOK, http.StatusNotFound,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if response.Status() == http.StatusNotFound {
+		return nil, nil
+	}
+	autoscaler := &Autoscaler{}
+	err = json.Unmarshal(response.Bytes(), autoscaler)
+	if err != nil {
+		return nil, err
+	}
+	return autoscaler, nil
+}
+
+// CreateAutoscaler adds the given autoscaler configuration to the cluster.
+func CreateAutoscaler(connection *sdk.Connection, clusterID string, autoscaler *Autoscaler) error {
+	body, err := json.Marshal(autoscaler)
+	if err != nil {
+		return err
+	}
+	_, err = raw.Send(
+		connection.Post().
+			Path(resourcePath(clusterID)).
+			Bytes(body),
+		http.StatusCreated, http.StatusOK,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdateAutoscaler replaces the options of the autoscaler configuration of the cluster with the
+// given ones.
+func UpdateAutoscaler(connection *sdk.Connection, clusterID string, autoscaler *Autoscaler) error {
+	body, err := json.Marshal(autoscaler)
+	if err != nil {
+		return err
+	}
+	_, err = raw.Send(
+		connection.Patch().
+			Path(resourcePath(clusterID)).
+			Bytes(body),
+		http.StatusOK,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,616 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the interface of the client of the OCM API used by the commands.
+
+package ocm
+
+import (
+	"net"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	amsv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+
+	"github.com/openshift/moactl/pkg/defaults"
+	"github.com/openshift/moactl/pkg/ocm/autoscalers"
+	"github.com/openshift/moactl/pkg/ocm/htpasswd"
+	"github.com/openshift/moactl/pkg/ocm/kubeletconfigs"
+	"github.com/openshift/moactl/pkg/ocm/machinepools"
+	"github.com/openshift/moactl/pkg/ocm/machines"
+	"github.com/openshift/moactl/pkg/ocm/oidcconfigs"
+	"github.com/openshift/moactl/pkg/ocm/roles"
+	"github.com/openshift/moactl/pkg/ocm/tuningconfigs"
+	"github.com/openshift/moactl/pkg/ocm/upgrades"
+	"github.com/openshift/moactl/pkg/ocm/versions"
+)
+
+// Client defines the operations of the OCM API used by the commands, so that they can be tested
+// with a mock of this interface instead of a real connection.
+type Client interface {
+	// URL returns the URL of the OCM API that the client talks to.
+	URL() string
+
+	// Clusters:
+	GetCluster(clusterKey string, creatorARN string) (*cmv1.Cluster, error)
+	GetClusterByID(clusterID string) (*cmv1.Cluster, error)
+	HasClusters(creatorARN string) (bool, error)
+	GetClusters(creatorARN string, count int) ([]*cmv1.Cluster, error)
+	SearchClusters(creatorARN string, search string) ([]*cmv1.Cluster, error)
+	StreamClusters(creatorARN string, search string, pageSize int,
+		fn func(page []*cmv1.Cluster) bool) error
+	GetClustersPage(creatorARN string, search string, page int, size int) ([]*cmv1.Cluster,
+		int, error)
+	AddCluster(spec *cmv1.Cluster, details map[string]interface{}, dryRun bool) (*cmv1.Cluster,
+		error)
+	UpdateCluster(clusterID string, spec *cmv1.Cluster) error
+	PatchCluster(clusterID string, fields map[string]interface{}) error
+	DeleteCluster(clusterID string) error
+	ImportCluster(cluster *cmv1.Cluster, creatorARN string) error
+	GetClusterDocument(clusterID string) ([]byte, error)
+	GetClusterState(clusterID string) (cmv1.ClusterState, error)
+	GetClusterStatus(clusterID string) (*cmv1.ClusterStatus, error)
+	GetInfraID(clusterID string) (string, error)
+	GetPrivateLink(clusterID string) (bool, error)
+	GetKMSKeyARN(clusterID string) (string, error)
+	GetCompliance(clusterID string) (*Compliance, error)
+	SetDeleteProtection(cluster *cmv1.Cluster, enabled bool) error
+	HibernateCluster(clusterID string) error
+	ResumeCluster(clusterID string) error
+	RetryInstall(clusterID string) error
+	WaitForState(clusterID string, target cmv1.ClusterState, interval time.Duration,
+		timeout time.Duration, fn func(state cmv1.ClusterState)) error
+	WatchInstall(clusterID string, interval time.Duration, timeout time.Duration,
+		fn func(state cmv1.ClusterState, logs *cmv1.Log)) (cmv1.ClusterState, error)
+	WaitForUninstall(clusterID string, interval time.Duration, timeout time.Duration) error
+	GetInstallLogs(clusterID string, tail int) (*cmv1.Log, error)
+	GetUninstallLogs(clusterID string, tail int) (*cmv1.Log, error)
+	PollUninstallLogs(clusterID string, cb func(*cmv1.LogGetResponse) bool) (*cmv1.Log, error)
+
+	// Identity providers, users and groups:
+	GetIdentityProviders(clusterID string) ([]*cmv1.IdentityProvider, error)
+	AddIdentityProvider(clusterID string, idp *cmv1.IdentityProvider) error
+	DeleteIdentityProvider(clusterID string, idpID string) error
+	GetAdminIdentityProvider(clusterID string) (*cmv1.IdentityProvider, error)
+	CreateHTPasswdIdentityProvider(clusterID string, name string, mappingMethod string,
+		users []*htpasswd.User) error
+	GetHTPasswdUsers(clusterID string, idpID string) ([]*htpasswd.User, error)
+	AddHTPasswdUser(clusterID string, idpID string, user *htpasswd.User) error
+	UpdateHTPasswdPassword(clusterID string, idpID string, userID string, password string) error
+	DeleteHTPasswdUser(clusterID string, idpID string, userID string) error
+	GetUser(clusterID string, group string, username string) (*cmv1.User, error)
+	GetUsers(clusterID string, group string) ([]*cmv1.User, error)
+	GetGroupMembers(cluster *cmv1.Cluster) ([]string, map[string][]string, error)
+	AddGroupUser(clusterID string, group string, username string) error
+	DeleteGroupUser(clusterID string, group string, username string) error
+	SetAccessExpiration(cluster *cmv1.Cluster, group string, username string,
+		expiration time.Time) error
+	RemoveAccessExpiration(cluster *cmv1.Cluster, group string, username string) error
+	RemoveAccessExpirations(cluster *cmv1.Cluster, expirations []*AccessExpiration) error
+
+	// Ingresses:
+	GetIngresses(clusterID string) ([]*cmv1.Ingress, error)
+	AddIngress(clusterID string, ingress *cmv1.Ingress) error
+	UpdateIngress(clusterID string, ingressID string, ingress *cmv1.Ingress) error
+	DeleteIngress(clusterID string, ingressID string) error
+
+	// Machine pools and their configurations:
+	GetMachinePools(clusterID string) ([]*cmv1.MachinePool, error)
+	GetMachinePool(clusterID string, machinePoolID string) (*cmv1.MachinePool,
+		*machinepools.SpotMarketOptions, []byte, error)
+	AddMachinePool(clusterID string, machinePool *cmv1.MachinePool,
+		spot *machinepools.SpotMarketOptions, subnetID string, diskSize int) error
+	UpdateMachinePool(clusterID string, machinePoolID string,
+		machinePool *cmv1.MachinePool) error
+	UpdateInstanceType(clusterID string, machinePoolID string, instanceType string,
+		maxSurge string, maxUnavailable string) error
+	UpdateNodeConfigs(clusterID string, machinePoolID string, kubeletConfigs []string,
+		tuningConfigs []string) error
+	DeleteMachinePool(clusterID string, machinePoolID string) error
+	SetMachinePoolDeleteProtection(cluster *cmv1.Cluster, machinePoolID string,
+		enabled bool) error
+	GetSpotMarketOptions(clusterID string) (map[string]*machinepools.SpotMarketOptions, error)
+	GetAutoscaler(clusterID string) (*autoscalers.Autoscaler, error)
+	CreateAutoscaler(clusterID string, autoscaler *autoscalers.Autoscaler) error
+	UpdateAutoscaler(clusterID string, autoscaler *autoscalers.Autoscaler) error
+	GetKubeletConfigs(clusterID string) ([]*kubeletconfigs.KubeletConfig, error)
+	GetKubeletConfig(clusterID string, name string) (*kubeletconfigs.KubeletConfig, error)
+	CreateKubeletConfig(clusterID string,
+		config *kubeletconfigs.KubeletConfig) (*kubeletconfigs.KubeletConfig, error)
+	UpdateKubeletConfig(clusterID string, configID string, podPidsLimit int) error
+	GetTuningConfigs(clusterID string) ([]*tuningconfigs.TuningConfig, error)
+	GetTuningConfig(clusterID string, name string) (*tuningconfigs.TuningConfig, error)
+	CreateTuningConfig(clusterID string,
+		config *tuningconfigs.TuningConfig) (*tuningconfigs.TuningConfig, error)
+	UpdateTuningConfig(clusterID string, configID string, spec map[string]interface{}) error
+
+	// Add-ons:
+	GetAddOn(id string) (*cmv1.AddOn, error)
+	GetAddOnSchema(id string) (*AddOnSchema, error)
+	GetClusterAddOns(clusterID string) ([]*ClusterAddOn, error)
+	InstallAddOn(clusterID string, addOnID string, params map[string]string) error
+	GetAddOnInstallation(clusterID string, addOnID string) (*cmv1.AddOnInstallation, error)
+	UninstallAddOn(clusterID string, addOnID string) error
+
+	// Versions and upgrades:
+	GetVersions(channelGroup string) ([]*cmv1.Version, error)
+	GetAvailableUpgrades(versionID string) ([]string, error)
+	GetEndOfLifeDates(channelGroup string) (map[string]time.Time, error)
+	GetUpgradePolicies(clusterID string) ([]*cmv1.UpgradePolicy, error)
+	GetScheduledUpgrade(clusterID string) (*cmv1.UpgradePolicy, error)
+	GetUpgradeState(clusterID string, upgradePolicyID string) (*cmv1.UpgradePolicyState, error)
+	ScheduleUpgrade(clusterID string, version string, nextRun time.Time) error
+	CancelUpgrade(clusterID string) (bool, error)
+
+	// Service logs:
+	GetServiceLogs(externalID string) ([]*slv1.LogEntry, error)
+	ListServiceLogs(externalID string, filter ServiceLogFilter) ([]*slv1.LogEntry, error)
+	CreateServiceLog(entry *slv1.LogEntry) (*slv1.LogEntry, error)
+
+	// Accounts, organizations and the rest of the service:
+	GetCurrentAccount() (*amsv1.Account, error)
+	GetAccess() (*roles.Access, error)
+	LoadDefaults() (*defaults.Defaults, error)
+	GetClusterQuotaCost(multiAZ bool, computeMachineType string,
+		computeNodes int) ([]*QuotaCost, error)
+	GetMachineTypes() ([]*cmv1.MachineType, error)
+	GetMachineTypeList() ([]string, error)
+	GetAvailableRegions(credentials *cmv1.AWS) ([]*cmv1.CloudRegion, error)
+	GetDefaultClusterFlavors() (*net.IPNet, *net.IPNet, *net.IPNet, int)
+	CreateOIDCConfig(config *oidcconfigs.OIDCConfig) (*oidcconfigs.OIDCConfig, error)
+	GetMinimumCLIVersion() (string, error)
+}
+
+// client implements the Client interface using a connection to the OCM API.
+type client struct {
+	connection *sdk.Connection
+}
+
+// NewClient creates a client that uses the given connection to the OCM API.
+func NewClient(connection *sdk.Connection) Client {
+	return &client{
+		connection: connection,
+	}
+}
+
+func (c *client) clustersMgmt() *cmv1.Client {
+	return c.connection.ClustersMgmt().V1()
+}
+
+func (c *client) clusters() *cmv1.ClustersClient {
+	return c.clustersMgmt().Clusters()
+}
+
+func (c *client) URL() string {
+	return c.connection.URL()
+}
+
+func (c *client) GetCluster(clusterKey string, creatorARN string) (*cmv1.Cluster, error) {
+	return GetCluster(c.clusters(), clusterKey, creatorARN)
+}
+
+func (c *client) GetClusterByID(clusterID string) (*cmv1.Cluster, error) {
+	return GetClusterByID(c.clusters(), clusterID)
+}
+
+func (c *client) HasClusters(creatorARN string) (bool, error) {
+	return HasClusters(c.clusters(), creatorARN)
+}
+
+func (c *client) GetClusters(creatorARN string, count int) ([]*cmv1.Cluster, error) {
+	return GetClusters(c.clusters(), creatorARN, count)
+}
+
+func (c *client) SearchClusters(creatorARN string, search string) ([]*cmv1.Cluster, error) {
+	return SearchClusters(c.clusters(), creatorARN, search)
+}
+
+func (c *client) StreamClusters(creatorARN string, search string, pageSize int,
+	fn func(page []*cmv1.Cluster) bool) error {
+	return StreamClusters(c.clusters(), creatorARN, search, pageSize, fn)
+}
+
+func (c *client) GetClustersPage(creatorARN string, search string, page int,
+	size int) ([]*cmv1.Cluster, int, error) {
+	return GetClustersPage(c.clusters(), creatorARN, search, page, size)
+}
+
+func (c *client) AddCluster(spec *cmv1.Cluster, details map[string]interface{},
+	dryRun bool) (*cmv1.Cluster, error) {
+	return AddCluster(c.connection, spec, details, dryRun)
+}
+
+func (c *client) UpdateCluster(clusterID string, spec *cmv1.Cluster) error {
+	return UpdateCluster(c.clusters(), clusterID, spec)
+}
+
+func (c *client) PatchCluster(clusterID string, fields map[string]interface{}) error {
+	return PatchCluster(c.connection, clusterID, fields)
+}
+
+func (c *client) DeleteCluster(clusterID string) error {
+	return DeleteCluster(c.clusters(), clusterID)
+}
+
+func (c *client) ImportCluster(cluster *cmv1.Cluster, creatorARN string) error {
+	return ImportCluster(c.clusters(), cluster, creatorARN)
+}
+
+func (c *client) GetClusterDocument(clusterID string) ([]byte, error) {
+	return GetClusterDocument(c.connection, clusterID)
+}
+
+func (c *client) GetClusterState(clusterID string) (cmv1.ClusterState, error) {
+	return GetClusterState(c.clusters(), clusterID)
+}
+
+func (c *client) GetClusterStatus(clusterID string) (*cmv1.ClusterStatus, error) {
+	return GetClusterStatus(c.clusters(), clusterID)
+}
+
+func (c *client) GetInfraID(clusterID string) (string, error) {
+	return GetInfraID(c.connection, clusterID)
+}
+
+func (c *client) GetPrivateLink(clusterID string) (bool, error) {
+	return GetPrivateLink(c.connection, clusterID)
+}
+
+func (c *client) GetKMSKeyARN(clusterID string) (string, error) {
+	return GetKMSKeyARN(c.connection, clusterID)
+}
+
+func (c *client) GetCompliance(clusterID string) (*Compliance, error) {
+	return GetCompliance(c.connection, clusterID)
+}
+
+func (c *client) SetDeleteProtection(cluster *cmv1.Cluster, enabled bool) error {
+	return SetDeleteProtection(c.clusters(), cluster, enabled)
+}
+
+func (c *client) HibernateCluster(clusterID string) error {
+	return HibernateCluster(c.connection, clusterID)
+}
+
+func (c *client) ResumeCluster(clusterID string) error {
+	return ResumeCluster(c.connection, clusterID)
+}
+
+func (c *client) RetryInstall(clusterID string) error {
+	return RetryInstall(c.connection, clusterID)
+}
+
+func (c *client) WaitForState(clusterID string, target cmv1.ClusterState, interval time.Duration,
+	timeout time.Duration, fn func(state cmv1.ClusterState)) error {
+	return WaitForState(c.clusters(), clusterID, target, interval, timeout, fn)
+}
+
+func (c *client) WatchInstall(clusterID string, interval time.Duration, timeout time.Duration,
+	fn func(state cmv1.ClusterState, logs *cmv1.Log)) (cmv1.ClusterState, error) {
+	return WatchInstall(c.clusters(), clusterID, interval, timeout, fn)
+}
+
+func (c *client) WaitForUninstall(clusterID string, interval time.Duration,
+	timeout time.Duration) error {
+	return WaitForUninstall(c.clusters(), clusterID, interval, timeout)
+}
+
+func (c *client) GetInstallLogs(clusterID string, tail int) (*cmv1.Log, error) {
+	return GetInstallLogs(c.clusters(), clusterID, tail)
+}
+
+func (c *client) GetUninstallLogs(clusterID string, tail int) (*cmv1.Log, error) {
+	return GetUninstallLogs(c.clusters(), clusterID, tail)
+}
+
+func (c *client) PollUninstallLogs(clusterID string,
+	cb func(*cmv1.LogGetResponse) bool) (*cmv1.Log, error) {
+	return PollUninstallLogs(c.clusters(), clusterID, cb)
+}
+
+func (c *client) GetIdentityProviders(clusterID string) ([]*cmv1.IdentityProvider, error) {
+	return GetIdentityProviders(c.clusters(), clusterID)
+}
+
+func (c *client) AddIdentityProvider(clusterID string, idp *cmv1.IdentityProvider) error {
+	return AddIdentityProvider(c.clusters(), clusterID, idp)
+}
+
+func (c *client) DeleteIdentityProvider(clusterID string, idpID string) error {
+	return DeleteIdentityProvider(c.clusters(), clusterID, idpID)
+}
+
+func (c *client) GetAdminIdentityProvider(clusterID string) (*cmv1.IdentityProvider, error) {
+	return GetAdminIdentityProvider(c.clusters(), clusterID)
+}
+
+func (c *client) CreateHTPasswdIdentityProvider(clusterID string, name string,
+	mappingMethod string, users []*htpasswd.User) error {
+	return htpasswd.CreateIdentityProvider(c.connection, clusterID, name, mappingMethod, users)
+}
+
+func (c *client) GetHTPasswdUsers(clusterID string, idpID string) ([]*htpasswd.User, error) {
+	return htpasswd.GetUsers(c.connection, clusterID, idpID)
+}
+
+func (c *client) AddHTPasswdUser(clusterID string, idpID string, user *htpasswd.User) error {
+	return htpasswd.AddUser(c.connection, clusterID, idpID, user)
+}
+
+func (c *client) UpdateHTPasswdPassword(clusterID string, idpID string, userID string,
+	password string) error {
+	return htpasswd.UpdatePassword(c.connection, clusterID, idpID, userID, password)
+}
+
+func (c *client) DeleteHTPasswdUser(clusterID string, idpID string, userID string) error {
+	return htpasswd.DeleteUser(c.connection, clusterID, idpID, userID)
+}
+
+func (c *client) GetUser(clusterID string, group string, username string) (*cmv1.User, error) {
+	return GetUser(c.clusters(), clusterID, group, username)
+}
+
+func (c *client) GetUsers(clusterID string, group string) ([]*cmv1.User, error) {
+	return GetUsers(c.clusters(), clusterID, group)
+}
+
+func (c *client) GetGroupMembers(cluster *cmv1.Cluster) ([]string, map[string][]string, error) {
+	return GetGroupMembers(c.clusters(), cluster)
+}
+
+func (c *client) AddGroupUser(clusterID string, group string, username string) error {
+	return AddGroupUser(c.clusters(), clusterID, group, username)
+}
+
+func (c *client) DeleteGroupUser(clusterID string, group string, username string) error {
+	return DeleteGroupUser(c.clusters(), clusterID, group, username)
+}
+
+func (c *client) SetAccessExpiration(cluster *cmv1.Cluster, group string, username string,
+	expiration time.Time) error {
+	return SetAccessExpiration(c.clusters(), cluster, group, username, expiration)
+}
+
+func (c *client) RemoveAccessExpiration(cluster *cmv1.Cluster, group string,
+	username string) error {
+	return RemoveAccessExpiration(c.clusters(), cluster, group, username)
+}
+
+func (c *client) RemoveAccessExpirations(cluster *cmv1.Cluster,
+	expirations []*AccessExpiration) error {
+	return RemoveAccessExpirations(c.clusters(), cluster, expirations)
+}
+
+func (c *client) GetIngresses(clusterID string) ([]*cmv1.Ingress, error) {
+	return GetIngresses(c.clusters(), clusterID)
+}
+
+func (c *client) AddIngress(clusterID string, ingress *cmv1.Ingress) error {
+	return AddIngress(c.clusters(), clusterID, ingress)
+}
+
+func (c *client) UpdateIngress(clusterID string, ingressID string, ingress *cmv1.Ingress) error {
+	return UpdateIngress(c.clusters(), clusterID, ingressID, ingress)
+}
+
+func (c *client) DeleteIngress(clusterID string, ingressID string) error {
+	return DeleteIngress(c.clusters(), clusterID, ingressID)
+}
+
+func (c *client) GetMachinePools(clusterID string) ([]*cmv1.MachinePool, error) {
+	return GetMachinePools(c.clusters(), clusterID)
+}
+
+func (c *client) GetMachinePool(clusterID string, machinePoolID string) (*cmv1.MachinePool,
+	*machinepools.SpotMarketOptions, []byte, error) {
+	return machinepools.GetMachinePool(c.connection, clusterID, machinePoolID)
+}
+
+func (c *client) AddMachinePool(clusterID string, machinePool *cmv1.MachinePool,
+	spot *machinepools.SpotMarketOptions, subnetID string, diskSize int) error {
+	return machinepools.AddMachinePool(c.connection, clusterID, machinePool, spot, subnetID, diskSize)
+}
+
+func (c *client) UpdateMachinePool(clusterID string, machinePoolID string,
+	machinePool *cmv1.MachinePool) error {
+	return UpdateMachinePool(c.clusters(), clusterID, machinePoolID, machinePool)
+}
+
+func (c *client) UpdateInstanceType(clusterID string, machinePoolID string, instanceType string,
+	maxSurge string, maxUnavailable string) error {
+	return machinepools.UpdateInstanceType(c.connection, clusterID, machinePoolID, instanceType, maxSurge, maxUnavailable)
+}
+
+func (c *client) UpdateNodeConfigs(clusterID string, machinePoolID string, kubeletConfigs []string,
+	tuningConfigs []string) error {
+	return machinepools.UpdateNodeConfigs(c.connection, clusterID, machinePoolID, kubeletConfigs, tuningConfigs)
+}
+
+func (c *client) DeleteMachinePool(clusterID string, machinePoolID string) error {
+	return DeleteMachinePool(c.clusters(), clusterID, machinePoolID)
+}
+
+func (c *client) SetMachinePoolDeleteProtection(cluster *cmv1.Cluster, machinePoolID string,
+	enabled bool) error {
+	return SetMachinePoolDeleteProtection(c.clusters(), cluster, machinePoolID, enabled)
+}
+
+func (c *client) GetSpotMarketOptions(clusterID string) (map[string]*machinepools.SpotMarketOptions, error) {
+	return machinepools.GetSpotMarketOptions(c.connection, clusterID)
+}
+
+func (c *client) GetAutoscaler(clusterID string) (*autoscalers.Autoscaler, error) {
+	return autoscalers.GetAutoscaler(c.connection, clusterID)
+}
+
+func (c *client) CreateAutoscaler(clusterID string, autoscaler *autoscalers.Autoscaler) error {
+	return autoscalers.CreateAutoscaler(c.connection, clusterID, autoscaler)
+}
+
+func (c *client) UpdateAutoscaler(clusterID string, autoscaler *autoscalers.Autoscaler) error {
+	return autoscalers.UpdateAutoscaler(c.connection, clusterID, autoscaler)
+}
+
+func (c *client) GetKubeletConfigs(clusterID string) ([]*kubeletconfigs.KubeletConfig, error) {
+	return kubeletconfigs.GetKubeletConfigs(c.connection, clusterID)
+}
+
+func (c *client) GetKubeletConfig(clusterID string, name string) (*kubeletconfigs.KubeletConfig,
+	error) {
+	return kubeletconfigs.GetKubeletConfig(c.connection, clusterID, name)
+}
+
+func (c *client) CreateKubeletConfig(clusterID string,
+	config *kubeletconfigs.KubeletConfig) (*kubeletconfigs.KubeletConfig, error) {
+	return kubeletconfigs.CreateKubeletConfig(c.connection, clusterID, config)
+}
+
+func (c *client) UpdateKubeletConfig(clusterID string, configID string, podPidsLimit int) error {
+	return kubeletconfigs.UpdateKubeletConfig(c.connection, clusterID, configID, podPidsLimit)
+}
+
+func (c *client) GetTuningConfigs(clusterID string) ([]*tuningconfigs.TuningConfig, error) {
+	return tuningconfigs.GetTuningConfigs(c.connection, clusterID)
+}
+
+func (c *client) GetTuningConfig(clusterID string, name string) (*tuningconfigs.TuningConfig,
+	error) {
+	return tuningconfigs.GetTuningConfig(c.connection, clusterID, name)
+}
+
+func (c *client) CreateTuningConfig(clusterID string,
+	config *tuningconfigs.TuningConfig) (*tuningconfigs.TuningConfig, error) {
+	return tuningconfigs.CreateTuningConfig(c.connection, clusterID, config)
+}
+
+func (c *client) UpdateTuningConfig(clusterID string, configID string,
+	spec map[string]interface{}) error {
+	return tuningconfigs.UpdateTuningConfig(c.connection, clusterID, configID, spec)
+}
+
+func (c *client) GetAddOn(id string) (*cmv1.AddOn, error) {
+	return GetAddOn(c.clustersMgmt().Addons(), id)
+}
+
+func (c *client) GetAddOnSchema(id string) (*AddOnSchema, error) {
+	return GetAddOnSchema(c.connection, id)
+}
+
+func (c *client) GetClusterAddOns(clusterID string) ([]*ClusterAddOn, error) {
+	return GetClusterAddOns(c.connection, clusterID)
+}
+
+func (c *client) InstallAddOn(clusterID string, addOnID string, params map[string]string) error {
+	return InstallAddOn(c.clusters(), clusterID, addOnID, params)
+}
+
+func (c *client) GetAddOnInstallation(clusterID string, addOnID string) (*cmv1.AddOnInstallation,
+	error) {
+	return GetAddOnInstallation(c.clusters(), clusterID, addOnID)
+}
+
+func (c *client) UninstallAddOn(clusterID string, addOnID string) error {
+	return UninstallAddOn(c.connection, clusterID, addOnID)
+}
+
+func (c *client) GetVersions(channelGroup string) ([]*cmv1.Version, error) {
+	return versions.GetVersions(c.clustersMgmt(), channelGroup)
+}
+
+func (c *client) GetAvailableUpgrades(versionID string) ([]string, error) {
+	return versions.GetAvailableUpgrades(c.clustersMgmt(), versionID)
+}
+
+func (c *client) GetEndOfLifeDates(channelGroup string) (map[string]time.Time, error) {
+	return versions.GetEndOfLifeDates(c.connection, channelGroup)
+}
+
+func (c *client) GetUpgradePolicies(clusterID string) ([]*cmv1.UpgradePolicy, error) {
+	return upgrades.GetUpgradePolicies(c.clustersMgmt(), clusterID)
+}
+
+func (c *client) GetScheduledUpgrade(clusterID string) (*cmv1.UpgradePolicy, error) {
+	return upgrades.GetScheduledUpgrade(c.clustersMgmt(), clusterID)
+}
+
+func (c *client) GetUpgradeState(clusterID string,
+	upgradePolicyID string) (*cmv1.UpgradePolicyState, error) {
+	return upgrades.GetUpgradeState(c.clustersMgmt(), clusterID, upgradePolicyID)
+}
+
+func (c *client) ScheduleUpgrade(clusterID string, version string, nextRun time.Time) error {
+	return upgrades.ScheduleUpgrade(c.clustersMgmt(), clusterID, version, nextRun)
+}
+
+func (c *client) CancelUpgrade(clusterID string) (bool, error) {
+	return upgrades.CancelUpgrade(c.clustersMgmt(), clusterID)
+}
+
+func (c *client) GetServiceLogs(externalID string) ([]*slv1.LogEntry, error) {
+	return GetServiceLogs(c.connection, externalID)
+}
+
+func (c *client) ListServiceLogs(externalID string, filter ServiceLogFilter) ([]*slv1.LogEntry,
+	error) {
+	return ListServiceLogs(c.connection, externalID, filter)
+}
+
+func (c *client) CreateServiceLog(entry *slv1.LogEntry) (*slv1.LogEntry, error) {
+	return CreateServiceLog(c.connection, entry)
+}
+
+func (c *client) GetCurrentAccount() (*amsv1.Account, error) {
+	return GetCurrentAccount(c.connection)
+}
+
+func (c *client) GetAccess() (*roles.Access, error) {
+	return roles.GetAccess(c.connection)
+}
+
+func (c *client) LoadDefaults() (*defaults.Defaults, error) {
+	return defaults.Load(c.connection)
+}
+
+func (c *client) GetClusterQuotaCost(multiAZ bool, computeMachineType string,
+	computeNodes int) ([]*QuotaCost, error) {
+	return GetClusterQuotaCost(c.connection, multiAZ, computeMachineType, computeNodes)
+}
+
+func (c *client) GetMachineTypes() ([]*cmv1.MachineType, error) {
+	return machines.GetMachineTypes(c.clustersMgmt())
+}
+
+func (c *client) GetMachineTypeList() ([]string, error) {
+	return machines.GetMachineTypeList(c.clustersMgmt())
+}
+
+func (c *client) GetAvailableRegions(credentials *cmv1.AWS) ([]*cmv1.CloudRegion, error) {
+	return GetAvailableRegions(c.clustersMgmt(), credentials)
+}
+
+func (c *client) GetDefaultClusterFlavors() (*net.IPNet, *net.IPNet, *net.IPNet, int) {
+	return GetDefaultClusterFlavors(c.clustersMgmt())
+}
+
+func (c *client) CreateOIDCConfig(config *oidcconfigs.OIDCConfig) (*oidcconfigs.OIDCConfig, error) {
+	return oidcconfigs.CreateOIDCConfig(c.connection, config)
+}
+
+func (c *client) GetMinimumCLIVersion() (string, error) {
+	return GetMinimumCLIVersion(c.connection)
+}
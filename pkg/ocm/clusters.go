@@ -0,0 +1,106 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that retrieve the collections of clusters created by the current
+// AWS user.
+
+package ocm
+
+import (
+	"errors"
+	"fmt"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/ocm/properties"
+)
+
+func GetClusters(client *cmv1.ClustersClient, creatorARN string, count int) (clusters []*cmv1.Cluster, err error) {
+	err = StreamClusters(client, creatorARN, "", count, func(page []*cmv1.Cluster) bool {
+		clusters = append(clusters, page...)
+		return true
+	})
+	return clusters, err
+}
+
+// StreamClusters retrieves the clusters created by the given creator that match the given OCM search
+// expression, or all of them if it is empty, one page at a time, and passes each page to the given
+// function as soon as it arrives, so that the complete collection doesn't need to be kept in
+// memory. Retrieval stops when the function returns false.
+func StreamClusters(client *cmv1.ClustersClient, creatorARN string, search string, pageSize int,
+	fn func(page []*cmv1.Cluster) bool) error {
+	if pageSize < 1 {
+		return errors.New("Cannot fetch fewer than 1 cluster")
+	}
+	query := fmt.Sprintf("properties.%s = '%s'", properties.CreatorARN, creatorARN)
+	if search != "" {
+		query = fmt.Sprintf("(%s) and %s", search, query)
+	}
+	request := client.List().Search(query)
+	page := 1
+	for {
+		response, err := request.Page(page).Size(pageSize).Send()
+		if err != nil {
+			return handleErr(response.Error(), err)
+		}
+		if !fn(response.Items().Slice()) || response.Size() < pageSize {
+			break
+		}
+		page++
+	}
+	return nil
+}
+
+// GetClustersPage retrieves one page of the clusters created by the given creator that match the
+// given OCM search expression, or all of them if it is empty. It also returns the total number of
+// clusters that match, so that callers can tell if there are more pages.
+func GetClustersPage(client *cmv1.ClustersClient, creatorARN string, search string, page int,
+	size int) (clusters []*cmv1.Cluster, total int, err error) {
+	if page < 1 || size < 1 {
+		return nil, 0, errors.New("Page and size must be positive numbers")
+	}
+	query := fmt.Sprintf("properties.%s = '%s'", properties.CreatorARN, creatorARN)
+	if search != "" {
+		query = fmt.Sprintf("(%s) and %s", search, query)
+	}
+	response, err := client.List().Search(query).Page(page).Size(size).Send()
+	if err != nil {
+		return nil, 0, handleErr(response.Error(), err)
+	}
+	return response.Items().Slice(), response.Total(), nil
+}
+
+// SearchClusters returns the clusters created by the given creator that match the given OCM search
+// expression.
+func SearchClusters(client *cmv1.ClustersClient, creatorARN string, search string) (
+	clusters []*cmv1.Cluster, err error) {
+	query := fmt.Sprintf("(%s) and properties.%s = '%s'", search, properties.CreatorARN, creatorARN)
+	request := client.List().Search(query)
+	page := 1
+	size := 100
+	for {
+		response, err := request.Page(page).Size(size).Send()
+		if err != nil {
+			return nil, handleErr(response.Error(), err)
+		}
+		clusters = append(clusters, response.Items().Slice()...)
+		if response.Size() < size {
+			break
+		}
+		page++
+	}
+	return clusters, nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the compliance settings of clusters: FIPS mode and the monitoring of user
+// workloads.
+
+package ocm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+
+	"github.com/openshift/moactl/pkg/info"
+	"github.com/openshift/moactl/pkg/ocm/raw"
+)
+
+// MinFIPSVersion is the oldest OpenShift version that can be installed in FIPS mode.
+const MinFIPSVersion = "4.10"
+
+// Compliance contains the compliance settings of a cluster.
+type Compliance struct {
+	// FIPS is true when the cluster uses FIPS validated cryptographic libraries.
+	FIPS bool `json:"fips"`
+
+	// DisableWorkloadMonitoring is true when the monitoring stack for user workloads is disabled.
+	DisableWorkloadMonitoring bool `json:"disable_user_workload_monitoring"`
+}
+
+// ValidateFIPSVersion checks that clusters with the given OpenShift version, like '4.10.3', can be
+// installed in FIPS mode.
+func ValidateFIPSVersion(version string) error {
+	result, err := info.CompareVersions(version, MinFIPSVersion)
+	if err != nil {
+		return err
+	}
+	if result < 0 {
+		return fmt.Errorf("FIPS mode isn't supported for OpenShift version '%s', it requires version "+
+			"%s or newer", version, MinFIPSVersion)
+	}
+	return nil
+}
+
+// GetCompliance returns the compliance settings of the cluster with the given identifier.
+func GetCompliance(connection *sdk.Connection, clusterID string) (*Compliance, error) {
+	response, err := raw.Send(
+		connection.Get().
+			Path("/api/clusters_mgmt/v1/clusters/"+clusterID),
+		http.StatusOK,
+	)
+	if err != nil {
+		return nil, err
+	}
+	result := &Compliance{}
+	err = json.Unmarshal(response.Bytes(), result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -33,6 +33,7 @@ import (
 	sdk "github.com/openshift-online/ocm-sdk-go"
 
 	"github.com/openshift/moactl/pkg/debug"
+	"github.com/openshift/moactl/pkg/simulate"
 )
 
 // URLAliases allows the value of the `--env` option to map to the various API URLs.
@@ -52,11 +53,25 @@ type Config struct {
 	Scopes       []string `json:"scopes,omitempty"`
 	TokenURL     string   `json:"token_url,omitempty"`
 	URL          string   `json:"url,omitempty"`
+
+	// Keyring is true when the tokens are kept in the keychain of the operating system instead
+	// of in this file.
+	Keyring bool `json:"keyring,omitempty"`
+
+	// keyringTokens are the tokens that were read from or written to the keychain, so that
+	// they are only written again when they change, and keyringErr is the error reading them.
+	keyringTokens *keyringTokens
+	keyringErr    error
 }
 
 // Load loads the configuration from the configuration file. If the configuration file doesn't exist
 // it will return an empty configuration object.
 func Load() (cfg *Config, err error) {
+	// The simulation mode neither uses nor changes the configuration of the real account:
+	if simulate.Enabled() {
+		cfg = loadSimulated()
+		return
+	}
 	file, err := Location()
 	if err != nil {
 		return
@@ -83,16 +98,90 @@ func Load() (cfg *Config, err error) {
 		err = fmt.Errorf("Failed to parse config file '%s': %v", file, err)
 		return
 	}
+	// Failing to read the tokens from the keychain only affects the commands that need them, so
+	// the error is reported when the connection is created:
+	if cfg.Keyring {
+		cfg.keyringTokens, cfg.keyringErr = readKeyring(file)
+		if cfg.keyringErr == nil {
+			cfg.AccessToken = cfg.keyringTokens.AccessToken
+			cfg.RefreshToken = cfg.keyringTokens.RefreshToken
+		}
+	}
 	return
 }
 
-// Save saves the given configuration to the configuration file.
+// simulated is the configuration used in simulation mode. It is only kept in memory.
+var simulated *Config
+
+// loadSimulated returns a copy of the configuration used in simulation mode, logged in as the
+// simulated user.
+func loadSimulated() *Config {
+	if simulated == nil {
+		simulated = &Config{
+			URL:         simulate.OCMURL,
+			AccessToken: simulate.AccessToken(),
+		}
+	}
+	result := *simulated
+	return &result
+}
+
+// KeyringError returns the error that happened reading the tokens from the keychain, if any.
+func (c *Config) KeyringError() error {
+	return c.keyringErr
+}
+
+// Save saves the given configuration to the configuration file. The tokens are saved to the
+// keychain of the operating system when it is available, and otherwise to the file, which is only
+// readable by the user.
 func Save(cfg *Config) error {
+	if simulate.Enabled() {
+		saved := *cfg
+		simulated = &saved
+		return nil
+	}
 	file, err := Location()
 	if err != nil {
 		return err
 	}
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	saved := *cfg
+	switch {
+	case cfg.keyringErr != nil:
+		// The tokens that couldn't be read are kept in the keychain as they are:
+		saved.AccessToken = ""
+		saved.RefreshToken = ""
+	case cfg.AccessToken == "" && cfg.RefreshToken == "":
+		// Configurations without tokens, like the one saved when logging out, remove them from
+		// the keychain as well:
+		if cfg.Keyring || KeyringAvailable() {
+			err = deleteKeyring(file)
+			if err != nil {
+				return err
+			}
+		}
+		saved.Keyring = false
+	case KeyringAvailable():
+		tokens := &keyringTokens{
+			AccessToken:  cfg.AccessToken,
+			RefreshToken: cfg.RefreshToken,
+		}
+		if cfg.keyringTokens == nil || *cfg.keyringTokens != *tokens {
+			err = writeKeyring(file, tokens)
+		}
+		if err == nil {
+			cfg.Keyring = true
+			cfg.keyringTokens = tokens
+			saved.Keyring = true
+			saved.AccessToken = ""
+			saved.RefreshToken = ""
+		} else {
+			// Fall back to the file, which is still protected by its permissions:
+			saved.Keyring = false
+		}
+	default:
+		saved.Keyring = false
+	}
+	data, err := json.MarshalIndent(&saved, "", "  ")
 	if err != nil {
 		return fmt.Errorf("Failed to marshal config: %v", err)
 	}
@@ -100,6 +189,11 @@ func Save(cfg *Config) error {
 	if err != nil {
 		return fmt.Errorf("Failed to write file '%s': %v", file, err)
 	}
+	// The permissions aren't changed when the file already exists, so they are set explicitly:
+	err = os.Chmod(file, 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to set permissions of file '%s': %v", file, err)
+	}
 	return nil
 }
 
@@ -113,6 +207,12 @@ func Remove() error {
 	if os.IsNotExist(err) {
 		return nil
 	}
+	if cfg, err := Load(); err == nil && cfg != nil && cfg.Keyring {
+		err = deleteKeyring(file)
+		if err != nil {
+			return err
+		}
+	}
 	err = os.Remove(file)
 	if err != nil {
 		return err
@@ -122,7 +222,9 @@ func Remove() error {
 
 // Location returns the location of the configuration file.
 func Location() (path string, err error) {
-	if ocmconfig := os.Getenv("OCM_CONFIG"); ocmconfig != "" {
+	if flagPath := Path(); flagPath != "" {
+		path = flagPath
+	} else if ocmconfig := os.Getenv(configEnv); ocmconfig != "" {
 		path = ocmconfig
 	} else {
 		home, err := homedir.Dir()
@@ -209,6 +311,23 @@ func (c *Config) Armed() (armed bool, err error) {
 	return
 }
 
+// AccessTokenValid checks if the configuration contains an access token that doesn't expire in the
+// next minute, so that it can be used without requesting a new one.
+func (c *Config) AccessTokenValid() bool {
+	if c.AccessToken == "" {
+		return false
+	}
+	accessToken, err := parseToken(c.AccessToken)
+	if err != nil {
+		return false
+	}
+	expires, left, err := sdk.GetTokenExpiry(accessToken, time.Now())
+	if err != nil {
+		return false
+	}
+	return !expires || left > time.Minute
+}
+
 // Connection creates a connection using this configuration.
 func (c *Config) Connection() (connection *sdk.Connection, err error) {
 	// Create the logger:
@@ -0,0 +1,97 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the '--env' command line option, which selects the OCM environment used by a
+// single invocation, for example to run a command against the staging API with the credentials
+// saved by 'rosa login'.
+
+package config
+
+import (
+	"os"
+	"strings"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	"github.com/spf13/pflag"
+
+	rosaconfig "github.com/openshift/moactl/pkg/config"
+)
+
+// urlEnv is the environment variable that contains the OCM environment.
+const urlEnv = "OCM_URL"
+
+// AddEnvFlag adds the '--env' flag to the given set of command line flags.
+func AddEnvFlag(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&env,
+		"env",
+		"",
+		"Environment of the OCM API used by the command, instead of the one of 'rosa login'. "+
+			"The value can be the complete URL or an alias: 'production', 'staging' or "+
+			"'integration'. It can also be given in the 'OCM_URL' environment variable.",
+	)
+}
+
+// ResolveURL returns the URL of the given environment, which can be an alias or a URL.
+func ResolveURL(value string) string {
+	if url, ok := URLAliases[value]; ok {
+		return url
+	}
+	return value
+}
+
+// EnvURL returns the URL of the environment selected with the '--env' flag, the 'OCM_URL'
+// environment variable or the profile, in that order, or an empty string if none is selected.
+func EnvURL() string {
+	for _, value := range []string{env, os.Getenv(urlEnv), rosaconfig.Environment()} {
+		if value != "" {
+			return ResolveURL(value)
+		}
+	}
+	return ""
+}
+
+// APIURL returns the URL of the OCM API that the commands use: the one of the selected environment
+// or, if none is selected, the one saved by 'rosa login'.
+func (c *Config) APIURL() string {
+	if url := EnvURL(); url != "" {
+		return url
+	}
+	if c != nil && c.URL != "" {
+		return c.URL
+	}
+	return sdk.DefaultURL
+}
+
+// EnvName returns the alias of the environment of the given URL, like 'staging', or the URL itself
+// if it doesn't have an alias.
+func EnvName(url string) string {
+	url = strings.TrimSuffix(url, "/")
+	for name, item := range URLAliases {
+		if item == url {
+			return name
+		}
+	}
+	return url
+}
+
+// IsProduction checks if the given URL is the one of the production environment.
+func IsProduction(url string) bool {
+	return strings.TrimSuffix(url, "/") == URLAliases["production"]
+}
+
+// env is the environment given in the command line.
+var env string
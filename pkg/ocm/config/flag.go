@@ -0,0 +1,74 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to implement the '--ocm-config' command line option, which
+// selects the configuration file for a single invocation, so that several OCM accounts can be
+// used at the same time.
+
+package config
+
+import (
+	"os"
+
+	"github.com/spf13/pflag"
+)
+
+// configEnv is the environment variable that contains the location of the configuration file.
+const configEnv = "OCM_CONFIG"
+
+// AddFlag adds the '--ocm-config' flag, and its '--token-path' alias, to the given set of command
+// line flags.
+func AddFlag(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&path,
+		"ocm-config",
+		"",
+		"Location of the configuration file that contains the OCM credentials, instead of the one "+
+			"given in the 'OCM_CONFIG' environment variable or '~/.ocm.json'.",
+	)
+	flags.StringVar(
+		&path,
+		"token-path",
+		"",
+		"Alias of '--ocm-config'.",
+	)
+	flags.MarkHidden("token-path")
+}
+
+// ParseFlag extracts the value of the '--ocm-config' flag from the given command line, ignoring
+// the rest of the flags. It is needed because the configuration is loaded by some functions
+// before the command line is parsed. The value is also put in the environment, so that the
+// processes that the command starts use the same file.
+func ParseFlag(argv []string) {
+	flags := pflag.NewFlagSet("", pflag.ContinueOnError)
+	flags.ParseErrorsWhitelist.UnknownFlags = true
+	flags.Usage = func() {}
+	AddFlag(flags)
+	// Errors are ignored here, the command will report them when it is executed:
+	_ = flags.Parse(argv)
+	if path != "" {
+		os.Setenv(configEnv, path)
+	}
+}
+
+// Path returns the location of the configuration file given in the command line, or an empty
+// string if it wasn't given.
+func Path() string {
+	return path
+}
+
+// path is the location of the configuration file given in the command line.
+var path string
@@ -0,0 +1,152 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that keep the tokens in the keychain of the operating system,
+// when there is one, instead of in the configuration file. The keychain is used through the
+// 'security' command in macOS and the 'secret-tool' command of libsecret in Linux, so no native
+// libraries are needed. Set the 'ROSA_KEYRING' environment variable to 'false' to keep the tokens
+// in the configuration file, for example in machines without a desktop session.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// keyringEnv is the environment variable that disables the keychain.
+const keyringEnv = "ROSA_KEYRING"
+
+// keyringService is the name of the service of the keychain items.
+const keyringService = "rosa"
+
+// keyringTokens is the content of the keychain item of a configuration file.
+type keyringTokens struct {
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// KeyringAvailable checks if the tokens can be kept in the keychain of the operating system.
+func KeyringAvailable() bool {
+	if value := os.Getenv(keyringEnv); value == "false" || value == "0" {
+		return false
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		_, err := exec.LookPath("security")
+		return err == nil
+	case "linux":
+		// The secret service is provided by the desktop session, through D-Bus:
+		if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+			return false
+		}
+		_, err := exec.LookPath("secret-tool")
+		return err == nil
+	default:
+		return false
+	}
+}
+
+// readKeyring returns the tokens stored in the keychain for the configuration file in the given
+// location.
+func readKeyring(location string) (tokens *keyringTokens, err error) {
+	var output []byte
+	switch runtime.GOOS {
+	case "darwin":
+		// #nosec G204
+		output, err = exec.Command("security", "find-generic-password", "-s", keyringService,
+			"-a", location, "-w").Output()
+	case "linux":
+		// #nosec G204
+		output, err = exec.Command("secret-tool", "lookup", "service", keyringService,
+			"account", location).Output()
+	default:
+		err = fmt.Errorf("Keychain isn't supported in %s", runtime.GOOS)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read tokens from keychain: %v", err)
+	}
+	tokens = &keyringTokens{}
+	err = json.Unmarshal(bytes.TrimSpace(output), tokens)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse tokens from keychain: %v", err)
+	}
+	return tokens, nil
+}
+
+// writeKeyring stores the given tokens in the keychain for the configuration file in the given
+// location. The tokens are passed in the standard input, so that they don't appear in the list of
+// processes.
+func writeKeyring(location string, tokens *keyringTokens) error {
+	data, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		// In interactive mode the command reads the subcommand from the standard input. The
+		// JSON document doesn't contain single quotes, as the tokens are base64 encoded:
+		cmd = exec.Command("security", "-i")
+		cmd.Stdin = strings.NewReader(fmt.Sprintf(
+			"add-generic-password -U -s %s -a '%s' -w '%s'\n",
+			keyringService, location, data,
+		))
+	case "linux":
+		// #nosec G204
+		cmd = exec.Command("secret-tool", "store", "--label=rosa tokens", "service",
+			keyringService, "account", location)
+		cmd.Stdin = bytes.NewReader(data)
+	default:
+		return fmt.Errorf("Keychain isn't supported in %s", runtime.GOOS)
+	}
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("Failed to write tokens to keychain: %v: %s", err,
+			strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// deleteKeyring removes the tokens of the configuration file in the given location from the
+// keychain. It isn't an error if there are no tokens.
+func deleteKeyring(location string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		// #nosec G204
+		cmd = exec.Command("security", "delete-generic-password", "-s", keyringService,
+			"-a", location)
+	case "linux":
+		// #nosec G204
+		cmd = exec.Command("secret-tool", "clear", "service", keyringService, "account",
+			location)
+	default:
+		return nil
+	}
+	// The commands fail when the item doesn't exist, so the result is ignored, and the item is
+	// checked instead:
+	_ = cmd.Run()
+	if _, err := readKeyring(location); err == nil {
+		return fmt.Errorf("Failed to remove tokens from keychain")
+	}
+	return nil
+}
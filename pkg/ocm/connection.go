@@ -18,12 +18,19 @@ package ocm
 
 import (
 	"fmt"
+	"net/http"
 
 	sdk "github.com/openshift-online/ocm-sdk-go"
 	"github.com/sirupsen/logrus"
 
+	"github.com/openshift/moactl/pkg/dryrun"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/logging"
 	"github.com/openshift/moactl/pkg/ocm/config"
+	"github.com/openshift/moactl/pkg/retry"
+	"github.com/openshift/moactl/pkg/simulate"
+	"github.com/openshift/moactl/pkg/tracing"
+	"github.com/openshift/moactl/pkg/transport"
 )
 
 // ConnectionBuilder contains the information and logic needed to build a connection to OCM. Don't
@@ -54,6 +61,7 @@ func (b *ConnectionBuilder) Config(value *config.Config) *ConnectionBuilder {
 
 // Build uses the information stored in the builder to create a new OCM connection.
 func (b *ConnectionBuilder) Build() (result *sdk.Connection, err error) {
+	loaded := b.cfg == nil
 	if b.cfg == nil {
 		// Load the configuration file:
 		b.cfg, err = config.Load()
@@ -62,10 +70,14 @@ func (b *ConnectionBuilder) Build() (result *sdk.Connection, err error) {
 			return result, err
 		}
 		if b.cfg == nil {
-			err = fmt.Errorf("Not logged in, run the 'rosa login' command")
+			err = rosaerrors.Authf("Not logged in, run the 'rosa login' command")
 			return result, err
 		}
 	}
+	if b.cfg.KeyringError() != nil {
+		err = rosaerrors.Authf("%v, run the 'rosa login' command", b.cfg.KeyringError())
+		return result, err
+	}
 
 	// Check parameters:
 	if b.logger == nil {
@@ -75,7 +87,7 @@ func (b *ConnectionBuilder) Build() (result *sdk.Connection, err error) {
 
 	// Create the OCM logger that uses the logging framework of the project:
 	logger, err := logging.NewOCMLogger().
-		Logger(b.logger).
+		Logger(logging.Subsystem(b.logger, logging.OCM)).
 		Build()
 	if err != nil {
 		return
@@ -94,8 +106,14 @@ func (b *ConnectionBuilder) Build() (result *sdk.Connection, err error) {
 	if b.cfg.Scopes != nil {
 		builder.Scopes(b.cfg.Scopes...)
 	}
-	if b.cfg.URL != "" {
-		builder.URL(b.cfg.URL)
+	// The environment selected in the command line replaces the one saved by 'rosa login', but not
+	// the one of an explicit configuration, like the one of 'rosa login' itself:
+	url := b.cfg.URL
+	if loaded {
+		url = b.cfg.APIURL()
+	}
+	if url != "" {
+		builder.URL(url)
 	}
 	tokens := make([]string, 0, 2)
 	if b.cfg.AccessToken != "" {
@@ -108,6 +126,18 @@ func (b *ConnectionBuilder) Build() (result *sdk.Connection, err error) {
 		builder.Tokens(tokens...)
 	}
 	builder.Insecure(b.cfg.Insecure)
+	if caFile := transport.CAFile(); caFile != "" {
+		builder.TrustedCAFile(caFile)
+	}
+
+	// Retry the requests that fail with transient errors, warn about deprecated endpoints, record
+	// a span for each request, in case traces are exported, print instead of sending the requests
+	// that change resources, in dry run mode, and answer all of them with canned responses, in
+	// simulation mode:
+	builder.TransportWrapper(func(next http.RoundTripper) http.RoundTripper {
+		return dryrun.NewRoundTripper(tracing.NewRoundTripper("ocm",
+			newDeprecationRoundTripper(retry.NewRoundTripper(simulate.NewRoundTripper(next)))))
+	})
 
 	// Create the connection:
 	result, err = builder.Build()
@@ -115,5 +145,23 @@ func (b *ConnectionBuilder) Build() (result *sdk.Connection, err error) {
 		return
 	}
 
+	// When the access token of the configuration file has expired request a new one with the
+	// refresh token, and save it, so that the next commands can use it directly:
+	if loaded && b.cfg.RefreshToken != "" && !b.cfg.AccessTokenValid() {
+		b.cfg.AccessToken, b.cfg.RefreshToken, err = result.Tokens()
+		if err != nil {
+			_ = result.Close()
+			err = fmt.Errorf("Failed to refresh access token: %v. Run the 'rosa login' command "+
+				"again", err)
+			return nil, err
+		}
+		err = config.Save(b.cfg)
+		if err != nil {
+			// The connection still works, the tokens will be requested again next time:
+			b.logger.Debugf("Failed to save refreshed tokens: %v", err)
+			err = nil
+		}
+	}
+
 	return
 }
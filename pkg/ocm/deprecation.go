@@ -0,0 +1,104 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the round tripper that reports the deprecation headers of the responses of
+// the OCM API, so that users learn about deprecated endpoints before they are removed.
+
+package ocm
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/openshift/moactl/pkg/output"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+)
+
+// deprecationRoundTripper warns once about each deprecated endpoint and each warning header
+// returned by the OCM API.
+type deprecationRoundTripper struct {
+	next     http.RoundTripper
+	reporter *rprtr.Object
+	lock     sync.Mutex
+	reported map[string]bool
+}
+
+// Make sure that we implement the http.RoundTripper interface:
+var _ http.RoundTripper = &deprecationRoundTripper{}
+
+// newDeprecationRoundTripper creates a round tripper that reports the deprecation headers of the
+// responses of the given round tripper.
+func newDeprecationRoundTripper(next http.RoundTripper) *deprecationRoundTripper {
+	return &deprecationRoundTripper{
+		next:     next,
+		reporter: rprtr.CreateReporterOrExit(),
+		reported: map[string]bool{},
+	}
+}
+
+func (t *deprecationRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	response, err := t.next.RoundTrip(request)
+	if err != nil {
+		return response, err
+	}
+	fields := rprtr.Fields{
+		"method": request.Method,
+		"path":   request.URL.Path,
+	}
+	if response.Header.Get("Deprecation") != "" {
+		message := fmt.Sprintf("OCM API endpoint '%s %s' is deprecated", request.Method,
+			request.URL.Path)
+		if sunset := response.Header.Get("Sunset"); sunset != "" {
+			fields["sunset"] = sunset
+			message += fmt.Sprintf(" and will be removed on %s", sunset)
+		}
+		t.report(fields, message)
+	}
+	for _, warning := range response.Header.Values("Warning") {
+		t.report(fields, "OCM API: "+parseWarning(warning))
+	}
+	return response, nil
+}
+
+// report prints the message, unless it has already been printed. Messages aren't printed when
+// the output is JSON or YAML meant for other programs, unless the messages are JSON events too.
+func (t *deprecationRoundTripper) report(fields rprtr.Fields, message string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.reported[message] || (output.Structured() && !rprtr.JSONFormat()) {
+		return
+	}
+	t.reported[message] = true
+	t.reporter.WarnEventf(rprtr.CodeAPIDeprecated, fields, "%s", message)
+}
+
+// parseWarning returns the text of a warning header, like '299 - "Deprecated"', or the complete
+// header if it doesn't have that format.
+func parseWarning(header string) string {
+	parts := strings.SplitN(header, " ", 3)
+	if len(parts) != 3 || parts[0] != "299" {
+		return header
+	}
+	text := strings.TrimSpace(parts[2])
+	if strings.HasPrefix(text, "\"") {
+		if end := strings.Index(text[1:], "\""); end >= 0 {
+			return text[1 : end+1]
+		}
+	}
+	return text
+}
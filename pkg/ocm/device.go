@@ -0,0 +1,154 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that log in with the OAuth device authorization grant, where
+// the user opens a page in a browser, possibly in another device, and types a code to approve the
+// login, so that no token needs to be copied.
+
+package ocm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/openshift/moactl/pkg/transport"
+)
+
+// DeviceClientID is the OpenID client used to log in with a device code, as the default client
+// doesn't support it.
+const DeviceClientID = "ocm-cli"
+
+// DeviceAuthorization is the code that the user needs to approve in the browser.
+type DeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+
+	tokenURL string
+	clientID string
+	client   *http.Client
+}
+
+// deviceResponse is the response of the token endpoint, which contains either the tokens or the
+// error.
+type deviceResponse struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// RequestDeviceAuthorization requests a device code for the given OpenID client. The device
+// authorization endpoint is next to the given token endpoint, as in Red Hat SSO.
+func RequestDeviceAuthorization(tokenURL string, clientID string, scopes []string,
+	insecure bool) (*DeviceAuthorization, error) {
+	if !strings.HasSuffix(tokenURL, "/token") {
+		return nil, fmt.Errorf("Can't find the device authorization endpoint of token URL '%s'",
+			tokenURL)
+	}
+	authURL := strings.TrimSuffix(tokenURL, "/token") + "/auth/device"
+
+	clientTransport, err := transport.New(insecure)
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: clientTransport,
+	}
+
+	response, err := client.PostForm(authURL, url.Values{
+		"client_id": {clientID},
+		"scope":     {strings.Join(scopes, " ")},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		var body deviceResponse
+		err = json.NewDecoder(response.Body).Decode(&body)
+		if err != nil || body.Error == "" {
+			return nil, fmt.Errorf("Unexpected status code %d", response.StatusCode)
+		}
+		return nil, fmt.Errorf("%s: %s", body.Error, body.ErrorDescription)
+	}
+
+	authorization := &DeviceAuthorization{
+		tokenURL: tokenURL,
+		clientID: clientID,
+		client:   client,
+	}
+	err = json.NewDecoder(response.Body).Decode(authorization)
+	if err != nil {
+		return nil, err
+	}
+	if authorization.Interval <= 0 {
+		authorization.Interval = 5
+	}
+	return authorization, nil
+}
+
+// WaitForTokens polls the token endpoint until the user approves or denies the code, or until it
+// expires, and returns the access and refresh tokens.
+func (a *DeviceAuthorization) WaitForTokens() (accessToken string, refreshToken string, err error) {
+	interval := time.Duration(a.Interval) * time.Second
+	deadline := time.Now().Add(time.Duration(a.ExpiresIn) * time.Second)
+	for {
+		time.Sleep(interval)
+		if a.ExpiresIn > 0 && time.Now().After(deadline) {
+			return "", "", fmt.Errorf("The code expired before it was approved")
+		}
+
+		response, err := a.client.PostForm(a.tokenURL, url.Values{
+			"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+			"client_id":   {a.clientID},
+			"device_code": {a.DeviceCode},
+		})
+		if err != nil {
+			return "", "", err
+		}
+		var body deviceResponse
+		err = json.NewDecoder(response.Body).Decode(&body)
+		response.Body.Close()
+		if err != nil {
+			return "", "", fmt.Errorf("Failed to parse token response: %v", err)
+		}
+
+		switch body.Error {
+		case "":
+			return body.AccessToken, body.RefreshToken, nil
+		case "authorization_pending":
+			continue
+		case "slow_down":
+			interval += 5 * time.Second
+			continue
+		case "access_denied":
+			return "", "", fmt.Errorf("The login was denied")
+		case "expired_token":
+			return "", "", fmt.Errorf("The code expired before it was approved")
+		default:
+			return "", "", fmt.Errorf("%s: %s", body.Error, body.ErrorDescription)
+		}
+	}
+}
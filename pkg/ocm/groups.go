@@ -0,0 +1,145 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that manage the membership of users in the groups of a
+// cluster, which give them roles like 'cluster-admin' or 'dedicated-admin'.
+
+package ocm
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// Groups of a cluster that users can be added to:
+const (
+	ClusterAdminsGroup   = "cluster-admins"
+	DedicatedAdminsGroup = "dedicated-admins"
+)
+
+// Groups are the names of the groups that users can be granted, in the order that they are shown.
+var Groups = []string{ClusterAdminsGroup, DedicatedAdminsGroup}
+
+// GroupForRole returns the name of the group that gives the given role. The role can be given as
+// the name of the group, like 'dedicated-admins', or as the name of the role, like
+// 'dedicated-admin'.
+func GroupForRole(role string) (string, error) {
+	for _, group := range Groups {
+		if role == group || role+"s" == group {
+			return group, nil
+		}
+	}
+	return "", fmt.Errorf("Role '%s' isn't valid, expected one of: %s", role, strings.Join(Groups, ", "))
+}
+
+// GetClusterGroups returns the names of the groups that can be used in the given cluster. The
+// 'cluster-admins' group can only be used when cluster admin is enabled in the cluster.
+func GetClusterGroups(cluster *cmv1.Cluster) []string {
+	if cluster.ClusterAdminEnabled() {
+		return Groups
+	}
+	return []string{DedicatedAdminsGroup}
+}
+
+// GetUser returns the user with the given name from the group of the cluster, or nil if the user
+// isn't in the group.
+func GetUser(client *cmv1.ClustersClient, clusterID string, group string, username string) (*cmv1.User, error) {
+	response, err := client.Cluster(clusterID).
+		Groups().Group(group).
+		Users().User(username).
+		Get().Send()
+	if err != nil {
+		if response.Status() == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, handleErr(response.Error(), err)
+	}
+
+	return response.Body(), nil
+}
+
+// GetUsers returns the users of the group of the cluster.
+func GetUsers(client *cmv1.ClustersClient, clusterID string, group string) ([]*cmv1.User, error) {
+	usersClient := client.Cluster(clusterID).Groups().Group(group).Users()
+	response, err := usersClient.List().
+		Page(1).
+		Size(-1).
+		Send()
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+
+	return response.Items().Slice(), nil
+}
+
+// GetGroupMembers returns the groups of the given cluster that each user is a member of, and the
+// names of the users sorted alphabetically.
+func GetGroupMembers(client *cmv1.ClustersClient, cluster *cmv1.Cluster) ([]string, map[string][]string,
+	error) {
+	members := map[string][]string{}
+	for _, group := range GetClusterGroups(cluster) {
+		users, err := GetUsers(client, cluster.ID(), group)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed to get %s: %v", group, err)
+		}
+		for _, user := range users {
+			members[user.ID()] = append(members[user.ID()], group)
+		}
+	}
+	usernames := make([]string, 0, len(members))
+	for username := range members {
+		usernames = append(usernames, username)
+	}
+	sort.Strings(usernames)
+	return usernames, members, nil
+}
+
+// AddGroupUser adds the user with the given name to the group of the cluster.
+func AddGroupUser(client *cmv1.ClustersClient, clusterID string, group string, username string) error {
+	user, err := cmv1.NewUser().ID(username).Build()
+	if err != nil {
+		return err
+	}
+	response, err := client.Cluster(clusterID).
+		Groups().Group(group).
+		Users().Add().Body(user).
+		Send()
+	if err != nil {
+		return handleErr(response.Error(), err)
+	}
+	return nil
+}
+
+// DeleteGroupUser removes the user with the given name from the group of the cluster. It isn't an
+// error if the user isn't in the group.
+func DeleteGroupUser(client *cmv1.ClustersClient, clusterID string, group string, username string) error {
+	response, err := client.Cluster(clusterID).
+		Groups().Group(group).
+		Users().User(username).
+		Delete().
+		Send()
+	if err != nil {
+		if response.Status() == http.StatusNotFound {
+			return nil
+		}
+		return handleErr(response.Error(), err)
+	}
+	return nil
+}
@@ -17,7 +17,8 @@ limitations under the License.
 package ocm
 
 import (
-	"errors"
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
@@ -28,7 +29,9 @@ import (
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
 
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/ocm/properties"
+	"github.com/openshift/moactl/pkg/ocm/raw"
 )
 
 // Regular expression to used to make sure that the identifier or name given by the user is
@@ -74,7 +77,7 @@ func GetCluster(client *cmv1.ClustersClient, clusterKey string, creatorARN strin
 
 	switch response.Total() {
 	case 0:
-		return nil, fmt.Errorf("There is no cluster with identifier or name '%s'", clusterKey)
+		return nil, rosaerrors.NotFoundf("There is no cluster with identifier or name '%s'", clusterKey)
 	case 1:
 		return response.Items().Slice()[0], nil
 	default:
@@ -82,6 +85,24 @@ func GetCluster(client *cmv1.ClustersClient, clusterKey string, creatorARN strin
 	}
 }
 
+// UpdateCluster applies the given changes to the cluster with the given identifier.
+func UpdateCluster(client *cmv1.ClustersClient, clusterID string, spec *cmv1.Cluster) error {
+	response, err := client.Cluster(clusterID).Update().Body(spec).Send()
+	if err != nil {
+		return handleErr(response.Error(), err)
+	}
+	return nil
+}
+
+// DeleteCluster starts the uninstallation of the cluster with the given identifier.
+func DeleteCluster(client *cmv1.ClustersClient, clusterID string) error {
+	response, err := client.Cluster(clusterID).Delete().Send()
+	if err != nil {
+		return handleErr(response.Error(), err)
+	}
+	return nil
+}
+
 func GetIdentityProviders(client *cmv1.ClustersClient, clusterID string) ([]*cmv1.IdentityProvider, error) {
 	idpClient := client.Cluster(clusterID).IdentityProviders()
 	response, err := idpClient.List().
@@ -95,6 +116,32 @@ func GetIdentityProviders(client *cmv1.ClustersClient, clusterID string) ([]*cmv
 	return response.Items().Slice(), nil
 }
 
+// AddIdentityProvider adds the given identity provider to the cluster.
+func AddIdentityProvider(client *cmv1.ClustersClient, clusterID string, idp *cmv1.IdentityProvider) error {
+	response, err := client.Cluster(clusterID).
+		IdentityProviders().
+		Add().
+		Body(idp).
+		Send()
+	if err != nil {
+		return handleErr(response.Error(), err)
+	}
+	return nil
+}
+
+// DeleteIdentityProvider removes the identity provider with the given identifier from the cluster.
+func DeleteIdentityProvider(client *cmv1.ClustersClient, clusterID string, idpID string) error {
+	response, err := client.Cluster(clusterID).
+		IdentityProviders().
+		IdentityProvider(idpID).
+		Delete().
+		Send()
+	if err != nil {
+		return handleErr(response.Error(), err)
+	}
+	return nil
+}
+
 func IdentityProviderType(idp *cmv1.IdentityProvider) string {
 	switch idp.Type() {
 	case "GithubIdentityProvider":
@@ -127,32 +174,32 @@ func GetIngresses(client *cmv1.ClustersClient, clusterID string) ([]*cmv1.Ingres
 	return response.Items().Slice(), nil
 }
 
-func GetUser(client *cmv1.ClustersClient, clusterID string, group string, username string) (*cmv1.User, error) {
-	response, err := client.Cluster(clusterID).
-		Groups().Group(group).
-		Users().User(username).
-		Get().Send()
+// AddIngress adds the given ingress to the cluster.
+func AddIngress(client *cmv1.ClustersClient, clusterID string, ingress *cmv1.Ingress) error {
+	response, err := client.Cluster(clusterID).Ingresses().Add().Body(ingress).Send()
 	if err != nil {
-		if response.Status() == http.StatusNotFound {
-			return nil, nil
-		}
-		return nil, handleErr(response.Error(), err)
+		return handleErr(response.Error(), err)
 	}
-
-	return response.Body(), nil
+	return nil
 }
 
-func GetUsers(client *cmv1.ClustersClient, clusterID string, group string) ([]*cmv1.User, error) {
-	usersClient := client.Cluster(clusterID).Groups().Group(group).Users()
-	response, err := usersClient.List().
-		Page(1).
-		Size(-1).
-		Send()
+// UpdateIngress applies the given changes to the ingress of the cluster with the given identifier.
+func UpdateIngress(client *cmv1.ClustersClient, clusterID string, ingressID string,
+	ingress *cmv1.Ingress) error {
+	response, err := client.Cluster(clusterID).Ingresses().Ingress(ingressID).Update().Body(ingress).Send()
 	if err != nil {
-		return nil, handleErr(response.Error(), err)
+		return handleErr(response.Error(), err)
 	}
+	return nil
+}
 
-	return response.Items().Slice(), nil
+// DeleteIngress removes the ingress with the given identifier from the cluster.
+func DeleteIngress(client *cmv1.ClustersClient, clusterID string, ingressID string) error {
+	response, err := client.Cluster(clusterID).Ingresses().Ingress(ingressID).Delete().Send()
+	if err != nil {
+		return handleErr(response.Error(), err)
+	}
+	return nil
 }
 
 func GetAddOn(client *cmv1.AddOnsClient, id string) (*cmv1.AddOn, error) {
@@ -164,10 +211,10 @@ func GetAddOn(client *cmv1.AddOnsClient, id string) (*cmv1.AddOn, error) {
 }
 
 type ClusterAddOn struct {
-	ID        string
-	Name      string
-	State     string
-	Available bool
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	State     string `json:"state"`
+	Available bool   `json:"available"`
 }
 
 // Get all add-ons available for a cluster
@@ -268,6 +315,172 @@ func GetClusterState(client *cmv1.ClustersClient, clusterID string) (cmv1.Cluste
 	return response.Body().State(), nil
 }
 
+// GetInfraID returns the infrastructure identifier of the cluster, which is the prefix of the
+// names and tags of the AWS resources of the cluster. It is empty until the installation starts.
+func GetInfraID(connection *sdk.Connection, clusterID string) (string, error) {
+	response, err := raw.Send(
+		connection.Get().
+			Path("/api/clusters_mgmt/v1/clusters/"+clusterID),
+		http.StatusOK,
+	)
+	if err != nil {
+		return "", err
+	}
+	var body struct {
+		InfraID string `json:"infra_id"`
+	}
+	err = json.Unmarshal(response.Bytes(), &body)
+	if err != nil {
+		return "", err
+	}
+	return body.InfraID, nil
+}
+
+// GetPrivateLink returns true if the API of the cluster with the given identifier is only reachable
+// over AWS PrivateLink.
+func GetPrivateLink(connection *sdk.Connection, clusterID string) (bool, error) {
+	response, err := raw.Send(
+		connection.Get().
+			Path("/api/clusters_mgmt/v1/clusters/"+clusterID),
+		http.StatusOK,
+	)
+	if err != nil {
+		return false, err
+	}
+	var body struct {
+		AWS struct {
+			PrivateLink bool `json:"private_link"`
+		} `json:"aws"`
+	}
+	err = json.Unmarshal(response.Bytes(), &body)
+	if err != nil {
+		return false, err
+	}
+	return body.AWS.PrivateLink, nil
+}
+
+// GetClusterDocument returns the JSON document of the cluster with the given identifier, as returned
+// by the API. It contains all the fields of the cluster, including those that the typed clients of
+// the SDK don't support yet.
+func GetClusterDocument(connection *sdk.Connection, clusterID string) ([]byte, error) {
+	response, err := raw.Send(
+		connection.Get().
+			Path("/api/clusters_mgmt/v1/clusters/"+clusterID),
+		http.StatusOK,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return response.Bytes(), nil
+}
+
+// AddCluster sends the request to create the given cluster, adding the given fields to the objects
+// of the description of the cluster with the given names, like the tags to the AWS details of the
+// cluster. Details that aren't objects, like the FIPS flag, are set as top level fields of the
+// description. The typed client of the SDK can't send those fields, so when there are details the
+// description of the cluster is converted to JSON and sent with the raw API. In dry run mode OCM
+// only validates the description and nothing is returned.
+func AddCluster(connection *sdk.Connection, spec *cmv1.Cluster, details map[string]interface{},
+	dryRun bool) (*cmv1.Cluster, error) {
+	if len(details) == 0 {
+		response, err := connection.ClustersMgmt().V1().Clusters().Add().
+			Parameter("dryRun", dryRun).
+			Body(spec).
+			Send()
+		if err != nil {
+			return nil, handleErr(response.Error(), err)
+		}
+		if dryRun {
+			return nil, nil
+		}
+		return response.Body(), nil
+	}
+
+	var buffer bytes.Buffer
+	err := cmv1.MarshalCluster(spec, &buffer)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal description of cluster: %v", err)
+	}
+	var body map[string]interface{}
+	err = json.Unmarshal(buffer.Bytes(), &body)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal description of cluster: %v", err)
+	}
+	for name, value := range details {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			body[name] = value
+			continue
+		}
+		object, ok := body[name].(map[string]interface{})
+		if !ok {
+			object = map[string]interface{}{}
+			body[name] = object
+		}
+		for key, field := range fields {
+			object[key] = field
+		}
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal description of cluster: %v", err)
+	}
+
+	response, err := raw.Send(
+		connection.Post().
+			Path("/api/clusters_mgmt/v1/clusters").
+			Parameter("dryRun", dryRun).
+			Bytes(data),
+		http.StatusCreated, http.StatusNoContent,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return nil, nil
+	}
+	return cmv1.UnmarshalCluster(response.Bytes())
+}
+
+// PatchCluster changes the given fields of the JSON document of the cluster with the given
+// identifier, for the fields that the typed client of the SDK can't send.
+func PatchCluster(connection *sdk.Connection, clusterID string, fields map[string]interface{}) error {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	_, err = raw.Send(
+		connection.Patch().
+			Path("/api/clusters_mgmt/v1/clusters/"+clusterID).
+			Bytes(data),
+		http.StatusOK, http.StatusNoContent,
+	)
+	return err
+}
+
+// GetKMSKeyARN returns the ARN of the customer managed KMS key that encrypts the volumes of the
+// cluster with the given identifier, or an empty string if the default key is used.
+func GetKMSKeyARN(connection *sdk.Connection, clusterID string) (string, error) {
+	response, err := raw.Send(
+		connection.Get().
+			Path("/api/clusters_mgmt/v1/clusters/"+clusterID),
+		http.StatusOK,
+	)
+	if err != nil {
+		return "", err
+	}
+	var body struct {
+		AWS struct {
+			KMSKeyARN string `json:"kms_key_arn"`
+		} `json:"aws"`
+	}
+	err = json.Unmarshal(response.Bytes(), &body)
+	if err != nil {
+		return "", err
+	}
+	return body.AWS.KMSKeyARN, nil
+}
+
 func GetMachinePools(client *cmv1.ClustersClient, clusterID string) ([]*cmv1.MachinePool, error) {
 	response, err := client.Cluster(clusterID).MachinePools().
 		List().
@@ -281,12 +494,43 @@ func GetMachinePools(client *cmv1.ClustersClient, clusterID string) ([]*cmv1.Mac
 	return response.Items().Slice(), nil
 }
 
-func handleErr(res *ocmerrors.Error, err error) error {
-	msg := res.Reason()
-	if msg == "" {
-		msg = err.Error()
+// UpdateMachinePool applies the given changes to the machine pool of the cluster with the given
+// identifier.
+func UpdateMachinePool(client *cmv1.ClustersClient, clusterID string, machinePoolID string,
+	machinePool *cmv1.MachinePool) error {
+	response, err := client.Cluster(clusterID).
+		MachinePools().
+		MachinePool(machinePoolID).
+		Update().
+		Body(machinePool).
+		Send()
+	if err != nil {
+		return handleErr(response.Error(), err)
+	}
+	return nil
+}
+
+// DeleteMachinePool removes the machine pool with the given identifier from the cluster.
+func DeleteMachinePool(client *cmv1.ClustersClient, clusterID string, machinePoolID string) error {
+	response, err := client.Cluster(clusterID).MachinePools().MachinePool(machinePoolID).Delete().Send()
+	if err != nil {
+		return handleErr(response.Error(), err)
 	}
-	return errors.New(msg)
+	return nil
+}
+
+// GetClusterStatus returns the status of the cluster with the given identifier, which contains a
+// description of the state that is more detailed than the state of the cluster.
+func GetClusterStatus(client *cmv1.ClustersClient, clusterID string) (*cmv1.ClusterStatus, error) {
+	response, err := client.Cluster(clusterID).Status().Get().Send()
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Body(), nil
+}
+
+func handleErr(res *ocmerrors.Error, err error) error {
+	return rosaerrors.FromOCM(res, err)
 }
 
 func GetDefaultClusterFlavors(ocmClient *cmv1.Client) (dMachinecidr *net.IPNet, dPodcidr *net.IPNet,
@@ -311,3 +555,41 @@ func GetDefaultClusterFlavors(ocmClient *cmv1.Client) (dMachinecidr *net.IPNet,
 	dhostPrefix, _ = network.GetHostPrefix()
 	return dMachinecidr, dPodcidr, dServicecidr, dhostPrefix
 }
+
+// GetCurrentAccount returns the account of the current user, or nil if the user doesn't have an
+// account, like service accounts.
+func GetCurrentAccount(connection *sdk.Connection) (*amsv1.Account, error) {
+	response, err := connection.AccountsMgmt().V1().CurrentAccount().Get().Send()
+	if response != nil && response.Status() == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Body(), nil
+}
+
+// GetAvailableRegions returns the AWS regions where clusters can be created with the given AWS
+// credentials.
+func GetAvailableRegions(client *cmv1.Client, credentials *cmv1.AWS) ([]*cmv1.CloudRegion, error) {
+	collection := client.CloudProviders().CloudProvider("aws").AvailableRegions()
+	regions := []*cmv1.CloudRegion{}
+	page := 1
+	size := 100
+	for {
+		response, err := collection.Search().
+			Page(page).
+			Size(size).
+			Body(credentials).
+			Send()
+		if err != nil {
+			return nil, handleErr(response.Error(), err)
+		}
+		regions = append(regions, response.Items().Slice()...)
+		if response.Size() < size {
+			break
+		}
+		page++
+	}
+	return regions, nil
+}
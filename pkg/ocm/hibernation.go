@@ -0,0 +1,139 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that hibernate and resume clusters. Hibernated clusters stop
+// their nodes, so that they don't cost anything but their storage until they are resumed.
+
+package ocm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/ci"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm/raw"
+)
+
+// States of clusters that are hibernating, or on their way to or from hibernation:
+const (
+	ClusterStatePoweringDown cmv1.ClusterState = "powering_down"
+	ClusterStateHibernating  cmv1.ClusterState = "hibernating"
+	ClusterStateResuming     cmv1.ClusterState = "resuming"
+)
+
+// ErrHibernationNotSupported is returned by HibernateCluster and ResumeCluster when the clusters
+// management service doesn't support hibernating the cluster.
+var ErrHibernationNotSupported = errors.New("Hibernation isn't supported for this cluster")
+
+// ValidateHibernate checks that a cluster in the given state can be hibernated. Only ready clusters
+// can, as the installation or uninstallation of the others would be interrupted.
+func ValidateHibernate(state cmv1.ClusterState) error {
+	switch state {
+	case cmv1.ClusterStateReady:
+		return nil
+	case ClusterStatePoweringDown, ClusterStateHibernating:
+		return fmt.Errorf("it is already hibernating")
+	default:
+		return fmt.Errorf("only ready clusters can be hibernated, and it is %s", state)
+	}
+}
+
+// ValidateResume checks that a cluster in the given state can be resumed.
+func ValidateResume(state cmv1.ClusterState) error {
+	switch state {
+	case ClusterStateHibernating:
+		return nil
+	case ClusterStateResuming:
+		return fmt.Errorf("it is already resuming")
+	case ClusterStatePoweringDown:
+		return fmt.Errorf("it is still powering down, wait until it is hibernating")
+	default:
+		return fmt.Errorf("only hibernating clusters can be resumed, and it is %s", state)
+	}
+}
+
+// HibernateCluster requests the hibernation of the cluster with the given identifier.
+func HibernateCluster(connection *sdk.Connection, clusterID string) error {
+	return postClusterAction(connection, clusterID, "hibernate")
+}
+
+// ResumeCluster requests the cluster with the given identifier to resume from hibernation.
+func ResumeCluster(connection *sdk.Connection, clusterID string) error {
+	return postClusterAction(connection, clusterID, "resume")
+}
+
+func postClusterAction(connection *sdk.Connection, clusterID string, action string) error {
+	response, err := connection.Post().
+		Path(fmt.Sprintf("/api/clusters_mgmt/v1/clusters/%s/%s", clusterID, action)).
+		Bytes([]byte("{}")).
+		Send()
+	if err != nil {
+		return rosaerrors.FromStatus(0, err)
+	}
+	switch response.Status() {
+	case http.StatusOK, http.StatusAccepted, http.StatusNoContent:
+		return nil
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return ErrHibernationNotSupported
+	}
+	return raw.Error(response)
+}
+
+// WaitForState polls the state of the cluster with the given identifier with the given interval
+// until it is the given one, and calls the given function with the state of each poll. It returns
+// an error if the cluster gets into error state or if the timeout expires, and ErrWatchInterrupted
+// when the user presses Ctrl-C.
+func WaitForState(client *cmv1.ClustersClient, clusterID string, target cmv1.ClusterState,
+	interval time.Duration, timeout time.Duration, fn func(state cmv1.ClusterState)) error {
+	ctx, cancel := context.WithTimeout(context.Background(), ci.Bound(timeout))
+	defer cancel()
+
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupts)
+
+	for {
+		state, err := GetClusterState(client, clusterID)
+		if err != nil {
+			return err
+		}
+		fn(state)
+		if state == target {
+			return nil
+		}
+		if state == cmv1.ClusterStateError {
+			return fmt.Errorf("Cluster is in error state")
+		}
+
+		select {
+		case <-interrupts:
+			return ErrWatchInterrupted
+		case <-ctx.Done():
+			return rosaerrors.Transientf("Timed out waiting for the cluster to be %s", target)
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions that manage the users of htpasswd identity providers, which can
+// have multiple users.
+
+package htpasswd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+
+	"github.com/openshift/moactl/pkg/ocm/raw"
+)
+
+// User is a user of an htpasswd identity provider. Only one of the password and the hashed
+// password should be set.
+type User struct {
+	ID             string `json:"id,omitempty"`
+	Username       string `json:"username,omitempty"`
+	Password       string `json:"password,omitempty"`
+	HashedPassword string `json:"hashed_password,omitempty"`
+}
+
+func idpsPath(clusterID string) string {
+	return fmt.Sprintf("/api/clusters_mgmt/v1/clusters/%s/identity_providers", clusterID)
+}
+
+func usersPath(clusterID string, idpID string) string {
+	return fmt.Sprintf("%s/%s/htpasswd_users", idpsPath(clusterID), idpID)
+}
+
+// ParseFile parses the content of a file in the format generated by the 'htpasswd' tool. Only
+// bcrypt hashes, generated with 'htpasswd -B', are supported.
+func ParseFile(data []byte) ([]*User, error) {
+	users := []*User{}
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		parts := strings.SplitN(text, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("Line %d isn't valid: expected 'username:hash'", line)
+		}
+		username, hash := parts[0], parts[1]
+		if !strings.HasPrefix(hash, "$2") {
+			return nil, fmt.Errorf("Password of user '%s' in line %d isn't a bcrypt hash: "+
+				"generate the file with 'htpasswd -B'", username, line)
+		}
+		if seen[username] {
+			return nil, fmt.Errorf("User '%s' in line %d is duplicated", username, line)
+		}
+		seen[username] = true
+		users = append(users, &User{
+			Username:       username,
+			HashedPassword: hash,
+		})
+	}
+	err := scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, fmt.Errorf("There are no users in the file")
+	}
+	return users, nil
+}
+
+// CreateIdentityProvider adds an htpasswd identity provider with the given users to the cluster.
+func CreateIdentityProvider(connection *sdk.Connection, clusterID string, name string, mappingMethod string,
+	users []*User) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"type":           "HTPasswdIdentityProvider",
+		"name":           name,
+		"mapping_method": mappingMethod,
+		"htpasswd": map[string]interface{}{
+			"users": map[string]interface{}{
+				"items": users,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = raw.Send(
+		connection.Post().
+			Path(idpsPath(clusterID)).
+			Bytes(body),
+		http.StatusCreated, http.StatusOK,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetUsers returns the users of the htpasswd identity provider.
+func GetUsers(connection *sdk.Connection, clusterID string, idpID string) ([]*User, error) {
+	response, err := raw.Send(
+		connection.Get().
+			Path(usersPath(clusterID, idpID)).
+			Parameter("size", -1),
+		http.StatusOK,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Items []*User `json:"items"`
+	}
+	err = json.Unmarshal(response.Bytes(), &list)
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// AddUser adds a user to the htpasswd identity provider.
+func AddUser(connection *sdk.Connection, clusterID string, idpID string, user *User) error {
+	body, err := json.Marshal(user)
+	if err != nil {
+		return err
+	}
+	_, err = raw.Send(
+		connection.Post().
+			Path(usersPath(clusterID, idpID)).
+			Bytes(body),
+		http.StatusCreated, http.StatusOK,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdatePassword changes the password of a user of the htpasswd identity provider.
+func UpdatePassword(connection *sdk.Connection, clusterID string, idpID string, userID string,
+	password string) error {
+	body, err := json.Marshal(&User{Password: password})
+	if err != nil {
+		return err
+	}
+	_, err = raw.Send(
+		connection.Patch().
+			Path(usersPath(clusterID, idpID)+"/"+userID).
+			Bytes(body),
+		http.StatusOK,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteUser removes a user from the htpasswd identity provider.
+func DeleteUser(connection *sdk.Connection, clusterID string, idpID string, userID string) error {
+	_, err := raw.Send(
+		connection.Delete().
+			Path(usersPath(clusterID, idpID)+"/"+userID),
+		http.StatusNoContent, http.StatusOK,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,355 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that validate the settings of identity providers and build the
+// objects used to create them.
+
+package ocm
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// IdentityProviderMappingMethods are the supported ways of mapping new identities to users when
+// they log in.
+var IdentityProviderMappingMethods = []string{"add", "claim", "generate", "lookup"}
+
+// LDAPIdentityProviderSpec contains the settings of an LDAP identity provider.
+type LDAPIdentityProviderSpec struct {
+	Name          string
+	MappingMethod string
+
+	URL          string
+	Insecure     bool
+	CA           string
+	BindDN       string
+	BindPassword string
+
+	IDAttributes       []string
+	UsernameAttributes []string
+	NameAttributes     []string
+	EmailAttributes    []string
+}
+
+// OpenIDIdentityProviderSpec contains the settings of an OpenID identity provider.
+type OpenIDIdentityProviderSpec struct {
+	Name          string
+	MappingMethod string
+
+	ClientID     string
+	ClientSecret string
+	IssuerURL    string
+	CA           string
+
+	EmailClaims    []string
+	NameClaims     []string
+	UsernameClaims []string
+	ExtraScopes    []string
+}
+
+// GoogleIdentityProviderSpec contains the settings of a Google identity provider.
+type GoogleIdentityProviderSpec struct {
+	Name          string
+	MappingMethod string
+
+	ClientID     string
+	ClientSecret string
+	HostedDomain string
+}
+
+// GitLabIdentityProviderSpec contains the settings of a GitLab identity provider.
+type GitLabIdentityProviderSpec struct {
+	Name          string
+	MappingMethod string
+
+	ClientID     string
+	ClientSecret string
+	URL          string
+	CA           string
+}
+
+// BuildLDAPIdentityProvider validates the settings of an LDAP identity provider and returns the
+// builder of the provider.
+func BuildLDAPIdentityProvider(spec *LDAPIdentityProviderSpec) (*cmv1.IdentityProviderBuilder, error) {
+	err := ValidateMappingMethod(spec.MappingMethod)
+	if err != nil {
+		return nil, err
+	}
+	parsedURL, err := url.ParseRequestURI(spec.URL)
+	if err != nil {
+		return nil, fmt.Errorf("Expected a valid LDAP URL: %v", err)
+	}
+	if parsedURL.Scheme != "ldap" && parsedURL.Scheme != "ldaps" {
+		return nil, errors.New("Expected LDAP URL to have an ldap:// or ldaps:// scheme")
+	}
+	if spec.Insecure && parsedURL.Scheme == "ldaps" {
+		return nil, errors.New("Cannot use insecure connection on ldaps URLs")
+	}
+	if spec.Insecure && spec.CA != "" {
+		return nil, errors.New("Cannot use certificate bundle with an insecure connection")
+	}
+	if spec.BindPassword != "" && spec.BindDN == "" {
+		return nil, errors.New("Bind password requires a bind DN")
+	}
+	if len(spec.IDAttributes) == 0 {
+		return nil, errors.New("At least one ID attribute is required")
+	}
+
+	attributes := cmv1.NewLDAPAttributes().
+		ID(spec.IDAttributes...)
+	if len(spec.UsernameAttributes) > 0 {
+		attributes = attributes.PreferredUsername(spec.UsernameAttributes...)
+	}
+	if len(spec.NameAttributes) > 0 {
+		attributes = attributes.Name(spec.NameAttributes...)
+	}
+	if len(spec.EmailAttributes) > 0 {
+		attributes = attributes.Email(spec.EmailAttributes...)
+	}
+
+	ldapIDP := cmv1.NewLDAPIdentityProvider().
+		URL(spec.URL).
+		Insecure(spec.Insecure).
+		Attributes(attributes)
+	if spec.BindDN != "" {
+		ldapIDP = ldapIDP.BindDN(spec.BindDN)
+		if spec.BindPassword != "" {
+			ldapIDP = ldapIDP.BindPassword(spec.BindPassword)
+		}
+	}
+	if spec.CA != "" {
+		ldapIDP = ldapIDP.CA(spec.CA)
+	}
+
+	return cmv1.NewIdentityProvider().
+		Type("LDAPIdentityProvider"). // FIXME: ocm-api-model has the wrong enum values
+		Name(spec.Name).
+		MappingMethod(cmv1.IdentityProviderMappingMethod(spec.MappingMethod)).
+		LDAP(ldapIDP), nil
+}
+
+// BuildOpenIDIdentityProvider validates the settings of an OpenID identity provider and returns
+// the builder of the provider.
+func BuildOpenIDIdentityProvider(spec *OpenIDIdentityProviderSpec) (*cmv1.IdentityProviderBuilder, error) {
+	err := ValidateMappingMethod(spec.MappingMethod)
+	if err != nil {
+		return nil, err
+	}
+	err = validateClient(spec.ClientID, spec.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	err = ValidateHTTPSURL("OpenID issuer", spec.IssuerURL)
+	if err != nil {
+		return nil, err
+	}
+	if len(spec.EmailClaims) == 0 && len(spec.NameClaims) == 0 && len(spec.UsernameClaims) == 0 {
+		return nil, errors.New("At least one claim is required: [email-claims name-claims username-claims]")
+	}
+
+	claims := cmv1.NewOpenIDClaims()
+	if len(spec.EmailClaims) > 0 {
+		claims = claims.Email(spec.EmailClaims...)
+	}
+	if len(spec.NameClaims) > 0 {
+		claims = claims.Name(spec.NameClaims...)
+	}
+	if len(spec.UsernameClaims) > 0 {
+		claims = claims.PreferredUsername(spec.UsernameClaims...)
+	}
+
+	openIDIDP := cmv1.NewOpenIDIdentityProvider().
+		ClientID(spec.ClientID).
+		ClientSecret(spec.ClientSecret).
+		Issuer(spec.IssuerURL).
+		Claims(claims)
+	if len(spec.ExtraScopes) > 0 {
+		openIDIDP = openIDIDP.ExtraScopes(spec.ExtraScopes...)
+	}
+	if spec.CA != "" {
+		openIDIDP = openIDIDP.CA(spec.CA)
+	}
+
+	return cmv1.NewIdentityProvider().
+		Type("OpenIDIdentityProvider"). // FIXME: ocm-api-model has the wrong enum values
+		Name(spec.Name).
+		MappingMethod(cmv1.IdentityProviderMappingMethod(spec.MappingMethod)).
+		OpenID(openIDIDP), nil
+}
+
+var hostedDomainRE = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+// BuildGoogleIdentityProvider validates the settings of a Google identity provider and returns
+// the builder of the provider. The hosted domain is required unless users are mapped with the
+// 'lookup' method, as otherwise any Google account could log in.
+func BuildGoogleIdentityProvider(spec *GoogleIdentityProviderSpec) (*cmv1.IdentityProviderBuilder, error) {
+	err := ValidateMappingMethod(spec.MappingMethod)
+	if err != nil {
+		return nil, err
+	}
+	err = validateClient(spec.ClientID, spec.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	if spec.HostedDomain == "" && spec.MappingMethod != "lookup" {
+		return nil, errors.New("Hosted domain is required unless the mapping method is 'lookup'")
+	}
+	if spec.HostedDomain != "" && !hostedDomainRE.MatchString(strings.ToLower(spec.HostedDomain)) {
+		return nil, fmt.Errorf("Expected a valid Hosted Domain: '%s' isn't a domain name", spec.HostedDomain)
+	}
+
+	googleIDP := cmv1.NewGoogleIdentityProvider().
+		ClientID(spec.ClientID).
+		ClientSecret(spec.ClientSecret)
+	if spec.HostedDomain != "" {
+		googleIDP = googleIDP.HostedDomain(spec.HostedDomain)
+	}
+
+	return cmv1.NewIdentityProvider().
+		Type("GoogleIdentityProvider"). // FIXME: ocm-api-model has the wrong enum values
+		Name(spec.Name).
+		MappingMethod(cmv1.IdentityProviderMappingMethod(spec.MappingMethod)).
+		Google(googleIDP), nil
+}
+
+// BuildGitLabIdentityProvider validates the settings of a GitLab identity provider and returns
+// the builder of the provider.
+func BuildGitLabIdentityProvider(spec *GitLabIdentityProviderSpec) (*cmv1.IdentityProviderBuilder, error) {
+	err := ValidateMappingMethod(spec.MappingMethod)
+	if err != nil {
+		return nil, err
+	}
+	err = validateClient(spec.ClientID, spec.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	err = ValidateHTTPSURL("GitLab provider", spec.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	gitlabIDP := cmv1.NewGitlabIdentityProvider().
+		ClientID(spec.ClientID).
+		ClientSecret(spec.ClientSecret).
+		URL(spec.URL)
+	if spec.CA != "" {
+		gitlabIDP = gitlabIDP.CA(spec.CA)
+	}
+
+	return cmv1.NewIdentityProvider().
+		Type("GitlabIdentityProvider"). // FIXME: ocm-api-model has the wrong enum values
+		Name(spec.Name).
+		MappingMethod(cmv1.IdentityProviderMappingMethod(spec.MappingMethod)).
+		Gitlab(gitlabIDP), nil
+}
+
+// HTPasswdIdentityProviderSpec contains the settings of an htpasswd identity provider with a
+// single user.
+type HTPasswdIdentityProviderSpec struct {
+	Name          string
+	MappingMethod string
+
+	Username string
+	Password string
+}
+
+// Minimum length of the passwords of htpasswd users:
+const minHTPasswdPasswordLength = 14
+
+// BuildHTPasswdIdentityProvider validates the settings of an htpasswd identity provider and
+// returns the builder of the provider. Providers with more than one user are created with the
+// functions of the htpasswd package instead.
+func BuildHTPasswdIdentityProvider(spec *HTPasswdIdentityProviderSpec) (*cmv1.IdentityProviderBuilder, error) {
+	err := ValidateMappingMethod(spec.MappingMethod)
+	if err != nil {
+		return nil, err
+	}
+	if spec.Username == "" || !IsValidUsername(spec.Username) {
+		return nil, fmt.Errorf("Expected a valid username: '%s' isn't valid", spec.Username)
+	}
+	if len(spec.Password) < minHTPasswdPasswordLength {
+		return nil, fmt.Errorf("Password of user '%s' must be at least %d characters long",
+			spec.Username, minHTPasswdPasswordLength)
+	}
+
+	return cmv1.NewIdentityProvider().
+		Type("HTPasswdIdentityProvider"). // FIXME: ocm-api-model has the wrong enum values
+		Name(spec.Name).
+		MappingMethod(cmv1.IdentityProviderMappingMethod(spec.MappingMethod)).
+		Htpasswd(
+			cmv1.NewHTPasswdIdentityProvider().
+				Username(spec.Username).
+				Password(spec.Password),
+		), nil
+}
+
+// ValidateMappingMethod checks that the given mapping method is one of the supported ones.
+func ValidateMappingMethod(mappingMethod string) error {
+	for _, method := range IdentityProviderMappingMethods {
+		if mappingMethod == method {
+			return nil
+		}
+	}
+	return fmt.Errorf("Unsupported mapping method '%s'. Options are %s", mappingMethod, IdentityProviderMappingMethods)
+}
+
+// ValidateHTTPSURL checks that the given URL uses the https scheme and has no query parameters or
+// fragment, as required for the URLs of the servers of identity providers. The description is
+// used in the error messages.
+func ValidateHTTPSURL(description string, value string) error {
+	parsed, err := url.ParseRequestURI(value)
+	if err != nil {
+		return fmt.Errorf("Expected a valid %s URL: %v", description, err)
+	}
+	if parsed.Scheme != "https" {
+		return fmt.Errorf("Expected %s URL to use an https:// scheme", description)
+	}
+	if parsed.RawQuery != "" {
+		return fmt.Errorf("%s URL must not have query parameters", description)
+	}
+	if parsed.Fragment != "" {
+		return fmt.Errorf("%s URL must not have a fragment", description)
+	}
+	return nil
+}
+
+// SplitList splits a comma separated list of values, ignoring spaces and empty values.
+func SplitList(list string) []string {
+	result := []string{}
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func validateClient(clientID string, clientSecret string) error {
+	if clientID == "" {
+		return errors.New("Client ID is required")
+	}
+	if clientSecret == "" {
+		return errors.New("Client secret is required")
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to export the configuration of identity providers, so
+// that it can be used to create the same identity providers in other clusters.
+
+package ocm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"gopkg.in/yaml.v2"
+)
+
+// IdentityProviderSecrets contains, for each type of provider, the field that contains its
+// secret. Secrets are never exported.
+var IdentityProviderSecrets = map[string]string{
+	"github":   "client_secret",
+	"gitlab":   "client_secret",
+	"google":   "client_secret",
+	"htpasswd": "password",
+	"ldap":     "bind_password",
+	"open_id":  "client_secret",
+}
+
+// Fields that identify a provider within a cluster, and therefore aren't exported:
+var identityProviderLocalFields = []string{"kind", "id", "href"}
+
+// ExportIdentityProvider returns the configuration of the identity provider in YAML format,
+// without the fields that are specific to the cluster and without secrets.
+func ExportIdentityProvider(idp *cmv1.IdentityProvider) ([]byte, error) {
+	config, err := IdentityProviderConfig(idp)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(config)
+}
+
+// IdentityProviderConfig returns the configuration of the identity provider exported by
+// ExportIdentityProvider, before it is converted to YAML.
+func IdentityProviderConfig(idp *cmv1.IdentityProvider) (map[string]interface{}, error) {
+	buffer := &bytes.Buffer{}
+	err := cmv1.MarshalIdentityProvider(idp, buffer)
+	if err != nil {
+		return nil, err
+	}
+	config := map[string]interface{}{}
+	err = json.Unmarshal(buffer.Bytes(), &config)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range identityProviderLocalFields {
+		delete(config, field)
+	}
+	for provider, secret := range IdentityProviderSecrets {
+		if settings, ok := config[provider].(map[string]interface{}); ok {
+			delete(settings, secret)
+		}
+	}
+	return config, nil
+}
+
+// ImportIdentityProvider parses a configuration exported with ExportIdentityProvider. The given
+// function is called to get the secret of the provider, as it isn't part of the configuration.
+func ImportIdentityProvider(data []byte,
+	getSecret func(provider string, field string) (string, error)) (*cmv1.IdentityProvider, error) {
+	var parsed interface{}
+	err := yaml.Unmarshal(data, &parsed)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse identity provider configuration: %v", err)
+	}
+	config, ok := toJSONValue(parsed).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Expected the identity provider configuration to be an object")
+	}
+	for _, field := range identityProviderLocalFields {
+		delete(config, field)
+	}
+
+	for provider, secret := range IdentityProviderSecrets {
+		settings, ok := config[provider].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if _, ok := settings[secret]; ok {
+			continue
+		}
+		// LDAP providers only need a password when they bind with a DN:
+		if provider == "ldap" && settings["bind_dn"] == nil {
+			continue
+		}
+		value, err := getSecret(provider, secret)
+		if err != nil {
+			return nil, err
+		}
+		settings[secret] = value
+	}
+
+	body, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	return cmv1.UnmarshalIdentityProvider(body)
+}
+
+// toJSONValue converts the maps generated by the YAML parser, which have keys of any type, into
+// maps with string keys, so that they can be serialized as JSON.
+func toJSONValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{})
+		for key, item := range typed {
+			result[fmt.Sprintf("%v", key)] = toJSONValue(item)
+		}
+		return result
+	case []interface{}:
+		for i, item := range typed {
+			typed[i] = toJSONValue(item)
+		}
+	}
+	return value
+}
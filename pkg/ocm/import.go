@@ -0,0 +1,101 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that import into the tool the clusters that were created with
+// other tools, like OpenShift Dedicated clusters that use the customer's cloud subscription (CCS).
+// The tool only manages the clusters that have the property with the ARN of their creator, so
+// importing a cluster sets that property.
+
+package ocm
+
+import (
+	"fmt"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/ocm/properties"
+)
+
+// ImportProblem is a configuration of a cluster that the tool doesn't support. Blocking problems
+// prevent the import, the rest only limit what the tool can do with the cluster.
+type ImportProblem struct {
+	Message  string
+	Blocking bool
+}
+
+// GetClusterByID returns the cluster with the given identifier, regardless of who created it.
+func GetClusterByID(client *cmv1.ClustersClient, clusterID string) (*cmv1.Cluster, error) {
+	response, err := client.Cluster(clusterID).Get().Send()
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Body(), nil
+}
+
+// IsImported checks if the cluster is already managed by the tool for the given creator.
+func IsImported(cluster *cmv1.Cluster, creatorARN string) bool {
+	return cluster.Properties()[properties.CreatorARN] == creatorARN
+}
+
+// CheckImport returns the problems that the given cluster has to be managed by the tool with the
+// credentials of the given AWS account and creator.
+func CheckImport(cluster *cmv1.Cluster, accountID string, creatorARN string) []*ImportProblem {
+	problems := []*ImportProblem{}
+	blocking := func(format string, args ...interface{}) {
+		problems = append(problems, &ImportProblem{Message: fmt.Sprintf(format, args...), Blocking: true})
+	}
+	warning := func(format string, args ...interface{}) {
+		problems = append(problems, &ImportProblem{Message: fmt.Sprintf(format, args...)})
+	}
+
+	if cluster.CloudProvider().ID() != "aws" {
+		blocking("Cluster runs in cloud provider '%s', only AWS clusters are supported",
+			cluster.CloudProvider().ID())
+		return problems
+	}
+	if !cluster.CCS().Enabled() {
+		blocking("Cluster doesn't use the customer's cloud subscription (CCS), its AWS account is " +
+			"managed by Red Hat")
+		return problems
+	}
+	if cluster.AWS().AccountID() != "" && cluster.AWS().AccountID() != accountID {
+		blocking("Cluster is in AWS account '%s', but the current AWS credentials are for account '%s'",
+			cluster.AWS().AccountID(), accountID)
+	}
+	owner := cluster.Properties()[properties.CreatorARN]
+	if owner != "" && owner != creatorARN {
+		blocking("Cluster is already managed by '%s'", owner)
+	}
+
+	switch cluster.State() {
+	case cmv1.ClusterStateReady:
+	case cmv1.ClusterStateUninstalling:
+		blocking("Cluster is being uninstalled")
+	default:
+		warning("Cluster is in state '%s', most commands require it to be '%s'",
+			cluster.State(), cmv1.ClusterStateReady)
+	}
+	if cluster.Product().ID() != "" && cluster.Product().ID() != "osd" && cluster.Product().ID() != "rosa" {
+		warning("Cluster is a '%s' cluster, some commands may not support it", cluster.Product().ID())
+	}
+	return problems
+}
+
+// ImportCluster makes the tool manage the cluster on behalf of the given creator.
+func ImportCluster(client *cmv1.ClustersClient, cluster *cmv1.Cluster, creatorARN string) error {
+	props := withProperty(cluster.Properties(), properties.CreatorARN, creatorARN)
+	return updateProperties(client, cluster, props)
+}
@@ -0,0 +1,138 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions that manage the kubelet configurations of clusters, stored in the
+// 'kubelet_configs' collection.
+
+package kubeletconfigs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+
+	"github.com/openshift/moactl/pkg/ocm/raw"
+)
+
+// Range of values of the PIDs limit that OCM accepts:
+const (
+	MinPodPidsLimit = 4096
+	MaxPodPidsLimit = 16384
+)
+
+// KubeletConfig is a kubelet configuration that can be attached to the machine pools of a
+// cluster.
+type KubeletConfig struct {
+	ID           string `json:"id,omitempty"`
+	Name         string `json:"name,omitempty"`
+	PodPidsLimit int    `json:"pod_pids_limit"`
+}
+
+func collectionPath(clusterID string) string {
+	return fmt.Sprintf("/api/clusters_mgmt/v1/clusters/%s/kubelet_configs", clusterID)
+}
+
+// ValidatePodPidsLimit checks that the given PIDs limit is in the range that OCM accepts.
+func ValidatePodPidsLimit(limit int) error {
+	if limit < MinPodPidsLimit || limit > MaxPodPidsLimit {
+		return fmt.Errorf("Pod PIDs limit %d isn't valid: it must be between %d and %d",
+			limit, MinPodPidsLimit, MaxPodPidsLimit)
+	}
+	return nil
+}
+
+// GetKubeletConfigs returns the kubelet configurations of the given cluster.
+func GetKubeletConfigs(connection *sdk.Connection, clusterID string) ([]*KubeletConfig, error) {
+	response, err := raw.Send(
+		connection.Get().
+			Path(collectionPath(clusterID)),
+		http.StatusOK,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Items []*KubeletConfig `json:"items"`
+	}
+	err = json.Unmarshal(response.Bytes(), &list)
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// GetKubeletConfig returns the kubelet configuration of the given cluster with the given name, or
+// nil if it doesn't exist.
+func GetKubeletConfig(connection *sdk.Connection, clusterID string, name string) (*KubeletConfig, error) {
+	configs, err := GetKubeletConfigs(connection, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	for _, config := range configs {
+		if config.Name == name {
+			return config, nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateKubeletConfig adds the given kubelet configuration to the cluster and returns it as stored
+// by OCM.
+func CreateKubeletConfig(connection *sdk.Connection, clusterID string,
+	config *KubeletConfig) (*KubeletConfig, error) {
+	body, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	response, err := raw.Send(
+		connection.Post().
+			Path(collectionPath(clusterID)).
+			Bytes(body),
+		http.StatusCreated, http.StatusOK,
+	)
+	if err != nil {
+		return nil, err
+	}
+	result := &KubeletConfig{}
+	err = json.Unmarshal(response.Bytes(), result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// UpdateKubeletConfig changes the PIDs limit of the kubelet configuration with the given
+// identifier. The nodes of the machine pools that use it are updated by OCM.
+func UpdateKubeletConfig(connection *sdk.Connection, clusterID string, configID string,
+	podPidsLimit int) error {
+	body, err := json.Marshal(&KubeletConfig{PodPidsLimit: podPidsLimit})
+	if err != nil {
+		return err
+	}
+	_, err = raw.Send(
+		connection.Patch().
+			Path(collectionPath(clusterID)+"/"+url.PathEscape(configID)).
+			Bytes(body),
+		http.StatusOK,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -24,6 +24,8 @@ import (
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	errors "github.com/zgalor/weberr"
+
+	"github.com/openshift/moactl/pkg/ci"
 )
 
 const interval = 15 * time.Second
@@ -60,33 +62,9 @@ func GetUninstallLogs(client *cmv1.ClustersClient, clusterID string, tail int) (
 	return response.Body(), nil
 }
 
-func PollInstallLogs(client *cmv1.ClustersClient, clusterID string,
-	cb func(*cmv1.LogGetResponse) bool) (logs *cmv1.Log, err error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
-	defer func() {
-		cancel()
-	}()
-
-	logsClient := client.Cluster(clusterID).Logs().Install()
-	response, err := logsClient.Poll().
-		Parameter("tail", 100).
-		Interval(interval).
-		Predicate(cb).
-		StartContext(ctx)
-	if err != nil {
-		err = fmt.Errorf("Failed to poll logs for cluster '%s': %v", clusterID, err)
-		if response.Status() == http.StatusNotFound {
-			err = errors.NotFound.UserErrorf("Failed to poll logs for cluster '%s'", clusterID)
-		}
-		return
-	}
-
-	return response.Body(), nil
-}
-
 func PollUninstallLogs(client *cmv1.ClustersClient, clusterID string,
 	cb func(*cmv1.LogGetResponse) bool) (logs *cmv1.Log, err error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), ci.Bound(time.Hour))
 	defer func() {
 		cancel()
 	}()
@@ -0,0 +1,69 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that validate and build the autoscaling settings of machine
+// pools, which replace the fixed number of replicas with a range that the cluster autoscaler
+// keeps the number of nodes within.
+
+package machinepools
+
+import (
+	"fmt"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// multiAZZones is the number of availability zones of multi-AZ clusters. The nodes of their
+// machine pools are spread evenly over the zones.
+const multiAZZones = 3
+
+// ValidateAutoscaling checks that the given range of replicas can be used for a machine pool of a
+// cluster: the minimum can't be greater than the maximum, the maximum has to be at least one and,
+// in multi-AZ clusters, both have to be multiples of the number of zones.
+func ValidateAutoscaling(minReplicas int, maxReplicas int, multiAZ bool) error {
+	if minReplicas < 0 {
+		return fmt.Errorf("Minimum number of replicas must be a non-negative number")
+	}
+	if maxReplicas < 1 {
+		return fmt.Errorf("Maximum number of replicas must be greater than zero")
+	}
+	if minReplicas > maxReplicas {
+		return fmt.Errorf("Minimum number of replicas %d can't be greater than maximum number of replicas %d",
+			minReplicas, maxReplicas)
+	}
+	if multiAZ && (minReplicas%multiAZZones != 0 || maxReplicas%multiAZZones != 0) {
+		return fmt.Errorf("Minimum and maximum number of replicas of multi-AZ clusters must be multiples "+
+			"of %d", multiAZZones)
+	}
+	return nil
+}
+
+// NewAutoscaling returns the builder of the autoscaling settings for the given range of replicas.
+func NewAutoscaling(minReplicas int, maxReplicas int) *cmv1.MachinePoolAutoscalingBuilder {
+	return cmv1.NewMachinePoolAutoscaling().
+		MinReplicas(minReplicas).
+		MaxReplicas(maxReplicas)
+}
+
+// FormatReplicas returns the number of replicas of the given machine pool, or the range of
+// replicas if it is autoscaled.
+func FormatReplicas(machinePool *cmv1.MachinePool) string {
+	autoscaling := machinePool.Autoscaling()
+	if autoscaling != nil {
+		return fmt.Sprintf("%d-%d", autoscaling.MinReplicas(), autoscaling.MaxReplicas())
+	}
+	return fmt.Sprintf("%d", machinePool.Replicas())
+}
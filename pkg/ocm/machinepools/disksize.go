@@ -0,0 +1,78 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinepools
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// diskSizeRE matches sizes like '300GiB', '1.5 TiB' or '500GB'. Sizes without unit are in GiB.
+var diskSizeRE = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)\s*([A-Za-z]*)$`)
+
+// Number of bytes of each of the units accepted in disk sizes:
+var diskSizeUnits = map[string]float64{
+	"":    1 << 30,
+	"g":   1 << 30,
+	"gi":  1 << 30,
+	"gib": 1 << 30,
+	"gb":  1e9,
+	"t":   1 << 40,
+	"ti":  1 << 40,
+	"tib": 1 << 40,
+	"tb":  1e12,
+}
+
+// ParseDiskSize parses a human readable disk size, like '300GiB' or '1TiB', and returns it in GiB,
+// rounded up to the next whole GiB as that is the unit that AWS uses for volumes.
+func ParseDiskSize(size string) (int, error) {
+	matches := diskSizeRE.FindStringSubmatch(strings.TrimSpace(size))
+	if matches == nil {
+		return 0, fmt.Errorf("Disk size '%s' isn't valid: expected a number followed by a unit like "+
+			"'GiB' or 'TiB', for example '300GiB'", size)
+	}
+	unit, ok := diskSizeUnits[strings.ToLower(matches[2])]
+	if !ok {
+		return 0, fmt.Errorf("Unit '%s' of disk size '%s' isn't valid: it must be 'GiB', 'TiB', 'GB' "+
+			"or 'TB'", matches[2], size)
+	}
+	value, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("Disk size '%s' isn't valid: %v", size, err)
+	}
+	return int(math.Ceil(value * unit / (1 << 30))), nil
+}
+
+// DiskSizeValidator returns a validator for interactive answers that checks that the answer is a
+// valid disk size and that it passes the given check. Empty answers are accepted, as they select
+// the default size.
+func DiskSizeValidator(check func(int) error) func(answer interface{}) error {
+	return func(answer interface{}) error {
+		str, ok := answer.(string)
+		if !ok || str == "" {
+			return nil
+		}
+		size, err := ParseDiskSize(str)
+		if err != nil {
+			return err
+		}
+		return check(size)
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that parse, validate and format the labels and taints of the
+// nodes of machine pools. The keys, values and effects are checked with the same rules that
+// Kubernetes uses, so that invalid ones are rejected before the machine pool is created.
+
+package machinepools
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// Maximum lengths of the prefix and the name of keys, and of label values:
+const (
+	maxKeyPrefixLength = 253
+	maxNameLength      = 63
+)
+
+// Effects that taints can have:
+var TaintEffects = []string{"NoSchedule", "PreferNoSchedule", "NoExecute"}
+
+var (
+	nameRE   = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`)
+	prefixRE = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+)
+
+// ParseLabels parses a comma separated list of labels in 'key=value' format. An empty string
+// returns an empty map, which removes all the labels of a machine pool.
+func ParseLabels(labels string) (map[string]string, error) {
+	result := map[string]string{}
+	if strings.TrimSpace(labels) == "" {
+		return result, nil
+	}
+	for _, label := range strings.Split(labels, ",") {
+		tokens := strings.SplitN(label, "=", 2)
+		if len(tokens) != 2 {
+			return nil, fmt.Errorf("Label '%s' isn't valid: expected 'key=value' format", label)
+		}
+		key := strings.TrimSpace(tokens[0])
+		value := strings.TrimSpace(tokens[1])
+		err := validateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("Label '%s' isn't valid: %v", label, err)
+		}
+		err = validateValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("Label '%s' isn't valid: %v", label, err)
+		}
+		if _, ok := result[key]; ok {
+			return nil, fmt.Errorf("Label key '%s' is given more than once", key)
+		}
+		result[key] = value
+	}
+	return result, nil
+}
+
+// ParseTaints parses a comma separated list of taints in 'key=value:Effect' format. The value is
+// optional, so 'key:Effect' is also accepted. An empty string returns an empty list, which removes
+// all the taints of a machine pool.
+func ParseTaints(taints string) ([]*cmv1.TaintBuilder, error) {
+	result := []*cmv1.TaintBuilder{}
+	if strings.TrimSpace(taints) == "" {
+		return result, nil
+	}
+	for _, taint := range strings.Split(taints, ",") {
+		index := strings.LastIndex(taint, ":")
+		if index < 0 {
+			return nil, fmt.Errorf("Taint '%s' isn't valid: expected 'key=value:Effect' format", taint)
+		}
+		effect := strings.TrimSpace(taint[index+1:])
+		key := strings.TrimSpace(taint[:index])
+		value := ""
+		if i := strings.Index(key, "="); i >= 0 {
+			value = strings.TrimSpace(key[i+1:])
+			key = strings.TrimSpace(key[:i])
+		}
+		err := validateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("Taint '%s' isn't valid: %v", taint, err)
+		}
+		err = validateValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("Taint '%s' isn't valid: %v", taint, err)
+		}
+		err = validateEffect(effect)
+		if err != nil {
+			return nil, fmt.Errorf("Taint '%s' isn't valid: %v", taint, err)
+		}
+		result = append(result, cmv1.NewTaint().Key(key).Value(value).Effect(effect))
+	}
+	return result, nil
+}
+
+// FormatLabels returns the given labels as a comma separated list sorted by key.
+func FormatLabels(labels map[string]string) string {
+	values := make([]string, 0, len(labels))
+	for key, value := range labels {
+		values = append(values, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(values)
+	return strings.Join(values, ", ")
+}
+
+// FormatTaints returns the given taints as a comma separated list, in the same format that
+// ParseTaints accepts.
+func FormatTaints(taints []*cmv1.Taint) string {
+	values := make([]string, 0, len(taints))
+	for _, taint := range taints {
+		if taint.Value() == "" {
+			values = append(values, fmt.Sprintf("%s:%s", taint.Key(), taint.Effect()))
+		} else {
+			values = append(values, fmt.Sprintf("%s=%s:%s", taint.Key(), taint.Value(), taint.Effect()))
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
+// validateKey checks that the given key is a Kubernetes qualified name: a name optionally preceded
+// by a DNS subdomain prefix and a slash, like 'example.com/name'.
+func validateKey(key string) error {
+	name := key
+	if i := strings.Index(key, "/"); i >= 0 {
+		prefix := key[:i]
+		name = key[i+1:]
+		if prefix == "" || len(prefix) > maxKeyPrefixLength || !prefixRE.MatchString(prefix) {
+			return fmt.Errorf("key prefix '%s' must be a lowercase DNS subdomain of at most %d "+
+				"characters", prefix, maxKeyPrefixLength)
+		}
+	}
+	if name == "" {
+		return fmt.Errorf("key can't be empty")
+	}
+	if len(name) > maxNameLength || !nameRE.MatchString(name) {
+		return fmt.Errorf("key name '%s' must be at most %d characters, contain only letters, "+
+			"digits, '-', '_' and '.', and start and end with a letter or digit", name, maxNameLength)
+	}
+	return nil
+}
+
+// validateValue checks that the given value is a valid Kubernetes label value. Empty values are
+// valid.
+func validateValue(value string) error {
+	if value == "" {
+		return nil
+	}
+	if len(value) > maxNameLength || !nameRE.MatchString(value) {
+		return fmt.Errorf("value '%s' must be at most %d characters, contain only letters, digits, "+
+			"'-', '_' and '.', and start and end with a letter or digit", value, maxNameLength)
+	}
+	return nil
+}
+
+func validateEffect(effect string) error {
+	for _, valid := range TaintEffects {
+		if effect == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("effect '%s' must be one of %s", effect, strings.Join(TaintEffects, ", "))
+}
@@ -0,0 +1,186 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions that change the instance type, the upgrade settings and the kubelet
+// and tuning configurations of machine pools.
+
+package machinepools
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+
+	"github.com/openshift/moactl/pkg/ocm/raw"
+)
+
+// Default values of the upgrade settings, the same that OCM uses, so that a single new node is
+// added before each old node is removed:
+const (
+	DefaultMaxSurge       = "1"
+	DefaultMaxUnavailable = "0"
+)
+
+// RolloutPlan describes how the nodes of a machine pool are replaced when its instance type is
+// changed.
+type RolloutPlan struct {
+	Replicas       int
+	MaxSurge       int
+	MaxUnavailable int
+
+	// Batches is the number of rounds of node replacements needed to replace all the nodes.
+	Batches int
+
+	// MaxNodes is the maximum number of nodes that exist at the same time during the rollout.
+	MaxNodes int
+
+	// MinAvailable is the minimum number of nodes that remain available during the rollout.
+	MinAvailable int
+}
+
+// PlanRollout calculates the rollout of a machine pool with the given number of replicas and
+// upgrade settings. The settings are either a number of nodes or a percentage of the replicas,
+// like '25%'. As Kubernetes does, percentages of the surge are rounded up and percentages of the
+// unavailable nodes are rounded down.
+func PlanRollout(replicas int, maxSurge string, maxUnavailable string) (*RolloutPlan, error) {
+	surge, err := resolve(maxSurge, replicas, true)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid max surge '%s': %v", maxSurge, err)
+	}
+	unavailable, err := resolve(maxUnavailable, replicas, false)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid max unavailable '%s': %v", maxUnavailable, err)
+	}
+	if unavailable > replicas {
+		unavailable = replicas
+	}
+	if surge == 0 && unavailable == 0 {
+		return nil, fmt.Errorf("Max surge and max unavailable can't both be zero, " +
+			"as no nodes could be replaced")
+	}
+
+	batches := 0
+	if replicas > 0 {
+		batches = (replicas + surge + unavailable - 1) / (surge + unavailable)
+	}
+	return &RolloutPlan{
+		Replicas:       replicas,
+		MaxSurge:       surge,
+		MaxUnavailable: unavailable,
+		Batches:        batches,
+		MaxNodes:       replicas + surge,
+		MinAvailable:   replicas - unavailable,
+	}, nil
+}
+
+// resolve converts an upgrade setting to a number of nodes.
+func resolve(value string, replicas int, roundUp bool) (int, error) {
+	if strings.HasSuffix(value, "%") {
+		percent, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+		if err != nil || percent < 0 || percent > 100 {
+			return 0, fmt.Errorf("expected a percentage between 0%% and 100%%")
+		}
+		if roundUp {
+			return (replicas*percent + 99) / 100, nil
+		}
+		return replicas * percent / 100, nil
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 0 {
+		return 0, fmt.Errorf("expected a non-negative number of nodes or a percentage")
+	}
+	return count, nil
+}
+
+func machinePoolPath(clusterID string, machinePoolID string) string {
+	return fmt.Sprintf("/api/clusters_mgmt/v1/clusters/%s/machine_pools/%s", clusterID, machinePoolID)
+}
+
+type managementUpgrade struct {
+	Type           string `json:"type"`
+	MaxSurge       string `json:"max_surge"`
+	MaxUnavailable string `json:"max_unavailable"`
+}
+
+type machinePoolPatch struct {
+	InstanceType      string             `json:"instance_type"`
+	ManagementUpgrade *managementUpgrade `json:"management_upgrade"`
+}
+
+// UpdateInstanceType changes the instance type of the given machine pool, replacing its nodes
+// with the given upgrade settings. OCM rejects the change for machine pools that don't support
+// changing the instance type in place.
+func UpdateInstanceType(connection *sdk.Connection, clusterID string, machinePoolID string,
+	instanceType string, maxSurge string, maxUnavailable string) error {
+	body, err := json.Marshal(&machinePoolPatch{
+		InstanceType: instanceType,
+		ManagementUpgrade: &managementUpgrade{
+			Type:           "Replace",
+			MaxSurge:       maxSurge,
+			MaxUnavailable: maxUnavailable,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = raw.Send(
+		connection.Patch().
+			Path(machinePoolPath(clusterID, machinePoolID)).
+			Bytes(body),
+		http.StatusOK,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+type nodeConfigsPatch struct {
+	KubeletConfigs *[]string `json:"kubelet_configs,omitempty"`
+	TuningConfigs  *[]string `json:"tuning_configs,omitempty"`
+}
+
+// UpdateNodeConfigs replaces the names of the kubelet and tuning configurations attached to the
+// given machine pool. A nil list leaves the corresponding configurations unchanged, and an empty
+// list detaches all of them.
+func UpdateNodeConfigs(connection *sdk.Connection, clusterID string, machinePoolID string,
+	kubeletConfigs []string, tuningConfigs []string) error {
+	patch := &nodeConfigsPatch{}
+	if kubeletConfigs != nil {
+		patch.KubeletConfigs = &kubeletConfigs
+	}
+	if tuningConfigs != nil {
+		patch.TuningConfigs = &tuningConfigs
+	}
+	body, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	_, err = raw.Send(
+		connection.Patch().
+			Path(machinePoolPath(clusterID, machinePoolID)).
+			Bytes(body),
+		http.StatusOK,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions for machine pools that run on AWS spot instances, which are
+// part of the AWS details of the machine pools.
+
+package machinepools
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/ocm/raw"
+)
+
+// OnDemandMaxPrice is the value of the maximum price that means that spot instances can cost up to
+// the price of the on-demand instances.
+const OnDemandMaxPrice = "on-demand"
+
+// SpotMarketOptions contains the settings of the spot instances of a machine pool. A nil maximum
+// price means that the instances can cost up to the on-demand price.
+type SpotMarketOptions struct {
+	MaxPrice *float64 `json:"max_price,omitempty"`
+}
+
+// ParseSpotMaxPrice converts the maximum hourly price given by the user, either a number of US
+// dollars or 'on-demand', into the spot market options.
+func ParseSpotMaxPrice(value string) (*SpotMarketOptions, error) {
+	if value == "" || value == OnDemandMaxPrice {
+		return &SpotMarketOptions{}, nil
+	}
+	price, err := strconv.ParseFloat(value, 64)
+	if err != nil || price <= 0 {
+		return nil, fmt.Errorf("Spot instance max price '%s' isn't valid: it must be a positive number "+
+			"of US dollars per hour or '%s'", value, OnDemandMaxPrice)
+	}
+	return &SpotMarketOptions{MaxPrice: &price}, nil
+}
+
+// String returns the description of the maximum price of the spot instances.
+func (o *SpotMarketOptions) String() string {
+	if o.MaxPrice == nil {
+		return "on-demand price"
+	}
+	return fmt.Sprintf("max $%s/hour", strconv.FormatFloat(*o.MaxPrice, 'f', -1, 64))
+}
+
+type machinePoolAWS struct {
+	SpotMarketOptions *SpotMarketOptions `json:"spot_market_options,omitempty"`
+	SubnetIDs         []string           `json:"subnet_ids,omitempty"`
+}
+
+// AddMachinePool adds the given machine pool to the cluster. If spot market options are given the
+// nodes of the machine pool run on spot instances. If a subnet is given the nodes run in it instead
+// of in the subnets of the cluster, which is how machine pools are placed in Local Zones and
+// Outposts. If a disk size, in GiB, is given it replaces the default size of the root volume of the
+// nodes.
+func AddMachinePool(connection *sdk.Connection, clusterID string, machinePool *cmv1.MachinePool,
+	spot *SpotMarketOptions, subnetID string, diskSize int) error {
+	var buffer bytes.Buffer
+	err := cmv1.MarshalMachinePool(machinePool, &buffer)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal description of machine pool: %v", err)
+	}
+	var body map[string]interface{}
+	err = json.Unmarshal(buffer.Bytes(), &body)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal description of machine pool: %v", err)
+	}
+	if spot != nil || subnetID != "" {
+		details := &machinePoolAWS{SpotMarketOptions: spot}
+		if subnetID != "" {
+			details.SubnetIDs = []string{subnetID}
+		}
+		body["aws"] = details
+	}
+	if diskSize != 0 {
+		body["root_volume"] = RootVolume(diskSize)
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal description of machine pool: %v", err)
+	}
+	_, err = raw.Send(
+		connection.Post().
+			Path(fmt.Sprintf("/api/clusters_mgmt/v1/clusters/%s/machine_pools", clusterID)).
+			Bytes(data),
+		http.StatusCreated,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// RootVolume returns the JSON description of a root volume with the given size in GiB, as used by
+// machine pools and by the compute nodes of clusters.
+func RootVolume(diskSize int) map[string]interface{} {
+	return map[string]interface{}{
+		"aws": map[string]interface{}{
+			"size": diskSize,
+		},
+	}
+}
+
+// GetSpotMarketOptions returns the spot market options of the machine pools of the cluster, indexed
+// by the identifier of the machine pool. Machine pools that don't use spot instances aren't
+// included.
+func GetSpotMarketOptions(connection *sdk.Connection, clusterID string) (map[string]*SpotMarketOptions, error) {
+	response, err := raw.Send(
+		connection.Get().
+			Path(fmt.Sprintf("/api/clusters_mgmt/v1/clusters/%s/machine_pools", clusterID)).
+			Parameter("size", -1),
+		http.StatusOK,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var body struct {
+		Items []struct {
+			ID  string         `json:"id"`
+			AWS machinePoolAWS `json:"aws"`
+		} `json:"items"`
+	}
+	err = json.Unmarshal(response.Bytes(), &body)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]*SpotMarketOptions{}
+	for _, item := range body.Items {
+		if item.AWS.SpotMarketOptions != nil {
+			result[item.ID] = item.AWS.SpotMarketOptions
+		}
+	}
+	return result, nil
+}
+
+// GetMachinePool returns the given machine pool of the cluster, together with its spot market
+// options, which are nil if it doesn't use spot instances, and the raw description that OCM
+// returned.
+func GetMachinePool(connection *sdk.Connection, clusterID string, machinePoolID string) (
+	machinePool *cmv1.MachinePool, spot *SpotMarketOptions, data []byte, err error) {
+	response, err := raw.Send(
+		connection.Get().
+			Path(machinePoolPath(clusterID, machinePoolID)),
+		http.StatusOK,
+	)
+	if err != nil {
+		return
+	}
+	data = response.Bytes()
+	machinePool, err = cmv1.UnmarshalMachinePool(data)
+	if err != nil {
+		return
+	}
+	var body struct {
+		AWS machinePoolAWS `json:"aws"`
+	}
+	err = json.Unmarshal(data, &body)
+	if err != nil {
+		return
+	}
+	spot = body.AWS.SpotMarketOptions
+	return
+}
+
+// FormatSpot returns the description of the spot instances used by a machine pool, for example
+// 'Yes (max $0.5/hour)'.
+func FormatSpot(spot *SpotMarketOptions) string {
+	if spot == nil {
+		return "No"
+	}
+	return fmt.Sprintf("Yes (%s)", spot)
+}
@@ -0,0 +1,46 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinepools
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidateAvailabilityZones checks that the given zones can be used for a machine pool of a
+// cluster with the given zones: they have to be zones of the cluster, and either a single zone or
+// all of them. An empty list means all the zones of the cluster.
+func ValidateAvailabilityZones(zones []string, clusterZones []string) error {
+	if len(zones) == 0 {
+		return nil
+	}
+	valid := make(map[string]bool, len(clusterZones))
+	for _, zone := range clusterZones {
+		valid[zone] = true
+	}
+	for _, zone := range zones {
+		if !valid[zone] {
+			return fmt.Errorf("Availability zone '%s' isn't a zone of the cluster, the zones of the "+
+				"cluster are: %s", zone, strings.Join(clusterZones, ", "))
+		}
+	}
+	if len(zones) != 1 && len(zones) != len(clusterZones) {
+		return fmt.Errorf("Machine pools must use either a single availability zone or all the %d "+
+			"zones of the cluster", len(clusterZones))
+	}
+	return nil
+}
@@ -17,14 +17,25 @@ limitations under the License.
 package machines
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"strings"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/cache"
 )
 
 func GetMachineTypes(client *cmv1.Client) (machineTypes []*cmv1.MachineType, err error) {
+	cacheKey := cache.Key("machine-types")
+	if data, ok := cache.Get(cacheKey, cache.MachineTypesTTL); ok {
+		machineTypes, err = cmv1.UnmarshalMachineTypeList(data)
+		if err == nil {
+			return
+		}
+	}
+
 	collection := client.MachineTypes()
 	page := 1
 	size := 100
@@ -49,6 +60,11 @@ func GetMachineTypes(client *cmv1.Client) (machineTypes []*cmv1.MachineType, err
 		}
 		page++
 	}
+
+	buffer := new(bytes.Buffer)
+	if cmv1.MarshalMachineTypeList(machineTypes, buffer) == nil {
+		cache.Set(cacheKey, buffer.Bytes())
+	}
 	return
 }
 
@@ -85,3 +101,39 @@ func GetMachineTypeList(client *cmv1.Client) (machineTypeList []string, err erro
 
 	return
 }
+
+// IsAvailable checks if EC2 offers the given machine type in all the given availability zones,
+// according to the zones where each instance type is offered. When no zones are given it is enough
+// that the machine type is offered in one of the zones of the region.
+func IsAvailable(machineType string, offerings map[string][]string, zones []string) bool {
+	offered := offerings[machineType]
+	if len(offered) == 0 {
+		return false
+	}
+	for _, zone := range zones {
+		found := false
+		for _, item := range offered {
+			if item == zone {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterAvailableList returns the machine types of the given list that EC2 offers in all the given
+// availability zones, see IsAvailable.
+func FilterAvailableList(machineTypeList []string, offerings map[string][]string,
+	zones []string) []string {
+	available := []string{}
+	for _, machineType := range machineTypeList {
+		if IsAvailable(machineType, offerings, zones) {
+			available = append(available, machineType)
+		}
+	}
+	return available
+}
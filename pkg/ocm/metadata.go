@@ -0,0 +1,47 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocm
+
+import (
+	"encoding/json"
+	"net/http"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+
+	"github.com/openshift/moactl/pkg/ocm/raw"
+)
+
+// GetMinimumCLIVersion returns the oldest version of the tool that the clusters management
+// service still accepts requests from, or an empty string if the service doesn't say.
+func GetMinimumCLIVersion(connection *sdk.Connection) (string, error) {
+	response, err := raw.Send(
+		connection.Get().
+			Path("/api/clusters_mgmt/v1"),
+		http.StatusOK,
+	)
+	if err != nil {
+		return "", err
+	}
+	var body struct {
+		MinimumCLIVersion string `json:"rosa_minimum_version"`
+	}
+	err = json.Unmarshal(response.Bytes(), &body)
+	if err != nil {
+		return "", err
+	}
+	return body.MinimumCLIVersion, nil
+}
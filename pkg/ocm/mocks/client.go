@@ -0,0 +1,1484 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/openshift/moactl/pkg/ocm (interfaces: Client)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	v1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
+	v10 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	v11 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+	defaults "github.com/openshift/moactl/pkg/defaults"
+	ocm "github.com/openshift/moactl/pkg/ocm"
+	autoscalers "github.com/openshift/moactl/pkg/ocm/autoscalers"
+	htpasswd "github.com/openshift/moactl/pkg/ocm/htpasswd"
+	kubeletconfigs "github.com/openshift/moactl/pkg/ocm/kubeletconfigs"
+	machinepools "github.com/openshift/moactl/pkg/ocm/machinepools"
+	oidcconfigs "github.com/openshift/moactl/pkg/ocm/oidcconfigs"
+	roles "github.com/openshift/moactl/pkg/ocm/roles"
+	tuningconfigs "github.com/openshift/moactl/pkg/ocm/tuningconfigs"
+	net "net"
+	reflect "reflect"
+	time "time"
+)
+
+// MockClient is a mock of Client interface
+type MockClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientMockRecorder
+}
+
+// MockClientMockRecorder is the mock recorder for MockClient
+type MockClientMockRecorder struct {
+	mock *MockClient
+}
+
+// NewMockClient creates a new mock instance
+func NewMockClient(ctrl *gomock.Controller) *MockClient {
+	mock := &MockClient{ctrl: ctrl}
+	mock.recorder = &MockClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockClient) EXPECT() *MockClientMockRecorder {
+	return m.recorder
+}
+
+// AddCluster mocks base method
+func (m *MockClient) AddCluster(arg0 *v10.Cluster, arg1 map[string]interface{}, arg2 bool) (*v10.Cluster, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCluster", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v10.Cluster)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCluster indicates an expected call of AddCluster
+func (mr *MockClientMockRecorder) AddCluster(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCluster", reflect.TypeOf((*MockClient)(nil).AddCluster), arg0, arg1, arg2)
+}
+
+// AddGroupUser mocks base method
+func (m *MockClient) AddGroupUser(arg0, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddGroupUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddGroupUser indicates an expected call of AddGroupUser
+func (mr *MockClientMockRecorder) AddGroupUser(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddGroupUser", reflect.TypeOf((*MockClient)(nil).AddGroupUser), arg0, arg1, arg2)
+}
+
+// AddHTPasswdUser mocks base method
+func (m *MockClient) AddHTPasswdUser(arg0, arg1 string, arg2 *htpasswd.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddHTPasswdUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddHTPasswdUser indicates an expected call of AddHTPasswdUser
+func (mr *MockClientMockRecorder) AddHTPasswdUser(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddHTPasswdUser", reflect.TypeOf((*MockClient)(nil).AddHTPasswdUser), arg0, arg1, arg2)
+}
+
+// AddIdentityProvider mocks base method
+func (m *MockClient) AddIdentityProvider(arg0 string, arg1 *v10.IdentityProvider) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddIdentityProvider", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddIdentityProvider indicates an expected call of AddIdentityProvider
+func (mr *MockClientMockRecorder) AddIdentityProvider(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddIdentityProvider", reflect.TypeOf((*MockClient)(nil).AddIdentityProvider), arg0, arg1)
+}
+
+// AddIngress mocks base method
+func (m *MockClient) AddIngress(arg0 string, arg1 *v10.Ingress) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddIngress", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddIngress indicates an expected call of AddIngress
+func (mr *MockClientMockRecorder) AddIngress(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddIngress", reflect.TypeOf((*MockClient)(nil).AddIngress), arg0, arg1)
+}
+
+// AddMachinePool mocks base method
+func (m *MockClient) AddMachinePool(arg0 string, arg1 *v10.MachinePool, arg2 *machinepools.SpotMarketOptions, arg3 string, arg4 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMachinePool", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddMachinePool indicates an expected call of AddMachinePool
+func (mr *MockClientMockRecorder) AddMachinePool(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMachinePool", reflect.TypeOf((*MockClient)(nil).AddMachinePool), arg0, arg1, arg2, arg3, arg4)
+}
+
+// CancelUpgrade mocks base method
+func (m *MockClient) CancelUpgrade(arg0 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelUpgrade", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelUpgrade indicates an expected call of CancelUpgrade
+func (mr *MockClientMockRecorder) CancelUpgrade(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelUpgrade", reflect.TypeOf((*MockClient)(nil).CancelUpgrade), arg0)
+}
+
+// CreateAutoscaler mocks base method
+func (m *MockClient) CreateAutoscaler(arg0 string, arg1 *autoscalers.Autoscaler) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAutoscaler", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAutoscaler indicates an expected call of CreateAutoscaler
+func (mr *MockClientMockRecorder) CreateAutoscaler(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAutoscaler", reflect.TypeOf((*MockClient)(nil).CreateAutoscaler), arg0, arg1)
+}
+
+// CreateHTPasswdIdentityProvider mocks base method
+func (m *MockClient) CreateHTPasswdIdentityProvider(arg0, arg1, arg2 string, arg3 []*htpasswd.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHTPasswdIdentityProvider", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateHTPasswdIdentityProvider indicates an expected call of CreateHTPasswdIdentityProvider
+func (mr *MockClientMockRecorder) CreateHTPasswdIdentityProvider(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHTPasswdIdentityProvider", reflect.TypeOf((*MockClient)(nil).CreateHTPasswdIdentityProvider), arg0, arg1, arg2, arg3)
+}
+
+// CreateKubeletConfig mocks base method
+func (m *MockClient) CreateKubeletConfig(arg0 string, arg1 *kubeletconfigs.KubeletConfig) (*kubeletconfigs.KubeletConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateKubeletConfig", arg0, arg1)
+	ret0, _ := ret[0].(*kubeletconfigs.KubeletConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateKubeletConfig indicates an expected call of CreateKubeletConfig
+func (mr *MockClientMockRecorder) CreateKubeletConfig(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateKubeletConfig", reflect.TypeOf((*MockClient)(nil).CreateKubeletConfig), arg0, arg1)
+}
+
+// CreateOIDCConfig mocks base method
+func (m *MockClient) CreateOIDCConfig(arg0 *oidcconfigs.OIDCConfig) (*oidcconfigs.OIDCConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOIDCConfig", arg0)
+	ret0, _ := ret[0].(*oidcconfigs.OIDCConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOIDCConfig indicates an expected call of CreateOIDCConfig
+func (mr *MockClientMockRecorder) CreateOIDCConfig(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOIDCConfig", reflect.TypeOf((*MockClient)(nil).CreateOIDCConfig), arg0)
+}
+
+// CreateServiceLog mocks base method
+func (m *MockClient) CreateServiceLog(arg0 *v11.LogEntry) (*v11.LogEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateServiceLog", arg0)
+	ret0, _ := ret[0].(*v11.LogEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateServiceLog indicates an expected call of CreateServiceLog
+func (mr *MockClientMockRecorder) CreateServiceLog(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateServiceLog", reflect.TypeOf((*MockClient)(nil).CreateServiceLog), arg0)
+}
+
+// CreateTuningConfig mocks base method
+func (m *MockClient) CreateTuningConfig(arg0 string, arg1 *tuningconfigs.TuningConfig) (*tuningconfigs.TuningConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTuningConfig", arg0, arg1)
+	ret0, _ := ret[0].(*tuningconfigs.TuningConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTuningConfig indicates an expected call of CreateTuningConfig
+func (mr *MockClientMockRecorder) CreateTuningConfig(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTuningConfig", reflect.TypeOf((*MockClient)(nil).CreateTuningConfig), arg0, arg1)
+}
+
+// DeleteCluster mocks base method
+func (m *MockClient) DeleteCluster(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCluster", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCluster indicates an expected call of DeleteCluster
+func (mr *MockClientMockRecorder) DeleteCluster(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCluster", reflect.TypeOf((*MockClient)(nil).DeleteCluster), arg0)
+}
+
+// DeleteGroupUser mocks base method
+func (m *MockClient) DeleteGroupUser(arg0, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGroupUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGroupUser indicates an expected call of DeleteGroupUser
+func (mr *MockClientMockRecorder) DeleteGroupUser(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGroupUser", reflect.TypeOf((*MockClient)(nil).DeleteGroupUser), arg0, arg1, arg2)
+}
+
+// DeleteHTPasswdUser mocks base method
+func (m *MockClient) DeleteHTPasswdUser(arg0, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteHTPasswdUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteHTPasswdUser indicates an expected call of DeleteHTPasswdUser
+func (mr *MockClientMockRecorder) DeleteHTPasswdUser(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHTPasswdUser", reflect.TypeOf((*MockClient)(nil).DeleteHTPasswdUser), arg0, arg1, arg2)
+}
+
+// DeleteIdentityProvider mocks base method
+func (m *MockClient) DeleteIdentityProvider(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdentityProvider", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIdentityProvider indicates an expected call of DeleteIdentityProvider
+func (mr *MockClientMockRecorder) DeleteIdentityProvider(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdentityProvider", reflect.TypeOf((*MockClient)(nil).DeleteIdentityProvider), arg0, arg1)
+}
+
+// DeleteIngress mocks base method
+func (m *MockClient) DeleteIngress(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIngress", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIngress indicates an expected call of DeleteIngress
+func (mr *MockClientMockRecorder) DeleteIngress(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIngress", reflect.TypeOf((*MockClient)(nil).DeleteIngress), arg0, arg1)
+}
+
+// DeleteMachinePool mocks base method
+func (m *MockClient) DeleteMachinePool(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMachinePool", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMachinePool indicates an expected call of DeleteMachinePool
+func (mr *MockClientMockRecorder) DeleteMachinePool(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMachinePool", reflect.TypeOf((*MockClient)(nil).DeleteMachinePool), arg0, arg1)
+}
+
+// GetAccess mocks base method
+func (m *MockClient) GetAccess() (*roles.Access, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccess")
+	ret0, _ := ret[0].(*roles.Access)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccess indicates an expected call of GetAccess
+func (mr *MockClientMockRecorder) GetAccess() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccess", reflect.TypeOf((*MockClient)(nil).GetAccess))
+}
+
+// GetAddOn mocks base method
+func (m *MockClient) GetAddOn(arg0 string) (*v10.AddOn, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAddOn", arg0)
+	ret0, _ := ret[0].(*v10.AddOn)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAddOn indicates an expected call of GetAddOn
+func (mr *MockClientMockRecorder) GetAddOn(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAddOn", reflect.TypeOf((*MockClient)(nil).GetAddOn), arg0)
+}
+
+// GetAddOnInstallation mocks base method
+func (m *MockClient) GetAddOnInstallation(arg0, arg1 string) (*v10.AddOnInstallation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAddOnInstallation", arg0, arg1)
+	ret0, _ := ret[0].(*v10.AddOnInstallation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAddOnInstallation indicates an expected call of GetAddOnInstallation
+func (mr *MockClientMockRecorder) GetAddOnInstallation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAddOnInstallation", reflect.TypeOf((*MockClient)(nil).GetAddOnInstallation), arg0, arg1)
+}
+
+// GetAddOnSchema mocks base method
+func (m *MockClient) GetAddOnSchema(arg0 string) (*ocm.AddOnSchema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAddOnSchema", arg0)
+	ret0, _ := ret[0].(*ocm.AddOnSchema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAddOnSchema indicates an expected call of GetAddOnSchema
+func (mr *MockClientMockRecorder) GetAddOnSchema(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAddOnSchema", reflect.TypeOf((*MockClient)(nil).GetAddOnSchema), arg0)
+}
+
+// GetAdminIdentityProvider mocks base method
+func (m *MockClient) GetAdminIdentityProvider(arg0 string) (*v10.IdentityProvider, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdminIdentityProvider", arg0)
+	ret0, _ := ret[0].(*v10.IdentityProvider)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAdminIdentityProvider indicates an expected call of GetAdminIdentityProvider
+func (mr *MockClientMockRecorder) GetAdminIdentityProvider(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdminIdentityProvider", reflect.TypeOf((*MockClient)(nil).GetAdminIdentityProvider), arg0)
+}
+
+// GetAutoscaler mocks base method
+func (m *MockClient) GetAutoscaler(arg0 string) (*autoscalers.Autoscaler, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAutoscaler", arg0)
+	ret0, _ := ret[0].(*autoscalers.Autoscaler)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAutoscaler indicates an expected call of GetAutoscaler
+func (mr *MockClientMockRecorder) GetAutoscaler(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAutoscaler", reflect.TypeOf((*MockClient)(nil).GetAutoscaler), arg0)
+}
+
+// GetAvailableRegions mocks base method
+func (m *MockClient) GetAvailableRegions(arg0 *v10.AWS) ([]*v10.CloudRegion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAvailableRegions", arg0)
+	ret0, _ := ret[0].([]*v10.CloudRegion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAvailableRegions indicates an expected call of GetAvailableRegions
+func (mr *MockClientMockRecorder) GetAvailableRegions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailableRegions", reflect.TypeOf((*MockClient)(nil).GetAvailableRegions), arg0)
+}
+
+// GetAvailableUpgrades mocks base method
+func (m *MockClient) GetAvailableUpgrades(arg0 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAvailableUpgrades", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAvailableUpgrades indicates an expected call of GetAvailableUpgrades
+func (mr *MockClientMockRecorder) GetAvailableUpgrades(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailableUpgrades", reflect.TypeOf((*MockClient)(nil).GetAvailableUpgrades), arg0)
+}
+
+// GetCluster mocks base method
+func (m *MockClient) GetCluster(arg0, arg1 string) (*v10.Cluster, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCluster", arg0, arg1)
+	ret0, _ := ret[0].(*v10.Cluster)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCluster indicates an expected call of GetCluster
+func (mr *MockClientMockRecorder) GetCluster(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCluster", reflect.TypeOf((*MockClient)(nil).GetCluster), arg0, arg1)
+}
+
+// GetClusterAddOns mocks base method
+func (m *MockClient) GetClusterAddOns(arg0 string) ([]*ocm.ClusterAddOn, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClusterAddOns", arg0)
+	ret0, _ := ret[0].([]*ocm.ClusterAddOn)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClusterAddOns indicates an expected call of GetClusterAddOns
+func (mr *MockClientMockRecorder) GetClusterAddOns(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClusterAddOns", reflect.TypeOf((*MockClient)(nil).GetClusterAddOns), arg0)
+}
+
+// GetClusterByID mocks base method
+func (m *MockClient) GetClusterByID(arg0 string) (*v10.Cluster, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClusterByID", arg0)
+	ret0, _ := ret[0].(*v10.Cluster)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClusterByID indicates an expected call of GetClusterByID
+func (mr *MockClientMockRecorder) GetClusterByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClusterByID", reflect.TypeOf((*MockClient)(nil).GetClusterByID), arg0)
+}
+
+// GetClusterDocument mocks base method
+func (m *MockClient) GetClusterDocument(arg0 string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClusterDocument", arg0)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClusterDocument indicates an expected call of GetClusterDocument
+func (mr *MockClientMockRecorder) GetClusterDocument(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClusterDocument", reflect.TypeOf((*MockClient)(nil).GetClusterDocument), arg0)
+}
+
+// GetClusterQuotaCost mocks base method
+func (m *MockClient) GetClusterQuotaCost(arg0 bool, arg1 string, arg2 int) ([]*ocm.QuotaCost, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClusterQuotaCost", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*ocm.QuotaCost)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClusterQuotaCost indicates an expected call of GetClusterQuotaCost
+func (mr *MockClientMockRecorder) GetClusterQuotaCost(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClusterQuotaCost", reflect.TypeOf((*MockClient)(nil).GetClusterQuotaCost), arg0, arg1, arg2)
+}
+
+// GetClusterState mocks base method
+func (m *MockClient) GetClusterState(arg0 string) (v10.ClusterState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClusterState", arg0)
+	ret0, _ := ret[0].(v10.ClusterState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClusterState indicates an expected call of GetClusterState
+func (mr *MockClientMockRecorder) GetClusterState(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClusterState", reflect.TypeOf((*MockClient)(nil).GetClusterState), arg0)
+}
+
+// GetClusterStatus mocks base method
+func (m *MockClient) GetClusterStatus(arg0 string) (*v10.ClusterStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClusterStatus", arg0)
+	ret0, _ := ret[0].(*v10.ClusterStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClusterStatus indicates an expected call of GetClusterStatus
+func (mr *MockClientMockRecorder) GetClusterStatus(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClusterStatus", reflect.TypeOf((*MockClient)(nil).GetClusterStatus), arg0)
+}
+
+// GetClusters mocks base method
+func (m *MockClient) GetClusters(arg0 string, arg1 int) ([]*v10.Cluster, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClusters", arg0, arg1)
+	ret0, _ := ret[0].([]*v10.Cluster)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClusters indicates an expected call of GetClusters
+func (mr *MockClientMockRecorder) GetClusters(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClusters", reflect.TypeOf((*MockClient)(nil).GetClusters), arg0, arg1)
+}
+
+// GetClustersPage mocks base method
+func (m *MockClient) GetClustersPage(arg0, arg1 string, arg2, arg3 int) ([]*v10.Cluster, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClustersPage", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*v10.Cluster)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetClustersPage indicates an expected call of GetClustersPage
+func (mr *MockClientMockRecorder) GetClustersPage(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClustersPage", reflect.TypeOf((*MockClient)(nil).GetClustersPage), arg0, arg1, arg2, arg3)
+}
+
+// GetCompliance mocks base method
+func (m *MockClient) GetCompliance(arg0 string) (*ocm.Compliance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCompliance", arg0)
+	ret0, _ := ret[0].(*ocm.Compliance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCompliance indicates an expected call of GetCompliance
+func (mr *MockClientMockRecorder) GetCompliance(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCompliance", reflect.TypeOf((*MockClient)(nil).GetCompliance), arg0)
+}
+
+// GetCurrentAccount mocks base method
+func (m *MockClient) GetCurrentAccount() (*v1.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentAccount")
+	ret0, _ := ret[0].(*v1.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentAccount indicates an expected call of GetCurrentAccount
+func (mr *MockClientMockRecorder) GetCurrentAccount() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentAccount", reflect.TypeOf((*MockClient)(nil).GetCurrentAccount))
+}
+
+// GetDefaultClusterFlavors mocks base method
+func (m *MockClient) GetDefaultClusterFlavors() (*net.IPNet, *net.IPNet, *net.IPNet, int) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDefaultClusterFlavors")
+	ret0, _ := ret[0].(*net.IPNet)
+	ret1, _ := ret[1].(*net.IPNet)
+	ret2, _ := ret[2].(*net.IPNet)
+	ret3, _ := ret[3].(int)
+	return ret0, ret1, ret2, ret3
+}
+
+// GetDefaultClusterFlavors indicates an expected call of GetDefaultClusterFlavors
+func (mr *MockClientMockRecorder) GetDefaultClusterFlavors() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDefaultClusterFlavors", reflect.TypeOf((*MockClient)(nil).GetDefaultClusterFlavors))
+}
+
+// GetEndOfLifeDates mocks base method
+func (m *MockClient) GetEndOfLifeDates(arg0 string) (map[string]time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEndOfLifeDates", arg0)
+	ret0, _ := ret[0].(map[string]time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEndOfLifeDates indicates an expected call of GetEndOfLifeDates
+func (mr *MockClientMockRecorder) GetEndOfLifeDates(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEndOfLifeDates", reflect.TypeOf((*MockClient)(nil).GetEndOfLifeDates), arg0)
+}
+
+// GetGroupMembers mocks base method
+func (m *MockClient) GetGroupMembers(arg0 *v10.Cluster) ([]string, map[string][]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupMembers", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(map[string][]string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetGroupMembers indicates an expected call of GetGroupMembers
+func (mr *MockClientMockRecorder) GetGroupMembers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupMembers", reflect.TypeOf((*MockClient)(nil).GetGroupMembers), arg0)
+}
+
+// GetHTPasswdUsers mocks base method
+func (m *MockClient) GetHTPasswdUsers(arg0, arg1 string) ([]*htpasswd.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHTPasswdUsers", arg0, arg1)
+	ret0, _ := ret[0].([]*htpasswd.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHTPasswdUsers indicates an expected call of GetHTPasswdUsers
+func (mr *MockClientMockRecorder) GetHTPasswdUsers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHTPasswdUsers", reflect.TypeOf((*MockClient)(nil).GetHTPasswdUsers), arg0, arg1)
+}
+
+// GetIdentityProviders mocks base method
+func (m *MockClient) GetIdentityProviders(arg0 string) ([]*v10.IdentityProvider, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdentityProviders", arg0)
+	ret0, _ := ret[0].([]*v10.IdentityProvider)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdentityProviders indicates an expected call of GetIdentityProviders
+func (mr *MockClientMockRecorder) GetIdentityProviders(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdentityProviders", reflect.TypeOf((*MockClient)(nil).GetIdentityProviders), arg0)
+}
+
+// GetInfraID mocks base method
+func (m *MockClient) GetInfraID(arg0 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInfraID", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInfraID indicates an expected call of GetInfraID
+func (mr *MockClientMockRecorder) GetInfraID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInfraID", reflect.TypeOf((*MockClient)(nil).GetInfraID), arg0)
+}
+
+// GetIngresses mocks base method
+func (m *MockClient) GetIngresses(arg0 string) ([]*v10.Ingress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngresses", arg0)
+	ret0, _ := ret[0].([]*v10.Ingress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIngresses indicates an expected call of GetIngresses
+func (mr *MockClientMockRecorder) GetIngresses(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngresses", reflect.TypeOf((*MockClient)(nil).GetIngresses), arg0)
+}
+
+// GetInstallLogs mocks base method
+func (m *MockClient) GetInstallLogs(arg0 string, arg1 int) (*v10.Log, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInstallLogs", arg0, arg1)
+	ret0, _ := ret[0].(*v10.Log)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInstallLogs indicates an expected call of GetInstallLogs
+func (mr *MockClientMockRecorder) GetInstallLogs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstallLogs", reflect.TypeOf((*MockClient)(nil).GetInstallLogs), arg0, arg1)
+}
+
+// GetKMSKeyARN mocks base method
+func (m *MockClient) GetKMSKeyARN(arg0 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKMSKeyARN", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKMSKeyARN indicates an expected call of GetKMSKeyARN
+func (mr *MockClientMockRecorder) GetKMSKeyARN(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKMSKeyARN", reflect.TypeOf((*MockClient)(nil).GetKMSKeyARN), arg0)
+}
+
+// GetKubeletConfig mocks base method
+func (m *MockClient) GetKubeletConfig(arg0, arg1 string) (*kubeletconfigs.KubeletConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKubeletConfig", arg0, arg1)
+	ret0, _ := ret[0].(*kubeletconfigs.KubeletConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKubeletConfig indicates an expected call of GetKubeletConfig
+func (mr *MockClientMockRecorder) GetKubeletConfig(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKubeletConfig", reflect.TypeOf((*MockClient)(nil).GetKubeletConfig), arg0, arg1)
+}
+
+// GetKubeletConfigs mocks base method
+func (m *MockClient) GetKubeletConfigs(arg0 string) ([]*kubeletconfigs.KubeletConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKubeletConfigs", arg0)
+	ret0, _ := ret[0].([]*kubeletconfigs.KubeletConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKubeletConfigs indicates an expected call of GetKubeletConfigs
+func (mr *MockClientMockRecorder) GetKubeletConfigs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKubeletConfigs", reflect.TypeOf((*MockClient)(nil).GetKubeletConfigs), arg0)
+}
+
+// GetMachinePool mocks base method
+func (m *MockClient) GetMachinePool(arg0, arg1 string) (*v10.MachinePool, *machinepools.SpotMarketOptions, []byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMachinePool", arg0, arg1)
+	ret0, _ := ret[0].(*v10.MachinePool)
+	ret1, _ := ret[1].(*machinepools.SpotMarketOptions)
+	ret2, _ := ret[2].([]byte)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// GetMachinePool indicates an expected call of GetMachinePool
+func (mr *MockClientMockRecorder) GetMachinePool(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMachinePool", reflect.TypeOf((*MockClient)(nil).GetMachinePool), arg0, arg1)
+}
+
+// GetMachinePools mocks base method
+func (m *MockClient) GetMachinePools(arg0 string) ([]*v10.MachinePool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMachinePools", arg0)
+	ret0, _ := ret[0].([]*v10.MachinePool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMachinePools indicates an expected call of GetMachinePools
+func (mr *MockClientMockRecorder) GetMachinePools(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMachinePools", reflect.TypeOf((*MockClient)(nil).GetMachinePools), arg0)
+}
+
+// GetMachineTypeList mocks base method
+func (m *MockClient) GetMachineTypeList() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMachineTypeList")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMachineTypeList indicates an expected call of GetMachineTypeList
+func (mr *MockClientMockRecorder) GetMachineTypeList() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMachineTypeList", reflect.TypeOf((*MockClient)(nil).GetMachineTypeList))
+}
+
+// GetMachineTypes mocks base method
+func (m *MockClient) GetMachineTypes() ([]*v10.MachineType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMachineTypes")
+	ret0, _ := ret[0].([]*v10.MachineType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMachineTypes indicates an expected call of GetMachineTypes
+func (mr *MockClientMockRecorder) GetMachineTypes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMachineTypes", reflect.TypeOf((*MockClient)(nil).GetMachineTypes))
+}
+
+// GetMinimumCLIVersion mocks base method
+func (m *MockClient) GetMinimumCLIVersion() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMinimumCLIVersion")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMinimumCLIVersion indicates an expected call of GetMinimumCLIVersion
+func (mr *MockClientMockRecorder) GetMinimumCLIVersion() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMinimumCLIVersion", reflect.TypeOf((*MockClient)(nil).GetMinimumCLIVersion))
+}
+
+// GetPrivateLink mocks base method
+func (m *MockClient) GetPrivateLink(arg0 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrivateLink", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPrivateLink indicates an expected call of GetPrivateLink
+func (mr *MockClientMockRecorder) GetPrivateLink(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrivateLink", reflect.TypeOf((*MockClient)(nil).GetPrivateLink), arg0)
+}
+
+// GetScheduledUpgrade mocks base method
+func (m *MockClient) GetScheduledUpgrade(arg0 string) (*v10.UpgradePolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScheduledUpgrade", arg0)
+	ret0, _ := ret[0].(*v10.UpgradePolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScheduledUpgrade indicates an expected call of GetScheduledUpgrade
+func (mr *MockClientMockRecorder) GetScheduledUpgrade(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduledUpgrade", reflect.TypeOf((*MockClient)(nil).GetScheduledUpgrade), arg0)
+}
+
+// GetServiceLogs mocks base method
+func (m *MockClient) GetServiceLogs(arg0 string) ([]*v11.LogEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceLogs", arg0)
+	ret0, _ := ret[0].([]*v11.LogEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceLogs indicates an expected call of GetServiceLogs
+func (mr *MockClientMockRecorder) GetServiceLogs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceLogs", reflect.TypeOf((*MockClient)(nil).GetServiceLogs), arg0)
+}
+
+// GetSpotMarketOptions mocks base method
+func (m *MockClient) GetSpotMarketOptions(arg0 string) (map[string]*machinepools.SpotMarketOptions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSpotMarketOptions", arg0)
+	ret0, _ := ret[0].(map[string]*machinepools.SpotMarketOptions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSpotMarketOptions indicates an expected call of GetSpotMarketOptions
+func (mr *MockClientMockRecorder) GetSpotMarketOptions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpotMarketOptions", reflect.TypeOf((*MockClient)(nil).GetSpotMarketOptions), arg0)
+}
+
+// GetTuningConfig mocks base method
+func (m *MockClient) GetTuningConfig(arg0, arg1 string) (*tuningconfigs.TuningConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTuningConfig", arg0, arg1)
+	ret0, _ := ret[0].(*tuningconfigs.TuningConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTuningConfig indicates an expected call of GetTuningConfig
+func (mr *MockClientMockRecorder) GetTuningConfig(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTuningConfig", reflect.TypeOf((*MockClient)(nil).GetTuningConfig), arg0, arg1)
+}
+
+// GetTuningConfigs mocks base method
+func (m *MockClient) GetTuningConfigs(arg0 string) ([]*tuningconfigs.TuningConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTuningConfigs", arg0)
+	ret0, _ := ret[0].([]*tuningconfigs.TuningConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTuningConfigs indicates an expected call of GetTuningConfigs
+func (mr *MockClientMockRecorder) GetTuningConfigs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTuningConfigs", reflect.TypeOf((*MockClient)(nil).GetTuningConfigs), arg0)
+}
+
+// GetUninstallLogs mocks base method
+func (m *MockClient) GetUninstallLogs(arg0 string, arg1 int) (*v10.Log, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUninstallLogs", arg0, arg1)
+	ret0, _ := ret[0].(*v10.Log)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUninstallLogs indicates an expected call of GetUninstallLogs
+func (mr *MockClientMockRecorder) GetUninstallLogs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUninstallLogs", reflect.TypeOf((*MockClient)(nil).GetUninstallLogs), arg0, arg1)
+}
+
+// GetUpgradePolicies mocks base method
+func (m *MockClient) GetUpgradePolicies(arg0 string) ([]*v10.UpgradePolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUpgradePolicies", arg0)
+	ret0, _ := ret[0].([]*v10.UpgradePolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUpgradePolicies indicates an expected call of GetUpgradePolicies
+func (mr *MockClientMockRecorder) GetUpgradePolicies(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUpgradePolicies", reflect.TypeOf((*MockClient)(nil).GetUpgradePolicies), arg0)
+}
+
+// GetUpgradeState mocks base method
+func (m *MockClient) GetUpgradeState(arg0, arg1 string) (*v10.UpgradePolicyState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUpgradeState", arg0, arg1)
+	ret0, _ := ret[0].(*v10.UpgradePolicyState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUpgradeState indicates an expected call of GetUpgradeState
+func (mr *MockClientMockRecorder) GetUpgradeState(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUpgradeState", reflect.TypeOf((*MockClient)(nil).GetUpgradeState), arg0, arg1)
+}
+
+// GetUser mocks base method
+func (m *MockClient) GetUser(arg0, arg1, arg2 string) (*v10.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v10.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser
+func (mr *MockClientMockRecorder) GetUser(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockClient)(nil).GetUser), arg0, arg1, arg2)
+}
+
+// GetUsers mocks base method
+func (m *MockClient) GetUsers(arg0, arg1 string) ([]*v10.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsers", arg0, arg1)
+	ret0, _ := ret[0].([]*v10.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsers indicates an expected call of GetUsers
+func (mr *MockClientMockRecorder) GetUsers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockClient)(nil).GetUsers), arg0, arg1)
+}
+
+// GetVersions mocks base method
+func (m *MockClient) GetVersions(arg0 string) ([]*v10.Version, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersions", arg0)
+	ret0, _ := ret[0].([]*v10.Version)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVersions indicates an expected call of GetVersions
+func (mr *MockClientMockRecorder) GetVersions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersions", reflect.TypeOf((*MockClient)(nil).GetVersions), arg0)
+}
+
+// HasClusters mocks base method
+func (m *MockClient) HasClusters(arg0 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasClusters", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasClusters indicates an expected call of HasClusters
+func (mr *MockClientMockRecorder) HasClusters(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasClusters", reflect.TypeOf((*MockClient)(nil).HasClusters), arg0)
+}
+
+// HibernateCluster mocks base method
+func (m *MockClient) HibernateCluster(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HibernateCluster", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HibernateCluster indicates an expected call of HibernateCluster
+func (mr *MockClientMockRecorder) HibernateCluster(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HibernateCluster", reflect.TypeOf((*MockClient)(nil).HibernateCluster), arg0)
+}
+
+// ImportCluster mocks base method
+func (m *MockClient) ImportCluster(arg0 *v10.Cluster, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportCluster", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImportCluster indicates an expected call of ImportCluster
+func (mr *MockClientMockRecorder) ImportCluster(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportCluster", reflect.TypeOf((*MockClient)(nil).ImportCluster), arg0, arg1)
+}
+
+// InstallAddOn mocks base method
+func (m *MockClient) InstallAddOn(arg0, arg1 string, arg2 map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstallAddOn", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InstallAddOn indicates an expected call of InstallAddOn
+func (mr *MockClientMockRecorder) InstallAddOn(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallAddOn", reflect.TypeOf((*MockClient)(nil).InstallAddOn), arg0, arg1, arg2)
+}
+
+// ListServiceLogs mocks base method
+func (m *MockClient) ListServiceLogs(arg0 string, arg1 ocm.ServiceLogFilter) ([]*v11.LogEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListServiceLogs", arg0, arg1)
+	ret0, _ := ret[0].([]*v11.LogEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListServiceLogs indicates an expected call of ListServiceLogs
+func (mr *MockClientMockRecorder) ListServiceLogs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServiceLogs", reflect.TypeOf((*MockClient)(nil).ListServiceLogs), arg0, arg1)
+}
+
+// LoadDefaults mocks base method
+func (m *MockClient) LoadDefaults() (*defaults.Defaults, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadDefaults")
+	ret0, _ := ret[0].(*defaults.Defaults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadDefaults indicates an expected call of LoadDefaults
+func (mr *MockClientMockRecorder) LoadDefaults() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadDefaults", reflect.TypeOf((*MockClient)(nil).LoadDefaults))
+}
+
+// PatchCluster mocks base method
+func (m *MockClient) PatchCluster(arg0 string, arg1 map[string]interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchCluster", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PatchCluster indicates an expected call of PatchCluster
+func (mr *MockClientMockRecorder) PatchCluster(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchCluster", reflect.TypeOf((*MockClient)(nil).PatchCluster), arg0, arg1)
+}
+
+// PollUninstallLogs mocks base method
+func (m *MockClient) PollUninstallLogs(arg0 string, arg1 func(*v10.LogGetResponse) bool) (*v10.Log, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PollUninstallLogs", arg0, arg1)
+	ret0, _ := ret[0].(*v10.Log)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PollUninstallLogs indicates an expected call of PollUninstallLogs
+func (mr *MockClientMockRecorder) PollUninstallLogs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PollUninstallLogs", reflect.TypeOf((*MockClient)(nil).PollUninstallLogs), arg0, arg1)
+}
+
+// RemoveAccessExpiration mocks base method
+func (m *MockClient) RemoveAccessExpiration(arg0 *v10.Cluster, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAccessExpiration", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveAccessExpiration indicates an expected call of RemoveAccessExpiration
+func (mr *MockClientMockRecorder) RemoveAccessExpiration(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAccessExpiration", reflect.TypeOf((*MockClient)(nil).RemoveAccessExpiration), arg0, arg1, arg2)
+}
+
+// RemoveAccessExpirations mocks base method
+func (m *MockClient) RemoveAccessExpirations(arg0 *v10.Cluster, arg1 []*ocm.AccessExpiration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAccessExpirations", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveAccessExpirations indicates an expected call of RemoveAccessExpirations
+func (mr *MockClientMockRecorder) RemoveAccessExpirations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAccessExpirations", reflect.TypeOf((*MockClient)(nil).RemoveAccessExpirations), arg0, arg1)
+}
+
+// ResumeCluster mocks base method
+func (m *MockClient) ResumeCluster(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeCluster", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResumeCluster indicates an expected call of ResumeCluster
+func (mr *MockClientMockRecorder) ResumeCluster(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeCluster", reflect.TypeOf((*MockClient)(nil).ResumeCluster), arg0)
+}
+
+// RetryInstall mocks base method
+func (m *MockClient) RetryInstall(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryInstall", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryInstall indicates an expected call of RetryInstall
+func (mr *MockClientMockRecorder) RetryInstall(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryInstall", reflect.TypeOf((*MockClient)(nil).RetryInstall), arg0)
+}
+
+// ScheduleUpgrade mocks base method
+func (m *MockClient) ScheduleUpgrade(arg0, arg1 string, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduleUpgrade", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ScheduleUpgrade indicates an expected call of ScheduleUpgrade
+func (mr *MockClientMockRecorder) ScheduleUpgrade(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleUpgrade", reflect.TypeOf((*MockClient)(nil).ScheduleUpgrade), arg0, arg1, arg2)
+}
+
+// SearchClusters mocks base method
+func (m *MockClient) SearchClusters(arg0, arg1 string) ([]*v10.Cluster, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchClusters", arg0, arg1)
+	ret0, _ := ret[0].([]*v10.Cluster)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchClusters indicates an expected call of SearchClusters
+func (mr *MockClientMockRecorder) SearchClusters(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchClusters", reflect.TypeOf((*MockClient)(nil).SearchClusters), arg0, arg1)
+}
+
+// SetAccessExpiration mocks base method
+func (m *MockClient) SetAccessExpiration(arg0 *v10.Cluster, arg1, arg2 string, arg3 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAccessExpiration", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAccessExpiration indicates an expected call of SetAccessExpiration
+func (mr *MockClientMockRecorder) SetAccessExpiration(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAccessExpiration", reflect.TypeOf((*MockClient)(nil).SetAccessExpiration), arg0, arg1, arg2, arg3)
+}
+
+// SetDeleteProtection mocks base method
+func (m *MockClient) SetDeleteProtection(arg0 *v10.Cluster, arg1 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDeleteProtection", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDeleteProtection indicates an expected call of SetDeleteProtection
+func (mr *MockClientMockRecorder) SetDeleteProtection(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDeleteProtection", reflect.TypeOf((*MockClient)(nil).SetDeleteProtection), arg0, arg1)
+}
+
+// SetMachinePoolDeleteProtection mocks base method
+func (m *MockClient) SetMachinePoolDeleteProtection(arg0 *v10.Cluster, arg1 string, arg2 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMachinePoolDeleteProtection", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMachinePoolDeleteProtection indicates an expected call of SetMachinePoolDeleteProtection
+func (mr *MockClientMockRecorder) SetMachinePoolDeleteProtection(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMachinePoolDeleteProtection", reflect.TypeOf((*MockClient)(nil).SetMachinePoolDeleteProtection), arg0, arg1, arg2)
+}
+
+// StreamClusters mocks base method
+func (m *MockClient) StreamClusters(arg0, arg1 string, arg2 int, arg3 func([]*v10.Cluster) bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamClusters", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamClusters indicates an expected call of StreamClusters
+func (mr *MockClientMockRecorder) StreamClusters(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamClusters", reflect.TypeOf((*MockClient)(nil).StreamClusters), arg0, arg1, arg2, arg3)
+}
+
+// URL mocks base method
+func (m *MockClient) URL() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "URL")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// URL indicates an expected call of URL
+func (mr *MockClientMockRecorder) URL() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "URL", reflect.TypeOf((*MockClient)(nil).URL))
+}
+
+// UninstallAddOn mocks base method
+func (m *MockClient) UninstallAddOn(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UninstallAddOn", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UninstallAddOn indicates an expected call of UninstallAddOn
+func (mr *MockClientMockRecorder) UninstallAddOn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UninstallAddOn", reflect.TypeOf((*MockClient)(nil).UninstallAddOn), arg0, arg1)
+}
+
+// UpdateAutoscaler mocks base method
+func (m *MockClient) UpdateAutoscaler(arg0 string, arg1 *autoscalers.Autoscaler) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAutoscaler", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAutoscaler indicates an expected call of UpdateAutoscaler
+func (mr *MockClientMockRecorder) UpdateAutoscaler(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAutoscaler", reflect.TypeOf((*MockClient)(nil).UpdateAutoscaler), arg0, arg1)
+}
+
+// UpdateCluster mocks base method
+func (m *MockClient) UpdateCluster(arg0 string, arg1 *v10.Cluster) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCluster", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCluster indicates an expected call of UpdateCluster
+func (mr *MockClientMockRecorder) UpdateCluster(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCluster", reflect.TypeOf((*MockClient)(nil).UpdateCluster), arg0, arg1)
+}
+
+// UpdateHTPasswdPassword mocks base method
+func (m *MockClient) UpdateHTPasswdPassword(arg0, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateHTPasswdPassword", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateHTPasswdPassword indicates an expected call of UpdateHTPasswdPassword
+func (mr *MockClientMockRecorder) UpdateHTPasswdPassword(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHTPasswdPassword", reflect.TypeOf((*MockClient)(nil).UpdateHTPasswdPassword), arg0, arg1, arg2, arg3)
+}
+
+// UpdateIngress mocks base method
+func (m *MockClient) UpdateIngress(arg0, arg1 string, arg2 *v10.Ingress) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateIngress", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateIngress indicates an expected call of UpdateIngress
+func (mr *MockClientMockRecorder) UpdateIngress(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIngress", reflect.TypeOf((*MockClient)(nil).UpdateIngress), arg0, arg1, arg2)
+}
+
+// UpdateInstanceType mocks base method
+func (m *MockClient) UpdateInstanceType(arg0, arg1, arg2, arg3, arg4 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateInstanceType", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateInstanceType indicates an expected call of UpdateInstanceType
+func (mr *MockClientMockRecorder) UpdateInstanceType(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInstanceType", reflect.TypeOf((*MockClient)(nil).UpdateInstanceType), arg0, arg1, arg2, arg3, arg4)
+}
+
+// UpdateKubeletConfig mocks base method
+func (m *MockClient) UpdateKubeletConfig(arg0, arg1 string, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateKubeletConfig", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateKubeletConfig indicates an expected call of UpdateKubeletConfig
+func (mr *MockClientMockRecorder) UpdateKubeletConfig(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateKubeletConfig", reflect.TypeOf((*MockClient)(nil).UpdateKubeletConfig), arg0, arg1, arg2)
+}
+
+// UpdateMachinePool mocks base method
+func (m *MockClient) UpdateMachinePool(arg0, arg1 string, arg2 *v10.MachinePool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMachinePool", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMachinePool indicates an expected call of UpdateMachinePool
+func (mr *MockClientMockRecorder) UpdateMachinePool(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMachinePool", reflect.TypeOf((*MockClient)(nil).UpdateMachinePool), arg0, arg1, arg2)
+}
+
+// UpdateNodeConfigs mocks base method
+func (m *MockClient) UpdateNodeConfigs(arg0, arg1 string, arg2, arg3 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNodeConfigs", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateNodeConfigs indicates an expected call of UpdateNodeConfigs
+func (mr *MockClientMockRecorder) UpdateNodeConfigs(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNodeConfigs", reflect.TypeOf((*MockClient)(nil).UpdateNodeConfigs), arg0, arg1, arg2, arg3)
+}
+
+// UpdateTuningConfig mocks base method
+func (m *MockClient) UpdateTuningConfig(arg0, arg1 string, arg2 map[string]interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTuningConfig", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTuningConfig indicates an expected call of UpdateTuningConfig
+func (mr *MockClientMockRecorder) UpdateTuningConfig(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTuningConfig", reflect.TypeOf((*MockClient)(nil).UpdateTuningConfig), arg0, arg1, arg2)
+}
+
+// WaitForState mocks base method
+func (m *MockClient) WaitForState(arg0 string, arg1 v10.ClusterState, arg2, arg3 time.Duration, arg4 func(v10.ClusterState)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForState", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WaitForState indicates an expected call of WaitForState
+func (mr *MockClientMockRecorder) WaitForState(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForState", reflect.TypeOf((*MockClient)(nil).WaitForState), arg0, arg1, arg2, arg3, arg4)
+}
+
+// WaitForUninstall mocks base method
+func (m *MockClient) WaitForUninstall(arg0 string, arg1, arg2 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForUninstall", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WaitForUninstall indicates an expected call of WaitForUninstall
+func (mr *MockClientMockRecorder) WaitForUninstall(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForUninstall", reflect.TypeOf((*MockClient)(nil).WaitForUninstall), arg0, arg1, arg2)
+}
+
+// WatchInstall mocks base method
+func (m *MockClient) WatchInstall(arg0 string, arg1, arg2 time.Duration, arg3 func(v10.ClusterState, *v10.Log)) (v10.ClusterState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchInstall", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(v10.ClusterState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchInstall indicates an expected call of WatchInstall
+func (mr *MockClientMockRecorder) WatchInstall(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchInstall", reflect.TypeOf((*MockClient)(nil).WatchInstall), arg0, arg1, arg2, arg3)
+}
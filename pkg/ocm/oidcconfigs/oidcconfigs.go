@@ -0,0 +1,64 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions that register OIDC configurations in the 'oidc_configs' collection
+// of OCM.
+
+package oidcconfigs
+
+import (
+	"encoding/json"
+	"net/http"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+
+	"github.com/openshift/moactl/pkg/ocm/raw"
+)
+
+const collectionPath = "/api/clusters_mgmt/v1/oidc_configs"
+
+// OIDCConfig is an OIDC configuration that can be shared by multiple clusters.
+type OIDCConfig struct {
+	ID               string `json:"id,omitempty"`
+	Managed          bool   `json:"managed"`
+	IssuerURL        string `json:"issuer_url,omitempty"`
+	SecretARN        string `json:"secret_arn,omitempty"`
+	InstallerRoleARN string `json:"installer_role_arn,omitempty"`
+}
+
+// CreateOIDCConfig registers the given OIDC configuration and returns it as stored by OCM, which
+// includes the identifier and, for managed configurations, the issuer URL.
+func CreateOIDCConfig(connection *sdk.Connection, config *OIDCConfig) (*OIDCConfig, error) {
+	body, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	response, err := raw.Send(
+		connection.Post().
+			Path(collectionPath).
+			Bytes(body),
+		http.StatusCreated, http.StatusOK,
+	)
+	if err != nil {
+		return nil, err
+	}
+	result := &OIDCConfig{}
+	err = json.Unmarshal(response.Bytes(), result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -27,3 +27,18 @@ const prefix = "rosa_"
 const CreatorARN = prefix + "creator_arn"
 
 const CLIVersion = prefix + "cli_version"
+
+// AccessExpirationPrefix is the prefix of the names of the properties that contain the time when
+// a temporary grant of a group to a user expires. The complete name is the prefix followed by the
+// group, an underscore and the user name.
+const AccessExpirationPrefix = prefix + "access_expiration_"
+
+// DeleteProtection is the name of the property that prevents deleting the cluster unless the
+// deletion is forced.
+const DeleteProtection = prefix + "delete_protection"
+
+// MachinePoolDeleteProtectionPrefix is the prefix of the names of the properties that prevent
+// deleting machine pools unless the deletion is forced. Machine pools don't have properties, so
+// they are stored in the cluster and the complete name is the prefix followed by the identifier of
+// the machine pool.
+const MachinePoolDeleteProtectionPrefix = prefix + "machine_pool_delete_protection_"
@@ -0,0 +1,72 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to protect clusters and machine pools against accidental
+// deletion. The protection is stored in properties of the cluster and checked by the delete
+// commands, which refuse to proceed unless the deletion is forced.
+
+package ocm
+
+import (
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/ocm/properties"
+)
+
+// protectionValue returns the value of the property that enables or disables the protection. The
+// property is removed when the protection is disabled.
+func protectionValue(enabled bool) string {
+	if enabled {
+		return "true"
+	}
+	return ""
+}
+
+func machinePoolDeleteProtectionProperty(machinePoolID string) string {
+	return properties.MachinePoolDeleteProtectionPrefix + machinePoolID
+}
+
+// IsDeleteProtected checks if the cluster is protected against deletion.
+func IsDeleteProtected(cluster *cmv1.Cluster) bool {
+	return cluster.Properties()[properties.DeleteProtection] == "true"
+}
+
+// SetDeleteProtection enables or disables the protection of the cluster against deletion.
+func SetDeleteProtection(client *cmv1.ClustersClient, cluster *cmv1.Cluster, enabled bool) error {
+	if IsDeleteProtected(cluster) == enabled {
+		return nil
+	}
+	props := withProperty(cluster.Properties(), properties.DeleteProtection, protectionValue(enabled))
+	return updateProperties(client, cluster, props)
+}
+
+// IsMachinePoolDeleteProtected checks if the given machine pool of the cluster is protected against
+// deletion.
+func IsMachinePoolDeleteProtected(cluster *cmv1.Cluster, machinePoolID string) bool {
+	return cluster.Properties()[machinePoolDeleteProtectionProperty(machinePoolID)] == "true"
+}
+
+// SetMachinePoolDeleteProtection enables or disables the protection of the given machine pool of
+// the cluster against deletion.
+func SetMachinePoolDeleteProtection(client *cmv1.ClustersClient, cluster *cmv1.Cluster,
+	machinePoolID string, enabled bool) error {
+	if IsMachinePoolDeleteProtected(cluster, machinePoolID) == enabled {
+		return nil
+	}
+	props := withProperty(cluster.Properties(), machinePoolDeleteProtectionProperty(machinePoolID),
+		protectionValue(enabled))
+	return updateProperties(client, cluster, props)
+}
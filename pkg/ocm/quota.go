@@ -0,0 +1,105 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocm
+
+import (
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	amsv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
+)
+
+// Types of the OCM resources that a customer cloud subscription cluster consumes:
+const (
+	ClusterResourceType     = "cluster.aws"
+	ComputeNodeResourceType = "compute.node"
+)
+
+// QuotaCost describes how much of an OCM quota of the organization a planned cluster requires.
+type QuotaCost struct {
+	ResourceType string `json:"resource_type"`
+	ResourceName string `json:"resource_name"`
+	Required     int    `json:"required"`
+	Allowed      int    `json:"allowed"`
+	Reserved     int    `json:"reserved"`
+}
+
+// Available returns the part of the quota that isn't reserved by other clusters.
+func (c *QuotaCost) Available() int {
+	return c.Allowed - c.Reserved
+}
+
+// Sufficient checks if the part of the quota that isn't reserved is enough for the cluster.
+func (c *QuotaCost) Sufficient() bool {
+	return c.Required <= c.Available()
+}
+
+// GetClusterQuotaCost calculates the OCM quota of the organization of the current account that a
+// cluster with the given availability and compute nodes requires. Quotas that the organization
+// doesn't have are returned with nothing allowed.
+func GetClusterQuotaCost(connection *sdk.Connection, multiAZ bool, computeMachineType string,
+	computeNodes int) ([]*QuotaCost, error) {
+	acctResponse, err := connection.AccountsMgmt().V1().CurrentAccount().
+		Get().
+		Send()
+	if err != nil {
+		return nil, handleErr(acctResponse.Error(), err)
+	}
+	organization := acctResponse.Body().Organization().ID()
+
+	summaryResponse, err := connection.AccountsMgmt().V1().Organizations().
+		Organization(organization).
+		QuotaSummary().
+		List().
+		Page(1).
+		Size(-1).
+		Send()
+	if err != nil {
+		return nil, handleErr(summaryResponse.Error(), err)
+	}
+
+	zoneType := "single"
+	if multiAZ {
+		zoneType = "multi"
+	}
+	cluster := &QuotaCost{
+		ResourceType: ClusterResourceType,
+		Required:     1,
+	}
+	nodes := &QuotaCost{
+		ResourceType: ComputeNodeResourceType,
+		ResourceName: computeMachineType,
+		Required:     computeNodes,
+	}
+	summaryResponse.Items().Each(func(summary *amsv1.QuotaSummary) bool {
+		if !summary.BYOC() || summary.AvailabilityZoneType() != zoneType {
+			return true
+		}
+		var cost *QuotaCost
+		switch {
+		case summary.ResourceType() == ClusterResourceType:
+			cost = cluster
+			cost.ResourceName = summary.ResourceName()
+		case summary.ResourceType() == ComputeNodeResourceType && summary.ResourceName() == computeMachineType:
+			cost = nodes
+		default:
+			return true
+		}
+		cost.Allowed += summary.Allowed()
+		cost.Reserved += summary.Reserved()
+		return true
+	})
+	return []*QuotaCost{cluster, nodes}, nil
+}
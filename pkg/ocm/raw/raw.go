@@ -0,0 +1,59 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package raw sends the requests that the version of the OCM SDK that we use doesn't support yet,
+// like new collections, fields and actions, using the raw API of the connection. When the SDK is
+// updated the callers should use the typed clients instead.
+package raw
+
+import (
+	"encoding/json"
+	"fmt"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// Send sends the given request and returns the response if its status is one of the expected
+// ones. Otherwise it returns the error described by the response.
+func Send(request *sdk.Request, expected ...int) (*sdk.Response, error) {
+	response, err := request.Send()
+	if err != nil {
+		return nil, rosaerrors.FromStatus(0, err)
+	}
+	for _, status := range expected {
+		if response.Status() == status {
+			return response, nil
+		}
+	}
+	return nil, Error(response)
+}
+
+// Error returns the error described by an unsuccessful response: the reason contained in the body,
+// or the status code if the body doesn't contain one. The error is categorized by the status code.
+func Error(response *sdk.Response) error {
+	var body struct {
+		Reason string `json:"reason"`
+	}
+	err := json.Unmarshal(response.Bytes(), &body)
+	if err != nil || body.Reason == "" {
+		err = fmt.Errorf("Unexpected status code %d", response.Status())
+	} else {
+		err = fmt.Errorf("%s", body.Reason)
+	}
+	return rosaerrors.FromStatus(response.Status(), err)
+}
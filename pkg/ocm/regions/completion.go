@@ -0,0 +1,68 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package regions
+
+import (
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/runtime"
+)
+
+// Complete returns the identifiers of the enabled AWS regions that start with the given text, to
+// complete the '--region' flags in the shell. Unlike GetRegions it doesn't need AWS credentials,
+// so it also offers regions that the account may not be able to use. It offers nothing when the
+// regions can't be loaded, as printing errors would break the completion of the shell.
+func Complete(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	connection, err := ocm.NewConnection().
+		Logger(runtime.FromContext(cmd.Context()).Logger()).
+		Build()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer connection.Close()
+
+	response, err := connection.ClustersMgmt().V1().CloudProviders().CloudProvider("aws").Regions().List().
+		Size(100).
+		Send()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	ids := []string{}
+	response.Items().Each(func(region *cmv1.CloudRegion) bool {
+		if region.Enabled() && strings.HasPrefix(region.ID(), toComplete) {
+			ids = append(ids, region.ID())
+		}
+		return true
+	})
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
+
+// RegisterCompletion registers Complete as the completion of the '--region' flags of the given
+// command and of all its subcommands.
+func RegisterCompletion(cmd *cobra.Command) {
+	if cmd.LocalFlags().Lookup("region") != nil {
+		// The only error is that the completion is already registered, which is fine:
+		_ = cmd.RegisterFlagCompletionFunc("region", Complete)
+	}
+	for _, child := range cmd.Commands() {
+		RegisterCompletion(child)
+	}
+}
@@ -17,34 +17,22 @@ limitations under the License.
 package regions
 
 import (
-	"errors"
+	"bytes"
 	"fmt"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 
 	"github.com/openshift/moactl/pkg/aws"
-	"github.com/openshift/moactl/pkg/logging"
+	"github.com/openshift/moactl/pkg/cache"
+	"github.com/openshift/moactl/pkg/ocm"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
 )
 
-func GetRegions(client *cmv1.Client) (regions []*cmv1.CloudRegion, err error) {
-	// Retrieve AWS credentials from the local AWS user
-	// pass these to OCM to validate what regions are available
-	// in this AWS account
-
-	// Build AWS client and retrieve credentials
-	// This ensures we use the profile flag if passed to rosa
-	// Create the AWS client:
+// GetRegions returns the regions where the AWS account of the given AWS client can create
+// clusters. The credentials of the AWS client are passed to OCM to check which regions are
+// available.
+func GetRegions(client ocm.Client, awsClient aws.Client) (regions []*cmv1.CloudRegion, err error) {
 	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
-
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Region(aws.DefaultRegion).
-		Build()
-	if err != nil {
-		return nil, fmt.Errorf("Error creating AWS client: %v", err)
-	}
 
 	// Get AWS region
 	currentAWSCreds, err := awsClient.GetIAMCredentials()
@@ -52,44 +40,80 @@ func GetRegions(client *cmv1.Client) (regions []*cmv1.CloudRegion, err error) {
 	if err != nil {
 		return nil, fmt.Errorf("Failed to get local AWS credentials: %v", err)
 	}
+	accessKeyID := currentAWSCreds.AccessKeyID
+	secretAccessKey := currentAWSCreds.SecretAccessKey
+
+	// OCM doesn't accept temporary credentials, like those of an assumed role, as they need the
+	// session token, so the access key of the osdCcsAdmin user is generated with them instead:
+	if currentAWSCreds.SessionToken != "" {
+		var accessKey *aws.AccessKey
+		accessKey, err = awsClient.GetAWSAccessKeys()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get access keys for user '%s': %v\n"+
+				"Run 'rosa init' and try again", aws.AdminUserName, err)
+		}
+		accessKeyID = accessKey.AccessKeyID
+		secretAccessKey = accessKey.SecretAccessKey
+	}
 
 	// Build cmv1.AWS object to get list of available regions:
 	awsCredentials, err := cmv1.NewAWS().
-		AccessKeyID(currentAWSCreds.AccessKeyID).
-		SecretAccessKey(currentAWSCreds.SecretAccessKey).
+		AccessKeyID(accessKeyID).
+		SecretAccessKey(secretAccessKey).
 		Build()
 	if err != nil {
 		return nil, fmt.Errorf("Failed to build AWS credentials for user '%s': %v", aws.AdminUserName, err)
 	}
 
-	collection := client.CloudProviders().CloudProvider("aws").AvailableRegions()
-	page := 1
-	size := 100
-	for {
-		var response *cmv1.AvailableRegionsSearchResponse
-		response, err = collection.Search().
-			Page(page).
-			Size(size).
-			Body(awsCredentials).
-			Send()
-		if err != nil {
-			errMsg := response.Error().Reason()
-			if errMsg == "" {
-				errMsg = err.Error()
-			}
-			return nil, errors.New(errMsg)
-		}
-		regions = append(regions, response.Items().Slice()...)
-		if response.Size() < size {
-			break
+	// Credentials can only be used in the regions of their own partition, so the regions of the
+	// other partitions are discarded. If the partition can't be determined all the regions are
+	// returned and OCM will reject the ones that can't be used:
+	partition, err := awsClient.GetPartition()
+	if err != nil {
+		reporter.Debugf("Failed to get partition of AWS credentials: %v", err)
+	}
+
+	// The available regions depend on the AWS account, so the credentials are part of the key:
+	cacheKey := cache.Key("regions", accessKeyID)
+	if data, ok := cache.Get(cacheKey, cache.RegionsTTL); ok {
+		regions, err = cmv1.UnmarshalCloudRegionList(data)
+		if err == nil {
+			regions = filterRegions(regions, partition)
+			return
 		}
-		page++
 	}
+
+	regions, err = client.GetAvailableRegions(awsCredentials)
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := new(bytes.Buffer)
+	if cmv1.MarshalCloudRegionList(regions, buffer) == nil {
+		cache.Set(cacheKey, buffer.Bytes())
+	}
+	regions = filterRegions(regions, partition)
 	return
 }
 
-func GetRegionList(client *cmv1.Client, multiAZ bool) (regionList []string, regionAZ map[string]bool, err error) {
-	regions, err := GetRegions(client)
+// filterRegions returns the regions that are in the given partition, or all of them if the
+// partition is empty.
+func filterRegions(regions []*cmv1.CloudRegion, partition string) []*cmv1.CloudRegion {
+	if partition == "" {
+		return regions
+	}
+	result := []*cmv1.CloudRegion{}
+	for _, region := range regions {
+		if aws.GetPartition(region.ID()) == partition {
+			result = append(result, region)
+		}
+	}
+	return result
+}
+
+func GetRegionList(client ocm.Client, awsClient aws.Client, multiAZ bool) (regionList []string,
+	regionAZ map[string]bool, err error) {
+	regions, err := GetRegions(client, awsClient)
 	if err != nil {
 		err = fmt.Errorf("Failed to retrieve AWS regions: %s", err)
 		return
@@ -0,0 +1,50 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+
+	"github.com/openshift/moactl/pkg/ocm/raw"
+)
+
+// ErrRetryNotSupported is returned by RetryInstall when the clusters management service doesn't
+// support retrying the installation of the cluster.
+var ErrRetryNotSupported = errors.New("Retrying the installation isn't supported for this cluster")
+
+// RetryInstall asks OCM to provision again a cluster whose installation failed. The cluster keeps
+// its identifier, name and configuration.
+func RetryInstall(connection *sdk.Connection, clusterID string) error {
+	response, err := connection.Post().
+		Path(fmt.Sprintf("/api/clusters_mgmt/v1/clusters/%s/retry_install", clusterID)).
+		Bytes([]byte("{}")).
+		Send()
+	if err != nil {
+		return err
+	}
+	switch response.Status() {
+	case http.StatusOK, http.StatusAccepted, http.StatusNoContent:
+		return nil
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return ErrRetryNotSupported
+	}
+	return raw.Error(response)
+}
@@ -0,0 +1,174 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that check if the OCM account has the roles needed by the
+// commands that change resources, so that accounts that can only view the resources of the
+// organization get a clear message instead of failing with a permission error in the middle of a
+// command.
+
+package roles
+
+import (
+	"fmt"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	azv1 "github.com/openshift-online/ocm-sdk-go/authorizations/v1"
+	"github.com/spf13/cobra"
+
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// ExitCode is the exit code of the commands that fail because the account doesn't have the role
+// that they need.
+const ExitCode = rosaerrors.ExitAuth
+
+// annotation is the annotation of the commands that contains the requirement to run them.
+const annotation = "ocm.requirement"
+
+// Requirement is the action on clusters that a command needs to be allowed to perform, and the
+// role that allows it.
+type Requirement struct {
+	Action string
+	Role   string
+}
+
+// Requirements of the commands:
+var (
+	// Create is needed to provision new clusters.
+	Create = &Requirement{Action: "create", Role: "ClusterProvisioner"}
+
+	// Update is needed to change existing clusters and their resources.
+	Update = &Requirement{Action: "update", Role: "ClusterEditor"}
+
+	// Delete is needed to delete clusters.
+	Delete = &Requirement{Action: "delete", Role: "ClusterOwner"}
+
+	// None marks the commands that don't change OCM resources, like those that only change AWS
+	// resources or the configuration file, when their parent command has a requirement.
+	None = &Requirement{}
+)
+
+var requirements = map[string]*Requirement{
+	"":            None,
+	Create.Action: Create,
+	Update.Action: Update,
+	Delete.Action: Delete,
+}
+
+// Require marks the given command, and its subcommands unless they say otherwise, as one that
+// needs the given requirement.
+func Require(cmd *cobra.Command, requirement *Requirement) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[annotation] = requirement.Action
+}
+
+// Required returns the requirement of the given command, inherited from its parents if it
+// doesn't have its own, or nil if it doesn't have any.
+func Required(cmd *cobra.Command) *Requirement {
+	for ; cmd != nil; cmd = cmd.Parent() {
+		action, ok := cmd.Annotations[annotation]
+		if !ok {
+			continue
+		}
+		requirement := requirements[action]
+		if requirement == None {
+			return nil
+		}
+		return requirement
+	}
+	return nil
+}
+
+// Access describes the actions on clusters that the account is allowed to perform in its
+// organization.
+type Access struct {
+	allowed map[string]bool
+}
+
+// GetAccess checks which of the actions of the requirements the account is allowed to perform.
+func GetAccess(connection *sdk.Connection) (*Access, error) {
+	access := &Access{
+		allowed: map[string]bool{},
+	}
+	for _, requirement := range []*Requirement{Create, Update, Delete} {
+		request, err := azv1.NewSelfAccessReviewRequest().
+			Action(requirement.Action).
+			ResourceType("Cluster").
+			Build()
+		if err != nil {
+			return nil, err
+		}
+		response, err := connection.Authorizations().V1().SelfAccessReview().Post().
+			Request(request).
+			Send()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to check access to action '%s': %v", requirement.Action, err)
+		}
+		access.allowed[requirement.Action] = response.Response().Allowed()
+	}
+	return access, nil
+}
+
+// ReadOnly checks if the account can only view the clusters of the organization.
+func (a *Access) ReadOnly() bool {
+	return !a.allowed[Create.Action] && !a.allowed[Update.Action] && !a.allowed[Delete.Action]
+}
+
+// Denies checks if the account isn't allowed to run commands with the given requirement. The
+// actions are checked for the whole organization, but accounts can also get roles for individual
+// clusters, so commands that change existing clusters are only denied to read-only accounts.
+func (a *Access) Denies(requirement *Requirement) bool {
+	if requirement == nil {
+		return false
+	}
+	if requirement == Create {
+		return !a.allowed[Create.Action]
+	}
+	return a.ReadOnly()
+}
+
+// Hide hides the commands of the given tree that the account isn't allowed to run, so that they
+// aren't offered in the help. Commands that only group other commands are hidden when all their
+// subcommands are hidden. It returns true if the given command was hidden.
+func (a *Access) Hide(cmd *cobra.Command) bool {
+	children := cmd.Commands()
+	if len(children) == 0 {
+		if a.Denies(Required(cmd)) {
+			cmd.Hidden = true
+		}
+		return cmd.Hidden
+	}
+	hidden := true
+	for _, child := range children {
+		if !a.Hide(child) {
+			hidden = false
+		}
+	}
+	if hidden && !cmd.Runnable() {
+		cmd.Hidden = true
+	}
+	return cmd.Hidden
+}
+
+// DeniedMessage returns the message that explains that the account can't run the given command
+// and which role it needs.
+func DeniedMessage(cmd *cobra.Command, requirement *Requirement) string {
+	return fmt.Sprintf("Your OCM account isn't allowed to %s clusters, so it can't run '%s'. Ask an "+
+		"administrator of your organization to grant you the '%s' role in OpenShift Cluster Manager",
+		requirement.Action, cmd.CommandPath(), requirement.Role)
+}
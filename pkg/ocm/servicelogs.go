@@ -0,0 +1,124 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocm
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+)
+
+// ServiceLogSeverities are the severities of the entries of the service log, from the least to the
+// most severe.
+var ServiceLogSeverities = []string{"Debug", "Info", "Warning", "Error", "Fatal"}
+
+// ValidateServiceLogSeverity checks that the given severity is one of the known severities, ignoring
+// the case, and returns it with the case used by the service.
+func ValidateServiceLogSeverity(severity string) (string, error) {
+	for _, item := range ServiceLogSeverities {
+		if strings.EqualFold(severity, item) {
+			return item, nil
+		}
+	}
+	return "", fmt.Errorf("Severity '%s' isn't valid, it must be one of '%s'", severity,
+		strings.Join(ServiceLogSeverities, "', '"))
+}
+
+// ServiceLogFilter selects the entries of the service log. Empty fields don't filter.
+type ServiceLogFilter struct {
+	// Since is the time of the oldest entry.
+	Since time.Time
+
+	// Severities are the severities of the entries, compared ignoring the case.
+	Severities []string
+}
+
+// GetServiceLogs returns the entries of the service log of the cluster with the given external
+// identifier, oldest first.
+func GetServiceLogs(connection *sdk.Connection, externalID string) ([]*slv1.LogEntry, error) {
+	return ListServiceLogs(connection, externalID, ServiceLogFilter{})
+}
+
+// ListServiceLogs returns the entries of the service log of the cluster with the given external
+// identifier that match the given filter, oldest first.
+func ListServiceLogs(connection *sdk.Connection, externalID string,
+	filter ServiceLogFilter) ([]*slv1.LogEntry, error) {
+	search := fmt.Sprintf("cluster_uuid = '%s'", externalID)
+	if !filter.Since.IsZero() {
+		search = fmt.Sprintf("%s and timestamp >= '%s'", search,
+			filter.Since.UTC().Format(time.RFC3339))
+	}
+	collection := connection.ServiceLogs().V1().ClusterLogs()
+	var entries []*slv1.LogEntry
+	page := 1
+	size := 100
+	for {
+		response, err := collection.List().
+			Search(search).
+			Order("timestamp asc").
+			Page(page).
+			Size(size).
+			Send()
+		if err != nil {
+			return nil, handleErr(response.Error(), err)
+		}
+		response.Items().Each(func(entry *slv1.LogEntry) bool {
+			if matchesSeverity(entry, filter.Severities) {
+				entries = append(entries, entry)
+			}
+			return true
+		})
+		if response.Size() < size {
+			break
+		}
+		page++
+	}
+	return entries, nil
+}
+
+// matchesSeverity checks if the severity of the given entry is one of the given ones, or if no
+// severities are given. The service doesn't use the same case as the SDK, so the case is ignored.
+func matchesSeverity(entry *slv1.LogEntry, severities []string) bool {
+	if len(severities) == 0 {
+		return true
+	}
+	for _, severity := range severities {
+		if strings.EqualFold(string(entry.Severity()), severity) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateServiceLog adds the given entry to the service log. Only privileged accounts, like those of
+// SRE, are allowed to add entries.
+func CreateServiceLog(connection *sdk.Connection, entry *slv1.LogEntry) (*slv1.LogEntry, error) {
+	response, err := connection.ServiceLogs().V1().ClusterLogs().Add().
+		Body(entry).
+		Send()
+	if err != nil {
+		if response != nil && response.Status() == http.StatusForbidden {
+			return nil, fmt.Errorf("Account isn't allowed to add entries to the service log")
+		}
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Body(), nil
+}
@@ -0,0 +1,182 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions that manage the tuning configurations of clusters, stored in the
+// 'tuning_configs' collection.
+
+package tuningconfigs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	"gopkg.in/yaml.v2"
+
+	"github.com/openshift/moactl/pkg/ocm/raw"
+)
+
+// TuningConfig is a tuning configuration that can be attached to the machine pools of a cluster.
+// The specification is the 'spec' of a Node Tuning Operator 'Tuned' object.
+type TuningConfig struct {
+	ID   string                 `json:"id,omitempty"`
+	Name string                 `json:"name,omitempty"`
+	Spec map[string]interface{} `json:"spec"`
+}
+
+func collectionPath(clusterID string) string {
+	return fmt.Sprintf("/api/clusters_mgmt/v1/clusters/%s/tuning_configs", clusterID)
+}
+
+// ParseSpec parses the specification of a tuning configuration, in JSON or YAML format, and checks
+// that it contains at least one profile.
+func ParseSpec(data []byte) (map[string]interface{}, error) {
+	raw := map[interface{}]interface{}{}
+	err := yaml.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse tuning specification: %v", err)
+	}
+	spec, err := toJSONObject(raw)
+	if err != nil {
+		return nil, err
+	}
+	profiles, ok := spec["profile"].([]interface{})
+	if !ok || len(profiles) == 0 {
+		return nil, fmt.Errorf("Tuning specification isn't valid: it must contain at least one 'profile'")
+	}
+	if _, ok := spec["recommend"].([]interface{}); !ok {
+		return nil, fmt.Errorf("Tuning specification isn't valid: it must contain a 'recommend' list")
+	}
+	return spec, nil
+}
+
+// toJSONObject converts the maps returned by the YAML parser, which may have keys that aren't
+// strings, into maps that can be serialized to JSON.
+func toJSONObject(value map[interface{}]interface{}) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+	for key, item := range value {
+		name, ok := key.(string)
+		if !ok {
+			return nil, fmt.Errorf("Tuning specification isn't valid: key '%v' isn't a string", key)
+		}
+		converted, err := toJSONValue(item)
+		if err != nil {
+			return nil, err
+		}
+		result[name] = converted
+	}
+	return result, nil
+}
+
+func toJSONValue(value interface{}) (interface{}, error) {
+	switch typed := value.(type) {
+	case map[interface{}]interface{}:
+		return toJSONObject(typed)
+	case []interface{}:
+		result := make([]interface{}, len(typed))
+		for i, item := range typed {
+			converted, err := toJSONValue(item)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = converted
+		}
+		return result, nil
+	}
+	return value, nil
+}
+
+// GetTuningConfigs returns the tuning configurations of the given cluster.
+func GetTuningConfigs(connection *sdk.Connection, clusterID string) ([]*TuningConfig, error) {
+	response, err := raw.Send(
+		connection.Get().
+			Path(collectionPath(clusterID)),
+		http.StatusOK,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Items []*TuningConfig `json:"items"`
+	}
+	err = json.Unmarshal(response.Bytes(), &list)
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// GetTuningConfig returns the tuning configuration of the given cluster with the given name, or nil
+// if it doesn't exist.
+func GetTuningConfig(connection *sdk.Connection, clusterID string, name string) (*TuningConfig, error) {
+	configs, err := GetTuningConfigs(connection, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	for _, config := range configs {
+		if config.Name == name {
+			return config, nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateTuningConfig adds the given tuning configuration to the cluster and returns it as stored by
+// OCM.
+func CreateTuningConfig(connection *sdk.Connection, clusterID string,
+	config *TuningConfig) (*TuningConfig, error) {
+	body, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	response, err := raw.Send(
+		connection.Post().
+			Path(collectionPath(clusterID)).
+			Bytes(body),
+		http.StatusCreated, http.StatusOK,
+	)
+	if err != nil {
+		return nil, err
+	}
+	result := &TuningConfig{}
+	err = json.Unmarshal(response.Bytes(), result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// UpdateTuningConfig replaces the specification of the tuning configuration with the given
+// identifier.
+func UpdateTuningConfig(connection *sdk.Connection, clusterID string, configID string,
+	spec map[string]interface{}) error {
+	body, err := json.Marshal(&TuningConfig{Spec: spec})
+	if err != nil {
+		return err
+	}
+	_, err = raw.Send(
+		connection.Patch().
+			Path(collectionPath(clusterID)+"/"+url.PathEscape(configID)).
+			Bytes(body),
+		http.StatusOK,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -18,6 +18,8 @@ package upgrades
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
@@ -49,14 +51,98 @@ func GetScheduledUpgrade(client *cmv1.Client, clusterID string) (*cmv1.UpgradePo
 	if err != nil {
 		return nil, err
 	}
+	return FindScheduledUpgrade(upgradePolicies), nil
+}
 
+// FindScheduledUpgrade returns the manual upgrade policy of the given list, or nil if there is none.
+func FindScheduledUpgrade(upgradePolicies []*cmv1.UpgradePolicy) *cmv1.UpgradePolicy {
 	for _, upgradePolicy := range upgradePolicies {
 		if upgradePolicy.ScheduleType() == "manual" && upgradePolicy.UpgradeType() == "OSD" {
-			return upgradePolicy, nil
+			return upgradePolicy
 		}
 	}
+	return nil
+}
 
-	return nil, nil
+// FindAutomaticUpgrade returns the automatic upgrade policy of the given list, or nil if upgrades
+// aren't automatic.
+func FindAutomaticUpgrade(upgradePolicies []*cmv1.UpgradePolicy) *cmv1.UpgradePolicy {
+	for _, upgradePolicy := range upgradePolicies {
+		if upgradePolicy.ScheduleType() == "automatic" && upgradePolicy.UpgradeType() == "OSD" {
+			return upgradePolicy
+		}
+	}
+	return nil
+}
+
+// UpgradeStateStarted is the state of upgrade policies whose upgrade is already running, so they
+// can no longer be canceled.
+const UpgradeStateStarted = "started"
+
+// States of upgrade policies whose upgrade hasn't started yet. Delayed upgrades are blocked, for
+// example by an upgrade gate that hasn't been acknowledged:
+const (
+	UpgradeStatePending   = "pending"
+	UpgradeStateScheduled = "scheduled"
+	UpgradeStateDelayed   = "delayed"
+)
+
+// MissedGracePeriod is the time that an upgrade can take to start after its scheduled time before
+// its window is considered missed.
+const MissedGracePeriod = 15 * time.Minute
+
+// IsMissed checks if the scheduled time of the given upgrade policy has passed without the upgrade
+// starting. Upgrades whose state is unknown are only considered missed once the grace period has
+// passed.
+func IsMissed(upgradePolicy *cmv1.UpgradePolicy, state *cmv1.UpgradePolicyState, now time.Time) bool {
+	if state != nil && state.Value() == UpgradeStateDelayed {
+		return true
+	}
+	if state != nil && state.Value() != "" && state.Value() != UpgradeStatePending &&
+		state.Value() != UpgradeStateScheduled {
+		return false
+	}
+	return now.After(upgradePolicy.NextRun().Add(MissedGracePeriod))
+}
+
+// MissedWarning returns the message that explains that the window of the given upgrade policy was
+// missed, with the reason given by the state if there is one.
+func MissedWarning(clusterKey string, upgradePolicy *cmv1.UpgradePolicy, state *cmv1.UpgradePolicyState) string {
+	result := fmt.Sprintf("The upgrade of cluster '%s' to version %s was scheduled for %s but it "+
+		"hasn't started", clusterKey, upgradePolicy.Version(),
+		upgradePolicy.NextRun().Format("2006-01-02 15:04 MST"))
+	if state != nil && state.Description() != "" {
+		result = fmt.Sprintf("%s: %s", result, state.Description())
+	}
+	return result
+}
+
+// GetUpgradeState returns the state of the given upgrade policy, for example 'pending', 'scheduled'
+// or 'started'.
+func GetUpgradeState(client *cmv1.Client, clusterID string, upgradePolicyID string) (
+	*cmv1.UpgradePolicyState, error) {
+	response, err := client.Clusters().
+		Cluster(clusterID).
+		UpgradePolicies().
+		UpgradePolicy(upgradePolicyID).
+		State().
+		Get().
+		Send()
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Body(), nil
+}
+
+// FormatScheduledUpgrade returns the description of the version and the time of the scheduled
+// upgrade, followed by its state if it is known.
+func FormatScheduledUpgrade(upgradePolicy *cmv1.UpgradePolicy, state *cmv1.UpgradePolicyState) string {
+	result := fmt.Sprintf("%s on %s", upgradePolicy.Version(),
+		upgradePolicy.NextRun().Format("2006-01-02 15:04 MST"))
+	if state != nil && state.Value() != "" {
+		result = fmt.Sprintf("%s (%s)", result, state.Value())
+	}
+	return result
 }
 
 func CancelUpgrade(client *cmv1.Client, clusterID string) (bool, error) {
@@ -78,6 +164,28 @@ func CancelUpgrade(client *cmv1.Client, clusterID string) (bool, error) {
 	return true, nil
 }
 
+// ScheduleUpgrade schedules a manual upgrade of the cluster to the given version.
+func ScheduleUpgrade(client *cmv1.Client, clusterID string, version string, nextRun time.Time) error {
+	upgradePolicy, err := cmv1.NewUpgradePolicy().
+		ScheduleType("manual").
+		Version(version).
+		NextRun(nextRun).
+		Build()
+	if err != nil {
+		return err
+	}
+	response, err := client.Clusters().
+		Cluster(clusterID).
+		UpgradePolicies().
+		Add().
+		Body(upgradePolicy).
+		Send()
+	if err != nil {
+		return handleErr(response.Error(), err)
+	}
+	return nil
+}
+
 func handleErr(res *ocmerrors.Error, err error) error {
 	msg := res.Reason()
 	if msg == "" {
@@ -0,0 +1,159 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the checks of the blocks of IP addresses of a cluster. They are used both for
+// the values given in the command line and for the answers given in interactive mode.
+
+package validations
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/openshift/moactl/pkg/network"
+)
+
+// Number of nodes that clusters have besides the compute nodes: the control plane nodes and the
+// infrastructure nodes, which are one per zone in multi-AZ clusters.
+const (
+	controlPlaneNodes = 3
+	infraNodes        = 2
+	multiAZInfraNodes = 3
+)
+
+// Range of host prefixes supported by OpenShift Dedicated clusters:
+const (
+	minHostPrefix = 23
+	maxHostPrefix = 26
+)
+
+// Block is a named block of IP addresses of a cluster, like the machine block.
+type Block struct {
+	Name string
+	CIDR *net.IPNet
+}
+
+// ClusterNodes returns the total number of nodes of a cluster with the given number of compute
+// nodes, including the control plane and infrastructure nodes.
+func ClusterNodes(computeNodes int, multiAZ bool) int {
+	if multiAZ {
+		return computeNodes + controlPlaneNodes + multiAZInfraNodes
+	}
+	return computeNodes + controlPlaneNodes + infraNodes
+}
+
+// ValidateCIDR checks that the given block is an IPv4 block that doesn't overlap with any of the
+// used blocks. Used blocks that are nil are ignored.
+func ValidateCIDR(block Block, used ...Block) error {
+	if block.CIDR.IP.To4() == nil {
+		return fmt.Errorf("The %s block '%s' isn't an IPv4 block", block.Name, block.CIDR)
+	}
+	for _, other := range used {
+		if other.CIDR == nil || other.CIDR.IP == nil {
+			continue
+		}
+		if network.Overlaps(block.CIDR, other.CIDR) {
+			return fmt.Errorf("The %s block '%s' overlaps with the %s block '%s'",
+				block.Name, block.CIDR, other.Name, other.CIDR)
+		}
+	}
+	return nil
+}
+
+// ValidateHostPrefix checks that the given host prefix is supported and that it gives enough
+// blocks of pod addresses for the given number of nodes.
+func ValidateHostPrefix(hostPrefix int, podCIDR *net.IPNet, nodes int) error {
+	if hostPrefix < minHostPrefix || hostPrefix > maxHostPrefix {
+		return fmt.Errorf("Host prefix %d isn't valid: it must be between %d and %d",
+			hostPrefix, minHostPrefix, maxHostPrefix)
+	}
+	if podCIDR == nil || podCIDR.IP == nil {
+		return nil
+	}
+	ones, _ := podCIDR.Mask.Size()
+	if hostPrefix < ones {
+		return fmt.Errorf("Host prefix %d isn't valid for the pod block '%s': it must be at least %d",
+			hostPrefix, podCIDR, ones)
+	}
+	maxNodes := network.MaxNodes(podCIDR, hostPrefix)
+	if maxNodes < nodes {
+		return fmt.Errorf("The pod block '%s' with host prefix %d only has room for %d nodes, but "+
+			"the cluster needs %d", podCIDR, hostPrefix, maxNodes, nodes)
+	}
+	return nil
+}
+
+// ValidateMachineCIDR checks that the given machine block has enough addresses for the given
+// number of nodes.
+func ValidateMachineCIDR(machineCIDR *net.IPNet, nodes int) error {
+	usable := network.UsableAddresses(machineCIDR)
+	if usable < nodes {
+		return fmt.Errorf("The machine block '%s' only has %d usable addresses, but the cluster "+
+			"needs %d", machineCIDR, usable, nodes)
+	}
+	return nil
+}
+
+// ValidateNetwork checks the blocks of IP addresses and the host prefix of a cluster with the given
+// total number of nodes: the blocks must be IPv4 blocks that don't overlap with each other, and
+// they must be large enough for the nodes. Blocks that are nil, and a host prefix that is zero, are
+// left for the server to choose and aren't checked.
+func ValidateNetwork(machineCIDR, serviceCIDR, podCIDR *net.IPNet, hostPrefix int, nodes int) error {
+	blocks := []Block{
+		{Name: "machine", CIDR: machineCIDR},
+		{Name: "service", CIDR: serviceCIDR},
+		{Name: "pod", CIDR: podCIDR},
+	}
+	for i, block := range blocks {
+		if block.CIDR == nil || block.CIDR.IP == nil {
+			continue
+		}
+		err := ValidateCIDR(block, blocks[:i]...)
+		if err != nil {
+			return err
+		}
+	}
+	if machineCIDR != nil && machineCIDR.IP != nil {
+		err := ValidateMachineCIDR(machineCIDR, nodes)
+		if err != nil {
+			return err
+		}
+	}
+	if hostPrefix != 0 {
+		err := ValidateHostPrefix(hostPrefix, podCIDR, nodes)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CIDRValidator returns a validator for interactive answers that checks that the answer is a valid
+// IPv4 block that doesn't overlap with the used blocks. Empty answers are accepted, as they select
+// the default.
+func CIDRValidator(name string, used ...Block) func(answer interface{}) error {
+	return func(answer interface{}) error {
+		str, ok := answer.(string)
+		if !ok || str == "" {
+			return nil
+		}
+		_, cidr, err := net.ParseCIDR(str)
+		if err != nil {
+			return fmt.Errorf("'%s' isn't a valid CIDR block", str)
+		}
+		return ValidateCIDR(Block{Name: name, CIDR: cidr}, used...)
+	}
+}
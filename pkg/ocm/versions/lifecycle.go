@@ -0,0 +1,128 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions that retrieve the lifecycle dates of the OpenShift versions.
+
+package versions
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+
+	"github.com/openshift/moactl/pkg/cache"
+	"github.com/openshift/moactl/pkg/ocm/raw"
+)
+
+// EndOfLifeWarningPeriod is how long before the end of life of a version users are warned about it.
+const EndOfLifeWarningPeriod = 60 * 24 * time.Hour
+
+// versionLifecycle contains the subset of the attributes of a version returned by the API that
+// describe its lifecycle, including the 'end_of_life_timestamp' attribute.
+type versionLifecycle struct {
+	ID                 string    `json:"id"`
+	EndOfLifeTimestamp time.Time `json:"end_of_life_timestamp"`
+}
+
+type versionLifecyclePage struct {
+	Size  int                `json:"size"`
+	Items []versionLifecycle `json:"items"`
+}
+
+// GetEndOfLifeDates returns a map from version identifier to the date when that version reaches the
+// end of its life. Versions that don't have an end of life date aren't included. When the channel
+// group is empty the versions of all the channel groups are returned. The dates are cached like the
+// versions themselves.
+func GetEndOfLifeDates(connection *sdk.Connection, channelGroup string) (map[string]time.Time, error) {
+	dates := map[string]time.Time{}
+	cacheKey := cache.Key("end-of-life-dates", channelGroup)
+	if data, ok := cache.Get(cacheKey, cache.VersionsTTL); ok {
+		if json.Unmarshal(data, &dates) == nil {
+			return dates, nil
+		}
+	}
+
+	page := 1
+	size := 100
+	filter := "rosa_enabled = 'true'"
+	if channelGroup != "" {
+		filter = fmt.Sprintf("%s AND channel_group = '%s'", filter, channelGroup)
+	}
+	for {
+		response, err := raw.Send(
+			connection.Get().
+				Path("/api/clusters_mgmt/v1/versions").
+				Parameter("search", filter).
+				Parameter("page", page).
+				Parameter("size", size),
+			http.StatusOK,
+		)
+		if err != nil {
+			return nil, err
+		}
+		var body versionLifecyclePage
+		err = json.Unmarshal(response.Bytes(), &body)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range body.Items {
+			if !item.EndOfLifeTimestamp.IsZero() {
+				dates[item.ID] = item.EndOfLifeTimestamp
+			}
+		}
+		if body.Size < size {
+			break
+		}
+		page++
+	}
+	if data, err := json.Marshal(dates); err == nil {
+		cache.Set(cacheKey, data)
+	}
+	return dates, nil
+}
+
+// IsNearEndOfLife checks if the given end of life date is within the warning period, or already in
+// the past. A zero date means that the version doesn't have an end of life date.
+func IsNearEndOfLife(endOfLife time.Time) bool {
+	return !endOfLife.IsZero() && time.Until(endOfLife) <= EndOfLifeWarningPeriod
+}
+
+// HasReachedEndOfLife checks if the given end of life date is in the past. A zero date means that
+// the version doesn't have an end of life date.
+func HasReachedEndOfLife(endOfLife time.Time) bool {
+	return !endOfLife.IsZero() && time.Now().After(endOfLife)
+}
+
+// EndOfLifeWarning returns the message that should be shown to the user when a version is near its
+// end of life.
+func EndOfLifeWarning(version string, endOfLife time.Time) string {
+	if HasReachedEndOfLife(endOfLife) {
+		return fmt.Sprintf(
+			"Version %s reached its end of life on %s and is no longer supported. "+
+				"Upgrade to a newer version as soon as possible.",
+			version, endOfLife.Format("2006-01-02"),
+		)
+	}
+	days := int(time.Until(endOfLife).Hours() / 24)
+	return fmt.Sprintf(
+		"Version %s will reach its end of life on %s (in %d days). "+
+			"Plan an upgrade to a newer version before then.",
+		version, endOfLife.Format("2006-01-02"), days,
+	)
+}
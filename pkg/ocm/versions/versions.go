@@ -17,16 +17,49 @@ limitations under the License.
 package versions
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"strings"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/cache"
 )
 
 const DefaultChannelGroup = "stable"
 
+// ChannelGroups are the channel groups that versions can belong to. Candidate and nightly versions
+// are provided to preview upcoming releases and aren't supported for production clusters.
+var ChannelGroups = []string{DefaultChannelGroup, "candidate", "nightly"}
+
+// ValidateChannelGroup checks that the given channel group is one of the known channel groups.
+func ValidateChannelGroup(channelGroup string) error {
+	for _, item := range ChannelGroups {
+		if channelGroup == item {
+			return nil
+		}
+	}
+	return fmt.Errorf("Channel group '%s' isn't valid, it must be one of '%s'", channelGroup,
+		strings.Join(ChannelGroups, "', '"))
+}
+
+// CompleteChannelGroup completes the '--channel-group' flags in the shell.
+func CompleteChannelGroup(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	return ChannelGroups, cobra.ShellCompDirectiveNoFileComp
+}
+
 func GetVersions(client *cmv1.Client, channelGroup string) (versions []*cmv1.Version, err error) {
+	cacheKey := cache.Key("versions", channelGroup)
+	if data, ok := cache.Get(cacheKey, cache.VersionsTTL); ok {
+		versions, err = cmv1.UnmarshalVersionList(data)
+		if err == nil {
+			return
+		}
+	}
+
 	collection := client.Versions()
 	page := 1
 	size := 100
@@ -51,12 +84,38 @@ func GetVersions(client *cmv1.Client, channelGroup string) (versions []*cmv1.Ver
 		}
 		page++
 	}
+
+	buffer := new(bytes.Buffer)
+	if cmv1.MarshalVersionList(versions, buffer) == nil {
+		cache.Set(cacheKey, buffer.Bytes())
+	}
 	return
 }
 
+// GetDefaultVersion returns the raw identifier, like '4.5.1', of the default version of the given
+// list, or an empty string if none of them is the default.
+func GetDefaultVersion(versions []*cmv1.Version) string {
+	for _, version := range versions {
+		if version.Default() {
+			return GetRawID(version)
+		}
+	}
+	return ""
+}
+
+// GetRawID returns the raw identifier of the given version, like '4.5.1', extracting it from the
+// identifier when the service doesn't return it.
+func GetRawID(version *cmv1.Version) string {
+	if version.RawID() != "" {
+		return version.RawID()
+	}
+	rawID := strings.TrimPrefix(version.ID(), "openshift-v")
+	return strings.TrimSuffix(rawID, "-"+version.ChannelGroup())
+}
+
 func GetVersionID(cluster *cmv1.Cluster) string {
 	if cluster.OpenshiftVersion() != "" {
-		return createVersionID(cluster.OpenshiftVersion(), cluster.Version().ChannelGroup())
+		return CreateVersionID(cluster.OpenshiftVersion(), cluster.Version().ChannelGroup())
 	}
 	return cluster.Version().ID()
 }
@@ -71,7 +130,7 @@ func GetAvailableUpgrades(client *cmv1.Client, versionID string) ([]string, erro
 	availableUpgrades := []string{}
 
 	for _, v := range version.AvailableUpgrades() {
-		id := createVersionID(v, version.ChannelGroup())
+		id := CreateVersionID(v, version.ChannelGroup())
 		resp, err := client.Versions().Version(id).Get().Send()
 		if err != nil {
 			return nil, handleErr(response.Error(), err)
@@ -85,9 +144,11 @@ func GetAvailableUpgrades(client *cmv1.Client, versionID string) ([]string, erro
 	return availableUpgrades, nil
 }
 
-func createVersionID(version string, channelGroup string) string {
+// CreateVersionID returns the identifier of the given raw version, like '4.5.1', in the given
+// channel group, for example 'openshift-v4.5.1-candidate'.
+func CreateVersionID(version string, channelGroup string) string {
 	versionID := fmt.Sprintf("openshift-v%s", version)
-	if channelGroup != "stable" {
+	if channelGroup != "" && channelGroup != DefaultChannelGroup {
 		versionID = fmt.Sprintf("%s-%s", versionID, channelGroup)
 	}
 	return versionID
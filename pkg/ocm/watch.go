@@ -0,0 +1,119 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/zgalor/weberr"
+
+	"github.com/openshift/moactl/pkg/ci"
+)
+
+// Default values of the options used to watch the installation of a cluster:
+const (
+	DefaultWatchInterval = interval
+	DefaultWatchTimeout  = time.Hour
+)
+
+// Errors returned by WatchInstall when it stops before the installation finishes. The installation
+// itself continues in both cases.
+var (
+	ErrWatchInterrupted = errors.New("Watch interrupted")
+	ErrWatchTimeout     = errors.New("Timed out waiting for the installation to finish")
+)
+
+// ErrUninstallTimeout is returned by WaitForUninstall when the timeout expires before the cluster
+// is gone. The uninstallation itself continues.
+var ErrUninstallTimeout = errors.New("Timed out waiting for the uninstallation to finish")
+
+// WatchInstall polls the state and the install logs of the cluster with the given interval, until
+// the cluster is ready or in error state, and calls the given function with the results of each
+// poll. The logs are empty until the installation starts. It returns the last state of the cluster.
+//
+// When the timeout expires it returns ErrWatchTimeout, and when the user presses Ctrl-C it returns
+// ErrWatchInterrupted, so that the caller can detach cleanly.
+func WatchInstall(client *cmv1.ClustersClient, clusterID string, interval time.Duration,
+	timeout time.Duration, fn func(state cmv1.ClusterState, logs *cmv1.Log)) (cmv1.ClusterState, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ci.Bound(timeout))
+	defer cancel()
+
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupts)
+
+	for {
+		state, err := GetClusterState(client, clusterID)
+		if err != nil {
+			return state, err
+		}
+		logs, err := GetInstallLogs(client, clusterID, 100)
+		if err != nil && weberr.GetType(err) != weberr.NotFound {
+			return state, err
+		}
+		fn(state, logs)
+		if state == cmv1.ClusterStateReady || state == cmv1.ClusterStateError {
+			return state, nil
+		}
+
+		select {
+		case <-interrupts:
+			return state, ErrWatchInterrupted
+		case <-ctx.Done():
+			return state, ErrWatchTimeout
+		case <-time.After(interval):
+		}
+	}
+}
+
+// WaitForUninstall polls the cluster with the given identifier with the given interval until it
+// doesn't exist anymore. When the timeout expires it returns ErrUninstallTimeout, and when the
+// user presses Ctrl-C it returns ErrWatchInterrupted.
+func WaitForUninstall(client *cmv1.ClustersClient, clusterID string, interval time.Duration,
+	timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), ci.Bound(timeout))
+	defer cancel()
+
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupts)
+
+	for {
+		response, err := client.Cluster(clusterID).Status().Get().Send()
+		if response != nil && response.Status() == http.StatusNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-interrupts:
+			return ErrWatchInterrupted
+		case <-ctx.Done():
+			return ErrUninstallTimeout
+		case <-time.After(interval):
+		}
+	}
+}
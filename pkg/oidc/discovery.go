@@ -0,0 +1,136 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions that check the discovery document of an external OIDC issuer.
+
+package oidc
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/openshift/moactl/pkg/transport"
+)
+
+// Discovery contains the fields of the OpenID discovery document that are checked.
+type Discovery struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	ScopesSupported       []string `json:"scopes_supported"`
+	ClaimsSupported       []string `json:"claims_supported"`
+}
+
+// ValidateIssuer fetches the discovery document of the given issuer and checks that it is
+// reachable, that it describes the same issuer and that its endpoints use HTTPS. The given CA
+// bundle, if any, is trusted in addition to the system certificates and the ones of '--ca-file'.
+// Scopes and claims that the issuer doesn't advertise are returned as warnings, as advertising
+// them is optional.
+func ValidateIssuer(issuerURL string, ca string, scopes []string, claims []string) (warnings []string,
+	err error) {
+	pool, err := transport.RootCAs()
+	if err != nil {
+		return nil, err
+	}
+	if ca != "" && !pool.AppendCertsFromPEM([]byte(ca)) {
+		return nil, errors.New("The CA bundle doesn't contain any valid certificate")
+	}
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{
+				RootCAs:    pool,
+				MinVersion: tls.VersionTLS12,
+			},
+		},
+	}
+
+	discoveryURL := strings.TrimSuffix(issuerURL, "/") + "/.well-known/openid-configuration"
+	response, err := client.Get(discoveryURL)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get discovery document '%s': %v", discoveryURL, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Failed to get discovery document '%s': unexpected status code %d, "+
+			"check that the issuer URL is correct", discoveryURL, response.StatusCode)
+	}
+	discovery := &Discovery{}
+	err = json.NewDecoder(response.Body).Decode(discovery)
+	if err != nil {
+		return nil, fmt.Errorf("Discovery document '%s' isn't valid JSON: %v", discoveryURL, err)
+	}
+
+	// The issuer has to match exactly, otherwise tokens will be rejected:
+	if discovery.Issuer != issuerURL {
+		return nil, fmt.Errorf("The discovery document describes issuer '%s' instead of '%s'",
+			discovery.Issuer, issuerURL)
+	}
+	endpoints := []struct {
+		name  string
+		value string
+	}{
+		{"authorization_endpoint", discovery.AuthorizationEndpoint},
+		{"token_endpoint", discovery.TokenEndpoint},
+		{"jwks_uri", discovery.JWKSURI},
+	}
+	for _, endpoint := range endpoints {
+		if endpoint.value == "" {
+			return nil, fmt.Errorf("The discovery document doesn't contain the required '%s'", endpoint.name)
+		}
+		parsed, err := url.Parse(endpoint.value)
+		if err != nil || parsed.Scheme != "https" {
+			return nil, fmt.Errorf("The '%s' of the discovery document must be an https:// URL, but it is '%s'",
+				endpoint.name, endpoint.value)
+		}
+	}
+
+	if len(discovery.ScopesSupported) > 0 {
+		if !contains(discovery.ScopesSupported, "openid") {
+			return nil, errors.New("The issuer doesn't support the 'openid' scope")
+		}
+		for _, scope := range scopes {
+			if !contains(discovery.ScopesSupported, scope) {
+				warnings = append(warnings, fmt.Sprintf("The issuer doesn't advertise scope '%s'", scope))
+			}
+		}
+	}
+	if len(discovery.ClaimsSupported) > 0 {
+		for _, claim := range claims {
+			if !contains(discovery.ClaimsSupported, claim) {
+				warnings = append(warnings, fmt.Sprintf("The issuer doesn't advertise claim '%s'", claim))
+			}
+		}
+	}
+	return warnings, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
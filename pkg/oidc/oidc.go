@@ -0,0 +1,103 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions that generate the keys and documents served by an OIDC issuer.
+
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+)
+
+// Size in bits of the RSA keys used to sign service account tokens:
+const keySize = 4096
+
+// Keys contains the private key of an issuer, in PEM format, and the JSON web key set with the
+// corresponding public key.
+type Keys struct {
+	PrivateKey []byte
+	JWKS       []byte
+}
+
+type jwk struct {
+	KeyID     string `json:"kid"`
+	KeyType   string `json:"kty"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+// GenerateKeys generates a new RSA key pair for signing service account tokens.
+func GenerateKeys() (*Keys, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, keySize)
+	if err != nil {
+		return nil, err
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// The key identifier is derived from the public key, so that it is stable:
+	hash := sha256.Sum256(publicDER)
+	encoding := base64.RawURLEncoding
+	set, err := json.MarshalIndent(jwks{
+		Keys: []jwk{{
+			KeyID:     encoding.EncodeToString(hash[:]),
+			KeyType:   "RSA",
+			Algorithm: "RS256",
+			Use:       "sig",
+			Modulus:   encoding.EncodeToString(privateKey.PublicKey.N.Bytes()),
+			Exponent:  encoding.EncodeToString(big.NewInt(int64(privateKey.PublicKey.E)).Bytes()),
+		}},
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Keys{
+		PrivateKey: pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+		}),
+		JWKS: set,
+	}, nil
+}
+
+// DiscoveryDocument returns the OpenID discovery document of the given issuer, pointing to the
+// key set stored in the given path relative to the issuer URL.
+func DiscoveryDocument(issuerURL string, keysPath string) ([]byte, error) {
+	return json.MarshalIndent(map[string]interface{}{
+		"issuer":                                issuerURL,
+		"jwks_uri":                              issuerURL + "/" + keysPath,
+		"response_types_supported":              []string{"id_token"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"claims_supported":                      []string{"aud", "exp", "sub", "iat", "iss"},
+	}, "", "  ")
+}
@@ -0,0 +1,154 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package output implements the '--output' flag that the list and describe commands use to print
+// the objects that they retrieve as JSON or YAML, so that they can be consumed by scripts.
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+
+	"github.com/openshift/moactl/pkg/ci"
+	"github.com/openshift/moactl/pkg/config"
+)
+
+// Structured formats supported by all the commands that have the flag:
+const (
+	JSON = "json"
+	YAML = "yaml"
+)
+
+var format string
+
+// AddFlag adds the '--output' flag to the given flag set. It is added as a persistent flag of the
+// parent commands, so that all their subcommands accept it.
+func AddFlag(fs *pflag.FlagSet) {
+	fs.StringVarP(
+		&format,
+		"output",
+		"o",
+		"",
+		"Output format. Allowed formats are 'json' and 'yaml', some commands also accept "+
+			"other formats, like 'wide', 'csv' or 'redacted-yaml'.",
+	)
+}
+
+// Format returns the output format selected by the user, or an empty string if the default output
+// should be used.
+func Format() string {
+	return format
+}
+
+// Structured returns true if the user selected the JSON or YAML format, or the redacted YAML
+// format, which is only accepted by the commands that pass it to Validate.
+func Structured() bool {
+	return format == JSON || format == YAML || format == RedactedYAML
+}
+
+// Validate checks that the selected format is one of the structured formats or one of the given
+// additional formats supported by the command. When the flag isn't used the format of the profile
+// of the configuration file, if any, is selected, and in CI mode the default format is JSON.
+func Validate(extra ...string) error {
+	allowed := append([]string{JSON, YAML}, extra...)
+	if format == "" {
+		format = config.Output()
+	}
+	if format == "" && ci.Enabled() {
+		format = JSON
+	}
+	if format == "" {
+		return nil
+	}
+	for _, candidate := range allowed {
+		if format == candidate {
+			return nil
+		}
+	}
+	quoted := make([]string, len(allowed))
+	for i, candidate := range allowed {
+		quoted[i] = fmt.Sprintf("'%s'", candidate)
+	}
+	return fmt.Errorf("Invalid output format '%s'. Allowed formats are %s and %s", format,
+		strings.Join(quoted[:len(quoted)-1], ", "), quoted[len(quoted)-1])
+}
+
+// Print writes the object marshalled by the given function to the standard output, in the selected
+// format. The function is usually one of the marshal functions of the OCM SDK, so that the output
+// contains the same fields that the API returns.
+func Print(marshal func(writer io.Writer) error) error {
+	buffer := &bytes.Buffer{}
+	err := marshal(buffer)
+	if err != nil {
+		return err
+	}
+	return write(buffer.Bytes())
+}
+
+// PrintValue writes the given value to the standard output, in the selected format. It is used for
+// the values that don't come from OCM, which are marshalled with the 'encoding/json' package.
+func PrintValue(value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return write(data)
+}
+
+func write(data []byte) error {
+	var result []byte
+	switch format {
+	case YAML:
+		// The YAML output is generated from the JSON document, so that both formats contain exactly
+		// the same fields:
+		var value interface{}
+		err := yaml.Unmarshal(data, &value)
+		if err != nil {
+			return err
+		}
+		result, err = yaml.Marshal(value)
+		if err != nil {
+			return err
+		}
+	case RedactedYAML:
+		var value interface{}
+		err := json.Unmarshal(data, &value)
+		if err != nil {
+			return err
+		}
+		result, err = yaml.Marshal(redact("", value))
+		if err != nil {
+			return err
+		}
+	default:
+		indented := &bytes.Buffer{}
+		err := json.Indent(indented, data, "", "  ")
+		if err != nil {
+			return err
+		}
+		indented.WriteString("\n")
+		result = indented.Bytes()
+	}
+	_, err := os.Stdout.Write(result)
+	return err
+}
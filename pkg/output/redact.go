@@ -0,0 +1,182 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the 'redacted-yaml' format, which masks the AWS account identifiers, the ARNs
+// and the domains of the objects, so that they can be shared in public bug reports.
+
+package output
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// RedactedYAML is the YAML format with the sensitive values masked. Commands that support it pass
+// it to Validate.
+const RedactedYAML = "redacted-yaml"
+
+// Regular expressions used to find the values to mask:
+var (
+	accountIDRE = regexp.MustCompile(`\b\d{12}\b`)
+	arnRE       = regexp.MustCompile(`\barn:([\w-]+):([\w-]*):([\w-]*):(\d{12})?:([\w+=,.@/:*-]+)`)
+	schemeRE    = regexp.MustCompile(`^[a-z][a-z0-9+.-]*://`)
+)
+
+// publicSuffixes are the domains that aren't masked, because they belong to Red Hat or AWS and
+// not to the user.
+var publicSuffixes = []string{
+	"amazonaws.com",
+	"openshift.com",
+	"openshiftapps.com",
+	"redhat.com",
+}
+
+// publicLabels are the labels of the domains of the clusters that are the same for all of them.
+var publicLabels = map[string]bool{
+	"*":                         true,
+	"api":                       true,
+	"apps":                      true,
+	"console-openshift-console": true,
+	"oauth-openshift":           true,
+}
+
+// redactionKey is the key used to compute the masks. The same value is always replaced by the
+// same mask in the output, which preserves the relations between fields, but the key is random
+// so that the masks of short values, like account identifiers, can't be reversed by hashing all
+// the possible values.
+var redactionKey []byte
+
+// redact returns a copy of the given JSON value where the sensitive values are masked.
+func redact(key string, value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		result := map[string]interface{}{}
+		for name, item := range typed {
+			result[name] = redact(name, item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(typed))
+		for i, item := range typed {
+			result[i] = redact(key, item)
+		}
+		return result
+	case string:
+		return redactString(key, typed)
+	}
+	return value
+}
+
+// redactString masks the sensitive parts of the given string, which is the value of the given key.
+// Domains are only masked in URLs and in the fields that contain them, to avoid confusing them
+// with other dotted values, like instance types.
+func redactString(key string, value string) string {
+	value = accountIDRE.ReplaceAllStringFunc(value, maskDigits)
+	value = arnRE.ReplaceAllStringFunc(value, func(arn string) string {
+		parts := arnRE.FindStringSubmatch(arn)
+		return strings.Join([]string{"arn", parts[1], parts[2], parts[3], parts[4],
+			maskResource(parts[5])}, ":")
+	})
+	if schemeRE.MatchString(value) {
+		parsed, err := url.Parse(value)
+		if err == nil && parsed.Hostname() != "" {
+			host := maskDomain(parsed.Hostname())
+			if parsed.Port() != "" {
+				host += ":" + parsed.Port()
+			}
+			parsed.Host = host
+			parsed.User = nil
+			return parsed.String()
+		}
+	}
+	key = strings.ToLower(key)
+	if strings.Contains(key, "domain") || strings.Contains(key, "host") {
+		return maskDomain(value)
+	}
+	return value
+}
+
+// mask returns the mask of the given value.
+func mask(value string) []byte {
+	if redactionKey == nil {
+		redactionKey = make([]byte, 32)
+		_, err := rand.Read(redactionKey)
+		if err != nil {
+			panic(err)
+		}
+	}
+	hash := hmac.New(sha256.New, redactionKey)
+	hash.Write([]byte(value))
+	return hash.Sum(nil)
+}
+
+// maskDigits replaces the given account identifier with other twelve digits.
+func maskDigits(value string) string {
+	sum := mask(value)
+	digits := make([]byte, len(value))
+	for i := range digits {
+		digits[i] = '0' + sum[i]%10
+	}
+	return string(digits)
+}
+
+// maskName replaces the given name with one that has the same format in all the masked values.
+func maskName(value string) string {
+	return "redacted-" + hex.EncodeToString(mask(value))[:8]
+}
+
+// maskResource masks the resource of an ARN, keeping its type, like 'role/' or 'secret:'.
+func maskResource(resource string) string {
+	index := strings.IndexAny(resource, "/:")
+	if index < 0 {
+		return maskName(resource)
+	}
+	return resource[:index+1] + maskName(resource[index+1:])
+}
+
+// maskDomain masks the labels of the given domain that are specific to the user. The top level
+// domain and the domains of Red Hat and AWS are kept.
+func maskDomain(domain string) string {
+	suffix := ""
+	for _, candidate := range publicSuffixes {
+		if domain == candidate || strings.HasSuffix(domain, "."+candidate) {
+			suffix = candidate
+			break
+		}
+	}
+	if suffix == "" {
+		index := strings.LastIndex(domain, ".")
+		if index < 0 {
+			return maskName(domain)
+		}
+		suffix = domain[index+1:]
+	}
+	if domain == suffix {
+		return domain
+	}
+	labels := strings.Split(strings.TrimSuffix(domain, "."+suffix), ".")
+	for i, label := range labels {
+		if !publicLabels[label] {
+			labels[i] = maskName(label)
+		}
+	}
+	return strings.Join(labels, ".") + "." + suffix
+}
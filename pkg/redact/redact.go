@@ -0,0 +1,110 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package redact removes credentials and tokens from text, like the messages sent to the log and
+// the requests printed in dry run mode, so that they can be shared safely.
+package redact
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Replacement is the string that replaces the redacted values.
+const Replacement = "***"
+
+// sensitiveNames are the names of the JSON, XML and form fields, and of the HTTP headers, whose
+// values are redacted. They are compared ignoring case.
+var sensitiveNames = []string{
+	"access_token",
+	"refresh_token",
+	"id_token",
+	"offline_token",
+	"token",
+	"password",
+	"bind_password",
+	"client_secret",
+	"secret_access_key",
+	"aws_secret_access_key",
+	"secretaccesskey",
+	"sessiontoken",
+	"session_token",
+	"secretstring",
+	"secretbinary",
+	"private_key",
+	"privatekey",
+	"kubeconfig",
+	"authorization",
+	"x-amz-security-token",
+	"cookie",
+	"set-cookie",
+}
+
+var sensitiveSet = func() map[string]bool {
+	result := map[string]bool{}
+	for _, name := range sensitiveNames {
+		result[name] = true
+	}
+	return result
+}()
+
+// IsSensitive checks if the value of the field or header with the given name must be redacted.
+func IsSensitive(name string) bool {
+	return sensitiveSet[strings.ToLower(name)]
+}
+
+var (
+	namesPattern = strings.Join(quoteAll(sensitiveNames), "|")
+
+	// "name": "value", as in JSON documents:
+	jsonRE = regexp.MustCompile(`(?i)("(?:` + namesPattern + `)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+	// <Name>value</Name>, as in the XML responses of the AWS API:
+	xmlRE = regexp.MustCompile(`(?i)(<(?:` + namesPattern + `)>)[^<]*(</)`)
+
+	// name=value, as in forms and query strings:
+	formRE = regexp.MustCompile(`(?i)(\b(?:` + namesPattern + `)=)[^&\s'"]*`)
+
+	// Name: "value", as in the parameters of the AWS requests pretty printed by the SDK:
+	prettyRE = regexp.MustCompile(`(?i)(\b(?:` + namesPattern + `): )"(?:[^"\\]|\\.)*"`)
+
+	// Header 'Name' is 'value', as in the messages of the round trippers:
+	headerRE = regexp.MustCompile(`(?i)(header '(?:` + namesPattern + `)' is ')[^']*(')`)
+
+	// Bearer tokens and JSON web tokens, like the offline tokens, wherever they appear:
+	bearerRE = regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9._~+/=-]+`)
+	jwtRE    = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+)
+
+func quoteAll(names []string) []string {
+	result := make([]string, len(names))
+	for i, name := range names {
+		result[i] = regexp.QuoteMeta(name)
+	}
+	return result
+}
+
+// String replaces the credentials and tokens contained in the given text.
+func String(text string) string {
+	text = jsonRE.ReplaceAllString(text, `${1}"`+Replacement+`"`)
+	text = xmlRE.ReplaceAllString(text, `${1}`+Replacement+`${2}`)
+	text = formRE.ReplaceAllString(text, `${1}`+Replacement)
+	text = prettyRE.ReplaceAllString(text, `${1}"`+Replacement+`"`)
+	text = headerRE.ReplaceAllString(text, `${1}`+Replacement+`${2}`)
+	text = bearerRE.ReplaceAllString(text, `${1}`+Replacement)
+	text = jwtRE.ReplaceAllString(text, Replacement)
+	return text
+}
@@ -0,0 +1,162 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the '--log-format' flag, that selects if messages are printed as text for
+// humans or as JSON events for the programs that wrap the tool, and the stable codes of the
+// events.
+
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// Formats of the messages:
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// FormatEnv is the environment variable that selects the format when the flag isn't given, so that
+// pipelines can set it once for all the commands.
+const FormatEnv = "ROSA_LOG_FORMAT"
+
+// Code identifies the kind of an event. Codes are part of the interface of the tool, so existing
+// ones must not be changed.
+type Code string
+
+// Codes of the messages that don't have a more specific one:
+const (
+	CodeDebug   Code = "debug"
+	CodeInfo    Code = "info"
+	CodeWarning Code = "warning"
+	CodeError   Code = "error"
+)
+
+// Codes of the progress of long running operations:
+const (
+	CodeClusterCreated     Code = "cluster_created"
+	CodeClusterState       Code = "cluster_state_changed"
+	CodeClusterReady       Code = "cluster_ready"
+	CodeClusterInstallFail Code = "cluster_install_failed"
+	CodeClusterUninstalled Code = "cluster_uninstalled"
+	CodeUpgradeMissed      Code = "upgrade_window_missed"
+	CodeWatchStopped       Code = "watch_stopped"
+	CodeWatchTimeout       Code = "watch_timeout"
+	CodeDownloadProgress   Code = "download_progress"
+	CodeProgressPhase      Code = "progress_phase"
+)
+
+// Codes of the warnings about the use of the OCM API:
+const (
+	CodeAPIDeprecated Code = "api_deprecated"
+)
+
+// Fields are the details of an event, like the cluster and its state.
+type Fields map[string]interface{}
+
+// EventError is an error returned by a command that is reported as an event with its own code and
+// fields in JSON format, instead of the generic error code.
+type EventError struct {
+	Code   Code
+	Fields Fields
+	Err    error
+}
+
+func (e *EventError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *EventError) Unwrap() error {
+	return e.Err
+}
+
+// Event is a message printed as a JSON line.
+type Event struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Code    Code      `json:"code"`
+	Message string    `json:"message"`
+	Fields  Fields    `json:"fields,omitempty"`
+}
+
+var format = formatValue{value: FormatText}
+
+// AddFormatFlag adds the '--log-format' flag to the given set of command line flags.
+func AddFormatFlag(fs *pflag.FlagSet) {
+	if value := os.Getenv(FormatEnv); value != "" {
+		// Invalid values are ignored, the flag still rejects them:
+		_ = format.Set(value)
+	}
+	fs.Var(
+		&format,
+		"log-format",
+		fmt.Sprintf("Format of the messages, '%s' or '%s'. With '%s' the messages are printed "+
+			"to the standard error as JSON lines with a stable 'code', so that programs don't "+
+			"need to parse the text. The default can also be set with the '%s' environment "+
+			"variable.", FormatText, FormatJSON, FormatJSON, FormatEnv),
+	)
+}
+
+// JSONFormat returns true if the messages should be printed as JSON events.
+func JSONFormat() bool {
+	return format.value == FormatJSON
+}
+
+// printEvent writes the given event as a JSON line to the standard error, so that it doesn't mix
+// with the output of the commands.
+func printEvent(level string, code Code, fields Fields, message string) {
+	data, err := json.Marshal(&Event{
+		Time:    time.Now().UTC(),
+		Level:   level,
+		Code:    code,
+		Message: message,
+		Fields:  fields,
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "%s\n", message)
+		return
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "%s\n", data)
+}
+
+// formatValue implements the value of the '--log-format' flag.
+type formatValue struct {
+	value string
+}
+
+func (v *formatValue) String() string {
+	return v.value
+}
+
+func (v *formatValue) Type() string {
+	return "format"
+}
+
+func (v *formatValue) Set(text string) error {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text != FormatText && text != FormatJSON {
+		return fmt.Errorf("it must be '%s' or '%s'", FormatText, FormatJSON)
+	}
+	v.value = text
+	return nil
+}
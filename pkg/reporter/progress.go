@@ -0,0 +1,131 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the progress of long running operations. In terminals it is a spinner that
+// shows the current phase and the elapsed time in place, otherwise each new phase is printed once
+// as a message, so that pipes and log files don't fill with spinner frames.
+
+package reporter
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/briandowns/spinner"
+
+	"github.com/openshift/moactl/pkg/ci"
+)
+
+// Progress shows the current phase of a long running operation and the time elapsed since it
+// started. It is created with StartProgress and must be stopped with Stop.
+type Progress struct {
+	reporter *Object
+	spin     *spinner.Spinner
+	start    time.Time
+	mutex    sync.Mutex
+	phase    string
+}
+
+// StartProgress starts showing the progress of a long running operation, with the given initial
+// phase. Messages printed with the reporter while the progress is active don't mix with the
+// spinner.
+func (r *Object) StartProgress(format string, args ...interface{}) *Progress {
+	p := &Progress{
+		reporter: r,
+		start:    time.Now(),
+	}
+	if progressTerminal() {
+		p.spin = spinner.New(spinner.CharSets[9], 100*time.Millisecond)
+		p.spin.Writer = os.Stdout
+		p.spin.PreUpdate = func(s *spinner.Spinner) {
+			s.Suffix = p.suffix()
+		}
+		r.progress = p
+	}
+	p.Phasef(format, args...)
+	if p.spin != nil {
+		p.spin.Start()
+	}
+	return p
+}
+
+// Phasef changes the current phase of the operation. Outside of terminals the phase is printed
+// if it is different to the previous one.
+func (p *Progress) Phasef(format string, args ...interface{}) {
+	phase := fmt.Sprintf(format, args...)
+	p.mutex.Lock()
+	changed := phase != p.phase
+	p.phase = phase
+	p.mutex.Unlock()
+	if changed && p.spin == nil {
+		p.reporter.infof(CodeProgressPhase, Fields{
+			"phase":   phase,
+			"elapsed": int(p.Elapsed().Seconds()),
+		}, "%s", phase)
+	}
+}
+
+// Printf prints a line of output of the operation, like a line of a log, above the spinner.
+func (p *Progress) Printf(format string, args ...interface{}) {
+	defer p.reporter.pauseProgress()()
+	fmt.Printf(format, args...)
+}
+
+// Elapsed returns the time elapsed since the operation started.
+func (p *Progress) Elapsed() time.Duration {
+	return time.Since(p.start).Round(time.Second)
+}
+
+// Stop stops showing the progress, removing the spinner.
+func (p *Progress) Stop() {
+	if p.spin == nil {
+		return
+	}
+	p.spin.Stop()
+	if p.reporter.progress == p {
+		p.reporter.progress = nil
+	}
+}
+
+func (p *Progress) suffix() string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return fmt.Sprintf(" %s (%s)", p.phase, p.Elapsed())
+}
+
+// pauseProgress removes the spinner of the active progress, if any, so that a message can be
+// printed, and returns the function that shows it again.
+func (r *Object) pauseProgress() func() {
+	p := r.progress
+	if p == nil {
+		return func() {}
+	}
+	p.spin.Lock()
+	_, _ = fmt.Fprint(os.Stdout, "\r\033[K")
+	return p.spin.Unlock
+}
+
+// progressTerminal checks if the progress can be shown with a spinner: the standard output is a
+// terminal, and the messages aren't meant for programs.
+func progressTerminal() bool {
+	if JSONFormat() || ci.Enabled() {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
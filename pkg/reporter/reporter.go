@@ -22,7 +22,10 @@ import (
 	"os"
 	"runtime"
 
+	"github.com/openshift/moactl/pkg/ci"
 	"github.com/openshift/moactl/pkg/debug"
+	"github.com/openshift/moactl/pkg/exit"
+	"github.com/openshift/moactl/pkg/tracing"
 )
 
 // Builder contains the information and logic needed to create a new reporter.
@@ -33,7 +36,8 @@ type Builder struct {
 // Object is the reported object used by the tool. It prints the messages to the standard output or
 // error streams.
 type Object struct {
-	errors int
+	errors   int
+	progress *Progress
 }
 
 // New creates a builder that can then be used to configure and build a reporter.
@@ -54,39 +58,89 @@ func (r *Object) Debugf(format string, args ...interface{}) {
 	if !debug.Enabled() {
 		return
 	}
+	if JSONFormat() {
+		printEvent("debug", CodeDebug, nil, fmt.Sprintf(format, args...))
+		return
+	}
 	r.Infof(format, args...)
 }
 
 // Infof prints an informative message with the given format and arguments.
 func (r *Object) Infof(format string, args ...interface{}) {
+	r.infof(CodeInfo, nil, format, args...)
+}
+
+// Warnf prints an warning message with the given format and arguments.
+func (r *Object) Warnf(format string, args ...interface{}) {
+	r.warnf(CodeWarning, nil, format, args...)
+}
+
+// Errorf prints an error message with the given format and arguments. It also return an error
+// containing the same information, which will be usually discarded, except when the caller needs to
+// report the error and also return it.
+func (r *Object) Errorf(format string, args ...interface{}) error {
+	return r.errorf(CodeError, nil, format, args...)
+}
+
+// Progressf prints an informative message about the progress of an operation, like a change of
+// the state of a cluster. In JSON format the event has the given code and fields, in text format
+// it is like the messages printed by Infof.
+func (r *Object) Progressf(code Code, fields Fields, format string, args ...interface{}) {
+	r.infof(code, fields, format, args...)
+}
+
+// WarnEventf prints a warning message like Warnf, with the given code and fields in JSON format.
+func (r *Object) WarnEventf(code Code, fields Fields, format string, args ...interface{}) {
+	r.warnf(code, fields, format, args...)
+}
+
+// ErrorEventf prints an error message like Errorf, with the given code and fields in JSON format.
+func (r *Object) ErrorEventf(code Code, fields Fields, format string, args ...interface{}) error {
+	return r.errorf(code, fields, format, args...)
+}
+
+func (r *Object) infof(code Code, fields Fields, format string, args ...interface{}) {
 	message := fmt.Sprintf(format, args...)
-	if r.useColors() {
+	defer r.pauseProgress()()
+	if JSONFormat() {
+		printEvent("info", code, fields, message)
+	} else if ci.Enabled() {
+		_, _ = fmt.Fprintf(os.Stdout, "%s\n", ci.Message("info", message))
+	} else if r.useColors() {
 		_, _ = fmt.Fprintf(os.Stdout, "%s%s\n", infoPrefix, message)
 	} else {
 		_, _ = fmt.Fprintf(os.Stdout, "%s%s\n", "INFO: ", message)
 	}
 }
 
-// Warnf prints an warning message with the given format and arguments.
-func (r *Object) Warnf(format string, args ...interface{}) {
+func (r *Object) warnf(code Code, fields Fields, format string, args ...interface{}) {
 	message := fmt.Sprintf(format, args...)
-	if r.useColors() {
+	defer r.pauseProgress()()
+	if JSONFormat() {
+		printEvent("warning", code, fields, message)
+	} else if ci.Enabled() {
+		_, _ = fmt.Fprintf(os.Stdout, "%s\n", ci.Message("warning", message))
+	} else if r.useColors() {
 		_, _ = fmt.Fprintf(os.Stdout, "%s%s\n", warnPrefix, message)
 	} else {
 		_, _ = fmt.Fprintf(os.Stdout, "%s%s\n", "WARN: ", message)
 	}
 }
 
-// Errorf prints an error message with the given format and arguments. It also return an error
-// containing the same information, which will be usually discarded, except when the caller needs to
-// report the error and also return it.
-func (r *Object) Errorf(format string, args ...interface{}) error {
+func (r *Object) errorf(code Code, fields Fields, format string, args ...interface{}) error {
 	message := fmt.Sprintf(format, args...)
-	if r.useColors() {
+	defer r.pauseProgress()()
+	if JSONFormat() {
+		printEvent("error", code, fields, message)
+	} else if ci.Enabled() {
+		_, _ = fmt.Fprintf(os.Stderr, "%s\n", ci.Message("error", message))
+	} else if r.useColors() {
 		_, _ = fmt.Fprintf(os.Stderr, "%s%s\n", errorPrefix, message)
 	} else {
 		_, _ = fmt.Fprintf(os.Stdout, "%s%s\n", "ERR: ", message)
 	}
+	ci.RecordError(message)
+	tracing.RecordError(message)
 	r.errors++
 	return errors.New(message)
 }
@@ -115,7 +169,7 @@ func CreateReporterOrExit() *Object {
 		Build()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create reporter: %v\n", err)
-		os.Exit(1)
+		exit.Exit(1)
 	}
 	return reporter
 }
@@ -0,0 +1,151 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the retry policy shared by the OCM and AWS clients, so that transient errors,
+// like throttling or a server that is briefly unavailable, don't abort commands halfway through
+// operations that take several steps.
+
+package retry
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// Default values of the flags:
+const (
+	DefaultMaxRetries     = 5
+	DefaultRequestTimeout = 0
+)
+
+// Limits of the delay between attempts:
+const (
+	BaseDelay = 1 * time.Second
+	MaxDelay  = 30 * time.Second
+
+	// MaxRetryAfter is the maximum delay requested by the server with the 'Retry-After' header that
+	// is respected, so that a wrong value doesn't block the tool.
+	MaxRetryAfter = 2 * time.Minute
+)
+
+var (
+	maxRetries     = DefaultMaxRetries
+	requestTimeout = time.Duration(DefaultRequestTimeout)
+)
+
+// AddFlags adds the '--max-retries' and '--request-timeout' flags to the given set of command line
+// flags.
+func AddFlags(fs *pflag.FlagSet) {
+	fs.IntVar(
+		&maxRetries,
+		"max-retries",
+		DefaultMaxRetries,
+		"Maximum number of times that requests to the OCM and AWS APIs are retried after "+
+			"throttling, server and connection errors. Use 0 to disable retries.",
+	)
+	fs.DurationVar(
+		&requestTimeout,
+		"request-timeout",
+		DefaultRequestTimeout,
+		"Maximum time that each attempt of a request to the OCM and AWS APIs can take, like '30s'. "+
+			"The default is no limit.",
+	)
+}
+
+// MaxRetries returns the maximum number of times that a request is retried.
+func MaxRetries() int {
+	if maxRetries < 0 {
+		return 0
+	}
+	return maxRetries
+}
+
+// RequestTimeout returns the maximum time that each attempt of a request can take, or zero if
+// there is no limit.
+func RequestTimeout() time.Duration {
+	return requestTimeout
+}
+
+// Retryable checks if a request that got a response with the given status code can be retried.
+// Requests that change resources are only retried when the server rejected them without
+// processing them, as otherwise they could be applied twice.
+func Retryable(method string, status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return Idempotent(method)
+	default:
+		return false
+	}
+}
+
+// Idempotent checks if requests with the given method can be sent more than once with the same
+// result.
+func Idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// Backoff returns the time to wait before the given attempt, counting from zero for the first
+// retry. It grows exponentially with random jitter, unless the server asked for a specific delay
+// with the given value of the 'Retry-After' header.
+func Backoff(attempt int, retryAfter string) time.Duration {
+	if delay, ok := parseRetryAfter(retryAfter, time.Now()); ok {
+		return delay
+	}
+	delay := MaxDelay
+	if attempt < 10 {
+		delay = BaseDelay << uint(attempt)
+		if delay > MaxDelay {
+			delay = MaxDelay
+		}
+	}
+	// Full jitter, so that clients that failed at the same time don't retry at the same time:
+	// #nosec G404
+	return time.Duration(rand.Int63n(int64(delay)) + 1)
+}
+
+// parseRetryAfter parses the value of the 'Retry-After' header, which is either a number of
+// seconds or a date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	var delay time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		delay = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		delay = date.Sub(now)
+	} else {
+		return 0, false
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	if delay > MaxRetryAfter {
+		delay = MaxRetryAfter
+	}
+	return delay, true
+}
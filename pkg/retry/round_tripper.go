@@ -0,0 +1,148 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the round tripper that applies the retry policy and the request timeout to
+// the requests sent to the OCM API.
+
+package retry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// RoundTripper retries the requests that fail with transient errors. Don't create instances of
+// this type directly; use the NewRoundTripper function instead.
+type RoundTripper struct {
+	next http.RoundTripper
+}
+
+// Make sure that we implement the http.RoundTripper interface:
+var _ http.RoundTripper = &RoundTripper{}
+
+// NewRoundTripper creates a round tripper that sends the requests with the next one, retrying them
+// according to the '--max-retries' and '--request-timeout' flags.
+func NewRoundTripper(next http.RoundTripper) *RoundTripper {
+	return &RoundTripper{
+		next: next,
+	}
+}
+
+// RoundTrip is the implementation of the http.RoundTripper interface.
+func (t *RoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	// The body is kept in memory so that it can be sent again:
+	var body []byte
+	if request.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		response, err := t.send(request, body)
+		retryAfter := ""
+		switch {
+		case err != nil:
+			if attempt >= MaxRetries() || !temporary(request, err) {
+				return nil, err
+			}
+		case Retryable(request.Method, response.StatusCode):
+			if attempt >= MaxRetries() {
+				return response, nil
+			}
+			retryAfter = response.Header.Get("Retry-After")
+			// The body of the response is discarded, so that the connection can be reused:
+			_, _ = io.Copy(ioutil.Discard, response.Body)
+			response.Body.Close()
+		default:
+			return response, nil
+		}
+
+		timer := time.NewTimer(Backoff(attempt, retryAfter))
+		select {
+		case <-request.Context().Done():
+			timer.Stop()
+			return nil, request.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// send sends one attempt of the request, with a copy of the body and, if a timeout was given, a
+// deadline that is cancelled when the body of the response is closed.
+func (t *RoundTripper) send(request *http.Request, body []byte) (*http.Response, error) {
+	ctx := request.Context()
+	cancel := context.CancelFunc(func() {})
+	if RequestTimeout() > 0 {
+		ctx, cancel = context.WithTimeout(ctx, RequestTimeout())
+	}
+	attempt := request.Clone(ctx)
+	if body != nil {
+		attempt.Body = ioutil.NopCloser(bytes.NewReader(body))
+		attempt.ContentLength = int64(len(body))
+	}
+	response, err := t.next.RoundTrip(attempt)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	response.Body = &cancelBody{ReadCloser: response.Body, cancel: cancel}
+	return response, nil
+}
+
+// temporary checks if the given error of a request is a timeout or a connection error that may
+// not happen again. Requests that change resources are only retried if they couldn't have reached
+// the server.
+func temporary(request *http.Request, err error) bool {
+	if request.Context().Err() != nil {
+		return false
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	if !Idempotent(request.Method) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET)
+}
+
+// cancelBody releases the deadline of an attempt when the body of its response is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
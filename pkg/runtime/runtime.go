@@ -0,0 +1,221 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the runtime shared by the commands. It is created once by the root command and
+// passed to the subcommands in the context, and it creates the clients for the OCM and AWS APIs only
+// when they are first needed.
+
+package runtime
+
+import (
+	"context"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/moactl/pkg/aws"
+	rosaerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
+	"github.com/openshift/moactl/pkg/logging"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/config"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/simulate"
+)
+
+// Runtime contains the reporter, the logger and the API clients used by a command. The logger and
+// the clients are created the first time that they are requested, so that commands that don't need
+// them start faster and work without the corresponding configuration. Don't create instances of
+// this type directly; use the New function instead.
+type Runtime struct {
+	reporter      *rprtr.Object
+	logger        *logrus.Logger
+	awsRegion     string
+	awsClient     aws.Client
+	awsGiven      bool
+	awsCreator    *aws.Creator
+	ocmConnection *sdk.Connection
+	ocmClient     ocm.Client
+}
+
+// New creates a new runtime. None of the API clients are created until they are requested.
+func New() *Runtime {
+	return &Runtime{
+		reporter: rprtr.CreateReporterOrExit(),
+	}
+}
+
+// contextKey is the type of the key used to store the runtime in a context.
+type contextKey struct{}
+
+// NewContext returns a copy of the given context that carries the given runtime.
+func NewContext(ctx context.Context, r *Runtime) context.Context {
+	return context.WithValue(ctx, contextKey{}, r)
+}
+
+// FromContext returns the runtime stored in the given context. If the context is nil or doesn't
+// contain a runtime a new one is created, so that commands also work when they are executed
+// directly instead of from the root command.
+func FromContext(ctx context.Context) *Runtime {
+	if ctx != nil {
+		r, ok := ctx.Value(contextKey{}).(*Runtime)
+		if ok && r != nil {
+			return r
+		}
+	}
+	return New()
+}
+
+// Reporter returns the reporter used to print messages to the user.
+func (r *Runtime) Reporter() *rprtr.Object {
+	return r.reporter
+}
+
+// Logger returns the logger, creating it if needed. It exits noting the error on failure.
+func (r *Runtime) Logger() *logrus.Logger {
+	if r.logger == nil {
+		r.logger = logging.CreateLoggerOrExit(r.reporter)
+	}
+	return r.logger
+}
+
+// WithAWSRegion sets the region that will be used by the AWS client. If the AWS client was already
+// created for a different region it will be created again the next time that it is requested,
+// unless it was given with WithAWSClient.
+func (r *Runtime) WithAWSRegion(region string) *Runtime {
+	if region != r.awsRegion {
+		r.awsRegion = region
+		if !r.awsGiven {
+			r.awsClient = nil
+		}
+	}
+	return r
+}
+
+// WithAWSClient sets the AWS client used by the commands instead of creating one, for example a
+// mock in tests.
+func (r *Runtime) WithAWSClient(awsClient aws.Client) *Runtime {
+	r.awsClient = awsClient
+	r.awsGiven = awsClient != nil
+	r.awsCreator = nil
+	return r
+}
+
+// AWSClient returns the AWS client, creating it if needed. It exits noting the error on failure.
+func (r *Runtime) AWSClient() aws.Client {
+	if r.awsClient == nil {
+		builder := aws.NewClient().
+			Logger(r.Logger())
+		if r.awsRegion != "" {
+			builder = builder.Region(r.awsRegion)
+		}
+		awsClient, err := builder.Build()
+		if err != nil {
+			r.reporter.Errorf("Failed to create AWS client: %v", err)
+			exit.Exit(rosaerrors.ExitAuth)
+		}
+		r.awsClient = awsClient
+	}
+	return r.awsClient
+}
+
+// Creator returns the identity of the AWS user running the command, which is used to find the
+// clusters that belong to the user. It exits noting the error on failure.
+func (r *Runtime) Creator() *aws.Creator {
+	if r.awsCreator == nil {
+		awsCreator, err := r.AWSClient().GetCreator()
+		if err != nil {
+			r.reporter.Errorf("Failed to get AWS creator: %v", err)
+			exit.Exit(rosaerrors.ExitAuth)
+		}
+		r.awsCreator = awsCreator
+	}
+	return r.awsCreator
+}
+
+// CheckAWSIdentity checks that the AWS identity running the command can be used for an operation
+// that takes the given time, printing warnings for the minor problems. It exits noting the error
+// if the identity must not be used.
+func (r *Runtime) CheckAWSIdentity(duration time.Duration) {
+	identity, err := r.AWSClient().GetIdentity()
+	if err != nil {
+		r.reporter.Warnf("Failed to check AWS identity: %v", err)
+		return
+	}
+	r.reporter.Debugf("Running as AWS %s '%s'", identity.Type, identity.ARN)
+	warnings, err := identity.Check(time.Now(), duration)
+	for _, warning := range warnings {
+		r.reporter.Warnf("%s", warning)
+	}
+	if err != nil {
+		r.reporter.Errorf("%v", err)
+		exit.Exit(rosaerrors.ExitAuth)
+	}
+}
+
+// WithOCMConnection sets the connection to the OCM API used by the commands instead of creating
+// one, for example a connection to a test server.
+func (r *Runtime) WithOCMConnection(ocmConnection *sdk.Connection) *Runtime {
+	r.ocmConnection = ocmConnection
+	return r
+}
+
+// ocmConn returns the connection to the OCM API, creating it if needed. It exits noting the error
+// on failure. Commands don't use the connection directly, they use the client returned by OCM.
+func (r *Runtime) ocmConn() *sdk.Connection {
+	if r.ocmConnection == nil {
+		ocmConnection, err := ocm.NewConnection().
+			Logger(r.Logger()).
+			Build()
+		if err != nil {
+			r.reporter.Errorf("Failed to create OCM connection: %v", err)
+			exit.Exit(rosaerrors.ExitCode(err))
+		}
+		r.ocmConnection = ocmConnection
+		if url := ocmConnection.URL(); !config.IsProduction(url) && !simulate.Enabled() {
+			r.reporter.Warnf("Using the non-production OCM environment '%s'", config.EnvName(url))
+		}
+	}
+	return r.ocmConnection
+}
+
+// WithOCMClient sets the OCM client used by the commands instead of creating one, for example a
+// mock.
+func (r *Runtime) WithOCMClient(ocmClient ocm.Client) *Runtime {
+	r.ocmClient = ocmClient
+	return r
+}
+
+// OCM returns the OCM client, creating it with the connection to the OCM API if needed. It exits
+// noting the error on failure.
+func (r *Runtime) OCM() ocm.Client {
+	if r.ocmClient == nil {
+		r.ocmClient = ocm.NewClient(r.ocmConn())
+	}
+	return r.ocmClient
+}
+
+// Cleanup releases the resources used by the clients that have been created.
+func (r *Runtime) Cleanup() {
+	if r.ocmConnection != nil {
+		err := r.ocmConnection.Close()
+		if err != nil {
+			r.reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+		r.ocmConnection = nil
+	}
+}
@@ -0,0 +1,216 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that search for a text in the clusters of the user and in
+// their machine pools, identity providers and upgrade policies.
+
+package search
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/ocm"
+)
+
+// Types of the resources that are searched:
+const (
+	TypeCluster          = "cluster"
+	TypeMachinePool      = "machinepool"
+	TypeIdentityProvider = "idp"
+	TypeUpgradePolicy    = "upgrade"
+)
+
+// Match is a resource with a field that contains the searched text.
+type Match struct {
+	Type      string `json:"type"`
+	ClusterID string `json:"cluster_id"`
+	Cluster   string `json:"cluster"`
+	ID        string `json:"id"`
+	Name      string `json:"name,omitempty"`
+	Field     string `json:"field"`
+	Value     string `json:"value"`
+}
+
+// Error is the failure to load the resources of one of the clusters.
+type Error struct {
+	Cluster string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("Failed to search cluster '%s': %v", e.Cluster, e.Err)
+}
+
+// field is the name and value of one of the fields of a resource that are compared with the text.
+type field struct {
+	name  string
+	value string
+}
+
+// Search looks for the given text, ignoring case, in the given clusters and in their machine
+// pools, identity providers and upgrade policies. The resources of the clusters are loaded in
+// parallel, using at most the given number of workers. Clusters whose resources can't be loaded
+// are returned as errors and don't stop the search. Matches are sorted by cluster and type.
+func Search(client ocm.Client, clusters []*cmv1.Cluster, text string,
+	concurrency int) ([]*Match, []*Error) {
+	text = strings.ToLower(text)
+
+	var mutex sync.Mutex
+	matches := []*Match{}
+	errs := []*Error{}
+
+	jobs := make(chan *cmv1.Cluster)
+	var wg sync.WaitGroup
+	if concurrency > len(clusters) {
+		concurrency = len(clusters)
+	}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for cluster := range jobs {
+				clusterMatches, err := searchCluster(client, cluster, text)
+				mutex.Lock()
+				matches = append(matches, clusterMatches...)
+				if err != nil {
+					errs = append(errs, &Error{Cluster: cluster.Name(), Err: err})
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+	for _, cluster := range clusters {
+		jobs <- cluster
+	}
+	close(jobs)
+	wg.Wait()
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Cluster != matches[j].Cluster {
+			return matches[i].Cluster < matches[j].Cluster
+		}
+		if typeOrder(matches[i].Type) != typeOrder(matches[j].Type) {
+			return typeOrder(matches[i].Type) < typeOrder(matches[j].Type)
+		}
+		return matches[i].ID < matches[j].ID
+	})
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Cluster < errs[j].Cluster
+	})
+	return matches, errs
+}
+
+// searchCluster returns the matches of the given cluster and of its resources. The matches found
+// before an error are returned with it.
+func searchCluster(client ocm.Client, cluster *cmv1.Cluster, text string) ([]*Match, error) {
+	result := []*Match{}
+	add := func(matchType string, id string, name string, fields []field) {
+		for _, item := range fields {
+			if item.value != "" && strings.Contains(strings.ToLower(item.value), text) {
+				result = append(result, &Match{
+					Type:      matchType,
+					ClusterID: cluster.ID(),
+					Cluster:   cluster.Name(),
+					ID:        id,
+					Name:      name,
+					Field:     item.name,
+					Value:     item.value,
+				})
+				return
+			}
+		}
+	}
+
+	add(TypeCluster, cluster.ID(), cluster.Name(), []field{
+		{"name", cluster.Name()},
+		{"id", cluster.ID()},
+		{"external_id", cluster.ExternalID()},
+		{"region", cluster.Region().ID()},
+		{"state", string(cluster.State())},
+		{"version", cluster.Version().RawID()},
+		{"domain", cluster.DNS().BaseDomain()},
+	})
+
+	machinePools, err := client.GetMachinePools(cluster.ID())
+	if err != nil {
+		return result, err
+	}
+	for _, machinePool := range machinePools {
+		fields := []field{
+			{"id", machinePool.ID()},
+			{"instance_type", machinePool.InstanceType()},
+		}
+		labels := machinePool.Labels()
+		keys := make([]string, 0, len(labels))
+		for key := range labels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fields = append(fields, field{"label", key + "=" + labels[key]})
+		}
+		for _, taint := range machinePool.Taints() {
+			fields = append(fields, field{"taint", taint.Key() + "=" + taint.Value() + ":" + taint.Effect()})
+		}
+		add(TypeMachinePool, machinePool.ID(), "", fields)
+	}
+
+	idps, err := client.GetIdentityProviders(cluster.ID())
+	if err != nil {
+		return result, err
+	}
+	for _, idp := range idps {
+		add(TypeIdentityProvider, idp.ID(), idp.Name(), []field{
+			{"name", idp.Name()},
+			{"id", idp.ID()},
+			{"type", string(idp.Type())},
+		})
+	}
+
+	policies, err := client.GetUpgradePolicies(cluster.ID())
+	if err != nil {
+		return result, err
+	}
+	for _, policy := range policies {
+		add(TypeUpgradePolicy, policy.ID(), "", []field{
+			{"id", policy.ID()},
+			{"version", policy.Version()},
+			{"schedule_type", policy.ScheduleType()},
+			{"schedule", policy.Schedule()},
+		})
+	}
+
+	return result, nil
+}
+
+// typeOrder returns the position of the given type in the results of a cluster.
+func typeOrder(matchType string) int {
+	switch matchType {
+	case TypeCluster:
+		return 0
+	case TypeMachinePool:
+		return 1
+	case TypeIdentityProvider:
+		return 2
+	default:
+		return 3
+	}
+}
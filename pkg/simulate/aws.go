@@ -0,0 +1,200 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulate
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/aws/aws-sdk-go/service/sts"
+
+	"github.com/openshift/moactl/pkg/aws/quotas"
+	"github.com/openshift/moactl/pkg/dryrun"
+)
+
+// stackName is the name of the CloudFormation stack of the osdCcsAdmin user created by 'rosa init',
+// which already exists in the simulated account. Its status is the one of an updated stack, so that
+// 'rosa init' doesn't wait for updates forever.
+const stackName = "osdCcsAdminIAMUser"
+
+// awsResults are the functions that return the canned results of the AWS calls, indexed by the
+// name of the service and the name of the operation. Calls that aren't in this map succeed with
+// empty results.
+var awsResults = map[string]func(r *request.Request) interface{}{
+	sts.ServiceName + " GetCallerIdentity": func(r *request.Request) interface{} {
+		return &sts.GetCallerIdentityOutput{
+			Account: aws.String(AccountID),
+			Arn:     aws.String(UserARN),
+			UserId:  aws.String(UserID),
+		}
+	},
+	iam.ServiceName + " GetUser": func(r *request.Request) interface{} {
+		return &iam.GetUserOutput{
+			User: &iam.User{
+				Arn:        aws.String(UserARN),
+				CreateDate: aws.Time(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+				Path:       aws.String("/"),
+				UserId:     aws.String(UserID),
+				UserName:   aws.String(UserName),
+			},
+		}
+	},
+	cloudformation.ServiceName + " ListStacks": func(r *request.Request) interface{} {
+		return &cloudformation.ListStacksOutput{
+			StackSummaries: []*cloudformation.StackSummary{{
+				CreationTime: aws.Time(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+				StackName:    aws.String(stackName),
+				StackStatus:  aws.String(cloudformation.StackStatusUpdateComplete),
+			}},
+		}
+	},
+	cloudformation.ServiceName + " DescribeStacks": func(r *request.Request) interface{} {
+		input := r.Params.(*cloudformation.DescribeStacksInput)
+		if aws.StringValue(input.StackName) != stackName {
+			return &cloudformation.DescribeStacksOutput{}
+		}
+		return &cloudformation.DescribeStacksOutput{
+			Stacks: []*cloudformation.Stack{{
+				CreationTime: aws.Time(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+				StackName:    aws.String(stackName),
+				StackStatus:  aws.String(cloudformation.StackStatusUpdateComplete),
+			}},
+		}
+	},
+	ec2.ServiceName + " DescribeRegions": func(r *request.Request) interface{} {
+		output := &ec2.DescribeRegionsOutput{}
+		for _, name := range []string{"us-east-1", "us-east-2", "us-west-2", "eu-west-1"} {
+			output.Regions = append(output.Regions, &ec2.Region{
+				Endpoint:    aws.String("ec2." + name + ".amazonaws.com"),
+				OptInStatus: aws.String("opt-in-not-required"),
+				RegionName:  aws.String(name),
+			})
+		}
+		return output
+	},
+	ec2.ServiceName + " DescribeAvailabilityZones": func(r *request.Request) interface{} {
+		region := aws.StringValue(r.Config.Region)
+		output := &ec2.DescribeAvailabilityZonesOutput{}
+		for _, zone := range zones(region) {
+			output.AvailabilityZones = append(output.AvailabilityZones, &ec2.AvailabilityZone{
+				OptInStatus: aws.String(ec2.AvailabilityZoneOptInStatusOptInNotRequired),
+				RegionName:  aws.String(region),
+				State:       aws.String(ec2.AvailabilityZoneStateAvailable),
+				ZoneName:    aws.String(zone),
+			})
+		}
+		return output
+	},
+	ec2.ServiceName + " DescribeInstanceTypeOfferings": func(r *request.Request) interface{} {
+		region := aws.StringValue(r.Config.Region)
+		locations := []string{region}
+		input := r.Params.(*ec2.DescribeInstanceTypeOfferingsInput)
+		if aws.StringValue(input.LocationType) == ec2.LocationTypeAvailabilityZone {
+			locations = zones(region)
+		}
+		output := &ec2.DescribeInstanceTypeOfferingsOutput{}
+		for _, instanceType := range instanceTypes {
+			for _, location := range locations {
+				output.InstanceTypeOfferings = append(output.InstanceTypeOfferings,
+					&ec2.InstanceTypeOffering{
+						InstanceType: aws.String(instanceType.id),
+						Location:     aws.String(location),
+						LocationType: input.LocationType,
+					})
+			}
+		}
+		return output
+	},
+	servicequotas.ServiceName + " ListServiceQuotas": func(r *request.Request) interface{} {
+		input := r.Params.(*servicequotas.ListServiceQuotasInput)
+		output := &servicequotas.ListServiceQuotasOutput{}
+		// The simulated account has the quotas that the tool checks before creating clusters:
+		for _, quota := range quotas.Required {
+			if quota.ServiceCode != aws.StringValue(input.ServiceCode) {
+				continue
+			}
+			output.Quotas = append(output.Quotas, &servicequotas.ServiceQuota{
+				QuotaCode:   aws.String(quota.QuotaCode),
+				QuotaName:   aws.String(quota.QuotaName),
+				ServiceCode: input.ServiceCode,
+				Value:       aws.Float64(QuotaValue),
+			})
+		}
+		return output
+	},
+	servicequotas.ServiceName + " GetServiceQuota": func(r *request.Request) interface{} {
+		input := r.Params.(*servicequotas.GetServiceQuotaInput)
+		return &servicequotas.GetServiceQuotaOutput{
+			Quota: &servicequotas.ServiceQuota{
+				QuotaCode:   input.QuotaCode,
+				ServiceCode: input.ServiceCode,
+				Value:       aws.Float64(QuotaValue),
+			},
+		}
+	},
+}
+
+// QuotaValue is the value of all the service quotas of the simulated account, large enough for any
+// cluster.
+const QuotaValue = 1000000
+
+// zones returns the names of the availability zones of the given region.
+func zones(region string) []string {
+	return []string{region + "a", region + "b", region + "c"}
+}
+
+// AddAWSHandlers adds to the given handlers of an AWS session the handler that, in simulation
+// mode, answers all the calls with the canned results instead of sending them.
+func AddAWSHandlers(handlers *request.Handlers) {
+	handlers.Validate.PushBack(func(r *request.Request) {
+		if !Enabled() || r.Error != nil {
+			return
+		}
+		r.Handlers.Send.Clear()
+		r.Handlers.Send.PushBack(func(r *request.Request) {
+			r.HTTPResponse = &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{},
+				Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+			}
+		})
+		r.Handlers.ValidateResponse.Clear()
+		r.Handlers.UnmarshalMeta.Clear()
+		r.Handlers.UnmarshalError.Clear()
+		r.Handlers.Unmarshal.Clear()
+		r.Handlers.Unmarshal.PushBack(func(r *request.Request) {
+			if r.Data == nil {
+				return
+			}
+			result, ok := awsResults[r.ClientInfo.ServiceName+" "+r.Operation.Name]
+			if !ok {
+				dryrun.Fill(r.Data)
+				return
+			}
+			reflect.ValueOf(r.Data).Elem().Set(reflect.ValueOf(result(r)).Elem())
+		})
+	})
+}
@@ -0,0 +1,393 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Paths of the OCM resources that have canned responses.
+const (
+	clustersPath         = "/api/clusters_mgmt/v1/clusters"
+	versionsPath         = "/api/clusters_mgmt/v1/versions"
+	machineTypesPath     = "/api/clusters_mgmt/v1/machine_types"
+	regionsPath          = "/api/clusters_mgmt/v1/cloud_providers/aws/regions"
+	availableRegionsPath = "/api/clusters_mgmt/v1/cloud_providers/aws/available_regions"
+	currentAccountPath   = "/api/accounts_mgmt/v1/current_account"
+)
+
+// quotedRE matches the quoted values of search expressions, like the name in "name = 'mycluster'".
+var quotedRE = regexp.MustCompile(`'([^']*)'`)
+
+// RoundTripper is a round tripper that, in simulation mode, answers the requests to the OCM API
+// with canned responses instead of sending them.
+type RoundTripper struct {
+	next http.RoundTripper
+}
+
+// Make sure that we implement the http.RoundTripper interface:
+var _ http.RoundTripper = &RoundTripper{}
+
+// NewRoundTripper creates a round tripper that answers the requests with canned responses, in
+// simulation mode, or calls the next one otherwise.
+func NewRoundTripper(next http.RoundTripper) *RoundTripper {
+	return &RoundTripper{
+		next: next,
+	}
+}
+
+// RoundTrip is the implementation of the http.RoundTripper interface.
+func (t *RoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	if !Enabled() {
+		return t.next.RoundTrip(request)
+	}
+	var data []byte
+	if request.Body != nil {
+		var err error
+		data, err = ioutil.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	status, body := answer(request, data)
+	response := &http.Response{
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode: status,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Request:    request,
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	response.Header.Set("Content-Type", "application/json")
+	response.Body = ioutil.NopCloser(bytes.NewReader(data))
+	response.ContentLength = int64(len(data))
+	return response, nil
+}
+
+// answer returns the status and the body of the canned response to the given request. Requests
+// that change resources succeed returning the object that was sent, as the server would, and
+// requests for resources that don't have canned data return empty lists.
+func answer(request *http.Request, data []byte) (int, interface{}) {
+	path := strings.TrimSuffix(request.URL.Path, "/")
+	var sent interface{}
+	if len(data) > 0 && json.Unmarshal(data, &sent) != nil {
+		sent = nil
+	}
+
+	switch request.Method {
+	case http.MethodPost:
+		switch {
+		case strings.HasSuffix(path, "access_review"):
+			// All the actions are allowed to the simulated user:
+			review, _ := sent.(map[string]interface{})
+			if review == nil {
+				review = map[string]interface{}{}
+			}
+			review["allowed"] = true
+			return http.StatusOK, review
+		case path == availableRegionsPath:
+			return http.StatusOK, list("CloudRegionList", regions())
+		case path == clustersPath:
+			// Nothing is stored, so new clusters get the identifier of the simulated one, which
+			// is what the requests that follow the creation will find:
+			created, _ := sent.(map[string]interface{})
+			if created == nil {
+				created = map[string]interface{}{}
+			}
+			created["id"] = ClusterID
+			created["href"] = clustersPath + "/" + ClusterID
+			return http.StatusCreated, created
+		}
+		if sent == nil {
+			sent = map[string]interface{}{}
+		}
+		return http.StatusCreated, sent
+	case http.MethodPatch:
+		if sent == nil {
+			sent = map[string]interface{}{}
+		}
+		return http.StatusOK, sent
+	case http.MethodDelete:
+		return http.StatusNoContent, map[string]interface{}{}
+	}
+
+	switch {
+	case path == clustersPath:
+		items := []interface{}{}
+		if matches(request.URL.Query().Get("search"), ClusterID, ClusterName) {
+			items = append(items, cluster())
+		}
+		return http.StatusOK, list("ClusterList", items)
+	case path == clustersPath+"/"+ClusterID:
+		return http.StatusOK, cluster()
+	case path == clustersPath+"/"+ClusterID+"/status":
+		return http.StatusOK, map[string]interface{}{
+			"kind":      "ClusterStatus",
+			"id":        ClusterID,
+			"state":     "ready",
+			"dns_ready": true,
+		}
+	case strings.HasPrefix(path, clustersPath+"/"+ClusterID+"/"):
+		return http.StatusOK, list("List", nil)
+	case strings.HasPrefix(path, clustersPath+"/"):
+		return http.StatusNotFound, notFound(fmt.Sprintf("Cluster '%s' not found",
+			strings.Split(strings.TrimPrefix(path, clustersPath+"/"), "/")[0]))
+	case path == versionsPath:
+		return http.StatusOK, list("VersionList", versions())
+	case strings.HasPrefix(path, versionsPath+"/"):
+		id := strings.TrimPrefix(path, versionsPath+"/")
+		for _, item := range versions() {
+			if item["id"] == id {
+				return http.StatusOK, item
+			}
+		}
+		return http.StatusNotFound, notFound(fmt.Sprintf("Version '%s' not found", id))
+	case path == machineTypesPath:
+		return http.StatusOK, list("MachineTypeList", machineTypes())
+	case path == regionsPath:
+		return http.StatusOK, list("CloudRegionList", regions())
+	case path == currentAccountPath:
+		return http.StatusOK, account()
+	}
+	return http.StatusOK, list("List", nil)
+}
+
+// matches checks if the object with the given identifiers matches the given search expression.
+// Only the quoted values are compared, so expressions that select objects by other properties,
+// like the creator of the cluster, match all the objects.
+func matches(search string, ids ...string) bool {
+	if !strings.Contains(search, "id = '") && !strings.Contains(search, "name = '") {
+		return true
+	}
+	for _, match := range quotedRE.FindAllStringSubmatch(search, -1) {
+		for _, id := range ids {
+			if match[1] == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// list returns the body of a page that contains all the given items.
+func list(kind string, items interface{}) map[string]interface{} {
+	size := 0
+	switch typed := items.(type) {
+	case []interface{}:
+		size = len(typed)
+	case []map[string]interface{}:
+		size = len(typed)
+	case nil:
+		items = []interface{}{}
+	}
+	return map[string]interface{}{
+		"kind":  kind,
+		"page":  1,
+		"size":  size,
+		"total": size,
+		"items": items,
+	}
+}
+
+// notFound returns the body of the error returned for objects that don't exist.
+func notFound(reason string) map[string]interface{} {
+	return map[string]interface{}{
+		"kind":   "Error",
+		"id":     "404",
+		"href":   "/api/clusters_mgmt/v1/errors/404",
+		"code":   "CLUSTERS-MGMT-404",
+		"reason": reason,
+	}
+}
+
+// cluster returns the simulated cluster.
+func cluster() map[string]interface{} {
+	domain := "simulated.example.com"
+	return map[string]interface{}{
+		"kind":               "Cluster",
+		"id":                 ClusterID,
+		"href":               clustersPath + "/" + ClusterID,
+		"name":               ClusterName,
+		"display_name":       ClusterName,
+		"external_id":        "00000000-0000-0000-0000-000000000001",
+		"state":              "ready",
+		"creation_timestamp": "2020-01-01T00:00:00Z",
+		"openshift_version":  "4.6.8",
+		"multi_az":           false,
+		"managed":            true,
+		"cloud_provider":     link("CloudProviderLink", "aws"),
+		"region":             link("CloudRegionLink", Region),
+		"product":            link("ProductLink", "rosa"),
+		"version": map[string]interface{}{
+			"kind":          "Version",
+			"id":            "openshift-v4.6.8",
+			"raw_id":        "4.6.8",
+			"channel_group": "stable",
+		},
+		"nodes": map[string]interface{}{
+			"master":               3,
+			"infra":                2,
+			"compute":              2,
+			"compute_machine_type": link("MachineTypeLink", "m5.xlarge"),
+		},
+		"network": map[string]interface{}{
+			"machine_cidr": "10.0.0.0/16",
+			"service_cidr": "172.30.0.0/16",
+			"pod_cidr":     "10.128.0.0/14",
+			"host_prefix":  23,
+		},
+		"dns": map[string]interface{}{
+			"base_domain": domain,
+		},
+		"api": map[string]interface{}{
+			"url":       fmt.Sprintf("https://api.%s.%s:6443", ClusterName, domain),
+			"listening": "external",
+		},
+		"console": map[string]interface{}{
+			"url": fmt.Sprintf("https://console-openshift-console.apps.%s.%s", ClusterName, domain),
+		},
+		"ccs": map[string]interface{}{
+			"enabled": true,
+		},
+		"status": map[string]interface{}{
+			"state":     "ready",
+			"dns_ready": true,
+		},
+		"properties": map[string]interface{}{
+			"rosa_creator_arn": UserARN,
+		},
+	}
+}
+
+// versions returns the versions of OpenShift that can be used to create simulated clusters.
+func versions() []map[string]interface{} {
+	result := []map[string]interface{}{}
+	for i, raw := range []string{"4.6.8", "4.5.24"} {
+		result = append(result, map[string]interface{}{
+			"kind":          "Version",
+			"id":            "openshift-v" + raw,
+			"href":          versionsPath + "/openshift-v" + raw,
+			"raw_id":        raw,
+			"enabled":       true,
+			"rosa_enabled":  true,
+			"default":       i == 0,
+			"channel_group": "stable",
+		})
+	}
+	return result
+}
+
+// instanceTypes are the instance types offered in all the zones of the simulated account, with
+// their number of CPUs and their memory in GiB.
+var instanceTypes = []struct {
+	id       string
+	category string
+	size     string
+	cpu      int
+	memory   int64
+}{
+	{"m5.xlarge", "general_purpose", "large", 4, 16},
+	{"m5.2xlarge", "general_purpose", "xlarge", 8, 32},
+	{"r5.xlarge", "memory_optimized", "large", 4, 32},
+	{"c5.2xlarge", "compute_optimized", "xlarge", 8, 16},
+}
+
+// machineTypes returns the instance types that can be used for the nodes of simulated clusters.
+func machineTypes() []map[string]interface{} {
+	result := []map[string]interface{}{}
+	for _, item := range instanceTypes {
+		result = append(result, map[string]interface{}{
+			"kind":           "MachineType",
+			"id":             item.id,
+			"name":           item.id,
+			"category":       item.category,
+			"size":           item.size,
+			"cloud_provider": link("CloudProviderLink", "aws"),
+			"cpu": map[string]interface{}{
+				"value": item.cpu,
+				"unit":  "vCPU",
+			},
+			"memory": map[string]interface{}{
+				"value": item.memory << 30,
+				"unit":  "B",
+			},
+		})
+	}
+	return result
+}
+
+// regions returns the regions where simulated clusters can be created.
+func regions() []map[string]interface{} {
+	result := []map[string]interface{}{}
+	for _, item := range []struct {
+		id   string
+		name string
+	}{
+		{"us-east-1", "US East, N. Virginia"},
+		{"us-east-2", "US East, Ohio"},
+		{"us-west-2", "US West, Oregon"},
+		{"eu-west-1", "EU, Ireland"},
+	} {
+		result = append(result, map[string]interface{}{
+			"kind":              "CloudRegion",
+			"id":                item.id,
+			"display_name":      item.name,
+			"enabled":           true,
+			"supports_multi_az": true,
+			"cloud_provider":    link("CloudProviderLink", "aws"),
+		})
+	}
+	return result
+}
+
+// account returns the OCM account of the simulated user.
+func account() map[string]interface{} {
+	return map[string]interface{}{
+		"kind":       "Account",
+		"id":         "1simulatedaccount000000000000001",
+		"username":   UserName,
+		"email":      UserName + "@example.com",
+		"first_name": "Simulated",
+		"last_name":  "User",
+		"organization": map[string]interface{}{
+			"kind":        "Organization",
+			"id":          "1simulatedorganization000000001",
+			"name":        "Simulated Organization",
+			"external_id": "12345678",
+		},
+	}
+}
+
+// link returns a link to the object of the given kind and identifier.
+func link(kind string, id string) map[string]interface{} {
+	return map[string]interface{}{
+		"kind": kind,
+		"id":   id,
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the hidden '--simulate' command line option. When it is enabled the commands
+// don't use the real OCM and AWS APIs or credentials: the requests are answered with canned
+// responses that describe a simulated account with a single simulated cluster, so that the
+// commands can be demonstrated and tested end to end without an account.
+
+package simulate
+
+import (
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/spf13/pflag"
+)
+
+// Flag is the name of the command line flag that enables the simulation mode.
+const Flag = "simulate"
+
+// Identifiers of the simulated account and cluster.
+const (
+	OCMURL             = "https://api.simulated.invalid"
+	AccountID          = "123456789012"
+	Region             = "us-east-1"
+	UserName           = "simulated"
+	UserID             = "AIDASIMULATEDUSER0001"
+	UserARN            = "arn:aws:iam::" + AccountID + ":user/" + UserName
+	AWSAccessKeyID     = "AKIASIMULATEDKEY0001"
+	AWSSecretAccessKey = "simulated/secret/access/key/not/valid/0001"
+	ClusterName        = "simulated"
+	ClusterID          = "1simulatedclusteridentifier00001"
+)
+
+// AddFlag adds the hidden '--simulate' flag to the given set of command line flags.
+func AddFlag(flags *pflag.FlagSet) {
+	flags.BoolVar(
+		&enabled,
+		Flag,
+		false,
+		"Answer the requests to the OCM and AWS APIs with canned responses, without using real "+
+			"credentials.",
+	)
+	_ = flags.MarkHidden(Flag)
+}
+
+// ParseFlag extracts the value of the '--simulate' flag from the given command line, ignoring the
+// rest of the flags. It is needed because the configuration is loaded by some functions before the
+// command line is parsed.
+func ParseFlag(argv []string) {
+	flags := pflag.NewFlagSet("", pflag.ContinueOnError)
+	flags.ParseErrorsWhitelist.UnknownFlags = true
+	flags.Usage = func() {}
+	AddFlag(flags)
+	// Errors are ignored here, the command will report them when it is executed:
+	_ = flags.Parse(argv)
+}
+
+// Enabled returns a boolean flag that indicates if the simulation mode is enabled.
+func Enabled() bool {
+	return enabled
+}
+
+// Enable enables the simulation mode.
+func Enable() {
+	enabled = true
+}
+
+// Disable disables the simulation mode. It is intended for tests that enable it.
+func Disable() {
+	enabled = false
+}
+
+// enabled is a boolean flag that indicates that the simulation mode is enabled.
+var enabled bool
+
+// AccessToken returns an unsigned OCM access token for the simulated user. It is only parsed, never
+// verified, as the requests that use it aren't sent.
+func AccessToken() string {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{
+		"typ":        "Bearer",
+		"iat":        now.Unix(),
+		"exp":        now.Add(24 * time.Hour).Unix(),
+		"username":   UserName,
+		"email":      UserName + "@example.com",
+		"first_name": "Simulated",
+		"last_name":  "User",
+	})
+	// Signing with the 'none' method can't fail:
+	result, _ := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	return result
+}
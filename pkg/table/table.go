@@ -0,0 +1,304 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the writer that commands use to print tabulated results. It is used like a
+// text/tabwriter writer, writing lines with cells separated by tabs and calling Flush at the end,
+// but it measures the cells by the columns they take on the terminal, so that wide unicode
+// characters, like the ideographs that can be used in names, don't break the alignment. Columns
+// explicitly declared as numeric are right aligned and their numbers get thousands separators.
+
+package table
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/width"
+)
+
+// padding is the number of spaces between columns.
+const padding = 2
+
+// Writer formats the lines written to it as a table. Nothing is written to the underlying output
+// till Flush is called.
+type Writer struct {
+	out       io.Writer
+	buffer    bytes.Buffer
+	numeric   map[string]bool
+	minWidths map[string]int
+
+	// Header and widths of the table that was being written when FlushPart was last called, so
+	// that the rows written after that continue it with the same alignment:
+	header []string
+	widths []int
+}
+
+// NewWriter creates a writer that prints tables to the given output.
+func NewWriter(out io.Writer) *Writer {
+	return &Writer{
+		out:       out,
+		numeric:   map[string]bool{},
+		minWidths: map[string]int{},
+	}
+}
+
+// Numeric declares that the columns with the given headers contain quantities, so that they are
+// right aligned and their numbers get the thousands separator of the locale of the user. Columns
+// whose cells aren't all numbers, and columns that aren't declared, like identifiers, are written
+// as they are.
+func (w *Writer) Numeric(headers ...string) *Writer {
+	for _, header := range headers {
+		w.numeric[header] = true
+	}
+	return w
+}
+
+// MinWidth sets the minimum width of the column with the given header. It is intended for tables
+// that are written in parts with FlushPart, so that the columns don't need to grow after the first
+// part has been written.
+func (w *Writer) MinWidth(header string, width int) *Writer {
+	w.minWidths[header] = width
+	return w
+}
+
+// Write adds text to the table. Cells are separated by tabs and rows by new lines.
+func (w *Writer) Write(data []byte) (int, error) {
+	return w.buffer.Write(data)
+}
+
+// Flush formats the text written since the previous call and writes it to the output. Consecutive
+// lines containing tabs form a table, where the first line is the header. Lines without tabs are
+// written as they are, and end the table.
+func (w *Writer) Flush() error {
+	err := w.FlushPart()
+	w.header = nil
+	w.widths = nil
+	return err
+}
+
+// FlushPart is like Flush, but the table that is being written at the end of the text is kept
+// open, so that the rows written after this call continue it, aligned with the rows already
+// written. It is intended for tables that are printed in parts as their rows are retrieved.
+func (w *Writer) FlushPart() error {
+	text := w.buffer.String()
+	w.buffer.Reset()
+
+	var output strings.Builder
+	block := [][]string{}
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if line == "" {
+			continue
+		}
+		content := strings.TrimSuffix(line, "\n")
+		if !strings.Contains(content, "\t") {
+			w.writeBlock(&output, block)
+			block = block[:0]
+			w.header = nil
+			w.widths = nil
+			output.WriteString(line)
+			continue
+		}
+		block = append(block, strings.Split(content, "\t"))
+	}
+	w.writeBlock(&output, block)
+
+	_, err := io.WriteString(w.out, output.String())
+	return err
+}
+
+// writeBlock writes the rows of a table, aligning the columns. The first row is the header, unless
+// the rows continue the table written by the previous call to FlushPart.
+func (w *Writer) writeBlock(output *strings.Builder, rows [][]string) {
+	if len(rows) == 0 {
+		return
+	}
+	data := rows
+	if w.header == nil {
+		w.header = rows[0]
+		w.widths = nil
+		data = rows[1:]
+	}
+
+	columns := len(w.header)
+	for _, row := range rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+	headers := make([]string, columns)
+	copy(headers, w.header)
+
+	// Only the columns declared as numeric are candidates, and the header isn't a number, so only
+	// the data rows are checked:
+	numeric := make([]bool, columns)
+	for column := range numeric {
+		if !w.numeric[headers[column]] {
+			continue
+		}
+		numeric[column] = true
+		for _, row := range data {
+			if column < len(row) && row[column] != "" && !isNumber(row[column]) {
+				numeric[column] = false
+				break
+			}
+		}
+	}
+
+	// Format the numbers and calculate the widths, starting with the widths already used for the
+	// table. The last cell of a row isn't padded, so it only counts for the width of the column
+	// when it has to be right aligned:
+	widths := make([]int, columns)
+	copy(widths, w.widths)
+	for column, header := range headers {
+		if w.minWidths[header] > widths[column] {
+			widths[column] = w.minWidths[header]
+		}
+	}
+	for _, row := range rows {
+		for column, cell := range row {
+			if numeric[column] {
+				cell = FormatNumber(cell)
+				row[column] = cell
+			}
+			if column == len(row)-1 && !numeric[column] {
+				continue
+			}
+			if cellWidth := Width(cell); cellWidth > widths[column] {
+				widths[column] = cellWidth
+			}
+		}
+	}
+	w.widths = widths
+
+	for _, row := range rows {
+		for column, cell := range row {
+			fill := ""
+			if cellWidth := Width(cell); cellWidth < widths[column] {
+				fill = strings.Repeat(" ", widths[column]-cellWidth)
+			}
+			last := column == len(row)-1
+			switch {
+			case numeric[column]:
+				output.WriteString(fill)
+				output.WriteString(cell)
+			case last:
+				output.WriteString(cell)
+			default:
+				output.WriteString(cell)
+				output.WriteString(fill)
+			}
+			if !last {
+				output.WriteString(strings.Repeat(" ", padding))
+			}
+		}
+		output.WriteString("\n")
+	}
+}
+
+var numberRE = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?%?$`)
+
+func isNumber(cell string) bool {
+	return numberRE.MatchString(cell)
+}
+
+// FormatNumber formats the given number with the separators of the locale of the user, for example
+// '1024.5' is formatted as '1,024.5' in english locales and as '1.024,5' in german locales. Text
+// that isn't a number is returned unchanged.
+func FormatNumber(number string) string {
+	if !isNumber(number) {
+		return number
+	}
+	sign := ""
+	if strings.HasPrefix(number, "-") {
+		sign = "-"
+		number = number[1:]
+	}
+	integer := number
+	fraction := ""
+	percent := ""
+	if strings.HasSuffix(integer, "%") {
+		integer = strings.TrimSuffix(integer, "%")
+		percent = "%"
+	}
+	thousands, decimal := separators()
+	if index := strings.Index(integer, "."); index >= 0 {
+		fraction = decimal + integer[index+1:]
+		integer = integer[:index]
+	}
+	var result strings.Builder
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			result.WriteString(thousands)
+		}
+		result.WriteRune(digit)
+	}
+	return sign + result.String() + fraction + percent
+}
+
+// separators returns the thousands and decimal separators used by the locale selected with the
+// environment variables. Only the language is taken into account, and languages that aren't known
+// use the english separators.
+func separators() (thousands string, decimal string) {
+	locale := ""
+	for _, name := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+		locale = os.Getenv(name)
+		if locale != "" {
+			break
+		}
+	}
+	language := strings.ToLower(locale)
+	if index := strings.IndexAny(language, "_.@-"); index >= 0 {
+		language = language[:index]
+	}
+	switch language {
+	case "da", "de", "el", "es", "id", "it", "nl", "pt", "tr":
+		return ".", ","
+	case "cs", "fi", "fr", "hu", "nb", "pl", "ru", "sk", "sv", "uk":
+		// A no break space, so that the number isn't split:
+		return "\u00a0", ","
+	default:
+		return ",", "."
+	}
+}
+
+// Width returns the number of columns that the given text takes on the terminal. East asian wide
+// characters take two columns, and combining marks and format characters take none.
+func Width(text string) int {
+	result := 0
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+			continue
+		case isWide(r):
+			result += 2
+		default:
+			result++
+		}
+	}
+	return result
+}
+
+func isWide(r rune) bool {
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,237 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the exporter that sends the spans to an OTLP collector, using the JSON
+// encoding of the OTLP/HTTP protocol, so that no additional dependencies are needed.
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/moactl/pkg/config"
+	"github.com/openshift/moactl/pkg/info"
+)
+
+// exportTimeout is the maximum time that exporting the spans can take, so that an unreachable
+// collector doesn't block the command.
+const exportTimeout = 5 * time.Second
+
+type otlpExporter struct {
+	endpoint string
+	headers  map[string]string
+	service  string
+	client   *http.Client
+}
+
+// newExporter creates the exporter for the endpoint given in the standard OpenTelemetry environment
+// variables or, if they aren't set, in the configuration file. It returns nil if there is no
+// endpoint.
+func newExporter() *otlpExporter {
+	headers := map[string]string{}
+	endpoint := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+	if endpoint == "" {
+		if base := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); base != "" {
+			endpoint = strings.TrimSuffix(base, "/") + "/v1/traces"
+		}
+	}
+	if endpoint == "" {
+		cfg, err := config.Load()
+		if err != nil || cfg.Tracing == nil || cfg.Tracing.Endpoint == "" {
+			return nil
+		}
+		endpoint = cfg.Tracing.Endpoint
+		for key, value := range cfg.Tracing.Headers {
+			headers[key] = value
+		}
+	}
+
+	// The headers of the environment have the 'key1=value1,key2=value2' format:
+	for _, item := range strings.Split(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"), ",") {
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) == 2 && strings.TrimSpace(parts[0]) != "" {
+			headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		}
+	}
+
+	service := os.Getenv("OTEL_SERVICE_NAME")
+	if service == "" {
+		service = "rosa"
+	}
+
+	return &otlpExporter{
+		endpoint: endpoint,
+		headers:  headers,
+		service:  service,
+		client:   &http.Client{Timeout: exportTimeout},
+	}
+}
+
+// The following types are the JSON representation of the OTLP trace messages:
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+	BoolValue   *bool   `json:"boolValue,omitempty"`
+}
+
+// Status codes of spans, as defined by OTLP:
+const (
+	statusOK    = 1
+	statusError = 2
+)
+
+// export sends the given spans to the collector.
+func (e *otlpExporter) export(spans []*Span) error {
+	items := make([]otlpSpan, len(spans))
+	for i, span := range spans {
+		item := otlpSpan{
+			TraceID:           span.TraceID,
+			SpanID:            span.SpanID,
+			ParentSpanID:      span.ParentID,
+			Name:              span.Name,
+			Kind:              span.Kind,
+			StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+			Attributes:        attributes(span.Attributes),
+			Status:            otlpStatus{Code: statusOK},
+		}
+		if span.Error != "" {
+			item.Status = otlpStatus{Code: statusError, Message: span.Error}
+		}
+		items[i] = item
+	}
+	body := otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{
+				Attributes: attributes(map[string]interface{}{
+					"service.name":    e.service,
+					"service.version": info.Version,
+				}),
+			},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{
+					Name:    "github.com/openshift/moactl",
+					Version: info.Version,
+				},
+				Spans: items,
+			}},
+		}},
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, e.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for key, value := range e.headers {
+		request.Header.Set(key, value)
+	}
+	response, err := e.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, response.Body)
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("Unexpected status code %d", response.StatusCode)
+	}
+	return nil
+}
+
+// attributes converts the given values to OTLP attributes. Values of unsupported types are
+// converted to strings.
+func attributes(values map[string]interface{}) []otlpAttribute {
+	result := []otlpAttribute{}
+	for key, value := range values {
+		var converted otlpValue
+		switch typed := value.(type) {
+		case string:
+			if typed == "" {
+				continue
+			}
+			converted.StringValue = &typed
+		case int:
+			text := strconv.Itoa(typed)
+			converted.IntValue = &text
+		case bool:
+			converted.BoolValue = &typed
+		default:
+			text := fmt.Sprintf("%v", typed)
+			converted.StringValue = &text
+		}
+		result = append(result, otlpAttribute{Key: key, Value: converted})
+	}
+	return result
+}
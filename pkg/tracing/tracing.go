@@ -0,0 +1,275 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to record traces of the execution of the commands. Each
+// command is a span, and each call to the OCM and AWS APIs is a child span, so that pipelines can
+// see where the time is spent. The spans are exported to an OTLP collector only when an endpoint is
+// configured, and they join the trace given in the 'TRACEPARENT' environment variable, if any.
+
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// Span is an operation recorded in the trace.
+type Span struct {
+	TraceID    string
+	SpanID     string
+	ParentID   string
+	Name       string
+	Kind       int
+	Start      time.Time
+	End        time.Time
+	Attributes map[string]interface{}
+	Error      string
+}
+
+// Kinds of spans, as defined by OTLP:
+const (
+	kindInternal = 1
+	kindClient   = 3
+)
+
+var (
+	mutex    sync.Mutex
+	exporter *otlpExporter
+	root     *Span
+	pending  []*Span
+	// rootDone indicates that the root span has already been exported, because an error was
+	// reported and the command may exit at any moment.
+	rootDone bool
+)
+
+// Start starts recording the trace of the command with the given arguments. It does nothing if no
+// OTLP endpoint is configured.
+func Start(argv []string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	exporter = newExporter()
+	if exporter == nil {
+		return
+	}
+
+	// Only the names of the command and subcommands are kept, as the flags may contain secrets:
+	command := []string{"rosa"}
+	for _, arg := range argv {
+		if strings.HasPrefix(arg, "-") {
+			break
+		}
+		command = append(command, arg)
+	}
+
+	traceID, parentID := parseTraceParent(os.Getenv("TRACEPARENT"))
+	if traceID == "" {
+		traceID = randomID(16)
+	}
+	root = &Span{
+		TraceID:  traceID,
+		SpanID:   randomID(8),
+		ParentID: parentID,
+		Name:     strings.Join(command, " "),
+		Kind:     kindInternal,
+		Start:    time.Now(),
+		Attributes: map[string]interface{}{
+			"rosa.command": strings.Join(command[1:], " "),
+		},
+	}
+}
+
+// Enabled returns a boolean flag that indicates if traces are being recorded.
+func Enabled() bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return root != nil
+}
+
+// RecordError marks the command as failed with the given message. The commands usually exit right
+// after reporting an error, so the recorded spans are exported immediately.
+func RecordError(message string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if root == nil || rootDone {
+		return
+	}
+	root.Error = message
+	root.End = time.Now()
+	pending = append(pending, root)
+	rootDone = true
+	flushLocked()
+}
+
+// Finish ends the span of the command and exports all the recorded spans. The command failed if
+// the given error isn't nil.
+func Finish(err error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if root == nil {
+		return
+	}
+	if !rootDone {
+		if err != nil {
+			root.Error = err.Error()
+		}
+		root.End = time.Now()
+		pending = append(pending, root)
+		rootDone = true
+	}
+	flushLocked()
+}
+
+// record adds a finished child span of the command to the trace.
+func record(name string, start time.Time, attributes map[string]interface{}, err error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if root == nil {
+		return
+	}
+	span := &Span{
+		TraceID:    root.TraceID,
+		SpanID:     randomID(8),
+		ParentID:   root.SpanID,
+		Name:       name,
+		Kind:       kindClient,
+		Start:      start,
+		End:        time.Now(),
+		Attributes: attributes,
+	}
+	if err != nil {
+		span.Error = err.Error()
+	}
+	pending = append(pending, span)
+}
+
+// flushLocked exports the pending spans. It must be called with the mutex locked. Failures are
+// reported but don't change the result of the command.
+func flushLocked() {
+	if len(pending) == 0 {
+		return
+	}
+	err := exporter.export(pending)
+	pending = nil
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to export traces to '%s': %v\n", exporter.endpoint, err)
+	}
+}
+
+// traceParent returns the value of the W3C 'traceparent' header that makes the receiver of a
+// request part of the trace of the command.
+func traceParent() string {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if root == nil {
+		return ""
+	}
+	return fmt.Sprintf("00-%s-%s-01", root.TraceID, root.SpanID)
+}
+
+// parseTraceParent extracts the trace and parent span identifiers from a W3C 'traceparent' value.
+// Invalid values are ignored, starting a new trace.
+func parseTraceParent(value string) (traceID string, parentID string) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return "", ""
+	}
+	if _, err := hex.DecodeString(parts[1] + parts[2]); err != nil {
+		return "", ""
+	}
+	return strings.ToLower(parts[1]), strings.ToLower(parts[2])
+}
+
+func randomID(size int) string {
+	data := make([]byte, size)
+	_, _ = rand.Read(data)
+	return hex.EncodeToString(data)
+}
+
+// RoundTripper is a round tripper that records a span for each request sent to the given system.
+type RoundTripper struct {
+	system string
+	next   http.RoundTripper
+}
+
+// Make sure that we implement the http.RoundTripper interface:
+var _ http.RoundTripper = &RoundTripper{}
+
+// NewRoundTripper creates a round tripper that records a span for each request sent to the given
+// system, like 'ocm', before calling the next one.
+func NewRoundTripper(system string, next http.RoundTripper) *RoundTripper {
+	return &RoundTripper{
+		system: system,
+		next:   next,
+	}
+}
+
+// RoundTrip is the implementation of the http.RoundTripper interface.
+func (t *RoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	if !Enabled() {
+		return t.next.RoundTrip(request)
+	}
+	if value := traceParent(); value != "" {
+		request = request.Clone(request.Context())
+		request.Header.Set("traceparent", value)
+	}
+	start := time.Now()
+	response, err := t.next.RoundTrip(request)
+	attributes := map[string]interface{}{
+		"rpc.system":  t.system,
+		"http.method": request.Method,
+		"http.url":    fmt.Sprintf("%s://%s%s", request.URL.Scheme, request.URL.Host, request.URL.Path),
+	}
+	// Responses with error status codes are failed spans, but not errors of the round tripper:
+	spanErr := err
+	if response != nil {
+		attributes["http.status_code"] = response.StatusCode
+		if response.StatusCode >= http.StatusBadRequest {
+			spanErr = fmt.Errorf("Unexpected status code %d", response.StatusCode)
+		}
+	}
+	record(fmt.Sprintf("%s %s", request.Method, request.URL.Path), start, attributes, spanErr)
+	return response, err
+}
+
+// AddAWSHandlers adds to the given handlers of an AWS session the handler that records a span for
+// each call to the AWS API, including its retries.
+func AddAWSHandlers(handlers *request.Handlers) {
+	handlers.Complete.PushBack(func(r *request.Request) {
+		if !Enabled() {
+			return
+		}
+		attributes := map[string]interface{}{
+			"rpc.system":      "aws-api",
+			"rpc.service":     r.ClientInfo.ServiceName,
+			"rpc.method":      r.Operation.Name,
+			"aws.region":      r.ClientInfo.SigningRegion,
+			"aws.request_id":  r.RequestID,
+			"aws.retry_count": r.RetryCount,
+		}
+		if r.HTTPResponse != nil {
+			attributes["http.status_code"] = r.HTTPResponse.StatusCode
+		}
+		record(fmt.Sprintf("%s.%s", r.ClientInfo.ServiceName, r.Operation.Name), r.Time, attributes, r.Error)
+	})
+}
@@ -0,0 +1,124 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package transport contains the settings of the connections that the tool opens itself, to OCM,
+// to AWS and to other services: the additional certificate authorities trusted for TLS, selected
+// with the '--ca-file' flag, and the proxy, selected with the standard 'HTTPS_PROXY' and 'NO_PROXY'
+// environment variables.
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/moactl/pkg/config"
+)
+
+// CAFileFlag is the name of the command line flag that selects the file of additional certificate
+// authorities.
+const CAFileFlag = "ca-file"
+
+// caFileEnv is the environment variable that selects the file when the flag isn't given.
+const caFileEnv = "ROSA_CA_FILE"
+
+var caFile string
+
+// AddFlag adds the '--ca-file' flag to the given set of command line flags.
+func AddFlag(fs *pflag.FlagSet) {
+	fs.StringVar(
+		&caFile,
+		CAFileFlag,
+		"",
+		fmt.Sprintf("File containing PEM encoded certificates of certificate authorities that are "+
+			"trusted in addition to the ones of the system, for example those of a proxy that "+
+			"inspects TLS traffic. They are used for all the connections to OCM, to AWS and to "+
+			"other services. The default can also be set with the '%s' environment variable or "+
+			"with 'ca_file' in the profile.", caFileEnv),
+	)
+}
+
+// CAFile returns the file of additional certificate authorities given with the flag, the
+// environment variable or the profile, in that order, or an empty string if there is none.
+func CAFile() string {
+	if caFile != "" {
+		return caFile
+	}
+	if value := os.Getenv(caFileEnv); value != "" {
+		return value
+	}
+	return config.CAFile()
+}
+
+// RootCAs returns the certificate authorities of the system and the additional ones of the file
+// selected with CAFile.
+func RootCAs() (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		// The system pool isn't available in some platforms, like Windows with old versions of Go:
+		pool = x509.NewCertPool()
+	}
+	file := CAFile()
+	if file == "" {
+		return pool, nil
+	}
+	data, err := ioutil.ReadFile(file) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read certificate authorities from '%s': %v", file, err)
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("File '%s' doesn't contain any PEM encoded certificate", file)
+	}
+	return pool, nil
+}
+
+// New returns a transport that uses the proxy selected with the environment and trusts the
+// certificate authorities returned by RootCAs. The verification of certificates is disabled if
+// insecure is true.
+func New(insecure bool) (*http.Transport, error) {
+	pool, err := RootCAs()
+	if err != nil {
+		return nil, err
+	}
+	result := http.DefaultTransport.(*http.Transport).Clone()
+	result.Proxy = http.ProxyFromEnvironment
+	// #nosec G402
+	result.TLSClientConfig = &tls.Config{
+		RootCAs:            pool,
+		InsecureSkipVerify: insecure,
+	}
+	return result, nil
+}
+
+// Setup makes the default transport of the 'net/http' package, which is used by the AWS SDK and by
+// all the HTTP clients that don't have their own, trust the additional certificate authorities.
+// It is intended to be called once the command line has been parsed.
+func Setup() error {
+	if CAFile() == "" {
+		return nil
+	}
+	result, err := New(false)
+	if err != nil {
+		return err
+	}
+	http.DefaultTransport = result
+	return nil
+}
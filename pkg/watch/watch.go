@@ -0,0 +1,174 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the '--watch' flag of the list commands, and the functions that poll a list
+// of resources and find the changes between consecutive polls, so that the commands can refresh
+// their tables or emit change events.
+
+package watch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/moactl/pkg/ci"
+)
+
+// DefaultInterval is the default time between polls.
+const DefaultInterval = 30 * time.Second
+
+// Types of the events that describe the changes of a list:
+const (
+	Added    = "ADDED"
+	Modified = "MODIFIED"
+	Deleted  = "DELETED"
+)
+
+var (
+	enabled  bool
+	interval time.Duration
+)
+
+// AddFlags adds the '--watch' and '--watch-interval' flags to the given flag set.
+func AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVarP(
+		&enabled,
+		"watch",
+		"w",
+		false,
+		"After listing, watch for changes. Tables are refreshed in place, and structured formats "+
+			"print one change event per line.",
+	)
+	fs.DurationVar(
+		&interval,
+		"watch-interval",
+		DefaultInterval,
+		"Time between checks for changes while watching.",
+	)
+}
+
+// Enabled returns true if the user asked to watch for changes.
+func Enabled() bool {
+	return enabled
+}
+
+// Validate checks the values of the flags.
+func Validate() error {
+	if enabled && interval <= 0 {
+		return fmt.Errorf("Option '--watch-interval' must be a positive duration")
+	}
+	return nil
+}
+
+// Item is one of the resources of a watched list.
+type Item struct {
+	// Key identifies the resource across polls.
+	Key string
+
+	// State contains the values that are compared to detect that the resource changed. Values
+	// that change on their own, like ages, must not be part of it.
+	State string
+
+	// Object is the representation of the resource used in the events.
+	Object interface{}
+}
+
+// Event describes the change of one resource of a watched list.
+type Event struct {
+	Type   string      `json:"type"`
+	Time   time.Time   `json:"time"`
+	Object interface{} `json:"object"`
+}
+
+// Diff returns the events that describe the changes from the previous to the current list: the
+// resources added or modified in the order of the current list, followed by the deleted ones in the
+// order of the previous list.
+func Diff(previous []*Item, current []*Item, now time.Time) []*Event {
+	previousByKey := make(map[string]*Item, len(previous))
+	for _, item := range previous {
+		previousByKey[item.Key] = item
+	}
+	currentByKey := make(map[string]*Item, len(current))
+	events := []*Event{}
+	for _, item := range current {
+		currentByKey[item.Key] = item
+		old, ok := previousByKey[item.Key]
+		switch {
+		case !ok:
+			events = append(events, &Event{Type: Added, Time: now, Object: item.Object})
+		case old.State != item.State:
+			events = append(events, &Event{Type: Modified, Time: now, Object: item.Object})
+		}
+	}
+	for _, item := range previous {
+		if _, ok := currentByKey[item.Key]; !ok {
+			events = append(events, &Event{Type: Deleted, Time: now, Object: item.Object})
+		}
+	}
+	return events
+}
+
+// Run calls the load function with the interval given with the '--watch-interval' flag, and passes
+// the loaded list and the changes since the previous poll to the given function. The first call
+// reports all the resources as added. It returns when the user presses Ctrl-C, when the timeout of
+// the CI mode expires, or when loading the list fails.
+func Run(load func() ([]*Item, error), fn func(items []*Item, events []*Event) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), ci.Bound(time.Duration(1<<63-1)))
+	defer cancel()
+
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupts)
+
+	var previous []*Item
+	first := true
+	for {
+		items, err := load()
+		if err != nil {
+			return err
+		}
+		events := Diff(previous, items, time.Now())
+		if first || len(events) > 0 {
+			err = fn(items, events)
+			if err != nil {
+				return err
+			}
+		}
+		previous = items
+		first = false
+
+		select {
+		case <-interrupts:
+			return nil
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// ClearScreen moves the cursor to the top left corner of the terminal and clears it, so that a
+// table can be printed again in place.
+func ClearScreen(w io.Writer) {
+	fmt.Fprint(w, "\033[H\033[2J")
+}